@@ -22,6 +22,7 @@ var (
 	alertCommand          commands.AlertCommand
 	alertmanagerCommand   commands.AlertmanagerCommand
 	analyzeCommand        commands.AnalyzeCommand
+	bucketIndexCommand    commands.BucketIndexCommand
 	bucketValidateCommand commands.BucketValidationCommand
 	configCommand         commands.ConfigCommand
 	loadgenCommand        commands.LoadgenCommand
@@ -41,6 +42,7 @@ func main() {
 	alertmanagerCommand.Register(app, envVars)
 	analyzeCommand.Register(app, envVars)
 	backfillCommand.Register(app, envVars)
+	bucketIndexCommand.Register(app, envVars)
 	bucketValidateCommand.Register(app, envVars)
 	configCommand.Register(app, envVars)
 	loadgenCommand.Register(app, envVars, prometheus.DefaultRegisterer)
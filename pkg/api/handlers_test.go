@@ -66,6 +66,18 @@ func TestIndexPageContent(t *testing.T) {
 	require.False(t, strings.Contains(resp.Body.String(), "/compactor/ring"))
 }
 
+func TestIndexPageContent_ShouldMergeLinksOfTheSameGroup(t *testing.T) {
+	c := newIndexPageContent()
+	c.AddLinks(defaultWeight, "Ingester", []IndexPageLink{{Desc: "Ring status", Path: "/ingester/ring"}})
+	c.AddLinks(defaultWeight, "Ingester", []IndexPageLink{{Desc: "Owned series", Path: "/ingester/owned_series"}})
+	c.AddLinks(defaultWeight, "Distributor", []IndexPageLink{{Desc: "HA tracker status", Path: "/distributor/ha_tracker"}})
+
+	require.Equal(t, []IndexPageLinkGroup{
+		{weight: defaultWeight, Desc: "Distributor", Links: []IndexPageLink{{Desc: "HA tracker status", Path: "/distributor/ha_tracker"}}},
+		{weight: defaultWeight, Desc: "Ingester", Links: []IndexPageLink{{Desc: "Ring status", Path: "/ingester/ring"}, {Desc: "Owned series", Path: "/ingester/owned_series"}}},
+	}, c.GetContent())
+}
+
 type diffConfigMock struct {
 	MyInt          int          `yaml:"my_int"`
 	MyFloat        float64      `yaml:"my_float"`
@@ -90,6 +90,29 @@ var protobufCodecScenarios = map[string]struct {
 			},
 		},
 	},
+	"string with warnings": {
+		response: &v1.Response{
+			Status: "success",
+			Data: &v1.QueryData{
+				ResultType: parser.ValueTypeString,
+				Result: promql.String{
+					T: 1234,
+					V: "the-string",
+				},
+			},
+			Warnings: []string{"some blocks couldn't be queried"},
+		},
+		expectedPayload: mimirpb.QueryResponse{
+			Status: mimirpb.QueryResponse_SUCCESS,
+			Data: &mimirpb.QueryResponse_String_{
+				String_: &mimirpb.StringData{
+					TimestampMs: 1234,
+					Value:       "the-string",
+				},
+			},
+			Warnings: []string{"some blocks couldn't be queried"},
+		},
+	},
 	"scalar": {
 		response: &v1.Response{
 			Status: "success",
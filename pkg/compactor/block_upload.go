@@ -414,6 +414,9 @@ func (c *MultitenantCompactor) sanitizeMeta(logger log.Logger, userID string, bl
 		}
 	}
 
+	// The label names cardinality is computed by the compactor while validating the block.
+	meta.Thanos.TopLabelNames = nil
+
 	meta.Compaction.Parents = nil
 	meta.Compaction.Sources = []ulid.ULID{blockID}
 
@@ -552,6 +555,14 @@ func (c *MultitenantCompactor) validateBlock(ctx context.Context, logger log.Log
 		return errors.Wrap(err, "error validating block")
 	}
 
+	// Record the label names cardinality, now that the block index has been verified. The label names
+	// cardinality is only used for query planning, so failing to gather it doesn't fail the validation.
+	topLabelNames, err := block.GatherTopLabelNamesCardinality(blockDir, block.DefaultTopLabelNamesLimit)
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to gather label names cardinality, the block meta.json is written without it", "err", err)
+	}
+	blockMetadata.Thanos.TopLabelNames = topLabelNames
+
 	return nil
 }
 
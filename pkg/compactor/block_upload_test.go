@@ -1708,6 +1708,7 @@ func TestMultitenantCompactor_ValidateBlock(t *testing.T) {
 				require.Contains(t, err.Error(), tc.expectedMsg)
 			} else {
 				require.NoError(t, err)
+				require.NotEmpty(t, meta.Thanos.TopLabelNames)
 			}
 		})
 	}
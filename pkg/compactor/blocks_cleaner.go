@@ -27,6 +27,7 @@ import (
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
 	"github.com/grafana/mimir/pkg/util"
 	util_log "github.com/grafana/mimir/pkg/util/log"
 	"github.com/grafana/mimir/pkg/util/validation"
@@ -42,6 +43,8 @@ type BlocksCleanerConfig struct {
 	CleanupConcurrency      int
 	TenantCleanupDelay      time.Duration // Delay before removing tenant deletion mark and "debug".
 	DeleteBlocksConcurrency int
+	MetadataMergingEnabled  bool          // Whether to merge the metrics metadata uploaded by the ingesters.
+	MetadataRetentionPeriod time.Duration // Period after which metrics metadata not seen anymore is removed.
 }
 
 type BlocksCleaner struct {
@@ -345,6 +348,12 @@ func (c *BlocksCleaner) deleteUserMarkedForDeletion(ctx context.Context, userID
 		level.Info(userLogger).Log("msg", "deleted files under "+block.DebugMetas+" for tenant marked for deletion", "count", deleted)
 	}
 
+	if deleted, err := bucket.DeletePrefix(ctx, userBucket, metricsmetadata.MetadataDir, userLogger); err != nil {
+		return errors.Wrap(err, "failed to delete metrics metadata")
+	} else if deleted > 0 {
+		level.Info(userLogger).Log("msg", "deleted metrics metadata files for tenant marked for deletion", "count", deleted)
+	}
+
 	// Tenant deletion mark file is inside Markers as well.
 	if deleted, err := bucket.DeletePrefix(ctx, userBucket, block.MarkersPathname, userLogger); err != nil {
 		return errors.Wrap(err, "failed to delete marker files")
@@ -423,9 +432,35 @@ func (c *BlocksCleaner) cleanUser(ctx context.Context, userID string, userLogger
 	c.tenantPartialBlocks.WithLabelValues(userID).Set(float64(len(partials)))
 	c.tenantBucketIndexLastUpdate.WithLabelValues(userID).SetToCurrentTime()
 
+	if c.cfg.MetadataMergingEnabled {
+		// This is a best effort, so we don't return error if merging the metrics metadata fails.
+		c.mergeUserMetricsMetadata(ctx, userID, userLogger)
+	}
+
 	return nil
 }
 
+// mergeUserMetricsMetadata merges the metrics metadata snapshots uploaded by the ingesters into
+// the tenant merged metadata, applying the metadata retention period.
+func (c *BlocksCleaner) mergeUserMetricsMetadata(ctx context.Context, userID string, userLogger log.Logger) {
+	w := metricsmetadata.NewUpdater(c.bucketClient, userID, c.cfgProvider, userLogger)
+	m, err := w.UpdateMetadata(ctx, c.cfg.MetadataRetentionPeriod)
+	if err != nil {
+		level.Warn(userLogger).Log("msg", "failed to merge metrics metadata", "err", err)
+		return
+	}
+	if m == nil {
+		return
+	}
+
+	if err := metricsmetadata.WriteMergedMetadata(ctx, c.bucketClient, userID, c.cfgProvider, m); err != nil {
+		level.Warn(userLogger).Log("msg", "failed to upload merged metrics metadata", "err", err)
+		return
+	}
+
+	level.Info(userLogger).Log("msg", "merged metrics metadata", "entries", len(m.Metrics))
+}
+
 // Concurrently deletes blocks marked for deletion, and removes blocks from index.
 func (c *BlocksCleaner) deleteBlocksMarkedForDeletion(ctx context.Context, idx *bucketindex.Index, userBucket objstore.Bucket, userLogger log.Logger) {
 	blocksToDelete := make([]ulid.ULID, 0, len(idx.BlockDeletionMarks))
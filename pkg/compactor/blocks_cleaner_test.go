@@ -23,6 +23,7 @@ import (
 	"github.com/oklog/ulid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/textparse"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
@@ -31,6 +32,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
 	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/test"
@@ -334,6 +336,53 @@ func TestBlocksCleaner_ShouldRebuildBucketIndexOnCorruptedOne(t *testing.T) {
 	assert.ElementsMatch(t, []ulid.ULID{block3}, idx.BlockDeletionMarks.GetULIDs())
 }
 
+func TestBlocksCleaner_ShouldMergeMetricsMetadata(t *testing.T) {
+	const userID = "user-1"
+
+	bucketClient, _ := mimir_testutil.PrepareFilesystemBucket(t)
+	bucketClient = block.BucketWithGlobalMarkers(bucketClient)
+
+	ctx := context.Background()
+	now := time.Now()
+	retention := 24 * time.Hour
+	createTSDBBlock(t, bucketClient, userID, 10, 20, 2, nil)
+
+	require.NoError(t, metricsmetadata.WriteMergedMetadata(ctx, bucketClient, userID, nil, &metricsmetadata.Metadata{
+		Version: metricsmetadata.MetadataVersion1,
+		Metrics: []metricsmetadata.Entry{
+			{Metric: "expired", Type: textparse.MetricTypeGauge, LastSeen: now.Add(-2 * retention).Unix()},
+			{Metric: "merged", Type: textparse.MetricTypeGauge, LastSeen: now.Add(-time.Hour).Unix()},
+		},
+		UpdatedAt: now.Add(-time.Hour).Unix(),
+	}))
+	require.NoError(t, metricsmetadata.WriteIngesterMetadata(ctx, bucketClient, userID, nil, "ingester-1", &metricsmetadata.Metadata{
+		Version:   metricsmetadata.MetadataVersion1,
+		Metrics:   []metricsmetadata.Entry{{Metric: "ingester", Type: textparse.MetricTypeCounter, LastSeen: now.Unix()}},
+		UpdatedAt: now.Unix(),
+	}))
+
+	cfg := BlocksCleanerConfig{
+		DeletionDelay:           time.Hour,
+		CleanupInterval:         time.Minute,
+		CleanupConcurrency:      1,
+		DeleteBlocksConcurrency: 1,
+		MetadataMergingEnabled:  true,
+		MetadataRetentionPeriod: retention,
+	}
+
+	logger := log.NewNopLogger()
+	cleaner := NewBlocksCleaner(cfg, bucketClient, tsdb.AllUsers, newMockConfigProvider(), logger, nil)
+	require.NoError(t, services.StartAndAwaitRunning(ctx, cleaner))
+	defer services.StopAndAwaitTerminated(ctx, cleaner) //nolint:errcheck
+
+	m, err := metricsmetadata.ReadMergedMetadata(ctx, bucketClient, userID, nil, logger)
+	require.NoError(t, err)
+	assert.Equal(t, []metricsmetadata.Entry{
+		{Metric: "ingester", Type: textparse.MetricTypeCounter, LastSeen: now.Unix()},
+		{Metric: "merged", Type: textparse.MetricTypeGauge, LastSeen: now.Add(-time.Hour).Unix()},
+	}, m.Metrics)
+}
+
 func TestBlocksCleaner_ShouldRemoveMetricsForTenantsNotBelongingAnymoreToTheShard(t *testing.T) {
 	bucketClient, _ := mimir_testutil.PrepareFilesystemBucket(t)
 	bucketClient = block.BucketWithGlobalMarkers(bucketClient)
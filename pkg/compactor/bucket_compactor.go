@@ -28,9 +28,11 @@ import (
 	"github.com/thanos-io/objstore"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storegateway/indexheader"
 )
 
 type DeduplicateFilter interface {
@@ -375,11 +377,20 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 			newLabels[mimir_tsdb.CompactorShardIDExternalLabel] = sharding.FormatShardIDLabelValue(uint64(blockToUpload.shardIndex), uint64(job.SplittingShards()))
 		}
 
+		// The label names cardinality is only used for query planning, so failing to gather it doesn't
+		// fail the compaction, and the block meta.json is written without it.
+		topLabelNames, err := block.GatherTopLabelNamesCardinality(bdir, block.DefaultTopLabelNamesLimit)
+		if err != nil {
+			level.Warn(jobLogger).Log("msg", "failed to gather label names cardinality, the block meta.json is written without it", "block", bdir, "err", err)
+			topLabelNames = nil
+		}
+
 		newMeta, err := block.InjectThanosMeta(jobLogger, bdir, block.ThanosMeta{
-			Labels:       newLabels,
-			Downsample:   block.ThanosDownsample{Resolution: job.Resolution()},
-			Source:       block.CompactorSource,
-			SegmentFiles: block.GetSegmentFiles(bdir),
+			Labels:        newLabels,
+			Downsample:    block.ThanosDownsample{Resolution: job.Resolution()},
+			Source:        block.CompactorSource,
+			SegmentFiles:  block.GetSegmentFiles(bdir),
+			TopLabelNames: topLabelNames,
 		}, nil)
 		if err != nil {
 			return errors.Wrapf(err, "failed to finalize the block %s", bdir)
@@ -389,6 +400,29 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 			return errors.Wrap(err, "remove tombstones")
 		}
 
+		// Exemplars are not compacted by the TSDB compactor, so they're merged from the source blocks.
+		shardCount := uint64(1)
+		if job.UseSplitting() {
+			shardCount = uint64(job.SplittingShards())
+		}
+		if err := writeCompactedBlockExemplars(bdir, newMeta, blocksToCompactDirs, uint64(blockToUpload.shardIndex), shardCount); err != nil {
+			return errors.Wrapf(err, "failed to write exemplars of block %s", bdir)
+		}
+
+		if c.labelBloomFilterFalsePositiveRate > 0 {
+			if err := block.WriteLabelBloomFilterFile(bdir, c.labelBloomFilterFalsePositiveRate); err != nil {
+				return errors.Wrapf(err, "failed to write label bloom filter of block %s", bdir)
+			}
+		}
+
+		// The sparse index-header is an optimization for the store-gateways, which build it themselves if
+		// missing, so failing to write it doesn't fail the compaction.
+		if c.sparseIndexHeaderSampling > 0 {
+			if err := indexheader.WriteSparseHeaderFile(ctx, jobLogger, bdir, c.sparseIndexHeaderSampling); err != nil {
+				level.Warn(jobLogger).Log("msg", "failed to write sparse index-header, the block is uploaded without it", "block", bdir, "err", err)
+			}
+		}
+
 		// Ensure the output block is valid.
 		if err := block.VerifyBlock(jobLogger, bdir, newMeta.MinTime, newMeta.MaxTime, false); err != nil {
 			return errors.Wrapf(err, "invalid result block %s", bdir)
@@ -422,6 +456,74 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 	return true, compIDs, nil
 }
 
+// writeCompactedBlockExemplars writes to the compacted block directory the exemplars of the source block directories
+// within the block time range, whose series belong to the block shard. Series are assigned to shards the same way as
+// the split compaction does. The exemplars files of the source blocks are merged while streaming them, so that the
+// exemplars are never loaded in memory all at once. Source blocks without exemplars file are skipped.
+func writeCompactedBlockExemplars(blockDir string, meta *block.Meta, sourceDirs []string, shardIndex, shardCount uint64) (err error) {
+	var its []block.ExemplarsIterator
+	for _, dir := range sourceDirs {
+		f, openErr := os.Open(filepath.Join(dir, block.ExemplarsFilename))
+		if os.IsNotExist(openErr) {
+			continue
+		}
+		if openErr != nil {
+			return errors.Wrapf(openErr, "open exemplars of block %s", dir)
+		}
+		defer runutil.CloseWithErrCapture(&err, f, "close exemplars file")
+
+		r, readErr := block.NewExemplarsReader(f)
+		if readErr != nil {
+			return errors.Wrapf(readErr, "read exemplars of block %s", dir)
+		}
+		its = append(its, r)
+	}
+
+	var w *block.ExemplarsWriter
+	defer func() {
+		if err != nil && w != nil {
+			w.Abort()
+		}
+	}()
+
+	it := block.NewMergedExemplarsIterator(its...)
+	for it.Next() {
+		s := it.At()
+		if shardCount > 1 && labels.StableHash(mimirpb.FromLabelAdaptersToLabels(s.Labels))%shardCount != shardIndex {
+			continue
+		}
+
+		var filtered []mimirpb.Exemplar
+		for _, e := range s.Exemplars {
+			// The block max time is exclusive.
+			if e.TimestampMs >= meta.MinTime && e.TimestampMs < meta.MaxTime {
+				filtered = append(filtered, e)
+			}
+		}
+		if len(filtered) == 0 {
+			continue
+		}
+
+		// The exemplars file is created only if the block has exemplars.
+		if w == nil {
+			if w, err = block.NewExemplarsWriter(blockDir); err != nil {
+				return err
+			}
+		}
+		if err := w.Write(mimirpb.TimeSeries{Labels: s.Labels, Exemplars: filtered}); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return errors.Wrap(err, "read exemplars of source blocks")
+	}
+
+	if w == nil {
+		return nil
+	}
+	return w.Close()
+}
+
 // convertCompactionResultToForEachJobs filters out empty ULIDs.
 // When handling result of split compactions, shard index is index in the slice returned by compaction.
 func convertCompactionResultToForEachJobs(compactedBlocks []ulid.ULID, splitJob bool, jobLogger log.Logger) []ulidWithShardIndex {
@@ -629,6 +731,14 @@ type BucketCompactor struct {
 	waitPeriod                     time.Duration
 	blockSyncConcurrency           int
 	metrics                        *BucketCompactorMetrics
+
+	// labelBloomFilterFalsePositiveRate is the false positive rate of the label bloom filter
+	// written for each compacted block. The filter is not written if 0.
+	labelBloomFilterFalsePositiveRate float64
+
+	// sparseIndexHeaderSampling is the postings offsets in-memory sampling of the sparse index-header
+	// written for each compacted block. The sparse index-header is not written if 0.
+	sparseIndexHeaderSampling int
 }
 
 // NewBucketCompactor creates a new bucket compactor.
@@ -646,6 +756,8 @@ func NewBucketCompactor(
 	sortJobs JobsOrderFunc,
 	waitPeriod time.Duration,
 	blockSyncConcurrency int,
+	labelBloomFilterFalsePositiveRate float64,
+	sparseIndexHeaderSampling int,
 	metrics *BucketCompactorMetrics,
 ) (*BucketCompactor, error) {
 	if concurrency <= 0 {
@@ -666,6 +778,9 @@ func NewBucketCompactor(
 		waitPeriod:                     waitPeriod,
 		blockSyncConcurrency:           blockSyncConcurrency,
 		metrics:                        metrics,
+
+		labelBloomFilterFalsePositiveRate: labelBloomFilterFalsePositiveRate,
+		sparseIndexHeaderSampling:         sparseIndexHeaderSampling,
 	}, nil
 }
 
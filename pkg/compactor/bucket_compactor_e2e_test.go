@@ -242,7 +242,7 @@ func TestGroupCompactE2E(t *testing.T) {
 		planner := NewSplitAndMergePlanner([]int64{1000, 3000})
 		grouper := NewSplitAndMergeGrouper("user-1", []int64{1000, 3000}, 0, 0, logger)
 		metrics := NewBucketCompactorMetrics(blocksMarkedForDeletion, prometheus.NewPedanticRegistry())
-		bComp, err := NewBucketCompactor(logger, sy, grouper, planner, comp, dir, bkt, 2, true, ownAllJobs, sortJobsByNewestBlocksFirst, 0, 4, metrics)
+		bComp, err := NewBucketCompactor(logger, sy, grouper, planner, comp, dir, bkt, 2, true, ownAllJobs, sortJobsByNewestBlocksFirst, 0, 4, 0.01, 32, metrics)
 		require.NoError(t, err)
 
 		// Compaction on empty should not fail.
@@ -408,6 +408,17 @@ func TestGroupCompactE2E(t *testing.T) {
 			assert.True(t, labels.Equal(extLabels, labels.FromMap(meta.Thanos.Labels)), "ext labels does not match")
 			assert.Equal(t, int64(124), meta.Thanos.Downsample.Resolution)
 			assert.True(t, len(meta.Thanos.SegmentFiles) > 0, "compacted blocks have segment files set")
+			assert.NotEmpty(t, meta.Thanos.TopLabelNames, "compacted blocks have the top label names set")
+
+			// Check the label bloom filter has been uploaded alongside the block.
+			exists, err := bkt.Exists(ctx, path.Join(meta.ULID.String(), block.LabelBloomFilterFilename))
+			require.NoError(t, err)
+			assert.True(t, exists, "compacted blocks have the label bloom filter")
+
+			// Check the sparse index-header has been uploaded alongside the block.
+			exists, err = bkt.Exists(ctx, path.Join(meta.ULID.String(), block.SparseIndexHeaderFilename))
+			require.NoError(t, err)
+			assert.True(t, exists, "compacted blocks have the sparse index-header")
 		}
 		{
 			meta, ok := others[defaultGroupKey(124, extLabels2)]
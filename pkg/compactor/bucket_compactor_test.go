@@ -7,6 +7,7 @@ package compactor
 
 import (
 	"context"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -22,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util/extprom"
 )
@@ -119,7 +121,7 @@ func TestBucketCompactor_FilterOwnJobs(t *testing.T) {
 	m := NewBucketCompactorMetrics(promauto.With(nil).NewCounter(prometheus.CounterOpts{}), nil)
 	for testName, testCase := range tests {
 		t.Run(testName, func(t *testing.T) {
-			bc, err := NewBucketCompactor(log.NewNopLogger(), nil, nil, nil, nil, "", nil, 2, false, testCase.ownJob, nil, 0, 4, m)
+			bc, err := NewBucketCompactor(log.NewNopLogger(), nil, nil, nil, nil, "", nil, 2, false, testCase.ownJob, nil, 0, 4, 0, 0, m)
 			require.NoError(t, err)
 
 			res, err := bc.filterOwnJobs(jobsFn())
@@ -155,7 +157,7 @@ func TestBlockMaxTimeDeltas(t *testing.T) {
 
 	metrics := NewBucketCompactorMetrics(promauto.With(nil).NewCounter(prometheus.CounterOpts{}), nil)
 	now := time.UnixMilli(1500002900159)
-	bc, err := NewBucketCompactor(log.NewNopLogger(), nil, nil, nil, nil, "", nil, 2, false, nil, nil, 0, 4, metrics)
+	bc, err := NewBucketCompactor(log.NewNopLogger(), nil, nil, nil, nil, "", nil, 2, false, nil, nil, 0, 4, 0, 0, metrics)
 	require.NoError(t, err)
 
 	deltas := bc.blockMaxTimeDeltas(now, []*Job{j1, j2})
@@ -280,3 +282,56 @@ func TestConvertCompactionResultToForEachJobs(t *testing.T) {
 	require.Equal(t, ulidWithShardIndex{ulid: ulid1, shardIndex: 1}, res[0])
 	require.Equal(t, ulidWithShardIndex{ulid: ulid2, shardIndex: 3}, res[1])
 }
+
+func TestWriteCompactedBlockExemplars(t *testing.T) {
+	exemplar := func(ts int64) mimirpb.Exemplar {
+		return mimirpb.Exemplar{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: float64(ts), TimestampMs: ts}
+	}
+
+	seriesA := labels.FromStrings("__name__", "a")
+	seriesB := labels.FromStrings("__name__", "c")
+	require.NotEqual(t, labels.StableHash(seriesA)%2, labels.StableHash(seriesB)%2, "test series are expected to belong to different shards")
+
+	// The exemplars of the series A are split across two source blocks, while the third source block has no exemplars file.
+	sourceDirs := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	require.NoError(t, block.WriteExemplarsFile(sourceDirs[0], []mimirpb.TimeSeries{
+		{Labels: mimirpb.FromLabelsToLabelAdapters(seriesA), Exemplars: []mimirpb.Exemplar{exemplar(5), exemplar(12)}},
+		{Labels: mimirpb.FromLabelsToLabelAdapters(seriesB), Exemplars: []mimirpb.Exemplar{exemplar(15)}},
+	}))
+	require.NoError(t, block.WriteExemplarsFile(sourceDirs[1], []mimirpb.TimeSeries{
+		{Labels: mimirpb.FromLabelsToLabelAdapters(seriesA), Exemplars: []mimirpb.Exemplar{exemplar(10), exemplar(20)}},
+	}))
+	meta := &block.Meta{BlockMeta: tsdb.BlockMeta{MinTime: 10, MaxTime: 20}}
+
+	t.Run("without splitting", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, writeCompactedBlockExemplars(dir, meta, sourceDirs, 0, 1))
+
+		actual, err := block.ReadExemplarsFile(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []mimirpb.TimeSeries{
+			{Labels: mimirpb.FromLabelsToLabelAdapters(seriesA), Exemplars: []mimirpb.Exemplar{exemplar(10), exemplar(12)}},
+			{Labels: mimirpb.FromLabelsToLabelAdapters(seriesB), Exemplars: []mimirpb.Exemplar{exemplar(15)}},
+		}, actual)
+	})
+
+	t.Run("with splitting", func(t *testing.T) {
+		dir := t.TempDir()
+		shardIndex := labels.StableHash(seriesB) % 2
+		require.NoError(t, writeCompactedBlockExemplars(dir, meta, sourceDirs, shardIndex, 2))
+
+		actual, err := block.ReadExemplarsFile(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []mimirpb.TimeSeries{
+			{Labels: mimirpb.FromLabelsToLabelAdapters(seriesB), Exemplars: []mimirpb.Exemplar{exemplar(15)}},
+		}, actual)
+	})
+
+	t.Run("no exemplars within the block", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, writeCompactedBlockExemplars(dir, &block.Meta{BlockMeta: tsdb.BlockMeta{MinTime: 100, MaxTime: 200}}, sourceDirs, 0, 1))
+
+		_, err := block.ReadExemplarsFile(dir)
+		require.True(t, os.IsNotExist(err))
+	})
+}
@@ -58,6 +58,8 @@ var (
 	errInvalidMaxClosingBlocksConcurrency         = fmt.Errorf("invalid max-closing-blocks-concurrency value, must be positive")
 	errInvalidSymbolFlushersConcurrency           = fmt.Errorf("invalid symbols-flushers-concurrency value, must be positive")
 	errInvalidMaxBlockUploadValidationConcurrency = fmt.Errorf("invalid max-block-upload-validation-concurrency value, can't be negative")
+	errInvalidMetadataRetentionPeriod             = fmt.Errorf("invalid metadata-retention-period value, must be positive when metadata merging is enabled")
+	errInvalidLabelBloomFilterFalsePositiveRate   = fmt.Errorf("invalid label-bloom-filter-false-positive-rate value, must be greater than 0 and less than 1")
 	RingOp                                        = ring.NewOp([]ring.InstanceState{ring.ACTIVE}, nil)
 )
 
@@ -95,6 +97,13 @@ type Config struct {
 	TenantCleanupDelay    time.Duration           `yaml:"tenant_cleanup_delay" category:"advanced"`
 	MaxCompactionTime     time.Duration           `yaml:"max_compaction_time" category:"advanced"`
 
+	MetadataMergingEnabled  bool          `yaml:"metadata_merging_enabled" category:"experimental"`
+	MetadataRetentionPeriod time.Duration `yaml:"metadata_retention_period" category:"experimental"`
+
+	LabelBloomFilterEnabled           bool    `yaml:"label_bloom_filter_enabled" category:"experimental"`
+	LabelBloomFilterFalsePositiveRate float64 `yaml:"label_bloom_filter_false_positive_rate" category:"experimental"`
+	SparseIndexHeadersUploadEnabled   bool    `yaml:"sparse_index_headers_upload_enabled" category:"experimental"`
+
 	// Compactor concurrency options
 	MaxOpeningBlocksConcurrency         int `yaml:"max_opening_blocks_concurrency" category:"advanced"`          // Number of goroutines opening blocks before compaction.
 	MaxClosingBlocksConcurrency         int `yaml:"max_closing_blocks_concurrency" category:"advanced"`          // Max number of blocks that can be closed concurrently during split compaction. Note that closing of newly compacted block uses a lot of memory for writing index.
@@ -144,6 +153,11 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 		"If not 0, blocks will be marked for deletion and compactor component will permanently delete blocks marked for deletion from the bucket. "+
 		"If 0, blocks will be deleted straight away. Note that deleting blocks immediately can cause query failures.")
 	f.DurationVar(&cfg.TenantCleanupDelay, "compactor.tenant-cleanup-delay", 6*time.Hour, "For tenants marked for deletion, this is time between deleting of last block, and doing final cleanup (marker files, debug files) of the tenant.")
+	f.BoolVar(&cfg.MetadataMergingEnabled, "compactor.metadata-merging-enabled", false, "If enabled, the compactor merges the metrics metadata uploaded by the ingesters into the tenant metrics metadata stored in the bucket, during blocks cleanup and maintenance. Ingesters upload metrics metadata when -ingester.metadata-shipping-interval is set.")
+	f.DurationVar(&cfg.MetadataRetentionPeriod, "compactor.metadata-retention-period", 30*24*time.Hour, "Metrics metadata that has not been seen for longer than this period is removed from the tenant metrics metadata stored in the bucket.")
+	f.BoolVar(&cfg.LabelBloomFilterEnabled, "compactor.label-bloom-filter-enabled", false, "If enabled, the compactor builds a bloom filter of the label name/value pairs of each compacted block, and uploads it alongside the block. Store-gateways can use it to skip blocks that don't contain the label values requested by a query.")
+	f.Float64Var(&cfg.LabelBloomFilterFalsePositiveRate, "compactor.label-bloom-filter-false-positive-rate", 0.01, "The false positive rate the label bloom filter of each compacted block is sized for. Lower values increase the size of the filter.")
+	f.BoolVar(&cfg.SparseIndexHeadersUploadEnabled, "compactor.sparse-index-headers-upload-enabled", false, "If enabled, the compactor builds the sparse index-header of each compacted block, using -blocks-storage.bucket-store.posting-offsets-in-mem-sampling, and uploads it alongside the block. Store-gateways download it instead of rebuilding it when -blocks-storage.bucket-store.index-header.sparse-persistence-enabled is enabled. Building the sparse index-header requires building the full index-header of each compacted block in a temporary directory under the compaction directory, which costs additional disk space (the size of the index-header) and CPU. Failing to build it doesn't fail the compaction: the block is uploaded without it.")
 	// compactor concurrency options
 	f.IntVar(&cfg.MaxOpeningBlocksConcurrency, "compactor.max-opening-blocks-concurrency", 1, "Number of goroutines opening blocks before compaction.")
 	f.IntVar(&cfg.MaxClosingBlocksConcurrency, "compactor.max-closing-blocks-concurrency", 1, "Max number of blocks that can be closed concurrently during split compaction. Note that closing of newly compacted block uses a lot of memory for writing index.")
@@ -154,6 +168,15 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.Var(&cfg.DisabledTenants, "compactor.disabled-tenants", "Comma separated list of tenants that cannot be compacted by this compactor. If specified, and compactor would normally pick given tenant for compaction (via -compactor.enabled-tenants or sharding), it will be ignored instead.")
 }
 
+// labelBloomFilterFalsePositiveRate returns the false positive rate of the blocks label bloom filter,
+// or 0 if the label bloom filter is disabled.
+func (cfg *Config) labelBloomFilterFalsePositiveRate() float64 {
+	if !cfg.LabelBloomFilterEnabled {
+		return 0
+	}
+	return cfg.LabelBloomFilterFalsePositiveRate
+}
+
 func (cfg *Config) Validate() error {
 	// Each block range period should be divisible by the previous one.
 	for i := 1; i < len(cfg.BlockRanges); i++ {
@@ -177,6 +200,12 @@ func (cfg *Config) Validate() error {
 	if !util.StringsContain(CompactionOrders, cfg.CompactionJobsOrder) {
 		return errInvalidCompactionOrder
 	}
+	if cfg.MetadataMergingEnabled && cfg.MetadataRetentionPeriod <= 0 {
+		return errInvalidMetadataRetentionPeriod
+	}
+	if cfg.LabelBloomFilterEnabled && (cfg.LabelBloomFilterFalsePositiveRate <= 0 || cfg.LabelBloomFilterFalsePositiveRate >= 1) {
+		return errInvalidLabelBloomFilterFalsePositiveRate
+	}
 
 	return nil
 }
@@ -474,6 +503,8 @@ func (c *MultitenantCompactor) starting(ctx context.Context) error {
 		CleanupConcurrency:      c.compactorCfg.CleanupConcurrency,
 		TenantCleanupDelay:      c.compactorCfg.TenantCleanupDelay,
 		DeleteBlocksConcurrency: defaultDeleteBlocksConcurrency,
+		MetadataMergingEnabled:  c.compactorCfg.MetadataMergingEnabled,
+		MetadataRetentionPeriod: c.compactorCfg.MetadataRetentionPeriod,
 	}, c.bucketClient, c.shardingStrategy.blocksCleanerOwnUser, c.cfgProvider, c.parentLogger, c.registerer)
 
 	// Start blocks cleaner asynchronously, don't wait until initial cleanup is finished.
@@ -754,6 +785,8 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 		c.jobsOrder,
 		c.compactorCfg.CompactionWaitPeriod,
 		c.compactorCfg.BlockSyncConcurrency,
+		c.compactorCfg.labelBloomFilterFalsePositiveRate(),
+		c.sparseIndexHeaderSampling(),
 		c.bucketCompactorMetrics,
 	)
 	if err != nil {
@@ -767,6 +800,15 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 	return nil
 }
 
+// sparseIndexHeaderSampling returns the postings offsets in-memory sampling of the sparse index-header
+// written for each compacted block, or 0 if the sparse index-headers upload is disabled.
+func (c *MultitenantCompactor) sparseIndexHeaderSampling() int {
+	if !c.compactorCfg.SparseIndexHeadersUploadEnabled {
+		return 0
+	}
+	return c.storageCfg.BucketStore.PostingOffsetsInMemSampling
+}
+
 func (c *MultitenantCompactor) discoverUsersWithRetries(ctx context.Context) ([]string, error) {
 	var lastErr error
 
@@ -128,6 +128,20 @@ func TestConfig_Validate(t *testing.T) {
 			setup:    func(cfg *Config) { cfg.SymbolsFlushersConcurrency = 0 },
 			expected: errInvalidSymbolFlushersConcurrency.Error(),
 		},
+		"should fail on invalid value of metadata-retention-period when metadata merging is enabled": {
+			setup: func(cfg *Config) {
+				cfg.MetadataMergingEnabled = true
+				cfg.MetadataRetentionPeriod = 0
+			},
+			expected: errInvalidMetadataRetentionPeriod.Error(),
+		},
+		"should fail on invalid value of label-bloom-filter-false-positive-rate when label bloom filter is enabled": {
+			setup: func(cfg *Config) {
+				cfg.LabelBloomFilterEnabled = true
+				cfg.LabelBloomFilterFalsePositiveRate = 1
+			},
+			expected: errInvalidLabelBloomFilterFalsePositiveRate.Error(),
+		},
 	}
 
 	for testName, testData := range tests {
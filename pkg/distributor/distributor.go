@@ -99,6 +99,9 @@ type Distributor struct {
 	// For handling HA replicas.
 	HATracker *haTracker
 
+	// For mirroring accepted write requests, nil if disabled.
+	writeMirror *writeMirror
+
 	// Per-user rate limiters.
 	requestRateLimiter   *limiter.RateLimiter
 	ingestionRateLimiter *limiter.RateLimiter
@@ -126,6 +129,7 @@ type Distributor struct {
 	incomingMetadata                 *prometheus.CounterVec
 	nonHASamples                     *prometheus.CounterVec
 	dedupedSamples                   *prometheus.CounterVec
+	createdTimestampZeroSamples      *prometheus.CounterVec
 	labelsHistogram                  prometheus.Histogram
 	sampleDelayHistogram             prometheus.Histogram
 	replicationFactor                prometheus.Gauge
@@ -141,6 +145,9 @@ type Distributor struct {
 	// Metrics for data rejected for hitting per-instance limits
 	rejectedRequests *prometheus.CounterVec
 
+	// Metrics for push requests rejected by ingesters because of utilization based limiting
+	ingesterPushTooBusyRejections prometheus.Counter
+
 	sampleValidationMetrics   *validation.SampleValidationMetrics
 	exemplarValidationMetrics *validation.ExemplarValidationMetrics
 	metadataValidationMetrics *validation.MetadataValidationMetrics
@@ -158,6 +165,8 @@ type Config struct {
 
 	HATrackerConfig HATrackerConfig `yaml:"ha_tracker"`
 
+	WriteMirror WriteMirrorConfig `yaml:"write_mirror"`
+
 	MaxRecvMsgSize int           `yaml:"max_recv_msg_size" category:"advanced"`
 	RemoteTimeout  time.Duration `yaml:"remote_timeout" category:"advanced"`
 
@@ -195,6 +204,7 @@ type PushWrapper func(next push.Func) push.Func
 func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	cfg.PoolConfig.RegisterFlags(f)
 	cfg.HATrackerConfig.RegisterFlags(f)
+	cfg.WriteMirror.RegisterFlags(f)
 	cfg.DistributorRing.RegisterFlags(f, logger)
 
 	f.IntVar(&cfg.MaxRecvMsgSize, "distributor.max-recv-msg-size", 100<<20, "Max message size in bytes that the distributors will accept for incoming push requests to the remote write API. If exceeded, the request will be rejected.")
@@ -209,7 +219,11 @@ func (cfg *Config) Validate(limits validation.Limits) error {
 		return errInvalidTenantShardSize
 	}
 
-	return cfg.HATrackerConfig.Validate()
+	if err := cfg.HATrackerConfig.Validate(); err != nil {
+		return err
+	}
+
+	return cfg.WriteMirror.Validate()
 }
 
 const (
@@ -303,6 +317,11 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 			Name:      "distributor_deduped_samples_total",
 			Help:      "The total number of deduplicated samples.",
 		}, []string{"user", "cluster"}),
+		createdTimestampZeroSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Name:      "distributor_created_timestamp_zero_samples_injected_total",
+			Help:      "The total number of zero samples synthesized at the created timestamp of counters and histograms.",
+		}, []string{"user"}),
 		labelsHistogram: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
 			Namespace: "cortex",
 			Name:      "labels_per_sample",
@@ -349,6 +368,11 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 			Help: "Requests discarded for hitting per-instance limits",
 		}, []string{"reason"}),
 
+		ingesterPushTooBusyRejections: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_distributor_ingester_push_too_busy_rejections_total",
+			Help: "The total number of push requests to ingesters rejected because the ingester was too busy. The rejected write requests are retriable.",
+		}),
+
 		sampleValidationMetrics:   validation.NewSampleValidationMetrics(reg),
 		exemplarValidationMetrics: validation.NewExemplarValidationMetrics(reg),
 		metadataValidationMetrics: validation.NewMetadataValidationMetrics(reg),
@@ -429,6 +453,11 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 	d.distributorsLifecycler = distributorsLifecycler
 	d.distributorsRing = distributorsRing
 
+	if cfg.WriteMirror.enabled() {
+		d.writeMirror = newWriteMirror(cfg.WriteMirror, limits, reg, log)
+		subservices = append(subservices, d.writeMirror)
+	}
+
 	d.replicationFactor.Set(float64(ingestersRing.ReplicationFactor()))
 	d.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(d.cleanupInactiveUser)
 	d.activeGroups = activeGroupsCleanupService
@@ -519,6 +548,9 @@ func (d *Distributor) cleanupInactiveUser(userID string) {
 	d.ingestersRing.CleanupShuffleShardCache(userID)
 
 	d.HATracker.cleanupHATrackerMetricsForUser(userID)
+	if d.writeMirror != nil {
+		d.writeMirror.cleanupMetricsForUser(userID)
+	}
 
 	d.receivedRequests.DeleteLabelValues(userID)
 	d.receivedSamples.DeleteLabelValues(userID)
@@ -529,6 +561,7 @@ func (d *Distributor) cleanupInactiveUser(userID string) {
 	d.incomingExemplars.DeleteLabelValues(userID)
 	d.incomingMetadata.DeleteLabelValues(userID)
 	d.nonHASamples.DeleteLabelValues(userID)
+	d.createdTimestampZeroSamples.DeleteLabelValues(userID)
 	d.latestSeenSampleTimestampPerUser.DeleteLabelValues(userID)
 
 	filter := prometheus.Labels{"user": userID}
@@ -557,35 +590,11 @@ func (d *Distributor) stopping(_ error) error {
 }
 
 func (d *Distributor) tokenForLabels(userID string, labels []mimirpb.LabelAdapter) uint32 {
-	return shardByAllLabels(userID, labels)
+	return ingester_client.ShardByAllLabelAdapters(userID, labels)
 }
 
 func (d *Distributor) tokenForMetadata(userID string, metricName string) uint32 {
-	return shardByMetricName(userID, metricName)
-}
-
-// shardByMetricName returns the token for the given metric. The provided metricName
-// is guaranteed to not be retained.
-func shardByMetricName(userID string, metricName string) uint32 {
-	h := shardByUser(userID)
-	h = ingester_client.HashAdd32(h, metricName)
-	return h
-}
-
-func shardByUser(userID string) uint32 {
-	h := ingester_client.HashNew32()
-	h = ingester_client.HashAdd32(h, userID)
-	return h
-}
-
-// This function generates different values for different order of same labels.
-func shardByAllLabels(userID string, labels []mimirpb.LabelAdapter) uint32 {
-	h := shardByUser(userID)
-	for _, label := range labels {
-		h = ingester_client.HashAdd32(h, label.Name)
-		h = ingester_client.HashAdd32(h, label.Value)
-	}
-	return h
+	return ingester_client.ShardByMetricName(userID, metricName)
 }
 
 // Returns a boolean that indicates whether or not we want to remove the replica label going forward,
@@ -672,6 +681,59 @@ func (d *Distributor) validateSeries(nowt time.Time, ts *mimirpb.PreallocTimeser
 	return nil
 }
 
+// injectCreatedTimestampZeroSamples adds a zero sample at the created timestamp of the series, if the created
+// timestamp is known and it's older than the first sample of the series. Float samples and native histograms
+// are handled separately, because a series could contain both. Gauge histograms are skipped, since they have
+// no start time. Returns the number of synthesized samples.
+//
+// A zero sample is never injected at or after the timestamp of an existing sample, so it can't overwrite or
+// duplicate data sent by the client. The same created timestamp is usually sent in every request, so synthesized
+// samples are marked in the series, and the ingesters don't report them as failed when they're out-of-order,
+// too old or duplicated. Markers sent by the client are overwritten.
+func injectCreatedTimestampZeroSamples(ts *mimirpb.PreallocTimeseries) int {
+	ct := ts.CreatedTimestamp
+
+	zeroSample := ct > 0 && len(ts.Samples) > 0 && ct < ts.Samples[0].TimestampMs
+	if zeroSample {
+		ts.PrependSample(mimirpb.Sample{TimestampMs: ct, Value: 0})
+	}
+
+	zeroHistogram := ct > 0 && len(ts.Histograms) > 0 && ct < ts.Histograms[0].Timestamp && !ts.Histograms[0].IsGauge()
+	if zeroHistogram {
+		ts.PrependHistogram(createdTimestampZeroHistogram(ct, &ts.Histograms[0]))
+	}
+
+	ts.SetCreatedTimestampZeroSamples(zeroSample, zeroHistogram)
+
+	injected := 0
+	if zeroSample {
+		injected++
+	}
+	if zeroHistogram {
+		injected++
+	}
+	return injected
+}
+
+// createdTimestampZeroHistogram returns an empty native histogram at the given timestamp, with the same
+// schema, zero threshold and counter type (integer or float) of the reference histogram.
+func createdTimestampZeroHistogram(ts int64, ref *mimirpb.Histogram) mimirpb.Histogram {
+	h := mimirpb.Histogram{
+		Schema:        ref.Schema,
+		ZeroThreshold: ref.ZeroThreshold,
+		Timestamp:     ts,
+		ResetHint:     mimirpb.Histogram_UNKNOWN,
+	}
+	if ref.IsFloatHistogram() {
+		h.Count = &mimirpb.Histogram_CountFloat{CountFloat: 0}
+		h.ZeroCount = &mimirpb.Histogram_ZeroCountFloat{ZeroCountFloat: 0}
+	} else {
+		h.Count = &mimirpb.Histogram_CountInt{CountInt: 0}
+		h.ZeroCount = &mimirpb.Histogram_ZeroCountInt{ZeroCountInt: 0}
+	}
+	return h
+}
+
 // wrapPushWithMiddlewares returns push function wrapped in all Distributor's middlewares.
 // push wrappers will be applied to incoming requests in the order in which they are in the slice in the config struct.
 func (d *Distributor) wrapPushWithMiddlewares(next push.Func) push.Func {
@@ -685,6 +747,9 @@ func (d *Distributor) wrapPushWithMiddlewares(next push.Func) push.Func {
 	middlewares = append(middlewares, d.prePushHaDedupeMiddleware)
 	middlewares = append(middlewares, d.prePushRelabelMiddleware)
 	middlewares = append(middlewares, d.prePushValidationMiddleware)
+	if d.writeMirror != nil {
+		middlewares = append(middlewares, d.writeMirrorMiddleware) // mirrors write requests accepted by the previous middlewares
+	}
 	middlewares = append(middlewares, d.cfg.PushWrappers...)
 
 	for ix := len(middlewares) - 1; ix >= 0; ix-- {
@@ -892,6 +957,10 @@ func (d *Distributor) prePushValidationMiddleware(next push.Func) push.Func {
 			minExemplarTS = earliestSampleTimestampMs - 5*time.Minute.Milliseconds()
 		}
 
+		// fetch once per push request to avoid processing half the request differently
+		createdTimestampZeroIngestionEnabled := d.limits.CreatedTimestampZeroIngestionEnabled(userID)
+		createdTimestampZeroSamples := 0
+
 		var firstPartialErr error
 		var removeIndexes []int
 		for tsIdx, ts := range req.Timeseries {
@@ -920,6 +989,14 @@ func (d *Distributor) prePushValidationMiddleware(next push.Func) push.Func {
 
 			validatedSamples += len(ts.Samples) + len(ts.Histograms)
 			validatedExemplars += len(ts.Exemplars)
+
+			// Synthesized samples are added after validation and don't count towards the ingestion rate limit.
+			if createdTimestampZeroIngestionEnabled {
+				createdTimestampZeroSamples += injectCreatedTimestampZeroSamples(&req.Timeseries[tsIdx])
+			}
+		}
+		if createdTimestampZeroSamples > 0 {
+			d.createdTimestampZeroSamples.WithLabelValues(userID).Add(float64(createdTimestampZeroSamples))
 		}
 		if len(removeIndexes) > 0 {
 			for _, removeIndex := range removeIndexes {
@@ -976,6 +1053,28 @@ func (d *Distributor) prePushValidationMiddleware(next push.Func) push.Func {
 	}
 }
 
+// writeMirrorMiddleware asynchronously mirrors the write request to the secondary remote-write endpoint.
+func (d *Distributor) writeMirrorMiddleware(next push.Func) push.Func {
+	return func(ctx context.Context, pushReq *push.Request) (*mimirpb.WriteResponse, error) {
+		cleanupInDefer := true
+		defer func() {
+			if cleanupInDefer {
+				pushReq.CleanUp()
+			}
+		}()
+
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		d.writeMirror.mirror(userID, pushReq)
+
+		cleanupInDefer = false
+		return next(ctx, pushReq)
+	}
+}
+
 // metricsMiddleware updates metrics which are expected to account for all received data,
 // including data that later gets modified or dropped.
 func (d *Distributor) metricsMiddleware(next push.Func) push.Func {
@@ -1180,6 +1279,11 @@ func (d *Distributor) push(ctx context.Context, pushReq *push.Request) (*mimirpb
 		if errors.Is(err, context.DeadlineExceeded) {
 			return httpgrpc.Errorf(500, "exceeded configured distributor remote timeout: %s", err.Error())
 		}
+		if isIngesterTooBusyError(err) {
+			// The ingester is shedding load: the error is tracked separately because it's expected to be
+			// transient, and it's propagated as a 5xx so that the client retries the write request.
+			d.ingesterPushTooBusyRejections.Inc()
+		}
 		return err
 	}, func() { pushReq.CleanUp(); cancel() })
 
@@ -1189,6 +1293,13 @@ func (d *Distributor) push(ctx context.Context, pushReq *push.Request) (*mimirpb
 	return &mimirpb.WriteResponse{}, nil
 }
 
+// isIngesterTooBusyError returns whether the error has been returned by an ingester
+// rejecting the request because of its utilization based limiting.
+func isIngesterTooBusyError(err error) bool {
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	return ok && resp.Code == http.StatusServiceUnavailable && globalerror.IngesterTooBusy.IsInMessage(string(resp.Body))
+}
+
 func preallocSliceIfNeeded[T any](size int) []T {
 	if size > 0 {
 		return make([]T, 0, size)
@@ -1811,18 +1922,23 @@ type UserIDStats struct {
 // AllUserStats returns statistics about all users.
 // Note it does not divide by the ReplicationFactor like UserStats()
 func (d *Distributor) AllUserStats(ctx context.Context) ([]UserIDStats, error) {
+	return allUserStats(ctx, d.ingestersRing, d.ingesterPool)
+}
+
+// allUserStats returns statistics about all users, fetched from the healthy ingesters in the ring.
+func allUserStats(ctx context.Context, ingestersRing ring.ReadRing, ingesterPool *ring_client.Pool) ([]UserIDStats, error) {
 	// Add up by user, across all responses from ingesters
 	perUserTotals := make(map[string]UserStats)
 
 	req := &ingester_client.UserStatsRequest{}
 	ctx = user.InjectOrgID(ctx, "1") // fake: ingester insists on having an org ID
 	// Not using d.forReplicationSet(), so we can fail after first error.
-	replicationSet, err := d.ingestersRing.GetAllHealthy(readNoExtend)
+	replicationSet, err := ingestersRing.GetAllHealthy(readNoExtend)
 	if err != nil {
 		return nil, err
 	}
 	for _, ingester := range replicationSet.Instances {
-		client, err := d.ingesterPool.GetClientFor(ingester.Addr)
+		client, err := ingesterPool.GetClientFor(ingester.Addr)
 		if err != nil {
 			return nil, err
 		}
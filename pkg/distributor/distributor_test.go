@@ -2901,6 +2901,7 @@ func mockWriteRequest(lbls labels.Labels, value float64, timestampMs int64) *mim
 
 type prepConfig struct {
 	numIngesters, happyIngesters       int
+	tooBusyIngesters                   int
 	queryDelay                         time.Duration
 	pushDelay                          time.Duration
 	shuffleShardSize                   int
@@ -2939,6 +2940,7 @@ func prepare(t *testing.T, cfg prepConfig) ([]*Distributor, []mockIngester, []*p
 			zone:                          zone,
 			labelNamesStreamResponseDelay: labelNamesStreamResponseDelay,
 			timeOut:                       cfg.timeOut,
+			tooBusy:                       i < cfg.tooBusyIngesters,
 		})
 	}
 	for i := cfg.happyIngesters; i < cfg.numIngesters; i++ {
@@ -3379,6 +3381,7 @@ type mockIngester struct {
 	zone                          string
 	labelNamesStreamResponseDelay time.Duration
 	timeOut                       bool
+	tooBusy                       bool
 	tokens                        []uint32
 }
 
@@ -3422,6 +3425,10 @@ func (i *mockIngester) Push(ctx context.Context, req *mimirpb.WriteRequest, _ ..
 		return nil, context.DeadlineExceeded
 	}
 
+	if i.tooBusy {
+		return nil, httpgrpc.Errorf(http.StatusServiceUnavailable, globalerror.IngesterTooBusy.Message("the ingester is currently too busy to process write requests, try again later"))
+	}
+
 	if len(req.Timeseries) > 0 && i.timeseries == nil {
 		i.timeseries = map[uint32]*mimirpb.PreallocTimeseries{}
 	}
@@ -3436,14 +3443,17 @@ func (i *mockIngester) Push(ctx context.Context, req *mimirpb.WriteRequest, _ ..
 	}
 
 	for _, series := range req.Timeseries {
-		hash := shardByAllLabels(orgid, series.Labels)
+		hash := client.ShardByAllLabelAdapters(orgid, series.Labels)
 		existing, ok := i.timeseries[hash]
 		if !ok {
 			// Make a copy because the request Timeseries are reused
 			item := mimirpb.TimeSeries{
-				Labels:     make([]mimirpb.LabelAdapter, len(series.TimeSeries.Labels)),
-				Samples:    make([]mimirpb.Sample, len(series.TimeSeries.Samples)),
-				Histograms: make([]mimirpb.Histogram, len(series.TimeSeries.Histograms)),
+				Labels:                        make([]mimirpb.LabelAdapter, len(series.TimeSeries.Labels)),
+				Samples:                       make([]mimirpb.Sample, len(series.TimeSeries.Samples)),
+				Histograms:                    make([]mimirpb.Histogram, len(series.TimeSeries.Histograms)),
+				CreatedTimestamp:              series.TimeSeries.CreatedTimestamp,
+				CreatedTimestampZeroSample:    series.TimeSeries.CreatedTimestampZeroSample,
+				CreatedTimestampZeroHistogram: series.TimeSeries.CreatedTimestampZeroHistogram,
 			}
 
 			copy(item.Labels, series.TimeSeries.Labels)
@@ -3458,7 +3468,7 @@ func (i *mockIngester) Push(ctx context.Context, req *mimirpb.WriteRequest, _ ..
 	}
 
 	for _, m := range req.Metadata {
-		hash := shardByMetricName(orgid, m.MetricFamilyName)
+		hash := client.ShardByMetricName(orgid, m.MetricFamilyName)
 		set, ok := i.metadata[hash]
 		if !ok {
 			set = map[mimirpb.MetricMetadata]struct{}{}
@@ -4093,13 +4103,13 @@ func TestDistributorValidation(t *testing.T) {
 
 // This is not great, but we deal with unsorted labels in prePushRelabelMiddleware.
 func TestShardByAllLabelsReturnsWrongResultsForUnsortedLabels(t *testing.T) {
-	val1 := shardByAllLabels("test", []mimirpb.LabelAdapter{
+	val1 := client.ShardByAllLabelAdapters("test", []mimirpb.LabelAdapter{
 		{Name: "__name__", Value: "foo"},
 		{Name: "bar", Value: "baz"},
 		{Name: "sample", Value: "1"},
 	})
 
-	val2 := shardByAllLabels("test", []mimirpb.LabelAdapter{
+	val2 := client.ShardByAllLabelAdapters("test", []mimirpb.LabelAdapter{
 		{Name: "__name__", Value: "foo"},
 		{Name: "sample", Value: "1"},
 		{Name: "bar", Value: "baz"},
@@ -4168,6 +4178,184 @@ func TestDistributor_Push_Relabel(t *testing.T) {
 	}
 }
 
+func TestDistributor_Push_ShouldTrackIngestersTooBusyRejections(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "user")
+
+	tests := map[string]struct {
+		tooBusyIngesters   int
+		expectedRejections int
+		expectedErr        bool
+	}{
+		"should succeed if a minority of ingesters is too busy": {
+			tooBusyIngesters:   1,
+			expectedRejections: 1,
+		},
+		"should fail with a retriable error if a majority of ingesters is too busy": {
+			tooBusyIngesters:   3,
+			expectedRejections: 3,
+			expectedErr:        true,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ds, _, regs := prepare(t, prepConfig{
+				numIngesters:     3,
+				happyIngesters:   3,
+				tooBusyIngesters: testData.tooBusyIngesters,
+				numDistributors:  1,
+			})
+
+			_, err := ds[0].Push(ctx, makeWriteRequest(0, 1, 0, false, false))
+			if testData.expectedErr {
+				require.Error(t, err)
+				resp, ok := httpgrpc.HTTPResponseFromError(err)
+				require.True(t, ok)
+				assert.Equal(t, http.StatusServiceUnavailable, int(resp.Code))
+				assert.True(t, isIngesterTooBusyError(err))
+			} else {
+				require.NoError(t, err)
+			}
+
+			test.Poll(t, time.Second, nil, func() interface{} {
+				return testutil.GatherAndCompare(regs[0], strings.NewReader(fmt.Sprintf(`
+					# HELP cortex_distributor_ingester_push_too_busy_rejections_total The total number of push requests to ingesters rejected because the ingester was too busy. The rejected write requests are retriable.
+					# TYPE cortex_distributor_ingester_push_too_busy_rejections_total counter
+					cortex_distributor_ingester_push_too_busy_rejections_total %d
+				`, testData.expectedRejections)), "cortex_distributor_ingester_push_too_busy_rejections_total")
+			})
+		})
+	}
+}
+
+func TestDistributor_Push_CreatedTimestampZeroSamples(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	intHistogram := mimirpb.FromHistogramToHistogramProto(now, generateTestHistogram(1))
+	floatHistogram := mimirpb.FromFloatHistogramToHistogramProto(now, generateTestFloatHistogram(1))
+
+	tests := map[string]struct {
+		enabled            bool
+		createdTimestamp   int64
+		zeroSampleMarker   bool
+		samples            []mimirpb.Sample
+		histograms         []mimirpb.Histogram
+		expectedSamples    []mimirpb.Sample
+		expectedHistograms []mimirpb.Histogram
+	}{
+		"should not inject a zero sample if disabled": {
+			createdTimestamp: now - 1000,
+			samples:          []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+			expectedSamples:  []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+		},
+		"should not inject a zero sample if the created timestamp is unknown": {
+			enabled:         true,
+			samples:         []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+			expectedSamples: []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+		},
+		"should inject a zero sample before the first float sample": {
+			enabled:          true,
+			createdTimestamp: now - 1000,
+			samples:          []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+			expectedSamples:  []mimirpb.Sample{{TimestampMs: now - 1000, Value: 0}, {TimestampMs: now, Value: 1}},
+		},
+		"should overwrite the zero sample marker sent by the client": {
+			enabled:          true,
+			createdTimestamp: now,
+			zeroSampleMarker: true,
+			samples:          []mimirpb.Sample{{TimestampMs: now, Value: 0}},
+			expectedSamples:  []mimirpb.Sample{{TimestampMs: now, Value: 0}},
+		},
+		"should not inject a zero sample if a sample already exists at the created timestamp": {
+			enabled:          true,
+			createdTimestamp: now,
+			samples:          []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+			expectedSamples:  []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+		},
+		"should not inject a zero sample if the created timestamp is newer than the first sample": {
+			enabled:          true,
+			createdTimestamp: now + 1000,
+			samples:          []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+			expectedSamples:  []mimirpb.Sample{{TimestampMs: now, Value: 1}},
+		},
+		"should inject an empty integer histogram before the first native histogram": {
+			enabled:          true,
+			createdTimestamp: now - 1000,
+			histograms:       []mimirpb.Histogram{intHistogram},
+			expectedHistograms: []mimirpb.Histogram{{
+				Count:         &mimirpb.Histogram_CountInt{},
+				ZeroCount:     &mimirpb.Histogram_ZeroCountInt{},
+				Schema:        intHistogram.Schema,
+				ZeroThreshold: intHistogram.ZeroThreshold,
+				Timestamp:     now - 1000,
+			}, intHistogram},
+		},
+		"should inject an empty float histogram before the first native float histogram": {
+			enabled:          true,
+			createdTimestamp: now - 1000,
+			histograms:       []mimirpb.Histogram{floatHistogram},
+			expectedHistograms: []mimirpb.Histogram{{
+				Count:         &mimirpb.Histogram_CountFloat{},
+				ZeroCount:     &mimirpb.Histogram_ZeroCountFloat{},
+				Schema:        floatHistogram.Schema,
+				ZeroThreshold: floatHistogram.ZeroThreshold,
+				Timestamp:     now - 1000,
+			}, floatHistogram},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var limits validation.Limits
+			flagext.DefaultValues(&limits)
+			limits.CreatedTimestampZeroIngestionEnabled = tc.enabled
+
+			ds, ingesters, regs := prepare(t, prepConfig{
+				numIngesters:    3,
+				happyIngesters:  3,
+				numDistributors: 1,
+				limits:          &limits,
+			})
+
+			req := &mimirpb.WriteRequest{
+				Timeseries: []mimirpb.PreallocTimeseries{{TimeSeries: &mimirpb.TimeSeries{
+					Labels:                     []mimirpb.LabelAdapter{{Name: model.MetricNameLabel, Value: "foo_total"}},
+					Samples:                    tc.samples,
+					Histograms:                 tc.histograms,
+					CreatedTimestamp:           tc.createdTimestamp,
+					CreatedTimestampZeroSample: tc.zeroSampleMarker,
+				}}},
+			}
+			_, err := ds[0].Push(ctx, req)
+			require.NoError(t, err)
+
+			for i := range ingesters {
+				for _, series := range ingesters[i].series() {
+					assert.ElementsMatch(t, tc.expectedSamples, series.Samples)
+					assert.ElementsMatch(t, tc.expectedHistograms, series.Histograms)
+
+					// Synthesized samples are marked, so that the ingesters can tell them apart from the client ones.
+					if tc.enabled {
+						assert.Equal(t, len(tc.expectedSamples) > len(tc.samples), series.CreatedTimestampZeroSample)
+						assert.Equal(t, len(tc.expectedHistograms) > len(tc.histograms), series.CreatedTimestampZeroHistogram)
+					}
+				}
+			}
+
+			injected := len(tc.expectedSamples) - len(tc.samples) + len(tc.expectedHistograms) - len(tc.histograms)
+			expectedMetrics := ""
+			if injected > 0 {
+				expectedMetrics = fmt.Sprintf(`
+					# HELP cortex_distributor_created_timestamp_zero_samples_injected_total The total number of zero samples synthesized at the created timestamp of counters and histograms.
+					# TYPE cortex_distributor_created_timestamp_zero_samples_injected_total counter
+					cortex_distributor_created_timestamp_zero_samples_injected_total{user="user"} %d
+				`, injected)
+			}
+			assert.NoError(t, testutil.GatherAndCompare(regs[0], strings.NewReader(expectedMetrics), "cortex_distributor_created_timestamp_zero_samples_injected_total"))
+		})
+	}
+}
+
 func countMockIngestersCalls(ingesters []mockIngester, name string) int {
 	count := 0
 	for i := 0; i < len(ingesters); i++ {
@@ -35,6 +35,8 @@ var (
 	errNegativeUpdateTimeoutJitterMax = errors.New("HA tracker max update timeout jitter shouldn't be negative")
 	errInvalidFailoverTimeout         = "HA Tracker failover timeout (%v) must be at least 1s greater than update timeout - max jitter (%v)"
 	errMemberlistUnsupported          = errors.New("memberlist is not supported by the HA tracker since gossip propagation is too slow for HA purposes")
+	errHATrackerDisabled              = errors.New("the HA tracker is disabled")
+	errHAClusterNotFound              = errors.New("the HA cluster is not tracked")
 )
 
 type haTrackerLimits interface {
@@ -240,18 +242,9 @@ func (h *haTracker) loop(ctx context.Context) error {
 		cluster := segments[1]
 
 		if replica.DeletedAt > 0 {
-			h.electedReplicaChanges.DeleteLabelValues(user, cluster)
-			h.electedReplicaTimestamp.DeleteLabelValues(user, cluster)
-
 			h.electedLock.Lock()
-			defer h.electedLock.Unlock()
-			userClusters := h.clusters[user]
-			if userClusters != nil {
-				delete(userClusters, cluster)
-				if len(userClusters) == 0 {
-					delete(h.clusters, user)
-				}
-			}
+			h.deleteCache(user, cluster)
+			h.electedLock.Unlock()
 			return true
 		}
 
@@ -379,8 +372,8 @@ func (h *haTracker) cleanupOldReplicas(ctx context.Context, deadline time.Time)
 			continue
 		}
 
-		// Not marked as deleted yet.
-		if desc.DeletedAt == 0 && timestamp.Time(desc.ReceivedAt).Before(deadline) {
+		// Not marked as deleted yet. Pinned replicas are kept until the pin expires.
+		if desc.DeletedAt == 0 && timestamp.Time(desc.ReceivedAt).Before(deadline) && !isPinned(desc, time.Now()) {
 			err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
 				d, ok := in.(*ReplicaDesc)
 				if !ok || d == nil || d.DeletedAt > 0 || !timestamp.Time(desc.ReceivedAt).Before(deadline) {
@@ -469,6 +462,20 @@ func (h *haTracker) updateCache(userID, cluster string, desc *ReplicaDesc) {
 	h.electedReplicaTimestamp.WithLabelValues(userID, cluster).Set(float64(desc.ReceivedAt / 1000))
 }
 
+// Must be called with electedLock held.
+func (h *haTracker) deleteCache(userID, cluster string) {
+	h.electedReplicaChanges.DeleteLabelValues(userID, cluster)
+	h.electedReplicaTimestamp.DeleteLabelValues(userID, cluster)
+
+	userClusters := h.clusters[userID]
+	if userClusters != nil {
+		delete(userClusters, cluster)
+		if len(userClusters) == 0 {
+			delete(h.clusters, userID)
+		}
+	}
+}
+
 // If we do set the value then err will be nil and desc will contain the value we set.
 // If there is already a valid value in the store, return nil, nil.
 func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica string, now time.Time) error {
@@ -476,20 +483,29 @@ func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica
 	var desc *ReplicaDesc
 	err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
 		var ok bool
+		var pinnedUntil int64
 		if desc, ok = in.(*ReplicaDesc); ok && desc.DeletedAt == 0 {
 			// If the entry in KVStore is up-to-date, just stop the loop.
 			if h.withinUpdateTimeout(now, desc.ReceivedAt) ||
 				// If our replica is different, wait until the failover time.
-				desc.Replica != replica && now.Sub(timestamp.Time(desc.ReceivedAt)) < h.cfg.FailoverTimeout {
+				desc.Replica != replica && now.Sub(timestamp.Time(desc.ReceivedAt)) < h.cfg.FailoverTimeout ||
+				// If our replica is different and the elected one has been pinned, never fail over.
+				desc.Replica != replica && isPinned(desc, now) {
 				return nil, false, nil
 			}
+
+			// Keep the pin when refreshing the timestamp of the elected replica.
+			if desc.Replica == replica {
+				pinnedUntil = desc.PinnedUntil
+			}
 		}
 
 		// Attempt to update KVStore to our timestamp and replica.
 		desc = &ReplicaDesc{
-			Replica:    replica,
-			ReceivedAt: timestamp.FromTime(now),
-			DeletedAt:  0,
+			Replica:     replica,
+			ReceivedAt:  timestamp.FromTime(now),
+			DeletedAt:   0,
+			PinnedUntil: pinnedUntil,
 		}
 		return desc, true, nil
 	})
@@ -505,6 +521,80 @@ func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica
 	return err
 }
 
+// electReplica forces the given replica to be the elected one for the cluster, regardless of the
+// failover timeout. If pinDuration is positive, the replica is also pinned for that duration,
+// otherwise any existing pin is removed. The cluster must already be tracked.
+func (h *haTracker) electReplica(ctx context.Context, userID, cluster, replica string, pinDuration time.Duration, now time.Time) error {
+	return h.casTrackedCluster(ctx, userID, cluster, func(desc *ReplicaDesc) {
+		desc.Replica = replica
+		desc.ReceivedAt = timestamp.FromTime(now)
+		desc.PinnedUntil = pinnedUntil(pinDuration, now)
+	})
+}
+
+// pinReplica pins the currently elected replica of the cluster for the given duration, preventing
+// any failover to a different replica until the pin expires. A zero duration removes the pin.
+func (h *haTracker) pinReplica(ctx context.Context, userID, cluster string, duration time.Duration, now time.Time) error {
+	return h.casTrackedCluster(ctx, userID, cluster, func(desc *ReplicaDesc) {
+		desc.PinnedUntil = pinnedUntil(duration, now)
+	})
+}
+
+// clearCluster marks the cluster's elected replica for deletion, so that every distributor drops it
+// from its cache and a new replica is elected from the next received sample. The entry is removed
+// from the KV store by the periodic cleanup.
+func (h *haTracker) clearCluster(ctx context.Context, userID, cluster string, now time.Time) error {
+	return h.casTrackedCluster(ctx, userID, cluster, func(desc *ReplicaDesc) {
+		desc.DeletedAt = timestamp.FromTime(now)
+	})
+}
+
+// casTrackedCluster updates the KV store entry of a tracked cluster using the provided function,
+// and applies the updated entry to the local cache. Other distributors receive the change via watch.
+func (h *haTracker) casTrackedCluster(ctx context.Context, userID, cluster string, update func(desc *ReplicaDesc)) error {
+	if !h.cfg.EnableHATracker {
+		return errHATrackerDisabled
+	}
+
+	key := fmt.Sprintf("%s/%s", userID, cluster)
+	var desc *ReplicaDesc
+	err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
+		existing, ok := in.(*ReplicaDesc)
+		if !ok || existing == nil || existing.DeletedAt > 0 {
+			return nil, false, errHAClusterNotFound
+		}
+
+		desc = &ReplicaDesc{}
+		*desc = *existing
+		update(desc)
+		return desc, true, nil
+	})
+	h.kvCASCalls.WithLabelValues(userID, cluster).Inc()
+	if err != nil {
+		return err
+	}
+
+	h.electedLock.Lock()
+	defer h.electedLock.Unlock()
+	if desc.DeletedAt > 0 {
+		h.deleteCache(userID, cluster)
+	} else {
+		h.updateCache(userID, cluster, desc)
+	}
+	return nil
+}
+
+func isPinned(desc *ReplicaDesc, now time.Time) bool {
+	return desc.PinnedUntil > 0 && now.Before(timestamp.Time(desc.PinnedUntil))
+}
+
+func pinnedUntil(duration time.Duration, now time.Time) int64 {
+	if duration <= 0 {
+		return 0
+	}
+	return timestamp.FromTime(now.Add(duration))
+}
+
 type replicasNotMatchError struct {
 	replica, elected string
 }
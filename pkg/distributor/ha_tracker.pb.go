@@ -34,6 +34,10 @@ type ReplicaDesc struct {
 	// already remove entry from memory. Actual deletion from KV store does *not* trigger
 	// "watch" notification with a key for all KV stores.
 	DeletedAt int64 `protobuf:"varint,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Unix timestamp in milliseconds until which the elected replica is pinned.
+	// While pinned, distributors don't fail over to a different replica, even if
+	// the failover timeout has expired. Zero means not pinned.
+	PinnedUntil int64 `protobuf:"varint,4,opt,name=pinned_until,json=pinnedUntil,proto3" json:"pinned_until,omitempty"`
 }
 
 func (m *ReplicaDesc) Reset()      { *m = ReplicaDesc{} }
@@ -89,6 +93,13 @@ func (m *ReplicaDesc) GetDeletedAt() int64 {
 	return 0
 }
 
+func (m *ReplicaDesc) GetPinnedUntil() int64 {
+	if m != nil {
+		return m.PinnedUntil
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ReplicaDesc)(nil), "distributor.ReplicaDesc")
 }
@@ -96,21 +107,23 @@ func init() {
 func init() { proto.RegisterFile("ha_tracker.proto", fileDescriptor_86f0e7bcf71d860b) }
 
 var fileDescriptor_86f0e7bcf71d860b = []byte{
-	// 224 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xc8, 0x48, 0x8c, 0x2f,
-	0x29, 0x4a, 0x4c, 0xce, 0x4e, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4e, 0xc9,
-	0x2c, 0x2e, 0x29, 0xca, 0x4c, 0x2a, 0x2d, 0xc9, 0x2f, 0x92, 0xd2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28,
-	0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf, 0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x49, 0x2a, 0x4d,
-	0x03, 0xf3, 0xc0, 0x1c, 0x30, 0x0b, 0xa2, 0x57, 0x29, 0x9d, 0x8b, 0x3b, 0x28, 0xb5, 0x20, 0x27,
-	0x33, 0x39, 0xd1, 0x25, 0xb5, 0x38, 0x59, 0x48, 0x82, 0x8b, 0xbd, 0x08, 0xc2, 0x95, 0x60, 0x54,
-	0x60, 0xd4, 0xe0, 0x0c, 0x82, 0x71, 0x85, 0xe4, 0xb9, 0xb8, 0x8b, 0x52, 0x93, 0x53, 0x33, 0xcb,
-	0x52, 0x53, 0xe2, 0x13, 0x4b, 0x24, 0x98, 0x14, 0x18, 0x35, 0x98, 0x83, 0xb8, 0x60, 0x42, 0x8e,
-	0x25, 0x42, 0xb2, 0x5c, 0x5c, 0x29, 0xa9, 0x39, 0xa9, 0x25, 0x10, 0x79, 0x66, 0xb0, 0x3c, 0x27,
-	0x54, 0xc4, 0xb1, 0xc4, 0xc9, 0xe4, 0xc2, 0x43, 0x39, 0x86, 0x1b, 0x0f, 0xe5, 0x18, 0x3e, 0x3c,
-	0x94, 0x63, 0x6c, 0x78, 0x24, 0xc7, 0xb8, 0xe2, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72, 0x8c, 0x17,
-	0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0xf8, 0xe2, 0x91, 0x1c, 0xc3, 0x87, 0x47, 0x72, 0x8c,
-	0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x12, 0x1b, 0xd8,
-	0x95, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb3, 0xd1, 0xdd, 0x8d, 0xf5, 0x00, 0x00, 0x00,
+	// 242 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x34, 0x8f, 0x31, 0x4e, 0xc3, 0x40,
+	0x10, 0x45, 0x77, 0x08, 0x02, 0x65, 0x4d, 0x81, 0xb6, 0xb2, 0x90, 0x18, 0x02, 0x55, 0x1a, 0x92,
+	0x02, 0x2e, 0x10, 0xc4, 0x09, 0x2c, 0x51, 0x5b, 0xf6, 0x7a, 0x70, 0x56, 0x18, 0xaf, 0xb5, 0x19,
+	0x53, 0x53, 0x53, 0x71, 0x0c, 0x8e, 0x42, 0xe9, 0x32, 0x25, 0x5e, 0x37, 0x94, 0x39, 0x02, 0x62,
+	0x9d, 0x74, 0xf3, 0xde, 0xff, 0x53, 0x7c, 0x79, 0xbe, 0xce, 0x52, 0x76, 0x99, 0x7e, 0x21, 0xb7,
+	0x68, 0x9c, 0x65, 0xab, 0xa2, 0xc2, 0x6c, 0xd8, 0x99, 0xbc, 0x65, 0xeb, 0x2e, 0x6e, 0x4b, 0xc3,
+	0xeb, 0x36, 0x5f, 0x68, 0xfb, 0xba, 0x2c, 0x6d, 0x69, 0x97, 0xa1, 0x93, 0xb7, 0xcf, 0x81, 0x02,
+	0x84, 0x6b, 0xfc, 0xbd, 0xf9, 0x00, 0x19, 0x25, 0xd4, 0x54, 0x46, 0x67, 0x8f, 0xb4, 0xd1, 0x2a,
+	0x96, 0xa7, 0x6e, 0xc4, 0x18, 0x66, 0x30, 0x9f, 0x26, 0x07, 0x54, 0x57, 0x32, 0x72, 0xa4, 0xc9,
+	0xbc, 0x51, 0x91, 0x66, 0x1c, 0x1f, 0xcd, 0x60, 0x3e, 0x49, 0xe4, 0x41, 0xad, 0x58, 0x5d, 0x4a,
+	0x59, 0x50, 0x45, 0x3c, 0xe6, 0x93, 0x90, 0x4f, 0xf7, 0x66, 0xc5, 0xea, 0x5a, 0x9e, 0x35, 0xa6,
+	0xae, 0xa9, 0x48, 0xdb, 0x9a, 0x4d, 0x15, 0x1f, 0x87, 0x42, 0x34, 0xba, 0xa7, 0x7f, 0xf5, 0x70,
+	0xdf, 0xf5, 0x28, 0xb6, 0x3d, 0x8a, 0x5d, 0x8f, 0xf0, 0xee, 0x11, 0xbe, 0x3c, 0xc2, 0xb7, 0x47,
+	0xe8, 0x3c, 0xc2, 0x8f, 0x47, 0xf8, 0xf5, 0x28, 0x76, 0x1e, 0xe1, 0x73, 0x40, 0xd1, 0x0d, 0x28,
+	0xb6, 0x03, 0x8a, 0xfc, 0x24, 0x2c, 0xb9, 0xfb, 0x1b, 0x00, 0x1c, 0x3a, 0x1f, 0x97, 0x19, 0x01,
+	0x00, 0x00,
 }
 
 func (this *ReplicaDesc) Equal(that interface{}) bool {
@@ -141,17 +154,21 @@ func (this *ReplicaDesc) Equal(that interface{}) bool {
 	if this.DeletedAt != that1.DeletedAt {
 		return false
 	}
+	if this.PinnedUntil != that1.PinnedUntil {
+		return false
+	}
 	return true
 }
 func (this *ReplicaDesc) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
+	s := make([]string, 0, 8)
 	s = append(s, "&distributor.ReplicaDesc{")
 	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
 	s = append(s, "ReceivedAt: "+fmt.Sprintf("%#v", this.ReceivedAt)+",\n")
 	s = append(s, "DeletedAt: "+fmt.Sprintf("%#v", this.DeletedAt)+",\n")
+	s = append(s, "PinnedUntil: "+fmt.Sprintf("%#v", this.PinnedUntil)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -183,6 +200,11 @@ func (m *ReplicaDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.PinnedUntil != 0 {
+		i = encodeVarintHaTracker(dAtA, i, uint64(m.PinnedUntil))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.DeletedAt != 0 {
 		i = encodeVarintHaTracker(dAtA, i, uint64(m.DeletedAt))
 		i--
@@ -230,6 +252,9 @@ func (m *ReplicaDesc) Size() (n int) {
 	if m.DeletedAt != 0 {
 		n += 1 + sovHaTracker(uint64(m.DeletedAt))
 	}
+	if m.PinnedUntil != 0 {
+		n += 1 + sovHaTracker(uint64(m.PinnedUntil))
+	}
 	return n
 }
 
@@ -247,6 +272,7 @@ func (this *ReplicaDesc) String() string {
 		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
 		`ReceivedAt:` + fmt.Sprintf("%v", this.ReceivedAt) + `,`,
 		`DeletedAt:` + fmt.Sprintf("%v", this.DeletedAt) + `,`,
+		`PinnedUntil:` + fmt.Sprintf("%v", this.PinnedUntil) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -358,6 +384,25 @@ func (m *ReplicaDesc) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinnedUntil", wireType)
+			}
+			m.PinnedUntil = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PinnedUntil |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHaTracker(dAtA[iNdEx:])
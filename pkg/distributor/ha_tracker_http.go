@@ -7,11 +7,15 @@ package distributor
 
 import (
 	_ "embed" // Used to embed html template
+	"errors"
+	"fmt"
 	"html/template"
 	"net/http"
 	"sort"
 	"time"
 
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/timestamp"
 
 	"github.com/grafana/mimir/pkg/util"
@@ -33,6 +37,7 @@ type haTrackerReplica struct {
 	ElectedAt    time.Time     `json:"electedAt"`
 	UpdateTime   time.Duration `json:"updateDuration"`
 	FailoverTime time.Duration `json:"failoverDuration"`
+	PinnedUntil  *time.Time    `json:"pinnedUntil,omitempty"`
 }
 
 func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -42,14 +47,19 @@ func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	for userID, clusters := range h.clusters {
 		for cluster, entry := range clusters {
 			desc := &entry.elected
-			electedReplicas = append(electedReplicas, haTrackerReplica{
+			replica := haTrackerReplica{
 				UserID:       userID,
 				Cluster:      cluster,
 				Replica:      desc.Replica,
 				ElectedAt:    timestamp.Time(desc.ReceivedAt),
 				UpdateTime:   time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.UpdateTimeout)),
 				FailoverTime: time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.FailoverTimeout)),
-			})
+			}
+			if isPinned(desc, time.Now()) {
+				pinnedUntil := timestamp.Time(desc.PinnedUntil)
+				replica.PinnedUntil = &pinnedUntil
+			}
+			electedReplicas = append(electedReplicas, replica)
 		}
 	}
 	h.electedLock.RUnlock()
@@ -69,3 +79,101 @@ func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		Now:     time.Now(),
 	}, haTrackerStatusPageTemplate, req)
 }
+
+// ElectReplicaHandler forces the elected replica of an HA cluster of the tenant, without waiting
+// for the failover timeout. The replica is optionally pinned for the given duration.
+func (h *haTracker) ElectReplicaHandler(w http.ResponseWriter, req *http.Request) {
+	userID, cluster, ok := h.parseClusterRequest(w, req)
+	if !ok {
+		return
+	}
+
+	replica := req.FormValue("replica")
+	if replica == "" {
+		http.Error(w, "missing replica parameter", http.StatusBadRequest)
+		return
+	}
+
+	pinDuration, err := parseDurationParam(req, "pin_duration")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeHATrackerUpdateResponse(w, h.electReplica(req.Context(), userID, cluster, replica, pinDuration, time.Now()))
+}
+
+// PinReplicaHandler pins the elected replica of an HA cluster of the tenant for the given duration.
+// A zero duration removes the pin.
+func (h *haTracker) PinReplicaHandler(w http.ResponseWriter, req *http.Request) {
+	userID, cluster, ok := h.parseClusterRequest(w, req)
+	if !ok {
+		return
+	}
+
+	if req.FormValue("duration") == "" {
+		http.Error(w, "missing duration parameter", http.StatusBadRequest)
+		return
+	}
+
+	duration, err := parseDurationParam(req, "duration")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeHATrackerUpdateResponse(w, h.pinReplica(req.Context(), userID, cluster, duration, time.Now()))
+}
+
+// ClearClusterHandler removes the elected replica of an HA cluster of the tenant. A new replica
+// is elected from the next received sample.
+func (h *haTracker) ClearClusterHandler(w http.ResponseWriter, req *http.Request) {
+	userID, cluster, ok := h.parseClusterRequest(w, req)
+	if !ok {
+		return
+	}
+
+	writeHATrackerUpdateResponse(w, h.clearCluster(req.Context(), userID, cluster, time.Now()))
+}
+
+func (h *haTracker) parseClusterRequest(w http.ResponseWriter, req *http.Request) (userID, cluster string, ok bool) {
+	userID, err := tenant.TenantID(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	cluster = req.FormValue("cluster")
+	if cluster == "" {
+		http.Error(w, "missing cluster parameter", http.StatusBadRequest)
+		return "", "", false
+	}
+
+	return userID, cluster, true
+}
+
+func parseDurationParam(req *http.Request, name string) (time.Duration, error) {
+	value := req.FormValue(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := model.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+	return time.Duration(d), nil
+}
+
+func writeHATrackerUpdateResponse(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, errHATrackerDisabled):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errHAClusterNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
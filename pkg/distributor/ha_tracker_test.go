@@ -8,6 +8,9 @@ package distributor
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -819,6 +822,204 @@ func TestCheckReplicaCleanup(t *testing.T) {
 	))
 }
 
+func TestHATracker_ElectReplica(t *testing.T) {
+	const userID, cluster = "user", "cluster"
+
+	c := newHATrackerWithInMemoryConsul(t)
+	now := time.Now()
+
+	// Electing a replica for an unknown cluster should fail.
+	require.ErrorIs(t, c.electReplica(context.Background(), userID, cluster, "replica2", 0, now), errHAClusterNotFound)
+
+	require.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica1", now))
+	checkReplicaTimestamp(t, time.Second, c, userID, cluster, "replica1", now)
+
+	// Force the election of replica2 well before the failover timeout.
+	now = now.Add(time.Second)
+	require.NoError(t, c.electReplica(context.Background(), userID, cluster, "replica2", 0, now))
+	checkReplicaTimestamp(t, time.Second, c, userID, cluster, "replica2", now)
+
+	assert.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica2", now))
+	assert.ErrorIs(t, c.checkReplica(context.Background(), userID, cluster, "replica1", now), replicasNotMatchError{})
+
+	// Electing a replica with a pin duration should pin it.
+	require.NoError(t, c.electReplica(context.Background(), userID, cluster, "replica1", time.Hour, now))
+	desc := getReplicaDescFromKV(t, c, userID, cluster)
+	assert.Equal(t, "replica1", desc.Replica)
+	assert.Equal(t, timestamp.FromTime(now.Add(time.Hour)), desc.PinnedUntil)
+}
+
+func TestHATracker_PinReplica(t *testing.T) {
+	const userID, cluster = "user", "cluster"
+
+	c := newHATrackerWithInMemoryConsul(t)
+	now := time.Now()
+
+	require.ErrorIs(t, c.pinReplica(context.Background(), userID, cluster, time.Hour, now), errHAClusterNotFound)
+
+	require.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica1", now))
+	require.NoError(t, c.pinReplica(context.Background(), userID, cluster, time.Hour, now))
+
+	// The elected replica stops sending samples, while another replica does.
+	now = now.Add(5 * time.Second)
+	assert.Error(t, c.checkReplica(context.Background(), userID, cluster, "replica2", now))
+
+	// The failover timeout has expired, but the pin prevents the failover.
+	c.updateKVStoreAll(context.Background(), now)
+	desc := getReplicaDescFromKV(t, c, userID, cluster)
+	assert.Equal(t, "replica1", desc.Replica)
+
+	// Refreshing the elected replica keeps the pin.
+	require.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica1", now))
+	c.updateKVStoreAll(context.Background(), now)
+	desc = getReplicaDescFromKV(t, c, userID, cluster)
+	assert.Equal(t, timestamp.FromTime(now), desc.ReceivedAt)
+	assert.Equal(t, timestamp.FromTime(now.Add(-5*time.Second).Add(time.Hour)), desc.PinnedUntil)
+
+	// Once unpinned, the failover happens as usual.
+	require.NoError(t, c.pinReplica(context.Background(), userID, cluster, 0, now))
+	now = now.Add(5 * time.Second)
+	assert.Error(t, c.checkReplica(context.Background(), userID, cluster, "replica2", now))
+	c.updateKVStoreAll(context.Background(), now)
+	checkReplicaTimestamp(t, time.Second, c, userID, cluster, "replica2", now)
+}
+
+func TestHATracker_ClearCluster(t *testing.T) {
+	const userID, cluster = "user", "cluster"
+
+	c := newHATrackerWithInMemoryConsul(t)
+	now := time.Now()
+
+	require.ErrorIs(t, c.clearCluster(context.Background(), userID, cluster, now), errHAClusterNotFound)
+
+	require.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica1", now))
+	require.NoError(t, c.clearCluster(context.Background(), userID, cluster, now))
+	checkReplicaDeletionState(t, time.Second, c, userID, cluster, false, true, true)
+
+	// Clearing an already cleared cluster should fail.
+	require.ErrorIs(t, c.clearCluster(context.Background(), userID, cluster, now), errHAClusterNotFound)
+
+	// The next sample elects a new replica right away.
+	require.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica2", now))
+	checkReplicaTimestamp(t, time.Second, c, userID, cluster, "replica2", now)
+}
+
+func TestHATracker_ManagementHandlers(t *testing.T) {
+	const userID, cluster = "user", "cluster"
+
+	disabled, err := newHATracker(HATrackerConfig{EnableHATracker: false}, trackerLimits{}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	c := newHATrackerWithInMemoryConsul(t)
+	require.NoError(t, c.checkReplica(context.Background(), userID, cluster, "replica1", time.Now()))
+
+	tests := map[string]struct {
+		tracker        *haTracker
+		handler        func(h *haTracker) http.HandlerFunc
+		orgID          string
+		params         url.Values
+		expectedStatus int
+	}{
+		"should fail without tenant": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.ElectReplicaHandler },
+			params:         url.Values{"cluster": {cluster}, "replica": {"replica2"}},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"should fail without cluster": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.ClearClusterHandler },
+			orgID:          userID,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"should fail electing without replica": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.ElectReplicaHandler },
+			orgID:          userID,
+			params:         url.Values{"cluster": {cluster}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"should fail electing with invalid pin duration": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.ElectReplicaHandler },
+			orgID:          userID,
+			params:         url.Values{"cluster": {cluster}, "replica": {"replica2"}, "pin_duration": {"x"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"should fail pinning without duration": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.PinReplicaHandler },
+			orgID:          userID,
+			params:         url.Values{"cluster": {cluster}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"should fail if the HA tracker is disabled": {
+			tracker:        disabled,
+			handler:        func(h *haTracker) http.HandlerFunc { return h.ClearClusterHandler },
+			orgID:          userID,
+			params:         url.Values{"cluster": {cluster}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"should fail if the cluster is not tracked for the tenant": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.PinReplicaHandler },
+			orgID:          "another-user",
+			params:         url.Values{"cluster": {cluster}, "duration": {"1h"}},
+			expectedStatus: http.StatusNotFound,
+		},
+		"should elect and pin the replica": {
+			handler:        func(h *haTracker) http.HandlerFunc { return h.ElectReplicaHandler },
+			orgID:          userID,
+			params:         url.Values{"cluster": {cluster}, "replica": {"replica2"}, "pin_duration": {"1h"}},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tracker := testData.tracker
+			if tracker == nil {
+				tracker = c
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/distributor/ha_tracker", strings.NewReader(testData.params.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if testData.orgID != "" {
+				req = req.WithContext(user.InjectOrgID(req.Context(), testData.orgID))
+			}
+
+			rec := httptest.NewRecorder()
+			testData.handler(tracker).ServeHTTP(rec, req)
+			assert.Equal(t, testData.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+
+	desc := getReplicaDescFromKV(t, c, userID, cluster)
+	assert.Equal(t, "replica2", desc.Replica)
+	assert.Greater(t, desc.PinnedUntil, timestamp.FromTime(time.Now()))
+}
+
+func newHATrackerWithInMemoryConsul(t *testing.T) *haTracker {
+	kvStore, closer := consul.NewInMemoryClient(GetReplicaDescCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { assert.NoError(t, closer.Close()) })
+
+	c, err := newHATracker(HATrackerConfig{
+		EnableHATracker:        true,
+		KVStore:                kv.Config{Mock: kv.PrefixClient(kvStore, "prefix")},
+		UpdateTimeout:          time.Second,
+		UpdateTimeoutJitterMax: 0,
+		FailoverTimeout:        2 * time.Second,
+	}, trackerLimits{maxClusters: 100}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(func() {
+		assert.NoError(t, services.StopAndAwaitTerminated(context.Background(), c))
+	})
+
+	return c
+}
+
+func getReplicaDescFromKV(t *testing.T, c *haTracker, user, cluster string) *ReplicaDesc {
+	val, err := c.client.Get(context.Background(), fmt.Sprintf("%s/%s", user, cluster))
+	require.NoError(t, err)
+	require.NotNil(t, val)
+	return val.(*ReplicaDesc)
+}
+
 func checkUserClusters(t *testing.T, duration time.Duration, c *haTracker, user string, expectedClusters int) {
 	t.Helper()
 	test.Poll(t, duration, nil, func() interface{} {
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/services"
+
+	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
+)
+
+// IngestersTenantsLister lists the tenants with series in the ingesters, without running a whole
+// distributor. It's a service running the pool of ingester clients.
+type IngestersTenantsLister struct {
+	services.Service
+
+	ingestersRing ring.ReadRing
+	ingesterPool  *ring_client.Pool
+}
+
+// NewIngestersTenantsLister returns an IngestersTenantsLister querying the ingesters in the input ring.
+func NewIngestersTenantsLister(cfg Config, clientConfig ingester_client.Config, ingestersRing ring.ReadRing, logger log.Logger) *IngestersTenantsLister {
+	factory := cfg.IngesterClientFactory
+	if factory == nil {
+		factory = func(addr string) (ring_client.PoolClient, error) {
+			return ingester_client.MakeIngesterClient(addr, clientConfig)
+		}
+	}
+
+	poolCfg := cfg.PoolConfig
+	poolCfg.RemoteTimeout = cfg.RemoteTimeout
+	pool := NewPool(poolCfg, ingestersRing, factory, logger)
+
+	return &IngestersTenantsLister{
+		Service:       pool,
+		ingestersRing: ingestersRing,
+		ingesterPool:  pool,
+	}
+}
+
+// ListTenants returns the tenants with series in the healthy ingesters.
+func (l *IngestersTenantsLister) ListTenants(ctx context.Context) ([]string, error) {
+	stats, err := allUserStats(ctx, l.ingestersRing, l.ingesterPool)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := make([]string, 0, len(stats))
+	for _, s := range stats {
+		tenants = append(tenants, s.UserID)
+	}
+	return tenants, nil
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+)
+
+func TestIngestersTenantsLister_ListTenants(t *testing.T) {
+	ds, ingesters, _ := prepare(t, prepConfig{
+		numIngesters:    3,
+		happyIngesters:  3,
+		numDistributors: 1,
+	})
+
+	ingesters[0].stats = client.UsersStatsResponse{Stats: []*client.UserIDStatsResponse{
+		{UserId: "user-1", Data: &client.UserStatsResponse{NumSeries: 1}},
+		{UserId: "user-2", Data: &client.UserStatsResponse{NumSeries: 1}},
+	}}
+	ingesters[1].stats = client.UsersStatsResponse{Stats: []*client.UserIDStatsResponse{
+		{UserId: "user-2", Data: &client.UserStatsResponse{NumSeries: 1}},
+		{UserId: "user-3", Data: &client.UserStatsResponse{NumSeries: 1}},
+	}}
+
+	var clientConfig client.Config
+	flagext.DefaultValues(&clientConfig)
+
+	lister := NewIngestersTenantsLister(ds[0].cfg, clientConfig, ds[0].ingestersRing, log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), lister))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), lister))
+	})
+
+	tenants, err := lister.ListTenants(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user-1", "user-2", "user-3"}, tenants)
+}
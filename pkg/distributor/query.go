@@ -10,6 +10,7 @@ package distributor
 import (
 	"context"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -19,14 +20,17 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/weaveworks/common/instrument"
 	"golang.org/x/exp/slices"
 
 	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util/limiter"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 var (
@@ -196,6 +200,7 @@ type ingesterQueryResult struct {
 // queryIngesterStream queries the ingesters using the gRPC streaming API.
 func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ring.ReplicationSet, req *ingester_client.QueryRequest, queryMetrics *stats.QueryMetrics) (ingester_client.CombinedQueryStreamResponse, error) {
 	queryLimiter := limiter.QueryLimiterFromContextWithFallback(ctx)
+	memoryTracker := limiter.MemoryConsumptionTrackerFromContextWithFallback(ctx)
 	reqStats := stats.FromContext(ctx)
 
 	queryIngester := func(ctx context.Context, ing *ring.InstanceDesc, cancelContext context.CancelFunc) (ingesterQueryResult, error) {
@@ -222,6 +227,22 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 		log.Span.SetTag("ingester_address", ing.Addr)
 		log.Span.SetTag("ingester_zone", ing.Zone)
 
+		// Track the per-ingester stats, if requested. Chunks of streaming series are fetched later,
+		// so we can only report their bytes for non-streaming series.
+		var fetchedSeries, fetchedChunkBytes int
+		if stats.IsStoreStatsEnabled(ctx) {
+			start := time.Now()
+			defer func() {
+				reqStats.AddStoreStats(stats.StoreStats{
+					Component:          stats.StoreComponentIngester,
+					Address:            ing.Addr,
+					FetchedSeriesCount: uint64(fetchedSeries),
+					FetchedChunkBytes:  uint64(fetchedChunkBytes),
+					Latency:            time.Since(start),
+				})
+			}()
+		}
+
 		client, err := d.ingesterPool.GetClientFor(ing.Addr)
 		if err != nil {
 			return ingesterQueryResult{}, err
@@ -256,6 +277,7 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 					}
 				}
 
+				fetchedSeries += len(resp.Timeseries)
 				result.timeseriesBatches = append(result.timeseriesBatches, resp.Timeseries)
 			} else if len(resp.Chunkseries) > 0 {
 				// Enforce the max chunks limits.
@@ -269,10 +291,18 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 					}
 				}
 
-				if chunkBytesLimitErr := queryLimiter.AddChunkBytes(ingester_client.ChunksSize(resp.Chunkseries)); chunkBytesLimitErr != nil {
+				chunksSize := ingester_client.ChunksSize(resp.Chunkseries)
+				if chunkBytesLimitErr := queryLimiter.AddChunkBytes(chunksSize); chunkBytesLimitErr != nil {
 					return ingesterQueryResult{}, chunkBytesLimitErr
 				}
 
+				// The chunks are held in memory by the series iterators until the query completes.
+				if memoryLimitErr := memoryTracker.IncreaseMemoryConsumption(uint64(chunksSize)); memoryLimitErr != nil {
+					return ingesterQueryResult{}, memoryLimitErr
+				}
+
+				fetchedSeries += len(resp.Chunkseries)
+				fetchedChunkBytes += chunksSize
 				result.chunkseriesBatches = append(result.chunkseriesBatches, resp.Chunkseries)
 			} else if len(resp.StreamingSeries) > 0 {
 				labelsBatch := make([]labels.Labels, 0, len(resp.StreamingSeries))
@@ -291,6 +321,7 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 					labelsBatch = append(labelsBatch, mimirpb.FromLabelAdaptersToLabels(s.Labels))
 				}
 
+				fetchedSeries += len(resp.StreamingSeries)
 				streamingSeriesBatches = append(streamingSeriesBatches, labelsBatch)
 			}
 
@@ -318,10 +349,22 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 		}
 	}
 
-	results, err := ring.DoUntilQuorumWithoutSuccessfulContextCancellation(ctx, replicationSet, d.queryQuorumConfig, queryIngester, cleanup)
+	var (
+		results  []ingesterQueryResult
+		warnings storage.Warnings
+		err      error
+	)
+	if querierapi.IsPartialResponseEnabled(ctx) {
+		results, warnings, err = queryIngestersAllowingPartialResponse(ctx, replicationSet, queryIngester, cleanup)
+	} else {
+		results, err = ring.DoUntilQuorumWithoutSuccessfulContextCancellation(ctx, replicationSet, d.queryQuorumConfig, queryIngester, cleanup)
+	}
 	if err != nil {
 		return ingester_client.CombinedQueryStreamResponse{}, err
 	}
+	if len(warnings) > 0 {
+		querierapi.MarkPartialResponse(ctx)
+	}
 
 	// We keep track of the number of chunks that were able to be deduplicated entirely
 	// via the AccumulateChunks function (fast) instead of needing to merge samples one
@@ -381,6 +424,7 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 		Chunkseries:     make([]ingester_client.TimeSeriesChunk, 0, len(hashToChunkseries)),
 		Timeseries:      make([]mimirpb.TimeSeries, 0, len(hashToTimeSeries)),
 		StreamingSeries: mergeSeriesChunkStreams(results, d.estimatedIngestersPerSeries(replicationSet)),
+		Warnings:        warnings,
 	}
 	for _, series := range hashToChunkseries {
 		resp.Chunkseries = append(resp.Chunkseries, series)
@@ -398,6 +442,138 @@ func (d *Distributor) queryIngesterStream(ctx context.Context, replicationSet ri
 	return resp, nil
 }
 
+// queryIngestersAllowingPartialResponse queries all ingesters in the replication set. Like
+// ring.DoUntilQuorum, it returns as soon as the results are complete, which is when all ingesters
+// in enough zones (or enough ingesters, if zone-awareness is disabled) have been successfully queried,
+// and cancels the requests to the other ingesters. Unlike ring.DoUntilQuorum, it doesn't fail when
+// the ingesters which can't be queried exceed the tolerated failures. In that case, it waits for
+// all ingesters and returns the results of the successful ones along with a warning listing the
+// ingester zones (or the ingesters, if zone-awareness is disabled) which couldn't be queried. The
+// query fails anyway if all ingesters fail, if a query limit is hit or if the context is canceled.
+func queryIngestersAllowingPartialResponse[T any](ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, *ring.InstanceDesc, context.CancelFunc) (T, error), cleanup func(T)) ([]T, storage.Warnings, error) {
+	type instanceResult struct {
+		instance *ring.InstanceDesc
+		result   T
+		err      error
+	}
+
+	var (
+		resultsChan      = make(chan instanceResult, len(replicationSet.Instances))
+		resultsRemaining = len(replicationSet.Instances)
+		cancels          = make(map[*ring.InstanceDesc]context.CancelFunc, len(replicationSet.Instances))
+		zoneAwareness    = replicationSet.MaxUnavailableZones > 0
+		instancesPerZone = map[string]int{}
+	)
+
+	for i := range replicationSet.Instances {
+		instance := &replicationSet.Instances[i]
+		instanceCtx, cancel := context.WithCancel(ctx)
+		cancels[instance] = cancel
+		instancesPerZone[instance.Zone]++
+
+		go func() {
+			result, err := f(instanceCtx, instance, cancel)
+			resultsChan <- instanceResult{instance: instance, result: result, err: err}
+		}()
+	}
+
+	// The contexts of the successfully queried ingesters are canceled by f once their results are
+	// consumed, so here we only cancel the requests whose result is not returned, and clean up their
+	// results once received.
+	defer func() {
+		for instance, cancel := range cancels {
+			cancel()
+			delete(cancels, instance)
+		}
+
+		go func(remaining int) {
+			for ; remaining > 0; remaining-- {
+				if res := <-resultsChan; res.err == nil {
+					cleanup(res.result)
+				}
+			}
+		}(resultsRemaining)
+	}()
+
+	var (
+		results          []T
+		failedZones      []string
+		failedAddrs      []string
+		succeededPerZone = map[string]int{}
+		succeededZones   = 0
+		firstErr         error
+	)
+
+	cleanupResults := func() {
+		for _, result := range results {
+			cleanup(result)
+		}
+	}
+
+	for resultsRemaining > 0 {
+		var res instanceResult
+		select {
+		case <-ctx.Done():
+			cleanupResults()
+			return nil, nil, ctx.Err()
+		case res = <-resultsChan:
+			resultsRemaining--
+		}
+
+		if res.err != nil {
+			cancels[res.instance]()
+			delete(cancels, res.instance)
+
+			var limitErr validation.LimitError
+			if errors.As(res.err, &limitErr) {
+				cleanupResults()
+				return nil, nil, res.err
+			}
+
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			failedAddrs = append(failedAddrs, res.instance.Addr)
+			if !slices.Contains(failedZones, res.instance.Zone) {
+				failedZones = append(failedZones, res.instance.Zone)
+			}
+			continue
+		}
+
+		// The context of a successfully queried ingester is owned by f from now on.
+		delete(cancels, res.instance)
+		results = append(results, res.result)
+
+		// Return as soon as the results are complete, without waiting for the other ingesters.
+		if zoneAwareness {
+			succeededPerZone[res.instance.Zone]++
+			if succeededPerZone[res.instance.Zone] == instancesPerZone[res.instance.Zone] {
+				succeededZones++
+			}
+			if succeededZones >= len(instancesPerZone)-replicationSet.MaxUnavailableZones {
+				return results, nil, nil
+			}
+		} else if len(results) >= len(replicationSet.Instances)-replicationSet.MaxErrors {
+			return results, nil, nil
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, nil, firstErr
+	}
+
+	var warning error
+	if zoneAwareness {
+		slices.Sort(failedZones)
+		warning = errors.Errorf("partial response: failed to query ingesters in zones %s: %s", strings.Join(failedZones, ", "), firstErr.Error())
+	} else {
+		slices.Sort(failedAddrs)
+		warning = errors.Errorf("partial response: failed to query ingesters %s: %s", strings.Join(failedAddrs, ", "), firstErr.Error())
+	}
+
+	return results, storage.Warnings{warning}, nil
+}
+
 // estimatedIngestersPerSeries estimates the number of ingesters that will have chunks for each streaming series.
 func (d *Distributor) estimatedIngestersPerSeries(replicationSet ring.ReplicationSet) int {
 	// Under normal circumstances, a quorum of ingesters will have chunks for each series, so here
@@ -16,6 +16,8 @@ import (
 	"time"
 
 	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/test"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
@@ -26,6 +28,7 @@ import (
 
 	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util/limiter"
 	"github.com/grafana/mimir/pkg/util/validation"
@@ -149,6 +152,155 @@ func TestDistributor_QueryStream_ShouldReturnErrorIfMaxSeriesPerQueryLimitIsReac
 	assert.ErrorContains(t, err, "the query exceeded the maximum number of series")
 }
 
+func TestDistributor_QueryStream_PartialResponse(t *testing.T) {
+	const numSeries = 10
+
+	tests := map[string]struct {
+		partialResponseEnabled bool
+		ingesterZones          []string
+		unhappyIngesters       []int
+		expectedSeries         int
+		expectedWarnings       []string
+		expectedErr            string
+	}{
+		"partial response disabled, failures within the quorum": {
+			unhappyIngesters: []int{1},
+			expectedSeries:   numSeries,
+		},
+		"partial response disabled, failures exceeding the quorum": {
+			unhappyIngesters: []int{1, 2},
+			expectedErr:      errFail.Error(),
+		},
+		"partial response enabled, failures within the quorum": {
+			partialResponseEnabled: true,
+			unhappyIngesters:       []int{1},
+			expectedSeries:         numSeries,
+		},
+		"partial response enabled, failures exceeding the quorum": {
+			partialResponseEnabled: true,
+			unhappyIngesters:       []int{1, 2},
+			expectedSeries:         numSeries,
+			expectedWarnings:       []string{"partial response: failed to query ingesters 1, 2: " + errFail.Error()},
+		},
+		"partial response enabled, zone-aware, failures exceeding the quorum": {
+			partialResponseEnabled: true,
+			ingesterZones:          []string{"zone-a", "zone-b", "zone-c"},
+			unhappyIngesters:       []int{1, 2},
+			expectedSeries:         numSeries,
+			expectedWarnings:       []string{"partial response: failed to query ingesters in zones zone-b, zone-c: " + errFail.Error()},
+		},
+		"partial response enabled, all ingesters failing": {
+			partialResponseEnabled: true,
+			unhappyIngesters:       []int{0, 1, 2},
+			expectedErr:            errFail.Error(),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "user")
+			ctx = querierapi.ContextWithPartialResponse(ctx, testData.partialResponseEnabled)
+
+			ds, ingesters, reg := prepare(t, prepConfig{
+				numIngesters:    3,
+				happyIngesters:  3,
+				numDistributors: 1,
+				ingesterZones:   testData.ingesterZones,
+			})
+
+			writeRes, err := ds[0].Push(ctx, makeWriteRequest(0, numSeries, 0, false, false))
+			require.Equal(t, &mimirpb.WriteResponse{}, writeRes)
+			require.NoError(t, err)
+
+			// The push returns once the quorum is reached, so wait until all the ingesters received the series.
+			for i := range ingesters {
+				test.Poll(t, time.Second, numSeries, func() interface{} {
+					return len(ingesters[i].series())
+				})
+			}
+
+			for _, i := range testData.unhappyIngesters {
+				ingesters[i].Lock()
+				ingesters[i].happy = false
+				ingesters[i].Unlock()
+			}
+
+			allSeriesMatchers := []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchRegexp, model.MetricNameLabel, ".+"),
+			}
+
+			queryRes, err := ds[0].QueryStream(ctx, stats.NewQueryMetrics(reg[0]), math.MinInt32, math.MaxInt32, allSeriesMatchers...)
+			if testData.expectedErr != "" {
+				require.ErrorContains(t, err, testData.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, queryRes.Chunkseries, testData.expectedSeries)
+
+			var actualWarnings []string
+			for _, w := range queryRes.Warnings {
+				actualWarnings = append(actualWarnings, w.Error())
+			}
+			assert.Equal(t, testData.expectedWarnings, actualWarnings)
+			assert.Equal(t, len(testData.expectedWarnings) > 0, querierapi.IsPartialResponse(ctx))
+		})
+	}
+}
+
+func TestQueryIngestersAllowingPartialResponse(t *testing.T) {
+	replicationSet := ring.ReplicationSet{
+		Instances: []ring.InstanceDesc{{Addr: "1"}, {Addr: "2"}, {Addr: "3"}},
+		MaxErrors: 1,
+	}
+
+	t.Run("should return as soon as the results are complete and cancel the other requests", func(t *testing.T) {
+		slowCanceled := make(chan struct{})
+		cleanedUp := make(chan string, 1)
+
+		f := func(ctx context.Context, instance *ring.InstanceDesc, _ context.CancelFunc) (string, error) {
+			if instance.Addr == "3" {
+				<-ctx.Done()
+				close(slowCanceled)
+				return instance.Addr, nil
+			}
+			return instance.Addr, nil
+		}
+		cleanup := func(result string) { cleanedUp <- result }
+
+		results, warnings, err := queryIngestersAllowingPartialResponse(context.Background(), replicationSet, f, cleanup)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.ElementsMatch(t, []string{"1", "2"}, results)
+
+		select {
+		case <-slowCanceled:
+		case <-time.After(time.Second):
+			require.FailNow(t, "the request to the slow ingester has not been canceled")
+		}
+		select {
+		case result := <-cleanedUp:
+			assert.Equal(t, "3", result)
+		case <-time.After(time.Second):
+			require.FailNow(t, "the result of the slow ingester has not been cleaned up")
+		}
+	})
+
+	t.Run("should fail on a wrapped limit error", func(t *testing.T) {
+		limitErr := validation.LimitError("limit hit")
+
+		f := func(_ context.Context, instance *ring.InstanceDesc, _ context.CancelFunc) (string, error) {
+			if instance.Addr == "1" {
+				return "", fmt.Errorf("wrapped: %w", limitErr)
+			}
+			return "", errFail
+		}
+
+		_, _, err := queryIngestersAllowingPartialResponse(context.Background(), replicationSet, f, func(string) {})
+		require.ErrorIs(t, err, limitErr)
+	})
+}
+
 func TestDistributor_QueryStream_ShouldReturnErrorIfMaxChunkBytesPerQueryLimitIsReached(t *testing.T) {
 	const seriesToAdd = 10
 
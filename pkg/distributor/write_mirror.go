@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/services"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/push"
+)
+
+var (
+	errInvalidWriteMirrorURL           = errors.New("the write mirror URL must be an absolute http or https URL")
+	errInvalidWriteMirrorConcurrency   = errors.New("the write mirror concurrency must be greater than 0")
+	errInvalidWriteMirrorQueueCapacity = errors.New("the write mirror queue capacity must be greater than 0")
+)
+
+type writeMirrorLimits interface {
+	WriteMirrorEnabled(userID string) bool
+	WriteMirrorSampleRatio(userID string) float64
+}
+
+// WriteMirrorConfig configures the mirroring of accepted write requests to a secondary remote-write endpoint.
+type WriteMirrorConfig struct {
+	URL           string        `yaml:"url" category:"experimental"`
+	Timeout       time.Duration `yaml:"timeout" category:"experimental"`
+	Concurrency   int           `yaml:"concurrency" category:"experimental"`
+	QueueCapacity int           `yaml:"queue_capacity" category:"experimental"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet.
+func (cfg *WriteMirrorConfig) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&cfg.URL, "distributor.write-mirror.url", "", "Remote-write URL to mirror the accepted write requests to, for tenants with -distributor.write-mirror-enabled. Requests are mirrored asynchronously on a best-effort basis and are dropped when the mirror can't keep up. Empty to disable.")
+	f.DurationVar(&cfg.Timeout, "distributor.write-mirror.timeout", 10*time.Second, "Timeout for each mirrored write request.")
+	f.IntVar(&cfg.Concurrency, "distributor.write-mirror.concurrency", 4, "Number of workers sending mirrored write requests. Each tenant is always mirrored by the same worker.")
+	f.IntVar(&cfg.QueueCapacity, "distributor.write-mirror.queue-capacity", 1000, "Maximum number of write requests queued for each worker. Write requests are dropped from the mirror when the queue is full.")
+}
+
+// Validate config and returns error on failure
+func (cfg *WriteMirrorConfig) Validate() error {
+	if !cfg.enabled() {
+		return nil
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return errInvalidWriteMirrorURL
+	}
+	if cfg.Concurrency <= 0 {
+		return errInvalidWriteMirrorConcurrency
+	}
+	if cfg.QueueCapacity <= 0 {
+		return errInvalidWriteMirrorQueueCapacity
+	}
+	return nil
+}
+
+func (cfg *WriteMirrorConfig) enabled() bool {
+	return cfg.URL != ""
+}
+
+type mirroredWriteRequest struct {
+	userID string
+	req    *mimirpb.WriteRequest
+	// release must be called once the worker is done reading req.
+	release    func()
+	enqueuedAt time.Time
+}
+
+// writeMirror asynchronously sends a copy of write requests to a secondary remote-write endpoint.
+// Each tenant is mapped to one bounded queue, so that its requests are sent in order, and write
+// requests are dropped instead of blocking the caller when the queue is full.
+type writeMirror struct {
+	services.Service
+
+	cfg    WriteMirrorConfig
+	limits writeMirrorLimits
+	logger log.Logger
+	client *http.Client
+	queues []chan mirroredWriteRequest
+
+	mirroredRequests *prometheus.CounterVec
+	droppedRequests  *prometheus.CounterVec
+	failedRequests   *prometheus.CounterVec
+	lag              prometheus.Histogram
+}
+
+func newWriteMirror(cfg WriteMirrorConfig, limits writeMirrorLimits, reg prometheus.Registerer, logger log.Logger) *writeMirror {
+	m := &writeMirror{
+		cfg:    cfg,
+		limits: limits,
+		logger: logger,
+		client: &http.Client{Timeout: cfg.Timeout},
+		queues: make([]chan mirroredWriteRequest, cfg.Concurrency),
+
+		mirroredRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_write_mirror_requests_total",
+			Help: "The total number of write requests queued to be mirrored.",
+		}, []string{"user"}),
+		droppedRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_write_mirror_requests_dropped_total",
+			Help: "The total number of write requests not mirrored because the queue was full.",
+		}, []string{"user"}),
+		failedRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_write_mirror_requests_failed_total",
+			Help: "The total number of mirrored write requests that failed to be sent.",
+		}, []string{"user"}),
+		lag: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "cortex_distributor_write_mirror_lag_seconds",
+			Help:    "Time between queueing a mirrored write request and completing its sending.",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}),
+	}
+
+	for i := range m.queues {
+		m.queues[i] = make(chan mirroredWriteRequest, cfg.QueueCapacity)
+	}
+
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "cortex_distributor_write_mirror_queue_length",
+		Help: "Current number of write requests queued to be mirrored.",
+	}, func() float64 {
+		length := 0
+		for _, q := range m.queues {
+			length += len(q)
+		}
+		return float64(length)
+	})
+
+	m.Service = services.NewBasicService(nil, m.running, nil)
+	return m
+}
+
+func (m *writeMirror) running(ctx context.Context) error {
+	wg := sync.WaitGroup{}
+	wg.Add(len(m.queues))
+
+	for _, q := range m.queues {
+		go func(q chan mirroredWriteRequest) {
+			defer wg.Done()
+			m.runWorker(ctx, q)
+		}(q)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (m *writeMirror) runWorker(ctx context.Context, queue chan mirroredWriteRequest) {
+	for {
+		select {
+		case <-ctx.Done():
+			// Release the requests left in the queue, so that their buffers are returned to the pools.
+			for {
+				select {
+				case req := <-queue:
+					req.release()
+				default:
+					return
+				}
+			}
+		case req := <-queue:
+			if err := m.send(ctx, req); err != nil {
+				m.failedRequests.WithLabelValues(req.userID).Inc()
+				level.Debug(m.logger).Log("msg", "failed to mirror write request", "user", req.userID, "err", err)
+			}
+			m.lag.Observe(time.Since(req.enqueuedAt).Seconds())
+		}
+	}
+}
+
+// mirror queues the write request for the tenant, if mirroring is enabled and the request is sampled.
+// It never blocks and doesn't serialize the request: the request is dropped if the tenant's queue is full,
+// and it's otherwise retained until the worker has serialized it, so that its buffers are not reused in
+// the meantime. The caller must not modify the request afterwards.
+func (m *writeMirror) mirror(userID string, pushReq *push.Request) {
+	if !m.limits.WriteMirrorEnabled(userID) {
+		return
+	}
+	if ratio := m.limits.WriteMirrorSampleRatio(userID); ratio < 1 && rand.Float64() >= ratio {
+		return
+	}
+
+	req, err := pushReq.WriteRequest()
+	if err != nil {
+		return
+	}
+
+	release := pushReq.Retain()
+	select {
+	case m.queues[m.queueIndex(userID)] <- mirroredWriteRequest{userID: userID, req: req, release: release, enqueuedAt: time.Now()}:
+		m.mirroredRequests.WithLabelValues(userID).Inc()
+	default:
+		release()
+		m.droppedRequests.WithLabelValues(userID).Inc()
+	}
+}
+
+func (m *writeMirror) queueIndex(userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(userID))
+	return int(h.Sum32() % uint32(len(m.queues)))
+}
+
+func (m *writeMirror) send(ctx context.Context, req mirroredWriteRequest) error {
+	body, err := req.req.Marshal()
+	req.release()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal write request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.cfg.URL, bytes.NewReader(snappy.Encode(nil, body)))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	httpReq.Header.Set(user.OrgIDHeaderName, req.userID)
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("server returned HTTP status %s", resp.Status)
+	}
+	return nil
+}
+
+func (m *writeMirror) cleanupMetricsForUser(userID string) {
+	m.mirroredRequests.DeleteLabelValues(userID)
+	m.droppedRequests.DeleteLabelValues(userID)
+	m.failedRequests.DeleteLabelValues(userID)
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/push"
+)
+
+type writeMirrorLimitsMock struct {
+	enabled     bool
+	sampleRatio float64
+}
+
+func (m writeMirrorLimitsMock) WriteMirrorEnabled(string) bool        { return m.enabled }
+func (m writeMirrorLimitsMock) WriteMirrorSampleRatio(string) float64 { return m.sampleRatio }
+
+func TestWriteMirrorConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		setup       func(cfg *WriteMirrorConfig)
+		expectedErr error
+	}{
+		"should pass with default config": {
+			setup: func(*WriteMirrorConfig) {},
+		},
+		"should pass with a valid URL": {
+			setup: func(cfg *WriteMirrorConfig) { cfg.URL = "http://mimir:8080/api/v1/push" },
+		},
+		"should fail with a relative URL": {
+			setup:       func(cfg *WriteMirrorConfig) { cfg.URL = "/api/v1/push" },
+			expectedErr: errInvalidWriteMirrorURL,
+		},
+		"should fail with a non-http URL": {
+			setup:       func(cfg *WriteMirrorConfig) { cfg.URL = "ftp://mimir/api/v1/push" },
+			expectedErr: errInvalidWriteMirrorURL,
+		},
+		"should fail with zero concurrency": {
+			setup: func(cfg *WriteMirrorConfig) {
+				cfg.URL = "http://mimir:8080/api/v1/push"
+				cfg.Concurrency = 0
+			},
+			expectedErr: errInvalidWriteMirrorConcurrency,
+		},
+		"should fail with zero queue capacity": {
+			setup: func(cfg *WriteMirrorConfig) {
+				cfg.URL = "http://mimir:8080/api/v1/push"
+				cfg.QueueCapacity = 0
+			},
+			expectedErr: errInvalidWriteMirrorQueueCapacity,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := WriteMirrorConfig{}
+			flagext.DefaultValues(&cfg)
+			testData.setup(&cfg)
+			assert.Equal(t, testData.expectedErr, cfg.Validate())
+		})
+	}
+}
+
+func TestWriteMirror_ShouldMirrorWriteRequests(t *testing.T) {
+	var (
+		receivedMx sync.Mutex
+		received   []*mimirpb.WriteRequest
+		tenants    []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+
+		req := &mimirpb.WriteRequest{}
+		require.NoError(t, req.Unmarshal(body))
+
+		receivedMx.Lock()
+		received = append(received, req)
+		tenants = append(tenants, r.Header.Get(user.OrgIDHeaderName))
+		receivedMx.Unlock()
+
+		if r.Header.Get(user.OrgIDHeaderName) == "failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	reg := prometheus.NewPedanticRegistry()
+	m := startWriteMirror(t, server.URL, 10, writeMirrorLimitsMock{enabled: true, sampleRatio: 1}, reg)
+
+	req := makeWriteRequest(0, 2, 0, false, false)
+	m.mirror("user", push.NewParsedRequest(req))
+	m.mirror("failing", push.NewParsedRequest(req))
+
+	test.Poll(t, time.Second, 2, func() interface{} {
+		receivedMx.Lock()
+		defer receivedMx.Unlock()
+		return len(received)
+	})
+
+	receivedMx.Lock()
+	assert.ElementsMatch(t, []string{"user", "failing"}, tenants)
+	for _, r := range received {
+		require.Len(t, r.Timeseries, len(req.Timeseries))
+		for i, ts := range r.Timeseries {
+			assert.Equal(t, req.Timeseries[i].Labels, ts.Labels)
+			assert.Equal(t, req.Timeseries[i].Samples, ts.Samples)
+		}
+	}
+	receivedMx.Unlock()
+
+	test.Poll(t, time.Second, nil, func() interface{} {
+		return testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP cortex_distributor_write_mirror_requests_total The total number of write requests queued to be mirrored.
+			# TYPE cortex_distributor_write_mirror_requests_total counter
+			cortex_distributor_write_mirror_requests_total{user="failing"} 1
+			cortex_distributor_write_mirror_requests_total{user="user"} 1
+
+			# HELP cortex_distributor_write_mirror_requests_failed_total The total number of mirrored write requests that failed to be sent.
+			# TYPE cortex_distributor_write_mirror_requests_failed_total counter
+			cortex_distributor_write_mirror_requests_failed_total{user="failing"} 1
+		`), "cortex_distributor_write_mirror_requests_total", "cortex_distributor_write_mirror_requests_failed_total")
+	})
+}
+
+func TestWriteMirror_ShouldDropWriteRequestsWhenQueueIsFull(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-unblock
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(unblock) })
+
+	reg := prometheus.NewPedanticRegistry()
+	m := startWriteMirror(t, server.URL, 1, writeMirrorLimitsMock{enabled: true, sampleRatio: 1}, reg)
+
+	req := makeWriteRequest(0, 1, 0, false, false)
+
+	// The first request is picked up by the worker, which then blocks on the server.
+	m.mirror("user", push.NewParsedRequest(req))
+	test.Poll(t, time.Second, 0, func() interface{} {
+		return len(m.queues[0])
+	})
+
+	// The second request fills the queue, and the next ones are dropped without blocking.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		m.mirror("user", push.NewParsedRequest(req))
+	}
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_write_mirror_queue_length Current number of write requests queued to be mirrored.
+		# TYPE cortex_distributor_write_mirror_queue_length gauge
+		cortex_distributor_write_mirror_queue_length 1
+
+		# HELP cortex_distributor_write_mirror_requests_dropped_total The total number of write requests not mirrored because the queue was full.
+		# TYPE cortex_distributor_write_mirror_requests_dropped_total counter
+		cortex_distributor_write_mirror_requests_dropped_total{user="user"} 2
+
+		# HELP cortex_distributor_write_mirror_requests_total The total number of write requests queued to be mirrored.
+		# TYPE cortex_distributor_write_mirror_requests_total counter
+		cortex_distributor_write_mirror_requests_total{user="user"} 2
+	`), "cortex_distributor_write_mirror_queue_length", "cortex_distributor_write_mirror_requests_dropped_total", "cortex_distributor_write_mirror_requests_total"))
+}
+
+func TestWriteMirror_ShouldDelayCleanUpUntilRequestIsSerialized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	// Do not start the mirror, so that the request stays in the queue.
+	m := newWriteMirror(WriteMirrorConfig{URL: server.URL, Timeout: time.Second, Concurrency: 1, QueueCapacity: 1}, writeMirrorLimitsMock{enabled: true, sampleRatio: 1}, nil, log.NewNopLogger())
+
+	cleanedUp := false
+	pushReq := push.NewParsedRequest(makeWriteRequest(0, 1, 0, false, false))
+	pushReq.AddCleanup(func() { cleanedUp = true })
+
+	m.mirror("user", pushReq)
+	pushReq.CleanUp()
+	assert.False(t, cleanedUp)
+
+	require.NoError(t, m.send(context.Background(), <-m.queues[0]))
+	assert.True(t, cleanedUp)
+
+	// Dropped requests are not retained.
+	cleanedUp = false
+	pushReq = push.NewParsedRequest(makeWriteRequest(0, 1, 0, false, false))
+	pushReq.AddCleanup(func() { cleanedUp = true })
+
+	m.mirror("user", push.NewParsedRequest(makeWriteRequest(0, 1, 0, false, false)))
+	m.mirror("user", pushReq)
+	pushReq.CleanUp()
+	assert.True(t, cleanedUp)
+}
+
+func TestWriteMirror_ShouldHonorPerTenantLimits(t *testing.T) {
+	tests := map[string]struct {
+		limits   writeMirrorLimitsMock
+		expected int
+	}{
+		"mirroring disabled": {
+			limits:   writeMirrorLimitsMock{enabled: false, sampleRatio: 1},
+			expected: 0,
+		},
+		"mirroring enabled with zero sample ratio": {
+			limits:   writeMirrorLimitsMock{enabled: true, sampleRatio: 0},
+			expected: 0,
+		},
+		"mirroring enabled with full sample ratio": {
+			limits:   writeMirrorLimitsMock{enabled: true, sampleRatio: 1},
+			expected: 10,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			// Do not start the mirror, so that requests stay in the queue.
+			m := newWriteMirror(WriteMirrorConfig{URL: "http://localhost", Concurrency: 1, QueueCapacity: 100}, testData.limits, nil, log.NewNopLogger())
+
+			for i := 0; i < 10; i++ {
+				m.mirror("user", push.NewParsedRequest(makeWriteRequest(0, 1, 0, false, false)))
+			}
+			assert.Equal(t, testData.expected, len(m.queues[0]))
+		})
+	}
+}
+
+func startWriteMirror(t *testing.T, url string, queueCapacity int, limits writeMirrorLimits, reg prometheus.Registerer) *writeMirror {
+	m := newWriteMirror(WriteMirrorConfig{
+		URL:           url,
+		Timeout:       10 * time.Second,
+		Concurrency:   1,
+		QueueCapacity: queueCapacity,
+	}, limits, reg, log.NewNopLogger())
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), m))
+	t.Cleanup(func() {
+		assert.NoError(t, services.StopAndAwaitTerminated(context.Background(), m))
+	})
+
+	return m
+}
@@ -94,6 +94,11 @@ func (c *cardinalityEstimation) Do(ctx context.Context, request Request) (Respon
 	actualCardinality := statistics.GetFetchedSeriesCount()
 	spanLog.LogFields(otlog.Uint64("actual cardinality", actualCardinality))
 
+	// The query is not executed when only the plan is requested, so the actual cardinality is unknown.
+	if request.GetOptions().Explain == ExplainMode_PLAN {
+		return res, nil
+	}
+
 	if !estimateAvailable || !isCardinalitySimilar(actualCardinality, estimatedCardinality) {
 		c.storeCardinalityForKey(k, actualCardinality)
 		spanLog.LogFields(otlog.Bool("cache updated", true))
@@ -112,15 +117,26 @@ func (c *cardinalityEstimation) Do(ctx context.Context, request Request) (Respon
 // lookupCardinalityForKey fetches a cardinality estimate for the given key from
 // the results cache.
 func (c *cardinalityEstimation) lookupCardinalityForKey(ctx context.Context, key string) (uint64, bool) {
-	if c.cache == nil {
+	return lookupCardinalityEstimate(ctx, c.cache, c.logger, key)
+}
+
+// storeCardinalityForKey stores a cardinality estimate for the given key in the
+// results cache.
+func (c *cardinalityEstimation) storeCardinalityForKey(key string, count uint64) {
+	storeCardinalityEstimate(c.cache, c.logger, key, count)
+}
+
+// lookupCardinalityEstimate fetches a cardinality estimate for the given key from the input cache.
+func lookupCardinalityEstimate(ctx context.Context, c cache.Cache, logger log.Logger, key string) (uint64, bool) {
+	if c == nil {
 		return 0, false
 	}
-	res := c.cache.Fetch(ctx, []string{key})
+	res := c.Fetch(ctx, []string{key})
 	if val, ok := res[key]; ok {
 		qs := &QueryStatistics{}
 		err := proto.Unmarshal(val, qs)
 		if err != nil {
-			level.Warn(c.logger).Log("msg", "failed to unmarshal cardinality estimate")
+			level.Warn(logger).Log("msg", "failed to unmarshal cardinality estimate")
 			return 0, false
 		}
 		return qs.EstimatedSeriesCount, true
@@ -128,21 +144,20 @@ func (c *cardinalityEstimation) lookupCardinalityForKey(ctx context.Context, key
 	return 0, false
 }
 
-// storeCardinalityForKey stores a cardinality estimate for the given key in the
-// results cache.
-func (c *cardinalityEstimation) storeCardinalityForKey(key string, count uint64) {
-	if c.cache == nil {
+// storeCardinalityEstimate stores a cardinality estimate for the given key in the input cache.
+func storeCardinalityEstimate(c cache.Cache, logger log.Logger, key string, count uint64) {
+	if c == nil {
 		return
 	}
 	m := &QueryStatistics{EstimatedSeriesCount: count}
 	marshaled, err := proto.Marshal(m)
 	if err != nil {
-		level.Warn(c.logger).Log("msg", "failed to marshal cardinality estimate")
+		level.Warn(logger).Log("msg", "failed to marshal cardinality estimate")
 		return
 	}
 	// The store is executed asynchronously, potential errors are logged and not
 	// propagated back up the stack.
-	c.cache.StoreAsync(map[string][]byte{key: marshaled}, cardinalityEstimateTTL)
+	c.StoreAsync(map[string][]byte{key: marshaled}, cardinalityEstimateTTL)
 }
 
 func isCardinalitySimilar(actualCardinality, estimatedCardinality uint64) bool {
@@ -163,6 +178,13 @@ func isCardinalitySimilar(actualCardinality, estimatedCardinality uint64) bool {
 // estimates at the bucket boundary, an offset is added based on the hash of the
 // query string.
 func generateCardinalityEstimationCacheKey(userID string, r Request, bucketSize time.Duration) string {
+	// Prefix key with `QS` (short for "query statistics").
+	return generateQueryStatisticsCacheKey("QS", userID, r, bucketSize)
+}
+
+// generateQueryStatisticsCacheKey generates a key with the input prefix to cache
+// statistics about a request under, as described in generateCardinalityEstimationCacheKey.
+func generateQueryStatisticsCacheKey(prefix, userID string, r Request, bucketSize time.Duration) string {
 	hasher := fnv.New64a()
 	_, _ = hasher.Write([]byte(r.GetQuery()))
 
@@ -171,6 +193,5 @@ func generateCardinalityEstimationCacheKey(userID string, r Request, bucketSize
 	startBucket := (r.GetStart() + int64(offset)) / bucketSize.Milliseconds()
 	rangeBucket := (r.GetEnd() - r.GetStart()) / bucketSize.Milliseconds()
 
-	// Prefix key with `QS` (short for "query statistics").
-	return fmt.Sprintf("QS:%s:%s:%d:%d", userID, cacheHashKey(r.GetQuery()), startBucket, rangeBucket)
+	return fmt.Sprintf("%s:%s:%s:%d:%d", prefix, userID, cacheHashKey(r.GetQuery()), startBucket, rangeBucket)
 }
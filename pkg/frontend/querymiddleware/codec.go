@@ -33,6 +33,8 @@ import (
 
 	apierror "github.com/grafana/mimir/pkg/api/error"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 )
@@ -41,6 +43,7 @@ var (
 	errEndBeforeStart = apierror.New(apierror.TypeBadData, `invalid parameter "end": end timestamp must not be before start time`)
 	errNegativeStep   = apierror.New(apierror.TypeBadData, `invalid parameter "step": zero or negative query resolution step widths are not accepted. Try a positive integer`)
 	errStepTooSmall   = apierror.New(apierror.TypeBadData, "exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)")
+	errInvalidExplain = apierror.New(apierror.TypeBadData, `invalid parameter "explain": supported values are "plan" and "analyze"`)
 	allFormats        = []string{formatJSON, formatProtobuf}
 )
 
@@ -55,6 +58,10 @@ const (
 	// Instant query specific options
 	instantSplitControlHeader = "Instant-Split-Control"
 
+	explainParam        = "explain"
+	explainPlanValue    = "plan"
+	explainAnalyzeValue = "analyze"
+
 	operationEncode = "encode"
 	operationDecode = "decode"
 
@@ -120,6 +127,8 @@ type Response interface {
 	proto.Message
 	// GetHeaders returns the HTTP headers in the response.
 	GetHeaders() []*PrometheusResponseHeader
+	// GetWarnings returns the warnings in the response.
+	GetWarnings() []string
 }
 
 type prometheusCodecMetrics struct {
@@ -179,6 +188,10 @@ func (prometheusCodec) MergeResponse(responses ...Response) (Response, error) {
 		return newEmptyPrometheusResponse(), nil
 	}
 
+	if _, ok := responses[0].(*remoteReadResponse); ok {
+		return mergeRemoteReadResponses(responses)
+	}
+
 	promResponses := make([]*PrometheusResponse, 0, len(responses))
 
 	for _, res := range responses {
@@ -203,15 +216,31 @@ func (prometheusCodec) MergeResponse(responses ...Response) (Response, error) {
 			ResultType: model.ValMatrix.String(),
 			Result:     matrixMerge(promResponses),
 		},
+		Warnings: mergeWarnings(promResponses),
 	}, nil
 }
 
-func (c prometheusCodec) DecodeRequest(_ context.Context, r *http.Request) (Request, error) {
+// mergeWarnings returns the unique warnings of the input responses, preserving their order.
+func mergeWarnings(responses []*PrometheusResponse) []string {
+	var warnings []string
+	for _, res := range responses {
+		for _, warning := range res.Warnings {
+			if !util.StringsContain(warnings, warning) {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return warnings
+}
+
+func (c prometheusCodec) DecodeRequest(ctx context.Context, r *http.Request) (Request, error) {
 	switch {
 	case isRangeQuery(r.URL.Path):
 		return c.decodeRangeQueryRequest(r)
 	case isInstantQuery(r.URL.Path):
 		return c.decodeInstantQueryRequest(r)
+	case isRemoteReadQuery(r.URL.Path):
+		return c.decodeRemoteReadRequest(ctx, r)
 	default:
 		return nil, fmt.Errorf("prometheus codec doesn't support requests to %s", r.URL.Path)
 	}
@@ -252,7 +281,9 @@ func (prometheusCodec) decodeRangeQueryRequest(r *http.Request) (Request, error)
 
 	result.Query = r.FormValue("query")
 	result.Path = r.URL.Path
-	decodeOptions(r, &result.Options)
+	if err := decodeOptions(r, &result.Options); err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
 
@@ -266,11 +297,13 @@ func (c prometheusCodec) decodeInstantQueryRequest(r *http.Request) (Request, er
 
 	result.Query = r.FormValue("query")
 	result.Path = r.URL.Path
-	decodeOptions(r, &result.Options)
+	if err := decodeOptions(r, &result.Options); err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
 
-func decodeOptions(r *http.Request, opts *Options) {
+func decodeOptions(r *http.Request, opts *Options) error {
 	opts.CacheDisabled = decodeCacheDisabledOption(r)
 
 	for _, value := range r.Header.Values(totalShardsControlHeader) {
@@ -295,6 +328,41 @@ func decodeOptions(r *http.Request, opts *Options) {
 			opts.InstantSplitDisabled = true
 		}
 	}
+
+	switch r.FormValue(explainParam) {
+	case "":
+		opts.Explain = ExplainMode_NONE
+	case explainPlanValue:
+		opts.Explain = ExplainMode_PLAN
+		// The query is not executed when only the plan is requested, so the empty results must not be cached.
+		opts.CacheDisabled = true
+	case explainAnalyzeValue:
+		opts.Explain = ExplainMode_ANALYZE
+		// The query must be executed to be analyzed, so the results cache is bypassed.
+		opts.CacheDisabled = true
+	default:
+		return errInvalidExplain
+	}
+
+	if value := r.FormValue(querierapi.PartialResponseParam); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return apierror.New(apierror.TypeBadData, fmt.Sprintf("invalid parameter %q: %s", querierapi.PartialResponseParam, err.Error()))
+		}
+		opts.PartialResponse = strconv.FormatBool(enabled)
+	}
+
+	opts.Priority = decodePriorityOption(r)
+
+	return nil
+}
+
+// decodePriorityOption returns the name of the priority class of the query, or an empty string for ad-hoc queries.
+func decodePriorityOption(r *http.Request) string {
+	if priority := querierapi.QueryPriorityFromHeader(r.Header); priority != querierapi.QueryPriorityAdHoc {
+		return priority.String()
+	}
+	return ""
 }
 
 func decodeCacheDisabledOption(r *http.Request) bool {
@@ -328,10 +396,18 @@ func (c prometheusCodec) EncodeRequest(ctx context.Context, r Request) (*http.Re
 				"query": []string{r.Query},
 			}.Encode(),
 		}
+	case *remoteReadRequest:
+		return c.encodeRemoteReadRequest(ctx, r)
 	default:
 		return nil, fmt.Errorf("unsupported request type %T", r)
 	}
 
+	if partialResponse := r.GetOptions().PartialResponse; partialResponse != "" {
+		params := u.Query()
+		params.Set(querierapi.PartialResponseParam, partialResponse)
+		u.RawQuery = params.Encode()
+	}
+
 	req := &http.Request{
 		Method:     "GET",
 		RequestURI: u.String(), // This is what the httpgrpc code looks at.
@@ -340,6 +416,15 @@ func (c prometheusCodec) EncodeRequest(ctx context.Context, r Request) (*http.Re
 		Header:     http.Header{},
 	}
 
+	if r.GetOptions().Explain == ExplainMode_ANALYZE {
+		// Ask the querier to track the per-store statistics.
+		req.Header.Set(stats.StoreStatsHeader, "true")
+	}
+
+	if priority := r.GetOptions().Priority; priority != "" {
+		req.Header.Set(querierapi.QueryPriorityHeader, priority)
+	}
+
 	switch c.preferredQueryResultResponseFormat {
 	case formatJSON:
 		req.Header.Set("Accept", jsonMimeType)
@@ -352,7 +437,7 @@ func (c prometheusCodec) EncodeRequest(ctx context.Context, r Request) (*http.Re
 	return req.WithContext(ctx), nil
 }
 
-func (c prometheusCodec) DecodeResponse(ctx context.Context, r *http.Response, _ Request, logger log.Logger) (Response, error) {
+func (c prometheusCodec) DecodeResponse(ctx context.Context, r *http.Response, req Request, logger log.Logger) (Response, error) {
 	if r.StatusCode/100 == 5 {
 		return nil, httpgrpc.ErrorFromHTTPResponse(&httpgrpc.HTTPResponse{
 			Code: int32(r.StatusCode),
@@ -364,6 +449,10 @@ func (c prometheusCodec) DecodeResponse(ctx context.Context, r *http.Response, _
 		return nil, apierror.New(apierror.TypeTooLargeEntry, string(mustReadResponseBody(r)))
 	}
 
+	if remoteReadReq, ok := req.(*remoteReadRequest); ok {
+		return c.decodeRemoteReadResponse(ctx, r, remoteReadReq, logger)
+	}
+
 	log := spanlogger.FromContext(ctx, logger)
 
 	buf, err := readResponseBody(r)
@@ -411,6 +500,10 @@ func findFormatter(contentType string) formatter {
 }
 
 func (c prometheusCodec) EncodeResponse(ctx context.Context, req *http.Request, res Response) (*http.Response, error) {
+	if remoteReadRes, ok := res.(*remoteReadResponse); ok {
+		return c.encodeRemoteReadResponse(ctx, remoteReadRes)
+	}
+
 	sp, _ := opentracing.StartSpanFromContext(ctx, "APIResponse.ToHTTPResponse")
 	defer sp.Finish()
 
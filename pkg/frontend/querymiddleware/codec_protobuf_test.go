@@ -132,6 +132,25 @@ var protobufCodecScenarios = []struct {
 			Headers: expectedProtobufResponseHeaders,
 		},
 	},
+	{
+		name: "successful empty vector response with warnings",
+		payload: mimirpb.QueryResponse{
+			Status: mimirpb.QueryResponse_SUCCESS,
+			Data: &mimirpb.QueryResponse_Vector{
+				Vector: &mimirpb.VectorData{},
+			},
+			Warnings: []string{"some blocks couldn't be queried"},
+		},
+		response: &PrometheusResponse{
+			Status: statusSuccess,
+			Data: &PrometheusData{
+				ResultType: model.ValVector.String(),
+				Result:     []SampleStream{},
+			},
+			Headers:  expectedProtobufResponseHeaders,
+			Warnings: []string{"some blocks couldn't be queried"},
+		},
+	},
 	{
 		name: "successful vector response with single series with no labels",
 		payload: mimirpb.QueryResponse{
@@ -13,6 +13,7 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
@@ -30,6 +31,7 @@ import (
 
 	apierror "github.com/grafana/mimir/pkg/api/error"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 )
 
 var (
@@ -638,6 +640,42 @@ func TestMergeAPIResponses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Merging of responses with warnings.",
+			input: []Response{
+				&PrometheusResponse{
+					Status: statusSuccess,
+					Data: &PrometheusData{
+						ResultType: matrix,
+						Result:     []SampleStream{},
+					},
+					Warnings: []string{"warning 1", "warning 2"},
+				},
+				&PrometheusResponse{
+					Status: statusSuccess,
+					Data: &PrometheusData{
+						ResultType: matrix,
+						Result:     []SampleStream{},
+					},
+				},
+				&PrometheusResponse{
+					Status: statusSuccess,
+					Data: &PrometheusData{
+						ResultType: matrix,
+						Result:     []SampleStream{},
+					},
+					Warnings: []string{"warning 2", "warning 3"},
+				},
+			},
+			expected: &PrometheusResponse{
+				Status: statusSuccess,
+				Data: &PrometheusData{
+					ResultType: matrix,
+					Result:     []SampleStream{},
+				},
+				Warnings: []string{"warning 1", "warning 2", "warning 3"},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			output, err := codec.MergeResponse(tc.input...)
@@ -879,17 +917,133 @@ func Test_DecodeOptions(t *testing.T) {
 				InstantSplitDisabled: true,
 			},
 		},
+		{
+			name: "enable partial response",
+			input: &http.Request{
+				URL:    &url.URL{RawQuery: "partial_response=1"},
+				Header: http.Header{},
+			},
+			expected: &Options{
+				PartialResponse: "true",
+			},
+		},
+		{
+			name: "disable partial response",
+			input: &http.Request{
+				URL:    &url.URL{RawQuery: "partial_response=false"},
+				Header: http.Header{},
+			},
+			expected: &Options{
+				PartialResponse: "false",
+			},
+		},
+		{
+			name: "explain the query plan",
+			input: &http.Request{
+				URL:    &url.URL{RawQuery: "explain=plan"},
+				Header: http.Header{},
+			},
+			expected: &Options{
+				Explain:       ExplainMode_PLAN,
+				CacheDisabled: true,
+			},
+		},
+		{
+			name: "analyze the query",
+			input: &http.Request{
+				URL:    &url.URL{RawQuery: "explain=analyze"},
+				Header: http.Header{},
+			},
+			expected: &Options{
+				Explain:       ExplainMode_ANALYZE,
+				CacheDisabled: true,
+			},
+		},
+		{
+			name: "set priority class",
+			input: &http.Request{
+				Header: http.Header{
+					"X-Mimir-Query-Priority": []string{"rule"},
+				},
+			},
+			expected: &Options{
+				Priority: "rule",
+			},
+		},
+		{
+			name: "infer dashboard priority class",
+			input: &http.Request{
+				Header: http.Header{
+					"X-Dashboard-Uid": []string{"abc"},
+				},
+			},
+			expected: &Options{
+				Priority: "dashboard",
+			},
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			actual := &Options{}
-			decodeOptions(tt.input, actual)
+			require.NoError(t, decodeOptions(tt.input, actual))
 			require.Equal(t, tt.expected, actual)
 		})
 	}
 }
 
+func Test_DecodeOptions_InvalidPartialResponse(t *testing.T) {
+	input := &http.Request{
+		URL:    &url.URL{RawQuery: "partial_response=foo"},
+		Header: http.Header{},
+	}
+
+	err := decodeOptions(input, &Options{})
+	require.Error(t, err)
+	require.True(t, apierror.IsAPIError(err))
+	require.Contains(t, err.Error(), `invalid parameter "partial_response"`)
+}
+
+func TestPrometheusCodec_EncodeRequest_PartialResponse(t *testing.T) {
+	codec := newTestPrometheusCodec()
+
+	for _, partialResponse := range []string{"", "true", "false"} {
+		t.Run(fmt.Sprintf("partial response: %q", partialResponse), func(t *testing.T) {
+			req := &PrometheusInstantQueryRequest{
+				Path:    "/api/v1/query",
+				Time:    1000,
+				Query:   "up",
+				Options: Options{PartialResponse: partialResponse},
+			}
+
+			encoded, err := codec.EncodeRequest(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, partialResponse, encoded.URL.Query().Get("partial_response"))
+		})
+	}
+}
+
+func TestPrometheusCodec_EncodeRequest_Priority(t *testing.T) {
+	codec := newTestPrometheusCodec()
+
+	for _, priority := range []string{"", "rule", "dashboard"} {
+		t.Run(fmt.Sprintf("priority: %q", priority), func(t *testing.T) {
+			req := &PrometheusRangeQueryRequest{
+				Path:    "/api/v1/query_range",
+				Start:   1000,
+				End:     2000,
+				Step:    10,
+				Query:   "up",
+				Options: Options{Priority: priority},
+			}
+
+			encoded, err := codec.EncodeRequest(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, priority, encoded.Header.Get(querierapi.QueryPriorityHeader))
+		})
+	}
+}
+
 func newTestPrometheusCodec() Codec {
 	return NewPrometheusCodec(prometheus.NewPedanticRegistry(), formatJSON)
 }
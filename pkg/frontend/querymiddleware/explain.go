@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+)
+
+type queryExplainerContextKey int
+
+var queryExplainerCtxKey = queryExplainerContextKey(0)
+
+// queryExplainer collects the explanation of how a query is executed by the query-frontend,
+// while the query goes through the middlewares. It's safe for concurrent use.
+type queryExplainer struct {
+	mode ExplainMode
+
+	mtx              sync.Mutex
+	rewrittenQueries []RewrittenQuery
+	partialQueries   []PartialQuery
+	steps            []QueryStepTiming
+}
+
+func newQueryExplainer(mode ExplainMode) *queryExplainer {
+	return &queryExplainer{mode: mode}
+}
+
+func contextWithQueryExplainer(ctx context.Context, explainer *queryExplainer) context.Context {
+	return context.WithValue(ctx, queryExplainerCtxKey, explainer)
+}
+
+// queryExplainerFromContext returns the queryExplainer from the context, or nil if the query is not explained.
+func queryExplainerFromContext(ctx context.Context) *queryExplainer {
+	explainer, _ := ctx.Value(queryExplainerCtxKey).(*queryExplainer)
+	return explainer
+}
+
+// recordRewrittenQuery records the query rewritten by a middleware. It's a no-op if the explainer is nil.
+func (e *queryExplainer) recordRewrittenQuery(middleware, original, rewritten string, partialQueries int) {
+	if e == nil {
+		return
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.rewrittenQueries = append(e.rewrittenQueries, RewrittenQuery{
+		Middleware:     middleware,
+		Original:       original,
+		Rewritten:      rewritten,
+		PartialQueries: int32(partialQueries),
+	})
+}
+
+// startStep records the execution of a middleware step, when the query is analyzed. The returned
+// function must be called once the step completes, to record the time spent in it. It's a no-op
+// if the explainer is nil or the query is not analyzed.
+func (e *queryExplainer) startStep(name string) (done func()) {
+	if e == nil || e.mode != ExplainMode_ANALYZE {
+		return func() {}
+	}
+
+	e.mtx.Lock()
+	idx := e.stepIndex(name)
+	e.steps[idx].Calls++
+	e.mtx.Unlock()
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+
+		e.mtx.Lock()
+		defer e.mtx.Unlock()
+
+		e.steps[idx].DurationSeconds += elapsed.Seconds()
+	}
+}
+
+// stepIndex returns the index of the step with the given name, adding it if it doesn't exist yet.
+// It must be called with the mtx held.
+func (e *queryExplainer) stepIndex(name string) int {
+	for i := range e.steps {
+		if e.steps[i].Name == name {
+			return i
+		}
+	}
+
+	e.steps = append(e.steps, QueryStepTiming{Name: name})
+	return len(e.steps) - 1
+}
+
+func (e *queryExplainer) recordPartialQuery(partialQuery PartialQuery) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.partialQueries = append(e.partialQueries, partialQuery)
+}
+
+// explanation returns the explanation of the query. The partial queries are sorted by
+// time range and query, because they're recorded in the order they've been executed.
+func (e *queryExplainer) explanation(duration time.Duration) *QueryExplanation {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	partialQueries := append([]PartialQuery(nil), e.partialQueries...)
+	sort.SliceStable(partialQueries, func(i, j int) bool {
+		if partialQueries[i].Start != partialQueries[j].Start {
+			return partialQueries[i].Start < partialQueries[j].Start
+		}
+		if partialQueries[i].End != partialQueries[j].End {
+			return partialQueries[i].End < partialQueries[j].End
+		}
+		return partialQueries[i].Query < partialQueries[j].Query
+	})
+
+	explanation := &QueryExplanation{
+		Mode:             explainPlanValue,
+		RewrittenQueries: append([]RewrittenQuery(nil), e.rewrittenQueries...),
+		PartialQueries:   partialQueries,
+	}
+	if e.mode == ExplainMode_ANALYZE {
+		explanation.Mode = explainAnalyzeValue
+		explanation.DurationSeconds = duration.Seconds()
+		explanation.Steps = append([]QueryStepTiming(nil), e.steps...)
+	}
+
+	return explanation
+}
+
+// queryExplainHandler records the partial queries sent to the queriers when the query is explained.
+// When only the query plan is requested, the partial queries are not executed and empty responses
+// are returned instead.
+type queryExplainHandler struct {
+	next Handler
+}
+
+func newQueryExplainHandler(next Handler) Handler {
+	return queryExplainHandler{next: next}
+}
+
+func (h queryExplainHandler) Do(ctx context.Context, r Request) (Response, error) {
+	explainer := queryExplainerFromContext(ctx)
+	if explainer == nil {
+		return h.next.Do(ctx, r)
+	}
+
+	partialQuery := PartialQuery{
+		Query: r.GetQuery(),
+		Start: r.GetStart(),
+		End:   r.GetEnd(),
+	}
+	if _, ok := r.(*PrometheusRangeQueryRequest); ok {
+		partialQuery.Step = r.GetStep()
+	}
+
+	if explainer.mode != ExplainMode_ANALYZE {
+		explainer.recordPartialQuery(partialQuery)
+		return newEmptyPrometheusResponseForRequest(r), nil
+	}
+
+	// Track the stats of the partial query on their own, and then merge them to the stats of the whole query.
+	partialStats, partialCtx := stats.ContextWithEmptyStats(ctx)
+
+	start := time.Now()
+	res, err := h.next.Do(partialCtx, r)
+	partialQuery.DurationSeconds = time.Since(start).Seconds()
+
+	stats.FromContext(ctx).Merge(partialStats)
+
+	partialQuery.FetchedSeriesCount = partialStats.LoadFetchedSeries()
+	partialQuery.FetchedChunkBytes = partialStats.LoadFetchedChunkBytes()
+	for _, storeStats := range partialStats.LoadStoreStats() {
+		partialQuery.Stores = append(partialQuery.Stores, PartialQueryStoreStats{
+			Component:          storeStats.Component,
+			Address:            storeStats.Address,
+			FetchedSeriesCount: storeStats.FetchedSeriesCount,
+			FetchedChunkBytes:  storeStats.FetchedChunkBytes,
+			LatencySeconds:     storeStats.Latency.Seconds(),
+		})
+	}
+	for _, tenantStats := range partialStats.LoadTenantStats() {
+		partialQuery.Tenants = append(partialQuery.Tenants, PartialQueryTenantStats{
+			TenantID:           tenantStats.TenantId,
+			FetchedSeriesCount: tenantStats.FetchedSeriesCount,
+			FetchedChunkBytes:  tenantStats.FetchedChunkBytes,
+			QueueTimeSeconds:   tenantStats.QueueTime.Seconds(),
+		})
+	}
+	if err != nil {
+		partialQuery.Error = err.Error()
+	}
+
+	explainer.recordPartialQuery(partialQuery)
+	return res, err
+}
+
+// newEmptyPrometheusResponseForRequest returns an empty successful response, with the result type
+// matching the input request type.
+func newEmptyPrometheusResponseForRequest(r Request) *PrometheusResponse {
+	res := newEmptyPrometheusResponse()
+	if _, ok := r.(*PrometheusInstantQueryRequest); ok {
+		res.Data.ResultType = model.ValVector.String()
+	}
+	return res
+}
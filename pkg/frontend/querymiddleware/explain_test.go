@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/querier/stats"
+)
+
+func TestQueryExplain(t *testing.T) {
+	const totalShards = 4
+
+	codec := newTestPrometheusCodec()
+	tw, err := NewTripperware(
+		Config{ShardedQueries: true},
+		log.NewNopLogger(),
+		mockLimits{totalShards: totalShards},
+		codec,
+		nil,
+		promql.EngineOpts{
+			Logger:     log.NewNopLogger(),
+			MaxSamples: 1000,
+			Timeout:    time.Minute,
+		},
+		nil,
+	)
+	require.NoError(t, err)
+
+	start := time.Date(2021, 1, 2, 3, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	// The downstream simulates the querier tracking the per-store stats, which are then
+	// merged by the query-frontend into the stats of the request context.
+	downstreamCalls := atomic.NewInt32(0)
+	downstream := RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		downstreamCalls.Inc()
+
+		if enabled, _ := strconv.ParseBool(r.Header.Get(stats.StoreStatsHeader)); enabled {
+			querierStats := &stats.Stats{}
+			querierStats.AddFetchedSeries(1)
+			querierStats.AddFetchedChunkBytes(100)
+			querierStats.AddStoreStats(stats.StoreStats{Component: stats.StoreComponentIngester, Address: "ingester-1", FetchedSeriesCount: 1, FetchedChunkBytes: 100, Latency: time.Second})
+			querierStats.AddTenantStats(stats.TenantStats{TenantId: "team-a", FetchedSeriesCount: 1, FetchedChunkBytes: 100, QueueTime: time.Second})
+			stats.FromContext(r.Context()).Merge(querierStats)
+		}
+
+		return codec.EncodeResponse(r.Context(), r, &PrometheusResponse{
+			Status: statusSuccess,
+			Data: &PrometheusData{
+				ResultType: "matrix",
+				Result: []SampleStream{{
+					Labels:  []mimirpb.LabelAdapter{{Name: "foo", Value: "bar"}},
+					Samples: []mimirpb.Sample{{TimestampMs: end.UnixMilli(), Value: 1}},
+				}},
+			},
+		})
+	})
+	tripper := tw(downstream)
+
+	doRequest := func(t *testing.T, explain string) (*PrometheusResponse, error) {
+		queryStats, ctx := stats.ContextWithEmptyStats(user.InjectOrgID(context.Background(), "user-1"))
+		params := url.Values{
+			"query":   []string{`sum(rate(metric[1m]))`},
+			"start":   []string{strconv.FormatInt(start.Unix(), 10)},
+			"end":     []string{strconv.FormatInt(end.Unix(), 10)},
+			"step":    []string{"60"},
+			"explain": []string{explain},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/query_range?"+params.Encode(), nil).WithContext(ctx)
+
+		res, err := tripper.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		decoded := &PrometheusResponse{}
+		require.NoError(t, json.Unmarshal(body, decoded))
+
+		if explain == explainAnalyzeValue {
+			// The stats of the partial queries should have been merged into the stats of the query.
+			assert.Equal(t, uint64(totalShards), queryStats.LoadFetchedSeries())
+		}
+
+		return decoded, nil
+	}
+
+	t.Run("plan", func(t *testing.T) {
+		downstreamCalls.Store(0)
+		res, err := doRequest(t, explainPlanValue)
+		require.NoError(t, err)
+		require.NotNil(t, res.Explanation)
+
+		// The query should not be executed by the queriers.
+		assert.Equal(t, int32(0), downstreamCalls.Load())
+		assert.Empty(t, res.Data.Result)
+
+		assert.Equal(t, explainPlanValue, res.Explanation.Mode)
+		assert.Zero(t, res.Explanation.DurationSeconds)
+		assert.Empty(t, res.Explanation.Steps)
+		require.Len(t, res.Explanation.RewrittenQueries, 1)
+		assert.Equal(t, "querysharding", res.Explanation.RewrittenQueries[0].Middleware)
+		assert.Equal(t, `sum(rate(metric[1m]))`, res.Explanation.RewrittenQueries[0].Original)
+		assert.Equal(t, int32(totalShards), res.Explanation.RewrittenQueries[0].PartialQueries)
+
+		require.Len(t, res.Explanation.PartialQueries, totalShards)
+		for _, partialQuery := range res.Explanation.PartialQueries {
+			assert.Equal(t, time.Minute.Milliseconds(), partialQuery.Step)
+			assert.Zero(t, partialQuery.DurationSeconds)
+			assert.Empty(t, partialQuery.Stores)
+		}
+	})
+
+	t.Run("analyze", func(t *testing.T) {
+		downstreamCalls.Store(0)
+		res, err := doRequest(t, explainAnalyzeValue)
+		require.NoError(t, err)
+		require.NotNil(t, res.Explanation)
+
+		assert.Equal(t, int32(totalShards), downstreamCalls.Load())
+		assert.NotEmpty(t, res.Data.Result)
+
+		assert.Equal(t, explainAnalyzeValue, res.Explanation.Mode)
+		assert.Greater(t, res.Explanation.DurationSeconds, float64(0))
+		require.Len(t, res.Explanation.RewrittenQueries, 1)
+
+		steps := map[string]QueryStepTiming{}
+		for _, step := range res.Explanation.Steps {
+			steps[step.Name] = step
+		}
+		require.Contains(t, steps, "querysharding")
+		assert.Equal(t, int32(1), steps["querysharding"].Calls)
+		assert.Greater(t, steps["querysharding"].DurationSeconds, float64(0))
+
+		require.Len(t, res.Explanation.PartialQueries, totalShards)
+		for _, partialQuery := range res.Explanation.PartialQueries {
+			assert.Greater(t, partialQuery.DurationSeconds, float64(0))
+			assert.Equal(t, uint64(1), partialQuery.FetchedSeriesCount)
+			assert.Equal(t, uint64(100), partialQuery.FetchedChunkBytes)
+			assert.Equal(t, []PartialQueryStoreStats{
+				{Component: "ingester", Address: "ingester-1", FetchedSeriesCount: 1, FetchedChunkBytes: 100, LatencySeconds: 1},
+			}, partialQuery.Stores)
+			assert.Equal(t, []PartialQueryTenantStats{
+				{TenantID: "team-a", FetchedSeriesCount: 1, FetchedChunkBytes: 100, QueueTimeSeconds: 1},
+			}, partialQuery.Tenants)
+		}
+	})
+
+	t.Run("not explained", func(t *testing.T) {
+		downstreamCalls.Store(0)
+		res, err := doRequest(t, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(totalShards), downstreamCalls.Load())
+		assert.NotEmpty(t, res.Data.Result)
+		assert.Nil(t, res.Explanation)
+	})
+
+	t.Run("invalid explain mode", func(t *testing.T) {
+		_, err := doRequest(t, "foo")
+		require.ErrorIs(t, err, errInvalidExplain)
+	})
+}
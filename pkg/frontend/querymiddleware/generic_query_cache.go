@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
@@ -171,5 +172,16 @@ func generateGenericQueryRequestCacheKey(tenantIDs []string, req *genericQueryRe
 }
 
 func isGenericQueryResponseCacheable(res *http.Response) bool {
-	return res.StatusCode >= 200 && res.StatusCode < 300
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false
+	}
+
+	// Partial responses are marked as not cacheable by the queriers.
+	for _, value := range res.Header.Values(cacheControlHeader) {
+		if strings.Contains(value, noStoreValue) {
+			return false
+		}
+	}
+
+	return true
 }
@@ -16,6 +16,7 @@ import (
 )
 
 // newInstrumentMiddleware can be inserted into the middleware chain to expose timing information.
+// The timing is also recorded in the query explanation, when the query is analyzed.
 func newInstrumentMiddleware(name string, metrics *instrumentMiddlewareMetrics) Middleware {
 	var durationCol instrument.Collector
 
@@ -35,6 +36,9 @@ func newInstrumentMiddleware(name string, metrics *instrumentMiddlewareMetrics)
 					req.LogToSpan(sp)
 				}
 
+				done := queryExplainerFromContext(ctx).startStep(name)
+				defer done()
+
 				var err error
 				resp, err = next.Do(ctx, req)
 				return err
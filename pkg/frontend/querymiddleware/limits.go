@@ -42,6 +42,18 @@ type Limits interface {
 	// query may be. 0 means "unlimited".
 	MaxQueryExpressionSizeBytes(userID string) int
 
+	// MaxQueryCost returns the limit of the estimated cost of a query, computed as the estimated
+	// number of series fetched by the query multiplied by its number of steps. 0 means "unlimited".
+	MaxQueryCost(userID string) int
+
+	// QueryCostBudgetPerMinute returns the limit of the total estimated cost of the queries
+	// run per minute. 0 means "unlimited".
+	QueryCostBudgetPerMinute(userID string) int
+
+	// MaxRemoteReadResponseSizeBytes returns the limit of the size of a remote read response,
+	// in bytes. 0 means "unlimited".
+	MaxRemoteReadResponseSizeBytes(userID string) int
+
 	// MaxCacheFreshness returns the period after which results are cacheable,
 	// to prevent caching of very recent results.
 	MaxCacheFreshness(userID string) time.Duration
@@ -121,9 +133,11 @@ func (l limitsMiddleware) Do(ctx context.Context, r Request) (Response, error) {
 		return nil, apierror.New(apierror.TypeBadData, err.Error())
 	}
 
-	// Clamp the time range based on the max query lookback and block retention period.
-	blocksRetentionPeriod := validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, l.CompactorBlocksRetentionPeriod)
-	maxQueryLookback := validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, l.MaxQueryLookback)
+	// Clamp the time range based on the max query lookback and block retention period. When the query
+	// is federated across multiple tenants, the time range is clamped based on the least restrictive
+	// limits, because the querier applies each tenant's own limits to the tenant's sub-queries.
+	blocksRetentionPeriod := validation.LeastRestrictiveDurationPerTenant(tenantIDs, l.CompactorBlocksRetentionPeriod)
+	maxQueryLookback := validation.LeastRestrictiveDurationPerTenant(tenantIDs, l.MaxQueryLookback)
 	maxLookback := util_math.Min(blocksRetentionPeriod, maxQueryLookback)
 	if maxLookback > 0 {
 		minStartTime := util.TimeToMillis(time.Now().Add(-maxLookback))
@@ -138,7 +152,7 @@ func (l limitsMiddleware) Do(ctx context.Context, r Request) (Response, error) {
 				"maxQueryLookback", maxQueryLookback,
 				"blocksRetentionPeriod", blocksRetentionPeriod)
 
-			return newEmptyPrometheusResponse(), nil
+			return newEmptyResponse(r), nil
 		}
 
 		if r.GetStart() < minStartTime {
@@ -248,6 +262,14 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		request.LogToSpan(span)
 	}
+
+	// Collect the explanation of the query, if requested.
+	var explainer *queryExplainer
+	if mode := request.GetOptions().Explain; mode != ExplainMode_NONE {
+		explainer = newQueryExplainer(mode)
+		ctx = contextWithQueryExplainer(ctx, explainer)
+	}
+
 	tenantIDs, err := tenant.TenantIDs(ctx)
 	if err != nil {
 		return nil, apierror.New(apierror.TypeBadData, err.Error())
@@ -277,7 +299,8 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 	// different worker via the `intermediate` channel, so the maximum
 	// parallelism is limited. This worker will then call `Do` on the resulting
 	// handler.
-	response, err := rt.middleware.Wrap(
+	start := time.Now()
+	response, err := rt.middleware.Wrap(newQueryExplainHandler(
 		HandlerFunc(func(ctx context.Context, r Request) (Response, error) {
 			s := newSubRequest(ctx, r)
 			select {
@@ -292,11 +315,19 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 			case <-ctx.Done():
 				return nil, ctx.Err()
 			}
-		})).Do(ctx, request)
+		}))).Do(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
+	if explainer != nil {
+		if promResponse, ok := response.(*PrometheusResponse); ok {
+			explained := *promResponse
+			explained.Explanation = explainer.explanation(time.Since(start))
+			response = &explained
+		}
+	}
+
 	return rt.codec.EncodeResponse(ctx, r, response)
 }
 
@@ -129,6 +129,60 @@ func TestLimitsMiddleware_MaxQueryLookback(t *testing.T) {
 	}
 }
 
+func TestLimitsMiddleware_MaxQueryLookback_TenantFederation(t *testing.T) {
+	const (
+		thirtyDays = 30 * 24 * time.Hour
+	)
+
+	now := time.Now()
+
+	tests := map[string]struct {
+		orgID             string
+		expectedStartTime time.Time
+	}{
+		"should clamp the time range based on the least restrictive max lookback of the tenants": {
+			orgID:             "tenant-a|tenant-b",
+			expectedStartTime: now.Add(-2 * thirtyDays),
+		},
+		"should not clamp the time range if the max lookback is disabled for any of the tenants": {
+			orgID:             "tenant-a|tenant-b|tenant-c",
+			expectedStartTime: now.Add(-3 * thirtyDays),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			req := &PrometheusRangeQueryRequest{
+				Start: util.TimeToMillis(now.Add(-3 * thirtyDays)),
+				End:   util.TimeToMillis(now),
+			}
+
+			tenant.WithDefaultResolver(tenant.NewMultiResolver())
+			limits := multiTenantMockLimits{
+				byTenant: map[string]mockLimits{
+					"tenant-a": {maxQueryLookback: thirtyDays, compactorBlocksRetentionPeriod: 4 * thirtyDays},
+					"tenant-b": {maxQueryLookback: 2 * thirtyDays, compactorBlocksRetentionPeriod: 4 * thirtyDays},
+					"tenant-c": {compactorBlocksRetentionPeriod: 4 * thirtyDays},
+				},
+			}
+			middleware := newLimitsMiddleware(limits, log.NewNopLogger())
+
+			innerRes := newEmptyPrometheusResponse()
+			inner := &mockHandler{}
+			inner.On("Do", mock.Anything, mock.Anything).Return(innerRes, nil)
+
+			ctx := user.InjectOrgID(context.Background(), testData.orgID)
+			res, err := middleware.Wrap(inner).Do(ctx, req)
+			require.NoError(t, err)
+			assert.Same(t, innerRes, res)
+
+			// Assert on the time range of the request passed to the inner handler (5s delta).
+			require.Len(t, inner.Calls, 1)
+			assert.InDelta(t, util.TimeToMillis(testData.expectedStartTime), inner.Calls[0].Arguments.Get(1).(Request).GetStart(), float64(5000))
+		})
+	}
+}
+
 func TestLimitsMiddleware_MaxQueryExpressionSizeBytes(t *testing.T) {
 	now := time.Now()
 
@@ -371,6 +425,18 @@ func (m multiTenantMockLimits) MaxQueryExpressionSizeBytes(userID string) int {
 	return m.byTenant[userID].maxQueryExpressionSizeBytes
 }
 
+func (m multiTenantMockLimits) MaxQueryCost(userID string) int {
+	return m.byTenant[userID].maxQueryCost
+}
+
+func (m multiTenantMockLimits) QueryCostBudgetPerMinute(userID string) int {
+	return m.byTenant[userID].queryCostBudgetPerMinute
+}
+
+func (m multiTenantMockLimits) MaxRemoteReadResponseSizeBytes(userID string) int {
+	return m.byTenant[userID].maxRemoteReadResponseSizeBytes
+}
+
 func (m multiTenantMockLimits) MaxQueryParallelism(userID string) int {
 	return m.byTenant[userID].maxQueryParallelism
 }
@@ -440,6 +506,9 @@ type mockLimits struct {
 	maxQueryLength                       time.Duration
 	maxTotalQueryLength                  time.Duration
 	maxQueryExpressionSizeBytes          int
+	maxQueryCost                         int
+	queryCostBudgetPerMinute             int
+	maxRemoteReadResponseSizeBytes       int
 	maxCacheFreshness                    time.Duration
 	maxQueryParallelism                  int
 	maxShardedQueries                    int
@@ -473,6 +542,18 @@ func (m mockLimits) MaxQueryExpressionSizeBytes(string) int {
 	return m.maxQueryExpressionSizeBytes
 }
 
+func (m mockLimits) MaxQueryCost(string) int {
+	return m.maxQueryCost
+}
+
+func (m mockLimits) QueryCostBudgetPerMinute(string) int {
+	return m.queryCostBudgetPerMinute
+}
+
+func (m mockLimits) MaxRemoteReadResponseSizeBytes(string) int {
+	return m.maxRemoteReadResponseSizeBytes
+}
+
 func (m mockLimits) MaxQueryParallelism(string) int {
 	if m.maxQueryParallelism == 0 {
 		return 14 // Flag default.
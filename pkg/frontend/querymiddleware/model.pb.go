@@ -7,6 +7,7 @@ package querymiddleware
 
 import (
 	bytes "bytes"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -19,6 +20,7 @@ import (
 	math "math"
 	math_bits "math/bits"
 	reflect "reflect"
+	strconv "strconv"
 	strings "strings"
 	time "time"
 )
@@ -35,6 +37,33 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+type ExplainMode int32
+
+const (
+	// The query is executed and not explained.
+	ExplainMode_NONE ExplainMode = 0
+	// The query is not executed, and only the query plan is returned.
+	ExplainMode_PLAN ExplainMode = 1
+	// The query is executed, and the query plan is returned along with the execution statistics.
+	ExplainMode_ANALYZE ExplainMode = 2
+)
+
+var ExplainMode_name = map[int32]string{
+	0: "NONE",
+	1: "PLAN",
+	2: "ANALYZE",
+}
+
+var ExplainMode_value = map[string]int32{
+	"NONE":    0,
+	"PLAN":    1,
+	"ANALYZE": 2,
+}
+
+func (ExplainMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{0}
+}
+
 type PrometheusRangeQueryRequest struct {
 	Path    string        `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	Start   int64         `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
@@ -288,6 +317,10 @@ type PrometheusResponse struct {
 	ErrorType string                      `protobuf:"bytes,3,opt,name=ErrorType,proto3" json:"errorType,omitempty"`
 	Error     string                      `protobuf:"bytes,4,opt,name=Error,proto3" json:"error,omitempty"`
 	Headers   []*PrometheusResponseHeader `protobuf:"bytes,5,rep,name=Headers,proto3" json:"-"`
+	// Explanation of how the query has been executed. Only set when the query is explained.
+	Explanation *QueryExplanation `protobuf:"bytes,6,opt,name=Explanation,proto3" json:"explanation,omitempty"`
+	// Warnings returned along with a partial response.
+	Warnings []string `protobuf:"bytes,7,rep,name=Warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *PrometheusResponse) Reset()      { *m = PrometheusResponse{} }
@@ -357,6 +390,20 @@ func (m *PrometheusResponse) GetHeaders() []*PrometheusResponseHeader {
 	return nil
 }
 
+func (m *PrometheusResponse) GetExplanation() *QueryExplanation {
+	if m != nil {
+		return m.Explanation
+	}
+	return nil
+}
+
+func (m *PrometheusResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type PrometheusData struct {
 	ResultType string         `protobuf:"bytes,1,opt,name=ResultType,proto3" json:"resultType"`
 	Result     []SampleStream `protobuf:"bytes,2,rep,name=Result,proto3" json:"result"`
@@ -596,7 +643,12 @@ type Options struct {
 	TotalShards          int32 `protobuf:"varint,3,opt,name=TotalShards,proto3" json:"TotalShards,omitempty"`
 	InstantSplitDisabled bool  `protobuf:"varint,4,opt,name=InstantSplitDisabled,proto3" json:"InstantSplitDisabled,omitempty"`
 	// Instant split by time interval unit stored in nanoseconds (time.Duration unit in int64)
-	InstantSplitInterval int64 `protobuf:"varint,5,opt,name=InstantSplitInterval,proto3" json:"InstantSplitInterval,omitempty"`
+	InstantSplitInterval int64       `protobuf:"varint,5,opt,name=InstantSplitInterval,proto3" json:"InstantSplitInterval,omitempty"`
+	Explain              ExplainMode `protobuf:"varint,6,opt,name=Explain,proto3,enum=queryrange.ExplainMode" json:"Explain,omitempty"`
+	// Value of the partial_response request parameter, forwarded to the queriers. Empty if not set.
+	PartialResponse string `protobuf:"bytes,7,opt,name=PartialResponse,proto3" json:"PartialResponse,omitempty"`
+	// Name of the priority class of the query, forwarded to the query-scheduler. Empty for ad-hoc queries.
+	Priority string `protobuf:"bytes,8,opt,name=Priority,proto3" json:"Priority,omitempty"`
 }
 
 func (m *Options) Reset()      { *m = Options{} }
@@ -666,6 +718,27 @@ func (m *Options) GetInstantSplitInterval() int64 {
 	return 0
 }
 
+func (m *Options) GetExplain() ExplainMode {
+	if m != nil {
+		return m.Explain
+	}
+	return ExplainMode_NONE
+}
+
+func (m *Options) GetPartialResponse() string {
+	if m != nil {
+		return m.PartialResponse
+	}
+	return ""
+}
+
+func (m *Options) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
 type Hints struct {
 	// Total number of queries that are expected to to be executed to serve the original request.
 	TotalQueries int32 `protobuf:"varint,1,opt,name=TotalQueries,proto3" json:"TotalQueries,omitempty"`
@@ -751,21 +824,31 @@ func (*Hints) XXX_OneofWrappers() []interface{} {
 	}
 }
 
-type QueryStatistics struct {
-	EstimatedSeriesCount uint64 `protobuf:"varint,1,opt,name=EstimatedSeriesCount,proto3" json:"EstimatedSeriesCount,omitempty"`
+type QueryExplanation struct {
+	// The explain mode, either "plan" or "analyze".
+	Mode string `protobuf:"bytes,1,opt,name=Mode,proto3" json:"mode"`
+	// The queries rewritten by the query-frontend middlewares, in the order they've been rewritten.
+	RewrittenQueries []RewrittenQuery `protobuf:"bytes,2,rep,name=RewrittenQueries,proto3" json:"rewrittenQueries"`
+	// The partial queries the query has been split and sharded into, sent to the queriers.
+	PartialQueries []PartialQuery `protobuf:"bytes,3,rep,name=PartialQueries,proto3" json:"partialQueries"`
+	// The time spent to execute the query in the query-frontend. Only set when the query is analyzed.
+	DurationSeconds float64 `protobuf:"fixed64,4,opt,name=DurationSeconds,proto3" json:"durationSeconds,omitempty"`
+	// The time spent in each step of the query-frontend middlewares, in the order the steps have been executed.
+	// Only set when the query is analyzed.
+	Steps []QueryStepTiming `protobuf:"bytes,5,rep,name=Steps,proto3" json:"steps,omitempty"`
 }
 
-func (m *QueryStatistics) Reset()      { *m = QueryStatistics{} }
-func (*QueryStatistics) ProtoMessage() {}
-func (*QueryStatistics) Descriptor() ([]byte, []int) {
+func (m *QueryExplanation) Reset()      { *m = QueryExplanation{} }
+func (*QueryExplanation) ProtoMessage() {}
+func (*QueryExplanation) Descriptor() ([]byte, []int) {
 	return fileDescriptor_4c16552f9fdb66d8, []int{10}
 }
-func (m *QueryStatistics) XXX_Unmarshal(b []byte) error {
+func (m *QueryExplanation) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *QueryStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *QueryExplanation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_QueryStatistics.Marshal(b, m, deterministic)
+		return xxx_messageInfo_QueryExplanation.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -775,49 +858,73 @@ func (m *QueryStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, err
 		return b[:n], nil
 	}
 }
-func (m *QueryStatistics) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_QueryStatistics.Merge(m, src)
+func (m *QueryExplanation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryExplanation.Merge(m, src)
 }
-func (m *QueryStatistics) XXX_Size() int {
+func (m *QueryExplanation) XXX_Size() int {
 	return m.Size()
 }
-func (m *QueryStatistics) XXX_DiscardUnknown() {
-	xxx_messageInfo_QueryStatistics.DiscardUnknown(m)
+func (m *QueryExplanation) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryExplanation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_QueryStatistics proto.InternalMessageInfo
+var xxx_messageInfo_QueryExplanation proto.InternalMessageInfo
 
-func (m *QueryStatistics) GetEstimatedSeriesCount() uint64 {
+func (m *QueryExplanation) GetMode() string {
 	if m != nil {
-		return m.EstimatedSeriesCount
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *QueryExplanation) GetRewrittenQueries() []RewrittenQuery {
+	if m != nil {
+		return m.RewrittenQueries
+	}
+	return nil
+}
+
+func (m *QueryExplanation) GetPartialQueries() []PartialQuery {
+	if m != nil {
+		return m.PartialQueries
+	}
+	return nil
+}
+
+func (m *QueryExplanation) GetDurationSeconds() float64 {
+	if m != nil {
+		return m.DurationSeconds
 	}
 	return 0
 }
 
-// CachedHTTPResponse holds a generic HTTP response in the query results cache.
-type CachedHTTPResponse struct {
-	// cacheKey contains the non-hashed cache key, used to guarantee there haven't
-	// been any hashed cache key collision.
-	CacheKey string `protobuf:"bytes,1,opt,name=cache_key,json=cacheKey,proto3" json:"cache_key,omitempty"`
-	// statusCode contains the HTTP response status code.
-	StatusCode int32 `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
-	// headers contains the HTTP response headers.
-	Headers []*CachedHTTPHeader `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty"`
-	// body contains the HTTP response body.
-	Body []byte `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+func (m *QueryExplanation) GetSteps() []QueryStepTiming {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
 }
 
-func (m *CachedHTTPResponse) Reset()      { *m = CachedHTTPResponse{} }
-func (*CachedHTTPResponse) ProtoMessage() {}
-func (*CachedHTTPResponse) Descriptor() ([]byte, []int) {
+type QueryStepTiming struct {
+	// The name of the step, as in the method label of the cortex_frontend_query_range_duration_seconds metric.
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"name"`
+	// The number of times the step has been executed, eg. once for each split query for the steps after the split.
+	Calls int32 `protobuf:"varint,2,opt,name=Calls,proto3" json:"calls"`
+	// The time spent in the step and the following ones, summed across calls.
+	DurationSeconds float64 `protobuf:"fixed64,3,opt,name=DurationSeconds,proto3" json:"durationSeconds"`
+}
+
+func (m *QueryStepTiming) Reset()      { *m = QueryStepTiming{} }
+func (*QueryStepTiming) ProtoMessage() {}
+func (*QueryStepTiming) Descriptor() ([]byte, []int) {
 	return fileDescriptor_4c16552f9fdb66d8, []int{11}
 }
-func (m *CachedHTTPResponse) XXX_Unmarshal(b []byte) error {
+func (m *QueryStepTiming) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *CachedHTTPResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *QueryStepTiming) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_CachedHTTPResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_QueryStepTiming.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -827,63 +934,59 @@ func (m *CachedHTTPResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *CachedHTTPResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CachedHTTPResponse.Merge(m, src)
+func (m *QueryStepTiming) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStepTiming.Merge(m, src)
 }
-func (m *CachedHTTPResponse) XXX_Size() int {
+func (m *QueryStepTiming) XXX_Size() int {
 	return m.Size()
 }
-func (m *CachedHTTPResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_CachedHTTPResponse.DiscardUnknown(m)
+func (m *QueryStepTiming) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStepTiming.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CachedHTTPResponse proto.InternalMessageInfo
+var xxx_messageInfo_QueryStepTiming proto.InternalMessageInfo
 
-func (m *CachedHTTPResponse) GetCacheKey() string {
+func (m *QueryStepTiming) GetName() string {
 	if m != nil {
-		return m.CacheKey
+		return m.Name
 	}
 	return ""
 }
 
-func (m *CachedHTTPResponse) GetStatusCode() int32 {
+func (m *QueryStepTiming) GetCalls() int32 {
 	if m != nil {
-		return m.StatusCode
+		return m.Calls
 	}
 	return 0
 }
 
-func (m *CachedHTTPResponse) GetHeaders() []*CachedHTTPHeader {
-	if m != nil {
-		return m.Headers
-	}
-	return nil
-}
-
-func (m *CachedHTTPResponse) GetBody() []byte {
+func (m *QueryStepTiming) GetDurationSeconds() float64 {
 	if m != nil {
-		return m.Body
+		return m.DurationSeconds
 	}
-	return nil
+	return 0
 }
 
-// CachedHTTPHeader holds a HTTP header.
-type CachedHTTPHeader struct {
-	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+type RewrittenQuery struct {
+	// The middleware which has rewritten the query.
+	Middleware string `protobuf:"bytes,1,opt,name=Middleware,proto3" json:"middleware"`
+	Original   string `protobuf:"bytes,2,opt,name=Original,proto3" json:"original"`
+	Rewritten  string `protobuf:"bytes,3,opt,name=Rewritten,proto3" json:"rewritten"`
+	// The number of partial queries embedded in the rewritten query.
+	PartialQueries int32 `protobuf:"varint,4,opt,name=PartialQueries,proto3" json:"partialQueries"`
 }
 
-func (m *CachedHTTPHeader) Reset()      { *m = CachedHTTPHeader{} }
-func (*CachedHTTPHeader) ProtoMessage() {}
-func (*CachedHTTPHeader) Descriptor() ([]byte, []int) {
+func (m *RewrittenQuery) Reset()      { *m = RewrittenQuery{} }
+func (*RewrittenQuery) ProtoMessage() {}
+func (*RewrittenQuery) Descriptor() ([]byte, []int) {
 	return fileDescriptor_4c16552f9fdb66d8, []int{12}
 }
-func (m *CachedHTTPHeader) XXX_Unmarshal(b []byte) error {
+func (m *RewrittenQuery) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *CachedHTTPHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *RewrittenQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_CachedHTTPHeader.Marshal(b, m, deterministic)
+		return xxx_messageInfo_RewrittenQuery.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -893,375 +996,642 @@ func (m *CachedHTTPHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *CachedHTTPHeader) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CachedHTTPHeader.Merge(m, src)
+func (m *RewrittenQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RewrittenQuery.Merge(m, src)
 }
-func (m *CachedHTTPHeader) XXX_Size() int {
+func (m *RewrittenQuery) XXX_Size() int {
 	return m.Size()
 }
-func (m *CachedHTTPHeader) XXX_DiscardUnknown() {
-	xxx_messageInfo_CachedHTTPHeader.DiscardUnknown(m)
+func (m *RewrittenQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RewrittenQuery.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CachedHTTPHeader proto.InternalMessageInfo
+var xxx_messageInfo_RewrittenQuery proto.InternalMessageInfo
 
-func (m *CachedHTTPHeader) GetName() string {
+func (m *RewrittenQuery) GetMiddleware() string {
 	if m != nil {
-		return m.Name
+		return m.Middleware
 	}
 	return ""
 }
 
-func (m *CachedHTTPHeader) GetValue() string {
+func (m *RewrittenQuery) GetOriginal() string {
 	if m != nil {
-		return m.Value
+		return m.Original
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*PrometheusRangeQueryRequest)(nil), "queryrange.PrometheusRangeQueryRequest")
-	proto.RegisterType((*PrometheusInstantQueryRequest)(nil), "queryrange.PrometheusInstantQueryRequest")
-	proto.RegisterType((*PrometheusResponseHeader)(nil), "queryrange.PrometheusResponseHeader")
-	proto.RegisterType((*PrometheusResponse)(nil), "queryrange.PrometheusResponse")
-	proto.RegisterType((*PrometheusData)(nil), "queryrange.PrometheusData")
-	proto.RegisterType((*SampleStream)(nil), "queryrange.SampleStream")
-	proto.RegisterType((*CachedResponse)(nil), "queryrange.CachedResponse")
-	proto.RegisterType((*Extent)(nil), "queryrange.Extent")
-	proto.RegisterType((*Options)(nil), "queryrange.Options")
-	proto.RegisterType((*Hints)(nil), "queryrange.Hints")
-	proto.RegisterType((*QueryStatistics)(nil), "queryrange.QueryStatistics")
-	proto.RegisterType((*CachedHTTPResponse)(nil), "queryrange.CachedHTTPResponse")
-	proto.RegisterType((*CachedHTTPHeader)(nil), "queryrange.CachedHTTPHeader")
-}
-
-func init() { proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8) }
-
-var fileDescriptor_4c16552f9fdb66d8 = []byte{
-	// 1202 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4d, 0x8f, 0x1b, 0x45,
-	0x13, 0xf6, 0xd8, 0xe3, 0xaf, 0xf2, 0xbe, 0xbb, 0xfb, 0xf6, 0x2e, 0x62, 0x36, 0x21, 0x33, 0xd6,
-	0x28, 0x87, 0x05, 0x25, 0x5e, 0xd8, 0x00, 0x07, 0x04, 0x88, 0xcc, 0x66, 0xd1, 0x86, 0xcf, 0xa5,
-	0x77, 0xc5, 0x81, 0xcb, 0xaa, 0xed, 0xe9, 0xd8, 0x43, 0xe6, 0x2b, 0xd3, 0xed, 0x10, 0xdf, 0x10,
-	0x3f, 0x00, 0x71, 0xe4, 0xc4, 0x0d, 0x89, 0x5f, 0xc0, 0x6f, 0xc8, 0x31, 0xdc, 0xa2, 0x1c, 0x0c,
-	0x71, 0x84, 0x84, 0x7c, 0xca, 0x4f, 0x40, 0x5d, 0x3d, 0x63, 0xcf, 0x7e, 0x20, 0xc2, 0xc5, 0xae,
-	0xae, 0x7a, 0xaa, 0xfa, 0xa9, 0x9a, 0xee, 0xa7, 0xa1, 0x13, 0x25, 0x3e, 0x0f, 0x7b, 0x69, 0x96,
-	0xc8, 0x84, 0xc0, 0xbd, 0x31, 0xcf, 0x26, 0x19, 0x8b, 0x87, 0xfc, 0xd2, 0xf5, 0x61, 0x20, 0x47,
-	0xe3, 0x7e, 0x6f, 0x90, 0x44, 0x3b, 0xc3, 0x64, 0x98, 0xec, 0x20, 0xa4, 0x3f, 0xbe, 0x83, 0x2b,
-	0x5c, 0xa0, 0xa5, 0x53, 0x2f, 0xd9, 0xc3, 0x24, 0x19, 0x86, 0x7c, 0x89, 0xf2, 0xc7, 0x19, 0x93,
-	0x41, 0x12, 0xe7, 0xf1, 0xd7, 0xcb, 0xe5, 0x32, 0x76, 0x87, 0xc5, 0x6c, 0x27, 0x0a, 0xa2, 0x20,
-	0xdb, 0x49, 0xef, 0x0e, 0xb5, 0x95, 0xf6, 0xf5, 0x7f, 0x9e, 0xb1, 0x75, 0xb6, 0x22, 0x8b, 0x27,
-	0x3a, 0xe4, 0xfe, 0x5a, 0x85, 0xcb, 0x87, 0x59, 0x12, 0x71, 0x39, 0xe2, 0x63, 0x41, 0x15, 0xdf,
-	0x2f, 0x14, 0x73, 0xca, 0xef, 0x8d, 0xb9, 0x90, 0x84, 0x80, 0x99, 0x32, 0x39, 0xb2, 0x8c, 0xae,
-	0xb1, 0xdd, 0xa6, 0x68, 0x93, 0x4d, 0xa8, 0x0b, 0xc9, 0x32, 0x69, 0x55, 0xbb, 0xc6, 0x76, 0x8d,
-	0xea, 0x05, 0x59, 0x87, 0x1a, 0x8f, 0x7d, 0xab, 0x86, 0x3e, 0x65, 0xaa, 0x5c, 0x21, 0x79, 0x6a,
-	0x99, 0xe8, 0x42, 0x9b, 0xbc, 0x07, 0x4d, 0x19, 0x44, 0x3c, 0x19, 0x4b, 0xab, 0xde, 0x35, 0xb6,
-	0x3b, 0xbb, 0x5b, 0x3d, 0x4d, 0xae, 0x57, 0x90, 0xeb, 0xdd, 0xca, 0xdb, 0xf5, 0x5a, 0x0f, 0xa7,
-	0x4e, 0xe5, 0xc7, 0xdf, 0x1d, 0x83, 0x16, 0x39, 0x6a, 0x6b, 0x1c, 0xac, 0xd5, 0x40, 0x3e, 0x7a,
-	0x41, 0x6e, 0x40, 0x33, 0x49, 0x55, 0x8a, 0xb0, 0x9a, 0x58, 0x74, 0xa3, 0xb7, 0x1c, 0x7f, 0xef,
-	0x73, 0x1d, 0xf2, 0x4c, 0x55, 0x8e, 0x16, 0x48, 0xb2, 0x0a, 0xd5, 0xc0, 0xb7, 0x5a, 0xc8, 0xad,
-	0x1a, 0xf8, 0xe4, 0x3a, 0xd4, 0x47, 0x41, 0x2c, 0x85, 0xd5, 0xc6, 0x12, 0xff, 0x2f, 0x97, 0x38,
-	0x50, 0x01, 0x2c, 0x60, 0x50, 0x8d, 0x72, 0x7f, 0x33, 0xe0, 0xca, 0x72, 0x70, 0xb7, 0x63, 0x21,
-	0x59, 0x2c, 0xff, 0x75, 0x74, 0x04, 0x4c, 0xd5, 0x4a, 0x3e, 0x39, 0xb4, 0x97, 0x3d, 0xd5, 0xfe,
-	0xa1, 0x27, 0xf3, 0x3f, 0xf6, 0x54, 0x3f, 0xdf, 0x53, 0xe3, 0x85, 0x7a, 0x3a, 0x06, 0xab, 0x74,
-	0x16, 0xb8, 0x48, 0x93, 0x58, 0xf0, 0x03, 0xce, 0x7c, 0x9e, 0x91, 0x2d, 0x30, 0x3f, 0x63, 0x11,
-	0xd7, 0xdd, 0x78, 0xf5, 0xf9, 0xd4, 0x31, 0xae, 0x53, 0x74, 0x91, 0x2b, 0xd0, 0xf8, 0x92, 0x85,
-	0x63, 0x2e, 0xac, 0x6a, 0xb7, 0xb6, 0x0c, 0xe6, 0x4e, 0xf7, 0xe7, 0x2a, 0x90, 0xf3, 0x65, 0x89,
-	0x0b, 0x8d, 0x23, 0xc9, 0xe4, 0x58, 0xe4, 0x25, 0x61, 0x3e, 0x75, 0x1a, 0x02, 0x3d, 0x34, 0x8f,
-	0x10, 0x0f, 0xcc, 0x5b, 0x4c, 0x32, 0x1c, 0x57, 0x67, 0xf7, 0x52, 0x99, 0xfe, 0xb2, 0xa2, 0x42,
-	0x78, 0x64, 0x3e, 0x75, 0x56, 0x7d, 0x26, 0xd9, 0xb5, 0x24, 0x0a, 0x24, 0x8f, 0x52, 0x39, 0xa1,
-	0x98, 0x4b, 0xde, 0x82, 0xf6, 0x7e, 0x96, 0x25, 0xd9, 0xf1, 0x24, 0xe5, 0x7a, 0xc4, 0xde, 0xcb,
-	0xf3, 0xa9, 0xb3, 0xc1, 0x0b, 0x67, 0x29, 0x63, 0x89, 0x24, 0xaf, 0x42, 0x1d, 0x17, 0x38, 0xfd,
-	0xb6, 0xb7, 0x31, 0x9f, 0x3a, 0x6b, 0x98, 0x52, 0x82, 0x6b, 0x04, 0xd9, 0x87, 0xa6, 0x1e, 0x92,
-	0xb0, 0xea, 0xdd, 0xda, 0x76, 0x67, 0xf7, 0xea, 0xc5, 0x44, 0x4f, 0x4f, 0xb4, 0x18, 0x53, 0x91,
-	0xeb, 0x7e, 0x67, 0xc0, 0xea, 0xe9, 0xae, 0x48, 0x0f, 0x80, 0x72, 0x31, 0x0e, 0x25, 0x92, 0xd7,
-	0x73, 0x5a, 0x9d, 0x4f, 0x1d, 0xc8, 0x16, 0x5e, 0x5a, 0x42, 0x90, 0x0f, 0xa0, 0xa1, 0x57, 0xf8,
-	0x25, 0x3a, 0xbb, 0x56, 0x99, 0xc8, 0x11, 0x8b, 0xd2, 0x90, 0x1f, 0xc9, 0x8c, 0xb3, 0xc8, 0x5b,
-	0x55, 0x07, 0x47, 0x4d, 0x5c, 0x57, 0xa2, 0x79, 0x9e, 0xfb, 0x7d, 0x15, 0x56, 0xca, 0x40, 0x92,
-	0x42, 0x23, 0x64, 0x7d, 0x1e, 0xaa, 0xcf, 0x54, 0xc3, 0x63, 0x38, 0x48, 0x32, 0xc9, 0x1f, 0xa4,
-	0xfd, 0xde, 0x27, 0xca, 0x7f, 0xc8, 0x82, 0xcc, 0xdb, 0x53, 0xd5, 0x9e, 0x4c, 0x9d, 0x37, 0x5e,
-	0x44, 0x9a, 0x74, 0xde, 0x4d, 0x9f, 0xa5, 0x92, 0x67, 0x8a, 0x42, 0xc4, 0x65, 0x16, 0x0c, 0x68,
-	0xbe, 0x0f, 0x79, 0x07, 0x9a, 0x02, 0x19, 0x88, 0xbc, 0x8b, 0xf5, 0xe5, 0x96, 0x9a, 0xda, 0x92,
-	0xfd, 0x7d, 0x3c, 0x62, 0xb4, 0x48, 0x20, 0x87, 0x00, 0xa3, 0x40, 0xc8, 0x64, 0x98, 0xb1, 0x48,
-	0x58, 0x35, 0x4c, 0x7f, 0x65, 0x99, 0xfe, 0x61, 0x98, 0x30, 0x79, 0x50, 0x00, 0x90, 0x3a, 0xc9,
-	0x4b, 0x95, 0xf2, 0x68, 0xc9, 0x76, 0xbf, 0x86, 0xd5, 0x3d, 0x36, 0x18, 0x71, 0x7f, 0x71, 0x70,
-	0xb7, 0xa0, 0x76, 0x97, 0x4f, 0xf2, 0xaf, 0xd1, 0x9c, 0x4f, 0x1d, 0xb5, 0xa4, 0xea, 0x47, 0xa9,
-	0x1b, 0x7f, 0x20, 0xb9, 0xba, 0x71, 0x9a, 0x3a, 0x29, 0x7f, 0x80, 0x7d, 0x0c, 0x79, 0x6b, 0xf9,
-	0x8e, 0x05, 0x94, 0x16, 0x86, 0xfb, 0xc4, 0x80, 0x86, 0x06, 0x11, 0xa7, 0xd0, 0x58, 0xb5, 0x4d,
-	0xcd, 0x6b, 0xcf, 0xa7, 0x8e, 0x76, 0x14, 0x72, 0xbb, 0xa5, 0xe5, 0x16, 0x85, 0x44, 0xb3, 0xe0,
-	0xb1, 0xaf, 0x75, 0xb7, 0x0b, 0x2d, 0x99, 0xb1, 0x01, 0x3f, 0x09, 0xfc, 0xfc, 0xf4, 0x16, 0x47,
-	0x0d, 0xdd, 0xb7, 0x7d, 0xf2, 0x3e, 0xb4, 0xb2, 0xbc, 0x9d, 0x5c, 0x86, 0x37, 0xcf, 0xc9, 0xf0,
-	0xcd, 0x78, 0xe2, 0xad, 0xcc, 0xa7, 0xce, 0x02, 0x49, 0x17, 0x16, 0xb9, 0x06, 0x04, 0xfb, 0x3a,
-	0x51, 0x02, 0x26, 0x24, 0x8b, 0xd2, 0x93, 0x48, 0x8b, 0x4c, 0x8d, 0xae, 0x63, 0xe4, 0xb8, 0x08,
-	0x7c, 0x2a, 0x3e, 0x32, 0x5b, 0xb5, 0x75, 0xd3, 0xfd, 0xd3, 0x80, 0x66, 0x2e, 0x5b, 0xe4, 0x2a,
-	0xfc, 0x0f, 0x87, 0x7a, 0x2b, 0x10, 0xac, 0x1f, 0x72, 0x1f, 0xbb, 0x6c, 0xd1, 0xd3, 0x4e, 0xf2,
-	0x1a, 0xac, 0x1f, 0x8d, 0x58, 0xe6, 0x07, 0xf1, 0x70, 0x01, 0xac, 0x22, 0xf0, 0x9c, 0x9f, 0x74,
-	0xa1, 0x73, 0x9c, 0x48, 0x16, 0x62, 0x40, 0xe0, 0x3d, 0xaf, 0xd3, 0xb2, 0x8b, 0xec, 0xc2, 0x66,
-	0xae, 0xd2, 0x47, 0x69, 0x18, 0xc8, 0x45, 0x45, 0x13, 0x2b, 0x5e, 0x18, 0x3b, 0x9b, 0x73, 0x3b,
-	0x96, 0x3c, 0xbb, 0xcf, 0xc2, 0x5c, 0x61, 0x2f, 0x8c, 0xb9, 0x0f, 0xa0, 0x8e, 0xd2, 0x4a, 0x5c,
-	0x58, 0xc1, 0xfd, 0xd5, 0xa3, 0x10, 0x70, 0x2d, 0x73, 0x75, 0x7a, 0xca, 0x47, 0xde, 0x84, 0xcd,
-	0x7d, 0x21, 0x83, 0x88, 0x49, 0xee, 0x1f, 0xa1, 0x6b, 0x2f, 0x19, 0xc7, 0xfa, 0x65, 0x35, 0x0f,
-	0x2a, 0xf4, 0xc2, 0xa8, 0xf7, 0x12, 0x6c, 0xec, 0x61, 0xff, 0x2c, 0x0c, 0xe4, 0xa4, 0x80, 0xb8,
-	0xfb, 0xb0, 0x86, 0x0f, 0x90, 0x12, 0xcf, 0x40, 0xc8, 0x60, 0x80, 0x4d, 0x5f, 0x58, 0x5f, 0x71,
-	0x31, 0x2f, 0xae, 0xee, 0xfe, 0x64, 0x00, 0xd1, 0x47, 0xfe, 0xe0, 0xf8, 0xf8, 0x70, 0x71, 0xec,
-	0x2f, 0x43, 0x7b, 0xa0, 0xbc, 0x27, 0x8b, 0xc3, 0x4f, 0x5b, 0xe8, 0xf8, 0x98, 0x4f, 0x88, 0x03,
-	0x1d, 0x2d, 0xdd, 0x27, 0x83, 0xc4, 0xd7, 0xcf, 0x5b, 0x9d, 0x82, 0x76, 0xed, 0x25, 0x3e, 0x27,
-	0x6f, 0x43, 0x73, 0x94, 0x6b, 0x64, 0x71, 0x2b, 0x4b, 0x37, 0x63, 0xb9, 0x9d, 0x16, 0x43, 0x5a,
-	0x80, 0xd5, 0x83, 0xd9, 0x4f, 0xfc, 0x09, 0x7e, 0xa5, 0x15, 0x8a, 0xb6, 0xfb, 0x2e, 0xac, 0x9f,
-	0x4d, 0x50, 0xb8, 0x78, 0xf1, 0x3c, 0x51, 0xb4, 0xd5, 0xc3, 0x8a, 0xfa, 0x80, 0x74, 0xda, 0x54,
-	0x2f, 0xbc, 0xfd, 0x47, 0x4f, 0xed, 0xca, 0xe3, 0xa7, 0x76, 0xe5, 0xf9, 0x53, 0xdb, 0xf8, 0x76,
-	0x66, 0x1b, 0xbf, 0xcc, 0x6c, 0xe3, 0xe1, 0xcc, 0x36, 0x1e, 0xcd, 0x6c, 0xe3, 0x8f, 0x99, 0x6d,
-	0xfc, 0x35, 0xb3, 0x2b, 0xcf, 0x67, 0xb6, 0xf1, 0xc3, 0x33, 0xbb, 0xf2, 0xe8, 0x99, 0x5d, 0x79,
-	0xfc, 0xcc, 0xae, 0x7c, 0xb5, 0x86, 0x6c, 0xa3, 0xc0, 0xf7, 0x43, 0xfe, 0x0d, 0xcb, 0x78, 0xbf,
-	0x81, 0x17, 0xe5, 0xc6, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xf2, 0xd9, 0xcc, 0xb5, 0xf6, 0x09,
-	0x00, 0x00,
+func (m *RewrittenQuery) GetRewritten() string {
+	if m != nil {
+		return m.Rewritten
+	}
+	return ""
 }
 
-func (this *PrometheusRangeQueryRequest) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+func (m *RewrittenQuery) GetPartialQueries() int32 {
+	if m != nil {
+		return m.PartialQueries
 	}
+	return 0
+}
 
-	that1, ok := that.(*PrometheusRangeQueryRequest)
-	if !ok {
-		that2, ok := that.(PrometheusRangeQueryRequest)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+type PartialQuery struct {
+	Query string `protobuf:"bytes,1,opt,name=Query,proto3" json:"query"`
+	// Start and end timestamps of the partial query, in milliseconds. Both are equal for instant queries.
+	Start int64 `protobuf:"varint,2,opt,name=Start,proto3" json:"start"`
+	End   int64 `protobuf:"varint,3,opt,name=End,proto3" json:"end"`
+	// Step of the partial query, in milliseconds. Not set for instant queries.
+	Step int64 `protobuf:"varint,4,opt,name=Step,proto3" json:"step,omitempty"`
+	// The following fields are only set when the query is analyzed.
+	DurationSeconds    float64                   `protobuf:"fixed64,5,opt,name=DurationSeconds,proto3" json:"durationSeconds,omitempty"`
+	FetchedSeriesCount uint64                    `protobuf:"varint,6,opt,name=FetchedSeriesCount,proto3" json:"fetchedSeriesCount,omitempty"`
+	FetchedChunkBytes  uint64                    `protobuf:"varint,7,opt,name=FetchedChunkBytes,proto3" json:"fetchedChunkBytes,omitempty"`
+	Stores             []PartialQueryStoreStats  `protobuf:"bytes,8,rep,name=Stores,proto3" json:"stores,omitempty"`
+	Error              string                    `protobuf:"bytes,9,opt,name=Error,proto3" json:"error,omitempty"`
+	Tenants            []PartialQueryTenantStats `protobuf:"bytes,10,rep,name=Tenants,proto3" json:"tenants,omitempty"`
+}
+
+func (m *PartialQuery) Reset()      { *m = PartialQuery{} }
+func (*PartialQuery) ProtoMessage() {}
+func (*PartialQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{13}
+}
+func (m *PartialQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PartialQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PartialQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Path != that1.Path {
-		return false
-	}
-	if this.Start != that1.Start {
-		return false
+}
+func (m *PartialQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartialQuery.Merge(m, src)
+}
+func (m *PartialQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *PartialQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartialQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartialQuery proto.InternalMessageInfo
+
+func (m *PartialQuery) GetQuery() string {
+	if m != nil {
+		return m.Query
 	}
-	if this.End != that1.End {
-		return false
+	return ""
+}
+
+func (m *PartialQuery) GetStart() int64 {
+	if m != nil {
+		return m.Start
 	}
-	if this.Step != that1.Step {
-		return false
+	return 0
+}
+
+func (m *PartialQuery) GetEnd() int64 {
+	if m != nil {
+		return m.End
 	}
-	if this.Timeout != that1.Timeout {
-		return false
+	return 0
+}
+
+func (m *PartialQuery) GetStep() int64 {
+	if m != nil {
+		return m.Step
 	}
-	if this.Query != that1.Query {
-		return false
+	return 0
+}
+
+func (m *PartialQuery) GetDurationSeconds() float64 {
+	if m != nil {
+		return m.DurationSeconds
 	}
-	if !this.Options.Equal(&that1.Options) {
-		return false
+	return 0
+}
+
+func (m *PartialQuery) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
 	}
-	if this.Id != that1.Id {
-		return false
+	return 0
+}
+
+func (m *PartialQuery) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
 	}
-	if !this.Hints.Equal(that1.Hints) {
-		return false
+	return 0
+}
+
+func (m *PartialQuery) GetStores() []PartialQueryStoreStats {
+	if m != nil {
+		return m.Stores
 	}
-	return true
+	return nil
 }
-func (this *PrometheusInstantQueryRequest) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *PartialQuery) GetError() string {
+	if m != nil {
+		return m.Error
 	}
+	return ""
+}
 
-	that1, ok := that.(*PrometheusInstantQueryRequest)
-	if !ok {
-		that2, ok := that.(PrometheusInstantQueryRequest)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *PartialQuery) GetTenants() []PartialQueryTenantStats {
+	if m != nil {
+		return m.Tenants
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Path != that1.Path {
-		return false
-	}
-	if this.Time != that1.Time {
-		return false
-	}
-	if this.Query != that1.Query {
-		return false
-	}
-	if !this.Options.Equal(&that1.Options) {
-		return false
-	}
-	if this.Id != that1.Id {
-		return false
-	}
-	if !this.Hints.Equal(that1.Hints) {
-		return false
-	}
-	return true
+	return nil
 }
-func (this *PrometheusResponseHeader) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*PrometheusResponseHeader)
-	if !ok {
-		that2, ok := that.(PrometheusResponseHeader)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Name != that1.Name {
-		return false
-	}
-	if len(this.Values) != len(that1.Values) {
-		return false
-	}
-	for i := range this.Values {
-		if this.Values[i] != that1.Values[i] {
-			return false
-		}
-	}
-	return true
+type PartialQueryStoreStats struct {
+	// The component the store belongs to (eg. ingester or store-gateway).
+	Component          string  `protobuf:"bytes,1,opt,name=Component,proto3" json:"component"`
+	Address            string  `protobuf:"bytes,2,opt,name=Address,proto3" json:"address"`
+	FetchedSeriesCount uint64  `protobuf:"varint,3,opt,name=FetchedSeriesCount,proto3" json:"fetchedSeriesCount"`
+	FetchedChunkBytes  uint64  `protobuf:"varint,4,opt,name=FetchedChunkBytes,proto3" json:"fetchedChunkBytes"`
+	LatencySeconds     float64 `protobuf:"fixed64,5,opt,name=LatencySeconds,proto3" json:"latencySeconds"`
 }
-func (this *PrometheusResponse) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*PrometheusResponse)
-	if !ok {
-		that2, ok := that.(PrometheusResponse)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+func (m *PartialQueryStoreStats) Reset()      { *m = PartialQueryStoreStats{} }
+func (*PartialQueryStoreStats) ProtoMessage() {}
+func (*PartialQueryStoreStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{14}
+}
+func (m *PartialQueryStoreStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PartialQueryStoreStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PartialQueryStoreStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Status != that1.Status {
-		return false
+}
+func (m *PartialQueryStoreStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartialQueryStoreStats.Merge(m, src)
+}
+func (m *PartialQueryStoreStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *PartialQueryStoreStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartialQueryStoreStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartialQueryStoreStats proto.InternalMessageInfo
+
+func (m *PartialQueryStoreStats) GetComponent() string {
+	if m != nil {
+		return m.Component
 	}
-	if !this.Data.Equal(that1.Data) {
-		return false
+	return ""
+}
+
+func (m *PartialQueryStoreStats) GetAddress() string {
+	if m != nil {
+		return m.Address
 	}
-	if this.ErrorType != that1.ErrorType {
-		return false
+	return ""
+}
+
+func (m *PartialQueryStoreStats) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
 	}
-	if this.Error != that1.Error {
-		return false
+	return 0
+}
+
+func (m *PartialQueryStoreStats) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
 	}
-	if len(this.Headers) != len(that1.Headers) {
-		return false
+	return 0
+}
+
+func (m *PartialQueryStoreStats) GetLatencySeconds() float64 {
+	if m != nil {
+		return m.LatencySeconds
 	}
-	for i := range this.Headers {
-		if !this.Headers[i].Equal(that1.Headers[i]) {
-			return false
+	return 0
+}
+
+// PartialQueryTenantStats holds the statistics about the sub-queries run against a single tenant of a tenant federated query.
+type PartialQueryTenantStats struct {
+	TenantID           string `protobuf:"bytes,1,opt,name=TenantID,proto3" json:"tenantID"`
+	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=FetchedSeriesCount,proto3" json:"fetchedSeriesCount"`
+	FetchedChunkBytes  uint64 `protobuf:"varint,3,opt,name=FetchedChunkBytes,proto3" json:"fetchedChunkBytes"`
+	// The time the tenant sub-queries have been queued in the querier, waiting for a free worker.
+	QueueTimeSeconds float64 `protobuf:"fixed64,4,opt,name=QueueTimeSeconds,proto3" json:"queueTimeSeconds"`
+}
+
+func (m *PartialQueryTenantStats) Reset()      { *m = PartialQueryTenantStats{} }
+func (*PartialQueryTenantStats) ProtoMessage() {}
+func (*PartialQueryTenantStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{15}
+}
+func (m *PartialQueryTenantStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PartialQueryTenantStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PartialQueryTenantStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return true
 }
-func (this *PrometheusData) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+func (m *PartialQueryTenantStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartialQueryTenantStats.Merge(m, src)
+}
+func (m *PartialQueryTenantStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *PartialQueryTenantStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartialQueryTenantStats.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*PrometheusData)
-	if !ok {
-		that2, ok := that.(PrometheusData)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+var xxx_messageInfo_PartialQueryTenantStats proto.InternalMessageInfo
+
+func (m *PartialQueryTenantStats) GetTenantID() string {
+	if m != nil {
+		return m.TenantID
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return ""
+}
+
+func (m *PartialQueryTenantStats) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
 	}
-	if this.ResultType != that1.ResultType {
-		return false
+	return 0
+}
+
+func (m *PartialQueryTenantStats) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
 	}
-	if len(this.Result) != len(that1.Result) {
-		return false
+	return 0
+}
+
+func (m *PartialQueryTenantStats) GetQueueTimeSeconds() float64 {
+	if m != nil {
+		return m.QueueTimeSeconds
 	}
-	for i := range this.Result {
-		if !this.Result[i].Equal(&that1.Result[i]) {
-			return false
+	return 0
+}
+
+type QueryStatistics struct {
+	EstimatedSeriesCount uint64 `protobuf:"varint,1,opt,name=EstimatedSeriesCount,proto3" json:"EstimatedSeriesCount,omitempty"`
+}
+
+func (m *QueryStatistics) Reset()      { *m = QueryStatistics{} }
+func (*QueryStatistics) ProtoMessage() {}
+func (*QueryStatistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{16}
+}
+func (m *QueryStatistics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStatistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStatistics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return true
 }
-func (this *SampleStream) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+func (m *QueryStatistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStatistics.Merge(m, src)
+}
+func (m *QueryStatistics) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStatistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStatistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStatistics proto.InternalMessageInfo
+
+func (m *QueryStatistics) GetEstimatedSeriesCount() uint64 {
+	if m != nil {
+		return m.EstimatedSeriesCount
 	}
+	return 0
+}
 
-	that1, ok := that.(*SampleStream)
-	if !ok {
-		that2, ok := that.(SampleStream)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// CachedHTTPResponse holds a generic HTTP response in the query results cache.
+type CachedHTTPResponse struct {
+	// cacheKey contains the non-hashed cache key, used to guarantee there haven't
+	// been any hashed cache key collision.
+	CacheKey string `protobuf:"bytes,1,opt,name=cache_key,json=cacheKey,proto3" json:"cache_key,omitempty"`
+	// statusCode contains the HTTP response status code.
+	StatusCode int32 `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	// headers contains the HTTP response headers.
+	Headers []*CachedHTTPHeader `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty"`
+	// body contains the HTTP response body.
+	Body []byte `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (m *CachedHTTPResponse) Reset()      { *m = CachedHTTPResponse{} }
+func (*CachedHTTPResponse) ProtoMessage() {}
+func (*CachedHTTPResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{17}
+}
+func (m *CachedHTTPResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CachedHTTPResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CachedHTTPResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+}
+func (m *CachedHTTPResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CachedHTTPResponse.Merge(m, src)
+}
+func (m *CachedHTTPResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CachedHTTPResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CachedHTTPResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CachedHTTPResponse proto.InternalMessageInfo
+
+func (m *CachedHTTPResponse) GetCacheKey() string {
+	if m != nil {
+		return m.CacheKey
 	}
-	if len(this.Labels) != len(that1.Labels) {
-		return false
+	return ""
+}
+
+func (m *CachedHTTPResponse) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
 	}
-	for i := range this.Labels {
-		if !this.Labels[i].Equal(that1.Labels[i]) {
-			return false
-		}
+	return 0
+}
+
+func (m *CachedHTTPResponse) GetHeaders() []*CachedHTTPHeader {
+	if m != nil {
+		return m.Headers
 	}
-	if len(this.Samples) != len(that1.Samples) {
-		return false
+	return nil
+}
+
+func (m *CachedHTTPResponse) GetBody() []byte {
+	if m != nil {
+		return m.Body
 	}
-	for i := range this.Samples {
-		if !this.Samples[i].Equal(&that1.Samples[i]) {
-			return false
+	return nil
+}
+
+// CachedHTTPHeader holds a HTTP header.
+type CachedHTTPHeader struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *CachedHTTPHeader) Reset()      { *m = CachedHTTPHeader{} }
+func (*CachedHTTPHeader) ProtoMessage() {}
+func (*CachedHTTPHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{18}
+}
+func (m *CachedHTTPHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CachedHTTPHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CachedHTTPHeader.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(this.Histograms) != len(that1.Histograms) {
-		return false
+}
+func (m *CachedHTTPHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CachedHTTPHeader.Merge(m, src)
+}
+func (m *CachedHTTPHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *CachedHTTPHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_CachedHTTPHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CachedHTTPHeader proto.InternalMessageInfo
+
+func (m *CachedHTTPHeader) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	for i := range this.Histograms {
-		if !this.Histograms[i].Equal(&that1.Histograms[i]) {
-			return false
-		}
+	return ""
+}
+
+func (m *CachedHTTPHeader) GetValue() string {
+	if m != nil {
+		return m.Value
 	}
-	return true
+	return ""
 }
-func (this *CachedResponse) Equal(that interface{}) bool {
+
+func init() {
+	proto.RegisterEnum("queryrange.ExplainMode", ExplainMode_name, ExplainMode_value)
+	proto.RegisterType((*PrometheusRangeQueryRequest)(nil), "queryrange.PrometheusRangeQueryRequest")
+	proto.RegisterType((*PrometheusInstantQueryRequest)(nil), "queryrange.PrometheusInstantQueryRequest")
+	proto.RegisterType((*PrometheusResponseHeader)(nil), "queryrange.PrometheusResponseHeader")
+	proto.RegisterType((*PrometheusResponse)(nil), "queryrange.PrometheusResponse")
+	proto.RegisterType((*PrometheusData)(nil), "queryrange.PrometheusData")
+	proto.RegisterType((*SampleStream)(nil), "queryrange.SampleStream")
+	proto.RegisterType((*CachedResponse)(nil), "queryrange.CachedResponse")
+	proto.RegisterType((*Extent)(nil), "queryrange.Extent")
+	proto.RegisterType((*Options)(nil), "queryrange.Options")
+	proto.RegisterType((*Hints)(nil), "queryrange.Hints")
+	proto.RegisterType((*QueryExplanation)(nil), "queryrange.QueryExplanation")
+	proto.RegisterType((*QueryStepTiming)(nil), "queryrange.QueryStepTiming")
+	proto.RegisterType((*RewrittenQuery)(nil), "queryrange.RewrittenQuery")
+	proto.RegisterType((*PartialQuery)(nil), "queryrange.PartialQuery")
+	proto.RegisterType((*PartialQueryStoreStats)(nil), "queryrange.PartialQueryStoreStats")
+	proto.RegisterType((*PartialQueryTenantStats)(nil), "queryrange.PartialQueryTenantStats")
+	proto.RegisterType((*QueryStatistics)(nil), "queryrange.QueryStatistics")
+	proto.RegisterType((*CachedHTTPResponse)(nil), "queryrange.CachedHTTPResponse")
+	proto.RegisterType((*CachedHTTPHeader)(nil), "queryrange.CachedHTTPHeader")
+}
+
+func init() { proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8) }
+
+var fileDescriptor_4c16552f9fdb66d8 = []byte{
+	// 1921 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xcd, 0x73, 0x1b, 0x49,
+	0x15, 0xf7, 0x48, 0x23, 0x4b, 0x7a, 0xf2, 0xca, 0xda, 0x8e, 0x93, 0xc8, 0x4e, 0xa2, 0x71, 0x0d,
+	0x0b, 0x65, 0x96, 0x8d, 0xc3, 0x7a, 0x17, 0x0e, 0x29, 0x96, 0x5a, 0x8f, 0xa3, 0xe0, 0x2c, 0x89,
+	0x63, 0x5a, 0x2e, 0xd8, 0xdd, 0x8b, 0x69, 0x6b, 0x3a, 0xd2, 0x10, 0xcd, 0x47, 0x66, 0x5a, 0x9b,
+	0xe8, 0x06, 0x9c, 0x38, 0x01, 0xc5, 0x89, 0x13, 0x17, 0x2e, 0xfc, 0x01, 0xc0, 0xdf, 0xb0, 0xc7,
+	0x50, 0x5c, 0x52, 0x7b, 0x18, 0x88, 0x73, 0xa1, 0x74, 0xda, 0x3f, 0x81, 0xea, 0xd7, 0xf3, 0xa5,
+	0x0f, 0x57, 0x76, 0x29, 0x2e, 0x76, 0xf7, 0x7b, 0xbf, 0xf7, 0xe6, 0x7d, 0xf5, 0x7b, 0x4f, 0xd0,
+	0x70, 0x7d, 0x9b, 0x8f, 0x76, 0x83, 0xd0, 0x17, 0x3e, 0x81, 0x27, 0x63, 0x1e, 0x4e, 0x42, 0xe6,
+	0x0d, 0xf8, 0xd6, 0xcd, 0x81, 0x23, 0x86, 0xe3, 0xb3, 0xdd, 0xbe, 0xef, 0xde, 0x1a, 0xf8, 0x03,
+	0xff, 0x16, 0x42, 0xce, 0xc6, 0x8f, 0xf0, 0x86, 0x17, 0x3c, 0x29, 0xd1, 0xad, 0xce, 0xc0, 0xf7,
+	0x07, 0x23, 0x9e, 0xa3, 0xec, 0x71, 0xc8, 0x84, 0xe3, 0x7b, 0x09, 0xff, 0xbb, 0x45, 0x75, 0x21,
+	0x7b, 0xc4, 0x3c, 0x76, 0xcb, 0x75, 0x5c, 0x27, 0xbc, 0x15, 0x3c, 0x1e, 0xa8, 0x53, 0x70, 0xa6,
+	0xfe, 0x27, 0x12, 0x9b, 0xf3, 0x1a, 0x99, 0x37, 0x51, 0x2c, 0xf3, 0xef, 0x25, 0xb8, 0x76, 0x1c,
+	0xfa, 0x2e, 0x17, 0x43, 0x3e, 0x8e, 0xa8, 0xb4, 0xf7, 0x27, 0xd2, 0x72, 0xca, 0x9f, 0x8c, 0x79,
+	0x24, 0x08, 0x01, 0x3d, 0x60, 0x62, 0xd8, 0xd6, 0xb6, 0xb5, 0x9d, 0x3a, 0xc5, 0x33, 0xd9, 0x80,
+	0x4a, 0x24, 0x58, 0x28, 0xda, 0xa5, 0x6d, 0x6d, 0xa7, 0x4c, 0xd5, 0x85, 0xb4, 0xa0, 0xcc, 0x3d,
+	0xbb, 0x5d, 0x46, 0x9a, 0x3c, 0x4a, 0xd9, 0x48, 0xf0, 0xa0, 0xad, 0x23, 0x09, 0xcf, 0xe4, 0x03,
+	0xa8, 0x0a, 0xc7, 0xe5, 0xfe, 0x58, 0xb4, 0x2b, 0xdb, 0xda, 0x4e, 0x63, 0x6f, 0x73, 0x57, 0x19,
+	0xb7, 0x9b, 0x1a, 0xb7, 0x7b, 0x27, 0x71, 0xd7, 0xaa, 0x7d, 0x1e, 0x1b, 0x2b, 0x7f, 0xfc, 0x97,
+	0xa1, 0xd1, 0x54, 0x46, 0x7e, 0x1a, 0x03, 0xdb, 0x5e, 0x45, 0x7b, 0xd4, 0x85, 0xbc, 0x07, 0x55,
+	0x3f, 0x90, 0x22, 0x51, 0xbb, 0x8a, 0x4a, 0x2f, 0xed, 0xe6, 0xe1, 0xdf, 0x7d, 0xa8, 0x58, 0x96,
+	0x2e, 0xd5, 0xd1, 0x14, 0x49, 0x9a, 0x50, 0x72, 0xec, 0x76, 0x0d, 0x6d, 0x2b, 0x39, 0x36, 0xb9,
+	0x09, 0x95, 0xa1, 0xe3, 0x89, 0xa8, 0x5d, 0x47, 0x15, 0x6f, 0x16, 0x55, 0x1c, 0x4a, 0x06, 0x2a,
+	0xd0, 0xa8, 0x42, 0x99, 0xff, 0xd0, 0xe0, 0x46, 0x1e, 0xb8, 0x7b, 0x5e, 0x24, 0x98, 0x27, 0x5e,
+	0x1b, 0x3a, 0x02, 0xba, 0x74, 0x25, 0x89, 0x1c, 0x9e, 0x73, 0x9f, 0xca, 0x17, 0xf8, 0xa4, 0x7f,
+	0x4d, 0x9f, 0x2a, 0x8b, 0x3e, 0xad, 0x7e, 0x25, 0x9f, 0x4e, 0xa0, 0x5d, 0xa8, 0x05, 0x1e, 0x05,
+	0xbe, 0x17, 0xf1, 0x43, 0xce, 0x6c, 0x1e, 0x92, 0x4d, 0xd0, 0x8f, 0x98, 0xcb, 0x95, 0x37, 0x56,
+	0x65, 0x1a, 0x1b, 0xda, 0x4d, 0x8a, 0x24, 0x72, 0x03, 0x56, 0x7f, 0xca, 0x46, 0x63, 0x1e, 0xb5,
+	0x4b, 0xdb, 0xe5, 0x9c, 0x99, 0x10, 0xcd, 0xbf, 0x96, 0x81, 0x2c, 0xaa, 0x25, 0x26, 0xac, 0xf6,
+	0x04, 0x13, 0xe3, 0x28, 0x51, 0x09, 0xd3, 0xd8, 0x58, 0x8d, 0x90, 0x42, 0x13, 0x0e, 0xb1, 0x40,
+	0xbf, 0xc3, 0x04, 0xc3, 0x70, 0x35, 0xf6, 0xb6, 0x8a, 0xe6, 0xe7, 0x1a, 0x25, 0xc2, 0x22, 0xd3,
+	0xd8, 0x68, 0xda, 0x4c, 0xb0, 0x77, 0x7c, 0xd7, 0x11, 0xdc, 0x0d, 0xc4, 0x84, 0xa2, 0x2c, 0xf9,
+	0x1e, 0xd4, 0xbb, 0x61, 0xe8, 0x87, 0x27, 0x93, 0x80, 0xab, 0x10, 0x5b, 0x57, 0xa7, 0xb1, 0x71,
+	0x89, 0xa7, 0xc4, 0x82, 0x44, 0x8e, 0x24, 0xdf, 0x86, 0x0a, 0x5e, 0x30, 0xfa, 0x75, 0xeb, 0xd2,
+	0x34, 0x36, 0xd6, 0x51, 0xa4, 0x00, 0x57, 0x08, 0xd2, 0x85, 0xaa, 0x0a, 0x52, 0xd4, 0xae, 0x6c,
+	0x97, 0x77, 0x1a, 0x7b, 0x6f, 0x2d, 0x37, 0x74, 0x36, 0xa2, 0x69, 0x98, 0x52, 0x59, 0xf2, 0x09,
+	0x34, 0xba, 0xcf, 0x82, 0x11, 0xf3, 0xb0, 0xfa, 0x93, 0x94, 0x5d, 0x2f, 0xaa, 0xc2, 0xf2, 0x2a,
+	0x60, 0xac, 0xcd, 0x69, 0x6c, 0x5c, 0xe6, 0x39, 0xa1, 0x60, 0x5b, 0x51, 0x17, 0xd9, 0x83, 0xda,
+	0xcf, 0x58, 0xe8, 0x39, 0xde, 0x40, 0xbe, 0x10, 0x99, 0xa3, 0x2b, 0xd3, 0xd8, 0x20, 0x4f, 0x13,
+	0x5a, 0x41, 0x2c, 0xc3, 0x99, 0xbf, 0xd6, 0xa0, 0x39, 0x1b, 0x64, 0xb2, 0x0b, 0x40, 0x79, 0x34,
+	0x1e, 0x09, 0x8c, 0xa5, 0x4a, 0x5b, 0x73, 0x1a, 0x1b, 0x10, 0x66, 0x54, 0x5a, 0x40, 0x90, 0x0f,
+	0x61, 0x55, 0xdd, 0xb0, 0x30, 0x1a, 0x7b, 0xed, 0xa2, 0x33, 0x3d, 0xe6, 0x06, 0x23, 0xde, 0x13,
+	0x21, 0x67, 0xae, 0xd5, 0x94, 0x75, 0x2c, 0x0b, 0x40, 0x69, 0xa2, 0x89, 0x9c, 0xf9, 0xdb, 0x12,
+	0xac, 0x15, 0x81, 0x24, 0x80, 0xd5, 0x11, 0x3b, 0xe3, 0x23, 0x59, 0x35, 0x65, 0x7c, 0x15, 0x7d,
+	0x3f, 0x14, 0xfc, 0x59, 0x70, 0xb6, 0x7b, 0x5f, 0xd2, 0x8f, 0x99, 0x13, 0x5a, 0x07, 0x52, 0xdb,
+	0x17, 0xb1, 0xf1, 0xee, 0x57, 0xe9, 0x94, 0x4a, 0x6e, 0xdf, 0x66, 0x81, 0xe0, 0xa1, 0x34, 0xc1,
+	0xe5, 0x22, 0x74, 0xfa, 0x34, 0xf9, 0x0e, 0xb9, 0x0d, 0xd5, 0x08, 0x2d, 0x88, 0x12, 0x2f, 0x5a,
+	0xf9, 0x27, 0x95, 0x69, 0xb9, 0xf5, 0x9f, 0x61, 0xc5, 0xd3, 0x54, 0x80, 0x1c, 0x03, 0x0c, 0x9d,
+	0x48, 0xf8, 0x83, 0x90, 0xb9, 0x51, 0xbb, 0x8c, 0xe2, 0xd7, 0x73, 0xf1, 0xbb, 0x23, 0x9f, 0x89,
+	0xc3, 0x14, 0x80, 0xa6, 0x93, 0x44, 0x55, 0x41, 0x8e, 0x16, 0xce, 0xe6, 0x2f, 0xa0, 0x79, 0xc0,
+	0xfa, 0x43, 0x6e, 0x67, 0xef, 0x68, 0x13, 0xca, 0x8f, 0xf9, 0x24, 0xc9, 0x46, 0x75, 0x1a, 0x1b,
+	0xf2, 0x4a, 0xe5, 0x1f, 0xd9, 0x6c, 0xf9, 0x33, 0xc1, 0x3d, 0x91, 0x9a, 0x4e, 0x8a, 0x09, 0xe8,
+	0x22, 0xcb, 0x5a, 0x4f, 0xbe, 0x98, 0x42, 0x69, 0x7a, 0x30, 0xbf, 0xd0, 0x60, 0x55, 0x81, 0x88,
+	0x91, 0xb6, 0x7c, 0xf9, 0x99, 0xb2, 0x55, 0x9f, 0xc6, 0x86, 0x22, 0xa4, 0xdd, 0x7f, 0x53, 0x75,
+	0x7f, 0xec, 0x6b, 0xca, 0x0a, 0xee, 0xd9, 0x6a, 0x0c, 0x6c, 0x43, 0x4d, 0x84, 0xac, 0xcf, 0x4f,
+	0x1d, 0x3b, 0x79, 0x4c, 0x69, 0xe5, 0x23, 0xf9, 0x9e, 0x4d, 0x7e, 0x08, 0xb5, 0x30, 0x71, 0x27,
+	0x99, 0x0a, 0x1b, 0x0b, 0x53, 0x61, 0xdf, 0x9b, 0x58, 0x6b, 0xd3, 0xd8, 0xc8, 0x90, 0x34, 0x3b,
+	0x91, 0x77, 0x80, 0xa0, 0x5f, 0xa7, 0xb2, 0x9f, 0x46, 0x82, 0xb9, 0xc1, 0xa9, 0xab, 0x7a, 0x5e,
+	0x99, 0xb6, 0x90, 0x73, 0x92, 0x32, 0x1e, 0x44, 0x1f, 0xe9, 0xb5, 0x72, 0x4b, 0x37, 0xe3, 0x12,
+	0x54, 0x93, 0x2e, 0x4a, 0xde, 0x82, 0x37, 0x30, 0xa8, 0x77, 0x9c, 0x88, 0x9d, 0x8d, 0xb8, 0x8d,
+	0x5e, 0xd6, 0xe8, 0x2c, 0x91, 0xbc, 0x0d, 0xad, 0xde, 0x90, 0x85, 0xb6, 0xe3, 0x0d, 0x32, 0x60,
+	0x09, 0x81, 0x0b, 0x74, 0xb2, 0x0d, 0x8d, 0x13, 0x5f, 0xb0, 0x11, 0x32, 0x22, 0x6c, 0x3b, 0x15,
+	0x5a, 0x24, 0x91, 0x3d, 0xd8, 0x48, 0x86, 0x46, 0x2f, 0x18, 0x39, 0x22, 0xd3, 0xa8, 0xa3, 0xc6,
+	0xa5, 0xbc, 0x79, 0x99, 0x7b, 0x9e, 0xe0, 0xe1, 0x67, 0x6c, 0x94, 0x34, 0xfc, 0xa5, 0x3c, 0xf2,
+	0x2e, 0x54, 0xb1, 0x13, 0x38, 0xaa, 0xa3, 0x34, 0xf7, 0xae, 0xce, 0xd6, 0x00, 0xb2, 0x1e, 0xf8,
+	0x36, 0xa7, 0x29, 0x8e, 0xec, 0xc0, 0xfa, 0x31, 0x0b, 0x85, 0xc3, 0x46, 0x69, 0x91, 0xe1, 0x58,
+	0xad, 0xd3, 0x79, 0x32, 0xd9, 0x82, 0xda, 0x71, 0xe8, 0xf8, 0xa1, 0x23, 0x26, 0x38, 0x49, 0xeb,
+	0x34, 0xbb, 0x9b, 0xcf, 0xa0, 0x82, 0x23, 0x86, 0x98, 0xb0, 0x86, 0x8e, 0xcb, 0xee, 0xe5, 0x70,
+	0xd5, 0xee, 0x2b, 0x74, 0x86, 0x46, 0xde, 0x87, 0x8d, 0x6e, 0x24, 0x1c, 0x97, 0x09, 0x6e, 0xf7,
+	0x90, 0x74, 0xe0, 0x8f, 0x3d, 0xb5, 0x61, 0xe8, 0x87, 0x2b, 0x74, 0x29, 0xd7, 0xba, 0x0c, 0x97,
+	0x0e, 0x30, 0xf0, 0x6c, 0xe4, 0x88, 0x49, 0x0a, 0x31, 0x7f, 0x55, 0x86, 0xd6, 0x7c, 0xab, 0x24,
+	0xd7, 0x41, 0x97, 0x5e, 0x26, 0xef, 0xa4, 0x36, 0x8d, 0x0d, 0x5d, 0xee, 0x6b, 0x14, 0xa9, 0xe4,
+	0xe7, 0xd0, 0xa2, 0xfc, 0x69, 0xe8, 0x08, 0xc1, 0xbd, 0xd4, 0x4e, 0xf5, 0x64, 0x66, 0x86, 0xce,
+	0x0c, 0x66, 0x62, 0xb5, 0x93, 0xa7, 0xd3, 0x0a, 0xe7, 0x64, 0xe9, 0x82, 0x36, 0xf2, 0x31, 0x34,
+	0x93, 0xe8, 0xa5, 0xfa, 0xcb, 0x8b, 0x3d, 0xb1, 0x80, 0x98, 0x58, 0x57, 0x12, 0xed, 0xcd, 0x60,
+	0x46, 0x8e, 0xce, 0xe9, 0x21, 0x3f, 0x82, 0xf5, 0x74, 0x65, 0xea, 0xf1, 0xbe, 0xef, 0xd9, 0x6a,
+	0x63, 0xd0, 0xac, 0x1b, 0xd3, 0xd8, 0xd8, 0xb4, 0x67, 0x59, 0x85, 0x56, 0x3f, 0x2f, 0x45, 0x3e,
+	0x82, 0x4a, 0x4f, 0xf0, 0x20, 0x9d, 0x62, 0xd7, 0x16, 0x46, 0x8f, 0xe4, 0x9e, 0x38, 0xae, 0xe3,
+	0x0d, 0xac, 0xab, 0x89, 0x71, 0xeb, 0x72, 0xab, 0x2b, 0x6a, 0x55, 0x2a, 0xcc, 0xdf, 0x69, 0xb0,
+	0x3e, 0x27, 0x23, 0x53, 0x50, 0x58, 0x21, 0x30, 0x05, 0x1e, 0x73, 0x79, 0xb2, 0x45, 0x18, 0x50,
+	0x39, 0x60, 0xa3, 0x51, 0x84, 0x39, 0xaf, 0xa8, 0x16, 0xd3, 0x97, 0x04, 0xaa, 0xe8, 0xe4, 0x83,
+	0x45, 0x3f, 0xcb, 0xe8, 0x27, 0xce, 0xe6, 0x39, 0x3f, 0x17, 0xbc, 0x33, 0xff, 0xa9, 0x41, 0x73,
+	0x36, 0x7f, 0x72, 0x9e, 0x3d, 0x70, 0x6c, 0x7b, 0xc4, 0x9f, 0xb2, 0x70, 0x66, 0x9e, 0xb9, 0x19,
+	0x95, 0x16, 0x10, 0x64, 0x07, 0x6a, 0x0f, 0x43, 0x67, 0x20, 0xeb, 0x0d, 0xad, 0xac, 0xab, 0x8e,
+	0xe4, 0x27, 0x34, 0x9a, 0x71, 0xc9, 0x77, 0xa0, 0x9e, 0x7d, 0x2b, 0x59, 0x3a, 0xde, 0x98, 0xc6,
+	0x46, 0x3d, 0x2b, 0x14, 0x9a, 0xf3, 0xc9, 0xed, 0x85, 0xd2, 0xd0, 0x31, 0x04, 0xe4, 0xf5, 0xc9,
+	0x37, 0x5f, 0xe8, 0xb0, 0x56, 0xac, 0x1a, 0x19, 0x46, 0x3c, 0x24, 0xee, 0x60, 0x18, 0x31, 0x8f,
+	0xb4, 0x92, 0x01, 0x7a, 0xf9, 0xf6, 0x3e, 0xd3, 0xca, 0x7b, 0x69, 0x2b, 0xef, 0xa6, 0x8b, 0x7c,
+	0xa1, 0x95, 0x77, 0x3d, 0x9b, 0x7c, 0x0b, 0xf4, 0x5e, 0xb6, 0xd1, 0x2b, 0xfb, 0x64, 0xfe, 0x8b,
+	0x3b, 0x97, 0xe4, 0x2f, 0x2b, 0xc9, 0xca, 0xff, 0x54, 0x92, 0xc7, 0x40, 0xee, 0x72, 0xd1, 0x1f,
+	0xce, 0xbc, 0x7b, 0x6c, 0x64, 0xba, 0xb5, 0x3d, 0x8d, 0x8d, 0xeb, 0x8f, 0x16, 0xb8, 0x05, 0x75,
+	0x4b, 0x64, 0xc9, 0x03, 0x78, 0x33, 0xa1, 0x1e, 0x0c, 0xc7, 0xde, 0x63, 0x6b, 0x22, 0xb8, 0xfa,
+	0xd5, 0xa0, 0x5b, 0xc6, 0x34, 0x36, 0xae, 0x3d, 0x9a, 0x67, 0x16, 0xf4, 0x2d, 0x4a, 0x92, 0x13,
+	0xb9, 0xc5, 0xfa, 0x21, 0x8f, 0xda, 0x35, 0x7c, 0x34, 0xe6, 0x45, 0xcf, 0x19, 0x51, 0x72, 0xad,
+	0x8d, 0xf2, 0xb6, 0x11, 0xa1, 0x64, 0xe1, 0x03, 0x89, 0xae, 0x7c, 0xf9, 0xac, 0xbf, 0x76, 0xf9,
+	0xfc, 0x18, 0xaa, 0x27, 0xdc, 0x63, 0x72, 0xc6, 0x03, 0x5a, 0xf0, 0x8d, 0x8b, 0x2c, 0x50, 0x30,
+	0x65, 0xc2, 0x66, 0x62, 0xc2, 0x9b, 0x42, 0xc9, 0x16, 0xf4, 0xa6, 0xea, 0xcc, 0xbf, 0x95, 0xe0,
+	0xca, 0x72, 0x0f, 0x64, 0x79, 0x1f, 0xf8, 0x6e, 0xe0, 0x7b, 0xdc, 0x13, 0x6d, 0x2d, 0x2f, 0xef,
+	0x7e, 0x4a, 0xa4, 0x39, 0x9f, 0x7c, 0x13, 0xaa, 0xfb, 0xb6, 0x1d, 0xf2, 0x28, 0x4a, 0x1e, 0x4d,
+	0x43, 0x6e, 0x1b, 0x4c, 0x91, 0x68, 0xca, 0x23, 0x77, 0x97, 0xa6, 0xba, 0x8c, 0x99, 0xc1, 0x6d,
+	0x75, 0x31, 0xd5, 0x4b, 0x13, 0x7c, 0xb0, 0x2c, 0xc1, 0x3a, 0xaa, 0xb9, 0x2c, 0x3d, 0x5e, 0x48,
+	0xf0, 0xb2, 0xb4, 0xde, 0x86, 0xe6, 0x7d, 0x26, 0xb8, 0xd7, 0x9f, 0xcc, 0xd6, 0x2f, 0x96, 0xfc,
+	0x68, 0x86, 0x43, 0xe7, 0x90, 0xe6, 0x1f, 0x4a, 0x70, 0xf5, 0x82, 0xb8, 0xcb, 0x0e, 0xa2, 0xae,
+	0xf7, 0xee, 0xb4, 0xb5, 0xbc, 0x83, 0x88, 0x84, 0x46, 0x33, 0xee, 0x05, 0xe1, 0x28, 0xfd, 0x7f,
+	0xc2, 0x51, 0xfe, 0x9a, 0xe1, 0xf8, 0x10, 0x07, 0xea, 0x98, 0xcb, 0x35, 0x6a, 0x76, 0xc6, 0x6c,
+	0xc8, 0x3a, 0x7e, 0x32, 0xc7, 0xa3, 0x0b, 0x68, 0xb3, 0x9b, 0x8d, 0x03, 0x26, 0x9c, 0x48, 0x38,
+	0x7d, 0xdc, 0x80, 0x96, 0xce, 0x7c, 0x19, 0x17, 0x7d, 0xf9, 0xc4, 0x37, 0xff, 0xa4, 0x01, 0x51,
+	0xfb, 0xef, 0xe1, 0xc9, 0xc9, 0x71, 0xb6, 0x87, 0x5c, 0x83, 0x7a, 0x5f, 0x52, 0x4f, 0xb3, 0x4d,
+	0x98, 0xd6, 0x90, 0xf0, 0x63, 0x2e, 0x1b, 0x5e, 0x43, 0xfd, 0xac, 0x3c, 0xed, 0xcb, 0x05, 0x00,
+	0xc7, 0x0b, 0x05, 0x45, 0x3a, 0x90, 0xc3, 0xff, 0xfb, 0x50, 0x1d, 0x26, 0xbf, 0xdf, 0xd2, 0x15,
+	0xbd, 0xf0, 0x84, 0xf2, 0xcf, 0xa9, 0x1f, 0x6a, 0x34, 0x05, 0xcb, 0x1f, 0xf3, 0x67, 0xbe, 0x3d,
+	0xc1, 0x48, 0xac, 0x51, 0x3c, 0x9b, 0x3f, 0x80, 0xd6, 0xbc, 0x80, 0xc4, 0x79, 0xd9, 0xdc, 0xa3,
+	0x78, 0x96, 0x3f, 0xfa, 0xf1, 0xc7, 0x82, 0x7a, 0x12, 0x54, 0x5d, 0xde, 0x7e, 0x1f, 0x1a, 0x85,
+	0x8d, 0x8c, 0xd4, 0x40, 0x3f, 0x7a, 0x78, 0xd4, 0x6d, 0xad, 0xc8, 0xd3, 0xf1, 0xfd, 0xfd, 0xa3,
+	0x96, 0x46, 0x1a, 0x50, 0xdd, 0x3f, 0xda, 0xbf, 0xff, 0xc9, 0xa7, 0xdd, 0x56, 0x69, 0x4b, 0xff,
+	0xcd, 0x9f, 0x3b, 0x9a, 0xd5, 0x7d, 0xfe, 0xb2, 0xb3, 0xf2, 0xe2, 0x65, 0x67, 0xe5, 0xcb, 0x97,
+	0x1d, 0xed, 0x97, 0xe7, 0x1d, 0xed, 0x2f, 0xe7, 0x1d, 0xed, 0xf3, 0xf3, 0x8e, 0xf6, 0xfc, 0xbc,
+	0xa3, 0xfd, 0xfb, 0xbc, 0xa3, 0xfd, 0xe7, 0xbc, 0xb3, 0xf2, 0xe5, 0x79, 0x47, 0xfb, 0xfd, 0xab,
+	0xce, 0xca, 0xf3, 0x57, 0x9d, 0x95, 0x17, 0xaf, 0x3a, 0x2b, 0x9f, 0xae, 0xa3, 0x8f, 0xf9, 0xa4,
+	0x3b, 0x5b, 0xc5, 0x5d, 0xfb, 0xbd, 0xff, 0x0e, 0x00, 0x76, 0x7b, 0x5b, 0x93, 0xc8, 0x12, 0x00,
+	0x00,
+}
+
+func (x ExplainMode) String() string {
+	s, ok := ExplainMode_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (this *PrometheusRangeQueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*CachedResponse)
+	that1, ok := that.(*PrometheusRangeQueryRequest)
 	if !ok {
-		that2, ok := that.(CachedResponse)
+		that2, ok := that.(PrometheusRangeQueryRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1273,27 +1643,43 @@ func (this *CachedResponse) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Key != that1.Key {
+	if this.Path != that1.Path {
 		return false
 	}
-	if len(this.Extents) != len(that1.Extents) {
+	if this.Start != that1.Start {
 		return false
 	}
-	for i := range this.Extents {
-		if !this.Extents[i].Equal(&that1.Extents[i]) {
-			return false
-		}
+	if this.End != that1.End {
+		return false
+	}
+	if this.Step != that1.Step {
+		return false
+	}
+	if this.Timeout != that1.Timeout {
+		return false
+	}
+	if this.Query != that1.Query {
+		return false
+	}
+	if !this.Options.Equal(&that1.Options) {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if !this.Hints.Equal(that1.Hints) {
+		return false
 	}
 	return true
 }
-func (this *Extent) Equal(that interface{}) bool {
+func (this *PrometheusInstantQueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Extent)
+	that1, ok := that.(*PrometheusInstantQueryRequest)
 	if !ok {
-		that2, ok := that.(Extent)
+		that2, ok := that.(PrometheusInstantQueryRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1305,31 +1691,34 @@ func (this *Extent) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Start != that1.Start {
+	if this.Path != that1.Path {
 		return false
 	}
-	if this.End != that1.End {
+	if this.Time != that1.Time {
 		return false
 	}
-	if this.TraceId != that1.TraceId {
+	if this.Query != that1.Query {
 		return false
 	}
-	if !this.Response.Equal(that1.Response) {
+	if !this.Options.Equal(&that1.Options) {
 		return false
 	}
-	if this.QueryTimestampMs != that1.QueryTimestampMs {
+	if this.Id != that1.Id {
+		return false
+	}
+	if !this.Hints.Equal(that1.Hints) {
 		return false
 	}
 	return true
 }
-func (this *Options) Equal(that interface{}) bool {
+func (this *PrometheusResponseHeader) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Options)
+	that1, ok := that.(*PrometheusResponseHeader)
 	if !ok {
-		that2, ok := that.(Options)
+		that2, ok := that.(PrometheusResponseHeader)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1341,31 +1730,27 @@ func (this *Options) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CacheDisabled != that1.CacheDisabled {
-		return false
-	}
-	if this.ShardingDisabled != that1.ShardingDisabled {
-		return false
-	}
-	if this.TotalShards != that1.TotalShards {
+	if this.Name != that1.Name {
 		return false
 	}
-	if this.InstantSplitDisabled != that1.InstantSplitDisabled {
+	if len(this.Values) != len(that1.Values) {
 		return false
 	}
-	if this.InstantSplitInterval != that1.InstantSplitInterval {
-		return false
+	for i := range this.Values {
+		if this.Values[i] != that1.Values[i] {
+			return false
+		}
 	}
 	return true
 }
-func (this *Hints) Equal(that interface{}) bool {
+func (this *PrometheusResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Hints)
+	that1, ok := that.(*PrometheusResponse)
 	if !ok {
-		that2, ok := that.(Hints)
+		that2, ok := that.(PrometheusResponse)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1377,28 +1762,47 @@ func (this *Hints) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.TotalQueries != that1.TotalQueries {
+	if this.Status != that1.Status {
 		return false
 	}
-	if that1.CardinalityEstimate == nil {
-		if this.CardinalityEstimate != nil {
+	if !this.Data.Equal(that1.Data) {
+		return false
+	}
+	if this.ErrorType != that1.ErrorType {
+		return false
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
 			return false
 		}
-	} else if this.CardinalityEstimate == nil {
+	}
+	if !this.Explanation.Equal(that1.Explanation) {
 		return false
-	} else if !this.CardinalityEstimate.Equal(that1.CardinalityEstimate) {
+	}
+	if len(this.Warnings) != len(that1.Warnings) {
 		return false
 	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
 	return true
 }
-func (this *Hints_EstimatedSeriesCount) Equal(that interface{}) bool {
+func (this *PrometheusData) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Hints_EstimatedSeriesCount)
+	that1, ok := that.(*PrometheusData)
 	if !ok {
-		that2, ok := that.(Hints_EstimatedSeriesCount)
+		that2, ok := that.(PrometheusData)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1410,19 +1814,27 @@ func (this *Hints_EstimatedSeriesCount) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+	if this.ResultType != that1.ResultType {
+		return false
+	}
+	if len(this.Result) != len(that1.Result) {
 		return false
 	}
+	for i := range this.Result {
+		if !this.Result[i].Equal(&that1.Result[i]) {
+			return false
+		}
+	}
 	return true
 }
-func (this *QueryStatistics) Equal(that interface{}) bool {
+func (this *SampleStream) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*QueryStatistics)
+	that1, ok := that.(*SampleStream)
 	if !ok {
-		that2, ok := that.(QueryStatistics)
+		that2, ok := that.(SampleStream)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1434,19 +1846,40 @@ func (this *QueryStatistics) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+	if len(this.Labels) != len(that1.Labels) {
 		return false
 	}
-	return true
-}
-func (this *CachedHTTPResponse) Equal(that interface{}) bool {
+	for i := range this.Labels {
+		if !this.Labels[i].Equal(that1.Labels[i]) {
+			return false
+		}
+	}
+	if len(this.Samples) != len(that1.Samples) {
+		return false
+	}
+	for i := range this.Samples {
+		if !this.Samples[i].Equal(&that1.Samples[i]) {
+			return false
+		}
+	}
+	if len(this.Histograms) != len(that1.Histograms) {
+		return false
+	}
+	for i := range this.Histograms {
+		if !this.Histograms[i].Equal(&that1.Histograms[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *CachedResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*CachedHTTPResponse)
+	that1, ok := that.(*CachedResponse)
 	if !ok {
-		that2, ok := that.(CachedHTTPResponse)
+		that2, ok := that.(CachedResponse)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1458,33 +1891,27 @@ func (this *CachedHTTPResponse) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CacheKey != that1.CacheKey {
-		return false
-	}
-	if this.StatusCode != that1.StatusCode {
+	if this.Key != that1.Key {
 		return false
 	}
-	if len(this.Headers) != len(that1.Headers) {
+	if len(this.Extents) != len(that1.Extents) {
 		return false
 	}
-	for i := range this.Headers {
-		if !this.Headers[i].Equal(that1.Headers[i]) {
+	for i := range this.Extents {
+		if !this.Extents[i].Equal(&that1.Extents[i]) {
 			return false
 		}
 	}
-	if !bytes.Equal(this.Body, that1.Body) {
-		return false
-	}
 	return true
 }
-func (this *CachedHTTPHeader) Equal(that interface{}) bool {
+func (this *Extent) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*CachedHTTPHeader)
+	that1, ok := that.(*Extent)
 	if !ok {
-		that2, ok := that.(CachedHTTPHeader)
+		that2, ok := that.(Extent)
 		if ok {
 			that1 = &that2
 		} else {
@@ -1496,1481 +1923,3955 @@ func (this *CachedHTTPHeader) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Name != that1.Name {
+	if this.Start != that1.Start {
 		return false
 	}
-	if this.Value != that1.Value {
+	if this.End != that1.End {
+		return false
+	}
+	if this.TraceId != that1.TraceId {
+		return false
+	}
+	if !this.Response.Equal(that1.Response) {
+		return false
+	}
+	if this.QueryTimestampMs != that1.QueryTimestampMs {
 		return false
 	}
 	return true
 }
-func (this *PrometheusRangeQueryRequest) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *Options) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 13)
-	s = append(s, "&querymiddleware.PrometheusRangeQueryRequest{")
-	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
-	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
-	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
-	s = append(s, "Step: "+fmt.Sprintf("%#v", this.Step)+",\n")
-	s = append(s, "Timeout: "+fmt.Sprintf("%#v", this.Timeout)+",\n")
-	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
-	s = append(s, "Options: "+strings.Replace(this.Options.GoString(), `&`, ``, 1)+",\n")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	if this.Hints != nil {
-		s = append(s, "Hints: "+fmt.Sprintf("%#v", this.Hints)+",\n")
+
+	that1, ok := that.(*Options)
+	if !ok {
+		that2, ok := that.(Options)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *PrometheusInstantQueryRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := make([]string, 0, 10)
-	s = append(s, "&querymiddleware.PrometheusInstantQueryRequest{")
-	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
-	s = append(s, "Time: "+fmt.Sprintf("%#v", this.Time)+",\n")
-	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
-	s = append(s, "Options: "+strings.Replace(this.Options.GoString(), `&`, ``, 1)+",\n")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	if this.Hints != nil {
-		s = append(s, "Hints: "+fmt.Sprintf("%#v", this.Hints)+",\n")
+	if this.CacheDisabled != that1.CacheDisabled {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *PrometheusResponseHeader) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.ShardingDisabled != that1.ShardingDisabled {
+		return false
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&querymiddleware.PrometheusResponseHeader{")
-	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
-	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *PrometheusResponse) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.TotalShards != that1.TotalShards {
+		return false
 	}
-	s := make([]string, 0, 9)
-	s = append(s, "&querymiddleware.PrometheusResponse{")
-	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
-	if this.Data != nil {
-		s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	if this.InstantSplitDisabled != that1.InstantSplitDisabled {
+		return false
 	}
-	s = append(s, "ErrorType: "+fmt.Sprintf("%#v", this.ErrorType)+",\n")
-	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
-	if this.Headers != nil {
-		s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	if this.InstantSplitInterval != that1.InstantSplitInterval {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *PrometheusData) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Explain != that1.Explain {
+		return false
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&querymiddleware.PrometheusData{")
-	s = append(s, "ResultType: "+fmt.Sprintf("%#v", this.ResultType)+",\n")
-	if this.Result != nil {
-		vs := make([]*SampleStream, len(this.Result))
-		for i := range vs {
-			vs[i] = &this.Result[i]
-		}
-		s = append(s, "Result: "+fmt.Sprintf("%#v", vs)+",\n")
+	if this.PartialResponse != that1.PartialResponse {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+	if this.Priority != that1.Priority {
+		return false
+	}
+	return true
 }
-func (this *SampleStream) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *Hints) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 7)
-	s = append(s, "&querymiddleware.SampleStream{")
-	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
-	if this.Samples != nil {
-		vs := make([]*mimirpb.Sample, len(this.Samples))
-		for i := range vs {
-			vs[i] = &this.Samples[i]
+
+	that1, ok := that.(*Hints)
+	if !ok {
+		that2, ok := that.(Hints)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
-		s = append(s, "Samples: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	if this.Histograms != nil {
-		vs := make([]*mimirpb.FloatHistogramPair, len(this.Histograms))
-		for i := range vs {
-			vs[i] = &this.Histograms[i]
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.TotalQueries != that1.TotalQueries {
+		return false
+	}
+	if that1.CardinalityEstimate == nil {
+		if this.CardinalityEstimate != nil {
+			return false
 		}
-		s = append(s, "Histograms: "+fmt.Sprintf("%#v", vs)+",\n")
+	} else if this.CardinalityEstimate == nil {
+		return false
+	} else if !this.CardinalityEstimate.Equal(that1.CardinalityEstimate) {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return true
 }
-func (this *CachedResponse) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *Hints_EstimatedSeriesCount) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&querymiddleware.CachedResponse{")
-	s = append(s, "Key: "+fmt.Sprintf("%#v", this.Key)+",\n")
-	if this.Extents != nil {
-		vs := make([]*Extent, len(this.Extents))
-		for i := range vs {
-			vs[i] = &this.Extents[i]
+
+	that1, ok := that.(*Hints_EstimatedSeriesCount)
+	if !ok {
+		that2, ok := that.(Hints_EstimatedSeriesCount)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
-		s = append(s, "Extents: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Extent) GoString() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := make([]string, 0, 9)
-	s = append(s, "&querymiddleware.Extent{")
-	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
-	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
-	s = append(s, "TraceId: "+fmt.Sprintf("%#v", this.TraceId)+",\n")
-	if this.Response != nil {
-		s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+		return false
 	}
-	s = append(s, "QueryTimestampMs: "+fmt.Sprintf("%#v", this.QueryTimestampMs)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return true
 }
-func (this *Options) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *QueryExplanation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 9)
-	s = append(s, "&querymiddleware.Options{")
-	s = append(s, "CacheDisabled: "+fmt.Sprintf("%#v", this.CacheDisabled)+",\n")
-	s = append(s, "ShardingDisabled: "+fmt.Sprintf("%#v", this.ShardingDisabled)+",\n")
-	s = append(s, "TotalShards: "+fmt.Sprintf("%#v", this.TotalShards)+",\n")
-	s = append(s, "InstantSplitDisabled: "+fmt.Sprintf("%#v", this.InstantSplitDisabled)+",\n")
-	s = append(s, "InstantSplitInterval: "+fmt.Sprintf("%#v", this.InstantSplitInterval)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Hints) GoString() string {
-	if this == nil {
-		return "nil"
+
+	that1, ok := that.(*QueryExplanation)
+	if !ok {
+		that2, ok := that.(QueryExplanation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&querymiddleware.Hints{")
-	s = append(s, "TotalQueries: "+fmt.Sprintf("%#v", this.TotalQueries)+",\n")
-	if this.CardinalityEstimate != nil {
-		s = append(s, "CardinalityEstimate: "+fmt.Sprintf("%#v", this.CardinalityEstimate)+",\n")
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Hints_EstimatedSeriesCount) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Mode != that1.Mode {
+		return false
 	}
-	s := strings.Join([]string{`&querymiddleware.Hints_EstimatedSeriesCount{` +
-		`EstimatedSeriesCount:` + fmt.Sprintf("%#v", this.EstimatedSeriesCount) + `}`}, ", ")
-	return s
-}
-func (this *QueryStatistics) GoString() string {
-	if this == nil {
-		return "nil"
+	if len(this.RewrittenQueries) != len(that1.RewrittenQueries) {
+		return false
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&querymiddleware.QueryStatistics{")
-	s = append(s, "EstimatedSeriesCount: "+fmt.Sprintf("%#v", this.EstimatedSeriesCount)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *CachedHTTPResponse) GoString() string {
-	if this == nil {
-		return "nil"
+	for i := range this.RewrittenQueries {
+		if !this.RewrittenQueries[i].Equal(&that1.RewrittenQueries[i]) {
+			return false
+		}
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&querymiddleware.CachedHTTPResponse{")
-	s = append(s, "CacheKey: "+fmt.Sprintf("%#v", this.CacheKey)+",\n")
-	s = append(s, "StatusCode: "+fmt.Sprintf("%#v", this.StatusCode)+",\n")
-	if this.Headers != nil {
-		s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	if len(this.PartialQueries) != len(that1.PartialQueries) {
+		return false
 	}
-	s = append(s, "Body: "+fmt.Sprintf("%#v", this.Body)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *CachedHTTPHeader) GoString() string {
-	if this == nil {
-		return "nil"
+	for i := range this.PartialQueries {
+		if !this.PartialQueries[i].Equal(&that1.PartialQueries[i]) {
+			return false
+		}
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&querymiddleware.CachedHTTPHeader{")
-	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
-	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func valueToGoStringModel(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	if this.DurationSeconds != that1.DurationSeconds {
+		return false
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
-}
-func (m *PrometheusRangeQueryRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if len(this.Steps) != len(that1.Steps) {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *PrometheusRangeQueryRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *PrometheusRangeQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Hints != nil {
-		{
-			size, err := m.Hints.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintModel(dAtA, i, uint64(size))
+	for i := range this.Steps {
+		if !this.Steps[i].Equal(&that1.Steps[i]) {
+			return false
 		}
-		i--
-		dAtA[i] = 0x4a
 	}
-	if m.Id != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.Id))
-		i--
-		dAtA[i] = 0x40
+	return true
+}
+func (this *QueryStepTiming) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	{
-		size, err := m.Options.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
+
+	that1, ok := that.(*QueryStepTiming)
+	if !ok {
+		that2, ok := that.(QueryStepTiming)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
-		i -= size
-		i = encodeVarintModel(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x3a
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0x32
-	}
-	n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Timeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Timeout):])
-	if err3 != nil {
-		return 0, err3
 	}
-	i -= n3
-	i = encodeVarintModel(dAtA, i, uint64(n3))
-	i--
-	dAtA[i] = 0x2a
-	if m.Step != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.Step))
-		i--
-		dAtA[i] = 0x20
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.End != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.End))
-		i--
-		dAtA[i] = 0x18
+	if this.Name != that1.Name {
+		return false
 	}
-	if m.Start != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.Start))
-		i--
-		dAtA[i] = 0x10
+	if this.Calls != that1.Calls {
+		return false
 	}
-	if len(m.Path) > 0 {
-		i -= len(m.Path)
-		copy(dAtA[i:], m.Path)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Path)))
-		i--
-		dAtA[i] = 0xa
+	if this.DurationSeconds != that1.DurationSeconds {
+		return false
 	}
-	return len(dAtA) - i, nil
+	return true
 }
-
-func (m *PrometheusInstantQueryRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *RewrittenQuery) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
-
-func (m *PrometheusInstantQueryRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
 
-func (m *PrometheusInstantQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Hints != nil {
-		{
-			size, err := m.Hints.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintModel(dAtA, i, uint64(size))
+	that1, ok := that.(*RewrittenQuery)
+	if !ok {
+		that2, ok := that.(RewrittenQuery)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
-		i--
-		dAtA[i] = 0x32
 	}
-	if m.Id != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.Id))
-		i--
-		dAtA[i] = 0x28
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	{
-		size, err := m.Options.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintModel(dAtA, i, uint64(size))
+	if this.Middleware != that1.Middleware {
+		return false
 	}
-	i--
-	dAtA[i] = 0x22
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0x1a
+	if this.Original != that1.Original {
+		return false
 	}
-	if m.Time != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.Time))
-		i--
-		dAtA[i] = 0x10
+	if this.Rewritten != that1.Rewritten {
+		return false
 	}
-	if len(m.Path) > 0 {
-		i -= len(m.Path)
-		copy(dAtA[i:], m.Path)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Path)))
-		i--
-		dAtA[i] = 0xa
+	if this.PartialQueries != that1.PartialQueries {
+		return false
 	}
-	return len(dAtA) - i, nil
+	return true
 }
-
-func (m *PrometheusResponseHeader) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *PartialQuery) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
 
-func (m *PrometheusResponseHeader) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *PrometheusResponseHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Values) > 0 {
-		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Values[iNdEx])
-			copy(dAtA[i:], m.Values[iNdEx])
-			i = encodeVarintModel(dAtA, i, uint64(len(m.Values[iNdEx])))
-			i--
-			dAtA[i] = 0x12
+	that1, ok := that.(*PartialQuery)
+	if !ok {
+		that2, ok := that.(PartialQuery)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Name)))
-		i--
-		dAtA[i] = 0xa
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *PrometheusResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.Query != that1.Query {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *PrometheusResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *PrometheusResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Headers) > 0 {
-		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Headers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x2a
-		}
+	if this.Start != that1.Start {
+		return false
 	}
-	if len(m.Error) > 0 {
-		i -= len(m.Error)
-		copy(dAtA[i:], m.Error)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Error)))
-		i--
-		dAtA[i] = 0x22
+	if this.End != that1.End {
+		return false
 	}
-	if len(m.ErrorType) > 0 {
-		i -= len(m.ErrorType)
-		copy(dAtA[i:], m.ErrorType)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.ErrorType)))
-		i--
-		dAtA[i] = 0x1a
+	if this.Step != that1.Step {
+		return false
 	}
-	if m.Data != nil {
-		{
-			size, err := m.Data.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintModel(dAtA, i, uint64(size))
+	if this.DurationSeconds != that1.DurationSeconds {
+		return false
+	}
+	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
+		return false
+	}
+	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
+		return false
+	}
+	if len(this.Stores) != len(that1.Stores) {
+		return false
+	}
+	for i := range this.Stores {
+		if !this.Stores[i].Equal(&that1.Stores[i]) {
+			return false
 		}
-		i--
-		dAtA[i] = 0x12
 	}
-	if len(m.Status) > 0 {
-		i -= len(m.Status)
-		copy(dAtA[i:], m.Status)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Status)))
-		i--
-		dAtA[i] = 0xa
+	if this.Error != that1.Error {
+		return false
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *PrometheusData) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if len(this.Tenants) != len(that1.Tenants) {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *PrometheusData) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	for i := range this.Tenants {
+		if !this.Tenants[i].Equal(&that1.Tenants[i]) {
+			return false
+		}
+	}
+	return true
 }
+func (this *PartialQueryStoreStats) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *PrometheusData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Result) > 0 {
-		for iNdEx := len(m.Result) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Result[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
+	that1, ok := that.(*PartialQueryStoreStats)
+	if !ok {
+		that2, ok := that.(PartialQueryStoreStats)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	if len(m.ResultType) > 0 {
-		i -= len(m.ResultType)
-		copy(dAtA[i:], m.ResultType)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.ResultType)))
-		i--
-		dAtA[i] = 0xa
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return len(dAtA) - i, nil
+	if this.Component != that1.Component {
+		return false
+	}
+	if this.Address != that1.Address {
+		return false
+	}
+	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
+		return false
+	}
+	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
+		return false
+	}
+	if this.LatencySeconds != that1.LatencySeconds {
+		return false
+	}
+	return true
 }
+func (this *PartialQueryTenantStats) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *SampleStream) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*PartialQueryTenantStats)
+	if !ok {
+		that2, ok := that.(PartialQueryTenantStats)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.TenantID != that1.TenantID {
+		return false
+	}
+	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
+		return false
+	}
+	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
+		return false
+	}
+	if this.QueueTimeSeconds != that1.QueueTimeSeconds {
+		return false
+	}
+	return true
 }
+func (this *QueryStatistics) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *SampleStream) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	that1, ok := that.(*QueryStatistics)
+	if !ok {
+		that2, ok := that.(QueryStatistics)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+		return false
+	}
+	return true
 }
+func (this *CachedHTTPResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *SampleStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Histograms) > 0 {
-		for iNdEx := len(m.Histograms) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Histograms[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
+	that1, ok := that.(*CachedHTTPResponse)
+	if !ok {
+		that2, ok := that.(CachedHTTPResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	if len(m.Samples) > 0 {
-		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if len(m.Labels) > 0 {
-		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Labels[iNdEx].Size()
-				i -= size
-				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+	if this.CacheKey != that1.CacheKey {
+		return false
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *CachedResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.StatusCode != that1.StatusCode {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *CachedResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *CachedResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Extents) > 0 {
-		for iNdEx := len(m.Extents) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Extents[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
 		}
 	}
-	if len(m.Key) > 0 {
-		i -= len(m.Key)
-		copy(dAtA[i:], m.Key)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Key)))
-		i--
-		dAtA[i] = 0xa
+	if !bytes.Equal(this.Body, that1.Body) {
+		return false
 	}
-	return len(dAtA) - i, nil
+	return true
 }
-
-func (m *Extent) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *CachedHTTPHeader) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
 
-func (m *Extent) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *Extent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.QueryTimestampMs != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.QueryTimestampMs))
-		i--
-		dAtA[i] = 0x30
-	}
-	if m.Response != nil {
-		{
-			size, err := m.Response.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintModel(dAtA, i, uint64(size))
+	that1, ok := that.(*CachedHTTPHeader)
+	if !ok {
+		that2, ok := that.(CachedHTTPHeader)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
-		i--
-		dAtA[i] = 0x2a
 	}
-	if len(m.TraceId) > 0 {
-		i -= len(m.TraceId)
-		copy(dAtA[i:], m.TraceId)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.TraceId)))
-		i--
-		dAtA[i] = 0x22
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.End != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.End))
-		i--
-		dAtA[i] = 0x10
+	if this.Name != that1.Name {
+		return false
 	}
-	if m.Start != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.Start))
-		i--
-		dAtA[i] = 0x8
+	if this.Value != that1.Value {
+		return false
 	}
-	return len(dAtA) - i, nil
+	return true
 }
-
-func (m *Options) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *PrometheusRangeQueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Options) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	s := make([]string, 0, 13)
+	s = append(s, "&querymiddleware.PrometheusRangeQueryRequest{")
+	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	s = append(s, "Step: "+fmt.Sprintf("%#v", this.Step)+",\n")
+	s = append(s, "Timeout: "+fmt.Sprintf("%#v", this.Timeout)+",\n")
+	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
+	s = append(s, "Options: "+strings.Replace(this.Options.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	if this.Hints != nil {
+		s = append(s, "Hints: "+fmt.Sprintf("%#v", this.Hints)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *Options) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.InstantSplitInterval != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.InstantSplitInterval))
-		i--
-		dAtA[i] = 0x28
+func (this *PrometheusInstantQueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	if m.InstantSplitDisabled {
-		i--
-		if m.InstantSplitDisabled {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x20
+	s := make([]string, 0, 10)
+	s = append(s, "&querymiddleware.PrometheusInstantQueryRequest{")
+	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
+	s = append(s, "Time: "+fmt.Sprintf("%#v", this.Time)+",\n")
+	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
+	s = append(s, "Options: "+strings.Replace(this.Options.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	if this.Hints != nil {
+		s = append(s, "Hints: "+fmt.Sprintf("%#v", this.Hints)+",\n")
 	}
-	if m.TotalShards != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.TotalShards))
-		i--
-		dAtA[i] = 0x18
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrometheusResponseHeader) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	if m.ShardingDisabled {
-		i--
-		if m.ShardingDisabled {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x10
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.PrometheusResponseHeader{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrometheusResponse) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	if m.CacheDisabled {
-		i--
-		if m.CacheDisabled {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x8
+	s := make([]string, 0, 11)
+	s = append(s, "&querymiddleware.PrometheusResponse{")
+	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
+	if this.Data != nil {
+		s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *Hints) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	s = append(s, "ErrorType: "+fmt.Sprintf("%#v", this.ErrorType)+",\n")
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	if this.Headers != nil {
+		s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
 	}
-	return dAtA[:n], nil
+	if this.Explanation != nil {
+		s = append(s, "Explanation: "+fmt.Sprintf("%#v", this.Explanation)+",\n")
+	}
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *Hints) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (this *PrometheusData) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.PrometheusData{")
+	s = append(s, "ResultType: "+fmt.Sprintf("%#v", this.ResultType)+",\n")
+	if this.Result != nil {
+		vs := make([]*SampleStream, len(this.Result))
+		for i := range vs {
+			vs[i] = &this.Result[i]
+		}
+		s = append(s, "Result: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *Hints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.CardinalityEstimate != nil {
-		{
-			size := m.CardinalityEstimate.Size()
-			i -= size
-			if _, err := m.CardinalityEstimate.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
+func (this *SampleStream) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&querymiddleware.SampleStream{")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	if this.Samples != nil {
+		vs := make([]*mimirpb.Sample, len(this.Samples))
+		for i := range vs {
+			vs[i] = &this.Samples[i]
 		}
+		s = append(s, "Samples: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	if m.TotalQueries != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.TotalQueries))
-		i--
-		dAtA[i] = 0x8
+	if this.Histograms != nil {
+		vs := make([]*mimirpb.FloatHistogramPair, len(this.Histograms))
+		for i := range vs {
+			vs[i] = &this.Histograms[i]
+		}
+		s = append(s, "Histograms: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *Hints_EstimatedSeriesCount) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
-}
-
-func (m *Hints_EstimatedSeriesCount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	i = encodeVarintModel(dAtA, i, uint64(m.EstimatedSeriesCount))
-	i--
-	dAtA[i] = 0x10
-	return len(dAtA) - i, nil
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (m *QueryStatistics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *CachedResponse) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	return dAtA[:n], nil
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.CachedResponse{")
+	s = append(s, "Key: "+fmt.Sprintf("%#v", this.Key)+",\n")
+	if this.Extents != nil {
+		vs := make([]*Extent, len(this.Extents))
+		for i := range vs {
+			vs[i] = &this.Extents[i]
+		}
+		s = append(s, "Extents: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *QueryStatistics) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (this *Extent) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&querymiddleware.Extent{")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	s = append(s, "TraceId: "+fmt.Sprintf("%#v", this.TraceId)+",\n")
+	if this.Response != nil {
+		s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	}
+	s = append(s, "QueryTimestampMs: "+fmt.Sprintf("%#v", this.QueryTimestampMs)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *QueryStatistics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.EstimatedSeriesCount != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.EstimatedSeriesCount))
-		i--
-		dAtA[i] = 0x8
+func (this *Options) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	return len(dAtA) - i, nil
+	s := make([]string, 0, 12)
+	s = append(s, "&querymiddleware.Options{")
+	s = append(s, "CacheDisabled: "+fmt.Sprintf("%#v", this.CacheDisabled)+",\n")
+	s = append(s, "ShardingDisabled: "+fmt.Sprintf("%#v", this.ShardingDisabled)+",\n")
+	s = append(s, "TotalShards: "+fmt.Sprintf("%#v", this.TotalShards)+",\n")
+	s = append(s, "InstantSplitDisabled: "+fmt.Sprintf("%#v", this.InstantSplitDisabled)+",\n")
+	s = append(s, "InstantSplitInterval: "+fmt.Sprintf("%#v", this.InstantSplitInterval)+",\n")
+	s = append(s, "Explain: "+fmt.Sprintf("%#v", this.Explain)+",\n")
+	s = append(s, "PartialResponse: "+fmt.Sprintf("%#v", this.PartialResponse)+",\n")
+	s = append(s, "Priority: "+fmt.Sprintf("%#v", this.Priority)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *CachedHTTPResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *Hints) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	return dAtA[:n], nil
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.Hints{")
+	s = append(s, "TotalQueries: "+fmt.Sprintf("%#v", this.TotalQueries)+",\n")
+	if this.CardinalityEstimate != nil {
+		s = append(s, "CardinalityEstimate: "+fmt.Sprintf("%#v", this.CardinalityEstimate)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *CachedHTTPResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (this *Hints_EstimatedSeriesCount) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&querymiddleware.Hints_EstimatedSeriesCount{` +
+		`EstimatedSeriesCount:` + fmt.Sprintf("%#v", this.EstimatedSeriesCount) + `}`}, ", ")
+	return s
 }
-
-func (m *CachedHTTPResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Body) > 0 {
-		i -= len(m.Body)
-		copy(dAtA[i:], m.Body)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Body)))
-		i--
-		dAtA[i] = 0x22
+func (this *QueryExplanation) GoString() string {
+	if this == nil {
+		return "nil"
 	}
-	if len(m.Headers) > 0 {
-		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Headers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintModel(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
+	s := make([]string, 0, 9)
+	s = append(s, "&querymiddleware.QueryExplanation{")
+	s = append(s, "Mode: "+fmt.Sprintf("%#v", this.Mode)+",\n")
+	if this.RewrittenQueries != nil {
+		vs := make([]*RewrittenQuery, len(this.RewrittenQueries))
+		for i := range vs {
+			vs[i] = &this.RewrittenQueries[i]
 		}
+		s = append(s, "RewrittenQueries: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	if m.StatusCode != 0 {
-		i = encodeVarintModel(dAtA, i, uint64(m.StatusCode))
-		i--
-		dAtA[i] = 0x10
+	if this.PartialQueries != nil {
+		vs := make([]*PartialQuery, len(this.PartialQueries))
+		for i := range vs {
+			vs[i] = &this.PartialQueries[i]
+		}
+		s = append(s, "PartialQueries: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	if len(m.CacheKey) > 0 {
-		i -= len(m.CacheKey)
-		copy(dAtA[i:], m.CacheKey)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.CacheKey)))
-		i--
-		dAtA[i] = 0xa
+	s = append(s, "DurationSeconds: "+fmt.Sprintf("%#v", this.DurationSeconds)+",\n")
+	if this.Steps != nil {
+		vs := make([]*QueryStepTiming, len(this.Steps))
+		for i := range vs {
+			vs[i] = &this.Steps[i]
+		}
+		s = append(s, "Steps: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
-	return len(dAtA) - i, nil
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-
-func (m *CachedHTTPHeader) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *QueryStepTiming) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&querymiddleware.QueryStepTiming{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Calls: "+fmt.Sprintf("%#v", this.Calls)+",\n")
+	s = append(s, "DurationSeconds: "+fmt.Sprintf("%#v", this.DurationSeconds)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *RewrittenQuery) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&querymiddleware.RewrittenQuery{")
+	s = append(s, "Middleware: "+fmt.Sprintf("%#v", this.Middleware)+",\n")
+	s = append(s, "Original: "+fmt.Sprintf("%#v", this.Original)+",\n")
+	s = append(s, "Rewritten: "+fmt.Sprintf("%#v", this.Rewritten)+",\n")
+	s = append(s, "PartialQueries: "+fmt.Sprintf("%#v", this.PartialQueries)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PartialQuery) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&querymiddleware.PartialQuery{")
+	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	s = append(s, "Step: "+fmt.Sprintf("%#v", this.Step)+",\n")
+	s = append(s, "DurationSeconds: "+fmt.Sprintf("%#v", this.DurationSeconds)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	if this.Stores != nil {
+		vs := make([]*PartialQueryStoreStats, len(this.Stores))
+		for i := range vs {
+			vs[i] = &this.Stores[i]
+		}
+		s = append(s, "Stores: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	if this.Tenants != nil {
+		vs := make([]*PartialQueryTenantStats, len(this.Tenants))
+		for i := range vs {
+			vs[i] = &this.Tenants[i]
+		}
+		s = append(s, "Tenants: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PartialQueryStoreStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&querymiddleware.PartialQueryStoreStats{")
+	s = append(s, "Component: "+fmt.Sprintf("%#v", this.Component)+",\n")
+	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "LatencySeconds: "+fmt.Sprintf("%#v", this.LatencySeconds)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PartialQueryTenantStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&querymiddleware.PartialQueryTenantStats{")
+	s = append(s, "TenantID: "+fmt.Sprintf("%#v", this.TenantID)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "QueueTimeSeconds: "+fmt.Sprintf("%#v", this.QueueTimeSeconds)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryStatistics) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&querymiddleware.QueryStatistics{")
+	s = append(s, "EstimatedSeriesCount: "+fmt.Sprintf("%#v", this.EstimatedSeriesCount)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CachedHTTPResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&querymiddleware.CachedHTTPResponse{")
+	s = append(s, "CacheKey: "+fmt.Sprintf("%#v", this.CacheKey)+",\n")
+	s = append(s, "StatusCode: "+fmt.Sprintf("%#v", this.StatusCode)+",\n")
+	if this.Headers != nil {
+		s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	}
+	s = append(s, "Body: "+fmt.Sprintf("%#v", this.Body)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CachedHTTPHeader) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.CachedHTTPHeader{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringModel(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *PrometheusRangeQueryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
 	return dAtA[:n], nil
 }
 
-func (m *CachedHTTPHeader) MarshalTo(dAtA []byte) (int, error) {
+func (m *PrometheusRangeQueryRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CachedHTTPHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PrometheusRangeQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Value)))
+	if m.Hints != nil {
+		{
+			size, err := m.Hints.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x4a
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintModel(dAtA, i, uint64(len(m.Name)))
+	if m.Id != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Id))
 		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
-}
-
-func encodeVarintModel(dAtA []byte, offset int, v uint64) int {
-	offset -= sovModel(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *PrometheusRangeQueryRequest) Size() (n int) {
-	if m == nil {
-		return 0
+		dAtA[i] = 0x40
 	}
-	var l int
-	_ = l
-	l = len(m.Path)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	{
+		size, err := m.Options.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintModel(dAtA, i, uint64(size))
 	}
-	if m.Start != 0 {
-		n += 1 + sovModel(uint64(m.Start))
+	i--
+	dAtA[i] = 0x3a
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x32
 	}
-	if m.End != 0 {
-		n += 1 + sovModel(uint64(m.End))
+	n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Timeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Timeout):])
+	if err3 != nil {
+		return 0, err3
 	}
+	i -= n3
+	i = encodeVarintModel(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x2a
 	if m.Step != 0 {
-		n += 1 + sovModel(uint64(m.Step))
+		i = encodeVarintModel(dAtA, i, uint64(m.Step))
+		i--
+		dAtA[i] = 0x20
 	}
-	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Timeout)
-	n += 1 + l + sovModel(uint64(l))
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if m.End != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x18
 	}
-	l = m.Options.Size()
-	n += 1 + l + sovModel(uint64(l))
-	if m.Id != 0 {
-		n += 1 + sovModel(uint64(m.Id))
+	if m.Start != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.Hints != nil {
-		l = m.Hints.Size()
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *PrometheusInstantQueryRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PrometheusInstantQueryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusInstantQueryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusInstantQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Path)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
-	}
-	if m.Time != 0 {
-		n += 1 + sovModel(uint64(m.Time))
-	}
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if m.Hints != nil {
+		{
+			size, err := m.Hints.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
 	}
-	l = m.Options.Size()
-	n += 1 + l + sovModel(uint64(l))
 	if m.Id != 0 {
-		n += 1 + sovModel(uint64(m.Id))
-	}
-	if m.Hints != nil {
-		l = m.Hints.Size()
-		n += 1 + l + sovModel(uint64(l))
+		i = encodeVarintModel(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x28
 	}
-	return n
+	{
+		size, err := m.Options.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintModel(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Time != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Time))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *PrometheusResponseHeader) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PrometheusResponseHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusResponseHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusResponseHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
-	}
 	if len(m.Values) > 0 {
-		for _, s := range m.Values {
-			l = len(s)
-			n += 1 + l + sovModel(uint64(l))
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Values[iNdEx])
+			copy(dAtA[i:], m.Values[iNdEx])
+			i = encodeVarintModel(dAtA, i, uint64(len(m.Values[iNdEx])))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	return n
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *PrometheusResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PrometheusResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Status)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
-	}
-	if m.Data != nil {
-		l = m.Data.Size()
-		n += 1 + l + sovModel(uint64(l))
-	}
-	l = len(m.ErrorType)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintModel(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
 	}
-	l = len(m.Error)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if m.Explanation != nil {
+		{
+			size, err := m.Explanation.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
 	}
 	if len(m.Headers) > 0 {
-		for _, e := range m.Headers {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Headers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
 		}
 	}
-	return n
-}
-
-func (m *PrometheusData) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x22
 	}
-	var l int
-	_ = l
-	l = len(m.ResultType)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.ErrorType) > 0 {
+		i -= len(m.ErrorType)
+		copy(dAtA[i:], m.ErrorType)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ErrorType)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if len(m.Result) > 0 {
-		for _, e := range m.Result {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
+	if m.Data != nil {
+		{
+			size, err := m.Data.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.Status) > 0 {
+		i -= len(m.Status)
+		copy(dAtA[i:], m.Status)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Status)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *SampleStream) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PrometheusData) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusData) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for _, e := range m.Labels {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
-		}
-	}
-	if len(m.Samples) > 0 {
-		for _, e := range m.Samples {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
+	if len(m.Result) > 0 {
+		for iNdEx := len(m.Result) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Result[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	if len(m.Histograms) > 0 {
-		for _, e := range m.Histograms {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
-		}
+	if len(m.ResultType) > 0 {
+		i -= len(m.ResultType)
+		copy(dAtA[i:], m.ResultType)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.ResultType)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *CachedResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Key)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
-	}
-	if len(m.Extents) > 0 {
-		for _, e := range m.Extents {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
-		}
+func (m *SampleStream) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Extent) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Start != 0 {
-		n += 1 + sovModel(uint64(m.Start))
-	}
-	if m.End != 0 {
-		n += 1 + sovModel(uint64(m.End))
-	}
-	l = len(m.TraceId)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
-	}
-	if m.Response != nil {
-		l = m.Response.Size()
-		n += 1 + l + sovModel(uint64(l))
-	}
-	if m.QueryTimestampMs != 0 {
-		n += 1 + sovModel(uint64(m.QueryTimestampMs))
-	}
-	return n
+func (m *SampleStream) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Options) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SampleStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.CacheDisabled {
-		n += 2
-	}
-	if m.ShardingDisabled {
-		n += 2
-	}
-	if m.TotalShards != 0 {
-		n += 1 + sovModel(uint64(m.TotalShards))
+	if len(m.Histograms) > 0 {
+		for iNdEx := len(m.Histograms) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Histograms[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
-	if m.InstantSplitDisabled {
-		n += 2
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.InstantSplitInterval != 0 {
-		n += 1 + sovModel(uint64(m.InstantSplitInterval))
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Labels[iNdEx].Size()
+				i -= size
+				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Hints) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *CachedResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *CachedResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CachedResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.TotalQueries != 0 {
-		n += 1 + sovModel(uint64(m.TotalQueries))
+	if len(m.Extents) > 0 {
+		for iNdEx := len(m.Extents) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Extents[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.CardinalityEstimate != nil {
-		n += m.CardinalityEstimate.Size()
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Hints_EstimatedSeriesCount) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Extent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	n += 1 + sovModel(uint64(m.EstimatedSeriesCount))
-	return n
+	return dAtA[:n], nil
 }
-func (m *QueryStatistics) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.EstimatedSeriesCount != 0 {
-		n += 1 + sovModel(uint64(m.EstimatedSeriesCount))
-	}
-	return n
+
+func (m *Extent) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CachedHTTPResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *Extent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.CacheKey)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
-	}
-	if m.StatusCode != 0 {
-		n += 1 + sovModel(uint64(m.StatusCode))
+	if m.QueryTimestampMs != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.QueryTimestampMs))
+		i--
+		dAtA[i] = 0x30
 	}
-	if len(m.Headers) > 0 {
-		for _, e := range m.Headers {
-			l = e.Size()
-			n += 1 + l + sovModel(uint64(l))
+	if m.Response != nil {
+		{
+			size, err := m.Response.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x2a
 	}
-	l = len(m.Body)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.TraceId) > 0 {
+		i -= len(m.TraceId)
+		copy(dAtA[i:], m.TraceId)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.TraceId)))
+		i--
+		dAtA[i] = 0x22
 	}
-	return n
+	if m.End != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Start != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *CachedHTTPHeader) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Options) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Options) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Options) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.Priority) > 0 {
+		i -= len(m.Priority)
+		copy(dAtA[i:], m.Priority)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Priority)))
+		i--
+		dAtA[i] = 0x42
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovModel(uint64(l))
+	if len(m.PartialResponse) > 0 {
+		i -= len(m.PartialResponse)
+		copy(dAtA[i:], m.PartialResponse)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.PartialResponse)))
+		i--
+		dAtA[i] = 0x3a
 	}
-	return n
-}
-
-func sovModel(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozModel(x uint64) (n int) {
-	return sovModel(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *PrometheusRangeQueryRequest) String() string {
-	if this == nil {
-		return "nil"
+	if m.Explain != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Explain))
+		i--
+		dAtA[i] = 0x30
 	}
-	s := strings.Join([]string{`&PrometheusRangeQueryRequest{`,
-		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
-		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
-		`End:` + fmt.Sprintf("%v", this.End) + `,`,
-		`Step:` + fmt.Sprintf("%v", this.Step) + `,`,
-		`Timeout:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timeout), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
-		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
-		`Options:` + strings.Replace(strings.Replace(this.Options.String(), "Options", "Options", 1), `&`, ``, 1) + `,`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`Hints:` + strings.Replace(this.Hints.String(), "Hints", "Hints", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *PrometheusInstantQueryRequest) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&PrometheusInstantQueryRequest{`,
-		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
-		`Time:` + fmt.Sprintf("%v", this.Time) + `,`,
-		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
-		`Options:` + strings.Replace(strings.Replace(this.Options.String(), "Options", "Options", 1), `&`, ``, 1) + `,`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`Hints:` + strings.Replace(this.Hints.String(), "Hints", "Hints", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *PrometheusResponseHeader) String() string {
-	if this == nil {
-		return "nil"
+	if m.InstantSplitInterval != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.InstantSplitInterval))
+		i--
+		dAtA[i] = 0x28
 	}
-	s := strings.Join([]string{`&PrometheusResponseHeader{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *PrometheusResponse) String() string {
-	if this == nil {
-		return "nil"
+	if m.InstantSplitDisabled {
+		i--
+		if m.InstantSplitDisabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
 	}
-	repeatedStringForHeaders := "[]*PrometheusResponseHeader{"
-	for _, f := range this.Headers {
-		repeatedStringForHeaders += strings.Replace(f.String(), "PrometheusResponseHeader", "PrometheusResponseHeader", 1) + ","
+	if m.TotalShards != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.TotalShards))
+		i--
+		dAtA[i] = 0x18
 	}
-	repeatedStringForHeaders += "}"
-	s := strings.Join([]string{`&PrometheusResponse{`,
-		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
-		`Data:` + strings.Replace(this.Data.String(), "PrometheusData", "PrometheusData", 1) + `,`,
-		`ErrorType:` + fmt.Sprintf("%v", this.ErrorType) + `,`,
-		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
-		`Headers:` + repeatedStringForHeaders + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *PrometheusData) String() string {
-	if this == nil {
-		return "nil"
+	if m.ShardingDisabled {
+		i--
+		if m.ShardingDisabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
 	}
-	repeatedStringForResult := "[]SampleStream{"
-	for _, f := range this.Result {
-		repeatedStringForResult += strings.Replace(strings.Replace(f.String(), "SampleStream", "SampleStream", 1), `&`, ``, 1) + ","
+	if m.CacheDisabled {
+		i--
+		if m.CacheDisabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
 	}
-	repeatedStringForResult += "}"
-	s := strings.Join([]string{`&PrometheusData{`,
-		`ResultType:` + fmt.Sprintf("%v", this.ResultType) + `,`,
-		`Result:` + repeatedStringForResult + `,`,
-		`}`,
-	}, "")
-	return s
+	return len(dAtA) - i, nil
 }
-func (this *SampleStream) String() string {
-	if this == nil {
-		return "nil"
-	}
-	repeatedStringForSamples := "[]Sample{"
-	for _, f := range this.Samples {
-		repeatedStringForSamples += fmt.Sprintf("%v", f) + ","
-	}
-	repeatedStringForSamples += "}"
-	repeatedStringForHistograms := "[]FloatHistogramPair{"
-	for _, f := range this.Histograms {
-		repeatedStringForHistograms += fmt.Sprintf("%v", f) + ","
+
+func (m *Hints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	repeatedStringForHistograms += "}"
-	s := strings.Join([]string{`&SampleStream{`,
-		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
-		`Samples:` + repeatedStringForSamples + `,`,
-		`Histograms:` + repeatedStringForHistograms + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
 }
-func (this *CachedResponse) String() string {
-	if this == nil {
-		return "nil"
-	}
-	repeatedStringForExtents := "[]Extent{"
-	for _, f := range this.Extents {
-		repeatedStringForExtents += strings.Replace(strings.Replace(f.String(), "Extent", "Extent", 1), `&`, ``, 1) + ","
-	}
-	repeatedStringForExtents += "}"
-	s := strings.Join([]string{`&CachedResponse{`,
-		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
-		`Extents:` + repeatedStringForExtents + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (m *Hints) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (this *Extent) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *Hints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CardinalityEstimate != nil {
+		{
+			size := m.CardinalityEstimate.Size()
+			i -= size
+			if _, err := m.CardinalityEstimate.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
 	}
-	s := strings.Join([]string{`&Extent{`,
-		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
-		`End:` + fmt.Sprintf("%v", this.End) + `,`,
-		`TraceId:` + fmt.Sprintf("%v", this.TraceId) + `,`,
-		`Response:` + strings.Replace(fmt.Sprintf("%v", this.Response), "Any", "types.Any", 1) + `,`,
-		`QueryTimestampMs:` + fmt.Sprintf("%v", this.QueryTimestampMs) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Options) String() string {
-	if this == nil {
-		return "nil"
+	if m.TotalQueries != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.TotalQueries))
+		i--
+		dAtA[i] = 0x8
 	}
-	s := strings.Join([]string{`&Options{`,
-		`CacheDisabled:` + fmt.Sprintf("%v", this.CacheDisabled) + `,`,
-		`ShardingDisabled:` + fmt.Sprintf("%v", this.ShardingDisabled) + `,`,
-		`TotalShards:` + fmt.Sprintf("%v", this.TotalShards) + `,`,
-		`InstantSplitDisabled:` + fmt.Sprintf("%v", this.InstantSplitDisabled) + `,`,
-		`InstantSplitInterval:` + fmt.Sprintf("%v", this.InstantSplitInterval) + `,`,
-		`}`,
-	}, "")
-	return s
+	return len(dAtA) - i, nil
 }
-func (this *Hints) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Hints{`,
-		`TotalQueries:` + fmt.Sprintf("%v", this.TotalQueries) + `,`,
-		`CardinalityEstimate:` + fmt.Sprintf("%v", this.CardinalityEstimate) + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (m *Hints_EstimatedSeriesCount) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
 }
-func (this *Hints_EstimatedSeriesCount) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Hints_EstimatedSeriesCount{`,
-		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (m *Hints_EstimatedSeriesCount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i = encodeVarintModel(dAtA, i, uint64(m.EstimatedSeriesCount))
+	i--
+	dAtA[i] = 0x10
+	return len(dAtA) - i, nil
 }
-func (this *QueryStatistics) String() string {
-	if this == nil {
-		return "nil"
+func (m *QueryExplanation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	s := strings.Join([]string{`&QueryStatistics{`,
-		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
 }
-func (this *CachedHTTPResponse) String() string {
-	if this == nil {
-		return "nil"
-	}
-	repeatedStringForHeaders := "[]*CachedHTTPHeader{"
-	for _, f := range this.Headers {
-		repeatedStringForHeaders += strings.Replace(f.String(), "CachedHTTPHeader", "CachedHTTPHeader", 1) + ","
-	}
-	repeatedStringForHeaders += "}"
-	s := strings.Join([]string{`&CachedHTTPResponse{`,
-		`CacheKey:` + fmt.Sprintf("%v", this.CacheKey) + `,`,
-		`StatusCode:` + fmt.Sprintf("%v", this.StatusCode) + `,`,
-		`Headers:` + repeatedStringForHeaders + `,`,
-		`Body:` + fmt.Sprintf("%v", this.Body) + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (m *QueryExplanation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryExplanation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Steps) > 0 {
+		for iNdEx := len(m.Steps) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Steps[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.DurationSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.DurationSeconds))))
+		i--
+		dAtA[i] = 0x21
+	}
+	if len(m.PartialQueries) > 0 {
+		for iNdEx := len(m.PartialQueries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.PartialQueries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.RewrittenQueries) > 0 {
+		for iNdEx := len(m.RewrittenQueries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RewrittenQueries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Mode) > 0 {
+		i -= len(m.Mode)
+		copy(dAtA[i:], m.Mode)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Mode)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryStepTiming) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryStepTiming) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryStepTiming) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DurationSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.DurationSeconds))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.Calls != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Calls))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RewrittenQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RewrittenQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RewrittenQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.PartialQueries != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.PartialQueries))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Rewritten) > 0 {
+		i -= len(m.Rewritten)
+		copy(dAtA[i:], m.Rewritten)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Rewritten)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Original) > 0 {
+		i -= len(m.Original)
+		copy(dAtA[i:], m.Original)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Original)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Middleware) > 0 {
+		i -= len(m.Middleware)
+		copy(dAtA[i:], m.Middleware)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Middleware)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PartialQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PartialQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PartialQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Tenants) > 0 {
+		for iNdEx := len(m.Tenants) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Tenants[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Stores) > 0 {
+		for iNdEx := len(m.Stores) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Stores[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.FetchedChunkBytes != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.DurationSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.DurationSeconds))))
+		i--
+		dAtA[i] = 0x29
+	}
+	if m.Step != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Step))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.End != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Start != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PartialQueryStoreStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PartialQueryStoreStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PartialQueryStoreStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.LatencySeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.LatencySeconds))))
+		i--
+		dAtA[i] = 0x29
+	}
+	if m.FetchedChunkBytes != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Component) > 0 {
+		i -= len(m.Component)
+		copy(dAtA[i:], m.Component)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Component)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PartialQueryTenantStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PartialQueryTenantStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PartialQueryTenantStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.QueueTimeSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.QueueTimeSeconds))))
+		i--
+		dAtA[i] = 0x21
+	}
+	if m.FetchedChunkBytes != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TenantID) > 0 {
+		i -= len(m.TenantID)
+		copy(dAtA[i:], m.TenantID)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.TenantID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryStatistics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryStatistics) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryStatistics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.EstimatedSeriesCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.EstimatedSeriesCount))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CachedHTTPResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CachedHTTPResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CachedHTTPResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Body) > 0 {
+		i -= len(m.Body)
+		copy(dAtA[i:], m.Body)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Body)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Headers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.StatusCode != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.StatusCode))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.CacheKey) > 0 {
+		i -= len(m.CacheKey)
+		copy(dAtA[i:], m.CacheKey)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.CacheKey)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CachedHTTPHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CachedHTTPHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CachedHTTPHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintModel(dAtA []byte, offset int, v uint64) int {
+	offset -= sovModel(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *PrometheusRangeQueryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Start != 0 {
+		n += 1 + sovModel(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovModel(uint64(m.End))
+	}
+	if m.Step != 0 {
+		n += 1 + sovModel(uint64(m.Step))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Timeout)
+	n += 1 + l + sovModel(uint64(l))
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = m.Options.Size()
+	n += 1 + l + sovModel(uint64(l))
+	if m.Id != 0 {
+		n += 1 + sovModel(uint64(m.Id))
+	}
+	if m.Hints != nil {
+		l = m.Hints.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *PrometheusInstantQueryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Time != 0 {
+		n += 1 + sovModel(uint64(m.Time))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = m.Options.Size()
+	n += 1 + l + sovModel(uint64(l))
+	if m.Id != 0 {
+		n += 1 + sovModel(uint64(m.Id))
+	}
+	if m.Hints != nil {
+		l = m.Hints.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *PrometheusResponseHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PrometheusResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Status)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Data != nil {
+		l = m.Data.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.ErrorType)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	if m.Explanation != nil {
+		l = m.Explanation.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PrometheusData) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ResultType)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Result) > 0 {
+		for _, e := range m.Result {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SampleStream) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	if len(m.Histograms) > 0 {
+		for _, e := range m.Histograms {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CachedResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Extents) > 0 {
+		for _, e := range m.Extents {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Extent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Start != 0 {
+		n += 1 + sovModel(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovModel(uint64(m.End))
+	}
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.QueryTimestampMs != 0 {
+		n += 1 + sovModel(uint64(m.QueryTimestampMs))
+	}
+	return n
+}
+
+func (m *Options) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CacheDisabled {
+		n += 2
+	}
+	if m.ShardingDisabled {
+		n += 2
+	}
+	if m.TotalShards != 0 {
+		n += 1 + sovModel(uint64(m.TotalShards))
+	}
+	if m.InstantSplitDisabled {
+		n += 2
+	}
+	if m.InstantSplitInterval != 0 {
+		n += 1 + sovModel(uint64(m.InstantSplitInterval))
+	}
+	if m.Explain != 0 {
+		n += 1 + sovModel(uint64(m.Explain))
+	}
+	l = len(m.PartialResponse)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Priority)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *Hints) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalQueries != 0 {
+		n += 1 + sovModel(uint64(m.TotalQueries))
+	}
+	if m.CardinalityEstimate != nil {
+		n += m.CardinalityEstimate.Size()
+	}
+	return n
+}
+
+func (m *Hints_EstimatedSeriesCount) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovModel(uint64(m.EstimatedSeriesCount))
+	return n
+}
+func (m *QueryExplanation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Mode)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.RewrittenQueries) > 0 {
+		for _, e := range m.RewrittenQueries {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	if len(m.PartialQueries) > 0 {
+		for _, e := range m.PartialQueries {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	if m.DurationSeconds != 0 {
+		n += 9
+	}
+	if len(m.Steps) > 0 {
+		for _, e := range m.Steps {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *QueryStepTiming) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Calls != 0 {
+		n += 1 + sovModel(uint64(m.Calls))
+	}
+	if m.DurationSeconds != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *RewrittenQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Middleware)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Original)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Rewritten)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.PartialQueries != 0 {
+		n += 1 + sovModel(uint64(m.PartialQueries))
+	}
+	return n
+}
+
+func (m *PartialQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Start != 0 {
+		n += 1 + sovModel(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovModel(uint64(m.End))
+	}
+	if m.Step != 0 {
+		n += 1 + sovModel(uint64(m.Step))
+	}
+	if m.DurationSeconds != 0 {
+		n += 9
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovModel(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovModel(uint64(m.FetchedChunkBytes))
+	}
+	if len(m.Stores) > 0 {
+		for _, e := range m.Stores {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if len(m.Tenants) > 0 {
+		for _, e := range m.Tenants {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PartialQueryStoreStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Component)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovModel(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovModel(uint64(m.FetchedChunkBytes))
+	}
+	if m.LatencySeconds != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *PartialQueryTenantStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TenantID)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovModel(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovModel(uint64(m.FetchedChunkBytes))
+	}
+	if m.QueueTimeSeconds != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *QueryStatistics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.EstimatedSeriesCount != 0 {
+		n += 1 + sovModel(uint64(m.EstimatedSeriesCount))
+	}
+	return n
+}
+
+func (m *CachedHTTPResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.CacheKey)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.StatusCode != 0 {
+		n += 1 + sovModel(uint64(m.StatusCode))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	l = len(m.Body)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *CachedHTTPHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func sovModel(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozModel(x uint64) (n int) {
+	return sovModel(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *PrometheusRangeQueryRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrometheusRangeQueryRequest{`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`Step:` + fmt.Sprintf("%v", this.Step) + `,`,
+		`Timeout:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timeout), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
+		`Options:` + strings.Replace(strings.Replace(this.Options.String(), "Options", "Options", 1), `&`, ``, 1) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Hints:` + strings.Replace(this.Hints.String(), "Hints", "Hints", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusInstantQueryRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrometheusInstantQueryRequest{`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`Time:` + fmt.Sprintf("%v", this.Time) + `,`,
+		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
+		`Options:` + strings.Replace(strings.Replace(this.Options.String(), "Options", "Options", 1), `&`, ``, 1) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Hints:` + strings.Replace(this.Hints.String(), "Hints", "Hints", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusResponseHeader) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrometheusResponseHeader{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForHeaders := "[]*PrometheusResponseHeader{"
+	for _, f := range this.Headers {
+		repeatedStringForHeaders += strings.Replace(f.String(), "PrometheusResponseHeader", "PrometheusResponseHeader", 1) + ","
+	}
+	repeatedStringForHeaders += "}"
+	s := strings.Join([]string{`&PrometheusResponse{`,
+		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
+		`Data:` + strings.Replace(this.Data.String(), "PrometheusData", "PrometheusData", 1) + `,`,
+		`ErrorType:` + fmt.Sprintf("%v", this.ErrorType) + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`Headers:` + repeatedStringForHeaders + `,`,
+		`Explanation:` + strings.Replace(this.Explanation.String(), "QueryExplanation", "QueryExplanation", 1) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusData) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForResult := "[]SampleStream{"
+	for _, f := range this.Result {
+		repeatedStringForResult += strings.Replace(strings.Replace(f.String(), "SampleStream", "SampleStream", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForResult += "}"
+	s := strings.Join([]string{`&PrometheusData{`,
+		`ResultType:` + fmt.Sprintf("%v", this.ResultType) + `,`,
+		`Result:` + repeatedStringForResult + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SampleStream) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSamples := "[]Sample{"
+	for _, f := range this.Samples {
+		repeatedStringForSamples += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForSamples += "}"
+	repeatedStringForHistograms := "[]FloatHistogramPair{"
+	for _, f := range this.Histograms {
+		repeatedStringForHistograms += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForHistograms += "}"
+	s := strings.Join([]string{`&SampleStream{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`Samples:` + repeatedStringForSamples + `,`,
+		`Histograms:` + repeatedStringForHistograms + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CachedResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForExtents := "[]Extent{"
+	for _, f := range this.Extents {
+		repeatedStringForExtents += strings.Replace(strings.Replace(f.String(), "Extent", "Extent", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForExtents += "}"
+	s := strings.Join([]string{`&CachedResponse{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`Extents:` + repeatedStringForExtents + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Extent) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Extent{`,
+		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`TraceId:` + fmt.Sprintf("%v", this.TraceId) + `,`,
+		`Response:` + strings.Replace(fmt.Sprintf("%v", this.Response), "Any", "types.Any", 1) + `,`,
+		`QueryTimestampMs:` + fmt.Sprintf("%v", this.QueryTimestampMs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Options) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Options{`,
+		`CacheDisabled:` + fmt.Sprintf("%v", this.CacheDisabled) + `,`,
+		`ShardingDisabled:` + fmt.Sprintf("%v", this.ShardingDisabled) + `,`,
+		`TotalShards:` + fmt.Sprintf("%v", this.TotalShards) + `,`,
+		`InstantSplitDisabled:` + fmt.Sprintf("%v", this.InstantSplitDisabled) + `,`,
+		`InstantSplitInterval:` + fmt.Sprintf("%v", this.InstantSplitInterval) + `,`,
+		`Explain:` + fmt.Sprintf("%v", this.Explain) + `,`,
+		`PartialResponse:` + fmt.Sprintf("%v", this.PartialResponse) + `,`,
+		`Priority:` + fmt.Sprintf("%v", this.Priority) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Hints) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Hints{`,
+		`TotalQueries:` + fmt.Sprintf("%v", this.TotalQueries) + `,`,
+		`CardinalityEstimate:` + fmt.Sprintf("%v", this.CardinalityEstimate) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Hints_EstimatedSeriesCount) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Hints_EstimatedSeriesCount{`,
+		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryExplanation) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRewrittenQueries := "[]RewrittenQuery{"
+	for _, f := range this.RewrittenQueries {
+		repeatedStringForRewrittenQueries += strings.Replace(strings.Replace(f.String(), "RewrittenQuery", "RewrittenQuery", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRewrittenQueries += "}"
+	repeatedStringForPartialQueries := "[]PartialQuery{"
+	for _, f := range this.PartialQueries {
+		repeatedStringForPartialQueries += strings.Replace(strings.Replace(f.String(), "PartialQuery", "PartialQuery", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForPartialQueries += "}"
+	repeatedStringForSteps := "[]QueryStepTiming{"
+	for _, f := range this.Steps {
+		repeatedStringForSteps += strings.Replace(strings.Replace(f.String(), "QueryStepTiming", "QueryStepTiming", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSteps += "}"
+	s := strings.Join([]string{`&QueryExplanation{`,
+		`Mode:` + fmt.Sprintf("%v", this.Mode) + `,`,
+		`RewrittenQueries:` + repeatedStringForRewrittenQueries + `,`,
+		`PartialQueries:` + repeatedStringForPartialQueries + `,`,
+		`DurationSeconds:` + fmt.Sprintf("%v", this.DurationSeconds) + `,`,
+		`Steps:` + repeatedStringForSteps + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryStepTiming) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryStepTiming{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Calls:` + fmt.Sprintf("%v", this.Calls) + `,`,
+		`DurationSeconds:` + fmt.Sprintf("%v", this.DurationSeconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RewrittenQuery) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RewrittenQuery{`,
+		`Middleware:` + fmt.Sprintf("%v", this.Middleware) + `,`,
+		`Original:` + fmt.Sprintf("%v", this.Original) + `,`,
+		`Rewritten:` + fmt.Sprintf("%v", this.Rewritten) + `,`,
+		`PartialQueries:` + fmt.Sprintf("%v", this.PartialQueries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PartialQuery) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForStores := "[]PartialQueryStoreStats{"
+	for _, f := range this.Stores {
+		repeatedStringForStores += strings.Replace(strings.Replace(f.String(), "PartialQueryStoreStats", "PartialQueryStoreStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForStores += "}"
+	repeatedStringForTenants := "[]PartialQueryTenantStats{"
+	for _, f := range this.Tenants {
+		repeatedStringForTenants += strings.Replace(strings.Replace(f.String(), "PartialQueryTenantStats", "PartialQueryTenantStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForTenants += "}"
+	s := strings.Join([]string{`&PartialQuery{`,
+		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
+		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`Step:` + fmt.Sprintf("%v", this.Step) + `,`,
+		`DurationSeconds:` + fmt.Sprintf("%v", this.DurationSeconds) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`Stores:` + repeatedStringForStores + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`Tenants:` + repeatedStringForTenants + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PartialQueryStoreStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PartialQueryStoreStats{`,
+		`Component:` + fmt.Sprintf("%v", this.Component) + `,`,
+		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`LatencySeconds:` + fmt.Sprintf("%v", this.LatencySeconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PartialQueryTenantStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PartialQueryTenantStats{`,
+		`TenantID:` + fmt.Sprintf("%v", this.TenantID) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`QueueTimeSeconds:` + fmt.Sprintf("%v", this.QueueTimeSeconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryStatistics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryStatistics{`,
+		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CachedHTTPResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForHeaders := "[]*CachedHTTPHeader{"
+	for _, f := range this.Headers {
+		repeatedStringForHeaders += strings.Replace(f.String(), "CachedHTTPHeader", "CachedHTTPHeader", 1) + ","
+	}
+	repeatedStringForHeaders += "}"
+	s := strings.Join([]string{`&CachedHTTPResponse{`,
+		`CacheKey:` + fmt.Sprintf("%v", this.CacheKey) + `,`,
+		`StatusCode:` + fmt.Sprintf("%v", this.StatusCode) + `,`,
+		`Headers:` + repeatedStringForHeaders + `,`,
+		`Body:` + fmt.Sprintf("%v", this.Body) + `,`,
+		`}`,
+	}, "")
+	return s
 }
 func (this *CachedHTTPHeader) String() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&CachedHTTPHeader{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := strings.Join([]string{`&CachedHTTPHeader{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringModel(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusRangeQueryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusRangeQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Step", wireType)
+			}
+			m.Step = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Step |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Timeout, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hints == nil {
+				m.Hints = &Hints{}
+			}
+			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusInstantQueryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusInstantQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Time |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hints == nil {
+				m.Hints = &Hints{}
+			}
+			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusResponseHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusResponseHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Status = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Data == nil {
+				m.Data = &PrometheusData{}
+			}
+			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, &PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Explanation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Explanation == nil {
+				m.Explanation = &QueryExplanation{}
+			}
+			if err := m.Explanation.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResultType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResultType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Result = append(m.Result, SampleStream{})
+			if err := m.Result[len(m.Result)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func valueToStringModel(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+func (m *SampleStream) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SampleStream: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SampleStream: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, github_com_grafana_mimir_pkg_mimirpb.LabelAdapter{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, mimirpb.Sample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Histograms", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Histograms = append(m.Histograms, mimirpb.FloatHistogramPair{})
+			if err := m.Histograms[len(m.Histograms)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
+func (m *CachedResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2993,15 +5894,15 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PrometheusRangeQueryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: CachedResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PrometheusRangeQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CachedResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3029,9 +5930,96 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Path = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extents", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Extents = append(m.Extents, Extent{})
+			if err := m.Extents[len(m.Extents)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Extent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Extent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Extent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
 			}
@@ -3050,7 +6038,7 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 3:
+		case 2:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
@@ -3070,60 +6058,8 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Step", wireType)
-			}
-			m.Step = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowModel
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Step |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowModel
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Timeout, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3151,11 +6087,11 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
+			m.TraceId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 7:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3182,34 +6118,18 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Response == nil {
+				m.Response = &types.Any{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 8:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
-			}
-			m.Id = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowModel
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Id |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryTimestampMs", wireType)
 			}
-			var msglen int
+			m.QueryTimestampMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3219,28 +6139,11 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.QueryTimestampMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Hints == nil {
-				m.Hints = &Hints{}
-			}
-			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -3265,7 +6168,7 @@ func (m *PrometheusRangeQueryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
+func (m *Options) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3288,17 +6191,17 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PrometheusInstantQueryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Options: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PrometheusInstantQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Options: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CacheDisabled", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3308,29 +6211,17 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Path = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			m.CacheDisabled = bool(v != 0)
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardingDisabled", wireType)
 			}
-			m.Time = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3340,16 +6231,17 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Time |= int64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.ShardingDisabled = bool(v != 0)
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalShards", wireType)
 			}
-			var stringLen uint64
+			m.TotalShards = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3359,29 +6251,16 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.TotalShards |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Query = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InstantSplitDisabled", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3391,30 +6270,55 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
+			m.InstantSplitDisabled = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InstantSplitInterval", wireType)
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
+			m.InstantSplitInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InstantSplitInterval |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Explain", wireType)
 			}
-			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Explain = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Explain |= ExplainMode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PartialResponse", wireType)
 			}
-			m.Id = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3424,16 +6328,29 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Id |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PartialResponse = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3443,27 +6360,23 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Hints == nil {
-				m.Hints = &Hints{}
-			}
-			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Priority = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3489,7 +6402,7 @@ func (m *PrometheusInstantQueryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
+func (m *Hints) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3512,17 +6425,17 @@ func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PrometheusResponseHeader: wiretype end group for non-group")
+			return fmt.Errorf("proto: Hints: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PrometheusResponseHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Hints: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalQueries", wireType)
 			}
-			var stringLen uint64
+			m.TotalQueries = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3532,29 +6445,16 @@ func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.TotalQueries |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Name = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedSeriesCount", wireType)
 			}
-			var stringLen uint64
+			var v uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3564,24 +6464,12 @@ func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
+			m.CardinalityEstimate = &Hints_EstimatedSeriesCount{v}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -3606,7 +6494,7 @@ func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
+func (m *QueryExplanation) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3629,15 +6517,15 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PrometheusResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryExplanation: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PrometheusResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryExplanation: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3665,11 +6553,11 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Status = string(dAtA[iNdEx:postIndex])
+			m.Mode = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RewrittenQueries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3696,18 +6584,16 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Data == nil {
-				m.Data = &PrometheusData{}
-			}
-			if err := m.Data.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.RewrittenQueries = append(m.RewrittenQueries, RewrittenQuery{})
+			if err := m.RewrittenQueries[len(m.RewrittenQueries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PartialQueries", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3717,59 +6603,40 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ErrorType = string(dAtA[iNdEx:postIndex])
+			m.PartialQueries = append(m.PartialQueries, PartialQuery{})
+			if err := m.PartialQueries[len(m.PartialQueries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowModel
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationSeconds", wireType)
 			}
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Error = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.DurationSeconds = float64(math.Float64frombits(v))
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Steps", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3796,8 +6663,8 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Headers = append(m.Headers, &PrometheusResponseHeader{})
-			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Steps = append(m.Steps, QueryStepTiming{})
+			if err := m.Steps[len(m.Steps)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -3825,7 +6692,7 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PrometheusData) Unmarshal(dAtA []byte) error {
+func (m *QueryStepTiming) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3848,15 +6715,15 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PrometheusData: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryStepTiming: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PrometheusData: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryStepTiming: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResultType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3884,13 +6751,13 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ResultType = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Calls", wireType)
 			}
-			var msglen int
+			m.Calls = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3900,26 +6767,22 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Calls |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationSeconds", wireType)
 			}
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Result = append(m.Result, SampleStream{})
-			if err := m.Result[len(m.Result)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.DurationSeconds = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -3944,7 +6807,7 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SampleStream) Unmarshal(dAtA []byte) error {
+func (m *RewrittenQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3967,17 +6830,17 @@ func (m *SampleStream) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SampleStream: wiretype end group for non-group")
+			return fmt.Errorf("proto: RewrittenQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SampleStream: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RewrittenQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Middleware", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -3987,31 +6850,29 @@ func (m *SampleStream) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Labels = append(m.Labels, github_com_grafana_mimir_pkg_mimirpb.LabelAdapter{})
-			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Middleware = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Original", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4021,31 +6882,29 @@ func (m *SampleStream) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Samples = append(m.Samples, mimirpb.Sample{})
-			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Original = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Histograms", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Rewritten", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4055,26 +6914,43 @@ func (m *SampleStream) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Histograms = append(m.Histograms, mimirpb.FloatHistogramPair{})
-			if err := m.Histograms[len(m.Histograms)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Rewritten = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PartialQueries", wireType)
+			}
+			m.PartialQueries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PartialQueries |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -4099,7 +6975,7 @@ func (m *SampleStream) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CachedResponse) Unmarshal(dAtA []byte) error {
+func (m *PartialQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4122,15 +6998,15 @@ func (m *CachedResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CachedResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PartialQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CachedResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PartialQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4158,13 +7034,51 @@ func (m *CachedResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Key = string(dAtA[iNdEx:postIndex])
+			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Extents", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Step", wireType)
 			}
-			var msglen int
+			m.Step = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4174,84 +7088,27 @@ func (m *CachedResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Step |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Extents = append(m.Extents, Extent{})
-			if err := m.Extents[len(m.Extents)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipModel(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthModel
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthModel
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Extent) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowModel
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationSeconds", wireType)
 			}
-			if iNdEx >= l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Extent: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Extent: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.DurationSeconds = float64(math.Float64frombits(v))
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
 			}
-			m.Start = 0
+			m.FetchedSeriesCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4261,16 +7118,16 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Start |= int64(b&0x7F) << shift
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
 			}
-			m.End = 0
+			m.FetchedChunkBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4280,16 +7137,16 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= int64(b&0x7F) << shift
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Stores", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4299,29 +7156,31 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TraceId = string(dAtA[iNdEx:postIndex])
+			m.Stores = append(m.Stores, PartialQueryStoreStats{})
+			if err := m.Stores[len(m.Stores)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 5:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4331,33 +7190,29 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthModel
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Response == nil {
-				m.Response = &types.Any{}
-			}
-			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Error = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryTimestampMs", wireType)
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenants", wireType)
 			}
-			m.QueryTimestampMs = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4367,11 +7222,26 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.QueryTimestampMs |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenants = append(m.Tenants, PartialQueryTenantStats{})
+			if err := m.Tenants[len(m.Tenants)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -4396,7 +7266,7 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Options) Unmarshal(dAtA []byte) error {
+func (m *PartialQueryStoreStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4419,17 +7289,17 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Options: wiretype end group for non-group")
+			return fmt.Errorf("proto: PartialQueryStoreStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Options: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PartialQueryStoreStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CacheDisabled", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Component", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4439,17 +7309,29 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.CacheDisabled = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Component = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ShardingDisabled", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4459,17 +7341,29 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.ShardingDisabled = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalShards", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
 			}
-			m.TotalShards = 0
+			m.FetchedSeriesCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4479,16 +7373,16 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalShards |= int32(b&0x7F) << shift
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InstantSplitDisabled", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
 			}
-			var v int
+			m.FetchedChunkBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4498,31 +7392,22 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.InstantSplitDisabled = bool(v != 0)
 		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InstantSplitInterval", wireType)
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencySeconds", wireType)
 			}
-			m.InstantSplitInterval = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowModel
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.InstantSplitInterval |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
 			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.LatencySeconds = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -4547,7 +7432,7 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Hints) Unmarshal(dAtA []byte) error {
+func (m *PartialQueryTenantStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4570,17 +7455,17 @@ func (m *Hints) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Hints: wiretype end group for non-group")
+			return fmt.Errorf("proto: PartialQueryTenantStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Hints: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PartialQueryTenantStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalQueries", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantID", wireType)
 			}
-			m.TotalQueries = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4590,16 +7475,29 @@ func (m *Hints) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalQueries |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TenantID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedSeriesCount", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
 			}
-			var v uint64
+			m.FetchedSeriesCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -4609,12 +7507,41 @@ func (m *Hints) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= uint64(b&0x7F) << shift
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.CardinalityEstimate = &Hints_EstimatedSeriesCount{v}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
+			}
+			m.FetchedChunkBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueTimeSeconds", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.QueueTimeSeconds = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
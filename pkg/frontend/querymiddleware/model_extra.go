@@ -42,6 +42,14 @@ func newEmptyPrometheusResponse() *PrometheusResponse {
 	}
 }
 
+// newEmptyResponse returns an empty successful response to the input request.
+func newEmptyResponse(r Request) Response {
+	if remoteReadReq, ok := r.(*remoteReadRequest); ok {
+		return remoteReadReq.emptyResponse()
+	}
+	return newEmptyPrometheusResponse()
+}
+
 // WithID clones the current `PrometheusRangeQueryRequest` with the provided ID.
 func (q *PrometheusRangeQueryRequest) WithID(id int64) Request {
 	newRequest := *q
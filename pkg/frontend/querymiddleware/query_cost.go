@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/cache"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/promql/parser"
+	"golang.org/x/time/rate"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	queryCostRejectReasonMaxCost         = "max-cost"
+	queryCostRejectReasonBudgetExhausted = "budget-exhausted"
+
+	// queryCostBudgetsEvictionInterval is how often the rate limiters of the tenants whose query
+	// cost budget is fully replenished are removed.
+	queryCostBudgetsEvictionInterval = time.Minute
+
+	// queryCostFallbackSeriesPerSelector is the number of series each selector of a query is assumed to
+	// fetch when there's no estimate of the number of series fetched by the query.
+	queryCostFallbackSeriesPerSelector = 1000
+)
+
+// queryCost is a Handler that admits queries based on their estimated cost. The cost of a query is
+// the estimated number of series it fetches multiplied by the number of steps the query is evaluated at.
+// The cost is estimated for the whole query, before it's split and sharded, from the number of series
+// fetched by the previous executions of similar queries, which are cached in the results cache.
+// Queries without an estimate, such as the first execution of a query, are assumed to fetch
+// queryCostFallbackSeriesPerSelector series per selector when they're charged to the query cost
+// budget, but they're not rejected by the max query cost, because they would never get an estimate.
+// The query cost budgets are tracked by each query-frontend replica on its own.
+type queryCost struct {
+	next     Handler
+	cache    cache.Cache
+	limits   Limits
+	logger   log.Logger
+	budgets  *queryCostBudgets
+	rejected *prometheus.CounterVec
+	waiting  prometheus.Histogram
+}
+
+func newQueryCostMiddleware(cache cache.Cache, limits Limits, logger log.Logger, registerer prometheus.Registerer) Middleware {
+	budgets := newQueryCostBudgets()
+	rejected := promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+		Name: "cortex_query_frontend_query_cost_rejected_queries_total",
+		Help: "Number of queries rejected because of their estimated cost.",
+	}, []string{"reason"})
+	waiting := promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
+		Name:    "cortex_query_frontend_query_cost_budget_wait_duration_seconds",
+		Help:    "Time queries waited for the query cost budget of the tenant to be replenished.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// Pre-initialize the metrics.
+	rejected.WithLabelValues(queryCostRejectReasonMaxCost)
+	rejected.WithLabelValues(queryCostRejectReasonBudgetExhausted)
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return &queryCost{
+			next:     next,
+			cache:    cache,
+			limits:   limits,
+			logger:   logger,
+			budgets:  budgets,
+			rejected: rejected,
+			waiting:  waiting,
+		}
+	})
+}
+
+func (q *queryCost) Do(ctx context.Context, r Request) (Response, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+	userID := tenant.JoinTenantIDs(tenantIDs)
+
+	maxCost := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, q.limits.MaxQueryCost)
+	budget := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, q.limits.QueryCostBudgetPerMinute)
+	if budget <= 0 {
+		q.budgets.remove(userID)
+	}
+
+	// The query is not executed when only the plan is requested, so it doesn't cost anything.
+	if (maxCost <= 0 && budget <= 0) || r.GetOptions().Explain == ExplainMode_PLAN {
+		return q.next.Do(ctx, r)
+	}
+
+	spanLog := spanlogger.FromContext(ctx, q.logger)
+	key := generateQueryCostCacheKey(userID, r)
+	estimatedSeries, estimateAvailable := lookupCardinalityEstimate(ctx, q.cache, q.logger, key)
+
+	// Queries without an estimate are charged a fallback cost to the budget, but they're not rejected
+	// by the max cost, because a rejected query would never get an estimate.
+	admitSeries := estimatedSeries
+	if !estimateAvailable {
+		admitSeries = fallbackEstimatedSeries(r)
+		maxCost = 0
+	}
+	if err := q.admit(ctx, spanLog, userID, admitSeries, queryCostSteps(r), maxCost, budget); err != nil {
+		return nil, err
+	}
+
+	// Track the stats of the query on their own, because the query stats may not be enabled.
+	queryStats, queryCtx := stats.ContextWithEmptyStats(ctx)
+	res, err := q.next.Do(queryCtx, r)
+	stats.FromContext(ctx).Merge(queryStats)
+	if err != nil {
+		return nil, err
+	}
+
+	actualSeries := queryStats.LoadFetchedSeries()
+	if !estimateAvailable || !isCardinalitySimilar(actualSeries, estimatedSeries) {
+		storeCardinalityEstimate(q.cache, q.logger, key, actualSeries)
+	}
+	return res, nil
+}
+
+// admit returns an error if the query can't be admitted because of its cost, otherwise waits for
+// the query cost budget of the tenant to cover the cost of the query.
+func (q *queryCost) admit(ctx context.Context, spanLog *spanlogger.SpanLogger, userID string, estimatedSeries uint64, steps int64, maxCost, budget int) error {
+	cost := estimateQueryCost(estimatedSeries, steps)
+
+	if maxCost > 0 && cost > uint64(maxCost) {
+		q.rejected.WithLabelValues(queryCostRejectReasonMaxCost).Inc()
+		return apierror.New(apierror.TypeExec, validation.NewMaxQueryCostError(cost, estimatedSeries, steps, maxCost).Error())
+	}
+	if budget <= 0 {
+		return nil
+	}
+
+	// A query costing more than the whole budget can't be admitted by the rate limiter, so it waits
+	// for the whole budget to be available instead.
+	tokens := budget
+	if cost < uint64(budget) {
+		tokens = int(cost)
+	}
+
+	start := time.Now()
+	err := q.budgets.limiter(userID, budget).WaitN(ctx, tokens)
+	waited := time.Since(start)
+	q.waiting.Observe(waited.Seconds())
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// The limiter doesn't wait if the query can't be admitted before its deadline.
+		q.rejected.WithLabelValues(queryCostRejectReasonBudgetExhausted).Inc()
+		return apierror.New(apierror.TypeTooManyRequests, validation.NewQueryCostBudgetExhaustedError(budget).Error())
+	}
+
+	if waited > 0 {
+		level.Debug(spanLog).Log("msg", "query delayed because of the query cost budget of the tenant", "cost", cost, "budget", budget, "waited", waited)
+	}
+	return nil
+}
+
+// generateQueryCostCacheKey generates a key to cache the number of series fetched by a query under.
+// The key is different from the cardinality estimation one, because the cardinality estimation is stored
+// for the partial queries the query is split into, which may have the same key as a whole query. The
+// series of the partial queries served from the results cache are not fetched, so they're not counted.
+func generateQueryCostCacheKey(userID string, r Request) string {
+	// Prefix key with `QC` (short for "query cost").
+	return generateQueryStatisticsCacheKey("QC", userID, r, cardinalityEstimateBucketSize)
+}
+
+// fallbackEstimatedSeries returns the number of series assumed to be fetched by a query without an
+// estimate, based on the number of selectors in the query.
+func fallbackEstimatedSeries(r Request) uint64 {
+	expr, err := parser.ParseExpr(r.GetQuery())
+	if err != nil {
+		// The query will fail anyway, but it's charged as a single selector.
+		return queryCostFallbackSeriesPerSelector
+	}
+
+	selectors := uint64(0)
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		if _, ok := node.(*parser.VectorSelector); ok {
+			selectors++
+		}
+		return nil
+	})
+	return selectors * queryCostFallbackSeriesPerSelector
+}
+
+// queryCostSteps returns the number of steps the query is evaluated at. Instant queries are
+// evaluated at a single step.
+func queryCostSteps(r Request) int64 {
+	if r.GetStep() <= 0 {
+		return 1
+	}
+	return (r.GetEnd()-r.GetStart())/r.GetStep() + 1
+}
+
+// estimateQueryCost returns the estimated cost of a query, capped to the max uint64 on overflow.
+func estimateQueryCost(estimatedSeries uint64, steps int64) uint64 {
+	if estimatedSeries != 0 && uint64(steps) > math.MaxUint64/estimatedSeries {
+		return math.MaxUint64
+	}
+	return estimatedSeries * uint64(steps)
+}
+
+// queryCostBudgets holds the query cost budget of each tenant, or set of tenants for federated queries.
+// The budgets are held in memory, so each query-frontend replica admits up to the whole budget of a tenant.
+type queryCostBudgets struct {
+	mtx          sync.Mutex
+	limiters     map[string]*rate.Limiter
+	lastEviction time.Time
+}
+
+func newQueryCostBudgets() *queryCostBudgets {
+	return &queryCostBudgets{limiters: map[string]*rate.Limiter{}, lastEviction: time.Now()}
+}
+
+// limiter returns the rate limiter of the input tenant, updated to the input budget per minute.
+func (b *queryCostBudgets) limiter(tenantID string, budget int) *rate.Limiter {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if now := time.Now(); now.Sub(b.lastEviction) >= queryCostBudgetsEvictionInterval {
+		b.evictReplenished(now)
+		b.lastEviction = now
+	}
+
+	limit := rate.Limit(float64(budget) / time.Minute.Seconds())
+	l, ok := b.limiters[tenantID]
+	if !ok {
+		l = rate.NewLimiter(limit, budget)
+		b.limiters[tenantID] = l
+		return l
+	}
+
+	if l.Limit() != limit {
+		l.SetLimit(limit)
+	}
+	if l.Burst() != budget {
+		l.SetBurst(budget)
+	}
+	return l
+}
+
+// evictReplenished removes the rate limiters whose budget is fully replenished, because they're
+// equivalent to new ones. This removes the rate limiters of the tenants not running queries anymore.
+// Must be called with the lock held.
+func (b *queryCostBudgets) evictReplenished(now time.Time) {
+	for tenantID, l := range b.limiters {
+		if l.TokensAt(now) >= float64(l.Burst()) {
+			delete(b.limiters, tenantID)
+		}
+	}
+}
+
+// remove removes the rate limiter of the input tenant, if any.
+func (b *queryCostBudgets) remove(tenantID string) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	delete(b.limiters, tenantID)
+}
@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/cache"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/querier/stats"
+)
+
+func TestQueryCostMiddleware_MaxQueryCost(t *testing.T) {
+	now := time.Now()
+
+	rangeQuery := &PrometheusRangeQueryRequest{
+		Start: now.Add(-time.Hour).UnixMilli(),
+		End:   now.UnixMilli(),
+		Step:  time.Minute.Milliseconds(),
+		Query: "up",
+	}
+	instantQuery := &PrometheusInstantQueryRequest{
+		Time:  now.UnixMilli(),
+		Query: "up",
+	}
+
+	tests := map[string]struct {
+		request         Request
+		estimatedSeries uint64
+		maxQueryCost    int
+		expectedError   string
+	}{
+		"should admit a range query without estimate": {
+			request:      rangeQuery,
+			maxQueryCost: 1,
+		},
+		"should admit a range query with a cost lower than the limit": {
+			// The range query has 61 steps.
+			request:         rangeQuery,
+			estimatedSeries: 100,
+			maxQueryCost:    6100,
+		},
+		"should reject a range query with a cost higher than the limit": {
+			request:         rangeQuery,
+			estimatedSeries: 100,
+			maxQueryCost:    6099,
+			expectedError:   "the estimated cost of the query exceeds the limit (query cost: 6100, estimated series: 100, steps: 61, limit: 6099)",
+		},
+		"should admit a range query when the limit is disabled": {
+			request:         rangeQuery,
+			estimatedSeries: 100,
+			maxQueryCost:    0,
+		},
+		"should admit a range query when only the plan is requested": {
+			request: &PrometheusRangeQueryRequest{
+				Start:   rangeQuery.Start,
+				End:     rangeQuery.End,
+				Step:    rangeQuery.Step,
+				Query:   rangeQuery.Query,
+				Options: Options{Explain: ExplainMode_PLAN},
+			},
+			estimatedSeries: 100,
+			maxQueryCost:    6099,
+		},
+		"should admit an instant query with a cost lower than the limit": {
+			request:         instantQuery,
+			estimatedSeries: 100,
+			maxQueryCost:    100,
+		},
+		"should reject an instant query with a cost higher than the limit": {
+			request:         instantQuery,
+			estimatedSeries: 101,
+			maxQueryCost:    100,
+			expectedError:   "the estimated cost of the query exceeds the limit (query cost: 101, estimated series: 101, steps: 1, limit: 100)",
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reg := prometheus.NewPedanticRegistry()
+			limits := mockLimits{maxQueryCost: testData.maxQueryCost}
+			inner := &mockHandler{}
+			inner.On("Do", mock.Anything, mock.Anything).Return(&PrometheusResponse{}, nil)
+
+			c := newQueryCostTestCache("test", testData.request, testData.estimatedSeries)
+			ctx := user.InjectOrgID(context.Background(), "test")
+			_, err := newQueryCostMiddleware(c, limits, log.NewNopLogger(), reg).Wrap(inner).Do(ctx, testData.request)
+
+			if testData.expectedError == "" {
+				require.NoError(t, err)
+				inner.AssertNumberOfCalls(t, "Do", 1)
+				assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+					# HELP cortex_query_frontend_query_cost_rejected_queries_total Number of queries rejected because of their estimated cost.
+					# TYPE cortex_query_frontend_query_cost_rejected_queries_total counter
+					cortex_query_frontend_query_cost_rejected_queries_total{reason="budget-exhausted"} 0
+					cortex_query_frontend_query_cost_rejected_queries_total{reason="max-cost"} 0
+				`), "cortex_query_frontend_query_cost_rejected_queries_total"))
+				return
+			}
+
+			require.Error(t, err)
+			resp, ok := apierror.HTTPResponseFromError(err)
+			require.True(t, ok)
+			assert.Equal(t, int32(http.StatusUnprocessableEntity), resp.Code)
+			assert.Contains(t, err.Error(), testData.expectedError)
+			inner.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+			assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+				# HELP cortex_query_frontend_query_cost_rejected_queries_total Number of queries rejected because of their estimated cost.
+				# TYPE cortex_query_frontend_query_cost_rejected_queries_total counter
+				cortex_query_frontend_query_cost_rejected_queries_total{reason="budget-exhausted"} 0
+				cortex_query_frontend_query_cost_rejected_queries_total{reason="max-cost"} 1
+			`), "cortex_query_frontend_query_cost_rejected_queries_total"))
+		})
+	}
+}
+
+func TestQueryCostMiddleware_MaxQueryCost_MultiTenant(t *testing.T) {
+	tenant.WithDefaultResolver(tenant.NewMultiResolver())
+	t.Cleanup(func() {
+		tenant.WithDefaultResolver(tenant.NewSingleResolver())
+	})
+
+	limits := multiTenantMockLimits{
+		byTenant: map[string]mockLimits{
+			"tenant-1": {maxQueryCost: 0},
+			"tenant-2": {maxQueryCost: 50},
+		},
+	}
+	inner := &mockHandler{}
+	inner.On("Do", mock.Anything, mock.Anything).Return(&PrometheusResponse{}, nil)
+
+	req := &PrometheusInstantQueryRequest{Time: time.Now().UnixMilli(), Query: "up"}
+	c := newQueryCostTestCache("tenant-1|tenant-2", req, 100)
+	ctx := user.InjectOrgID(context.Background(), "tenant-1|tenant-2")
+	_, err := newQueryCostMiddleware(c, limits, log.NewNopLogger(), nil).Wrap(inner).Do(ctx, req)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limit: 50")
+	inner.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+}
+
+func TestQueryCostMiddleware_QueryCostBudgetPerMinute(t *testing.T) {
+	// The budget is replenished at 100 per second.
+	limits := mockLimits{queryCostBudgetPerMinute: 6000}
+	seriesByQuery := map[string]uint64{"expensive": 6000, "cheap": 50}
+	calls := atomic.NewInt32(0)
+	inner := HandlerFunc(func(ctx context.Context, r Request) (Response, error) {
+		calls.Inc()
+		stats.FromContext(ctx).AddFetchedSeries(seriesByQuery[r.GetQuery()])
+		return &PrometheusResponse{}, nil
+	})
+
+	now := time.Now()
+	expensiveQuery := &PrometheusInstantQueryRequest{Time: now.UnixMilli(), Query: "expensive"}
+	cheapQuery := &PrometheusInstantQueryRequest{Time: now.UnixMilli(), Query: "cheap"}
+
+	c := cache.NewMockCache()
+	for _, userID := range []string{"test", "another"} {
+		for query, series := range seriesByQuery {
+			storeCardinalityEstimate(c, log.NewNopLogger(), generateQueryCostCacheKey(userID, &PrometheusInstantQueryRequest{Time: now.UnixMilli(), Query: query}), series)
+		}
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	handler := newQueryCostMiddleware(c, limits, log.NewNopLogger(), reg).Wrap(inner)
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	// The first query spends the whole budget, so it's admitted without waiting.
+	start := time.Now()
+	_, err := handler.Do(ctx, expensiveQuery)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+
+	// The second query waits for the budget to be replenished.
+	start = time.Now()
+	_, err = handler.Do(ctx, cheapQuery)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	// Queries of other tenants aren't affected.
+	start = time.Now()
+	_, err = handler.Do(user.InjectOrgID(context.Background(), "another"), expensiveQuery)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+
+	// A query that can't be admitted before its deadline is rejected without waiting.
+	deadlineCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = handler.Do(deadlineCtx, expensiveQuery)
+	require.Error(t, err)
+	assert.True(t, apierror.IsAPIError(err))
+	assert.Contains(t, err.Error(), "exhausted the query cost budget")
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_query_frontend_query_cost_rejected_queries_total Number of queries rejected because of their estimated cost.
+		# TYPE cortex_query_frontend_query_cost_rejected_queries_total counter
+		cortex_query_frontend_query_cost_rejected_queries_total{reason="budget-exhausted"} 1
+		cortex_query_frontend_query_cost_rejected_queries_total{reason="max-cost"} 0
+	`), "cortex_query_frontend_query_cost_rejected_queries_total"))
+}
+
+func TestQueryCostMiddleware_QueryCostBudgetPerMinute_CostHigherThanBudget(t *testing.T) {
+	// The budget is replenished at 100 per second.
+	limits := mockLimits{queryCostBudgetPerMinute: 6000}
+	inner := &mockHandler{}
+	inner.On("Do", mock.Anything, mock.Anything).Return(&PrometheusResponse{}, nil)
+
+	query := &PrometheusInstantQueryRequest{Time: time.Now().UnixMilli(), Query: "up"}
+	c := newQueryCostTestCache("test", query, 10000)
+	handler := newQueryCostMiddleware(c, limits, log.NewNopLogger(), nil).Wrap(inner)
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	// A query costing more than the whole budget is admitted once the whole budget is available.
+	_, err := handler.Do(ctx, query)
+	require.NoError(t, err)
+	inner.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestQueryCostMiddleware_QueryCostBudgetPerMinute_QueryWithoutEstimate(t *testing.T) {
+	// The budget is replenished at 1000 per second.
+	limits := mockLimits{queryCostBudgetPerMinute: 60000}
+	inner := &mockHandler{}
+	inner.On("Do", mock.Anything, mock.Anything).Return(&PrometheusResponse{}, nil)
+
+	selectors := make([]string, 0, 60)
+	for i := 0; i < 60; i++ {
+		selectors = append(selectors, fmt.Sprintf("series_%d", i))
+	}
+
+	now := time.Now()
+	expensiveQuery := &PrometheusInstantQueryRequest{Time: now.UnixMilli(), Query: strings.Join(selectors, " + ")}
+	cheapQuery := &PrometheusInstantQueryRequest{Time: now.UnixMilli(), Query: "up"}
+
+	handler := newQueryCostMiddleware(cache.NewMockCache(), limits, log.NewNopLogger(), nil).Wrap(inner)
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	// The first query has no estimate, and its 60 selectors spend the whole budget.
+	start := time.Now()
+	_, err := handler.Do(ctx, expensiveQuery)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+
+	// The second query has no estimate either, and waits for its single selector to be covered by the budget.
+	start = time.Now()
+	_, err = handler.Do(ctx, cheapQuery)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 800*time.Millisecond)
+	inner.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestQueryCostMiddleware_EstimateFromPreviousExecutions(t *testing.T) {
+	limits := mockLimits{maxQueryCost: 1000}
+	inner := HandlerFunc(func(ctx context.Context, _ Request) (Response, error) {
+		stats.FromContext(ctx).AddFetchedSeries(200)
+		return &PrometheusResponse{}, nil
+	})
+
+	c := cache.NewMockCache()
+	handler := newQueryCostMiddleware(c, limits, log.NewNopLogger(), nil).Wrap(inner)
+	queryStats, ctx := stats.ContextWithEmptyStats(user.InjectOrgID(context.Background(), "test"))
+	now := time.Now()
+
+	// The first execution of the range query has no estimate, so it's admitted, and the number
+	// of series it fetched is stored as the estimate of the next executions.
+	rangeQuery := &PrometheusRangeQueryRequest{Start: now.Add(-time.Hour).UnixMilli(), End: now.UnixMilli(), Step: time.Minute.Milliseconds(), Query: "up"}
+	_, err := handler.Do(ctx, rangeQuery)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(200), queryStats.LoadFetchedSeries())
+
+	// The next execution is estimated to fetch 200 series over 61 steps, so it's rejected.
+	_, err = handler.Do(ctx, rangeQuery)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the estimated cost of the query exceeds the limit (query cost: 12200, estimated series: 200, steps: 61, limit: 1000)")
+
+	// An instant query fetching the same series is admitted.
+	instantQuery := &PrometheusInstantQueryRequest{Time: now.UnixMilli(), Query: "up"}
+	for i := 0; i < 2; i++ {
+		_, err = handler.Do(ctx, instantQuery)
+		require.NoError(t, err)
+	}
+}
+
+func TestQueryCostBudgets_EvictReplenished(t *testing.T) {
+	budgets := newQueryCostBudgets()
+
+	// The budget of the first tenant is spent, while the budget of the second one is untouched.
+	require.True(t, budgets.limiter("tenant-1", 6000).AllowN(time.Now(), 6000))
+	budgets.limiter("tenant-2", 6000)
+	require.Len(t, budgets.limiters, 2)
+
+	// Only the rate limiters with a fully replenished budget are evicted.
+	budgets.lastEviction = time.Now().Add(-queryCostBudgetsEvictionInterval)
+	budgets.limiter("tenant-3", 6000)
+	assert.Len(t, budgets.limiters, 2)
+	assert.Contains(t, budgets.limiters, "tenant-1")
+	assert.Contains(t, budgets.limiters, "tenant-3")
+}
+
+func TestFallbackEstimatedSeries(t *testing.T) {
+	for query, expected := range map[string]uint64{
+		"up":                       queryCostFallbackSeriesPerSelector,
+		"sum(rate(foo[5m])) / bar": 2 * queryCostFallbackSeriesPerSelector,
+		"vector(1)":                0,
+		"invalid(":                 queryCostFallbackSeriesPerSelector,
+	} {
+		t.Run(query, func(t *testing.T) {
+			assert.Equal(t, expected, fallbackEstimatedSeries(&PrometheusInstantQueryRequest{Query: query}))
+		})
+	}
+}
+
+func TestEstimateQueryCost(t *testing.T) {
+	assert.Equal(t, uint64(0), estimateQueryCost(0, 10))
+	assert.Equal(t, uint64(100), estimateQueryCost(10, 10))
+	assert.Equal(t, uint64(math.MaxUint64), estimateQueryCost(math.MaxUint64/2, 3))
+}
+
+// newQueryCostTestCache returns a cache with the input estimated number of series of the request,
+// or an empty cache if the estimated number of series is 0.
+func newQueryCostTestCache(userID string, r Request, estimatedSeries uint64) cache.Cache {
+	c := cache.NewMockCache()
+	if estimatedSeries > 0 {
+		storeCardinalityEstimate(c, log.NewNopLogger(), generateQueryCostCacheKey(userID, r), estimatedSeries)
+	}
+	return c
+}
@@ -144,6 +144,7 @@ func (s *querySharding) Do(ctx context.Context, r Request) (Response, error) {
 	// Update query stats.
 	queryStats := stats.FromContext(ctx)
 	queryStats.AddShardedQueries(uint32(shardingStats.GetShardedQueries()))
+	queryExplainerFromContext(ctx).recordRewrittenQuery("querysharding", r.GetQuery(), shardedQuery, shardingStats.GetShardedQueries())
 
 	r = r.WithQuery(shardedQuery)
 	shardedQueryable := newShardedQueryable(r, s.next)
@@ -164,7 +165,8 @@ func (s *querySharding) Do(ctx context.Context, r Request) (Response, error) {
 			ResultType: string(res.Value.Type()),
 			Result:     extracted,
 		},
-		Headers: shardedQueryable.getResponseHeaders(),
+		Headers:  shardedQueryable.getResponseHeaders(),
+		Warnings: warningsToStrings(res.Warnings),
 	}, nil
 }
 
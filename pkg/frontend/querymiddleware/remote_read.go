@@ -0,0 +1,992 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/tenant"
+	"github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	prom_remote "github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"go.uber.org/atomic"
+	"golang.org/x/exp/slices"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/storage/sharding"
+	"github.com/grafana/mimir/pkg/util"
+	util_math "github.com/grafana/mimir/pkg/util/math"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	// Remote read queries are a set of matchers with time ranges, so they should not get into megabytes.
+	maxRemoteReadQuerySize = 1024 * 1024
+
+	// Maximum number of bytes in a frame of a streamed remote read response. Same as the querier.
+	maxRemoteReadFrameBytes = 1024 * 1024
+
+	// Maximum size of a (decompressed) remote read response received from the queriers, when the
+	// response size isn't limited for the tenant. It's the maximum size of a protobuf message.
+	maxRemoteReadResponseMessageBytes = math.MaxInt32
+
+	remoteReadSamplesContentType        = "application/x-protobuf"
+	remoteReadStreamedChunksContentType = "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse"
+)
+
+// remoteReadRequest is a Prometheus remote read request. It implements the Request interface,
+// so that remote read requests can be processed by the query middlewares like other queries.
+type remoteReadRequest struct {
+	path string
+	id   int64
+
+	// responseType is the response type negotiated with the client. Queriers are asked to respond
+	// with the same response type.
+	responseType client.ReadRequest_ResponseType
+
+	// remoteClusterRead is true if the request has been sent by a remote cluster of a federated query.
+	remoteClusterRead bool
+
+	// priority is the name of the priority class of the request, or empty for ad-hoc requests.
+	priority string
+
+	// totalQueries is the number of queries in the remote read request received by the query-frontend.
+	totalQueries int
+
+	// queries to run. Each query is a part of a query in the remote read request received by the query-frontend.
+	queries []remoteReadQuery
+
+	hints *Hints
+}
+
+// remoteReadQuery is a single query of a remote read request.
+type remoteReadQuery struct {
+	// index of the query in the remote read request received by the query-frontend.
+	index    int
+	start    int64
+	end      int64
+	matchers []*labels.Matcher
+}
+
+func (q remoteReadQuery) String() string {
+	return (&parser.VectorSelector{LabelMatchers: q.matchers}).String()
+}
+
+func (r *remoteReadRequest) GetId() int64 {
+	return r.id
+}
+
+// GetStart returns the smallest start timestamp of the queries in the request.
+func (r *remoteReadRequest) GetStart() int64 {
+	if len(r.queries) == 0 {
+		return 0
+	}
+
+	start := r.queries[0].start
+	for _, q := range r.queries[1:] {
+		start = util_math.Min(start, q.start)
+	}
+	return start
+}
+
+// GetEnd returns the largest end timestamp of the queries in the request.
+func (r *remoteReadRequest) GetEnd() int64 {
+	if len(r.queries) == 0 {
+		return 0
+	}
+
+	end := r.queries[0].end
+	for _, q := range r.queries[1:] {
+		end = util_math.Max(end, q.end)
+	}
+	return end
+}
+
+func (r *remoteReadRequest) GetStep() int64 {
+	return 0
+}
+
+// GetQuery returns the selectors of the queries in the request, joined with the "or" operator.
+func (r *remoteReadRequest) GetQuery() string {
+	selectors := make([]string, 0, len(r.queries))
+	for _, q := range r.queries {
+		selectors = append(selectors, q.String())
+	}
+	return strings.Join(selectors, " or ")
+}
+
+func (r *remoteReadRequest) GetOptions() Options {
+	return Options{Priority: r.priority}
+}
+
+func (r *remoteReadRequest) GetHints() *Hints {
+	return r.hints
+}
+
+func (r *remoteReadRequest) WithID(id int64) Request {
+	newRequest := *r
+	newRequest.id = id
+	return &newRequest
+}
+
+// WithStartEnd clones the current remoteReadRequest, clamping the time range of each query to the new `start` and `end` timestamp.
+func (r *remoteReadRequest) WithStartEnd(start int64, end int64) Request {
+	newRequest := *r
+	newRequest.queries = make([]remoteReadQuery, 0, len(r.queries))
+	for _, q := range r.queries {
+		q.start = util_math.Max(q.start, start)
+		q.end = util_math.Min(q.end, end)
+		newRequest.queries = append(newRequest.queries, q)
+	}
+	return &newRequest
+}
+
+// WithQuery returns the current remoteReadRequest: the queries of a remote read request are defined by
+// label matchers, so they can't be replaced by a PromQL query.
+func (r *remoteReadRequest) WithQuery(string) Request {
+	return r
+}
+
+func (r *remoteReadRequest) WithTotalQueriesHint(totalQueries int32) Request {
+	newRequest := *r
+	if newRequest.hints == nil {
+		newRequest.hints = &Hints{TotalQueries: totalQueries}
+	} else {
+		hints := *r.hints
+		hints.TotalQueries = totalQueries
+		newRequest.hints = &hints
+	}
+	return &newRequest
+}
+
+func (r *remoteReadRequest) WithEstimatedSeriesCountHint(count uint64) Request {
+	newRequest := *r
+	if newRequest.hints == nil {
+		newRequest.hints = &Hints{CardinalityEstimate: &Hints_EstimatedSeriesCount{count}}
+	} else {
+		hints := *r.hints
+		hints.CardinalityEstimate = &Hints_EstimatedSeriesCount{count}
+		newRequest.hints = &hints
+	}
+	return &newRequest
+}
+
+// withQueries clones the current remoteReadRequest with different queries.
+func (r *remoteReadRequest) withQueries(queries ...remoteReadQuery) *remoteReadRequest {
+	newRequest := *r
+	newRequest.queries = queries
+	return &newRequest
+}
+
+// emptyResponse returns a response with no series for each query in the request.
+func (r *remoteReadRequest) emptyResponse() *remoteReadResponse {
+	resp := &remoteReadResponse{
+		responseType: r.responseType,
+		results:      make([]*remoteReadQueryResult, r.totalQueries),
+	}
+	for _, q := range r.queries {
+		resp.results[q.index] = &remoteReadQueryResult{start: q.start}
+	}
+	return resp
+}
+
+// LogToSpan logs the current remoteReadRequest parameters to the specified span.
+func (r *remoteReadRequest) LogToSpan(sp opentracing.Span) {
+	sp.LogFields(
+		otlog.String("query", r.GetQuery()),
+		otlog.String("start", timestamp.Time(r.GetStart()).String()),
+		otlog.String("end", timestamp.Time(r.GetEnd()).String()),
+		otlog.String("response type", r.responseType.String()),
+	)
+}
+
+func (r *remoteReadRequest) Reset() {
+	*r = remoteReadRequest{}
+}
+
+func (r *remoteReadRequest) String() string {
+	return fmt.Sprintf("remote read: %s, start: %d, end: %d", r.GetQuery(), r.GetStart(), r.GetEnd())
+}
+
+func (r *remoteReadRequest) ProtoMessage() {}
+
+// remoteReadResponse is the response to a remoteReadRequest.
+type remoteReadResponse struct {
+	responseType client.ReadRequest_ResponseType
+
+	// results has one entry for each query in the remote read request received by the query-frontend.
+	// The entry is nil if the query wasn't part of the request this is the response to.
+	results []*remoteReadQueryResult
+}
+
+// remoteReadQueryResult is the result of a single query of a remote read request.
+type remoteReadQueryResult struct {
+	// start is the start timestamp of the query, used to merge the results of the time-split queries in order.
+	start  int64
+	series []remoteReadSeries
+}
+
+// remoteReadSeries is a series in the result of a remote read query. Depending on the response type,
+// either the samples and histograms or the chunks are set.
+type remoteReadSeries struct {
+	labels     labels.Labels
+	samples    []mimirpb.Sample
+	histograms []mimirpb.Histogram
+	chunks     []client.StreamChunk
+}
+
+func (r *remoteReadResponse) GetHeaders() []*PrometheusResponseHeader {
+	return nil
+}
+
+func (r *remoteReadResponse) GetWarnings() []string {
+	return nil
+}
+
+func (r *remoteReadResponse) Reset() {
+	*r = remoteReadResponse{}
+}
+
+func (r *remoteReadResponse) String() string {
+	return fmt.Sprintf("remote read response: %s, queries: %d", r.responseType.String(), len(r.results))
+}
+
+func (r *remoteReadResponse) ProtoMessage() {}
+
+func isRemoteReadQuery(path string) bool {
+	return strings.HasSuffix(path, remoteReadPathSuffix)
+}
+
+func (prometheusCodec) decodeRemoteReadRequest(ctx context.Context, r *http.Request) (Request, error) {
+	var req client.ReadRequest
+	if _, err := util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRemoteReadQuerySize, nil, &req, util.RawSnappy); err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	responseType, err := negotiateRemoteReadResponseType(req.AcceptedResponseTypes)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	result := &remoteReadRequest{
+		path:              r.URL.Path,
+		responseType:      responseType,
+		remoteClusterRead: r.Header.Get(querierapi.RemoteClusterReadHeader) != "",
+		priority:          decodePriorityOption(r),
+		totalQueries:      len(req.Queries),
+		queries:           make([]remoteReadQuery, 0, len(req.Queries)),
+	}
+	for i, q := range req.Queries {
+		start, end, matchers, err := client.FromQueryRequest(q)
+		if err != nil {
+			return nil, apierror.New(apierror.TypeBadData, err.Error())
+		}
+		result.queries = append(result.queries, remoteReadQuery{index: i, start: int64(start), end: int64(end), matchers: matchers})
+	}
+	return result, nil
+}
+
+// negotiateRemoteReadResponseType returns the first response type accepted by the client and supported by the queriers.
+func negotiateRemoteReadResponseType(accepted []client.ReadRequest_ResponseType) (client.ReadRequest_ResponseType, error) {
+	if len(accepted) == 0 {
+		return client.SAMPLES, nil
+	}
+
+	for _, responseType := range accepted {
+		if responseType == client.SAMPLES || responseType == client.STREAMED_XOR_CHUNKS {
+			return responseType, nil
+		}
+	}
+	return 0, fmt.Errorf("server does not support any of the requested response types: %v; supported: %v", accepted, []client.ReadRequest_ResponseType{client.SAMPLES, client.STREAMED_XOR_CHUNKS})
+}
+
+func (prometheusCodec) encodeRemoteReadRequest(ctx context.Context, r *remoteReadRequest) (*http.Request, error) {
+	req := client.ReadRequest{
+		Queries:               make([]*client.QueryRequest, 0, len(r.queries)),
+		AcceptedResponseTypes: []client.ReadRequest_ResponseType{r.responseType},
+	}
+	for _, q := range r.queries {
+		queryReq, err := client.ToQueryRequest(model.Time(q.start), model.Time(q.end), q.matchers)
+		if err != nil {
+			return nil, err
+		}
+		req.Queries = append(req.Queries, queryReq)
+	}
+
+	data, err := proto.Marshal(&req)
+	if err != nil {
+		return nil, err
+	}
+	body := snappy.Encode(nil, data)
+
+	u := &url.URL{Path: r.path}
+	httpReq := &http.Request{
+		Method:        "POST",
+		RequestURI:    u.String(), // This is what the httpgrpc code looks at.
+		URL:           u,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Header:        http.Header{},
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", remoteReadSamplesContentType)
+	httpReq.Header.Set("X-Prometheus-Remote-Read-Version", "0.1.0")
+	if r.remoteClusterRead {
+		httpReq.Header.Set(querierapi.RemoteClusterReadHeader, "true")
+	}
+	if r.priority != "" {
+		httpReq.Header.Set(querierapi.QueryPriorityHeader, r.priority)
+	}
+
+	return httpReq.WithContext(ctx), nil
+}
+
+// decodeRemoteReadResponse decodes the response of a querier to a remote read request. The samples outside
+// the time range of each query are discarded, because the queries could be split by time and queriers return
+// the samples of the chunks overlapping the queried time range.
+func (c prometheusCodec) decodeRemoteReadResponse(ctx context.Context, r *http.Response, req *remoteReadRequest, logger log.Logger) (Response, error) {
+	if r.StatusCode/100 != 2 {
+		return nil, apierror.New(apierror.TypeBadData, string(mustReadResponseBody(r)))
+	}
+
+	log := spanlogger.FromContext(ctx, logger)
+	log.LogFields(otlog.String("message", "ParseRemoteReadResponse"), otlog.Int("status_code", r.StatusCode))
+
+	resp := &remoteReadResponse{results: make([]*remoteReadQueryResult, req.totalQueries)}
+	for _, q := range req.queries {
+		resp.results[q.index] = &remoteReadQueryResult{start: q.start}
+	}
+
+	var (
+		size = remoteReadResponseSizeFromContext(ctx)
+		err  error
+	)
+	switch contentType := r.Header.Get("Content-Type"); contentType {
+	case remoteReadStreamedChunksContentType:
+		resp.responseType = client.STREAMED_XOR_CHUNKS
+		err = decodeStreamedChunksRemoteReadResponse(r.Body, req, resp, size)
+	case remoteReadSamplesContentType:
+		resp.responseType = client.SAMPLES
+		err = decodeSamplesRemoteReadResponse(ctx, r, req, resp, size)
+	default:
+		return nil, apierror.Newf(apierror.TypeInternal, "unknown remote read response content type '%v'", contentType)
+	}
+	if err != nil {
+		log.Error(err)
+
+		var limitErr validation.LimitError
+		if errors.As(err, &limitErr) {
+			return nil, apierror.New(apierror.TypeTooLargeEntry, limitErr.Error())
+		}
+		return nil, apierror.Newf(apierror.TypeInternal, "error decoding remote read response: %v", err)
+	}
+	return resp, nil
+}
+
+func decodeSamplesRemoteReadResponse(ctx context.Context, r *http.Response, req *remoteReadRequest, resp *remoteReadResponse, size *remoteReadResponseSize) error {
+	var readResp client.ReadResponse
+	body, err := util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), size.remaining(), nil, &readResp, util.RawSnappy)
+	if err != nil {
+		if errors.As(err, &util.MsgSizeTooLargeErr{}) {
+			return size.limitError()
+		}
+		return err
+	}
+	if err := size.add(len(body)); err != nil {
+		return err
+	}
+	if len(readResp.Results) != len(req.queries) {
+		return fmt.Errorf("expected %d results, got %d", len(req.queries), len(readResp.Results))
+	}
+
+	for i, queryResp := range readResp.Results {
+		q := req.queries[i]
+		result := resp.results[q.index]
+		for _, ts := range queryResp.Timeseries {
+			s := remoteReadSeries{labels: mimirpb.FromLabelAdaptersToLabels(ts.Labels)}
+			for _, sample := range ts.Samples {
+				if sample.TimestampMs >= q.start && sample.TimestampMs <= q.end {
+					s.samples = append(s.samples, sample)
+				}
+			}
+			for _, h := range ts.Histograms {
+				if h.Timestamp >= q.start && h.Timestamp <= q.end {
+					s.histograms = append(s.histograms, h)
+				}
+			}
+			if len(s.samples) > 0 || len(s.histograms) > 0 {
+				result.series = append(result.series, s)
+			}
+		}
+	}
+	return nil
+}
+
+func decodeStreamedChunksRemoteReadResponse(body io.Reader, req *remoteReadRequest, resp *remoteReadResponse, size *remoteReadResponseSize) error {
+	limitedBody := &remoteReadResponseSizeReader{r: body, max: size.remaining(), size: size}
+	reader := prom_remote.NewChunkedReader(limitedBody, uint64(limitedBody.max), nil)
+	for {
+		var frame client.StreamReadResponse
+		if err := reader.NextProto(&frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				return size.add(limitedBody.read)
+			}
+			return err
+		}
+		if frame.QueryIndex < 0 || frame.QueryIndex >= int64(len(req.queries)) {
+			return fmt.Errorf("unexpected query index %d in a response to %d queries", frame.QueryIndex, len(req.queries))
+		}
+
+		q := req.queries[frame.QueryIndex]
+		result := resp.results[q.index]
+		for _, chunkedSeries := range frame.ChunkedSeries {
+			// The frame buffer is reused by the reader, so the labels and chunks must be copied.
+			lbls := mimirpb.FromLabelAdaptersToLabelsWithCopy(chunkedSeries.Labels)
+			chks := make([]client.StreamChunk, 0, len(chunkedSeries.Chunks))
+			for _, chk := range chunkedSeries.Chunks {
+				trimmed, err := trimStreamChunk(chk, q.start, q.end)
+				if err != nil {
+					return err
+				}
+				chks = append(chks, trimmed...)
+			}
+			if len(chks) == 0 {
+				continue
+			}
+
+			// The chunks of a series can be split across consecutive frames.
+			if last := len(result.series) - 1; last >= 0 && labels.Equal(result.series[last].labels, lbls) {
+				result.series[last].chunks = append(result.series[last].chunks, chks...)
+				continue
+			}
+			result.series = append(result.series, remoteReadSeries{labels: lbls, chunks: chks})
+		}
+	}
+}
+
+// trimStreamChunk returns the chunks with only the samples of the input chunk in the [start, end] time range.
+// A chunk fully within the time range is copied as is, while a chunk overlapping it is re-encoded.
+func trimStreamChunk(chk client.StreamChunk, start, end int64) ([]client.StreamChunk, error) {
+	if chk.MaxTimeMs < start || chk.MinTimeMs > end {
+		return nil, nil
+	}
+	if chk.MinTimeMs >= start && chk.MaxTimeMs <= end {
+		chk.Data = slices.Clone(chk.Data)
+		return []client.StreamChunk{chk}, nil
+	}
+
+	src, err := chunkenc.FromData(chunkenc.Encoding(chk.Type), chk.Data)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := storage.NewSeriesToChunkEncoder(&storage.SeriesEntry{
+		SampleIteratorFn: func(it chunkenc.Iterator) chunkenc.Iterator {
+			return &timeRangeIterator{Iterator: src.Iterator(it), minT: start, maxT: end}
+		},
+	})
+
+	var chks []client.StreamChunk
+	it := trimmed.Iterator(nil)
+	for it.Next() {
+		meta := it.At()
+		chks = append(chks, client.StreamChunk{
+			MinTimeMs: meta.MinTime,
+			MaxTimeMs: meta.MaxTime,
+			Type:      client.StreamChunk_Encoding(meta.Chunk.Encoding()),
+			Data:      meta.Chunk.Bytes(),
+		})
+	}
+	return chks, it.Err()
+}
+
+// timeRangeIterator is a chunkenc.Iterator over the samples of the wrapped iterator in the [minT, maxT] time range.
+// Only Next() honors the time range, because it's the only method called by the chunk encoder.
+type timeRangeIterator struct {
+	chunkenc.Iterator
+	minT, maxT int64
+	started    bool
+}
+
+func (it *timeRangeIterator) Next() chunkenc.ValueType {
+	var valType chunkenc.ValueType
+	if it.started {
+		valType = it.Iterator.Next()
+	} else {
+		it.started = true
+		valType = it.Iterator.Seek(it.minT)
+	}
+
+	if valType != chunkenc.ValNone && it.AtT() > it.maxT {
+		return chunkenc.ValNone
+	}
+	return valType
+}
+
+func (c prometheusCodec) encodeRemoteReadResponse(ctx context.Context, res *remoteReadResponse) (*http.Response, error) {
+	sp, _ := opentracing.StartSpanFromContext(ctx, "APIResponse.ToHTTPResponse")
+	defer sp.Finish()
+
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	switch res.responseType {
+	case client.STREAMED_XOR_CHUNKS:
+		contentType = remoteReadStreamedChunksContentType
+		body, err = encodeStreamedChunksRemoteReadResponse(res)
+	default:
+		contentType = remoteReadSamplesContentType
+		body, err = encodeSamplesRemoteReadResponse(res)
+	}
+	if err != nil {
+		return nil, apierror.Newf(apierror.TypeInternal, "error encoding remote read response: %v", err)
+	}
+	sp.LogFields(otlog.Int("bytes", len(body)))
+
+	resp := &http.Response{
+		Header: http.Header{
+			"Content-Type": []string{contentType},
+		},
+		Body:          io.NopCloser(bytes.NewBuffer(body)),
+		StatusCode:    http.StatusOK,
+		ContentLength: int64(len(body)),
+	}
+	if res.responseType == client.SAMPLES {
+		resp.Header.Set("Content-Encoding", "snappy")
+	}
+	return resp, nil
+}
+
+func encodeSamplesRemoteReadResponse(res *remoteReadResponse) ([]byte, error) {
+	readResp := client.ReadResponse{Results: make([]*client.QueryResponse, 0, len(res.results))}
+	for _, result := range res.results {
+		queryResp := &client.QueryResponse{}
+		if result != nil {
+			queryResp.Timeseries = make([]mimirpb.TimeSeries, 0, len(result.series))
+			for _, s := range result.series {
+				queryResp.Timeseries = append(queryResp.Timeseries, mimirpb.TimeSeries{
+					Labels:     mimirpb.FromLabelsToLabelAdapters(s.labels),
+					Samples:    s.samples,
+					Histograms: s.histograms,
+				})
+			}
+		}
+		readResp.Results = append(readResp.Results, queryResp)
+	}
+
+	data, err := proto.Marshal(&readResp)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+// encodeStreamedChunksRemoteReadResponse encodes the response in frames of about maxRemoteReadFrameBytes,
+// like the querier does. Unlike the querier, the frames are encoded in memory once the whole response
+// has been merged, so they're only sent to the client once the response is complete.
+func encodeStreamedChunksRemoteReadResponse(res *remoteReadResponse) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	stream := prom_remote.NewChunkedWriter(buf, nopFlusher{})
+
+	for queryIndex, result := range res.results {
+		if result == nil {
+			continue
+		}
+
+		for _, s := range result.series {
+			lbls := mimirpb.FromLabelsToLabelAdapters(s.labels)
+			frameBytesRemaining := remoteReadFrameBytesRemaining(lbls)
+			var chks []client.StreamChunk
+
+			for i, chk := range s.chunks {
+				chks = append(chks, chk)
+				frameBytesRemaining -= chk.Size()
+
+				// We are fine with minor inaccuracy of max bytes per frame. The inaccuracy will be max of full chunk size.
+				if frameBytesRemaining > 0 && i < len(s.chunks)-1 {
+					continue
+				}
+
+				b, err := proto.Marshal(&client.StreamReadResponse{
+					ChunkedSeries: []*client.StreamChunkedSeries{{Labels: lbls, Chunks: chks}},
+					QueryIndex:    int64(queryIndex),
+				})
+				if err != nil {
+					return nil, errors.Wrap(err, "marshal client.StreamReadResponse")
+				}
+				if _, err := stream.Write(b); err != nil {
+					return nil, errors.Wrap(err, "write to stream")
+				}
+
+				chks = chks[:0]
+				frameBytesRemaining = remoteReadFrameBytesRemaining(lbls)
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func remoteReadFrameBytesRemaining(lbls []mimirpb.LabelAdapter) int {
+	frameBytesRemaining := maxRemoteReadFrameBytes
+	for _, lbl := range lbls {
+		frameBytesRemaining -= lbl.Size()
+	}
+	return frameBytesRemaining
+}
+
+// nopFlusher is a http.Flusher doing nothing, used to stream a remote read response into a buffer.
+type nopFlusher struct{}
+
+func (nopFlusher) Flush() {}
+
+// mergeRemoteReadResponses merges the responses of the requests a remote read request has been split into.
+// The results of each query are merged in time order, and the series of the merged results are sorted by labels.
+func mergeRemoteReadResponses(responses []Response) (Response, error) {
+	var (
+		merged  *remoteReadResponse
+		results [][]*remoteReadQueryResult
+	)
+	for _, res := range responses {
+		rr, ok := res.(*remoteReadResponse)
+		if !ok {
+			return nil, fmt.Errorf("can't merge a remote read response with a response of type %T", res)
+		}
+
+		if merged == nil {
+			merged = &remoteReadResponse{responseType: rr.responseType, results: make([]*remoteReadQueryResult, len(rr.results))}
+			results = make([][]*remoteReadQueryResult, len(rr.results))
+		} else if rr.responseType != merged.responseType || len(rr.results) != len(merged.results) {
+			return nil, fmt.Errorf("can't merge remote read responses of different requests")
+		}
+
+		for i, result := range rr.results {
+			if result != nil {
+				results[i] = append(results[i], result)
+			}
+		}
+	}
+
+	for i := range results {
+		if len(results[i]) > 0 {
+			merged.results[i] = mergeRemoteReadQueryResults(results[i])
+		}
+	}
+	return merged, nil
+}
+
+func mergeRemoteReadQueryResults(results []*remoteReadQueryResult) *remoteReadQueryResult {
+	// The results of the shards of the same time range have the same start, but different series.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].start < results[j].start
+	})
+
+	merged := &remoteReadQueryResult{start: results[0].start}
+	seriesIndex := map[string]int{}
+	for _, result := range results {
+		for _, s := range result.series {
+			key := s.labels.String()
+			idx, ok := seriesIndex[key]
+			if !ok {
+				seriesIndex[key] = len(merged.series)
+				merged.series = append(merged.series, s)
+				continue
+			}
+
+			merged.series[idx].samples = append(merged.series[idx].samples, s.samples...)
+			merged.series[idx].histograms = append(merged.series[idx].histograms, s.histograms...)
+			merged.series[idx].chunks = append(merged.series[idx].chunks, s.chunks...)
+		}
+	}
+
+	// The streamed remote read API has to provide the series sorted.
+	sort.Slice(merged.series, func(i, j int) bool {
+		return labels.Compare(merged.series[i].labels, merged.series[j].labels) < 0
+	})
+	return merged
+}
+
+type remoteReadResponseSizeCtxKey struct{}
+
+// remoteReadResponseSize tracks the total size of the responses received from the queriers to the requests
+// a remote read request has been split into, which are all buffered in memory until they're merged.
+// A nil *remoteReadResponseSize doesn't apply any limit.
+type remoteReadResponseSize struct {
+	limit int
+	size  atomic.Int64
+}
+
+func remoteReadResponseSizeFromContext(ctx context.Context) *remoteReadResponseSize {
+	size, _ := ctx.Value(remoteReadResponseSizeCtxKey{}).(*remoteReadResponseSize)
+	return size
+}
+
+// remaining returns the max size of the next response received from the queriers.
+func (s *remoteReadResponseSize) remaining() int {
+	if s == nil {
+		return maxRemoteReadResponseMessageBytes
+	}
+	return util_math.Max(s.limit-int(s.size.Load()), 0)
+}
+
+// add adds the size of a response received from the queriers, and returns an error if the limit is exceeded.
+func (s *remoteReadResponseSize) add(bytes int) error {
+	if s == nil {
+		return nil
+	}
+	if s.size.Add(int64(bytes)) > int64(s.limit) {
+		return s.limitError()
+	}
+	return nil
+}
+
+func (s *remoteReadResponseSize) limitError() error {
+	if s == nil {
+		return fmt.Errorf("the remote read response exceeds the maximum size of %d bytes", maxRemoteReadResponseMessageBytes)
+	}
+	return validation.NewMaxRemoteReadResponseSizeBytesError(s.limit)
+}
+
+// remoteReadResponseSizeReader is an io.Reader failing once more than max bytes have been read.
+type remoteReadResponseSizeReader struct {
+	r         io.Reader
+	read, max int
+	size      *remoteReadResponseSize
+}
+
+func (r *remoteReadResponseSizeReader) Read(p []byte) (int, error) {
+	// Read at most one byte more than allowed, to detect responses exceeding the limit.
+	if remaining := r.max - r.read; len(p) > remaining+1 {
+		p = p[:remaining+1]
+	}
+
+	n, err := r.r.Read(p)
+	r.read += n
+	if r.read > r.max {
+		return 0, r.size.limitError()
+	}
+	return n, err
+}
+
+// remoteReadResponseSizeLimitMiddleware is a Middleware limiting the total size of the responses received
+// from the queriers to a remote read request, because the query-frontend buffers them until merged.
+type remoteReadResponseSizeLimitMiddleware struct {
+	next   Handler
+	limits Limits
+}
+
+// newRemoteReadResponseSizeLimitMiddleware makes a new Middleware limiting the size of remote read responses.
+func newRemoteReadResponseSizeLimitMiddleware(limits Limits) Middleware {
+	return MiddlewareFunc(func(next Handler) Handler {
+		return &remoteReadResponseSizeLimitMiddleware{
+			next:   next,
+			limits: limits,
+		}
+	})
+}
+
+func (m *remoteReadResponseSizeLimitMiddleware) Do(ctx context.Context, r Request) (Response, error) {
+	if _, ok := r.(*remoteReadRequest); !ok {
+		return m.next.Do(ctx, r)
+	}
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	maxSize := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, m.limits.MaxRemoteReadResponseSizeBytes)
+	if maxSize <= 0 {
+		return m.next.Do(ctx, r)
+	}
+	return m.next.Do(context.WithValue(ctx, remoteReadResponseSizeCtxKey{}, &remoteReadResponseSize{limit: maxSize}), r)
+}
+
+// splitRemoteReadByIntervalMiddleware is a Middleware splitting each query of a remote read request by time interval.
+type splitRemoteReadByIntervalMiddleware struct {
+	next     Handler
+	interval time.Duration
+	merger   Merger
+}
+
+// newSplitRemoteReadByIntervalMiddleware makes a new Middleware splitting remote read requests by time interval.
+func newSplitRemoteReadByIntervalMiddleware(interval time.Duration, merger Merger) Middleware {
+	return MiddlewareFunc(func(next Handler) Handler {
+		return &splitRemoteReadByIntervalMiddleware{
+			next:     next,
+			interval: interval,
+			merger:   merger,
+		}
+	})
+}
+
+func (s *splitRemoteReadByIntervalMiddleware) Do(ctx context.Context, r Request) (Response, error) {
+	req, ok := r.(*remoteReadRequest)
+	if !ok {
+		return s.next.Do(ctx, r)
+	}
+
+	reqs := splitRemoteReadByInterval(req, s.interval)
+	switch len(reqs) {
+	case 0:
+		return req.emptyResponse(), nil
+	case 1:
+		return s.next.Do(ctx, reqs[0])
+	}
+
+	for i := range reqs {
+		reqs[i] = reqs[i].WithTotalQueriesHint(int32(len(reqs)))
+	}
+
+	reqResps, err := doRequests(ctx, s.next, reqs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]Response, 0, len(reqResps))
+	for _, reqResp := range reqResps {
+		resps = append(resps, reqResp.Response)
+	}
+	return s.merger.MergeResponse(resps...)
+}
+
+// splitRemoteReadByInterval returns a request for each query of the input remote read request and time interval.
+// Queries with an empty time range are skipped.
+func splitRemoteReadByInterval(r *remoteReadRequest, interval time.Duration) []Request {
+	intervalMs := interval.Milliseconds()
+
+	var reqs []Request
+	for _, q := range r.queries {
+		for start := q.start; start <= q.end; {
+			// Floor the start to the interval, handling negative timestamps too.
+			intervalStart := start - ((start%intervalMs)+intervalMs)%intervalMs
+			end := util_math.Min(intervalStart+intervalMs-1, q.end)
+
+			split := q
+			split.start, split.end = start, end
+			reqs = append(reqs, r.withQueries(split))
+
+			start = end + 1
+		}
+	}
+	return reqs
+}
+
+// remoteReadShardingMiddleware is a Middleware sharding each query of a remote read request
+// by adding the query shard label matcher.
+type remoteReadShardingMiddleware struct {
+	next   Handler
+	limits Limits
+	merger Merger
+	logger log.Logger
+}
+
+// newRemoteReadShardingMiddleware makes a new Middleware sharding remote read requests.
+func newRemoteReadShardingMiddleware(limits Limits, merger Merger, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next Handler) Handler {
+		return &remoteReadShardingMiddleware{
+			next:   next,
+			limits: limits,
+			merger: merger,
+			logger: logger,
+		}
+	})
+}
+
+func (s *remoteReadShardingMiddleware) Do(ctx context.Context, r Request) (Response, error) {
+	req, ok := r.(*remoteReadRequest)
+	if !ok {
+		return s.next.Do(ctx, r)
+	}
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, apierror.New(apierror.TypeBadData, err.Error())
+	}
+
+	totalShards := s.getShardsForRequest(tenantIDs, req)
+	if totalShards <= 1 {
+		return s.next.Do(ctx, r)
+	}
+
+	spanLog, ctx := spanlogger.NewWithLogger(ctx, s.logger, "remoteReadSharding")
+	defer spanLog.Finish()
+	spanLog.LogFields(otlog.Int("total shards", totalShards))
+
+	reqs := make([]Request, 0, totalShards)
+	for shardIndex := 0; shardIndex < totalShards; shardIndex++ {
+		shard := sharding.ShardSelector{ShardIndex: uint64(shardIndex), ShardCount: uint64(totalShards)}
+
+		queries := make([]remoteReadQuery, 0, len(req.queries))
+		for _, q := range req.queries {
+			q.matchers = append(slices.Clone(q.matchers), shard.Matcher())
+			queries = append(queries, q)
+		}
+		reqs = append(reqs, req.withQueries(queries...))
+	}
+
+	reqResps, err := doRequests(ctx, s.next, reqs, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]Response, 0, len(reqResps))
+	for _, reqResp := range reqResps {
+		resps = append(resps, reqResp.Response)
+	}
+	return s.merger.MergeResponse(resps...)
+}
+
+// getShardsForRequest returns the number of shards to split the queries of the request into.
+func (s *remoteReadShardingMiddleware) getShardsForRequest(tenantIDs []string, r *remoteReadRequest) int {
+	totalShards := validation.SmallestPositiveIntPerTenant(tenantIDs, s.limits.QueryShardingTotalShards)
+	if totalShards <= 1 {
+		return 1
+	}
+
+	maxRegexpSizeBytes := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.QueryShardingMaxRegexpSizeBytes)
+	for _, q := range r.queries {
+		for _, m := range q.matchers {
+			// Don't shard queries which are already sharded.
+			if m.Name == sharding.ShardLabel {
+				return 1
+			}
+			// Don't shard queries with a regexp matcher longer than the configured limit.
+			if maxRegexpSizeBytes > 0 && (m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp) && len(m.Value) > maxRegexpSizeBytes {
+				return 1
+			}
+		}
+	}
+
+	// If the request has been split, honor the max sharded queries limit across all the split requests.
+	maxShardedQueries := validation.SmallestPositiveIntPerTenant(tenantIDs, s.limits.QueryShardingMaxShardedQueries)
+	if hints := r.GetHints(); hints != nil && hints.TotalQueries > 0 && maxShardedQueries > 0 {
+		totalShards = util_math.Min(totalShards, maxShardedQueries/int(hints.TotalQueries))
+	}
+
+	return totalShards
+}
@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	prom_remote "github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	apierror "github.com/grafana/mimir/pkg/api/error"
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/querier"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+)
+
+func TestRemoteReadTripperware(t *testing.T) {
+	var (
+		start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		end   = start.Add(60 * time.Hour)
+		step  = 30 * time.Second
+	)
+
+	var storageSeries []*promql.StorageSeries
+	for i := 0; i < 10; i++ {
+		storageSeries = append(storageSeries, newSeries(newTestCounterLabels(i), start, end, step, arithmeticSequence(float64(i))))
+	}
+	storageSeries = append(storageSeries, newNativeHistogramSeries(newTestNativeHistogramLabels(0), start, end, step, factor(2)))
+
+	// The queries don't start and end at a split interval boundary, and the downstream querier returns
+	// the samples of the whole series, so the chunks overlapping the queried time range must be trimmed.
+	queries := []*client.QueryRequest{
+		remoteReadTestQuery(t, start.Add(90*time.Minute), start.Add(50*time.Hour), labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric_counter")),
+		remoteReadTestQuery(t, start.Add(20*time.Hour), start.Add(30*time.Hour), labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, "metric_counter|metric_native_histogram"), labels.MustNewMatcher(labels.MatchRegexp, "unique", "0|1")),
+	}
+
+	tests := map[string]struct {
+		cfg                        Config
+		limits                     mockLimits
+		expectedDownstreamRequests int
+	}{
+		"neither split nor sharded": {
+			expectedDownstreamRequests: 1,
+		},
+		"split by interval": {
+			cfg: Config{SplitQueriesByInterval: 24 * time.Hour},
+			// The first query spans 3 days, the second one 2 days.
+			expectedDownstreamRequests: 5,
+		},
+		"split by interval and sharded": {
+			cfg:                        Config{SplitQueriesByInterval: 24 * time.Hour, ShardedQueries: true},
+			limits:                     mockLimits{totalShards: 4},
+			expectedDownstreamRequests: 20,
+		},
+		"split by interval and sharded honoring the max sharded queries": {
+			cfg:                        Config{SplitQueriesByInterval: 24 * time.Hour, ShardedQueries: true},
+			limits:                     mockLimits{totalShards: 4, maxShardedQueries: 10},
+			expectedDownstreamRequests: 10,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, responseType := range []client.ReadRequest_ResponseType{client.SAMPLES, client.STREAMED_XOR_CHUNKS} {
+				t.Run(responseType.String(), func(t *testing.T) {
+					downstreamRequests := atomic.NewInt32(0)
+					downstream := newRemoteReadTestDownstream(storageSeries, func(*http.Request) {
+						downstreamRequests.Inc()
+					})
+
+					tw, err := newQueryTripperware(testData.cfg, log.NewNopLogger(), testData.limits, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+					require.NoError(t, err)
+
+					resp, err := tw(downstream).RoundTrip(newRemoteReadTestRequest(t, responseType, queries...))
+					require.NoError(t, err)
+					require.Equal(t, http.StatusOK, resp.StatusCode)
+
+					actual := decodeRemoteReadTestResponse(t, resp, len(queries))
+					for i, q := range queries {
+						assert.Equal(t, expectedRemoteReadTestResult(t, storageSeries, q), actual[i], "query %d", i)
+					}
+					assert.Equal(t, testData.expectedDownstreamRequests, int(downstreamRequests.Load()))
+				})
+			}
+		})
+	}
+}
+
+func TestRemoteReadTripperware_Limits(t *testing.T) {
+	now := time.Now()
+	storageSeries := []*promql.StorageSeries{newSeries(newTestCounterLabels(0), now.Add(-3*time.Hour), now, time.Minute, factor(1))}
+	matcher := labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric_counter")
+
+	t.Run("max total query length", func(t *testing.T) {
+		tw, err := newQueryTripperware(Config{}, log.NewNopLogger(), mockLimits{maxTotalQueryLength: time.Hour}, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+		require.NoError(t, err)
+
+		_, err = tw(newRemoteReadTestDownstream(storageSeries, nil)).RoundTrip(newRemoteReadTestRequest(t, client.STREAMED_XOR_CHUNKS,
+			remoteReadTestQuery(t, now.Add(-3*time.Hour), now, matcher),
+		))
+		require.Error(t, err)
+		assert.True(t, apierror.IsAPIError(err))
+		assert.Contains(t, err.Error(), "the total query time range exceeds the limit")
+	})
+
+	t.Run("max query lookback", func(t *testing.T) {
+		tw, err := newQueryTripperware(Config{}, log.NewNopLogger(), mockLimits{maxQueryLookback: time.Hour, compactorBlocksRetentionPeriod: 24 * time.Hour}, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+		require.NoError(t, err)
+
+		downstreamRequests := atomic.NewInt32(0)
+		downstream := newRemoteReadTestDownstream(storageSeries, func(*http.Request) {
+			downstreamRequests.Inc()
+		})
+
+		queries := []*client.QueryRequest{
+			remoteReadTestQuery(t, now.Add(-3*time.Hour), now.Add(-2*time.Hour), matcher),
+			remoteReadTestQuery(t, now.Add(-3*time.Hour), now, matcher),
+		}
+		resp, err := tw(downstream).RoundTrip(newRemoteReadTestRequest(t, client.SAMPLES, queries...))
+		require.NoError(t, err)
+
+		// The first query is fully outside the max query lookback, so it has no series,
+		// while the start of the second one is clamped to the max query lookback.
+		actual := decodeRemoteReadTestResponse(t, resp, len(queries))
+		assert.Empty(t, actual[0])
+		require.Len(t, actual[1], 1)
+		for _, samples := range actual[1] {
+			require.NotEmpty(t, samples)
+			assert.GreaterOrEqual(t, samples[0].t, now.Add(-time.Hour).UnixMilli())
+		}
+		assert.Equal(t, 1, int(downstreamRequests.Load()))
+	})
+
+	t.Run("fully outside the max query lookback", func(t *testing.T) {
+		tw, err := newQueryTripperware(Config{}, log.NewNopLogger(), mockLimits{maxQueryLookback: time.Hour, compactorBlocksRetentionPeriod: 24 * time.Hour}, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+		require.NoError(t, err)
+
+		downstream := newRemoteReadTestDownstream(storageSeries, func(*http.Request) {
+			assert.Fail(t, "unexpected downstream request")
+		})
+		resp, err := tw(downstream).RoundTrip(newRemoteReadTestRequest(t, client.SAMPLES,
+			remoteReadTestQuery(t, now.Add(-3*time.Hour), now.Add(-2*time.Hour), matcher),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, []map[string][]remoteReadTestSample{{}}, decodeRemoteReadTestResponse(t, resp, 1))
+	})
+
+	t.Run("max remote read response size", func(t *testing.T) {
+		for _, responseType := range []client.ReadRequest_ResponseType{client.SAMPLES, client.STREAMED_XOR_CHUNKS} {
+			t.Run(responseType.String(), func(t *testing.T) {
+				query := remoteReadTestQuery(t, now.Add(-3*time.Hour), now, matcher)
+
+				// The limit applies to the total size of the responses to the split requests.
+				cfg := Config{SplitQueriesByInterval: time.Hour}
+				tw, err := newQueryTripperware(cfg, log.NewNopLogger(), mockLimits{maxRemoteReadResponseSizeBytes: 1024 * 1024}, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+				require.NoError(t, err)
+
+				resp, err := tw(newRemoteReadTestDownstream(storageSeries, nil)).RoundTrip(newRemoteReadTestRequest(t, responseType, query))
+				require.NoError(t, err)
+				assert.Equal(t, expectedRemoteReadTestResult(t, storageSeries, query), decodeRemoteReadTestResponse(t, resp, 1)[0])
+
+				tw, err = newQueryTripperware(cfg, log.NewNopLogger(), mockLimits{maxRemoteReadResponseSizeBytes: 200}, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+				require.NoError(t, err)
+
+				_, err = tw(newRemoteReadTestDownstream(storageSeries, nil)).RoundTrip(newRemoteReadTestRequest(t, responseType, query))
+				require.Error(t, err)
+				assert.True(t, apierror.IsAPIError(err))
+				assert.Contains(t, err.Error(), "the size of the remote read response exceeds the limit (limit: 200 bytes)")
+			})
+		}
+	})
+}
+
+func TestRemoteReadTripperware_RemoteClusterRead(t *testing.T) {
+	var received []string
+	downstream := RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		received = append(received, r.Header.Get(querierapi.RemoteClusterReadHeader))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{remoteReadStreamedChunksContentType}},
+			Body:       io.NopCloser(&bytes.Buffer{}),
+		}, nil
+	})
+
+	tw, err := newQueryTripperware(Config{}, log.NewNopLogger(), mockLimits{}, newTestPrometheusCodec(), nil, promql.EngineOpts{}, nil)
+	require.NoError(t, err)
+
+	query := remoteReadTestQuery(t, time.Now().Add(-time.Hour), time.Now(), labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"))
+	_, err = tw(downstream).RoundTrip(newRemoteReadTestRequest(t, client.STREAMED_XOR_CHUNKS, query))
+	require.NoError(t, err)
+
+	req := newRemoteReadTestRequest(t, client.STREAMED_XOR_CHUNKS, query)
+	req.Header.Set(querierapi.RemoteClusterReadHeader, "true")
+	_, err = tw(downstream).RoundTrip(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "true"}, received)
+}
+
+func TestPrometheusCodec_DecodeRemoteReadRequest(t *testing.T) {
+	codec := newTestPrometheusCodec()
+	matchers := []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"),
+		labels.MustNewMatcher(labels.MatchRegexp, "job", "a|b"),
+	}
+
+	tests := map[string]struct {
+		acceptedResponseTypes []client.ReadRequest_ResponseType
+		expectedResponseType  client.ReadRequest_ResponseType
+		expectedErr           string
+	}{
+		"no accepted response types": {
+			expectedResponseType: client.SAMPLES,
+		},
+		"streamed XOR chunks accepted first": {
+			acceptedResponseTypes: []client.ReadRequest_ResponseType{client.STREAMED_XOR_CHUNKS, client.SAMPLES},
+			expectedResponseType:  client.STREAMED_XOR_CHUNKS,
+		},
+		"unsupported response type": {
+			acceptedResponseTypes: []client.ReadRequest_ResponseType{client.ReadRequest_ResponseType(5)},
+			expectedErr:           "server does not support any of the requested response types",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			query, err := client.ToQueryRequest(1000, 2000, matchers)
+			require.NoError(t, err)
+
+			httpReq := newRemoteReadTestHTTPRequest(t, &client.ReadRequest{Queries: []*client.QueryRequest{query}, AcceptedResponseTypes: testData.acceptedResponseTypes})
+			req, err := codec.DecodeRequest(context.Background(), httpReq)
+			if testData.expectedErr != "" {
+				require.ErrorContains(t, err, testData.expectedErr)
+				assert.True(t, apierror.IsAPIError(err))
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(1000), req.GetStart())
+			assert.Equal(t, int64(2000), req.GetEnd())
+			assert.Equal(t, `{__name__="up",job=~"a|b"}`, req.GetQuery())
+			assert.Equal(t, testData.expectedResponseType, req.(*remoteReadRequest).responseType)
+
+			// Encoding the request again must produce the same remote read request.
+			encoded, err := codec.EncodeRequest(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, httpReq.URL.Path, encoded.URL.Path)
+
+			var actual client.ReadRequest
+			body, err := io.ReadAll(encoded.Body)
+			require.NoError(t, err)
+			decompressed, err := snappy.Decode(nil, body)
+			require.NoError(t, err)
+			require.NoError(t, proto.Unmarshal(decompressed, &actual))
+			assert.Equal(t, client.ReadRequest{Queries: []*client.QueryRequest{query}, AcceptedResponseTypes: []client.ReadRequest_ResponseType{testData.expectedResponseType}}, actual)
+		})
+	}
+}
+
+func TestSplitRemoteReadByInterval(t *testing.T) {
+	const hour = int64(time.Hour / time.Millisecond)
+
+	req := &remoteReadRequest{
+		totalQueries: 3,
+		queries: []remoteReadQuery{
+			{index: 0, start: 30 * 60 * 1000, end: 3*hour + 10},
+			{index: 1, start: hour, end: 2*hour - 1},
+			{index: 2, start: 2 * hour, end: hour},
+		},
+	}
+
+	var actual []remoteReadQuery
+	for _, split := range splitRemoteReadByInterval(req, time.Hour) {
+		actual = append(actual, split.(*remoteReadRequest).queries...)
+	}
+
+	assert.Equal(t, []remoteReadQuery{
+		{index: 0, start: 30 * 60 * 1000, end: hour - 1},
+		{index: 0, start: hour, end: 2*hour - 1},
+		{index: 0, start: 2 * hour, end: 3*hour - 1},
+		{index: 0, start: 3 * hour, end: 3*hour + 10},
+		{index: 1, start: hour, end: 2*hour - 1},
+	}, actual)
+}
+
+type remoteReadTestSample struct {
+	t         int64
+	v         float64
+	histogram bool
+}
+
+func remoteReadTestQuery(t *testing.T, start, end time.Time, matchers ...*labels.Matcher) *client.QueryRequest {
+	query, err := client.ToQueryRequest(model.TimeFromUnixNano(start.UnixNano()), model.TimeFromUnixNano(end.UnixNano()), matchers)
+	require.NoError(t, err)
+	return query
+}
+
+func newRemoteReadTestRequest(t *testing.T, responseType client.ReadRequest_ResponseType, queries ...*client.QueryRequest) *http.Request {
+	req := newRemoteReadTestHTTPRequest(t, &client.ReadRequest{Queries: queries, AcceptedResponseTypes: []client.ReadRequest_ResponseType{responseType}})
+	ctx := user.InjectOrgID(context.Background(), "user-1")
+	require.NoError(t, user.InjectOrgIDIntoHTTPRequest(ctx, req))
+	return req.WithContext(ctx)
+}
+
+func newRemoteReadTestHTTPRequest(t *testing.T, readReq *client.ReadRequest) *http.Request {
+	data, err := proto.Marshal(readReq)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/prometheus/api/v1/read", bytes.NewReader(snappy.Encode(nil, data)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	return req
+}
+
+// newRemoteReadTestDownstream returns a downstream serving remote read requests with the querier handler.
+// The series are returned with all their samples, regardless of the queried time range.
+func newRemoteReadTestDownstream(series []*promql.StorageSeries, onRequest func(*http.Request)) http.RoundTripper {
+	handler := querier.RemoteReadHandler(querier.NewSampleAndChunkQueryable(storageSeriesQueryable(series)), log.NewNopLogger())
+
+	return RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if onRequest != nil {
+			onRequest(r)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder.Result(), nil
+	})
+}
+
+// expectedRemoteReadTestResult returns the samples of the series matching the query, by series labels.
+func expectedRemoteReadTestResult(t *testing.T, series []*promql.StorageSeries, query *client.QueryRequest) map[string][]remoteReadTestSample {
+	start, end, matchers, err := client.FromQueryRequest(query)
+	require.NoError(t, err)
+
+	expected := map[string][]remoteReadTestSample{}
+	for _, s := range series {
+		if !seriesMatches(s, matchers...) {
+			continue
+		}
+
+		var samples []remoteReadTestSample
+		it := s.Iterator(nil)
+		for valType := it.Next(); valType != chunkenc.ValNone; valType = it.Next() {
+			if it.AtT() < int64(start) || it.AtT() > int64(end) {
+				continue
+			}
+			samples = append(samples, remoteReadTestSampleAt(t, it, valType))
+		}
+		require.NoError(t, it.Err())
+		expected[s.Labels().String()] = samples
+	}
+	return expected
+}
+
+// decodeRemoteReadTestResponse returns the samples of each query in the remote read response, by series labels.
+func decodeRemoteReadTestResponse(t *testing.T, resp *http.Response, numQueries int) []map[string][]remoteReadTestSample {
+	actual := make([]map[string][]remoteReadTestSample, numQueries)
+	for i := range actual {
+		actual[i] = map[string][]remoteReadTestSample{}
+	}
+
+	if resp.Header.Get("Content-Type") == remoteReadSamplesContentType {
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		decompressed, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+
+		var readResp client.ReadResponse
+		require.NoError(t, proto.Unmarshal(decompressed, &readResp))
+		require.Len(t, readResp.Results, numQueries)
+
+		for i, result := range readResp.Results {
+			for _, ts := range result.Timeseries {
+				key := mimirpb.FromLabelAdaptersToLabels(ts.Labels).String()
+				for _, s := range ts.Samples {
+					actual[i][key] = append(actual[i][key], remoteReadTestSample{t: s.TimestampMs, v: s.Value})
+				}
+				for _, h := range ts.Histograms {
+					actual[i][key] = append(actual[i][key], remoteReadTestSample{t: h.Timestamp, v: mimirpb.FromFloatHistogramProtoToFloatHistogram(&h).Sum, histogram: true})
+				}
+			}
+		}
+		return actual
+	}
+
+	require.Equal(t, remoteReadStreamedChunksContentType, resp.Header.Get("Content-Type"))
+	var (
+		prevQueryIndex int64
+		prevLabels     labels.Labels
+	)
+	reader := prom_remote.NewChunkedReader(resp.Body, maxRemoteReadFrameBytes*2, nil)
+	for {
+		var frame client.StreamReadResponse
+		err := reader.NextProto(&frame)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		for _, s := range frame.ChunkedSeries {
+			lbls := mimirpb.FromLabelAdaptersToLabelsWithCopy(s.Labels)
+			// The streamed series of each query must be sorted.
+			require.GreaterOrEqual(t, frame.QueryIndex, prevQueryIndex)
+			if frame.QueryIndex == prevQueryIndex {
+				require.LessOrEqual(t, labels.Compare(prevLabels, lbls), 0)
+			}
+			prevQueryIndex, prevLabels = frame.QueryIndex, lbls
+
+			key := lbls.String()
+			for _, chk := range s.Chunks {
+				c, err := chunkenc.FromData(chunkenc.Encoding(chk.Type), chk.Data)
+				require.NoError(t, err)
+
+				it := c.Iterator(nil)
+				for valType := it.Next(); valType != chunkenc.ValNone; valType = it.Next() {
+					actual[frame.QueryIndex][key] = append(actual[frame.QueryIndex][key], remoteReadTestSampleAt(t, it, valType))
+				}
+				require.NoError(t, it.Err())
+				assert.Equal(t, actual[frame.QueryIndex][key][len(actual[frame.QueryIndex][key])-1].t, chk.MaxTimeMs)
+			}
+		}
+	}
+	return actual
+}
+
+func remoteReadTestSampleAt(t *testing.T, it chunkenc.Iterator, valType chunkenc.ValueType) remoteReadTestSample {
+	switch valType {
+	case chunkenc.ValFloat:
+		ts, v := it.At()
+		return remoteReadTestSample{t: ts, v: v}
+	case chunkenc.ValHistogram, chunkenc.ValFloatHistogram:
+		var h *histogram.FloatHistogram
+		ts, h := it.AtFloatHistogram()
+		return remoteReadTestSample{t: ts, v: h.Sum, histogram: true}
+	default:
+		require.Fail(t, "unexpected value type", valType.String())
+		return remoteReadTestSample{}
+	}
+}
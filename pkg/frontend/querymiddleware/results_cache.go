@@ -236,6 +236,12 @@ func isResponseCachable(r Response, logger log.Logger) bool {
 		}
 	}
 
+	// Responses with warnings may be partial, so they're never cached.
+	if len(r.GetWarnings()) > 0 {
+		level.Debug(logger).Log("msg", "response contains warnings, not caching the response")
+		return false
+	}
+
 	return true
 }
 
@@ -355,6 +355,13 @@ func TestIsResponseCachable(t *testing.T) {
 			}),
 			expected: true,
 		},
+		{
+			name: "response with warnings",
+			response: Response(&PrometheusResponse{
+				Warnings: []string{"some blocks couldn't be queried"},
+			}),
+			expected: false,
+		},
 	} {
 		{
 			t.Run(tc.name, func(t *testing.T) {
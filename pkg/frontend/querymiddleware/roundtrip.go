@@ -34,6 +34,7 @@ const (
 	cardinalityLabelNamesPathSuffix  = "/api/v1/cardinality/label_names"
 	cardinalityLabelValuesPathSuffix = "/api/v1/cardinality/label_values"
 	labelNamesPathSuffix             = "/api/v1/labels"
+	remoteReadPathSuffix             = "/api/v1/read"
 
 	// DefaultDeprecatedCacheUnalignedRequests is the default value for the deprecated querier frontend config DeprecatedCacheUnalignedRequests
 	// which has been moved to a per-tenant limit; TODO remove in Mimir 2.12
@@ -220,6 +221,15 @@ func newQueryTripperware(
 		c = cache.NewCompression(cfg.ResultsCacheConfig.Compression, c, log)
 	}
 
+	// Inject the query cost middleware before the query is split and sharded, so that the whole query
+	// is admitted based on its estimated cost. The cost is estimated from the series fetched by previous
+	// executions of similar queries, which are stored in the results cache, so it requires a cache.
+	var queryCostMiddleware Middleware
+	if c != nil {
+		queryCostMiddleware = newQueryCostMiddleware(c, limits, log, registerer)
+		queryRangeMiddleware = append(queryRangeMiddleware, newInstrumentMiddleware("query_cost", metrics), queryCostMiddleware)
+	}
+
 	// Inject the middleware to split requests by interval + results cache (if at least one of the two is enabled).
 	if cfg.SplitQueriesByInterval > 0 || cfg.CacheResults {
 		shouldCache := func(r Request) bool {
@@ -247,6 +257,9 @@ func newQueryTripperware(
 	}
 
 	queryInstantMiddleware := []Middleware{newLimitsMiddleware(limits, log)}
+	if queryCostMiddleware != nil {
+		queryInstantMiddleware = append(queryInstantMiddleware, newInstrumentMiddleware("query_cost", metrics), queryCostMiddleware)
+	}
 
 	queryInstantMiddleware = append(
 		queryInstantMiddleware,
@@ -290,10 +303,21 @@ func newQueryTripperware(
 		)
 	}
 
+	// Remote read requests are split by time interval and sharded like range queries, but their results
+	// are neither cached nor rewritten by PromQL-aware middlewares.
+	remoteReadMiddleware := []Middleware{newLimitsMiddleware(limits, log), newRemoteReadResponseSizeLimitMiddleware(limits)}
+	if cfg.SplitQueriesByInterval > 0 {
+		remoteReadMiddleware = append(remoteReadMiddleware, newInstrumentMiddleware("split_remote_read_by_interval", metrics), newSplitRemoteReadByIntervalMiddleware(cfg.SplitQueriesByInterval, codec))
+	}
+	if cfg.ShardedQueries {
+		remoteReadMiddleware = append(remoteReadMiddleware, newInstrumentMiddleware("remote_read_sharding", metrics), newRemoteReadShardingMiddleware(limits, codec, log))
+	}
+
 	if cfg.MaxRetries > 0 {
 		retryMiddlewareMetrics := newRetryMiddlewareMetrics(registerer)
 		queryRangeMiddleware = append(queryRangeMiddleware, newInstrumentMiddleware("retry", metrics), newRetryMiddleware(log, cfg.MaxRetries, retryMiddlewareMetrics))
 		queryInstantMiddleware = append(queryInstantMiddleware, newInstrumentMiddleware("retry", metrics), newRetryMiddleware(log, cfg.MaxRetries, retryMiddlewareMetrics))
+		remoteReadMiddleware = append(remoteReadMiddleware, newInstrumentMiddleware("retry", metrics), newRetryMiddleware(log, cfg.MaxRetries, retryMiddlewareMetrics))
 	}
 
 	return func(next http.RoundTripper) http.RoundTripper {
@@ -301,6 +325,7 @@ func newQueryTripperware(
 		instant := defaultInstantQueryParamsRoundTripper(
 			newLimitedParallelismRoundTripper(next, codec, limits, queryInstantMiddleware...),
 		)
+		remoteRead := newLimitedParallelismRoundTripper(next, codec, limits, remoteReadMiddleware...)
 
 		// Inject the cardinality and labels query cache roundtripper only if the query results cache is enabled.
 		cardinality := next
@@ -321,6 +346,8 @@ func newQueryTripperware(
 				return cardinality.RoundTrip(r)
 			case isLabelsQuery(r.URL.Path):
 				return labels.RoundTrip(r)
+			case isRemoteReadQuery(r.URL.Path):
+				return remoteRead.RoundTrip(r)
 			default:
 				return next.RoundTrip(r)
 			}
@@ -346,6 +373,8 @@ func newActiveUsersTripperware(registerer prometheus.Registerer) Tripperware {
 			op := "query"
 			if isRangeQuery(r.URL.Path) {
 				op = "query_range"
+			} else if isRemoteReadQuery(r.URL.Path) {
+				op = "remote_read"
 			}
 
 			tenantIDs, err := tenant.TenantIDs(r.Context())
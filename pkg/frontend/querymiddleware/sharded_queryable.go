@@ -107,6 +107,7 @@ func (q *shardedQuerier) Select(_ bool, hints *storage.SelectHints, matchers ...
 // The returned storage.SeriesSet contains sorted series.
 func (q *shardedQuerier) handleEmbeddedQueries(queries []string, hints *storage.SelectHints) storage.SeriesSet {
 	streams := make([][]SampleStream, len(queries))
+	warnings := make([][]string, len(queries))
 
 	// Concurrently run each query. It breaks and cancels each worker context on first error.
 	err := concurrency.ForEachJob(q.ctx, len(queries), len(queries), func(ctx context.Context, idx int) error {
@@ -120,6 +121,7 @@ func (q *shardedQuerier) handleEmbeddedQueries(queries []string, hints *storage.
 			return err
 		}
 		streams[idx] = resStreams // No mutex is needed since each job writes its own index. This is like writing separate variables.
+		warnings[idx] = resp.(*PrometheusResponse).Warnings
 
 		q.responseHeaders.mergeHeaders(resp.(*PrometheusResponse).Headers)
 		return nil
@@ -129,7 +131,21 @@ func (q *shardedQuerier) handleEmbeddedQueries(queries []string, hints *storage.
 		return storage.ErrSeriesSet(err)
 	}
 
-	return newSeriesSetFromEmbeddedQueriesResults(streams, hints)
+	set := newSeriesSetFromEmbeddedQueriesResults(streams, hints)
+
+	// Partial responses come with warnings, which are returned through the series set
+	// so that they're collected by the PromQL engine.
+	var setWarnings storage.Warnings
+	for _, queryWarnings := range warnings {
+		for _, warning := range queryWarnings {
+			setWarnings = append(setWarnings, errors.New(warning))
+		}
+	}
+	if len(setWarnings) > 0 {
+		return series.NewSeriesSetWithWarnings(set, setWarnings)
+	}
+
+	return set
 }
 
 // LabelValues implements storage.LabelQuerier.
@@ -185,6 +201,17 @@ func (t *responseHeadersTracker) getHeaders() []*PrometheusResponseHeader {
 	return out
 }
 
+// warningsToStrings returns the unique messages of the input warnings, preserving their order.
+func warningsToStrings(warnings storage.Warnings) []string {
+	var out []string
+	for _, warning := range warnings {
+		if !util.StringsContain(out, warning.Error()) {
+			out = append(out, warning.Error())
+		}
+	}
+	return out
+}
+
 // newSeriesSetFromEmbeddedQueriesResults returns an in memory storage.SeriesSet from embedded queries results.
 // The passed hints (if any) is used to inject stale markers at the beginning of each gap in the embedded query
 // results.
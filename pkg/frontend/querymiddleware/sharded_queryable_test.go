@@ -249,6 +249,45 @@ func TestShardedQuerier_Select_ShouldConcurrentlyRunEmbeddedQueries(t *testing.T
 	require.Equal(t, len(embeddedQueries), actualSeries)
 }
 
+func TestShardedQuerier_Select_ShouldReturnWarningsOfEmbeddedQueries(t *testing.T) {
+	embeddedQueries := []string{
+		`sum(rate(metric{__query_shard__="0_of_2"}[1m]))`,
+		`sum(rate(metric{__query_shard__="1_of_2"}[1m]))`,
+	}
+
+	querier := mkShardedQuerier(HandlerFunc(func(ctx context.Context, req Request) (Response, error) {
+		res := &PrometheusResponse{
+			Data: &PrometheusData{
+				ResultType: string(parser.ValueTypeVector),
+				Result: []SampleStream{{
+					Labels:  []mimirpb.LabelAdapter{{Name: "a", Value: "1"}},
+					Samples: []mimirpb.Sample{{Value: 1, TimestampMs: 1}},
+				}},
+			},
+		}
+		if req.GetQuery() == embeddedQueries[1] {
+			res.Warnings = []string{"some blocks couldn't be queried"}
+		}
+		return res, nil
+	}))
+
+	encodedQueries, err := astmapper.JSONCodec.Encode(embeddedQueries)
+	require.Nil(t, err)
+
+	seriesSet := querier.Select(
+		false,
+		nil,
+		labels.MustNewMatcher(labels.MatchEqual, "__name__", astmapper.EmbeddedQueriesMetricName),
+		labels.MustNewMatcher(labels.MatchEqual, astmapper.EmbeddedQueriesLabelName, encodedQueries),
+	)
+	require.NoError(t, seriesSet.Err())
+
+	for seriesSet.Next() { // nolint
+	}
+	require.NoError(t, seriesSet.Err())
+	assert.Equal(t, []string{"some blocks couldn't be queried"}, warningsToStrings(seriesSet.Warnings()))
+}
+
 func TestShardedQueryable_GetResponseHeaders(t *testing.T) {
 	queryable := newShardedQueryable(&PrometheusRangeQueryRequest{}, nil)
 	assert.Empty(t, queryable.getResponseHeaders())
@@ -164,6 +164,7 @@ func (s *splitInstantQueryByIntervalMiddleware) Do(ctx context.Context, req Requ
 	// Update query stats.
 	queryStats := stats.FromContext(ctx)
 	queryStats.AddSplitQueries(uint32(mapperStats.GetSplitQueries()))
+	queryExplainerFromContext(ctx).recordRewrittenQuery("split_instant_query_by_interval", req.GetQuery(), instantSplitQuery.String(), mapperStats.GetSplitQueries())
 
 	// Update metrics.
 	s.metrics.splittingSuccesses.Inc()
@@ -192,7 +193,8 @@ func (s *splitInstantQueryByIntervalMiddleware) Do(ctx context.Context, req Requ
 			ResultType: string(res.Value.Type()),
 			Result:     extracted,
 		},
-		Headers: shardedQueryable.getResponseHeaders(),
+		Headers:  shardedQueryable.getResponseHeaders(),
+		Warnings: warningsToStrings(res.Warnings),
 	}, nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/dskit/grpcclient"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/storage"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
@@ -80,4 +81,7 @@ type CombinedQueryStreamResponse struct {
 	Chunkseries     []TimeSeriesChunk
 	Timeseries      []mimirpb.TimeSeries
 	StreamingSeries []StreamingSeries
+
+	// Warnings is set when a partial response is returned because some ingesters couldn't be queried.
+	Warnings storage.Warnings
 }
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package client
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+// ShardByUser returns the token for the given tenant.
+func ShardByUser(userID string) uint32 {
+	h := HashNew32()
+	h = HashAdd32(h, userID)
+	return h
+}
+
+// ShardByMetricName returns the token for the given metric. The provided metricName
+// is guaranteed to not be retained.
+func ShardByMetricName(userID string, metricName string) uint32 {
+	h := ShardByUser(userID)
+	h = HashAdd32(h, metricName)
+	return h
+}
+
+// ShardByAllLabelAdapters returns the token for the given series, which is used by distributors
+// to shard series across ingesters. This function generates different values for different order
+// of same labels.
+func ShardByAllLabelAdapters(userID string, ls []mimirpb.LabelAdapter) uint32 {
+	h := ShardByUser(userID)
+	for _, l := range ls {
+		h = HashAdd32(h, l.Name)
+		h = HashAdd32(h, l.Value)
+	}
+	return h
+}
+
+// ShardByAllLabels is like ShardByAllLabelAdapters, but for labels.Labels.
+func ShardByAllLabels(userID string, ls labels.Labels) uint32 {
+	h := ShardByUser(userID)
+	ls.Range(func(l labels.Label) {
+		h = HashAdd32(h, l.Name)
+		h = HashAdd32(h, l.Value)
+	})
+	return h
+}
@@ -9,14 +9,25 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/weaveworks/common/httpgrpc"
+
+	"github.com/grafana/mimir/pkg/util/globalerror"
 )
 
 var (
 	// This is the closest fitting Prometheus API error code for requests rejected due to limiting.
 	tooBusyError = httpgrpc.Errorf(http.StatusServiceUnavailable,
-		"the ingester is currently too busy to process queries, try again later")
+		globalerror.IngesterTooBusy.Message("the ingester is currently too busy to process queries, try again later"))
+
+	// Remote-write clients retry on 5xx errors, so the rejected write request will be retried.
+	tooBusyWriteError = httpgrpc.Errorf(http.StatusServiceUnavailable,
+		globalerror.IngesterTooBusy.Message("the ingester is currently too busy to process write requests, try again later"))
+
+	errInvalidWritePathMemoryUtilizationLimit = errors.New("the write path memory utilization limit must be greater than the read path memory utilization limit")
+	errInvalidOwnedSeriesLimitsConfig         = errors.New("using owned series for limits requires owned series tracking to be enabled")
+	errInvalidOwnedSeriesUpdateInterval       = errors.New("the owned series update interval must be greater than 0")
 )
 
 type validationError struct {
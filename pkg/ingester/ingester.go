@@ -60,6 +60,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
 	"github.com/grafana/mimir/pkg/usagestats"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/globalerror"
@@ -98,6 +99,7 @@ const (
 
 	// Reasons for discarding samples
 	sampleOutOfOrder     = "sample-out-of-order"
+	histogramOutOfOrder  = "histogram-out-of-order"
 	sampleTooOld         = "sample-too-old"
 	newValueForTimestamp = "new-value-for-timestamp"
 	sampleOutOfBounds    = "sample-out-of-bounds"
@@ -152,6 +154,8 @@ type Config struct {
 	// Config for metadata purging.
 	MetadataRetainPeriod time.Duration `yaml:"metadata_retain_period" category:"advanced"`
 
+	MetadataShippingInterval time.Duration `yaml:"metadata_shipping_interval" category:"experimental"`
+
 	RateUpdatePeriod time.Duration `yaml:"rate_update_period" category:"advanced"`
 
 	ActiveSeriesMetrics activeseries.Config `yaml:",inline"`
@@ -170,7 +174,12 @@ type Config struct {
 
 	ReadPathCPUUtilizationLimit          float64 `yaml:"read_path_cpu_utilization_limit" category:"experimental"`
 	ReadPathMemoryUtilizationLimit       uint64  `yaml:"read_path_memory_utilization_limit" category:"experimental"`
+	WritePathMemoryUtilizationLimit      uint64  `yaml:"write_path_memory_utilization_limit" category:"experimental"`
 	LogUtilizationBasedLimiterCPUSamples bool    `yaml:"log_utilization_based_limiter_cpu_samples" category:"experimental"`
+
+	TrackIngesterOwnedSeries        bool          `yaml:"track_ingester_owned_series" category:"experimental"`
+	UseIngesterOwnedSeriesForLimits bool          `yaml:"use_ingester_owned_series_for_limits" category:"experimental"`
+	OwnedSeriesUpdateInterval       time.Duration `yaml:"owned_series_update_interval" category:"experimental"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet
@@ -180,16 +189,33 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	cfg.ActiveSeriesMetrics.RegisterFlags(f)
 
 	f.DurationVar(&cfg.MetadataRetainPeriod, "ingester.metadata-retain-period", 10*time.Minute, "Period at which metadata we have not seen will remain in memory before being deleted.")
+	f.DurationVar(&cfg.MetadataShippingInterval, "ingester.metadata-shipping-interval", 0, "How frequently the ingester uploads a snapshot of the in-memory metrics metadata of each tenant to the long-term storage. The snapshots are merged by the compactor when -compactor.metadata-merging-enabled is true. 0 disables metadata shipping.")
 	f.DurationVar(&cfg.RateUpdatePeriod, "ingester.rate-update-period", 15*time.Second, "Period with which to update the per-tenant ingestion rates.")
 	f.BoolVar(&cfg.StreamChunksWhenUsingBlocks, "ingester.stream-chunks-when-using-blocks", true, "Stream chunks from ingesters to queriers.")
 	f.DurationVar(&cfg.TSDBConfigUpdatePeriod, "ingester.tsdb-config-update-period", 15*time.Second, "Period with which to update the per-tenant TSDB configuration.")
 	f.StringVar(&cfg.IgnoreSeriesLimitForMetricNames, "ingester.ignore-series-limit-for-metric-names", "", "Comma-separated list of metric names, for which the -ingester.max-global-series-per-metric limit will be ignored. Does not affect the -ingester.max-global-series-per-user limit.")
 	f.Float64Var(&cfg.ReadPathCPUUtilizationLimit, "ingester.read-path-cpu-utilization-limit", 0, "CPU utilization limit, as CPU cores, for CPU/memory utilization based read request limiting. Use 0 to disable it.")
 	f.Uint64Var(&cfg.ReadPathMemoryUtilizationLimit, "ingester.read-path-memory-utilization-limit", 0, "Memory limit, in bytes, for CPU/memory utilization based read request limiting. Use 0 to disable it.")
+	f.Uint64Var(&cfg.WritePathMemoryUtilizationLimit, "ingester.write-path-memory-utilization-limit", 0, "Memory limit, in bytes, for memory utilization based write request limiting. Write requests are rejected with a retriable error once the memory utilization has been above this limit for a sustained period of time. Must be greater than -ingester.read-path-memory-utilization-limit, if set. Use 0 to disable it.")
 	f.BoolVar(&cfg.LogUtilizationBasedLimiterCPUSamples, "ingester.log-utilization-based-limiter-cpu-samples", false, "Enable logging of utilization based limiter CPU samples.")
+	f.BoolVar(&cfg.TrackIngesterOwnedSeries, "ingester.track-ingester-owned-series", false, "When enabled, the ingester tracks the number of in-memory series it owns for each tenant, based on the ingesters ring and the tenant shard size. Owned series are exposed on the ingester owned series admin page.")
+	f.BoolVar(&cfg.UseIngesterOwnedSeriesForLimits, "ingester.use-ingester-owned-series-for-limits", false, "When enabled, the per-tenant series limit is enforced on the number of owned series, and the local limit is computed from the portion of the tokens space owned by the ingester. Requires -ingester.track-ingester-owned-series to be enabled.")
+	f.DurationVar(&cfg.OwnedSeriesUpdateInterval, "ingester.owned-series-update-interval", 15*time.Second, "How often to check for changes in the ingesters ring and in the tenants shard size, and to recompute owned series for the affected tenants.")
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.WritePathMemoryUtilizationLimit > 0 && cfg.WritePathMemoryUtilizationLimit <= cfg.ReadPathMemoryUtilizationLimit {
+		return errInvalidWritePathMemoryUtilizationLimit
+	}
+
+	if cfg.UseIngesterOwnedSeriesForLimits && !cfg.TrackIngesterOwnedSeries {
+		return errInvalidOwnedSeriesLimitsConfig
+	}
+
+	if cfg.TrackIngesterOwnedSeries && cfg.OwnedSeriesUpdateInterval <= 0 {
+		return errInvalidOwnedSeriesUpdateInterval
+	}
+
 	return cfg.IngesterRing.Validate()
 }
 
@@ -276,6 +302,10 @@ type Ingester struct {
 	maxOutOfOrderTimeWindowSecondsStat *expvar.Int
 
 	utilizationBasedLimiter utilizationBasedLimiter
+
+	// Ingesters ring client and service used to track owned series. Only set if owned series tracking is enabled.
+	ownedSeriesRing    *ring.Ring
+	ownedSeriesService *ownedSeriesService
 }
 
 func newIngester(cfg Config, limits *validation.Overrides, registerer prometheus.Registerer, logger log.Logger) (*Ingester, error) {
@@ -347,13 +377,27 @@ func New(cfg Config, limits *validation.Overrides, activeGroupsCleanupService *u
 		cfg.IngesterRing.ReplicationFactor,
 		cfg.IngesterRing.ZoneAwarenessEnabled)
 
-	if cfg.ReadPathCPUUtilizationLimit > 0 || cfg.ReadPathMemoryUtilizationLimit > 0 {
+	if cfg.ReadPathCPUUtilizationLimit > 0 || cfg.ReadPathMemoryUtilizationLimit > 0 || cfg.WritePathMemoryUtilizationLimit > 0 {
 		i.utilizationBasedLimiter = limiter.NewUtilizationBasedLimiter(cfg.ReadPathCPUUtilizationLimit,
-			cfg.ReadPathMemoryUtilizationLimit, cfg.LogUtilizationBasedLimiterCPUSamples,
-			log.WithPrefix(logger, "context", "read path"),
+			cfg.ReadPathMemoryUtilizationLimit, cfg.WritePathMemoryUtilizationLimit, cfg.LogUtilizationBasedLimiterCPUSamples,
+			log.WithPrefix(logger, "context", "utilization based limiter"),
 			prometheus.WrapRegistererWithPrefix("cortex_ingester_", registerer))
 	}
 
+	if cfg.TrackIngesterOwnedSeries {
+		i.ownedSeriesRing, err = ring.New(cfg.IngesterRing.ToRingConfig(), "ingester", IngesterRingKey, logger, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create the ingesters ring client for owned series tracking")
+		}
+
+		i.ownedSeriesService = newOwnedSeriesService(cfg.OwnedSeriesUpdateInterval, i.lifecycler.Addr, i.ownedSeriesRing,
+			limits.IngestionTenantShardSize, i.getTSDBUsers, i.getTSDB, log.With(logger, "component", "owned series"), registerer)
+
+		if cfg.UseIngesterOwnedSeriesForLimits {
+			i.limiter.ownershipRatioFn = i.getOwnershipRatio
+		}
+	}
+
 	i.shipperIngesterID = i.lifecycler.ID
 
 	// Apply positive jitter only to ensure that the minimum timeout is adhered to.
@@ -430,10 +474,19 @@ func (i *Ingester) starting(ctx context.Context) error {
 		servs = append(servs, closeIdleService)
 	}
 
+	if i.cfg.MetadataShippingInterval > 0 {
+		metadataShippingService := services.NewTimerService(i.cfg.MetadataShippingInterval, nil, i.shipMetricsMetadata, nil)
+		servs = append(servs, metadataShippingService)
+	}
+
 	if i.utilizationBasedLimiter != nil {
 		servs = append(servs, i.utilizationBasedLimiter)
 	}
 
+	if i.ownedSeriesService != nil {
+		servs = append(servs, i.ownedSeriesRing, i.ownedSeriesService)
+	}
+
 	shutdownMarkerPath := shutdownmarker.GetPath(i.cfg.BlocksStorageConfig.TSDB.Dir)
 	shutdownMarkerFound, err := shutdownmarker.Exists(shutdownMarkerPath)
 	if err != nil {
@@ -702,6 +755,7 @@ type pushStats struct {
 	failedExemplarsCount      int
 	sampleOutOfBoundsCount    int
 	sampleOutOfOrderCount     int
+	histogramOutOfOrderCount  int
 	sampleTooOldCount         int
 	newValueForTimestampCount int
 	perUserSeriesLimitCount   int
@@ -742,6 +796,10 @@ func (i *Ingester) PushWithCleanup(ctx context.Context, pushReq *push.Request) (
 		}
 	}
 
+	if err := i.checkWriteOverloaded(); err != nil {
+		return nil, err
+	}
+
 	req, err := pushReq.WriteRequest()
 	if err != nil {
 		return nil, err
@@ -800,7 +858,7 @@ func (i *Ingester) PushWithCleanup(ctx context.Context, pushReq *push.Request) (
 
 	minAppendTime, minAppendTimeAvailable := db.Head().AppendableMinValidTime()
 
-	err = i.pushSamplesToAppender(userID, req.Timeseries, app, startAppend, &stats, updateFirstPartial, activeSeries, i.limits.OutOfOrderTimeWindow(userID), minAppendTimeAvailable, minAppendTime)
+	err = i.pushSamplesToAppender(userID, req.Timeseries, app, db.Head(), startAppend, &stats, updateFirstPartial, activeSeries, i.limits.OutOfOrderTimeWindow(userID), minAppendTimeAvailable, minAppendTime)
 	if err != nil {
 		if err := app.Rollback(); err != nil {
 			level.Warn(i.logger).Log("msg", "failed to rollback appender on error", "user", userID, "err", err)
@@ -867,6 +925,9 @@ func (i *Ingester) updateMetricsFromPushStats(userID string, group string, stats
 	if stats.sampleOutOfOrderCount > 0 {
 		discarded.sampleOutOfOrder.WithLabelValues(userID, group).Add(float64(stats.sampleOutOfOrderCount))
 	}
+	if stats.histogramOutOfOrderCount > 0 {
+		discarded.histogramOutOfOrder.WithLabelValues(userID, group).Add(float64(stats.histogramOutOfOrderCount))
+	}
 	if stats.sampleTooOldCount > 0 {
 		discarded.sampleTooOld.WithLabelValues(userID, group).Add(float64(stats.sampleTooOldCount))
 	}
@@ -892,7 +953,7 @@ func (i *Ingester) updateMetricsFromPushStats(userID string, group string, stats
 
 // pushSamplesToAppender appends samples and exemplars to the appender. Most errors are handled via updateFirstPartial function,
 // but in case of unhandled errors, appender is rolled back and such error is returned.
-func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.PreallocTimeseries, app extendedAppender, startAppend time.Time,
+func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.PreallocTimeseries, app extendedAppender, head *tsdb.Head, startAppend time.Time,
 	stats *pushStats, updateFirstPartial func(errFn func() error), activeSeries *activeseries.ActiveSeries,
 	outOfOrderWindow time.Duration, minAppendTimeAvailable bool, minAppendTime int64) error {
 
@@ -951,6 +1012,7 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 
 	// fetch once per push request to avoid processing half the request differently
 	nativeHistogramsIngestionEnabled := i.limits.NativeHistogramsIngestionEnabled(userID)
+	createdTimestampZeroIngestionEnabled := i.limits.CreatedTimestampZeroIngestionEnabled(userID)
 
 	var builder labels.ScratchBuilder
 	var nonCopiedLabels labels.Labels
@@ -958,6 +1020,18 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 		// The labels must be sorted (in our case, it's guaranteed a write request
 		// has sorted labels once hit the ingester).
 
+		// The zero samples synthesized by the distributor at the created timestamp of the series are
+		// sent again with every request, so they're never reported as failed.
+		zeroSample := createdTimestampZeroIngestionEnabled && ts.CreatedTimestampZeroSample && len(ts.Samples) > 0
+		zeroHistogram := createdTimestampZeroIngestionEnabled && ts.CreatedTimestampZeroHistogram && len(ts.Histograms) > 0
+		clientSamples, clientHistograms := ts.Samples, ts.Histograms
+		if zeroSample {
+			clientSamples = clientSamples[1:]
+		}
+		if zeroHistogram {
+			clientHistograms = clientHistograms[1:]
+		}
+
 		// Fast path in case we only have samples and they are all out of bound
 		// and out-of-order support is not enabled.
 		// TODO(jesus.vazquez) If we had too many old samples we might want to
@@ -968,15 +1042,15 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 				allOutOfBoundsFloats(ts.Samples, minAppendTime) &&
 				allOutOfBoundsHistograms(ts.Histograms, minAppendTime) {
 
-				stats.failedSamplesCount += len(ts.Samples) + len(ts.Histograms)
-				stats.sampleOutOfBoundsCount += len(ts.Samples) + len(ts.Histograms)
+				stats.failedSamplesCount += len(clientSamples) + len(clientHistograms)
+				stats.sampleOutOfBoundsCount += len(clientSamples) + len(clientHistograms)
 
 				var firstTimestamp int64
-				if len(ts.Samples) > 0 {
-					firstTimestamp = ts.Samples[0].TimestampMs
+				if len(clientSamples) > 0 {
+					firstTimestamp = clientSamples[0].TimestampMs
 				}
-				if len(ts.Histograms) > 0 && ts.Histograms[0].Timestamp < firstTimestamp {
-					firstTimestamp = ts.Histograms[0].Timestamp
+				if len(clientHistograms) > 0 && clientHistograms[0].Timestamp < firstTimestamp {
+					firstTimestamp = clientHistograms[0].Timestamp
 				}
 
 				updateFirstPartial(func() error {
@@ -989,10 +1063,13 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 			if outOfOrderWindow <= 0 && minAppendTimeAvailable && len(ts.Exemplars) == 0 &&
 				len(ts.Samples) > 0 && allOutOfBoundsFloats(ts.Samples, minAppendTime) {
 
-				stats.failedSamplesCount += len(ts.Samples)
-				stats.sampleOutOfBoundsCount += len(ts.Samples)
+				stats.failedSamplesCount += len(clientSamples)
+				stats.sampleOutOfBoundsCount += len(clientSamples)
 
-				firstTimestamp := ts.Samples[0].TimestampMs
+				var firstTimestamp int64
+				if len(clientSamples) > 0 {
+					firstTimestamp = clientSamples[0].TimestampMs
+				}
 
 				updateFirstPartial(func() error {
 					return newIngestErrSampleTimestampTooOld(model.Time(firstTimestamp), ts.Labels)
@@ -1011,9 +1088,16 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 		// To find out if any sample was added to this series, we keep old value.
 		oldSucceededSamplesCount := stats.succeededSamplesCount
 
-		for _, s := range ts.Samples {
+		for idx, s := range ts.Samples {
 			var err error
 
+			// The zero sample at the created timestamp is not appended if the series already has newer samples,
+			// otherwise it would be ingested as an out-of-order sample.
+			isZeroSample := zeroSample && idx == 0
+			if isZeroSample && ref != 0 && outOfOrderWindow > 0 && hasSamplesSince(head, ref, s.TimestampMs) {
+				continue
+			}
+
 			// If the cached reference exists, we try to use it.
 			if ref != 0 {
 				if _, err = app.Append(ref, copiedLabels, s.TimestampMs, s.Value); err == nil {
@@ -1031,6 +1115,10 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 				}
 			}
 
+			if isZeroSample && isCreatedTimestampZeroSampleAppendError(err) {
+				continue
+			}
+
 			stats.failedSamplesCount++
 
 			// If it's a soft error it will be returned back to the distributor later as a 400.
@@ -1044,13 +1132,18 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 
 		numNativeHistogramBuckets := -1
 		if nativeHistogramsIngestionEnabled {
-			for _, h := range ts.Histograms {
+			for idx, h := range ts.Histograms {
 				var (
 					err error
 					ih  *histogram.Histogram
 					fh  *histogram.FloatHistogram
 				)
 
+				isZeroHistogram := zeroHistogram && idx == 0
+				if isZeroHistogram && ref != 0 && outOfOrderWindow > 0 && hasSamplesSince(head, ref, h.Timestamp) {
+					continue
+				}
+
 				if h.IsFloatHistogram() {
 					fh = mimirpb.FromFloatHistogramProtoToFloatHistogram(&h)
 				} else {
@@ -1074,8 +1167,23 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 					}
 				}
 
+				if isZeroHistogram && isCreatedTimestampZeroSampleAppendError(err) {
+					continue
+				}
+
 				stats.failedSamplesCount++
 
+				// The TSDB doesn't support out-of-order native histograms, even if the out-of-order time
+				// window is enabled, so we track them separately from out-of-order float samples.
+				//nolint:errorlint // We don't expect the cause error to be wrapped.
+				if errors.Cause(err) == storage.ErrOutOfOrderSample {
+					stats.histogramOutOfOrderCount++
+					updateFirstPartial(func() error {
+						return newIngestErrHistogramOutOfOrder(model.Time(h.Timestamp), ts.Labels)
+					})
+					continue
+				}
+
 				if handleAppendError(err, h.Timestamp, ts.Labels) {
 					continue
 				}
@@ -1137,6 +1245,60 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 	return nil
 }
 
+// isCreatedTimestampZeroSampleAppendError returns whether err is expected when appending a zero sample
+// at the created timestamp of a series which already has samples.
+func isCreatedTimestampZeroSampleAppendError(err error) bool {
+	//nolint:errorlint // We don't expect the cause error to be wrapped.
+	switch errors.Cause(err) {
+	case storage.ErrOutOfBounds, storage.ErrOutOfOrderSample, storage.ErrTooOldSample, storage.ErrDuplicateSampleForTimestamp:
+		return true
+	}
+	return false
+}
+
+// hasSamplesSince returns whether the series with the input reference has any in-order sample
+// with timestamp greater than or equal to ts in the TSDB head.
+func hasSamplesSince(head *tsdb.Head, ref storage.SeriesRef, ts int64) bool {
+	idx, err := head.Index()
+	if err != nil {
+		return false
+	}
+	defer idx.Close()
+
+	var (
+		builder labels.ScratchBuilder
+		chks    []chunks.Meta
+	)
+	if err := idx.Series(ref, &builder, &chks); err != nil || len(chks) == 0 {
+		return false
+	}
+
+	last := chks[len(chks)-1]
+	if last.MinTime >= ts {
+		return true
+	}
+	if last.MaxTime != math.MaxInt64 {
+		return last.MaxTime >= ts
+	}
+
+	// The index reader doesn't know the max time of the open head chunk,
+	// while the head chunk reader returns it together with the chunk.
+	cr, err := head.Chunks()
+	if err != nil {
+		return false
+	}
+	defer cr.Close()
+
+	hcr, ok := cr.(interface {
+		ChunkWithCopy(chunks.Meta) (chunkenc.Chunk, int64, error)
+	})
+	if !ok {
+		return false
+	}
+	_, maxTime, err := hcr.ChunkWithCopy(last)
+	return err == nil && maxTime >= ts
+}
+
 func (i *Ingester) QueryExemplars(ctx context.Context, req *client.ExemplarQueryRequest) (*client.ExemplarQueryResponse, error) {
 	if err := i.checkRunning(); err != nil {
 		return nil, err
@@ -2070,6 +2232,9 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 		instanceSeriesCount: &i.seriesCount,
 		instanceErrors:      i.metrics.rejected,
 		blockMinRetention:   i.cfg.BlocksStorageConfig.TSDB.Retention,
+
+		ownedSeriesTracked:      i.cfg.TrackIngesterOwnedSeries,
+		useOwnedSeriesForLimits: i.cfg.UseIngesterOwnedSeriesForLimits,
 	}
 
 	maxExemplars := i.limiter.convertGlobalToLocalLimit(userID, i.limits.MaxGlobalExemplarsPerUser(userID))
@@ -2135,6 +2300,11 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 
 	// Create a new shipper for this database
 	if i.cfg.BlocksStorageConfig.TSDB.IsBlocksShippingEnabled() {
+		var prepareUpload func(string, *block.Meta) error
+		if i.cfg.BlocksStorageConfig.TSDB.ExemplarsShippingEnabled {
+			prepareUpload = userDB.writeBlockExemplars
+		}
+
 		userDB.shipper = newShipper(
 			userLogger,
 			i.limits,
@@ -2143,6 +2313,7 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 			udir,
 			bucket.NewUserBucketClient(userID, i.bucket, i.limits),
 			block.ReceiveSource,
+			prepareUpload,
 		)
 
 		// Initialise the shipper blocks cache.
@@ -2866,6 +3037,10 @@ func newIngestErrSampleOutOfOrder(timestamp model.Time, labels []mimirpb.LabelAd
 	return newIngestErr(globalerror.SampleOutOfOrder, "the sample has been rejected because another sample with a more recent timestamp has already been ingested and out-of-order samples are not allowed", timestamp, labels)
 }
 
+func newIngestErrHistogramOutOfOrder(timestamp model.Time, labels []mimirpb.LabelAdapter) error {
+	return newIngestErr(globalerror.HistogramOutOfOrder, "the native histogram sample has been rejected because another sample with a more recent timestamp has already been ingested and out-of-order native histogram samples are not allowed", timestamp, labels)
+}
+
 func newIngestErrSampleDuplicateTimestamp(timestamp model.Time, labels []mimirpb.LabelAdapter) error {
 	return newIngestErr(globalerror.SampleDuplicateTimestamp, "the sample has been rejected because another sample with the same timestamp, but a different value, has already been ingested", timestamp, labels)
 }
@@ -3119,6 +3294,39 @@ func (i *Ingester) purgeUserMetricsMetadata() {
 	}
 }
 
+// shipMetricsMetadata uploads a snapshot of the in-memory metrics metadata of each tenant to the
+// storage, where the compactor merges the snapshots of all ingesters.
+func (i *Ingester) shipMetricsMetadata(ctx context.Context) error {
+	for _, userID := range i.getUsersWithMetadata() {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		metadata := i.getUserMetadata(userID)
+		if metadata == nil {
+			continue
+		}
+
+		entries := metadata.toStoredMetadata()
+		if len(entries) == 0 {
+			continue
+		}
+		metricsmetadata.SortEntries(entries)
+
+		err := metricsmetadata.WriteIngesterMetadata(ctx, i.bucket, userID, i.limits, i.cfg.IngesterRing.InstanceID, &metricsmetadata.Metadata{
+			Version:   metricsmetadata.MetadataVersion1,
+			Metrics:   entries,
+			UpdatedAt: time.Now().Unix(),
+		})
+		if err != nil {
+			// Errors are not returned in order to keep shipping metadata at the next interval.
+			level.Warn(i.logger).Log("msg", "failed to ship metrics metadata", "user", userID, "err", err)
+		}
+	}
+
+	return nil
+}
+
 // MetricsMetadata returns all the metrics metadata of a user.
 func (i *Ingester) MetricsMetadata(ctx context.Context, _ *client.MetricsMetadataRequest) (*client.MetricsMetadataResponse, error) {
 	if err := i.checkRunning(); err != nil {
@@ -3228,8 +3436,24 @@ func (i *Ingester) checkReadOverloaded() error {
 	return tooBusyError
 }
 
+// checkWriteOverloaded checks whether the ingester write path is overloaded wrt. memory.
+func (i *Ingester) checkWriteOverloaded() error {
+	if i.utilizationBasedLimiter == nil {
+		return nil
+	}
+
+	reason := i.utilizationBasedLimiter.WritePathLimitingReason()
+	if reason == "" {
+		return nil
+	}
+
+	i.metrics.utilizationLimitedWriteRequests.WithLabelValues(reason).Inc()
+	return tooBusyWriteError
+}
+
 type utilizationBasedLimiter interface {
 	services.Service
 
 	LimitingReason() string
+	WritePathLimitingReason() string
 }
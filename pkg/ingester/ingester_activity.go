@@ -167,6 +167,15 @@ func (i *ActivityTrackerWrapper) UserRegistryHandler(writer http.ResponseWriter,
 	i.ing.UserRegistryHandler(writer, request)
 }
 
+func (i *ActivityTrackerWrapper) OwnedSeriesHandler(writer http.ResponseWriter, request *http.Request) {
+	ix := i.tracker.Insert(func() string {
+		return requestActivity(request.Context(), "Ingester/OwnedSeriesHandler", nil)
+	})
+	defer i.tracker.Delete(ix)
+
+	i.ing.OwnedSeriesHandler(writer, request)
+}
+
 func requestActivity(ctx context.Context, name string, req interface{}) string {
 	userID, _ := tenant.TenantID(ctx)
 	traceID, _ := tracing.ExtractSampledTraceID(ctx)
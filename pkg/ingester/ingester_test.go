@@ -19,6 +19,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -39,6 +40,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
@@ -61,6 +64,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
 	"github.com/grafana/mimir/pkg/usagestats"
 	"github.com/grafana/mimir/pkg/util"
 	util_math "github.com/grafana/mimir/pkg/util/math"
@@ -430,6 +434,53 @@ func TestIngester_Push(t *testing.T) {
 				cortex_ingester_active_series{user="test"} 1
 			`,
 		},
+		"should soft fail on histogram out-of-order and track it separately from float samples": {
+			reqs: []*mimirpb.WriteRequest{
+				mimirpb.NewWriteRequest(nil, mimirpb.API).AddHistogramSeries([][]mimirpb.LabelAdapter{metricLabelAdapters},
+					[]mimirpb.Histogram{mimirpb.FromHistogramToHistogramProto(10, util_test.GenerateTestHistogram(1))}, nil),
+				mimirpb.NewWriteRequest(nil, mimirpb.API).AddHistogramSeries([][]mimirpb.LabelAdapter{metricLabelAdapters},
+					[]mimirpb.Histogram{mimirpb.FromHistogramToHistogramProto(9, util_test.GenerateTestHistogram(2))}, nil),
+			},
+			expectedErr: httpgrpc.Errorf(http.StatusBadRequest, wrapWithUser(newIngestErrHistogramOutOfOrder(model.Time(9), metricLabelAdapters), userID).Error()),
+			expectedIngested: model.Matrix{
+				&model.SampleStream{Metric: metricLabelSet, Histograms: []model.SampleHistogramPair{
+					{Timestamp: model.Time(10), Histogram: mimirpb.FromHistogramToPromHistogram(util_test.GenerateTestHistogram(1))},
+				}},
+			},
+			expectedMetrics: `
+				# HELP cortex_ingester_ingested_samples_total The total number of samples ingested per user.
+				# TYPE cortex_ingester_ingested_samples_total counter
+				cortex_ingester_ingested_samples_total{user="test"} 1
+				# HELP cortex_ingester_ingested_samples_failures_total The total number of samples that errored on ingestion per user.
+				# TYPE cortex_ingester_ingested_samples_failures_total counter
+				cortex_ingester_ingested_samples_failures_total{user="test"} 1
+				# HELP cortex_ingester_memory_users The current number of users in memory.
+				# TYPE cortex_ingester_memory_users gauge
+				cortex_ingester_memory_users 1
+				# HELP cortex_ingester_memory_series The current number of series in memory.
+				# TYPE cortex_ingester_memory_series gauge
+				cortex_ingester_memory_series 1
+				# HELP cortex_ingester_memory_series_created_total The total number of series that were created per user.
+				# TYPE cortex_ingester_memory_series_created_total counter
+				cortex_ingester_memory_series_created_total{user="test"} 1
+				# HELP cortex_ingester_memory_series_removed_total The total number of series that were removed per user.
+				# TYPE cortex_ingester_memory_series_removed_total counter
+				cortex_ingester_memory_series_removed_total{user="test"} 0
+				# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+				# TYPE cortex_discarded_samples_total counter
+				cortex_discarded_samples_total{group="",reason="histogram-out-of-order",user="test"} 1
+				# HELP cortex_ingester_active_series Number of currently active series per user.
+				# TYPE cortex_ingester_active_series gauge
+				cortex_ingester_active_series{user="test"} 1
+				# HELP cortex_ingester_active_native_histogram_series Number of currently active native histogram series per user.
+				# TYPE cortex_ingester_active_native_histogram_series gauge
+				cortex_ingester_active_native_histogram_series{user="test"} 1
+				# HELP cortex_ingester_active_native_histogram_buckets Number of currently active native histogram buckets per user.
+				# TYPE cortex_ingester_active_native_histogram_buckets gauge
+				cortex_ingester_active_native_histogram_buckets{user="test"} 8
+			`,
+			nativeHistograms: true,
+		},
 		"should soft fail on all samples out of bound in a write request": {
 			reqs: []*mimirpb.WriteRequest{
 				mimirpb.ToWriteRequest(
@@ -3991,6 +4042,68 @@ func TestIngester_dontShipBlocksWhenTenantDeletionMarkerIsPresent(t *testing.T)
 	require.Equal(t, tsdbTenantMarkedForDeletion, i.closeAndDeleteUserTSDBIfIdle(userID))
 }
 
+func TestIngester_shipBlocksWithExemplars(t *testing.T) {
+	for _, exemplarsShippingEnabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("exemplars shipping enabled: %t", exemplarsShippingEnabled), func(t *testing.T) {
+			cfg := defaultIngesterTestConfig(t)
+			cfg.BlocksStorageConfig.TSDB.ExemplarsShippingEnabled = exemplarsShippingEnabled
+			limits := defaultLimitsTestConfig()
+			limits.MaxGlobalExemplarsPerUser = 10
+
+			i, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, "", nil)
+			require.NoError(t, err)
+
+			// Use in-memory bucket.
+			bucket := objstore.NewInMemBucket()
+			i.bucket = bucket
+
+			require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+			defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+			// Wait until it's healthy
+			test.Poll(t, 1*time.Second, 1, func() interface{} {
+				return i.lifecycler.HealthyInstancesCount()
+			})
+
+			now := util.TimeToMillis(time.Now())
+			series := []mimirpb.LabelAdapter{{Name: labels.MetricName, Value: "test"}}
+			exemplar := mimirpb.Exemplar{Labels: []mimirpb.LabelAdapter{{Name: "traceID", Value: "123"}}, TimestampMs: now, Value: 1}
+
+			// The write request is cleared once pushed, so a copy of the exemplar is pushed.
+			pushedExemplar := mimirpb.Exemplar{Labels: []mimirpb.LabelAdapter{{Name: "traceID", Value: "123"}}, TimestampMs: now, Value: 1}
+
+			ctx := user.InjectOrgID(context.Background(), userID)
+			_, err = i.Push(ctx, mimirpb.ToWriteRequest([][]mimirpb.LabelAdapter{{{Name: labels.MetricName, Value: "test"}}}, []mimirpb.Sample{{Value: 1, TimestampMs: now}}, []*mimirpb.Exemplar{&pushedExemplar}, nil, mimirpb.API))
+			require.NoError(t, err)
+
+			i.compactBlocks(context.Background(), true, math.MaxInt64, nil)
+			i.shipBlocks(context.Background(), nil)
+
+			var blockIDs []string
+			require.NoError(t, bucket.Iter(context.Background(), userID, func(name string) error {
+				blockIDs = append(blockIDs, name)
+				return nil
+			}))
+			require.Len(t, blockIDs, 1)
+
+			reader, err := bucket.Get(context.Background(), path.Join(blockIDs[0], block.ExemplarsFilename))
+			if !exemplarsShippingEnabled {
+				require.True(t, bucket.IsObjNotFoundErr(err))
+				return
+			}
+			require.NoError(t, err)
+			defer reader.Close()
+
+			var actual []mimirpb.TimeSeries
+			require.NoError(t, block.ReadExemplars(reader, func(s mimirpb.TimeSeries) error {
+				actual = append(actual, s)
+				return nil
+			}))
+			require.Equal(t, []mimirpb.TimeSeries{{Labels: series, Exemplars: []mimirpb.Exemplar{exemplar}}}, actual)
+		})
+	}
+}
+
 func TestIngester_seriesCountIsCorrectAfterClosingTSDBForDeletedTenant(t *testing.T) {
 	cfg := defaultIngesterTestConfig(t)
 	cfg.BlocksStorageConfig.TSDB.ShipConcurrency = 2
@@ -5694,6 +5807,56 @@ func TestIngester_inflightPushRequests(t *testing.T) {
 	require.NoError(t, g.Wait())
 }
 
+func TestIngester_Push_ShouldRejectWhenWritePathIsOverloaded(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	i, err := prepareIngesterWithBlocksStorage(t, defaultIngesterTestConfig(t), registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), i))
+	})
+
+	// Wait until the ingester is healthy
+	test.Poll(t, 100*time.Millisecond, 1, func() interface{} {
+		return i.lifecycler.HealthyInstancesCount()
+	})
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	// Read path limiting doesn't affect the write path.
+	i.utilizationBasedLimiter = &fakeUtilizationBasedLimiter{limitingReason: "memory"}
+	_, err = i.Push(ctx, generateSamplesForLabel(labels.FromStrings(labels.MetricName, "test"), 1, 1))
+	require.NoError(t, err)
+
+	i.utilizationBasedLimiter = &fakeUtilizationBasedLimiter{limitingReason: "memory", writeLimitingReason: "memory"}
+	_, err = i.Push(ctx, generateSamplesForLabel(labels.FromStrings(labels.MetricName, "test"), 1, 1))
+	require.Equal(t, tooBusyWriteError, err)
+
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusServiceUnavailable, int(resp.Code))
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_utilization_limited_write_requests_total Total number of times write requests have been rejected due to utilization based limiting.
+		# TYPE cortex_ingester_utilization_limited_write_requests_total counter
+		cortex_ingester_utilization_limited_write_requests_total{reason="memory"} 1
+	`), "cortex_ingester_utilization_limited_write_requests_total"))
+}
+
+func TestConfig_ValidateWritePathMemoryUtilizationLimit(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	require.NoError(t, cfg.Validate())
+
+	cfg.WritePathMemoryUtilizationLimit = 1024
+	require.NoError(t, cfg.Validate())
+
+	cfg.ReadPathMemoryUtilizationLimit = 1024
+	require.Equal(t, errInvalidWritePathMemoryUtilizationLimit, cfg.Validate())
+
+	cfg.WritePathMemoryUtilizationLimit = 2048
+	require.NoError(t, cfg.Validate())
+}
+
 func generateSamplesForLabel(baseLabels labels.Labels, series, samples int) *mimirpb.WriteRequest {
 	lbls := make([][]mimirpb.LabelAdapter, 0, series*samples)
 	ss := make([]mimirpb.Sample, 0, series*samples)
@@ -5855,6 +6018,48 @@ func TestIngesterPurgeMetadata(t *testing.T) {
 	}
 }
 
+func TestIngester_shipMetricsMetadata(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+
+	ing, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, defaultLimitsTestConfig(), "", nil)
+	require.NoError(t, err)
+
+	// Use in-memory bucket.
+	bkt := objstore.NewInMemBucket()
+	ing.bucket = bkt
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+	defer services.StopAndAwaitTerminated(context.Background(), ing) //nolint:errcheck
+
+	// Wait until the ingester is healthy
+	test.Poll(t, 100*time.Millisecond, 1, func() interface{} {
+		return ing.lifecycler.HealthyInstancesCount()
+	})
+
+	startTime := time.Now().Unix()
+	userIDs, testData := pushTestMetadata(t, ing, 2, 2)
+	require.NoError(t, ing.shipMetricsMetadata(context.Background()))
+
+	for _, userID := range userIDs {
+		updater := metricsmetadata.NewUpdater(bkt, userID, nil, log.NewNopLogger())
+		stored, err := updater.UpdateMetadata(context.Background(), time.Hour)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+
+		expected := make([]scrape.MetricMetadata, 0, len(testData[userID]))
+		for _, m := range testData[userID] {
+			expected = append(expected, scrape.MetricMetadata{Metric: m.MetricFamilyName, Type: textparse.MetricTypeCounter, Help: m.Help, Unit: m.Unit})
+		}
+
+		actual := make([]scrape.MetricMetadata, 0, len(stored.Metrics))
+		for _, e := range stored.Metrics {
+			actual = append(actual, e.ToScrapeMetadata())
+			assert.GreaterOrEqual(t, e.LastSeen, startTime)
+		}
+		assert.ElementsMatch(t, expected, actual)
+	}
+}
+
 func TestIngesterMetadataMetrics(t *testing.T) {
 	reg := prometheus.NewPedanticRegistry()
 	cfg := defaultIngesterTestConfig(t)
@@ -7252,6 +7457,118 @@ func Test_Ingester_OutOfOrder(t *testing.T) {
 	assert.Equal(t, int64(30*60), usagestats.GetInt(maxOutOfOrderTimeWindowSecondsStatName).Value())
 }
 
+func TestIngester_Push_CreatedTimestampZeroSamples(t *testing.T) {
+	type pushedSeries struct {
+		createdTimestamp int64
+		zeroSample       bool
+		samples          []mimirpb.Sample
+		expectedErr      bool
+	}
+
+	tests := map[string]struct {
+		enabled         bool
+		oooTimeWindow   time.Duration
+		requests        []pushedSeries
+		expectedSamples []model.SamplePair
+	}{
+		"should not report the zero sample as failed when rejected": {
+			enabled: true,
+			requests: []pushedSeries{
+				{createdTimestamp: 1000, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 2000, Value: 1}}},
+				{createdTimestamp: 1000, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 3000, Value: 2}}},
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 1}, {Timestamp: 3000, Value: 2}},
+		},
+		"should report a zero sample sent by the client at the created timestamp as failed": {
+			enabled: true,
+			requests: []pushedSeries{
+				{createdTimestamp: 1000, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 2000, Value: 1}}},
+				{createdTimestamp: 1000, samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 3000, Value: 2}}, expectedErr: true},
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 1}, {Timestamp: 3000, Value: 2}},
+		},
+		"should report the zero sample as failed when disabled": {
+			requests: []pushedSeries{
+				{createdTimestamp: 1000, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 2000, Value: 1}}},
+				{createdTimestamp: 1000, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 3000, Value: 2}}, expectedErr: true},
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 1000, Value: 0}, {Timestamp: 2000, Value: 1}, {Timestamp: 3000, Value: 2}},
+		},
+		"should not append the zero sample out-of-order": {
+			enabled:       true,
+			oooTimeWindow: 30 * time.Minute,
+			requests: []pushedSeries{
+				{samples: []mimirpb.Sample{{TimestampMs: 2000, Value: 1}, {TimestampMs: 3000, Value: 2}}},
+				{createdTimestamp: 1500, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 1500, Value: 0}, {TimestampMs: 4000, Value: 3}}},
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 2000, Value: 1}, {Timestamp: 3000, Value: 2}, {Timestamp: 4000, Value: 3}},
+		},
+		"should append the zero sample in-order when the out-of-order time window is enabled": {
+			enabled:       true,
+			oooTimeWindow: 30 * time.Minute,
+			requests: []pushedSeries{
+				{samples: []mimirpb.Sample{{TimestampMs: 2000, Value: 1}, {TimestampMs: 3000, Value: 2}}},
+				{createdTimestamp: 3500, zeroSample: true, samples: []mimirpb.Sample{{TimestampMs: 3500, Value: 0}, {TimestampMs: 4000, Value: 1}}},
+			},
+			expectedSamples: []model.SamplePair{{Timestamp: 2000, Value: 1}, {Timestamp: 3000, Value: 2}, {Timestamp: 3500, Value: 0}, {Timestamp: 4000, Value: 1}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limits := defaultLimitsTestConfig()
+			limits.CreatedTimestampZeroIngestionEnabled = tc.enabled
+			limits.OutOfOrderTimeWindow = model.Duration(tc.oooTimeWindow)
+
+			i, err := prepareIngesterWithBlocksStorageAndLimits(t, defaultIngesterTestConfig(t), limits, "", nil)
+			require.NoError(t, err)
+			require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+			defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+			// Wait until it's healthy
+			test.Poll(t, 1*time.Second, 1, func() interface{} {
+				return i.lifecycler.HealthyInstancesCount()
+			})
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+
+			for _, series := range tc.requests {
+				req := &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{{TimeSeries: &mimirpb.TimeSeries{
+					Labels:                     []mimirpb.LabelAdapter{{Name: labels.MetricName, Value: "test_total"}},
+					Samples:                    series.samples,
+					CreatedTimestamp:           series.createdTimestamp,
+					CreatedTimestampZeroSample: series.zeroSample,
+				}}}}
+
+				_, err := i.Push(ctx, req)
+				if series.expectedErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+
+			req := &client.QueryRequest{
+				StartTimestampMs: math.MinInt64,
+				EndTimestampMs:   math.MaxInt64,
+				Matchers: []*client.LabelMatcher{
+					{Type: client.EQUAL, Name: model.MetricNameLabel, Value: "test_total"},
+				},
+			}
+
+			s := stream{ctx: ctx}
+			require.NoError(t, i.QueryStream(req, &s))
+
+			res, err := client.StreamsToMatrix(model.Earliest, model.Latest, s.responses)
+			require.NoError(t, err)
+			assert.Equal(t, model.Matrix{{
+				Metric: model.Metric{"__name__": "test_total"},
+				Values: tc.expectedSamples,
+			}}, res)
+		})
+	}
+}
+
 // Test_Ingester_OutOfOrder_CompactHead tests that the OOO head is compacted
 // when the compaction is forced or when the TSDB is idle.
 func Test_Ingester_OutOfOrder_CompactHead(t *testing.T) {
@@ -7465,6 +7782,10 @@ func TestNewIngestErrMsgs(t *testing.T) {
 			err: newIngestErrSampleOutOfOrder(timestamp, metricLabelAdapters),
 			msg: `the sample has been rejected because another sample with a more recent timestamp has already been ingested and out-of-order samples are not allowed (err-mimir-sample-out-of-order). The affected sample has timestamp 1970-01-19T05:30:43.969Z and is from series {__name__="test"}`,
 		},
+		"newIngestErrHistogramOutOfOrder": {
+			err: newIngestErrHistogramOutOfOrder(timestamp, metricLabelAdapters),
+			msg: `the native histogram sample has been rejected because another sample with a more recent timestamp has already been ingested and out-of-order native histogram samples are not allowed (err-mimir-histogram-out-of-order). The affected sample has timestamp 1970-01-19T05:30:43.969Z and is from series {__name__="test"}`,
+		},
 		"newIngestErrSampleDuplicateTimestamp": {
 			err: newIngestErrSampleDuplicateTimestamp(timestamp, metricLabelAdapters),
 			msg: `the sample has been rejected because another sample with the same timestamp, but a different value, has already been ingested (err-mimir-sample-duplicate-timestamp). The affected sample has timestamp 1970-01-19T05:30:43.969Z and is from series {__name__="test"}`,
@@ -7694,13 +8015,18 @@ func TestIngester_GetOpenTSDBsConcurrencyConfig(t *testing.T) {
 type fakeUtilizationBasedLimiter struct {
 	services.BasicService
 
-	limitingReason string
+	limitingReason      string
+	writeLimitingReason string
 }
 
 func (l *fakeUtilizationBasedLimiter) LimitingReason() string {
 	return l.limitingReason
 }
 
+func (l *fakeUtilizationBasedLimiter) WritePathLimitingReason() string {
+	return l.writeLimitingReason
+}
+
 func verifyUtilizationLimitedRequestsMetric(t *testing.T, reg *prometheus.Registry) {
 	t.Helper()
 
@@ -40,6 +40,10 @@ type Limiter struct {
 	ring                 RingCount
 	replicationFactor    int
 	zoneAwarenessEnabled bool
+
+	// Optional function returning the ratio of the tokens space replicated to this ingester for a tenant.
+	// When set and the ratio is known, it's used to compute the local series limit.
+	ownershipRatioFn func(userID string) (float64, bool)
 }
 
 // NewLimiter makes a new in-memory series limiter
@@ -160,6 +164,18 @@ func (l *Limiter) maxMetadataPerMetric(userID string) int {
 }
 
 func (l *Limiter) maxSeriesPerUser(userID string) int {
+	if l.ownershipRatioFn != nil {
+		if ratio, ok := l.ownershipRatioFn(userID); ok {
+			// Series are sharded across ingesters by token, so the ingester can hold up to
+			// the portion of the global limit matching the portion of tokens it owns.
+			if globalLimit := l.limits.MaxGlobalSeriesPerUser(userID); globalLimit > 0 {
+				if localLimit := int(float64(globalLimit) * ratio); localLimit > 0 {
+					return localLimit
+				}
+			}
+		}
+	}
+
 	return l.convertGlobalToLocalLimitOrUnlimited(userID, l.limits.MaxGlobalSeriesPerUser)
 }
 
@@ -468,6 +468,55 @@ func TestLimiter_AssertMaxSeriesPerUser(t *testing.T) {
 	}
 }
 
+func TestLimiter_maxSeriesPerUser_ShouldUseOwnershipRatioWhenAvailable(t *testing.T) {
+	tests := map[string]struct {
+		maxGlobalSeriesPerUser int
+		ratio                  float64
+		ratioAvailable         bool
+		expected               int
+	}{
+		"ratio is not available": {
+			maxGlobalSeriesPerUser: 1000,
+			ratioAvailable:         false,
+			expected:               300,
+		},
+		"ratio is available": {
+			maxGlobalSeriesPerUser: 1000,
+			ratio:                  0.4,
+			ratioAvailable:         true,
+			expected:               400,
+		},
+		"ratio is available but the limit is disabled": {
+			maxGlobalSeriesPerUser: 0,
+			ratio:                  0.4,
+			ratioAvailable:         true,
+			expected:               math.MaxInt32,
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			ring := &ringCountMock{}
+			ring.On("InstancesCount").Return(10)
+			ring.On("ZonesCount").Return(1)
+
+			limits, err := validation.NewOverrides(validation.Limits{
+				MaxGlobalSeriesPerUser: testData.maxGlobalSeriesPerUser,
+			}, nil)
+			require.NoError(t, err)
+
+			limiter := NewLimiter(limits, ring, 3, false)
+			limiter.ownershipRatioFn = func(string) (float64, bool) {
+				return testData.ratio, testData.ratioAvailable
+			}
+
+			assert.Equal(t, testData.expected, limiter.maxSeriesPerUser("test"))
+		})
+	}
+}
+
 func TestLimiter_AssertMaxMetricsWithMetadataPerUser(t *testing.T) {
 	tests := map[string]struct {
 		maxGlobalMetadataPerUser int
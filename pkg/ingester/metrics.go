@@ -71,7 +71,8 @@ type ingesterMetrics struct {
 	shutdownMarker prometheus.Gauge
 
 	// Count number of requests rejected due to utilization based limiting.
-	utilizationLimitedRequests *prometheus.CounterVec
+	utilizationLimitedRequests      *prometheus.CounterVec
+	utilizationLimitedWriteRequests *prometheus.CounterVec
 }
 
 func newIngesterMetrics(
@@ -176,6 +177,10 @@ func newIngesterMetrics(
 			Name: "cortex_ingester_utilization_limited_read_requests_total",
 			Help: "Total number of times read requests have been rejected due to utilization based limiting.",
 		}, []string{"reason"}),
+		utilizationLimitedWriteRequests: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_utilization_limited_write_requests_total",
+			Help: "Total number of times write requests have been rejected due to utilization based limiting.",
+		}, []string{"reason"}),
 
 		maxUsersGauge: promauto.With(r).NewGaugeFunc(prometheus.GaugeOpts{
 			Name:        instanceLimits,
@@ -366,6 +371,7 @@ func (m *ingesterMetrics) deletePerUserCustomTrackerMetrics(userID string, custo
 type discardedMetrics struct {
 	sampleOutOfBounds    *prometheus.CounterVec
 	sampleOutOfOrder     *prometheus.CounterVec
+	histogramOutOfOrder  *prometheus.CounterVec
 	sampleTooOld         *prometheus.CounterVec
 	newValueForTimestamp *prometheus.CounterVec
 	perUserSeriesLimit   *prometheus.CounterVec
@@ -376,6 +382,7 @@ func newDiscardedMetrics(r prometheus.Registerer) *discardedMetrics {
 	return &discardedMetrics{
 		sampleOutOfBounds:    validation.DiscardedSamplesCounter(r, sampleOutOfBounds),
 		sampleOutOfOrder:     validation.DiscardedSamplesCounter(r, sampleOutOfOrder),
+		histogramOutOfOrder:  validation.DiscardedSamplesCounter(r, histogramOutOfOrder),
 		sampleTooOld:         validation.DiscardedSamplesCounter(r, sampleTooOld),
 		newValueForTimestamp: validation.DiscardedSamplesCounter(r, newValueForTimestamp),
 		perUserSeriesLimit:   validation.DiscardedSamplesCounter(r, perUserSeriesLimit),
@@ -386,6 +393,7 @@ func newDiscardedMetrics(r prometheus.Registerer) *discardedMetrics {
 func (m *discardedMetrics) DeletePartialMatch(filter prometheus.Labels) {
 	m.sampleOutOfBounds.DeletePartialMatch(filter)
 	m.sampleOutOfOrder.DeletePartialMatch(filter)
+	m.histogramOutOfOrder.DeletePartialMatch(filter)
 	m.sampleTooOld.DeletePartialMatch(filter)
 	m.newValueForTimestamp.DeletePartialMatch(filter)
 	m.perUserSeriesLimit.DeletePartialMatch(filter)
@@ -395,6 +403,7 @@ func (m *discardedMetrics) DeletePartialMatch(filter prometheus.Labels) {
 func (m *discardedMetrics) DeleteLabelValues(userID string, group string) {
 	m.sampleOutOfBounds.DeleteLabelValues(userID, group)
 	m.sampleOutOfOrder.DeleteLabelValues(userID, group)
+	m.histogramOutOfOrder.DeleteLabelValues(userID, group)
 	m.sampleTooOld.DeleteLabelValues(userID, group)
 	m.newValueForTimestamp.DeleteLabelValues(userID, group)
 	m.perUserSeriesLimit.DeleteLabelValues(userID, group)
@@ -0,0 +1,432 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+)
+
+// tokenRanges is a sorted list of non-overlapping, inclusive token ranges, stored as
+// consecutive pairs of start and end tokens: [start0, end0, start1, end1, ...].
+type tokenRanges []uint32
+
+// includes returns whether the token is within one of the ranges.
+func (r tokenRanges) includes(token uint32) bool {
+	// Find the first range ending at or after the token.
+	i := sort.Search(len(r)/2, func(i int) bool {
+		return r[i*2+1] >= token
+	})
+	return i < len(r)/2 && r[i*2] <= token
+}
+
+// add appends the range [start, end] to the ranges, merging it with the last range if adjacent.
+// Ranges must be added in order.
+func (r tokenRanges) add(start, end uint32) tokenRanges {
+	if l := len(r); l > 0 && r[l-1] != math.MaxUint32 && r[l-1]+1 == start {
+		r[l-1] = end
+		return r
+	}
+	return append(r, start, end)
+}
+
+// forEachTokenRange calls f with each range [start, end] of the tokens space whose keys are all replicated to
+// the same instances, as computed by the distributor when sharding series across ingesters: the keys in the range
+// [tokens[i], tokens[i+1]) are replicated to the instances returned by the ring for the key tokens[i], because the
+// ring looks up the first token greater than the key. The range wrapping around the tokens space is split in two:
+// [0, first token - 1] is passed first, and [last token, math.MaxUint32] last. The input tokens are sorted and
+// deduplicated in place. The replication set passed to f is only valid until f returns.
+func forEachTokenRange(r ring.ReadRing, tokens []uint32, f func(set ring.ReplicationSet, start, end uint32)) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	slices.Sort(tokens)
+	tokens = slices.Compact(tokens)
+
+	bufDescs, bufHosts, bufZones := ring.MakeBuffersForGet()
+	last := len(tokens) - 1
+
+	if tokens[0] > 0 {
+		set, err := r.Get(tokens[last], ring.WriteNoExtend, bufDescs, bufHosts, bufZones)
+		if err != nil {
+			return err
+		}
+		f(set, 0, tokens[0]-1)
+	}
+
+	for i, start := range tokens {
+		set, err := r.Get(start, ring.WriteNoExtend, bufDescs, bufHosts, bufZones)
+		if err != nil {
+			return err
+		}
+
+		end := uint32(math.MaxUint32)
+		if i < last {
+			end = tokens[i+1] - 1
+		}
+		f(set, start, end)
+	}
+	return nil
+}
+
+// ownedTokenRanges returns the token ranges replicated to the instance with the given address
+// in the input ring, and the ratio of the whole tokens space they cover. Tokens are computed
+// the same way as the distributor does when sharding series across ingesters. Only the tokens
+// of healthy instances are considered: the ranges are recomputed once the ring health changes.
+func ownedTokenRanges(r ring.ReadRing, instanceAddr string) (tokenRanges, float64, error) {
+	rs, err := r.GetAllHealthy(ring.Reporting)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tokens []uint32
+	for _, instance := range rs.Instances {
+		tokens = append(tokens, instance.Tokens...)
+	}
+
+	var (
+		ranges      tokenRanges
+		ownedTokens uint64
+	)
+	err = forEachTokenRange(r, tokens, func(set ring.ReplicationSet, start, end uint32) {
+		if set.Includes(instanceAddr) {
+			ranges = ranges.add(start, end)
+			ownedTokens += uint64(end-start) + 1
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ranges, float64(ownedTokens) / float64(uint64(math.MaxUint32)+1), nil
+}
+
+// ownedSeriesState holds the series owned by the ingester for a tenant, based on the ingesters ring.
+type ownedSeriesState struct {
+	// Whether the state has been computed at least once.
+	computed bool
+	// The tenant shard size used to compute the state.
+	shardSize int
+	// The token ranges replicated to the ingester, and the ratio of the tokens space they cover.
+	ranges tokenRanges
+	ratio  float64
+	// The number of in-memory series owned by the ingester.
+	count int
+	// When the state has been computed.
+	computedAt time.Time
+}
+
+// ownedSeriesService periodically checks for changes in the ingesters ring and in the tenants shard size,
+// and recomputes the in-memory series owned by this ingester for the affected tenants.
+type ownedSeriesService struct {
+	services.Service
+
+	instanceAddr  string
+	ingestersRing ring.ReadRing
+	shardSize     func(userID string) int
+	getTSDBUsers  func() []string
+	getTSDB       func(userID string) *userTSDB
+	logger        log.Logger
+
+	// Fingerprint of the ingesters ring used for the last check. Only accessed by the service loop.
+	ringFingerprint uint64
+
+	recomputeDuration prometheus.Histogram
+}
+
+func newOwnedSeriesService(interval time.Duration, instanceAddr string, ingestersRing ring.ReadRing, shardSize func(string) int, getTSDBUsers func() []string, getTSDB func(string) *userTSDB, logger log.Logger, reg prometheus.Registerer) *ownedSeriesService {
+	s := &ownedSeriesService{
+		instanceAddr:  instanceAddr,
+		ingestersRing: ingestersRing,
+		shardSize:     shardSize,
+		getTSDBUsers:  getTSDBUsers,
+		getTSDB:       getTSDB,
+		logger:        logger,
+		recomputeDuration: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "cortex_ingester_owned_series_recompute_duration_seconds",
+			Help:    "Time taken to recompute the in-memory series owned by the ingester for a tenant.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+
+	s.Service = services.NewTimerService(interval, nil, s.iteration, nil)
+	return s
+}
+
+func (s *ownedSeriesService) iteration(ctx context.Context) error {
+	s.updateAllTenants(ctx)
+	return nil
+}
+
+// updateAllTenants recomputes the owned series of the tenants for which the ring or the shard size
+// changed since the last computation, or for which owned series have never been computed.
+func (s *ownedSeriesService) updateAllTenants(ctx context.Context) {
+	fingerprint, err := s.currentRingFingerprint()
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "failed to check the ingesters ring for owned series computation", "err", err)
+		return
+	}
+
+	ringChanged := fingerprint != s.ringFingerprint
+	s.ringFingerprint = fingerprint
+
+	for _, userID := range s.getTSDBUsers() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		db := s.getTSDB(userID)
+		if db == nil {
+			continue
+		}
+
+		shardSize := s.shardSize(userID)
+		if state := db.getOwnedSeriesState(); state.computed && !ringChanged && state.shardSize == shardSize {
+			continue
+		}
+
+		if err := s.updateTenant(userID, db, shardSize); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to recompute owned series", "user", userID, "err", err)
+
+			// Reset the fingerprint so that the computation is retried at the next iteration.
+			s.ringFingerprint = 0
+		}
+	}
+}
+
+func (s *ownedSeriesService) updateTenant(userID string, db *userTSDB, shardSize int) error {
+	start := time.Now()
+
+	subring := s.ingestersRing
+	if shardSize > 0 {
+		subring = s.ingestersRing.ShuffleShard(userID, shardSize)
+	}
+
+	ranges, ratio, err := ownedTokenRanges(subring, s.instanceAddr)
+	if err != nil {
+		return err
+	}
+
+	if err := db.recomputeOwnedSeries(ranges, ratio, shardSize); err != nil {
+		return err
+	}
+
+	s.recomputeDuration.Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// currentRingFingerprint returns a hash of the instances and tokens in the ingesters ring.
+func (s *ownedSeriesService) currentRingFingerprint() (uint64, error) {
+	rs, err := s.ingestersRing.GetAllHealthy(ring.Reporting)
+	if err != nil {
+		return 0, err
+	}
+
+	instances := rs.Instances
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Addr < instances[j].Addr })
+
+	h := fnv.New64a()
+	for _, instance := range instances {
+		_, _ = h.Write([]byte(instance.Addr))
+		_, _ = h.Write([]byte(instance.Zone))
+		for _, token := range instance.Tokens {
+			_, _ = h.Write([]byte(strconv.FormatUint(uint64(token), 10)))
+		}
+	}
+	return h.Sum64(), nil
+}
+
+// getOwnedSeriesState returns a copy of the current owned series state.
+func (u *userTSDB) getOwnedSeriesState() ownedSeriesState {
+	u.ownedSeriesMtx.Lock()
+	defer u.ownedSeriesMtx.Unlock()
+
+	return u.ownedSeries
+}
+
+// recomputeOwnedSeries counts the in-memory series whose token is within the input ranges. The series are
+// counted from a snapshot of the series taken when the recomputation starts, without blocking the creation
+// or deletion of series: the series created and deleted while counting are tracked separately, and applied
+// as deltas to the count once the recomputation completes. A deleted series is only subtracted from the count
+// if it had been counted, so the hashes of the counted series are kept until the recomputation completes.
+func (u *userTSDB) recomputeOwnedSeries(ranges tokenRanges, ratio float64, shardSize int) error {
+	if u.db == nil {
+		return errors.New("TSDB is not open")
+	}
+
+	idx, err := u.Head().Index()
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+
+	u.ownedSeriesRecomputeMtx.Lock()
+	defer u.ownedSeriesRecomputeMtx.Unlock()
+
+	recompute := &ownedSeriesRecompute{ranges: ranges}
+
+	u.ownedSeriesMtx.Lock()
+	postings, err := idx.Postings(index.AllPostingsKey())
+	if err == nil {
+		u.ownedSeriesRecompute = recompute
+	}
+	u.ownedSeriesMtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Stop tracking the series created and deleted while counting, in case of error.
+	defer func() {
+		u.ownedSeriesMtx.Lock()
+		u.ownedSeriesRecompute = nil
+		u.ownedSeriesMtx.Unlock()
+	}()
+
+	var counted []uint64
+	builder := labels.ScratchBuilder{}
+	for postings.Next() {
+		if err := idx.Series(postings.At(), &builder, nil); err != nil {
+			// The series may have been garbage collected in the meanwhile.
+			continue
+		}
+		if metric := builder.Labels(); ranges.includes(client.ShardByAllLabels(u.userID, metric)) {
+			counted = append(counted, metric.Hash())
+		}
+	}
+	if err := postings.Err(); err != nil {
+		return err
+	}
+	slices.Sort(counted)
+
+	u.ownedSeriesMtx.Lock()
+	defer u.ownedSeriesMtx.Unlock()
+
+	u.ownedSeries = ownedSeriesState{
+		computed:   true,
+		shardSize:  shardSize,
+		ranges:     ranges,
+		ratio:      ratio,
+		count:      recompute.count(counted),
+		computedAt: time.Now(),
+	}
+	return nil
+}
+
+// ownedSeriesRecompute tracks the owned series created and deleted while recomputing the owned series.
+type ownedSeriesRecompute struct {
+	// The token ranges being recomputed.
+	ranges tokenRanges
+	// The number of series within the ranges created while recomputing, by labels hash.
+	created map[uint64]int
+	// The number of series within the ranges deleted while recomputing, by labels hash.
+	deleted map[uint64]int
+}
+
+// update tracks the creation (positive delta) or the deletion (negative delta) of the input series,
+// if they're within the ranges being recomputed.
+func (r *ownedSeriesRecompute) update(userID string, delta int, metrics ...labels.Labels) {
+	for _, metric := range metrics {
+		if !r.ranges.includes(client.ShardByAllLabels(userID, metric)) {
+			continue
+		}
+
+		if delta > 0 {
+			if r.created == nil {
+				r.created = map[uint64]int{}
+			}
+			r.created[metric.Hash()] += delta
+		} else {
+			if r.deleted == nil {
+				r.deleted = map[uint64]int{}
+			}
+			r.deleted[metric.Hash()] -= delta
+		}
+	}
+}
+
+// count returns the number of owned series, given the sorted hashes of the series counted from the
+// snapshot. Series deleted while recomputing are only subtracted if they had been counted from the
+// snapshot or created while recomputing, since the same series may have been deleted before or after
+// being counted.
+func (r *ownedSeriesRecompute) count(counted []uint64) int {
+	count := len(counted)
+	for _, created := range r.created {
+		count += created
+	}
+
+	for hash, deleted := range r.deleted {
+		existing := r.created[hash]
+		if _, ok := slices.BinarySearch(counted, hash); ok {
+			existing++
+		}
+		if deleted > existing {
+			deleted = existing
+		}
+		count -= deleted
+	}
+	return count
+}
+
+// updateOwnedSeriesCount updates the owned series count for the created or deleted series.
+func (u *userTSDB) updateOwnedSeriesCount(delta int, metrics ...labels.Labels) {
+	if !u.ownedSeriesTracked {
+		return
+	}
+
+	u.ownedSeriesMtx.Lock()
+	defer u.ownedSeriesMtx.Unlock()
+
+	if recompute := u.ownedSeriesRecompute; recompute != nil {
+		recompute.update(u.userID, delta, metrics...)
+	}
+
+	if !u.ownedSeries.computed {
+		return
+	}
+
+	for _, metric := range metrics {
+		if u.ownedSeries.ranges.includes(client.ShardByAllLabels(u.userID, metric)) {
+			u.ownedSeries.count += delta
+		}
+	}
+	if u.ownedSeries.count < 0 {
+		u.ownedSeries.count = 0
+	}
+}
+
+// seriesCountForLimits returns the number of series to check against the per-tenant series limit.
+func (u *userTSDB) seriesCountForLimits() int {
+	if u.useOwnedSeriesForLimits {
+		if state := u.getOwnedSeriesState(); state.computed {
+			return state.count
+		}
+	}
+	return int(u.Head().NumSeries())
+}
+
+// ownershipRatio returns the ratio of the tokens space replicated to the ingester for the tenant,
+// and false if it's unknown.
+func (u *userTSDB) ownershipRatio() (float64, bool) {
+	state := u.getOwnedSeriesState()
+	if !state.computed || state.ratio <= 0 {
+		return 0, false
+	}
+	return state.ratio, true
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	_ "embed" // Used to embed html template
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+//go:embed owned_series.gohtml
+var ownedSeriesPageHTML string
+var ownedSeriesTemplate = template.Must(template.New("webpage").Parse(ownedSeriesPageHTML))
+
+type ownedSeriesPageContents struct {
+	Now     time.Time                `json:"now"`
+	Tenants []ownedSeriesTenantStats `json:"tenants"`
+}
+
+type ownedSeriesTenantStats struct {
+	Tenant           string    `json:"tenant"`
+	InMemorySeries   uint64    `json:"in_memory_series"`
+	Computed         bool      `json:"computed"`
+	OwnedSeries      int       `json:"owned_series"`
+	OwnershipRatio   float64   `json:"ownership_ratio"`
+	ShardSize        int       `json:"shard_size"`
+	LocalSeriesLimit int       `json:"local_series_limit"`
+	LastUpdate       time.Time `json:"last_update"`
+}
+
+// OwnedSeriesHandler shows the in-memory and owned series of each tenant in the ingester.
+func (i *Ingester) OwnedSeriesHandler(w http.ResponseWriter, req *http.Request) {
+	if !i.cfg.TrackIngesterOwnedSeries {
+		util.WriteTextResponse(w, "Owned series tracking is disabled.")
+		return
+	}
+
+	userIDs := i.getTSDBUsers()
+	sort.Strings(userIDs)
+
+	tenants := make([]ownedSeriesTenantStats, 0, len(userIDs))
+	for _, userID := range userIDs {
+		db := i.getTSDB(userID)
+		if db == nil || db.db == nil {
+			continue
+		}
+
+		state := db.getOwnedSeriesState()
+		localLimit := 0
+		if i.limits.MaxGlobalSeriesPerUser(userID) > 0 {
+			localLimit = i.limiter.maxSeriesPerUser(userID)
+		}
+
+		tenants = append(tenants, ownedSeriesTenantStats{
+			Tenant:           userID,
+			InMemorySeries:   db.Head().NumSeries(),
+			Computed:         state.computed,
+			OwnedSeries:      state.count,
+			OwnershipRatio:   state.ratio,
+			ShardSize:        state.shardSize,
+			LocalSeriesLimit: localLimit,
+			LastUpdate:       state.computedAt,
+		})
+	}
+
+	util.RenderHTTPResponse(w, ownedSeriesPageContents{
+		Now:     time.Now(),
+		Tenants: tenants,
+	}, ownedSeriesTemplate, req)
+}
+
+// getOwnershipRatio returns the ratio of the tokens space replicated to the ingester for the tenant,
+// and false if it's unknown.
+func (i *Ingester) getOwnershipRatio(userID string) (float64, bool) {
+	db := i.getTSDB(userID)
+	if db == nil {
+		return 0, false
+	}
+	return db.ownershipRatio()
+}
@@ -0,0 +1,360 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/util/push"
+)
+
+func TestTokenRanges_includes(t *testing.T) {
+	ranges := tokenRanges{0, 99, 500, 999, 2000, math.MaxUint32}
+
+	for token, expected := range map[uint32]bool{
+		0:              true,
+		99:             true,
+		100:            false,
+		499:            false,
+		500:            true,
+		999:            true,
+		1000:           false,
+		1999:           false,
+		2000:           true,
+		math.MaxUint32: true,
+	} {
+		assert.Equal(t, expected, ranges.includes(token), "token: %d", token)
+	}
+
+	assert.False(t, tokenRanges(nil).includes(0))
+}
+
+func TestOwnedSeriesRecompute_count(t *testing.T) {
+	const userID = "user"
+
+	series := func(i int) labels.Labels {
+		return labels.FromStrings(labels.MetricName, "series", "i", fmt.Sprint(i))
+	}
+	hashes := func(ids ...int) []uint64 {
+		var result []uint64
+		for _, i := range ids {
+			result = append(result, series(i).Hash())
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	// All the tokens are within the ranges.
+	newRecompute := func() *ownedSeriesRecompute {
+		return &ownedSeriesRecompute{ranges: tokenRanges{0, math.MaxUint32}}
+	}
+
+	t.Run("should count the series in the snapshot", func(t *testing.T) {
+		assert.Equal(t, 3, newRecompute().count(hashes(1, 2, 3)))
+	})
+
+	t.Run("should add the series created while recomputing", func(t *testing.T) {
+		r := newRecompute()
+		r.update(userID, 1, series(4), series(5))
+		assert.Equal(t, 5, r.count(hashes(1, 2, 3)))
+	})
+
+	t.Run("should subtract the counted series deleted while recomputing", func(t *testing.T) {
+		r := newRecompute()
+		r.update(userID, -1, series(2))
+		assert.Equal(t, 2, r.count(hashes(1, 2, 3)))
+	})
+
+	t.Run("should not subtract the series deleted before being counted", func(t *testing.T) {
+		r := newRecompute()
+		r.update(userID, -1, series(2))
+		assert.Equal(t, 2, r.count(hashes(1, 3)))
+	})
+
+	t.Run("should subtract the series created and deleted while recomputing", func(t *testing.T) {
+		r := newRecompute()
+		r.update(userID, 1, series(4))
+		r.update(userID, -1, series(4))
+		assert.Equal(t, 3, r.count(hashes(1, 2, 3)))
+	})
+
+	t.Run("should subtract a counted series deleted and created again while recomputing only once", func(t *testing.T) {
+		r := newRecompute()
+		r.update(userID, -1, series(2))
+		r.update(userID, 1, series(2))
+		assert.Equal(t, 3, r.count(hashes(1, 2, 3)))
+
+		r.update(userID, -1, series(2))
+		assert.Equal(t, 2, r.count(hashes(1, 2, 3)))
+	})
+
+	t.Run("should ignore the series outside the ranges", func(t *testing.T) {
+		r := &ownedSeriesRecompute{}
+		r.update(userID, 1, series(4))
+		r.update(userID, -1, series(1))
+		assert.Equal(t, 3, r.count(hashes(1, 2, 3)))
+	})
+}
+
+func TestOwnedTokenRanges(t *testing.T) {
+	desc := ring.NewDesc()
+	desc.AddIngester("ingester-a", "ingester-a", "", []uint32{100, 1000}, ring.ACTIVE, time.Now())
+	desc.AddIngester("ingester-b", "ingester-b", "", []uint32{500}, ring.ACTIVE, time.Now())
+	desc.AddIngester("ingester-c", "ingester-c", "", []uint32{2000}, ring.ACTIVE, time.Now())
+
+	tests := map[string]struct {
+		replicationFactor int
+		instanceAddr      string
+		expectedRanges    tokenRanges
+	}{
+		"replication factor 1, instance owning the wrap around range": {
+			replicationFactor: 1,
+			instanceAddr:      "ingester-a",
+			expectedRanges:    tokenRanges{0, 99, 500, 999, 2000, math.MaxUint32},
+		},
+		"replication factor 1, instance owning a single range": {
+			replicationFactor: 1,
+			instanceAddr:      "ingester-b",
+			expectedRanges:    tokenRanges{100, 499},
+		},
+		"replication factor 2, ranges adjacent to the wrap around range are merged": {
+			replicationFactor: 2,
+			instanceAddr:      "ingester-b",
+			expectedRanges:    tokenRanges{0, 499, 2000, math.MaxUint32},
+		},
+		"replication factor 3, instance owning all the tokens": {
+			replicationFactor: 3,
+			instanceAddr:      "ingester-c",
+			expectedRanges:    tokenRanges{0, math.MaxUint32},
+		},
+		"instance not in the ring": {
+			replicationFactor: 1,
+			instanceAddr:      "ingester-d",
+			expectedRanges:    nil,
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			r := newOwnedSeriesTestRing(t, testData.replicationFactor, false, desc)
+
+			ranges, ratio, err := ownedTokenRanges(r, testData.instanceAddr)
+			require.NoError(t, err)
+			assert.Equal(t, testData.expectedRanges, ranges)
+
+			// The ratio must match the size of the returned ranges.
+			owned := uint64(0)
+			for i := 0; i < len(ranges); i += 2 {
+				owned += uint64(ranges[i+1]-ranges[i]) + 1
+			}
+			assert.InDelta(t, float64(owned)/float64(uint64(math.MaxUint32)+1), ratio, 1e-9)
+
+			// Each owned token must be replicated to the instance, according to the ring.
+			for _, token := range []uint32{0, 99, 100, 499, 500, 999, 1000, 1999, 2000, math.MaxUint32} {
+				set, err := r.Get(token, ring.WriteNoExtend, nil, nil, nil)
+				require.NoError(t, err)
+				assert.Equal(t, set.Includes(testData.instanceAddr), ranges.includes(token), "token: %d", token)
+			}
+		})
+	}
+}
+
+func TestOwnedTokenRanges_DuplicateTokens(t *testing.T) {
+	desc := ring.NewDesc()
+	desc.AddIngester("ingester-a", "ingester-a", "", []uint32{0, 1000}, ring.ACTIVE, time.Now())
+	desc.AddIngester("ingester-b", "ingester-b", "", []uint32{1000, 2000}, ring.ACTIVE, time.Now())
+	r := newOwnedSeriesTestRing(t, 1, false, desc)
+
+	total := 0.0
+	for _, instanceAddr := range []string{"ingester-a", "ingester-b"} {
+		ranges, ratio, err := ownedTokenRanges(r, instanceAddr)
+		require.NoError(t, err)
+		total += ratio
+
+		for i := 0; i < len(ranges); i += 2 {
+			assert.LessOrEqual(t, ranges[i], ranges[i+1], "instance: %s, ranges: %v", instanceAddr, ranges)
+		}
+
+		// Each owned token must be replicated to the instance, according to the ring.
+		for _, token := range []uint32{0, 1, 999, 1000, 1001, 1999, 2000, math.MaxUint32} {
+			set, err := r.Get(token, ring.WriteNoExtend, nil, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, set.Includes(instanceAddr), ranges.includes(token), "instance: %s, token: %d", instanceAddr, token)
+		}
+	}
+
+	// Each token is owned by exactly one instance.
+	assert.InDelta(t, 1, total, 1e-9)
+}
+
+func TestIngester_OwnedSeries(t *testing.T) {
+	const maxGlobalSeriesPerUser = 10000
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.IngesterRing.ReplicationFactor = 1
+	cfg.TrackIngesterOwnedSeries = true
+	cfg.UseIngesterOwnedSeriesForLimits = true
+	cfg.OwnedSeriesUpdateInterval = time.Hour // Owned series are recomputed manually in this test.
+
+	limits := defaultLimitsTestConfig()
+	limits.MaxGlobalSeriesPerUser = maxGlobalSeriesPerUser
+
+	i, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), i))
+	})
+
+	test.Poll(t, time.Second, 1, func() interface{} {
+		return i.ownedSeriesRing.InstancesCount()
+	})
+
+	// Add another ingester to the ring, so that this ingester owns only a portion of the series.
+	require.NoError(t, cfg.IngesterRing.KVStore.Mock.CAS(context.Background(), IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := in.(*ring.Desc)
+		desc.AddIngester("ingester-2", "ingester-2:9095", "", []uint32{math.MaxUint32 / 2}, ring.ACTIVE, time.Now())
+		return desc, true, nil
+	}))
+	test.Poll(t, time.Second, 2, func() interface{} {
+		return i.ownedSeriesRing.InstancesCount()
+	})
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	pushSeries := func(from, to int) {
+		for s := from; s < to; s++ {
+			req, _, _, _ := mockWriteRequest(t, labels.FromStrings(labels.MetricName, "test", "series", fmt.Sprint(s)), 1, 100)
+			_, err := i.PushWithCleanup(ctx, push.NewParsedRequest(req))
+			require.NoError(t, err)
+		}
+	}
+
+	// Computes the expected owned series based on how the distributor shards series.
+	expectedOwnedSeries := func(numSeries int) int {
+		expected := 0
+		for s := 0; s < numSeries; s++ {
+			token := client.ShardByAllLabels(userID, labels.FromStrings(labels.MetricName, "test", "series", fmt.Sprint(s)))
+			set, err := i.ownedSeriesRing.Get(token, ring.WriteNoExtend, nil, nil, nil)
+			require.NoError(t, err)
+			if set.Includes(i.lifecycler.Addr) {
+				expected++
+			}
+		}
+		return expected
+	}
+
+	pushSeries(0, 100)
+
+	db := i.getTSDB(userID)
+	require.NotNil(t, db)
+	assert.False(t, db.getOwnedSeriesState().computed)
+	_, ok := i.getOwnershipRatio(userID)
+	assert.False(t, ok)
+
+	i.ownedSeriesService.updateAllTenants(context.Background())
+
+	state := db.getOwnedSeriesState()
+	require.True(t, state.computed)
+	assert.Equal(t, expectedOwnedSeries(100), state.count)
+	assert.Equal(t, state.count, db.seriesCountForLimits())
+
+	ratio, ok := i.getOwnershipRatio(userID)
+	require.True(t, ok)
+	assert.Equal(t, int(maxGlobalSeriesPerUser*ratio), i.limiter.maxSeriesPerUser(userID))
+
+	// New series are tracked once created.
+	pushSeries(100, 200)
+	assert.Equal(t, expectedOwnedSeries(200), db.getOwnedSeriesState().count)
+
+	// When the ring changes, owned series are recomputed.
+	require.NoError(t, cfg.IngesterRing.KVStore.Mock.CAS(context.Background(), IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := in.(*ring.Desc)
+		delete(desc.Ingesters, "ingester-2")
+		return desc, true, nil
+	}))
+	test.Poll(t, time.Second, 1, func() interface{} {
+		return i.ownedSeriesRing.InstancesCount()
+	})
+
+	i.ownedSeriesService.updateAllTenants(context.Background())
+
+	state = db.getOwnedSeriesState()
+	assert.Equal(t, 200, state.count)
+	assert.Equal(t, 1.0, state.ratio)
+
+	// The admin page shows the owned series.
+	resp := httptest.NewRecorder()
+	i.OwnedSeriesHandler(resp, httptest.NewRequest(http.MethodGet, "/ingester/owned_series", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "<td>"+userID+"</td>")
+	assert.Contains(t, resp.Body.String(), "<td>200</td>")
+}
+
+func TestIngester_OwnedSeriesHandler_ShouldReturnMessageWhenTrackingIsDisabled(t *testing.T) {
+	i, err := prepareIngesterWithBlocksStorage(t, defaultIngesterTestConfig(t), nil)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	i.OwnedSeriesHandler(resp, httptest.NewRequest(http.MethodGet, "/ingester/owned_series", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Owned series tracking is disabled.")
+}
+
+func newOwnedSeriesTestRing(t *testing.T, replicationFactor int, zoneAwarenessEnabled bool, desc *ring.Desc) *ring.Ring {
+	kvStore, closer := consul.NewInMemoryClient(ring.GetCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { assert.NoError(t, closer.Close()) })
+
+	require.NoError(t, kvStore.CAS(context.Background(), IngesterRingKey, func(interface{}) (interface{}, bool, error) {
+		return desc, true, nil
+	}))
+
+	cfg := ring.Config{
+		KVStore:              kv.Config{Mock: kvStore},
+		HeartbeatTimeout:     time.Minute,
+		ReplicationFactor:    replicationFactor,
+		ZoneAwarenessEnabled: zoneAwarenessEnabled,
+	}
+	r, err := ring.NewWithStoreClientAndStrategy(cfg, "ingester", IngesterRingKey, kvStore, ring.NewDefaultReplicationStrategy(), nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), r))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), r))
+	})
+
+	return r
+}
+
+func TestConfig_ValidateOwnedSeries(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	require.NoError(t, cfg.Validate())
+
+	cfg.UseIngesterOwnedSeriesForLimits = true
+	require.ErrorIs(t, cfg.Validate(), errInvalidOwnedSeriesLimitsConfig)
+
+	cfg.TrackIngesterOwnedSeries = true
+	require.NoError(t, cfg.Validate())
+
+	cfg.OwnedSeriesUpdateInterval = 0
+	require.ErrorIs(t, cfg.Validate(), errInvalidOwnedSeriesUpdateInterval)
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	_ "embed" // Used to embed html template
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/dskit/ring"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+//go:embed ring_ownership.gohtml
+var ringOwnershipPageHTML string
+var ringOwnershipTemplate = template.Must(template.New("webpage").Parse(ringOwnershipPageHTML))
+
+type ringOwnershipPageContents struct {
+	Now       time.Time               `json:"now"`
+	Zones     []ringOwnershipZone     `json:"zones"`
+	Instances []ringOwnershipInstance `json:"instances"`
+}
+
+type ringOwnershipZone struct {
+	Zone      string `json:"zone"`
+	Instances int    `json:"instances"`
+	// Ownership percentages of the instances in the zone.
+	MinOwnership      float64 `json:"min_ownership"`
+	MaxOwnership      float64 `json:"max_ownership"`
+	ExpectedOwnership float64 `json:"expected_ownership"`
+	// Difference between the max and min ownership, relative to the expected one, in percentage.
+	Spread float64 `json:"spread"`
+}
+
+type ringOwnershipInstance struct {
+	Addr   string `json:"addr"`
+	Zone   string `json:"zone"`
+	State  string `json:"state"`
+	Tokens int    `json:"tokens"`
+	// Percentage of the tokens space replicated to the instance.
+	Ownership float64 `json:"ownership"`
+	// Deviation of the ownership from the expected one for the zone, in percentage.
+	Deviation float64 `json:"deviation"`
+}
+
+type ringOwnershipHandler struct {
+	ring ring.ReadRing
+}
+
+// NewRingOwnershipHandler returns an HTTP handler showing the portion of the tokens space replicated to each
+// ingester in the ring, and how evenly it's spread across the ingesters of each zone. It can be used to validate
+// the token generation strategy.
+func NewRingOwnershipHandler(r ring.ReadRing) http.Handler {
+	return &ringOwnershipHandler{ring: r}
+}
+
+func (h *ringOwnershipHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rs, err := h.ring.GetAllHealthy(ring.Reporting)
+	if err != nil {
+		util.WriteTextResponse(w, fmt.Sprintf("Can't read the ingesters ring: %s", err))
+		return
+	}
+
+	ownership, err := instancesOwnership(h.ring, rs.Instances)
+	if err != nil {
+		util.WriteTextResponse(w, fmt.Sprintf("Can't compute ownership: %s", err))
+		return
+	}
+
+	util.RenderHTTPResponse(w, buildRingOwnershipPageContents(rs.Instances, ownership), ringOwnershipTemplate, req)
+}
+
+// instancesOwnership returns the ratio of the tokens space replicated to each of the input instances, by address.
+// Ratios are computed the same way as the distributor does when sharding series across ingesters, so the sum
+// of the ratios of all instances is equal to the replication factor.
+func instancesOwnership(r ring.ReadRing, instances []ring.InstanceDesc) (map[string]float64, error) {
+	var tokens []uint32
+	for _, instance := range instances {
+		tokens = append(tokens, instance.Tokens...)
+	}
+
+	owned := make(map[string]uint64, len(instances))
+	err := forEachTokenRange(r, tokens, func(set ring.ReplicationSet, start, end uint32) {
+		for _, instance := range set.Instances {
+			owned[instance.Addr] += uint64(end-start) + 1
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ratios := make(map[string]float64, len(instances))
+	for _, instance := range instances {
+		ratios[instance.Addr] = float64(owned[instance.Addr]) / float64(uint64(math.MaxUint32)+1)
+	}
+	return ratios, nil
+}
+
+func buildRingOwnershipPageContents(instances []ring.InstanceDesc, ownership map[string]float64) ringOwnershipPageContents {
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Zone != instances[j].Zone {
+			return instances[i].Zone < instances[j].Zone
+		}
+		return instances[i].Addr < instances[j].Addr
+	})
+
+	// Group the ownership by zone. The expected ownership of each instance is the average in its zone:
+	// with zone-aware replication each zone owns the whole tokens space, otherwise the whole ring owns
+	// it replication factor times.
+	byZone := map[string][]float64{}
+	var zoneNames []string
+	for _, instance := range instances {
+		if _, ok := byZone[instance.Zone]; !ok {
+			zoneNames = append(zoneNames, instance.Zone)
+		}
+		byZone[instance.Zone] = append(byZone[instance.Zone], ownership[instance.Addr]*100)
+	}
+
+	contents := ringOwnershipPageContents{Now: time.Now()}
+	expected := map[string]float64{}
+	for _, zone := range zoneNames {
+		values := byZone[zone]
+
+		z := ringOwnershipZone{Zone: zone, Instances: len(values), MinOwnership: math.MaxFloat64}
+		for _, v := range values {
+			z.MinOwnership = math.Min(z.MinOwnership, v)
+			z.MaxOwnership = math.Max(z.MaxOwnership, v)
+			z.ExpectedOwnership += v
+		}
+		z.ExpectedOwnership /= float64(len(values))
+		if z.ExpectedOwnership > 0 {
+			z.Spread = (z.MaxOwnership - z.MinOwnership) / z.ExpectedOwnership * 100
+		}
+
+		expected[zone] = z.ExpectedOwnership
+		contents.Zones = append(contents.Zones, z)
+	}
+
+	for _, instance := range instances {
+		i := ringOwnershipInstance{
+			Addr:      instance.Addr,
+			Zone:      instance.Zone,
+			State:     instance.State.String(),
+			Tokens:    len(instance.Tokens),
+			Ownership: ownership[instance.Addr] * 100,
+		}
+		if e := expected[instance.Zone]; e > 0 {
+			i.Deviation = (i.Ownership/e - 1) * 100
+		}
+		contents.Instances = append(contents.Instances, i)
+	}
+
+	return contents
+}
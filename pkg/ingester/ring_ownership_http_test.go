@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesOwnership(t *testing.T) {
+	desc := ring.NewDesc()
+	desc.AddIngester("ingester-a", "ingester-a", "", []uint32{100, 1000}, ring.ACTIVE, time.Now())
+	desc.AddIngester("ingester-b", "ingester-b", "", []uint32{500}, ring.ACTIVE, time.Now())
+	desc.AddIngester("ingester-c", "ingester-c", "", []uint32{2000}, ring.ACTIVE, time.Now())
+
+	const tokensSpace = float64(uint64(math.MaxUint32) + 1)
+
+	tests := map[string]struct {
+		replicationFactor int
+		expected          map[string]float64
+	}{
+		"replication factor 1": {
+			replicationFactor: 1,
+			expected: map[string]float64{
+				"ingester-a": (tokensSpace - 1000 - 400) / tokensSpace,
+				"ingester-b": 400 / tokensSpace,
+				"ingester-c": 1000 / tokensSpace,
+			},
+		},
+		"replication factor 3": {
+			replicationFactor: 3,
+			expected: map[string]float64{
+				"ingester-a": 1,
+				"ingester-b": 1,
+				"ingester-c": 1,
+			},
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			r := newOwnedSeriesTestRing(t, testData.replicationFactor, false, desc)
+
+			rs, err := r.GetAllHealthy(ring.Reporting)
+			require.NoError(t, err)
+
+			actual, err := instancesOwnership(r, rs.Instances)
+			require.NoError(t, err)
+			require.Len(t, actual, len(testData.expected))
+
+			for addr, expected := range testData.expected {
+				assert.InDelta(t, expected, actual[addr], 1e-12, addr)
+
+				// The ownership must match the one used for owned series.
+				_, ratio, err := ownedTokenRanges(r, addr)
+				require.NoError(t, err)
+				assert.InDelta(t, ratio, actual[addr], 1e-12, addr)
+			}
+		})
+	}
+}
+
+func TestRingOwnershipHandler_ShouldShowEvenOwnershipWithSpreadMinimizingTokens(t *testing.T) {
+	const (
+		numTokens        = 512
+		instancesPerZone = 5
+	)
+
+	zones := []string{"zone-a", "zone-b", "zone-c"}
+	desc := ring.NewDesc()
+	for _, zone := range zones {
+		for i := 0; i < instancesPerZone; i++ {
+			id := fmt.Sprintf("ingester-%s-%d", zone, i)
+			gen, err := ring.NewSpreadMinimizingTokenGenerator(id, zone, zones, false, nil)
+			require.NoError(t, err)
+			desc.AddIngester(id, id, zone, gen.GenerateTokens(numTokens, nil), ring.ACTIVE, time.Now())
+		}
+	}
+
+	r := newOwnedSeriesTestRing(t, 3, true, desc)
+
+	req := httptest.NewRequest(http.MethodGet, "/ingester/ring/ownership", nil)
+	req.Header.Set("Accept", "application/json")
+	resp := httptest.NewRecorder()
+	NewRingOwnershipHandler(r).ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var contents ringOwnershipPageContents
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &contents))
+
+	require.Len(t, contents.Zones, len(zones))
+	for ix, zone := range contents.Zones {
+		assert.Equal(t, zones[ix], zone.Zone)
+		assert.Equal(t, instancesPerZone, zone.Instances)
+		// With zone-aware replication each zone owns the whole tokens space.
+		assert.InDelta(t, 100.0/instancesPerZone, zone.ExpectedOwnership, 1e-6)
+		assert.Less(t, zone.Spread, 1.0)
+	}
+
+	require.Len(t, contents.Instances, len(zones)*instancesPerZone)
+	for _, instance := range contents.Instances {
+		assert.Equal(t, numTokens, instance.Tokens)
+		assert.Equal(t, ring.ACTIVE.String(), instance.State)
+		assert.Less(t, math.Abs(instance.Deviation), 1.0, instance.Addr)
+	}
+
+	// The HTML page is rendered too.
+	resp = httptest.NewRecorder()
+	NewRingOwnershipHandler(r).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ingester/ring/ownership", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "ingester-zone-a-0")
+}
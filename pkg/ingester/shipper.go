@@ -69,6 +69,10 @@ type shipper struct {
 	metrics     *shipperMetrics
 	bucket      objstore.Bucket
 	source      block.SourceType
+
+	// prepareUpload, if not nil, is called before uploading each block, and
+	// can add files to the block directory to be uploaded along with the block.
+	prepareUpload func(blockDir string, meta *block.Meta) error
 }
 
 // newShipper creates a new uploader that detects new TSDB blocks in dir and uploads them to
@@ -82,6 +86,7 @@ func newShipper(
 	dir string,
 	bucket objstore.Bucket,
 	source block.SourceType,
+	prepareUpload func(blockDir string, meta *block.Meta) error,
 ) *shipper {
 	if logger == nil {
 		logger = log.NewNopLogger()
@@ -95,6 +100,8 @@ func newShipper(
 		bucket:      bucket,
 		metrics:     metrics,
 		source:      source,
+
+		prepareUpload: prepareUpload,
 	}
 }
 
@@ -197,6 +204,12 @@ func (s *shipper) upload(ctx context.Context, meta *block.Meta) error {
 		meta.Thanos.Labels[mimir_tsdb.OutOfOrderExternalLabel] = mimir_tsdb.OutOfOrderExternalLabelValue
 	}
 
+	if s.prepareUpload != nil {
+		if err := s.prepareUpload(blockDir, meta); err != nil {
+			return errors.Wrap(err, "prepare block upload")
+		}
+	}
+
 	// Upload block with custom metadata.
 	return block.Upload(ctx, s.logger, s.bucket, blockDir, meta)
 }
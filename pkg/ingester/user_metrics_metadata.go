@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
 )
 
 // userMetricsMetadata allows metric metadata of a tenant to be held by the ingester.
@@ -99,6 +100,26 @@ func (mm *userMetricsMetadata) toClientMetadata() []*mimirpb.MetricMetadata {
 	return r
 }
 
+// toStoredMetadata returns the metadata as entries to be persisted to the storage, with the time
+// each metadata has been last seen.
+func (mm *userMetricsMetadata) toStoredMetadata() []metricsmetadata.Entry {
+	mm.mtx.RLock()
+	defer mm.mtx.RUnlock()
+	r := make([]metricsmetadata.Entry, 0, len(mm.metricToMetadata))
+	for _, set := range mm.metricToMetadata {
+		for m, lastSeen := range set {
+			r = append(r, metricsmetadata.Entry{
+				Metric:   m.MetricFamilyName,
+				Type:     mimirpb.MetricMetadataMetricTypeToMetricType(m.GetType()),
+				Help:     m.Help,
+				Unit:     m.Unit,
+				LastSeen: lastSeen.Unix(),
+			})
+		}
+	}
+	return r
+}
+
 type metricMetadataSet map[mimirpb.MetricMetadata]time.Time
 
 // If deadline is zero time, all metrics are purged.
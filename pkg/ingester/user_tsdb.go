@@ -9,18 +9,23 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/oklog/ulid"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
 	"go.uber.org/atomic"
 
 	"github.com/grafana/mimir/pkg/ingester/activeseries"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util/extract"
 	util_math "github.com/grafana/mimir/pkg/util/math"
 )
@@ -120,6 +125,16 @@ type userTSDB struct {
 	// Cached shipped blocks.
 	shippedBlocksMtx sync.Mutex
 	shippedBlocks    map[ulid.ULID]time.Time
+
+	// Series owned by the ingester, based on the ingesters ring and the tenant shard size.
+	ownedSeriesTracked      bool
+	useOwnedSeriesForLimits bool
+	ownedSeriesMtx          sync.Mutex
+	ownedSeries             ownedSeriesState
+	// Non-nil while the owned series are recomputed. Protected by ownedSeriesMtx.
+	ownedSeriesRecompute *ownedSeriesRecompute
+	// Serializes the owned series recomputations.
+	ownedSeriesRecomputeMtx sync.Mutex
 }
 
 func (u *userTSDB) Appender(ctx context.Context) storage.Appender {
@@ -272,7 +287,7 @@ func (u *userTSDB) PreCreation(metric labels.Labels) error {
 	}
 
 	// Total series limit.
-	if err := u.limiter.AssertMaxSeriesPerUser(u.userID, int(u.Head().NumSeries())); err != nil {
+	if err := u.limiter.AssertMaxSeriesPerUser(u.userID, u.seriesCountForLimits()); err != nil {
 		return err
 	}
 
@@ -290,6 +305,7 @@ func (u *userTSDB) PreCreation(metric labels.Labels) error {
 
 func (u *userTSDB) PostCreation(metric labels.Labels) {
 	u.instanceSeriesCount.Inc()
+	u.updateOwnedSeriesCount(1, metric)
 
 	metricName, err := extract.MetricNameFromLabels(metric)
 	if err != nil {
@@ -301,6 +317,7 @@ func (u *userTSDB) PostCreation(metric labels.Labels) {
 
 func (u *userTSDB) PostDeletion(metrics ...labels.Labels) {
 	u.instanceSeriesCount.Sub(int64(len(metrics)))
+	u.updateOwnedSeriesCount(-1, metrics...)
 
 	for _, metric := range metrics {
 		metricName, err := extract.MetricNameFromLabels(metric)
@@ -369,6 +386,43 @@ func (u *userTSDB) getCachedShippedBlocks() map[ulid.ULID]time.Time {
 	return u.shippedBlocks
 }
 
+// writeBlockExemplars writes the exemplars stored in the TSDB head for the time range of the block
+// to the exemplars file in the block directory, so that they're uploaded along with the block.
+// Exemplars are kept in a circular buffer, so the exemplars of a block may have been partially or
+// totally overwritten once the block is uploaded. No file is written if there are no exemplars.
+func (u *userTSDB) writeBlockExemplars(blockDir string, meta *block.Meta) error {
+	if _, err := os.Stat(filepath.Join(blockDir, block.ExemplarsFilename)); err == nil {
+		// The exemplars file has already been written by a previous upload attempt.
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	q, err := u.db.ExemplarQuerier(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// The block max time is exclusive.
+	res, err := q.Select(meta.MinTime, meta.MaxTime-1, []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, model.MetricNameLabel, ".+")})
+	if err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		return nil
+	}
+
+	series := make([]mimirpb.TimeSeries, 0, len(res))
+	for _, es := range res {
+		series = append(series, mimirpb.TimeSeries{
+			Labels:    mimirpb.FromLabelsToLabelAdapters(es.SeriesLabels),
+			Exemplars: mimirpb.FromExemplarsToExemplarProtos(es.Exemplars),
+		})
+	}
+
+	return block.WriteExemplarsFile(blockDir, series)
+}
+
 // getOldestUnshippedBlockTime returns the unix timestamp with milliseconds precision of the oldest
 // TSDB block not shipped to the storage yet, or 0 if all blocks have been shipped.
 func (u *userTSDB) getOldestUnshippedBlockTime() uint64 {
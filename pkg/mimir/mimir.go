@@ -31,8 +31,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
-	"github.com/prometheus/prometheus/promql"
 	prom_storage "github.com/prometheus/prometheus/storage"
+	promql_v1 "github.com/prometheus/prometheus/web/api/v1"
 	"github.com/weaveworks/common/server"
 	"github.com/weaveworks/common/signals"
 	"go.opentelemetry.io/otel"
@@ -54,6 +54,7 @@ import (
 	"github.com/grafana/mimir/pkg/ingester"
 	"github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/querier/tenantfederation"
 	querier_worker "github.com/grafana/mimir/pkg/querier/worker"
 	"github.com/grafana/mimir/pkg/ruler"
@@ -237,6 +238,9 @@ func (c *Config) Validate(log log.Logger) error {
 	if err := c.Querier.Validate(); err != nil {
 		return errors.Wrap(err, "invalid querier config")
 	}
+	if err := c.TenantFederation.Validate(); err != nil {
+		return errors.Wrap(err, "invalid tenant federation config")
+	}
 	if c.Querier.EngineConfig.Timeout > c.Server.HTTPServerWriteTimeout {
 		return fmt.Errorf("querier timeout (%s) must be lower than or equal to HTTP server write timeout (%s)",
 			c.Querier.EngineConfig.Timeout, c.Server.HTTPServerWriteTimeout)
@@ -682,7 +686,8 @@ type Mimir struct {
 	QuerierQueryable         prom_storage.SampleAndChunkQueryable
 	ExemplarQueryable        prom_storage.ExemplarQueryable
 	MetadataSupplier         querier.MetadataSupplier
-	QuerierEngine            *promql.Engine
+	QuerierEngine            promql_v1.QueryEngine
+	QuerierQueryMetrics      *stats.QueryMetrics
 	QueryFrontendTripperware querymiddleware.Tripperware
 	QueryFrontendCodec       querymiddleware.Codec
 	Ruler                    *ruler.Ruler
@@ -695,6 +700,7 @@ type Mimir struct {
 	ActivityTracker          *activitytracker.ActivityTracker
 	Vault                    *vault.Vault
 	UsageStatsReporter       *usagestats.Reporter
+	TenantPatternResolver    *tenantfederation.TenantPatternResolver
 	BuildInfoHandler         http.Handler
 
 	// Queryables that the querier should use to query the long term storage.
@@ -798,6 +804,7 @@ func (t *Mimir) Run() error {
 	// available in ingesters
 	if t.Ring != nil {
 		t.API.RegisterRing(t.Ring)
+		t.API.RegisterRingOwnership(ingester.NewRingOwnershipHandler(t.Ring))
 	} else if t.Ingester != nil {
 		t.API.RegisterRing(t.Ingester.RingHandler())
 	}
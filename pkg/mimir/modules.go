@@ -45,11 +45,13 @@ import (
 	"github.com/grafana/mimir/pkg/ingester"
 	"github.com/grafana/mimir/pkg/querier"
 	"github.com/grafana/mimir/pkg/querier/engine"
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/querier/tenantfederation"
 	querier_worker "github.com/grafana/mimir/pkg/querier/worker"
 	"github.com/grafana/mimir/pkg/ruler"
 	"github.com/grafana/mimir/pkg/scheduler"
 	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storegateway"
 	"github.com/grafana/mimir/pkg/usagestats"
 	"github.com/grafana/mimir/pkg/util"
@@ -91,6 +93,7 @@ const (
 	QueryScheduler             string = "query-scheduler"
 	Vault                      string = "vault"
 	TenantFederation           string = "tenant-federation"
+	TenantPatternResolver      string = "tenant-pattern-resolver"
 	UsageStats                 string = "usage-stats"
 	All                        string = "all"
 
@@ -379,7 +382,7 @@ func (t *Mimir) initDistributorService() (serv services.Service, err error) {
 }
 
 func (t *Mimir) initDistributor() (serv services.Service, err error) {
-	t.API.RegisterDistributor(t.Distributor, t.Cfg.Distributor, t.Registerer)
+	t.API.RegisterDistributor(t.Distributor, t.Cfg.Distributor, t.Registerer, t.Overrides)
 
 	return nil, nil
 }
@@ -390,11 +393,20 @@ func (t *Mimir) initQueryable() (serv services.Service, err error) {
 	querierRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{"engine": "querier"}, t.Registerer)
 
 	// Create a querier queryable and PromQL engine
-	t.QuerierQueryable, t.ExemplarQueryable, t.QuerierEngine = querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryables, querierRegisterer, util_log.Logger, t.ActivityTracker)
+	t.QuerierQueryMetrics = stats.NewQueryMetrics(querierRegisterer)
+	t.QuerierQueryable, t.ExemplarQueryable, t.QuerierEngine = querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryables, t.QuerierQueryMetrics, querierRegisterer, util_log.Logger, t.ActivityTracker)
 
 	// Use the distributor to return metric metadata by default
 	t.MetadataSupplier = t.Distributor
 
+	if t.Cfg.Querier.QueryStoreForMetadataEnabled {
+		bucketClient, err := bucket.NewClient(context.Background(), t.Cfg.BlocksStorage.Bucket, "querier-metadata", util_log.Logger, t.Registerer)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create metric metadata bucket client")
+		}
+		t.MetadataSupplier = querier.NewStoreMetadataSupplier(t.Distributor, bucketClient, t.Overrides, util_log.Logger)
+	}
+
 	// Register the default endpoints that are always enabled for the querier module
 	t.API.RegisterQueryable(t.Distributor)
 
@@ -408,13 +420,72 @@ func (t *Mimir) initTenantFederation() (serv services.Service, err error) {
 		// single tenant. This allows for a less impactful enabling of tenant
 		// federation.
 		const bypassForSingleQuerier = true
-		t.QuerierQueryable = querier.NewSampleAndChunkQueryable(tenantfederation.NewQueryable(t.QuerierQueryable, bypassForSingleQuerier, util_log.Logger))
-		t.ExemplarQueryable = tenantfederation.NewExemplarQueryable(t.ExemplarQueryable, bypassForSingleQuerier, util_log.Logger)
-		t.MetadataSupplier = tenantfederation.NewMetadataSupplier(t.MetadataSupplier, util_log.Logger)
+
+		// Each tenant's sub-query is federated across the remote clusters, if any.
+		if t.Cfg.TenantFederation.RemoteClustersEnabled() {
+			remotes := make([]tenantfederation.ClusterQueryable, 0, len(t.Cfg.TenantFederation.RemoteClusters))
+			for _, cluster := range t.Cfg.TenantFederation.RemoteClusters {
+				remotes = append(remotes, tenantfederation.ClusterQueryable{
+					Name:      cluster.Name,
+					Queryable: querier.NewRemoteReadQueryable(cluster, t.Overrides, nil, util_log.Logger),
+				})
+			}
+
+			local := tenantfederation.ClusterQueryable{Name: t.Cfg.TenantFederation.LocalClusterName, Queryable: t.QuerierQueryable}
+			t.QuerierQueryable = querier.NewSampleAndChunkQueryable(tenantfederation.NewClustersQueryable(local, remotes, t.Overrides, t.QuerierQueryMetrics, t.Cfg.TenantFederation.MaxConcurrent, util_log.Logger))
+		}
+
+		t.QuerierQueryable = querier.NewSampleAndChunkQueryable(tenantfederation.NewQueryable(t.QuerierQueryable, bypassForSingleQuerier, t.Cfg.TenantFederation.MaxConcurrent, util_log.Logger))
+		t.ExemplarQueryable = tenantfederation.NewExemplarQueryable(t.ExemplarQueryable, bypassForSingleQuerier, t.Cfg.TenantFederation.MaxConcurrent, util_log.Logger)
+		t.MetadataSupplier = tenantfederation.NewMetadataSupplier(t.MetadataSupplier, t.Cfg.TenantFederation.MaxConcurrent, util_log.Logger)
 	}
 	return nil, nil
 }
 
+// initTenantPatternResolver creates the resolver of the tenant ID patterns used in federated queries.
+// The patterns are resolved by the query-frontend, or by the querier when it's queried directly, so that
+// the limits, queues and caches of the query-frontend, query-scheduler and querier only see the resolved
+// tenant IDs.
+func (t *Mimir) initTenantPatternResolver() (serv services.Service, err error) {
+	if !t.Cfg.TenantFederation.PatternsEnabled() {
+		return nil, nil
+	}
+
+	bucketClient, err := bucket.NewClient(context.Background(), t.Cfg.BlocksStorage.Bucket, "tenant-federation", util_log.Logger, t.Registerer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tenant federation bucket client")
+	}
+
+	usersScanner := mimir_tsdb.NewUsersScanner(bucketClient, mimir_tsdb.AllUsers, util_log.Logger)
+	bucketTenants := tenantfederation.TenantsListerFunc(func(ctx context.Context) ([]string, error) {
+		users, _, err := usersScanner.ScanUsers(ctx)
+		return users, err
+	})
+
+	ingesterTenants := distributor.NewIngestersTenantsLister(t.Cfg.Distributor, t.Cfg.IngesterClient, t.Ring, util_log.Logger)
+
+	t.TenantPatternResolver, err = tenantfederation.NewTenantPatternResolver(
+		tenantfederation.NewLimitsTenantPatternAuthorizer(t.Overrides),
+		[]tenantfederation.TenantsLister{bucketTenants, ingesterTenants},
+		t.Overrides,
+		t.Cfg.TenantFederation.KnownTenantsRefreshInterval,
+		util_log.Logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return t.TenantPatternResolver, nil
+}
+
+// wrapWithTenantPatternResolver wraps the query API handler with the middleware resolving the tenant ID
+// patterns, if enabled.
+func (t *Mimir) wrapWithTenantPatternResolver(handler http.Handler) http.Handler {
+	if t.TenantPatternResolver == nil {
+		return handler
+	}
+	return t.TenantPatternResolver.Wrap(handler)
+}
+
 // initQuerier registers an internal HTTP router with a Prometheus API backed by the
 // Mimir Queryable. Then it does one of the following:
 //
@@ -487,7 +558,7 @@ func (t *Mimir) initQuerier() (serv services.Service, err error) {
 	// to ensure requests it processes use the default middleware instrumentation.
 	if !t.Cfg.isAnyModuleEnabled(QueryFrontend, QueryScheduler, Read, All) {
 		// First, register the internal querier handler with the external HTTP server
-		t.API.RegisterQueryAPI(internalQuerierRouter, t.BuildInfoHandler)
+		t.API.RegisterQueryAPI(t.wrapWithTenantPatternResolver(internalQuerierRouter), t.BuildInfoHandler)
 
 		// Second, set the http.Handler that the frontend worker will use to process requests to point to
 		// the external HTTP server. This will allow the querier to consolidate query metrics both external
@@ -636,7 +707,7 @@ func (t *Mimir) initQueryFrontend() (serv services.Service, err error) {
 	roundTripper = t.QueryFrontendTripperware(roundTripper)
 
 	handler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, t.Registerer, t.ActivityTracker)
-	t.API.RegisterQueryFrontendHandler(handler, t.BuildInfoHandler)
+	t.API.RegisterQueryFrontendHandler(t.wrapWithTenantPatternResolver(handler), t.BuildInfoHandler)
 
 	var frontendSvc services.Service
 	if frontendV1 != nil {
@@ -722,7 +793,7 @@ func (t *Mimir) initRuler() (serv services.Service, err error) {
 		// TODO: Consider wrapping logger to differentiate from querier module logger
 		rulerRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{"engine": "ruler"}, t.Registerer)
 
-		queryable, _, eng := querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryables, rulerRegisterer, util_log.Logger, t.ActivityTracker)
+		queryable, _, eng := querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryables, stats.NewQueryMetrics(rulerRegisterer), rulerRegisterer, util_log.Logger, t.ActivityTracker)
 		queryable = querier.NewErrorTranslateQueryableWithFn(queryable, ruler.WrapQueryableErrors)
 
 		if t.Cfg.Ruler.TenantFederation.Enabled {
@@ -734,17 +805,17 @@ func (t *Mimir) initRuler() (serv services.Service, err error) {
 			// This makes this label more consistent and hopefully less confusing to users.
 			const bypassForSingleQuerier = false
 
-			federatedQueryable = tenantfederation.NewQueryable(queryable, bypassForSingleQuerier, util_log.Logger)
+			federatedQueryable = tenantfederation.NewQueryable(queryable, bypassForSingleQuerier, t.Cfg.TenantFederation.MaxConcurrent, util_log.Logger)
 
-			regularQueryFunc := rules.EngineQueryFunc(eng, queryable)
-			federatedQueryFunc := rules.EngineQueryFunc(eng, federatedQueryable)
+			regularQueryFunc := ruler.EngineQueryFunc(eng, queryable)
+			federatedQueryFunc := ruler.EngineQueryFunc(eng, federatedQueryable)
 
 			embeddedQueryable = federatedQueryable
 			queryFunc = ruler.TenantFederationQueryFunc(regularQueryFunc, federatedQueryFunc)
 
 		} else {
 			embeddedQueryable = queryable
-			queryFunc = rules.EngineQueryFunc(eng, queryable)
+			queryFunc = ruler.EngineQueryFunc(eng, queryable)
 		}
 	}
 	managerFactory := ruler.DefaultTenantManagerFactory(
@@ -935,6 +1006,7 @@ func (t *Mimir) setupModuleManager() error {
 	mm.RegisterModule(StoreGateway, t.initStoreGateway)
 	mm.RegisterModule(QueryScheduler, t.initQueryScheduler)
 	mm.RegisterModule(TenantFederation, t.initTenantFederation, modules.UserInvisibleModule)
+	mm.RegisterModule(TenantPatternResolver, t.initTenantPatternResolver, modules.UserInvisibleModule)
 	mm.RegisterModule(UsageStats, t.initUsageStats, modules.UserInvisibleModule)
 	mm.RegisterModule(Vault, t.initVault, modules.UserInvisibleModule)
 	mm.RegisterModule(Write, nil)
@@ -957,10 +1029,10 @@ func (t *Mimir) setupModuleManager() error {
 		IngesterService:          {Overrides, RuntimeConfig, MemberlistKV},
 		Flusher:                  {Overrides, API},
 		Queryable:                {Overrides, DistributorService, Ring, API, StoreQueryable, MemberlistKV},
-		Querier:                  {TenantFederation, Vault},
+		Querier:                  {TenantFederation, TenantPatternResolver, Vault},
 		StoreQueryable:           {Overrides, MemberlistKV},
 		QueryFrontendTripperware: {API, Overrides},
-		QueryFrontend:            {QueryFrontendTripperware, MemberlistKV, Vault},
+		QueryFrontend:            {QueryFrontendTripperware, TenantPatternResolver, MemberlistKV, Vault},
 		QueryScheduler:           {API, Overrides, MemberlistKV, Vault},
 		Ruler:                    {DistributorService, StoreQueryable, RulerStorage, Vault},
 		RulerStorage:             {Overrides},
@@ -968,11 +1040,18 @@ func (t *Mimir) setupModuleManager() error {
 		Compactor:                {API, MemberlistKV, Overrides, Vault},
 		StoreGateway:             {API, Overrides, MemberlistKV, Vault},
 		TenantFederation:         {Queryable},
+		TenantPatternResolver:    {API, Overrides},
 		Write:                    {Distributor, Ingester},
 		Read:                     {QueryFrontend, Querier},
 		Backend:                  {QueryScheduler, Ruler, StoreGateway, Compactor, AlertManager, OverridesExporter},
 		All:                      {QueryFrontend, Querier, Ingester, Distributor, StoreGateway, Ruler, Compactor},
 	}
+
+	// The ingesters ring is only needed to list the tenants used to resolve the tenant ID patterns.
+	if t.Cfg.TenantFederation.PatternsEnabled() {
+		deps[TenantPatternResolver] = append(deps[TenantPatternResolver], Ring)
+	}
+
 	for mod, targets := range deps {
 		if err := mm.AddDependency(mod, targets...); err != nil {
 			return err
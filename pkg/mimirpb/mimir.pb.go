@@ -283,6 +283,13 @@ type TimeSeries struct {
 	Samples    []Sample    `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples"`
 	Exemplars  []Exemplar  `protobuf:"bytes,3,rep,name=exemplars,proto3" json:"exemplars"`
 	Histograms []Histogram `protobuf:"bytes,4,rep,name=histograms,proto3" json:"histograms"`
+	// Timestamp in milliseconds at which the counter or histogram series was created (its start time),
+	// as reported by the client. Zero value means the created timestamp is not known.
+	CreatedTimestamp int64 `protobuf:"varint,6,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
+	// Set by the distributor when the first sample (or the first histogram) is the zero sample it synthesized
+	// at the created timestamp. The ingester doesn't report a failure when appending such sample.
+	CreatedTimestampZeroSample    bool `protobuf:"varint,7,opt,name=created_timestamp_zero_sample,json=createdTimestampZeroSample,proto3" json:"created_timestamp_zero_sample,omitempty"`
+	CreatedTimestampZeroHistogram bool `protobuf:"varint,8,opt,name=created_timestamp_zero_histogram,json=createdTimestampZeroHistogram,proto3" json:"created_timestamp_zero_histogram,omitempty"`
 }
 
 func (m *TimeSeries) Reset()      { *m = TimeSeries{} }
@@ -338,6 +345,27 @@ func (m *TimeSeries) GetHistograms() []Histogram {
 	return nil
 }
 
+func (m *TimeSeries) GetCreatedTimestamp() int64 {
+	if m != nil {
+		return m.CreatedTimestamp
+	}
+	return 0
+}
+
+func (m *TimeSeries) GetCreatedTimestampZeroSample() bool {
+	if m != nil {
+		return m.CreatedTimestampZeroSample
+	}
+	return false
+}
+
+func (m *TimeSeries) GetCreatedTimestampZeroHistogram() bool {
+	if m != nil {
+		return m.CreatedTimestampZeroHistogram
+	}
+	return false
+}
+
 type LabelPair struct {
 	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -600,7 +628,6 @@ func (m *Exemplar) GetTimestampMs() int64 {
 // This is based on https://github.com/prometheus/prometheus/blob/main/prompb/types.proto
 type Histogram struct {
 	// Types that are valid to be assigned to Count:
-	//
 	//	*Histogram_CountInt
 	//	*Histogram_CountFloat
 	Count isHistogram_Count `protobuf_oneof:"count"`
@@ -615,7 +642,6 @@ type Histogram struct {
 	Schema        int32   `protobuf:"zigzag32,4,opt,name=schema,proto3" json:"schema,omitempty"`
 	ZeroThreshold float64 `protobuf:"fixed64,5,opt,name=zero_threshold,json=zeroThreshold,proto3" json:"zero_threshold,omitempty"`
 	// Types that are valid to be assigned to ZeroCount:
-	//
 	//	*Histogram_ZeroCountInt
 	//	*Histogram_ZeroCountFloat
 	ZeroCount isHistogram_ZeroCount `protobuf_oneof:"zero_count"`
@@ -1246,12 +1272,12 @@ type QueryResponse struct {
 	ErrorType QueryResponse_ErrorType `protobuf:"varint,2,opt,name=error_type,json=errorType,proto3,enum=cortexpb.QueryResponse_ErrorType" json:"error_type,omitempty"`
 	Error     string                  `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 	// Types that are valid to be assigned to Data:
-	//
 	//	*QueryResponse_String_
 	//	*QueryResponse_Vector
 	//	*QueryResponse_Scalar
 	//	*QueryResponse_Matrix
-	Data isQueryResponse_Data `protobuf_oneof:"data"`
+	Data     isQueryResponse_Data `protobuf_oneof:"data"`
+	Warnings []string             `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
@@ -1367,6 +1393,13 @@ func (m *QueryResponse) GetMatrix() *MatrixData {
 	return nil
 }
 
+func (m *QueryResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1787,117 +1820,122 @@ func init() {
 func init() { proto.RegisterFile("mimir.proto", fileDescriptor_86d4d7485f544059) }
 
 var fileDescriptor_86d4d7485f544059 = []byte{
-	// 1758 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcf, 0x73, 0x1b, 0x49,
-	0x15, 0x56, 0x4b, 0x63, 0x49, 0xf3, 0x2c, 0xc9, 0xb3, 0xbd, 0xa9, 0xa0, 0x4d, 0x6d, 0x64, 0x67,
-	0x28, 0x16, 0x43, 0x81, 0x42, 0x65, 0x21, 0x5b, 0xbb, 0x15, 0x0a, 0x46, 0xf2, 0x24, 0xb6, 0xd7,
-	0x96, 0x4c, 0x4b, 0xca, 0xb2, 0x5c, 0x54, 0x23, 0xb9, 0x6d, 0x4d, 0xed, 0x8c, 0x66, 0x98, 0x19,
-	0x85, 0x98, 0x13, 0x17, 0x28, 0x8a, 0x13, 0x17, 0x2e, 0x14, 0x37, 0x0e, 0xf0, 0x17, 0xf0, 0x37,
-	0xa4, 0x8a, 0xa2, 0x2a, 0xc7, 0x2d, 0x0e, 0x29, 0xe2, 0x5c, 0xf6, 0x98, 0x03, 0x27, 0x4e, 0x54,
-	0xbf, 0x9e, 0x1f, 0x1a, 0xd9, 0x86, 0xc0, 0xfa, 0x36, 0xfd, 0xfa, 0x7b, 0xaf, 0xbf, 0x7e, 0xfd,
-	0xf5, 0xd3, 0x6b, 0xc1, 0xba, 0x6b, 0xbb, 0x76, 0xd0, 0xf6, 0x03, 0x2f, 0xf2, 0x68, 0x75, 0xea,
-	0x05, 0x11, 0x7f, 0xea, 0x4f, 0x6e, 0x7d, 0xfb, 0xd4, 0x8e, 0x66, 0x8b, 0x49, 0x7b, 0xea, 0xb9,
-	0x77, 0x4f, 0xbd, 0x53, 0xef, 0x2e, 0x02, 0x26, 0x8b, 0x13, 0x1c, 0xe1, 0x00, 0xbf, 0xa4, 0xa3,
-	0xfe, 0x97, 0x22, 0xd4, 0x3e, 0x09, 0xec, 0x88, 0x33, 0xfe, 0xd3, 0x05, 0x0f, 0x23, 0x7a, 0x04,
-	0x10, 0xd9, 0x2e, 0x0f, 0x79, 0x60, 0xf3, 0xb0, 0x49, 0xb6, 0x4a, 0xdb, 0xeb, 0xf7, 0x6e, 0xb4,
-	0x93, 0xf0, 0xed, 0xa1, 0xed, 0xf2, 0x01, 0xce, 0x75, 0x6e, 0x3d, 0x7b, 0xb1, 0x59, 0xf8, 0xfb,
-	0x8b, 0x4d, 0x7a, 0x14, 0x70, 0xcb, 0x71, 0xbc, 0xe9, 0x30, 0xf5, 0x63, 0x4b, 0x31, 0xe8, 0x87,
-	0x50, 0x1e, 0x78, 0x8b, 0x60, 0xca, 0x9b, 0xc5, 0x2d, 0xb2, 0xdd, 0xb8, 0x77, 0x27, 0x8b, 0xb6,
-	0xbc, 0x72, 0x5b, 0x82, 0xcc, 0xf9, 0xc2, 0x65, 0xb1, 0x03, 0xfd, 0x08, 0xaa, 0x2e, 0x8f, 0xac,
-	0x63, 0x2b, 0xb2, 0x9a, 0x25, 0xa4, 0xd2, 0xcc, 0x9c, 0x0f, 0x79, 0x14, 0xd8, 0xd3, 0xc3, 0x78,
-	0xbe, 0xa3, 0x3c, 0x7b, 0xb1, 0x49, 0x58, 0x8a, 0xa7, 0x0f, 0xe0, 0x56, 0xf8, 0x99, 0xed, 0x8f,
-	0x1d, 0x6b, 0xc2, 0x9d, 0xf1, 0xdc, 0x72, 0xf9, 0xf8, 0x89, 0xe5, 0xd8, 0xc7, 0x56, 0x64, 0x7b,
-	0xf3, 0xe6, 0x17, 0x95, 0x2d, 0xb2, 0x5d, 0x65, 0x5f, 0x11, 0x90, 0x03, 0x81, 0xe8, 0x59, 0x2e,
-	0x7f, 0x9c, 0xce, 0xeb, 0x9b, 0x00, 0x19, 0x1f, 0x5a, 0x81, 0x92, 0x71, 0xb4, 0xa7, 0x15, 0x68,
-	0x15, 0x14, 0x36, 0x3a, 0x30, 0x35, 0xa2, 0x6f, 0x40, 0x3d, 0x66, 0x1f, 0xfa, 0xde, 0x3c, 0xe4,
-	0xfa, 0x3f, 0x09, 0x40, 0x96, 0x1d, 0x6a, 0x40, 0x19, 0x57, 0x4e, 0x72, 0xf8, 0x76, 0x46, 0x1c,
-	0xd7, 0x3b, 0xb2, 0xec, 0xa0, 0x73, 0x23, 0x4e, 0x61, 0x0d, 0x4d, 0xc6, 0xb1, 0xe5, 0x47, 0x3c,
-	0x60, 0xb1, 0x23, 0xfd, 0x0e, 0x54, 0x42, 0xcb, 0xf5, 0x1d, 0x1e, 0x36, 0x8b, 0x18, 0x43, 0xcb,
-	0x62, 0x0c, 0x70, 0x02, 0x37, 0x5d, 0x60, 0x09, 0x8c, 0xde, 0x07, 0x95, 0x3f, 0xe5, 0xae, 0xef,
-	0x58, 0x41, 0x18, 0x27, 0x8c, 0x66, 0x3e, 0x66, 0x3c, 0x15, 0x7b, 0x65, 0x50, 0xfa, 0x21, 0xc0,
-	0xcc, 0x0e, 0x23, 0xef, 0x34, 0xb0, 0xdc, 0xb0, 0xa9, 0xac, 0x12, 0xde, 0x4d, 0xe6, 0x62, 0xcf,
-	0x25, 0xb0, 0xfe, 0x3d, 0x50, 0xd3, 0xfd, 0x50, 0x0a, 0x8a, 0x48, 0x74, 0x93, 0x6c, 0x91, 0xed,
-	0x1a, 0xc3, 0x6f, 0x7a, 0x03, 0xd6, 0x9e, 0x58, 0xce, 0x42, 0x9e, 0x7e, 0x8d, 0xc9, 0x81, 0x6e,
-	0x40, 0x59, 0x6e, 0x81, 0xde, 0x81, 0x1a, 0x8a, 0x25, 0xb2, 0x5c, 0x7f, 0xec, 0x86, 0x08, 0x2b,
-	0xb1, 0xf5, 0xd4, 0x76, 0x18, 0x66, 0x21, 0x44, 0x5c, 0x92, 0x84, 0xf8, 0x7d, 0x11, 0x1a, 0x79,
-	0x0d, 0xd0, 0x0f, 0x40, 0x89, 0xce, 0x7c, 0x89, 0x6b, 0xdc, 0xfb, 0xea, 0x55, 0x5a, 0x89, 0x87,
-	0xc3, 0x33, 0x9f, 0x33, 0x74, 0xa0, 0xdf, 0x02, 0xea, 0xa2, 0x6d, 0x7c, 0x62, 0xb9, 0xb6, 0x73,
-	0x86, 0x7a, 0x41, 0x2a, 0x2a, 0xd3, 0xe4, 0xcc, 0x43, 0x9c, 0x10, 0x32, 0x11, 0xdb, 0x9c, 0x71,
-	0xc7, 0x6f, 0x2a, 0x38, 0x8f, 0xdf, 0xc2, 0xb6, 0x98, 0xdb, 0x51, 0x73, 0x4d, 0xda, 0xc4, 0xb7,
-	0x7e, 0x06, 0x90, 0xad, 0x44, 0xd7, 0xa1, 0x32, 0xea, 0x7d, 0xdc, 0xeb, 0x7f, 0xd2, 0xd3, 0x0a,
-	0x62, 0xd0, 0xed, 0x8f, 0x7a, 0x43, 0x93, 0x69, 0x84, 0xaa, 0xb0, 0xf6, 0xc8, 0x18, 0x3d, 0x32,
-	0xb5, 0x22, 0xad, 0x83, 0xba, 0xbb, 0x37, 0x18, 0xf6, 0x1f, 0x31, 0xe3, 0x50, 0x2b, 0x51, 0x0a,
-	0x0d, 0x9c, 0xc9, 0x6c, 0x8a, 0x70, 0x1d, 0x8c, 0x0e, 0x0f, 0x0d, 0xf6, 0xa9, 0xb6, 0x26, 0x04,
-	0xb9, 0xd7, 0x7b, 0xd8, 0xd7, 0xca, 0xb4, 0x06, 0xd5, 0xc1, 0xd0, 0x18, 0x9a, 0x03, 0x73, 0xa8,
-	0x55, 0xf4, 0x8f, 0xa1, 0x2c, 0x97, 0xbe, 0x06, 0x21, 0xea, 0xbf, 0x22, 0x50, 0x4d, 0xc4, 0x73,
-	0x1d, 0xc2, 0xce, 0x49, 0x22, 0x39, 0xcf, 0x0b, 0x42, 0x28, 0x5d, 0x10, 0x82, 0xfe, 0xd7, 0x35,
-	0x50, 0x53, 0x31, 0xd2, 0xdb, 0xa0, 0x4e, 0xbd, 0xc5, 0x3c, 0x1a, 0xdb, 0xf3, 0x08, 0x8f, 0x5c,
-	0xd9, 0x2d, 0xb0, 0x2a, 0x9a, 0xf6, 0xe6, 0x11, 0xbd, 0x03, 0xeb, 0x72, 0xfa, 0xc4, 0xf1, 0xac,
-	0x48, 0xae, 0xb5, 0x5b, 0x60, 0x80, 0xc6, 0x87, 0xc2, 0x46, 0x35, 0x28, 0x85, 0x0b, 0x17, 0x57,
-	0x22, 0x4c, 0x7c, 0xd2, 0x9b, 0x50, 0x0e, 0xa7, 0x33, 0xee, 0x5a, 0x78, 0xb8, 0x6f, 0xb1, 0x78,
-	0x44, 0xbf, 0x06, 0x8d, 0x9f, 0xf3, 0xc0, 0x1b, 0x47, 0xb3, 0x80, 0x87, 0x33, 0xcf, 0x39, 0xc6,
-	0x83, 0x26, 0xac, 0x2e, 0xac, 0xc3, 0xc4, 0x48, 0xdf, 0x8b, 0x61, 0x19, 0xaf, 0x32, 0xf2, 0x22,
-	0xac, 0x26, 0xec, 0xdd, 0x84, 0xdb, 0x37, 0x41, 0x5b, 0xc2, 0x49, 0x82, 0x15, 0x24, 0x48, 0x58,
-	0x23, 0x45, 0x4a, 0x92, 0x06, 0x34, 0xe6, 0xfc, 0xd4, 0x8a, 0xec, 0x27, 0x7c, 0x1c, 0xfa, 0xd6,
-	0x3c, 0x6c, 0x56, 0x57, 0xab, 0x72, 0x67, 0x31, 0xfd, 0x8c, 0x47, 0x03, 0xdf, 0x9a, 0xc7, 0x37,
-	0xb4, 0x9e, 0x78, 0x08, 0x5b, 0x48, 0xbf, 0x0e, 0x1b, 0x69, 0x88, 0x63, 0xee, 0x44, 0x56, 0xd8,
-	0x54, 0xb7, 0x4a, 0xdb, 0x94, 0xa5, 0x91, 0x77, 0xd0, 0x9a, 0x03, 0x22, 0xb7, 0xb0, 0x09, 0x5b,
-	0xa5, 0x6d, 0x92, 0x01, 0x91, 0x98, 0x28, 0x6f, 0x0d, 0xdf, 0x0b, 0xed, 0x25, 0x52, 0xeb, 0xff,
-	0x9d, 0x54, 0xe2, 0x91, 0x92, 0x4a, 0x43, 0xc4, 0xa4, 0x6a, 0x92, 0x54, 0x62, 0xce, 0x48, 0xa5,
-	0xc0, 0x98, 0x54, 0x5d, 0x92, 0x4a, 0xcc, 0x31, 0xa9, 0x07, 0x00, 0x01, 0x0f, 0x79, 0x34, 0x9e,
-	0x89, 0xcc, 0x37, 0xb0, 0x08, 0xdc, 0xbe, 0xa4, 0x8c, 0xb5, 0x99, 0x40, 0xed, 0xda, 0xf3, 0x88,
-	0xa9, 0x41, 0xf2, 0x49, 0xdf, 0x05, 0x35, 0xd5, 0x5a, 0x73, 0x03, 0xc5, 0x97, 0x19, 0xf4, 0x8f,
-	0x40, 0x4d, 0xbd, 0xf2, 0x57, 0xb9, 0x02, 0xa5, 0x4f, 0xcd, 0x81, 0x46, 0x68, 0x19, 0x8a, 0xbd,
-	0xbe, 0x56, 0xcc, 0xae, 0x73, 0xe9, 0x96, 0xf2, 0xeb, 0x3f, 0xb6, 0x48, 0xa7, 0x02, 0x6b, 0xc8,
-	0xbb, 0x53, 0x03, 0xc8, 0x8e, 0x5d, 0xff, 0x9b, 0x02, 0x0d, 0x3c, 0xe2, 0x4c, 0xd2, 0x21, 0x50,
-	0x9c, 0xe3, 0xc1, 0x78, 0x65, 0x27, 0xf5, 0x8e, 0xf9, 0xaf, 0x17, 0x9b, 0xc6, 0xd2, 0xaf, 0xbb,
-	0x1f, 0x78, 0x2e, 0x8f, 0x66, 0x7c, 0x11, 0x2e, 0x7f, 0xba, 0xde, 0x31, 0x77, 0xee, 0xa6, 0x05,
-	0xba, 0xdd, 0x95, 0xe1, 0xb2, 0x1d, 0x6b, 0xd3, 0x15, 0xcb, 0x97, 0xd5, 0xfc, 0xed, 0xe5, 0x4d,
-	0x49, 0x15, 0x33, 0x35, 0xd5, 0xb0, 0xb8, 0xec, 0x72, 0x26, 0xbe, 0xec, 0x38, 0xb8, 0xe4, 0xe6,
-	0x5d, 0x83, 0xa2, 0xae, 0xe1, 0xa6, 0x7c, 0x03, 0xb4, 0x94, 0xc5, 0x04, 0xb1, 0x89, 0xd8, 0x52,
-	0x0d, 0xca, 0x10, 0x08, 0x4d, 0x57, 0x4b, 0xa0, 0xf2, 0xb2, 0xa4, 0x77, 0x28, 0x86, 0xee, 0x2b,
-	0x55, 0xa2, 0x15, 0xf7, 0x95, 0x6a, 0x59, 0xab, 0xec, 0x2b, 0x55, 0x55, 0x83, 0x7d, 0xa5, 0x5a,
-	0xd3, 0xea, 0xfb, 0x4a, 0x75, 0x43, 0xd3, 0x58, 0x56, 0xc5, 0xd8, 0x4a, 0xf5, 0x60, 0xab, 0xd7,
-	0x96, 0xad, 0x5e, 0x99, 0x65, 0x89, 0x3e, 0x00, 0xc8, 0xb6, 0x27, 0x4e, 0xd5, 0x3b, 0x39, 0x09,
-	0xb9, 0x2c, 0x8d, 0x6f, 0xb1, 0x78, 0x24, 0xec, 0x0e, 0x9f, 0x9f, 0x46, 0x33, 0x3c, 0x90, 0x3a,
-	0x8b, 0x47, 0xfa, 0x02, 0x68, 0x5e, 0x8c, 0xf8, 0x8b, 0xfe, 0x06, 0xbf, 0xce, 0x0f, 0x40, 0x4d,
-	0xe5, 0x86, 0x6b, 0xe5, 0xba, 0xb4, 0x7c, 0xcc, 0xb8, 0x4b, 0xcb, 0x1c, 0xf4, 0x39, 0x6c, 0xc8,
-	0x46, 0x20, 0xbb, 0x04, 0xa9, 0x62, 0xc8, 0x25, 0x8a, 0x29, 0x66, 0x8a, 0x79, 0x1f, 0x2a, 0x49,
-	0xde, 0x65, 0xaf, 0xf3, 0xce, 0x65, 0x2d, 0x0b, 0x22, 0x58, 0x82, 0xd4, 0x43, 0xd8, 0x58, 0x99,
-	0xa3, 0x2d, 0x80, 0x89, 0xb7, 0x98, 0x1f, 0x5b, 0x71, 0xcb, 0x4b, 0xb6, 0xd7, 0xd8, 0x92, 0x45,
-	0xf0, 0x71, 0xbc, 0x9f, 0xf1, 0x20, 0x51, 0x30, 0x0e, 0x84, 0x75, 0xe1, 0xfb, 0x3c, 0x88, 0x35,
-	0x2c, 0x07, 0x19, 0x77, 0x65, 0x89, 0xbb, 0xee, 0xc0, 0xdb, 0x2b, 0x9b, 0xc4, 0xe4, 0xe6, 0x2a,
-	0x4e, 0x71, 0xa5, 0xe2, 0xd0, 0x0f, 0x2e, 0xe6, 0xf5, 0x9d, 0xd5, 0x06, 0x30, 0x8d, 0xb7, 0x9c,
-	0xd2, 0x3f, 0x29, 0x50, 0xff, 0xd1, 0x82, 0x07, 0x67, 0x49, 0x6f, 0x4a, 0xef, 0x43, 0x39, 0x8c,
-	0xac, 0x68, 0x11, 0xc6, 0x9d, 0x51, 0x2b, 0x8b, 0x93, 0x03, 0xb6, 0x07, 0x88, 0x62, 0x31, 0x9a,
-	0xfe, 0x10, 0x80, 0x07, 0x81, 0x17, 0x8c, 0xb1, 0xab, 0xba, 0xd0, 0xbe, 0xe7, 0x7d, 0x4d, 0x81,
-	0xc4, 0x9e, 0x4a, 0xe5, 0xc9, 0xa7, 0xc8, 0x07, 0x0e, 0x30, 0x4b, 0x2a, 0x93, 0x03, 0xda, 0x16,
-	0x7c, 0x02, 0x7b, 0x7e, 0x8a, 0x69, 0xca, 0x5d, 0xd0, 0x01, 0xda, 0x77, 0xac, 0xc8, 0xda, 0x2d,
-	0xb0, 0x18, 0x25, 0xf0, 0x4f, 0xf8, 0x34, 0xf2, 0x02, 0xac, 0x40, 0x39, 0xfc, 0x63, 0xb4, 0x27,
-	0x78, 0x89, 0xc2, 0xf8, 0x53, 0xcb, 0xb1, 0x02, 0xfc, 0xf9, 0xcd, 0xc7, 0x47, 0x7b, 0x1a, 0x1f,
-	0x47, 0x02, 0xef, 0x5a, 0x51, 0x60, 0x3f, 0xc5, 0xf2, 0x95, 0xc3, 0x1f, 0xa2, 0x3d, 0xc1, 0x4b,
-	0x94, 0xfe, 0x1e, 0x94, 0x65, 0xa6, 0x44, 0xad, 0x37, 0x19, 0xeb, 0x33, 0xd9, 0xd2, 0x0d, 0x46,
-	0xdd, 0xae, 0x39, 0x18, 0x68, 0x44, 0x16, 0x7e, 0xfd, 0x77, 0x04, 0xd4, 0x34, 0x2d, 0xa2, 0x57,
-	0xeb, 0xf5, 0x7b, 0xa6, 0x84, 0x0e, 0xf7, 0x0e, 0xcd, 0xfe, 0x68, 0xa8, 0x11, 0xd1, 0xb8, 0x75,
-	0x8d, 0x5e, 0xd7, 0x3c, 0x30, 0x77, 0x64, 0x03, 0x68, 0xfe, 0xd8, 0xec, 0x8e, 0x86, 0x7b, 0xfd,
-	0x9e, 0x56, 0x12, 0x93, 0x1d, 0x63, 0x67, 0xbc, 0x63, 0x0c, 0x0d, 0x4d, 0x11, 0xa3, 0x3d, 0xd1,
-	0x33, 0xf6, 0x8c, 0x03, 0x6d, 0x8d, 0x6e, 0xc0, 0xfa, 0xa8, 0x67, 0x3c, 0x36, 0xf6, 0x0e, 0x8c,
-	0xce, 0x81, 0xa9, 0x95, 0x85, 0x6f, 0xaf, 0x3f, 0x1c, 0x3f, 0xec, 0x8f, 0x7a, 0x3b, 0x5a, 0x45,
-	0x34, 0x8f, 0x62, 0x68, 0x74, 0xbb, 0xe6, 0xd1, 0x10, 0x21, 0xd5, 0xf8, 0x07, 0xa9, 0x0c, 0x8a,
-	0xe8, 0x83, 0x75, 0x13, 0x20, 0xcb, 0x77, 0xbe, 0xcd, 0x56, 0xaf, 0x6a, 0xcb, 0x2e, 0x56, 0x00,
-	0xfd, 0x97, 0x04, 0x20, 0x3b, 0x07, 0x7a, 0x3f, 0x7b, 0xb7, 0xc8, 0x16, 0xf1, 0xe6, 0xea, 0x71,
-	0x5d, 0xfe, 0x7a, 0xf9, 0x41, 0xee, 0x15, 0x52, 0x5c, 0xbd, 0xd2, 0xd2, 0xf5, 0x3f, 0xbd, 0x45,
-	0xc6, 0x50, 0x5b, 0x8e, 0x2f, 0x4a, 0x9d, 0xec, 0xdd, 0x91, 0x87, 0xca, 0xe2, 0xd1, 0xff, 0xdf,
-	0x7f, 0xfe, 0x86, 0xc0, 0xc6, 0x0a, 0x8d, 0x2b, 0x17, 0xc9, 0x95, 0xc5, 0xe2, 0x1b, 0x94, 0xc5,
-	0xc2, 0xd2, 0x1d, 0x7e, 0x13, 0x32, 0xe2, 0xf0, 0x52, 0x31, 0x5f, 0xfe, 0x46, 0x7a, 0x93, 0xc3,
-	0xeb, 0x00, 0x64, 0x1a, 0xa7, 0xdf, 0x85, 0x72, 0xee, 0xe9, 0x7f, 0x73, 0xf5, 0x26, 0xc4, 0x8f,
-	0x7f, 0x49, 0x38, 0xc6, 0xea, 0x7f, 0x20, 0x50, 0x5b, 0x9e, 0xbe, 0x32, 0x29, 0xff, 0xfb, 0x93,
-	0xb6, 0x93, 0x13, 0x85, 0xac, 0xf3, 0xef, 0x5e, 0x95, 0x47, 0x7c, 0x7b, 0x5c, 0xd0, 0x45, 0xe7,
-	0xfb, 0xcf, 0x5f, 0xb6, 0x0a, 0x9f, 0xbf, 0x6c, 0x15, 0x5e, 0xbf, 0x6c, 0x91, 0x5f, 0x9c, 0xb7,
-	0xc8, 0x9f, 0xcf, 0x5b, 0xe4, 0xd9, 0x79, 0x8b, 0x3c, 0x3f, 0x6f, 0x91, 0x7f, 0x9c, 0xb7, 0xc8,
-	0x17, 0xe7, 0xad, 0xc2, 0xeb, 0xf3, 0x16, 0xf9, 0xed, 0xab, 0x56, 0xe1, 0xf9, 0xab, 0x56, 0xe1,
-	0xf3, 0x57, 0xad, 0xc2, 0x4f, 0x2a, 0xf8, 0x07, 0x8b, 0x3f, 0x99, 0x94, 0xf1, 0xaf, 0x92, 0xf7,
-	0xff, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x65, 0xcf, 0xac, 0x82, 0x72, 0x11, 0x00, 0x00,
+	// 1829 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x73, 0x1b, 0x49,
+	0x15, 0x57, 0x4b, 0xa3, 0x91, 0xe6, 0x59, 0x92, 0x27, 0xbd, 0xa9, 0x30, 0xeb, 0x5a, 0xcb, 0xce,
+	0x50, 0x2c, 0xe6, 0x4b, 0xa1, 0xb2, 0x90, 0xad, 0xdd, 0x0a, 0x05, 0x23, 0x79, 0x12, 0xdb, 0x6b,
+	0x4b, 0xa6, 0x25, 0x65, 0xd9, 0xbd, 0xa8, 0xc6, 0x72, 0x5b, 0x9a, 0xda, 0xf9, 0x62, 0x66, 0x94,
+	0x8d, 0x39, 0x71, 0x81, 0xa2, 0x38, 0x71, 0xe1, 0x42, 0x71, 0xe3, 0xc2, 0x85, 0x2b, 0xff, 0x00,
+	0x97, 0x54, 0x51, 0x54, 0xe5, 0xb8, 0xc5, 0x21, 0x45, 0x9c, 0xcb, 0x1e, 0x73, 0xe6, 0x44, 0x75,
+	0xf7, 0x7c, 0x48, 0x63, 0x1b, 0x02, 0xe4, 0x36, 0xef, 0xbd, 0xdf, 0x7b, 0xfd, 0xeb, 0xee, 0xf7,
+	0xde, 0xbc, 0x19, 0x58, 0x73, 0x6d, 0xd7, 0x0e, 0x3b, 0x41, 0xe8, 0xc7, 0x3e, 0xae, 0x4f, 0xfd,
+	0x30, 0xa6, 0x4f, 0x82, 0x93, 0x8d, 0xef, 0xcc, 0xec, 0x78, 0xbe, 0x38, 0xe9, 0x4c, 0x7d, 0xf7,
+	0xce, 0xcc, 0x9f, 0xf9, 0x77, 0x38, 0xe0, 0x64, 0x71, 0xc6, 0x25, 0x2e, 0xf0, 0x27, 0xe1, 0xa8,
+	0xff, 0xb9, 0x0c, 0x8d, 0x8f, 0x43, 0x3b, 0xa6, 0x84, 0xfe, 0x74, 0x41, 0xa3, 0x18, 0x1f, 0x03,
+	0xc4, 0xb6, 0x4b, 0x23, 0x1a, 0xda, 0x34, 0xd2, 0xd0, 0x76, 0x65, 0x67, 0xed, 0xee, 0xcd, 0x4e,
+	0x1a, 0xbe, 0x33, 0xb2, 0x5d, 0x3a, 0xe4, 0xb6, 0xee, 0xc6, 0xd3, 0xe7, 0x5b, 0xa5, 0xbf, 0x3f,
+	0xdf, 0xc2, 0xc7, 0x21, 0xb5, 0x1c, 0xc7, 0x9f, 0x8e, 0x32, 0x3f, 0xb2, 0x14, 0x03, 0x7f, 0x00,
+	0xf2, 0xd0, 0x5f, 0x84, 0x53, 0xaa, 0x95, 0xb7, 0xd1, 0x4e, 0xeb, 0xee, 0xed, 0x3c, 0xda, 0xf2,
+	0xca, 0x1d, 0x01, 0x32, 0xbd, 0x85, 0x4b, 0x12, 0x07, 0xfc, 0x21, 0xd4, 0x5d, 0x1a, 0x5b, 0xa7,
+	0x56, 0x6c, 0x69, 0x15, 0x4e, 0x45, 0xcb, 0x9d, 0x8f, 0x68, 0x1c, 0xda, 0xd3, 0xa3, 0xc4, 0xde,
+	0x95, 0x9e, 0x3e, 0xdf, 0x42, 0x24, 0xc3, 0xe3, 0xfb, 0xb0, 0x11, 0x7d, 0x66, 0x07, 0x13, 0xc7,
+	0x3a, 0xa1, 0xce, 0xc4, 0xb3, 0x5c, 0x3a, 0x79, 0x6c, 0x39, 0xf6, 0xa9, 0x15, 0xdb, 0xbe, 0xa7,
+	0x7d, 0x59, 0xdb, 0x46, 0x3b, 0x75, 0xf2, 0x15, 0x06, 0x39, 0x64, 0x88, 0xbe, 0xe5, 0xd2, 0x47,
+	0x99, 0x5d, 0xdf, 0x02, 0xc8, 0xf9, 0xe0, 0x1a, 0x54, 0x8c, 0xe3, 0x7d, 0xb5, 0x84, 0xeb, 0x20,
+	0x91, 0xf1, 0xa1, 0xa9, 0x22, 0x7d, 0x1d, 0x9a, 0x09, 0xfb, 0x28, 0xf0, 0xbd, 0x88, 0xea, 0x7f,
+	0xaa, 0x00, 0xe4, 0xa7, 0x83, 0x0d, 0x90, 0xf9, 0xca, 0xe9, 0x19, 0xbe, 0x95, 0x13, 0xe7, 0xeb,
+	0x1d, 0x5b, 0x76, 0xd8, 0xbd, 0x99, 0x1c, 0x61, 0x83, 0xab, 0x8c, 0x53, 0x2b, 0x88, 0x69, 0x48,
+	0x12, 0x47, 0xfc, 0x5d, 0xa8, 0x45, 0x96, 0x1b, 0x38, 0x34, 0xd2, 0xca, 0x3c, 0x86, 0x9a, 0xc7,
+	0x18, 0x72, 0x03, 0xdf, 0x74, 0x89, 0xa4, 0x30, 0x7c, 0x0f, 0x14, 0xfa, 0x84, 0xba, 0x81, 0x63,
+	0x85, 0x51, 0x72, 0x60, 0x38, 0xf7, 0x31, 0x13, 0x53, 0xe2, 0x95, 0x43, 0xf1, 0x07, 0x00, 0x73,
+	0x3b, 0x8a, 0xfd, 0x59, 0x68, 0xb9, 0x91, 0x26, 0x15, 0x09, 0xef, 0xa5, 0xb6, 0xc4, 0x73, 0x09,
+	0x8c, 0xbf, 0x05, 0x37, 0xa6, 0x21, 0xb5, 0x62, 0x7a, 0x3a, 0xe1, 0x77, 0x1e, 0x5b, 0x6e, 0xa0,
+	0xc9, 0xdb, 0x68, 0xa7, 0x42, 0xd4, 0xc4, 0x30, 0x4a, 0xf5, 0xd8, 0x80, 0xcd, 0x4b, 0xe0, 0xc9,
+	0xcf, 0x68, 0xe8, 0x4f, 0xc4, 0x0e, 0x34, 0x71, 0x2b, 0x1b, 0x45, 0xc7, 0x4f, 0x69, 0xe8, 0x8b,
+	0x1d, 0xe3, 0x87, 0xb0, 0x7d, 0x4d, 0x88, 0x8c, 0x94, 0x56, 0xe7, 0x51, 0x36, 0xaf, 0x8a, 0x92,
+	0x6d, 0x45, 0xff, 0x3e, 0x28, 0xd9, 0x45, 0x60, 0x0c, 0x12, 0xcb, 0x10, 0x0d, 0x6d, 0xa3, 0x9d,
+	0x06, 0xe1, 0xcf, 0xf8, 0x26, 0x54, 0x1f, 0x5b, 0xce, 0x42, 0xa4, 0x6d, 0x83, 0x08, 0x41, 0x37,
+	0x40, 0x4e, 0x98, 0xdc, 0x86, 0x46, 0xce, 0xc0, 0x8d, 0x38, 0xac, 0x42, 0xd6, 0x32, 0xdd, 0x51,
+	0x94, 0x87, 0x60, 0x71, 0x51, 0x1a, 0xe2, 0x77, 0x65, 0x68, 0xad, 0x26, 0x2f, 0x7e, 0x1f, 0xa4,
+	0xf8, 0x3c, 0x10, 0xb8, 0xd6, 0xdd, 0xaf, 0x5e, 0x97, 0xe4, 0x89, 0x38, 0x3a, 0x0f, 0x28, 0xe1,
+	0x0e, 0xf8, 0xdb, 0x80, 0x5d, 0xae, 0x9b, 0x9c, 0x59, 0xae, 0xed, 0x9c, 0xf3, 0x44, 0xe7, 0x54,
+	0x14, 0xa2, 0x0a, 0xcb, 0x03, 0x6e, 0x60, 0xf9, 0xcd, 0xb6, 0x39, 0xa7, 0x4e, 0xa0, 0x49, 0xdc,
+	0xce, 0x9f, 0x99, 0x6e, 0xe1, 0xd9, 0xb1, 0x56, 0x15, 0x3a, 0xf6, 0xac, 0x9f, 0x03, 0xe4, 0x2b,
+	0xe1, 0x35, 0xa8, 0x8d, 0xfb, 0x1f, 0xf5, 0x07, 0x1f, 0xf7, 0xd5, 0x12, 0x13, 0x7a, 0x83, 0x71,
+	0x7f, 0x64, 0x12, 0x15, 0x61, 0x05, 0xaa, 0x0f, 0x8d, 0xf1, 0x43, 0x53, 0x2d, 0xe3, 0x26, 0x28,
+	0x7b, 0xfb, 0xc3, 0xd1, 0xe0, 0x21, 0x31, 0x8e, 0xd4, 0x0a, 0xc6, 0xd0, 0xe2, 0x96, 0x5c, 0x27,
+	0x31, 0xd7, 0xe1, 0xf8, 0xe8, 0xc8, 0x20, 0x9f, 0xa8, 0x55, 0x56, 0x49, 0xfb, 0xfd, 0x07, 0x03,
+	0x55, 0xc6, 0x0d, 0xa8, 0x0f, 0x47, 0xc6, 0xc8, 0x1c, 0x9a, 0x23, 0xb5, 0xa6, 0x7f, 0x04, 0xb2,
+	0x58, 0xfa, 0x0d, 0x54, 0x90, 0xfe, 0x4b, 0x04, 0xf5, 0x34, 0xeb, 0xdf, 0x44, 0x45, 0xae, 0xa4,
+	0x44, 0x7a, 0x9f, 0x97, 0x12, 0xa1, 0x72, 0x29, 0x11, 0xf4, 0xbf, 0x56, 0x41, 0xc9, 0x52, 0x0f,
+	0x6f, 0x82, 0x32, 0xf5, 0x17, 0x5e, 0x3c, 0xb1, 0xbd, 0x98, 0x5f, 0xb9, 0xb4, 0x57, 0x22, 0x75,
+	0xae, 0xda, 0xf7, 0x62, 0x7c, 0x1b, 0xd6, 0x84, 0xf9, 0xcc, 0xf1, 0xad, 0x58, 0xac, 0xb5, 0x57,
+	0x22, 0xc0, 0x95, 0x0f, 0x98, 0x0e, 0xab, 0x50, 0x89, 0x16, 0x2e, 0x5f, 0x09, 0x11, 0xf6, 0x88,
+	0x6f, 0x81, 0x1c, 0x4d, 0xe7, 0xd4, 0xb5, 0xf8, 0xe5, 0xde, 0x20, 0x89, 0x84, 0xbf, 0x06, 0x2d,
+	0x5e, 0x1d, 0xf1, 0x3c, 0xa4, 0xd1, 0xdc, 0x77, 0x4e, 0xf9, 0x45, 0x23, 0xd2, 0x64, 0xda, 0x51,
+	0xaa, 0xc4, 0xef, 0x26, 0xb0, 0x9c, 0x97, 0xcc, 0x79, 0x21, 0xd2, 0x60, 0xfa, 0x5e, 0xca, 0xed,
+	0x9b, 0xa0, 0x2e, 0xe1, 0x04, 0xc1, 0x1a, 0x27, 0x88, 0x48, 0x2b, 0x43, 0x0a, 0x92, 0x06, 0xb4,
+	0x3c, 0x3a, 0xb3, 0x62, 0xfb, 0x31, 0x9d, 0x44, 0x81, 0xe5, 0x45, 0x5a, 0xbd, 0xf8, 0x3a, 0xe9,
+	0x2e, 0xa6, 0x9f, 0xd1, 0x78, 0x18, 0x58, 0x5e, 0xd2, 0x5a, 0x9a, 0xa9, 0x07, 0xd3, 0x45, 0xf8,
+	0xeb, 0xb0, 0x9e, 0x85, 0x38, 0xa5, 0x4e, 0x6c, 0x45, 0x9a, 0xb2, 0x5d, 0xd9, 0xc1, 0x24, 0x8b,
+	0xbc, 0xcb, 0xb5, 0x2b, 0x40, 0xce, 0x2d, 0xd2, 0x60, 0xbb, 0xb2, 0x83, 0x72, 0x20, 0x27, 0xc6,
+	0xfa, 0x72, 0x2b, 0xf0, 0x23, 0x7b, 0x89, 0xd4, 0xda, 0x7f, 0x26, 0x95, 0x7a, 0x64, 0xa4, 0xb2,
+	0x10, 0x09, 0xa9, 0x86, 0x20, 0x95, 0xaa, 0x73, 0x52, 0x19, 0x30, 0x21, 0xd5, 0x14, 0xa4, 0x52,
+	0x75, 0x42, 0xea, 0x3e, 0x40, 0x48, 0x23, 0x1a, 0x4f, 0xe6, 0xec, 0xe4, 0x5b, 0xbc, 0x09, 0x6c,
+	0x5e, 0xd1, 0x7f, 0x3b, 0x84, 0xa1, 0xf6, 0x6c, 0x2f, 0x26, 0x4a, 0x98, 0x3e, 0xe2, 0x77, 0x40,
+	0xc9, 0x5b, 0xef, 0x3a, 0x4f, 0xbe, 0x5c, 0xa1, 0x7f, 0x08, 0x4a, 0xe6, 0xb5, 0x5a, 0xca, 0x35,
+	0xa8, 0x7c, 0x62, 0x0e, 0x55, 0x84, 0x65, 0x28, 0xf7, 0x07, 0x6a, 0x39, 0x2f, 0xe7, 0xca, 0x86,
+	0xf4, 0xab, 0x3f, 0xb4, 0x51, 0xb7, 0x06, 0x55, 0xce, 0xbb, 0xdb, 0x00, 0xc8, 0xaf, 0x5d, 0xff,
+	0x9b, 0x04, 0x2d, 0x7e, 0xc5, 0x79, 0x4a, 0x47, 0x80, 0xb9, 0x8d, 0x86, 0x93, 0xc2, 0x4e, 0x9a,
+	0x5d, 0xf3, 0x9f, 0xcf, 0xb7, 0x8c, 0xa5, 0xb1, 0x24, 0x08, 0x7d, 0x97, 0xc6, 0x73, 0xba, 0x88,
+	0x96, 0x1f, 0x5d, 0xff, 0x94, 0x3a, 0x77, 0xb2, 0x26, 0xde, 0xe9, 0x89, 0x70, 0xf9, 0x8e, 0xd5,
+	0x69, 0x41, 0xf3, 0xff, 0xe6, 0xfc, 0xe6, 0xf2, 0xa6, 0x44, 0x16, 0x13, 0x25, 0xcb, 0x61, 0x56,
+	0xec, 0xc2, 0x92, 0x14, 0x3b, 0x17, 0xae, 0xa8, 0xbc, 0x37, 0x90, 0x51, 0x6f, 0xa0, 0x52, 0xbe,
+	0x01, 0x6a, 0xc6, 0xe2, 0x84, 0x63, 0xd3, 0x64, 0xcb, 0x72, 0x50, 0x84, 0xe0, 0xd0, 0x6c, 0xb5,
+	0x14, 0x2a, 0x8a, 0x25, 0xab, 0xa1, 0x04, 0x7a, 0x20, 0xd5, 0x91, 0x5a, 0x3e, 0x90, 0xea, 0xb2,
+	0x5a, 0x3b, 0x90, 0xea, 0x8a, 0x0a, 0x07, 0x52, 0xbd, 0xa1, 0x36, 0x0f, 0xa4, 0xfa, 0xba, 0xaa,
+	0x92, 0xbc, 0x8b, 0x91, 0x42, 0xf7, 0x20, 0xc5, 0xb2, 0x25, 0xc5, 0x92, 0x59, 0x4e, 0xd1, 0xfb,
+	0x00, 0xf9, 0xf6, 0xd8, 0xad, 0xfa, 0x67, 0x67, 0x11, 0x15, 0xad, 0xf1, 0x06, 0x49, 0x24, 0xa6,
+	0x77, 0xa8, 0x37, 0x8b, 0xe7, 0xfc, 0x42, 0x9a, 0x24, 0x91, 0xf4, 0x05, 0xe0, 0xd5, 0x64, 0xe4,
+	0x6f, 0xf4, 0xd7, 0x78, 0x3b, 0xdf, 0x07, 0x25, 0x9f, 0x19, 0xd8, 0x5a, 0x2b, 0xe3, 0xe5, 0x6a,
+	0xcc, 0x64, 0xbc, 0xcc, 0x1d, 0x74, 0x0f, 0xd6, 0xc5, 0x20, 0x90, 0x17, 0x41, 0x96, 0x31, 0xe8,
+	0x8a, 0x8c, 0x29, 0xe7, 0x19, 0xf3, 0x1e, 0xd4, 0xd2, 0x73, 0x17, 0x43, 0xda, 0xdb, 0x57, 0xcd,
+	0x5a, 0x1c, 0x41, 0x52, 0xa4, 0x1e, 0xc1, 0x7a, 0xc1, 0x86, 0xdb, 0x00, 0x27, 0xfe, 0xc2, 0x3b,
+	0xb5, 0x92, 0x59, 0x1d, 0xed, 0x54, 0xc9, 0x92, 0x86, 0xf1, 0x71, 0xfc, 0xcf, 0x69, 0x98, 0x66,
+	0x30, 0x17, 0x98, 0x76, 0x11, 0x04, 0x34, 0x4c, 0x72, 0x58, 0x08, 0x39, 0x77, 0x69, 0x89, 0xbb,
+	0xee, 0xc0, 0x5b, 0x85, 0x4d, 0xf2, 0xc3, 0x5d, 0xe9, 0x38, 0xe5, 0x42, 0xc7, 0xc1, 0xef, 0x5f,
+	0x3e, 0xd7, 0xb7, 0x8b, 0x93, 0x6b, 0x16, 0x6f, 0xf9, 0x48, 0xff, 0x22, 0x41, 0xf3, 0xc7, 0x0b,
+	0x1a, 0x9e, 0xa7, 0x43, 0x35, 0xbe, 0x07, 0x72, 0x14, 0x5b, 0xf1, 0x22, 0x4a, 0x26, 0xa3, 0x76,
+	0x1e, 0x67, 0x05, 0xd8, 0x19, 0x72, 0x14, 0x49, 0xd0, 0xf8, 0x47, 0x00, 0x34, 0x0c, 0xfd, 0x70,
+	0xc2, 0xa7, 0xaa, 0x4b, 0xdf, 0x1d, 0xab, 0xbe, 0x26, 0x43, 0xf2, 0x99, 0x4a, 0xa1, 0xe9, 0x23,
+	0x3b, 0x0f, 0x2e, 0xf0, 0x53, 0x52, 0x88, 0x10, 0x70, 0x87, 0xf1, 0x09, 0x6d, 0x6f, 0xc6, 0x8f,
+	0x69, 0xa5, 0x40, 0x87, 0x5c, 0xbf, 0x6b, 0xc5, 0xd6, 0x5e, 0x89, 0x24, 0x28, 0x86, 0x7f, 0x4c,
+	0xa7, 0xb1, 0x1f, 0x6a, 0xd5, 0x22, 0xfe, 0x11, 0xd7, 0xa7, 0x78, 0x81, 0xe2, 0xf1, 0xa7, 0x96,
+	0x63, 0x85, 0x9a, 0x5c, 0xc4, 0x0f, 0xb9, 0x3e, 0x8b, 0xcf, 0x25, 0x86, 0x77, 0xad, 0x38, 0xb4,
+	0x9f, 0x68, 0xb5, 0x22, 0xfe, 0x88, 0xeb, 0x53, 0xbc, 0x40, 0xe1, 0x0d, 0xa8, 0x7f, 0x6e, 0x85,
+	0x9e, 0xed, 0xcd, 0x44, 0x8b, 0x51, 0x48, 0x26, 0xeb, 0xef, 0x82, 0x2c, 0x4e, 0x91, 0xbd, 0x07,
+	0x4c, 0x42, 0x06, 0x44, 0x8c, 0x7b, 0xc3, 0x71, 0xaf, 0x67, 0x0e, 0x87, 0x2a, 0x12, 0x2f, 0x05,
+	0xfd, 0xb7, 0x08, 0x94, 0xec, 0xc8, 0xd8, 0x1c, 0xd7, 0x1f, 0xf4, 0x4d, 0x01, 0x1d, 0xed, 0x1f,
+	0x99, 0x83, 0xf1, 0x48, 0x45, 0x6c, 0xa8, 0xeb, 0x19, 0xfd, 0x9e, 0x79, 0x68, 0xee, 0x8a, 0xe1,
+	0xd0, 0xfc, 0x89, 0xd9, 0x1b, 0x8f, 0xf6, 0x07, 0x7d, 0xb5, 0xc2, 0x8c, 0x5d, 0x63, 0x77, 0xb2,
+	0x6b, 0x8c, 0x0c, 0x55, 0x62, 0xd2, 0x3e, 0x9b, 0x27, 0xfb, 0xc6, 0xa1, 0x5a, 0xc5, 0xeb, 0xb0,
+	0x36, 0xee, 0x1b, 0x8f, 0x8c, 0xfd, 0x43, 0xa3, 0x7b, 0x68, 0xaa, 0x32, 0xf3, 0xed, 0x0f, 0x46,
+	0x93, 0x07, 0x83, 0x71, 0x7f, 0x57, 0xad, 0xb1, 0xc1, 0x92, 0x89, 0x46, 0xaf, 0x67, 0x1e, 0x8f,
+	0x38, 0xa4, 0x9e, 0xbc, 0xac, 0x64, 0x90, 0xd8, 0x8c, 0xac, 0x9b, 0x00, 0xf9, 0x5d, 0xac, 0x8e,
+	0xe0, 0xca, 0x75, 0x23, 0xdb, 0xe5, 0xee, 0xa0, 0xff, 0x02, 0x01, 0xe4, 0x77, 0x84, 0xef, 0xe5,
+	0x1f, 0x63, 0x62, 0x7c, 0xbc, 0x55, 0xbc, 0xca, 0xab, 0x3f, 0xc9, 0x7e, 0xb8, 0xf2, 0x69, 0x55,
+	0x2e, 0x96, 0xbb, 0x70, 0xfd, 0x37, 0x1f, 0x58, 0xfa, 0x04, 0x1a, 0xcb, 0xf1, 0x59, 0x1b, 0x14,
+	0x73, 0x3d, 0xe7, 0xa1, 0x90, 0x44, 0xfa, 0xdf, 0x67, 0xd3, 0x5f, 0x23, 0x58, 0x2f, 0xd0, 0xb8,
+	0x76, 0x91, 0x95, 0x96, 0x59, 0x7e, 0x8d, 0x96, 0x59, 0x5a, 0xaa, 0xef, 0xd7, 0x21, 0xc3, 0x2e,
+	0x2f, 0x4b, 0xf4, 0xab, 0xbf, 0x9f, 0x5e, 0xe7, 0xf2, 0xba, 0x00, 0x79, 0xfe, 0xe3, 0xef, 0x81,
+	0xbc, 0xf2, 0x3f, 0xe3, 0x56, 0xb1, 0x4a, 0x92, 0x3f, 0x1a, 0x82, 0x70, 0x82, 0xd5, 0x7f, 0x8f,
+	0xa0, 0xb1, 0x6c, 0xbe, 0xf6, 0x50, 0xfe, 0xfb, 0xef, 0xf4, 0xee, 0x4a, 0x52, 0x88, 0x77, 0xc0,
+	0x3b, 0xd7, 0x9d, 0x23, 0xff, 0x2e, 0xb9, 0x94, 0x17, 0xdd, 0x1f, 0x3c, 0x7b, 0xd1, 0x2e, 0x7d,
+	0xf1, 0xa2, 0x5d, 0x7a, 0xf5, 0xa2, 0x8d, 0x7e, 0x7e, 0xd1, 0x46, 0x7f, 0xbc, 0x68, 0xa3, 0xa7,
+	0x17, 0x6d, 0xf4, 0xec, 0xa2, 0x8d, 0xfe, 0x71, 0xd1, 0x46, 0x5f, 0x5e, 0xb4, 0x4b, 0xaf, 0x2e,
+	0xda, 0xe8, 0x37, 0x2f, 0xdb, 0xa5, 0x67, 0x2f, 0xdb, 0xa5, 0x2f, 0x5e, 0xb6, 0x4b, 0x9f, 0xd6,
+	0xf8, 0x5f, 0xa3, 0xe0, 0xe4, 0x44, 0xe6, 0xff, 0x7f, 0xde, 0xfb, 0xd7, 0x00, 0xdb, 0x82, 0xe3,
+	0x31, 0x47, 0x12, 0x00, 0x00,
 }
 
 func (x WriteRequest_SourceEnum) String() string {
@@ -2050,6 +2088,15 @@ func (this *TimeSeries) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.CreatedTimestamp != that1.CreatedTimestamp {
+		return false
+	}
+	if this.CreatedTimestampZeroSample != that1.CreatedTimestampZeroSample {
+		return false
+	}
+	if this.CreatedTimestampZeroHistogram != that1.CreatedTimestampZeroHistogram {
+		return false
+	}
 	return true
 }
 func (this *LabelPair) Equal(that interface{}) bool {
@@ -2658,6 +2705,14 @@ func (this *QueryResponse) Equal(that interface{}) bool {
 	} else if !this.Data.Equal(that1.Data) {
 		return false
 	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
 	return true
 }
 func (this *QueryResponse_String_) Equal(that interface{}) bool {
@@ -3019,7 +3074,7 @@ func (this *TimeSeries) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 11)
 	s = append(s, "&mimirpb.TimeSeries{")
 	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
 	if this.Samples != nil {
@@ -3043,6 +3098,9 @@ func (this *TimeSeries) GoString() string {
 		}
 		s = append(s, "Histograms: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
+	s = append(s, "CreatedTimestamp: "+fmt.Sprintf("%#v", this.CreatedTimestamp)+",\n")
+	s = append(s, "CreatedTimestampZeroSample: "+fmt.Sprintf("%#v", this.CreatedTimestampZeroSample)+",\n")
+	s = append(s, "CreatedTimestampZeroHistogram: "+fmt.Sprintf("%#v", this.CreatedTimestampZeroHistogram)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3272,7 +3330,7 @@ func (this *QueryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 11)
+	s := make([]string, 0, 12)
 	s = append(s, "&mimirpb.QueryResponse{")
 	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
 	s = append(s, "ErrorType: "+fmt.Sprintf("%#v", this.ErrorType)+",\n")
@@ -3280,6 +3338,7 @@ func (this *QueryResponse) GoString() string {
 	if this.Data != nil {
 		s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
 	}
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3543,6 +3602,31 @@ func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.CreatedTimestampZeroHistogram {
+		i--
+		if m.CreatedTimestampZeroHistogram {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.CreatedTimestampZeroSample {
+		i--
+		if m.CreatedTimestampZeroSample {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.CreatedTimestamp != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.CreatedTimestamp))
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.Histograms) > 0 {
 		for iNdEx := len(m.Histograms) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -4340,6 +4424,15 @@ func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintMimir(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
 	if m.Data != nil {
 		{
 			size := m.Data.Size()
@@ -4833,6 +4926,15 @@ func (m *TimeSeries) Size() (n int) {
 			n += 1 + l + sovMimir(uint64(l))
 		}
 	}
+	if m.CreatedTimestamp != 0 {
+		n += 1 + sovMimir(uint64(m.CreatedTimestamp))
+	}
+	if m.CreatedTimestampZeroSample {
+		n += 2
+	}
+	if m.CreatedTimestampZeroHistogram {
+		n += 2
+	}
 	return n
 }
 
@@ -5179,6 +5281,12 @@ func (m *QueryResponse) Size() (n int) {
 	if m.Data != nil {
 		n += m.Data.Size()
 	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovMimir(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -5422,6 +5530,9 @@ func (this *TimeSeries) String() string {
 		`Samples:` + repeatedStringForSamples + `,`,
 		`Exemplars:` + repeatedStringForExemplars + `,`,
 		`Histograms:` + repeatedStringForHistograms + `,`,
+		`CreatedTimestamp:` + fmt.Sprintf("%v", this.CreatedTimestamp) + `,`,
+		`CreatedTimestampZeroSample:` + fmt.Sprintf("%v", this.CreatedTimestampZeroSample) + `,`,
+		`CreatedTimestampZeroHistogram:` + fmt.Sprintf("%v", this.CreatedTimestampZeroHistogram) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -5656,6 +5767,7 @@ func (this *QueryResponse) String() string {
 		`ErrorType:` + fmt.Sprintf("%v", this.ErrorType) + `,`,
 		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
 		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -6190,6 +6302,65 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedTimestamp", wireType)
+			}
+			m.CreatedTimestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedTimestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedTimestampZeroSample", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CreatedTimestampZeroSample = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedTimestampZeroHistogram", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CreatedTimestampZeroHistogram = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMimir(dAtA[iNdEx:])
@@ -8393,6 +8564,38 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Data = &QueryResponse_Matrix{v}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMimir
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMimir(dAtA[iNdEx:])
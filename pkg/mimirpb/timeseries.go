@@ -157,6 +157,35 @@ func (p *PreallocTimeseries) DeleteExemplarByMovingLast(ix int) {
 	p.clearUnmarshalData()
 }
 
+// PrependSample inserts s before all the other samples of this timeseries. The caller is responsible
+// for guaranteeing that s is older than any sample already in the timeseries.
+func (p *PreallocTimeseries) PrependSample(s Sample) {
+	p.Samples = append(p.Samples, Sample{})
+	copy(p.Samples[1:], p.Samples)
+	p.Samples[0] = s
+	p.clearUnmarshalData()
+}
+
+// PrependHistogram inserts h before all the other histograms of this timeseries. The caller is responsible
+// for guaranteeing that h is older than any histogram already in the timeseries.
+func (p *PreallocTimeseries) PrependHistogram(h Histogram) {
+	p.Histograms = append(p.Histograms, Histogram{})
+	copy(p.Histograms[1:], p.Histograms)
+	p.Histograms[0] = h
+	p.clearUnmarshalData()
+}
+
+// SetCreatedTimestampZeroSamples sets whether the first sample and the first histogram of this timeseries are
+// the zero samples synthesized at its created timestamp.
+func (p *PreallocTimeseries) SetCreatedTimestampZeroSamples(sample, histogram bool) {
+	if p.CreatedTimestampZeroSample == sample && p.CreatedTimestampZeroHistogram == histogram {
+		return
+	}
+	p.CreatedTimestampZeroSample = sample
+	p.CreatedTimestampZeroHistogram = histogram
+	p.clearUnmarshalData()
+}
+
 // clearUnmarshalData removes cached unmarshalled version of the message.
 func (p *PreallocTimeseries) clearUnmarshalData() {
 	p.marshalledData = nil
@@ -429,6 +458,9 @@ func ReuseTimeseries(ts *TimeSeries) {
 	ts.Labels = ts.Labels[:0]
 	ts.Samples = ts.Samples[:0]
 	ts.Histograms = ts.Histograms[:0]
+	ts.CreatedTimestamp = 0
+	ts.CreatedTimestampZeroSample = false
+	ts.CreatedTimestampZeroHistogram = false
 
 	ClearExemplars(ts)
 	timeSeriesPool.Put(ts)
@@ -496,6 +528,9 @@ func DeepCopyTimeseries(dst, src PreallocTimeseries, keepExemplars bool) Preallo
 		dstTs.Samples = dstTs.Samples[:len(srcTs.Samples)]
 	}
 	copy(dstTs.Samples, srcTs.Samples)
+	dstTs.CreatedTimestamp = srcTs.CreatedTimestamp
+	dstTs.CreatedTimestampZeroSample = srcTs.CreatedTimestampZeroSample
+	dstTs.CreatedTimestampZeroHistogram = srcTs.CreatedTimestampZeroHistogram
 
 	// Prepare the slice of exemplars.
 	if keepExemplars {
@@ -415,6 +415,32 @@ func TestPreallocTimeseries_SetLabels(t *testing.T) {
 	require.Nil(t, p.marshalledData)
 }
 
+func TestPreallocTimeseries_PrependSample(t *testing.T) {
+	p := PreallocTimeseries{
+		TimeSeries: &TimeSeries{
+			Samples: []Sample{{TimestampMs: 20, Value: 2}, {TimestampMs: 30, Value: 3}},
+		},
+		marshalledData: []byte{1, 2, 3},
+	}
+	p.PrependSample(Sample{TimestampMs: 10, Value: 0})
+
+	require.Equal(t, []Sample{{TimestampMs: 10, Value: 0}, {TimestampMs: 20, Value: 2}, {TimestampMs: 30, Value: 3}}, p.Samples)
+	require.Nil(t, p.marshalledData)
+}
+
+func TestPreallocTimeseries_PrependHistogram(t *testing.T) {
+	p := PreallocTimeseries{
+		TimeSeries: &TimeSeries{
+			Histograms: []Histogram{{Timestamp: 20}, {Timestamp: 30}},
+		},
+		marshalledData: []byte{1, 2, 3},
+	}
+	p.PrependHistogram(Histogram{Timestamp: 10})
+
+	require.Equal(t, []Histogram{{Timestamp: 10}, {Timestamp: 20}, {Timestamp: 30}}, p.Histograms)
+	require.Nil(t, p.marshalledData)
+}
+
 func BenchmarkPreallocTimeseries_SortLabelsIfNeeded(b *testing.B) {
 	bcs := []int{10, 100, 1_000, 10_000, 100_000, 1_000_000}
 
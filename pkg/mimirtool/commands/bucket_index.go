@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+)
+
+// BucketIndexCommand is the kingpin command to inspect the bucket index of tenants.
+type BucketIndexCommand struct {
+	cfg           bucket.Config
+	bucketConfig  string
+	tenants       []string
+	topLabelNames int
+	logger        log.Logger
+}
+
+// bucketIndexTenantStats summarises the stats of the blocks in the bucket index of a tenant.
+type bucketIndexTenantStats struct {
+	tenant string
+	blocks int
+
+	// blocksWithoutStats is the number of blocks whose stats are unknown, because they have been
+	// uploaded before the stats have been recorded in the bucket index.
+	blocksWithoutStats int
+
+	numSeries      uint64
+	numChunks      uint64
+	numSamples     uint64
+	indexSizeBytes int64
+
+	// topLabelNames is the list of label names with the highest number of values in a single block.
+	topLabelNames []block.LabelNameCardinality
+}
+
+// Register is used to register the command to a parent command.
+func (c *BucketIndexCommand) Register(app *kingpin.Application, _ EnvVarNames) {
+	bucketIndexCmd := app.Command("bucket-index", "Inspect the bucket index of tenants.")
+
+	statsCmd := bucketIndexCmd.Command("stats", "Summarise the stats of the blocks in the bucket index, per tenant.").Action(c.stats)
+	statsCmd.Flag("bucket-config", "The CLI args to configure a storage bucket. Refer to the bucket-validation command help for details.").Required().StringVar(&c.bucketConfig)
+	statsCmd.Flag("tenant", "Tenant to summarise. Can be repeated. If not set, all the tenants in the bucket are summarised.").StringsVar(&c.tenants)
+	statsCmd.Flag("top-label-names", "Number of label names with the highest cardinality to display per tenant.").Default("5").IntVar(&c.topLabelNames)
+}
+
+func (c *BucketIndexCommand) stats(_ *kingpin.ParseContext) error {
+	c.logger = log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	ctx := context.Background()
+
+	if err := c.parseBucketConfig(); err != nil {
+		return errors.Wrap(err, "error when parsing bucket config")
+	}
+
+	bkt, err := bucket.NewClient(ctx, c.cfg, "bucket-index", c.logger, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the bucket client")
+	}
+
+	tenants := c.tenants
+	if len(tenants) == 0 {
+		tenants, err = mimir_tsdb.ListUsers(ctx, bkt)
+		if err != nil {
+			return errors.Wrap(err, "failed to list tenants")
+		}
+		slices.Sort(tenants)
+	}
+
+	stats := make([]bucketIndexTenantStats, 0, len(tenants))
+	for _, tenant := range tenants {
+		idx, err := bucketindex.ReadIndex(ctx, bkt, tenant, nil, c.logger)
+		if errors.Is(err, bucketindex.ErrIndexNotFound) {
+			level.Warn(c.logger).Log("msg", "skipped tenant because the bucket index doesn't exist", "tenant", tenant)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the bucket index of tenant %s", tenant)
+		}
+
+		stats = append(stats, summariseBucketIndex(tenant, idx, c.topLabelNames))
+	}
+
+	return printBucketIndexStats(os.Stdout, stats)
+}
+
+func (c *BucketIndexCommand) parseBucketConfig() error {
+	fs := flag.NewFlagSet("bucket-config", flag.ContinueOnError)
+	c.cfg.RegisterFlags(fs)
+	if err := fs.Parse(strings.Split(c.bucketConfig, " ")); err != nil {
+		return err
+	}
+
+	return c.cfg.Validate()
+}
+
+// summariseBucketIndex returns the stats of the blocks in the bucket index. The number of series, chunks
+// and samples is summed across blocks, so series in overlapping blocks are counted once per block.
+func summariseBucketIndex(tenant string, idx *bucketindex.Index, topLabelNames int) bucketIndexTenantStats {
+	stats := bucketIndexTenantStats{tenant: tenant, blocks: len(idx.Blocks)}
+	maxValues := map[string]int{}
+
+	for _, b := range idx.Blocks {
+		if b.NumSeries == 0 && b.IndexSizeBytes == 0 && len(b.TopLabelNames) == 0 {
+			stats.blocksWithoutStats++
+			continue
+		}
+
+		stats.numSeries += b.NumSeries
+		stats.numChunks += b.NumChunks
+		stats.numSamples += b.NumSamples
+		stats.indexSizeBytes += b.IndexSizeBytes
+
+		for _, l := range b.TopLabelNames {
+			if l.Values > maxValues[l.Name] {
+				maxValues[l.Name] = l.Values
+			}
+		}
+	}
+
+	for name, values := range maxValues {
+		stats.topLabelNames = append(stats.topLabelNames, block.LabelNameCardinality{Name: name, Values: values})
+	}
+	slices.SortFunc(stats.topLabelNames, func(a, b block.LabelNameCardinality) bool {
+		if a.Values != b.Values {
+			return a.Values > b.Values
+		}
+		return a.Name < b.Name
+	})
+	if len(stats.topLabelNames) > topLabelNames {
+		stats.topLabelNames = stats.topLabelNames[:topLabelNames]
+	}
+
+	return stats
+}
+
+func printBucketIndexStats(w io.Writer, stats []bucketIndexTenantStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TENANT\tBLOCKS\tBLOCKS WITHOUT STATS\tSERIES\tCHUNKS\tSAMPLES\tINDEX SIZE BYTES\tTOP LABEL NAMES")
+
+	for _, s := range stats {
+		topLabelNames := make([]string, 0, len(s.topLabelNames))
+		for _, l := range s.topLabelNames {
+			topLabelNames = append(topLabelNames, fmt.Sprintf("%s=%d", l.Name, l.Values))
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", s.tenant, s.blocks, s.blocksWithoutStats, s.numSeries, s.numChunks, s.numSamples, s.indexSizeBytes, strings.Join(topLabelNames, ", "))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/oklog/ulid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+)
+
+func TestSummariseBucketIndex(t *testing.T) {
+	idx := &bucketindex.Index{
+		Version: bucketindex.IndexVersion3,
+		Blocks: bucketindex.Blocks{
+			{
+				ID:             ulid.MustNew(1, nil),
+				NumSeries:      100,
+				NumChunks:      200,
+				NumSamples:     2000,
+				IndexSizeBytes: 1000,
+				TopLabelNames: []block.LabelNameCardinality{
+					{Name: "pod", Values: 80},
+					{Name: "__name__", Values: 10},
+					{Name: "job", Values: 2},
+				},
+			},
+			{
+				ID:             ulid.MustNew(2, nil),
+				NumSeries:      50,
+				NumChunks:      100,
+				NumSamples:     1000,
+				IndexSizeBytes: 500,
+				TopLabelNames: []block.LabelNameCardinality{
+					{Name: "pod", Values: 40},
+					{Name: "__name__", Values: 20},
+				},
+			},
+			{
+				// Block without stats.
+				ID: ulid.MustNew(3, nil),
+			},
+		},
+	}
+
+	stats := summariseBucketIndex("user-1", idx, 2)
+	assert.Equal(t, bucketIndexTenantStats{
+		tenant:             "user-1",
+		blocks:             3,
+		blocksWithoutStats: 1,
+		numSeries:          150,
+		numChunks:          300,
+		numSamples:         3000,
+		indexSizeBytes:     1500,
+		topLabelNames: []block.LabelNameCardinality{
+			{Name: "pod", Values: 80},
+			{Name: "__name__", Values: 20},
+		},
+	}, stats)
+
+	out := bytes.Buffer{}
+	require.NoError(t, printBucketIndexStats(&out, []bucketIndexTenantStats{stats}))
+	assert.Equal(t, ""+
+		"TENANT  BLOCKS  BLOCKS WITHOUT STATS  SERIES  CHUNKS  SAMPLES  INDEX SIZE BYTES  TOP LABEL NAMES\n"+
+		"user-1  3       1                     150     300     3000     1500              pod=80, __name__=20\n", out.String())
+}
@@ -281,6 +281,10 @@ func (i *InspectedEntry) decodeValue(decoder decoder) (Value, error) {
 	switch i.FieldType {
 	case "duration":
 		value := decoded.AsInterface().(**duration)
+		if *value == nil {
+			// The value is empty, like the default value of a field without a CLI flag.
+			return Value{}, nil
+		}
 		return DurationValue(time.Duration(**value)), err
 	case "list of strings":
 		return InterfaceValue(*decoded.AsInterface().(*stringSlice)), nil
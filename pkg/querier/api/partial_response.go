@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/dskit/tenant"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	// PartialResponseParam is the request parameter used to enable or disable partial responses for a single query,
+	// overriding the per-tenant configuration.
+	PartialResponseParam = "partial_response"
+
+	cacheControlHeader = "Cache-Control"
+	noStoreValue       = "no-store"
+)
+
+type contextKey int
+
+var partialResponseCtxKey = contextKey(0)
+
+// partialResponseTracker tracks whether partial responses are enabled for a query,
+// and whether the query response is partial.
+type partialResponseTracker struct {
+	enabled bool
+	partial atomic.Bool
+}
+
+// ContextWithPartialResponse returns a context with partial responses enabled or disabled.
+func ContextWithPartialResponse(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, partialResponseCtxKey, &partialResponseTracker{enabled: enabled})
+}
+
+// IsPartialResponseEnabled returns whether partial responses are enabled in the context.
+func IsPartialResponseEnabled(ctx context.Context) bool {
+	t, ok := ctx.Value(partialResponseCtxKey).(*partialResponseTracker)
+	return ok && t.enabled
+}
+
+// MarkPartialResponse records in the context that the query response is partial.
+func MarkPartialResponse(ctx context.Context) {
+	if t, ok := ctx.Value(partialResponseCtxKey).(*partialResponseTracker); ok {
+		t.partial.Store(true)
+	}
+}
+
+// IsPartialResponse returns whether the query response has been marked as partial in the context.
+func IsPartialResponse(ctx context.Context) bool {
+	t, ok := ctx.Value(partialResponseCtxKey).(*partialResponseTracker)
+	return ok && t.partial.Load()
+}
+
+// PartialResponseLimits is the interface of the limits used by PartialResponseMiddleware.
+type PartialResponseLimits interface {
+	QueryPartialResponseEnabled(userID string) bool
+}
+
+// PartialResponseMiddleware enables partial responses for the request, based on the PartialResponseParam
+// request parameter or, if not set, on the per-tenant configuration. If the response is partial, the
+// middleware adds a "Cache-Control: no-store" header to the response so that it's never stored in
+// the query-frontend results cache.
+type PartialResponseMiddleware struct {
+	limits PartialResponseLimits
+}
+
+// NewPartialResponseMiddleware makes a new PartialResponseMiddleware.
+func NewPartialResponseMiddleware(limits PartialResponseLimits) PartialResponseMiddleware {
+	return PartialResponseMiddleware{limits: limits}
+}
+
+// Wrap implements middleware.Interface.
+func (m PartialResponseMiddleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enabled, err := m.isEnabled(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := ContextWithPartialResponse(r.Context(), enabled)
+		next.ServeHTTP(&partialResponseWriter{ResponseWriter: w, ctx: ctx}, r.WithContext(ctx))
+	})
+}
+
+func (m PartialResponseMiddleware) isEnabled(r *http.Request) (bool, error) {
+	if value := r.FormValue(PartialResponseParam); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid parameter %q: %s", PartialResponseParam, err.Error())
+		}
+		return enabled, nil
+	}
+
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		// Let the downstream handler fail the request, if it requires a tenant.
+		return false, nil
+	}
+
+	return validation.AllTrueBooleansPerTenant(tenantIDs, m.limits.QueryPartialResponseEnabled), nil
+}
+
+// partialResponseWriter adds the "Cache-Control: no-store" header to partial responses.
+type partialResponseWriter struct {
+	http.ResponseWriter
+
+	ctx         context.Context
+	wroteHeader bool
+}
+
+func (w *partialResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if IsPartialResponse(w.ctx) {
+			w.Header().Set(cacheControlHeader, noStoreValue)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *partialResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
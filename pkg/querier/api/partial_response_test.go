@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+)
+
+type partialResponseLimitsMock map[string]bool
+
+func (m partialResponseLimitsMock) QueryPartialResponseEnabled(userID string) bool {
+	return m[userID]
+}
+
+func TestPartialResponseMiddleware(t *testing.T) {
+	limits := partialResponseLimitsMock{"enabled": true, "disabled": false}
+
+	tests := map[string]struct {
+		tenantID           string
+		param              string
+		markPartial        bool
+		expectedStatusCode int
+		expectedEnabled    bool
+		expectedNoStore    bool
+	}{
+		"enabled for the tenant": {
+			tenantID:           "enabled",
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    true,
+		},
+		"disabled for the tenant": {
+			tenantID:           "disabled",
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    false,
+		},
+		"multiple tenants, enabled only for some of them": {
+			tenantID:           "enabled|disabled",
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    false,
+		},
+		"enabled by the request parameter": {
+			tenantID:           "disabled",
+			param:              "true",
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    true,
+		},
+		"disabled by the request parameter": {
+			tenantID:           "enabled",
+			param:              "false",
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    false,
+		},
+		"invalid request parameter": {
+			tenantID:           "enabled",
+			param:              "foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"partial response is not cacheable": {
+			tenantID:           "enabled",
+			markPartial:        true,
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    true,
+			expectedNoStore:    true,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var actualEnabled bool
+			handler := NewPartialResponseMiddleware(limits).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actualEnabled = IsPartialResponseEnabled(r.Context())
+				if testData.markPartial {
+					MarkPartialResponse(r.Context())
+				}
+				_, _ = w.Write([]byte("{}"))
+			}))
+
+			target := "/api/v1/query"
+			if testData.param != "" {
+				target += "?" + PartialResponseParam + "=" + testData.param
+			}
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), testData.tenantID))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, testData.expectedStatusCode, rec.Code)
+			assert.Equal(t, testData.expectedEnabled, actualEnabled)
+			if testData.expectedNoStore {
+				assert.Equal(t, noStoreValue, rec.Header().Get(cacheControlHeader))
+			} else {
+				assert.Empty(t, rec.Header().Get(cacheControlHeader))
+			}
+		})
+	}
+}
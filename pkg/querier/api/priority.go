@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"net/http"
+)
+
+const (
+	// QueryPriorityHeader is the HTTP header used to set the priority class of a query. If not set,
+	// the priority class is inferred from the source of the query.
+	QueryPriorityHeader = "X-Mimir-Query-Priority"
+
+	// grafanaDashboardUIDHeader is the HTTP header set by Grafana to the queries run by dashboard panels.
+	grafanaDashboardUIDHeader = "X-Dashboard-Uid"
+)
+
+// QueryPriority is the priority class of a query.
+type QueryPriority int
+
+const (
+	// QueryPriorityAdHoc is the priority class of ad-hoc queries, for example run while exploring data.
+	// It's the default priority class.
+	QueryPriorityAdHoc QueryPriority = iota
+
+	// QueryPriorityDashboard is the priority class of queries run by dashboards.
+	QueryPriorityDashboard
+
+	// QueryPriorityRule is the priority class of queries run to evaluate alerting and recording rules.
+	QueryPriorityRule
+)
+
+// QueryPriorities is the list of all query priority classes.
+var QueryPriorities = []QueryPriority{QueryPriorityAdHoc, QueryPriorityDashboard, QueryPriorityRule}
+
+func (p QueryPriority) String() string {
+	switch p {
+	case QueryPriorityDashboard:
+		return "dashboard"
+	case QueryPriorityRule:
+		return "rule"
+	default:
+		return "adhoc"
+	}
+}
+
+// ParseQueryPriority returns the query priority class with the input name, and false if there's no such class.
+func ParseQueryPriority(name string) (QueryPriority, bool) {
+	for _, p := range QueryPriorities {
+		if p.String() == name {
+			return p, true
+		}
+	}
+	return QueryPriorityAdHoc, false
+}
+
+// QueryPriorityFromHeader returns the priority class of the query with the input HTTP headers. The priority
+// class is read from the QueryPriorityHeader if set to a valid class, and is otherwise inferred from the source
+// of the query: queries run by Grafana dashboards are in the dashboard class, while any other query is ad-hoc.
+func QueryPriorityFromHeader(h http.Header) QueryPriority {
+	if p, ok := ParseQueryPriority(h.Get(QueryPriorityHeader)); ok {
+		return p
+	}
+	if h.Get(grafanaDashboardUIDHeader) != "" {
+		return QueryPriorityDashboard
+	}
+	return QueryPriorityAdHoc
+}
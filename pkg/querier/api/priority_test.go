@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPriorityFromHeader(t *testing.T) {
+	tests := map[string]struct {
+		header   http.Header
+		expected QueryPriority
+	}{
+		"no header": {
+			header:   http.Header{},
+			expected: QueryPriorityAdHoc,
+		},
+		"explicit priority class": {
+			header:   http.Header{"X-Mimir-Query-Priority": []string{"rule"}},
+			expected: QueryPriorityRule,
+		},
+		"query run by a Grafana dashboard": {
+			header:   http.Header{"X-Dashboard-Uid": []string{"abc"}},
+			expected: QueryPriorityDashboard,
+		},
+		"explicit priority class takes precedence over the source of the query": {
+			header:   http.Header{"X-Mimir-Query-Priority": []string{"adhoc"}, "X-Dashboard-Uid": []string{"abc"}},
+			expected: QueryPriorityAdHoc,
+		},
+		"unknown priority class is ignored": {
+			header:   http.Header{"X-Mimir-Query-Priority": []string{"urgent"}, "X-Dashboard-Uid": []string{"abc"}},
+			expected: QueryPriorityDashboard,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testData.expected, QueryPriorityFromHeader(testData.header))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import "context"
+
+var queryEngineQuerierCtxKey = contextKey(1)
+
+// ContextForQueryEngineQuerier returns a context marking the queriers created with it as used by a query
+// engine which selects the labels of the series of a query with the "series" function hint, before selecting
+// the series with their samples. Selects with the "series" function hint from these queriers aren't series API
+// requests, so they're not subject to the label queries limits.
+func ContextForQueryEngineQuerier(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryEngineQuerierCtxKey, true)
+}
+
+// IsQueryEngineQuerierContext returns whether ctx has been returned by ContextForQueryEngineQuerier.
+func IsQueryEngineQuerierContext(ctx context.Context) bool {
+	v, _ := ctx.Value(queryEngineQuerierCtxKey).(bool)
+	return v
+}
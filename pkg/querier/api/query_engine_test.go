@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextForQueryEngineQuerier(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsQueryEngineQuerierContext(ctx))
+	assert.True(t, IsQueryEngineQuerierContext(ContextForQueryEngineQuerier(ctx)))
+
+	// The partial response tracking is unaffected.
+	ctx = ContextWithPartialResponse(ContextForQueryEngineQuerier(ctx), true)
+	assert.True(t, IsQueryEngineQuerierContext(ctx))
+	assert.True(t, IsPartialResponseEnabled(ctx))
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"net/http"
+)
+
+// RemoteClusterReadHeader is set on the remote read requests sent to remote clusters. Mimir serves
+// these requests only from the local cluster data, to avoid loops between clusters querying each other.
+const RemoteClusterReadHeader = "X-Mimir-Remote-Cluster-Read"
+
+// StripRemoteClusterReadHeader returns a handler removing the RemoteClusterReadHeader from the requests,
+// for the endpoints which aren't queried by remote clusters.
+func StripRemoteClusterReadHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(RemoteClusterReadHeader) != "" {
+			r = r.Clone(r.Context())
+			r.Header.Del(RemoteClusterReadHeader)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
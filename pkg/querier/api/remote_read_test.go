@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripRemoteClusterReadHeader(t *testing.T) {
+	var received http.Header
+	handler := StripRemoteClusterReadHeader(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/prometheus/api/v1/query", nil)
+	req.Header.Set(RemoteClusterReadHeader, "true")
+	req.Header.Set("X-Scope-OrgID", "team-a")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, received.Get(RemoteClusterReadHeader))
+	assert.Equal(t, "team-a", received.Get("X-Scope-OrgID"))
+
+	// The input request is not modified.
+	assert.Equal(t, "true", req.Header.Get(RemoteClusterReadHeader))
+}
@@ -26,14 +26,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/thanos-io/objstore"
+	"github.com/weaveworks/common/httpgrpc"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 	grpc_metadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/bucket"
 	"github.com/grafana/mimir/pkg/storage/series"
@@ -106,6 +110,7 @@ type blocksStoreQueryableMetrics struct {
 	blocksFound                                       prometheus.Counter
 	blocksQueried                                     prometheus.Counter
 	blocksWithCompactorShardButIncompatibleQueryShard prometheus.Counter
+	storeGatewayTooBusyRejections                     prometheus.Counter
 }
 
 func newBlocksStoreQueryableMetrics(reg prometheus.Registerer) *blocksStoreQueryableMetrics {
@@ -135,6 +140,10 @@ func newBlocksStoreQueryableMetrics(reg prometheus.Registerer) *blocksStoreQuery
 			Name: "cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total",
 			Help: "Blocks that couldn't be checked for query and compactor sharding optimization due to incompatible shard counts.",
 		}),
+		storeGatewayTooBusyRejections: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_querier_storegateway_too_busy_rejections_total",
+			Help: "Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.",
+		}),
 	}
 }
 
@@ -303,6 +312,24 @@ func (q *BlocksStoreQueryable) Querier(ctx context.Context, mint, maxt int64) (s
 		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
 	}
 
+	return q.newBlocksStoreQuerier(ctx, mint, maxt)
+}
+
+// ExemplarQuerier returns a new storage.ExemplarQuerier querying the exemplars stored alongside the blocks.
+func (q *BlocksStoreQueryable) ExemplarQuerier(ctx context.Context) (storage.ExemplarQuerier, error) {
+	if s := q.State(); s != services.Running {
+		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
+	}
+
+	querier, err := q.newBlocksStoreQuerier(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blocksStoreExemplarQuerier{querier: querier}, nil
+}
+
+func (q *BlocksStoreQueryable) newBlocksStoreQuerier(ctx context.Context, mint, maxt int64) (*blocksStoreQuerier, error) {
 	userID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -341,6 +368,28 @@ type blocksStoreQuerier struct {
 	queryStoreAfter time.Duration
 }
 
+// blocksStoreExemplarQuerier is a storage.ExemplarQuerier querying the exemplars stored alongside the blocks.
+type blocksStoreExemplarQuerier struct {
+	querier *blocksStoreQuerier
+}
+
+// Select implements storage.ExemplarQuerier interface.
+func (q *blocksStoreExemplarQuerier) Select(start, end int64, matchers ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	series, _, err := q.querier.selectExemplars(start, end, matchers...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]exemplar.QueryResult, 0, len(series))
+	for _, ts := range series {
+		ret = append(ret, exemplar.QueryResult{
+			SeriesLabels: mimirpb.FromLabelAdaptersToLabels(ts.Labels),
+			Exemplars:    mimirpb.FromExemplarProtosToExemplars(ts.Exemplars),
+		})
+	}
+	return ret, nil
+}
+
 // Select implements storage.Querier interface.
 // The bool passed is ignored because the series is always sorted.
 func (q *blocksStoreQuerier) Select(_ bool, sp *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
@@ -381,10 +430,11 @@ func (q *blocksStoreQuerier) LabelNames(matchers ...*labels.Matcher) ([]string,
 		return queriedBlocks, nil
 	}
 
-	err := q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, nil, queryFunc)
+	partialWarnings, err := q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, nil, queryFunc)
 	if err != nil {
 		return nil, nil, err
 	}
+	resWarnings = append(resWarnings, partialWarnings...)
 
 	return util.MergeSlices(resNameSets...), resWarnings, nil
 }
@@ -422,10 +472,11 @@ func (q *blocksStoreQuerier) LabelValues(name string, matchers ...*labels.Matche
 		return queriedBlocks, nil
 	}
 
-	err := q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, nil, queryFunc)
+	partialWarnings, err := q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, nil, queryFunc)
 	if err != nil {
 		return nil, nil, err
 	}
+	resWarnings = append(resWarnings, partialWarnings...)
 
 	return util.MergeSlices(resValueSets...), resWarnings, nil
 }
@@ -434,6 +485,38 @@ func (q *blocksStoreQuerier) Close() error {
 	return nil
 }
 
+// selectExemplars returns the exemplars within the closed interval [minT, maxT], of the series
+// matching any of the input matchers sets, sorted by series labels.
+func (q *blocksStoreQuerier) selectExemplars(minT, maxT int64, matchers ...[]*labels.Matcher) ([]mimirpb.TimeSeries, storage.Warnings, error) {
+	spanLog, spanCtx := spanlogger.NewWithLogger(q.ctx, q.logger, "blocksStoreQuerier.selectExemplars")
+	defer spanLog.Span.Finish()
+
+	var (
+		resSets     [][]mimirpb.TimeSeries
+		resWarnings = storage.Warnings(nil)
+	)
+
+	queryFunc := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error) {
+		sets, warnings, queriedBlocks, err := q.fetchExemplarsFromStore(spanCtx, clients, minT, maxT, matchers)
+		if err != nil {
+			return nil, err
+		}
+
+		resSets = append(resSets, sets...)
+		resWarnings = append(resWarnings, warnings...)
+
+		return queriedBlocks, nil
+	}
+
+	partialWarnings, err := q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, nil, queryFunc)
+	if err != nil {
+		return nil, nil, err
+	}
+	resWarnings = append(resWarnings, partialWarnings...)
+
+	return block.MergeExemplars(resSets...), resWarnings, nil
+}
+
 func (q *blocksStoreQuerier) selectSorted(sp *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
 	spanLog, spanCtx := spanlogger.NewWithLogger(q.ctx, q.logger, "blocksStoreQuerier.selectSorted")
 	defer spanLog.Span.Finish()
@@ -465,10 +548,11 @@ func (q *blocksStoreQuerier) selectSorted(sp *storage.SelectHints, matchers ...*
 		return queriedBlocks, nil
 	}
 
-	err = q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, shard, queryFunc)
+	partialWarnings, err := q.queryWithConsistencyCheck(spanCtx, spanLog, minT, maxT, shard, queryFunc)
 	if err != nil {
 		return storage.ErrSeriesSet(err)
 	}
+	resWarnings = append(resWarnings, partialWarnings...)
 
 	// If this was a streaming call, start fetching streaming chunks here.
 	for _, ss := range streamStarters {
@@ -484,8 +568,11 @@ func (q *blocksStoreQuerier) selectSorted(sp *storage.SelectHints, matchers ...*
 		resWarnings)
 }
 
+// queryWithConsistencyCheck queries the store-gateways for all blocks in the time range, retrying to fetch missing blocks
+// from other store-gateways. If some blocks can't be queried from any store-gateway, it returns an error or, if partial
+// responses are enabled for the query, a warning listing the missing blocks.
 func (q *blocksStoreQuerier) queryWithConsistencyCheck(ctx context.Context, logger log.Logger, minT, maxT int64, shard *sharding.ShardSelector,
-	queryFunc func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error)) error {
+	queryFunc func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error)) (storage.Warnings, error) {
 	// If queryStoreAfter is enabled, we do manipulate the query maxt to query samples up until
 	// now - queryStoreAfter, because the most recent time range is covered by ingesters. This
 	// optimization is particularly important for the blocks storage because can be used to skip
@@ -502,20 +589,20 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(ctx context.Context, logg
 		if maxT < minT {
 			q.metrics.storesHit.Observe(0)
 			level.Debug(logger).Log("msg", "empty query time range after max time manipulation")
-			return nil
+			return nil, nil
 		}
 	}
 
 	// Find the list of blocks we need to query given the time range.
 	knownBlocks, knownDeletionMarks, err := q.finder.GetBlocks(ctx, q.userID, minT, maxT)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(knownBlocks) == 0 {
 		q.metrics.storesHit.Observe(0)
 		level.Debug(logger).Log("msg", "no blocks found")
-		return nil
+		return nil, nil
 	}
 
 	q.metrics.blocksFound.Add(float64(len(knownBlocks)))
@@ -556,7 +643,13 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(ctx context.Context, logg
 				break
 			}
 
-			return err
+			// If partial responses are enabled, the blocks we can't get clients for are just reported as missing.
+			if querierapi.IsPartialResponseEnabled(ctx) {
+				level.Warn(logger).Log("msg", "unable to get store-gateway clients to fetch blocks, returning a partial response", "err", err)
+				break
+			}
+
+			return nil, err
 		}
 		level.Debug(logger).Log("msg", "found store-gateway instances to query", "num instances", len(clients), "attempt", attempt)
 
@@ -564,7 +657,7 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(ctx context.Context, logg
 		// are only meant to cover missing blocks.
 		queriedBlocks, err := queryFunc(clients, minT, maxT)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		level.Debug(logger).Log("msg", "received series from all store-gateways", "queried blocks", strings.Join(convertULIDsToString(queriedBlocks), " "))
 
@@ -585,7 +678,7 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(ctx context.Context, logg
 			q.metrics.storesHit.Observe(float64(len(touchedStores)))
 			q.metrics.refetches.Observe(float64(attempt - 1))
 
-			return nil
+			return nil, nil
 		}
 
 		level.Debug(logger).Log("msg", "consistency check failed", "attempt", attempt, "missing blocks", strings.Join(convertULIDsToString(missingBlocks), " "))
@@ -595,8 +688,15 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(ctx context.Context, logg
 	}
 
 	// We've not been able to query all expected blocks after all retries.
+	err = newStoreConsistencyCheckFailedError(remainingBlocks)
+	if querierapi.IsPartialResponseEnabled(ctx) {
+		level.Warn(util_log.WithContext(ctx, logger)).Log("msg", "failed consistency check, returning a partial response", "err", err)
+		querierapi.MarkPartialResponse(ctx)
+		return storage.Warnings{err}, nil
+	}
+
 	level.Warn(util_log.WithContext(ctx, logger)).Log("msg", "failed consistency check", "err", err)
-	return newStoreConsistencyCheckFailedError(remainingBlocks)
+	return nil, err
 }
 
 func newStoreConsistencyCheckFailedError(remainingBlocks []ulid.ULID) error {
@@ -707,6 +807,7 @@ func (q *blocksStoreQuerier) fetchSeriesFromStores(ctx context.Context, sp *stor
 		queriedBlocks = []ulid.ULID(nil)
 		spanLog       = spanlogger.FromContext(ctx, q.logger)
 		queryLimiter  = limiter.QueryLimiterFromContextWithFallback(ctx)
+		memoryTracker = limiter.MemoryConsumptionTrackerFromContextWithFallback(ctx)
 		reqStats      = stats.FromContext(ctx)
 		streamReaders []*storeGatewayStreamReader
 		streams       []storegatewaypb.StoreGateway_SeriesClient
@@ -730,6 +831,7 @@ func (q *blocksStoreQuerier) fetchSeriesFromStores(ctx context.Context, sp *stor
 				return errors.Wrapf(err, "failed to create series request")
 			}
 
+			start := time.Now()
 			stream, err := c.Series(reqCtx, req)
 			if err == nil {
 				mtx.Lock()
@@ -742,7 +844,7 @@ func (q *blocksStoreQuerier) fetchSeriesFromStores(ctx context.Context, sp *stor
 					return err
 				}
 
-				level.Warn(spanLog).Log("msg", "failed to fetch series", "remote", c.RemoteAddress(), "err", err)
+				q.logStoreGatewayError(spanLog, "failed to fetch series", c.RemoteAddress(), err)
 				return nil
 			}
 
@@ -769,7 +871,7 @@ func (q *blocksStoreQuerier) fetchSeriesFromStores(ctx context.Context, sp *stor
 						return err
 					}
 
-					level.Warn(spanLog).Log("msg", "failed to receive series", "remote", c.RemoteAddress(), "err", err)
+					q.logStoreGatewayError(spanLog, "failed to receive series", c.RemoteAddress(), err)
 					return nil
 				}
 
@@ -790,6 +892,10 @@ func (q *blocksStoreQuerier) fetchSeriesFromStores(ctx context.Context, sp *stor
 					if chunkLimitErr := queryLimiter.AddChunks(chunksCount); chunkLimitErr != nil {
 						return chunkLimitErr
 					}
+					// The chunks are held in memory by the series iterators until the query completes.
+					if memoryLimitErr := memoryTracker.IncreaseMemoryConsumption(uint64(chunksSize)); memoryLimitErr != nil {
+						return memoryLimitErr
+					}
 				}
 
 				if w := resp.GetWarning(); w != "" {
@@ -859,6 +965,18 @@ func (q *blocksStoreQuerier) fetchSeriesFromStores(ctx context.Context, sp *stor
 					"queried blocks", strings.Join(convertULIDsToString(myQueriedBlocks), " "))
 			}
 
+			if stats.IsStoreStatsEnabled(ctx) {
+				// Chunks of streaming series are fetched later, so we can only report their bytes for non-streaming series.
+				_, chunkBytes := countChunksAndBytes(mySeries...)
+				reqStats.AddStoreStats(stats.StoreStats{
+					Component:          stats.StoreComponentStoreGateway,
+					Address:            c.RemoteAddress(),
+					FetchedSeriesCount: uint64(len(mySeries) + len(myStreamingSeries)),
+					FetchedChunkBytes:  uint64(chunkBytes),
+					Latency:            time.Since(start),
+				})
+			}
+
 			// Store the result.
 			mtx.Lock()
 			if len(mySeries) > 0 {
@@ -908,6 +1026,25 @@ func shouldStopQueryFunc(err error) bool {
 	return false
 }
 
+// logStoreGatewayError logs an error received from a store-gateway which doesn't stop the query.
+// The blocks not queried because of the error are retried on another store-gateway replica.
+func (q *blocksStoreQuerier) logStoreGatewayError(logger log.Logger, msg, remote string, err error) {
+	if isStoreGatewayTooBusyError(err) {
+		q.metrics.storeGatewayTooBusyRejections.Inc()
+		level.Debug(logger).Log("msg", msg+", the store-gateway is too busy and blocks will be queried from another replica", "remote", remote, "err", err)
+		return
+	}
+
+	level.Warn(logger).Log("msg", msg, "remote", remote, "err", err)
+}
+
+// isStoreGatewayTooBusyError returns whether the error has been returned by a store-gateway
+// rejecting the request because of its CPU or memory utilization.
+func isStoreGatewayTooBusyError(err error) bool {
+	resp, ok := httpgrpc.HTTPResponseFromError(errors.Cause(err))
+	return ok && resp.Code == http.StatusServiceUnavailable && globalerror.StoreGatewayTooBusy.IsInMessage(string(resp.Body))
+}
+
 func (q *blocksStoreQuerier) fetchLabelNamesFromStore(
 	ctx context.Context,
 	clients map[BlocksStoreClient][]ulid.ULID,
@@ -943,7 +1080,7 @@ func (q *blocksStoreQuerier) fetchLabelNamesFromStore(
 					return err
 				}
 
-				level.Warn(spanLog).Log("msg", "failed to fetch label names", "remote", c.RemoteAddress(), "err", err)
+				q.logStoreGatewayError(spanLog, "failed to fetch label names", c.RemoteAddress(), err)
 				return nil
 			}
 
@@ -989,6 +1126,87 @@ func (q *blocksStoreQuerier) fetchLabelNamesFromStore(
 	return nameSets, warnings, queriedBlocks, nil
 }
 
+func (q *blocksStoreQuerier) fetchExemplarsFromStore(
+	ctx context.Context,
+	clients map[BlocksStoreClient][]ulid.ULID,
+	minT int64,
+	maxT int64,
+	matchers [][]*labels.Matcher,
+) ([][]mimirpb.TimeSeries, storage.Warnings, []ulid.ULID, error) {
+	var (
+		reqCtx        = grpc_metadata.AppendToOutgoingContext(ctx, storegateway.GrpcContextMetadataTenantID, q.userID)
+		g, gCtx       = errgroup.WithContext(reqCtx)
+		mtx           = sync.Mutex{}
+		sets          = [][]mimirpb.TimeSeries{}
+		warnings      = storage.Warnings(nil)
+		queriedBlocks = []ulid.ULID(nil)
+		spanLog       = spanlogger.FromContext(ctx, q.logger)
+	)
+
+	// Concurrently fetch exemplars from all clients.
+	for c, blockIDs := range clients {
+		// Change variables scope since it will be used in a goroutine.
+		c := c
+		blockIDs := blockIDs
+
+		g.Go(func() error {
+			req, err := createExemplarsRequest(minT, maxT, blockIDs, matchers)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create exemplars request")
+			}
+
+			exemplarsResp, err := c.Exemplars(gCtx, req)
+			if err != nil {
+				if shouldStopQueryFunc(err) {
+					return err
+				}
+
+				q.logStoreGatewayError(spanLog, "failed to fetch exemplars", c.RemoteAddress(), err)
+				return nil
+			}
+
+			myQueriedBlocks := []ulid.ULID(nil)
+			if exemplarsResp.Hints != nil {
+				hints := hintspb.ExemplarsResponseHints{}
+				if err := types.UnmarshalAny(exemplarsResp.Hints, &hints); err != nil {
+					return errors.Wrapf(err, "failed to unmarshal exemplars hints from %s", c.RemoteAddress())
+				}
+
+				ids, err := convertBlockHintsToULIDs(hints.QueriedBlocks)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse queried block IDs from received hints")
+				}
+
+				myQueriedBlocks = ids
+			}
+
+			level.Debug(spanLog).Log("msg", "received exemplars from store-gateway",
+				"instance", c,
+				"num series", len(exemplarsResp.Timeseries),
+				"requested blocks", strings.Join(convertULIDsToString(blockIDs), " "),
+				"queried blocks", strings.Join(convertULIDsToString(myQueriedBlocks), " "))
+
+			// Store the result.
+			mtx.Lock()
+			sets = append(sets, exemplarsResp.Timeseries)
+			for _, w := range exemplarsResp.Warnings {
+				warnings = append(warnings, errors.New(w))
+			}
+			queriedBlocks = append(queriedBlocks, myQueriedBlocks...)
+			mtx.Unlock()
+
+			return nil
+		})
+	}
+
+	// Wait until all client requests complete.
+	if err := g.Wait(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return sets, warnings, queriedBlocks, nil
+}
+
 func (q *blocksStoreQuerier) fetchLabelValuesFromStore(
 	ctx context.Context,
 	name string,
@@ -1024,7 +1242,7 @@ func (q *blocksStoreQuerier) fetchLabelValuesFromStore(
 				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 					return err
 				}
-				level.Warn(spanLog).Log("msg", "failed to fetch label values", "remote", c.RemoteAddress(), "err", err)
+				q.logStoreGatewayError(spanLog, "failed to fetch label values", c.RemoteAddress(), err)
 				return nil
 			}
 
@@ -1161,6 +1379,37 @@ func createLabelValuesRequest(minT, maxT int64, label string, blockIDs []ulid.UL
 	return req, nil
 }
 
+func createExemplarsRequest(minT, maxT int64, blockIDs []ulid.ULID, matchers [][]*labels.Matcher) (*storepb.ExemplarsRequest, error) {
+	req := &storepb.ExemplarsRequest{
+		Start:    minT,
+		End:      maxT,
+		Matchers: make([]storepb.ExemplarMatchers, 0, len(matchers)),
+	}
+	for _, m := range matchers {
+		req.Matchers = append(req.Matchers, storepb.ExemplarMatchers{Matchers: convertMatchersToLabelMatcher(m)})
+	}
+
+	// Selectively query only specific blocks.
+	hints := &hintspb.ExemplarsRequestHints{
+		BlockMatchers: []storepb.LabelMatcher{
+			{
+				Type:  storepb.LabelMatcher_RE,
+				Name:  block.BlockIDLabel,
+				Value: strings.Join(convertULIDsToString(blockIDs), "|"),
+			},
+		},
+	}
+
+	anyHints, err := types.MarshalAny(hints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal exemplars request hints")
+	}
+
+	req.Hints = anyHints
+
+	return req, nil
+}
+
 func convertULIDsToString(ids []ulid.ULID) []string {
 	res := make([]string, len(ids))
 	for idx, id := range ids {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -29,12 +30,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/user"
 	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
@@ -42,6 +45,7 @@ import (
 	"github.com/grafana/mimir/pkg/storegateway/storegatewaypb"
 	"github.com/grafana/mimir/pkg/storegateway/storepb"
 	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/globalerror"
 	"github.com/grafana/mimir/pkg/util/limiter"
 	"github.com/grafana/mimir/pkg/util/validation"
 )
@@ -333,6 +337,10 @@ func TestBlocksStoreQuerier_Select(t *testing.T) {
 				# HELP cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total Blocks that couldn't be checked for query and compactor sharding optimization due to incompatible shard counts.
 				# TYPE cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total counter
 				cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total 0
+
+				# HELP cortex_querier_storegateway_too_busy_rejections_total Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.
+				# TYPE cortex_querier_storegateway_too_busy_rejections_total counter
+				cortex_querier_storegateway_too_busy_rejections_total 0
 			`,
 		},
 		"a single store-gateway instance has some missing blocks (consistency check failed)": {
@@ -469,6 +477,10 @@ func TestBlocksStoreQuerier_Select(t *testing.T) {
 				# HELP cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total Blocks that couldn't be checked for query and compactor sharding optimization due to incompatible shard counts.
 				# TYPE cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total counter
 				cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total 0
+
+				# HELP cortex_querier_storegateway_too_busy_rejections_total Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.
+				# TYPE cortex_querier_storegateway_too_busy_rejections_total counter
+				cortex_querier_storegateway_too_busy_rejections_total 0
 			`,
 		},
 		"max chunks per query limit greater then the number of chunks fetched": {
@@ -630,6 +642,10 @@ func TestBlocksStoreQuerier_Select(t *testing.T) {
 					# TYPE cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total counter
 					cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total 0
 
+					# HELP cortex_querier_storegateway_too_busy_rejections_total Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.
+					# TYPE cortex_querier_storegateway_too_busy_rejections_total counter
+					cortex_querier_storegateway_too_busy_rejections_total 0
+
 					# HELP cortex_querier_storegateway_instances_hit_per_query Number of store-gateway instances hit for a single query.
 					# TYPE cortex_querier_storegateway_instances_hit_per_query histogram
 					cortex_querier_storegateway_instances_hit_per_query_bucket{le="0"} 0
@@ -697,6 +713,10 @@ func TestBlocksStoreQuerier_Select(t *testing.T) {
 					# TYPE cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total counter
 					cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total 4
 
+					# HELP cortex_querier_storegateway_too_busy_rejections_total Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.
+					# TYPE cortex_querier_storegateway_too_busy_rejections_total counter
+					cortex_querier_storegateway_too_busy_rejections_total 0
+
 					# HELP cortex_querier_storegateway_instances_hit_per_query Number of store-gateway instances hit for a single query.
 					# TYPE cortex_querier_storegateway_instances_hit_per_query histogram
 					cortex_querier_storegateway_instances_hit_per_query_bucket{le="0"} 0
@@ -764,6 +784,81 @@ func TestBlocksStoreQuerier_Select(t *testing.T) {
 					# TYPE cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total counter
 					cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total 0
 
+					# HELP cortex_querier_storegateway_too_busy_rejections_total Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.
+					# TYPE cortex_querier_storegateway_too_busy_rejections_total counter
+					cortex_querier_storegateway_too_busy_rejections_total 0
+
+					# HELP cortex_querier_storegateway_instances_hit_per_query Number of store-gateway instances hit for a single query.
+					# TYPE cortex_querier_storegateway_instances_hit_per_query histogram
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="0"} 0
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="1"} 0
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="2"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="3"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="4"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="5"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="6"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="7"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="8"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="9"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="10"} 1
+					cortex_querier_storegateway_instances_hit_per_query_bucket{le="+Inf"} 1
+					cortex_querier_storegateway_instances_hit_per_query_sum 2
+					cortex_querier_storegateway_instances_hit_per_query_count 1
+					# HELP cortex_querier_storegateway_refetches_per_query Number of re-fetches attempted while querying store-gateway instances due to missing blocks.
+					# TYPE cortex_querier_storegateway_refetches_per_query histogram
+					cortex_querier_storegateway_refetches_per_query_bucket{le="0"} 0
+					cortex_querier_storegateway_refetches_per_query_bucket{le="1"} 1
+					cortex_querier_storegateway_refetches_per_query_bucket{le="2"} 1
+					cortex_querier_storegateway_refetches_per_query_bucket{le="+Inf"} 1
+					cortex_querier_storegateway_refetches_per_query_sum 1
+					cortex_querier_storegateway_refetches_per_query_count 1
+			`,
+		},
+		"multiple store-gateways have the block, but one of them is too busy": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr:      "1.1.1.1",
+						mockedSeriesErr: httpgrpc.Errorf(http.StatusServiceUnavailable, globalerror.StoreGatewayTooBusy.Message("the store-gateway is currently too busy to process queries, try again later")),
+					}: {block1},
+				},
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "2.2.2.2", mockedSeriesResponses: []*storepb.SeriesResponse{
+						mockSeriesResponse(series1Label, minT, 2),
+						mockHintsResponse(block1),
+					}}: {block1},
+				},
+			},
+			limits:       &blocksStoreLimitsMock{},
+			queryLimiter: noOpQueryLimiter,
+			expectedSeries: []seriesResult{
+				{
+					lbls: series1Label,
+					values: []valueResult{
+						{t: minT, v: 2},
+					},
+				},
+			},
+			expectedMetrics: `
+					# HELP cortex_querier_blocks_found_total Number of blocks found based on query time range.
+					# TYPE cortex_querier_blocks_found_total counter
+					cortex_querier_blocks_found_total 1
+
+					# HELP cortex_querier_blocks_queried_total Number of blocks queried to satisfy query. Compared to blocks found, some blocks may have been filtered out thanks to query and compactor sharding.
+					# TYPE cortex_querier_blocks_queried_total counter
+					cortex_querier_blocks_queried_total 1
+
+					# HELP cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total Blocks that couldn't be checked for query and compactor sharding optimization due to incompatible shard counts.
+					# TYPE cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total counter
+					cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total 0
+
+					# HELP cortex_querier_storegateway_too_busy_rejections_total Number of requests to store-gateways rejected because the store-gateway was too busy. The blocks are queried from another store-gateway replica.
+					# TYPE cortex_querier_storegateway_too_busy_rejections_total counter
+					cortex_querier_storegateway_too_busy_rejections_total 1
+
 					# HELP cortex_querier_storegateway_instances_hit_per_query Number of store-gateway instances hit for a single query.
 					# TYPE cortex_querier_storegateway_instances_hit_per_query histogram
 					cortex_querier_storegateway_instances_hit_per_query_bucket{le="0"} 0
@@ -915,7 +1010,8 @@ func TestBlocksStoreQuerier_Select(t *testing.T) {
 					if testData.expectedMetrics != "" {
 						assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(testData.expectedMetrics),
 							"cortex_querier_storegateway_instances_hit_per_query", "cortex_querier_storegateway_refetches_per_query",
-							"cortex_querier_blocks_found_total", "cortex_querier_blocks_queried_total", "cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total"))
+							"cortex_querier_blocks_found_total", "cortex_querier_blocks_queried_total", "cortex_querier_blocks_with_compactor_shard_but_incompatible_query_shard_total",
+							"cortex_querier_storegateway_too_busy_rejections_total"))
 					}
 				})
 			}
@@ -1010,6 +1106,106 @@ func TestBlocksStoreQuerier_Select_cancelledContext(t *testing.T) {
 	}
 }
 
+func TestBlocksStoreQuerier_Select_partialResponse(t *testing.T) {
+	const (
+		metricName = "test_metric"
+		minT       = int64(10)
+		maxT       = int64(20)
+	)
+
+	var (
+		block1       = ulid.MustNew(1, nil)
+		block2       = ulid.MustNew(2, nil)
+		series1Label = labels.FromStrings(labels.MetricName, metricName, "series", "1")
+	)
+
+	tests := map[string]struct {
+		partialResponseEnabled bool
+		storeSetResponses      []interface{}
+		expectedSeries         int
+		expectedWarnings       storage.Warnings
+		expectedErr            error
+	}{
+		"partial response disabled: missing blocks fail the query": {
+			partialResponseEnabled: false,
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedSeriesResponses: []*storepb.SeriesResponse{
+						mockSeriesResponse(series1Label, minT, 1),
+						mockHintsResponse(block1),
+					}}: {block1},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedErr: newStoreConsistencyCheckFailedError([]ulid.ULID{block2}),
+		},
+		"partial response enabled: missing blocks are returned as warning": {
+			partialResponseEnabled: true,
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedSeriesResponses: []*storepb.SeriesResponse{
+						mockSeriesResponse(series1Label, minT, 1),
+						mockHintsResponse(block1),
+					}}: {block1},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedSeries:   1,
+			expectedWarnings: storage.Warnings{newStoreConsistencyCheckFailedError([]ulid.ULID{block2})},
+		},
+		"partial response enabled: no store-gateway available on the first attempt": {
+			partialResponseEnabled: true,
+			storeSetResponses: []interface{}{
+				errors.New("no client found"),
+			},
+			expectedWarnings: storage.Warnings{newStoreConsistencyCheckFailedError([]ulid.ULID{block1, block2})},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx := limiter.AddQueryLimiterToContext(context.Background(), limiter.NewQueryLimiter(0, 0, 0, nil))
+			ctx = querierapi.ContextWithPartialResponse(ctx, testData.partialResponseEnabled)
+
+			finder := &blocksFinderMock{}
+			finder.On("GetBlocks", mock.Anything, "user-1", minT, maxT).Return(bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			}, map[ulid.ULID]*bucketindex.BlockDeletionMark(nil), nil)
+
+			q := &blocksStoreQuerier{
+				ctx:         ctx,
+				minT:        minT,
+				maxT:        maxT,
+				userID:      "user-1",
+				finder:      finder,
+				stores:      &blocksStoreSetMock{mockedResponses: testData.storeSetResponses},
+				consistency: NewBlocksConsistencyChecker(0, 0, log.NewNopLogger(), nil),
+				logger:      log.NewNopLogger(),
+				metrics:     newBlocksStoreQueryableMetrics(prometheus.NewPedanticRegistry()),
+				limits:      &blocksStoreLimitsMock{},
+			}
+
+			sp := &storage.SelectHints{Start: minT, End: maxT}
+			set := q.Select(true, sp, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, metricName))
+			if testData.expectedErr != nil {
+				require.ErrorContains(t, set.Err(), testData.expectedErr.Error())
+				assert.False(t, querierapi.IsPartialResponse(ctx))
+				return
+			}
+
+			actualSeries := 0
+			for set.Next() {
+				actualSeries++
+			}
+			require.NoError(t, set.Err())
+			assert.Equal(t, testData.expectedSeries, actualSeries)
+			assert.Equal(t, testData.expectedWarnings, set.Warnings())
+			assert.True(t, querierapi.IsPartialResponse(ctx))
+		})
+	}
+}
+
 func TestBlocksStoreQuerier_Labels(t *testing.T) {
 	const (
 		metricName = "test_metric"
@@ -1588,6 +1784,102 @@ func TestBlocksStoreQuerier_Labels(t *testing.T) {
 	})
 }
 
+func TestBlocksStoreQuerier_Exemplars(t *testing.T) {
+	const (
+		minT = int64(10)
+		maxT = int64(20)
+	)
+
+	var (
+		block1  = ulid.MustNew(1, nil)
+		block2  = ulid.MustNew(2, nil)
+		series1 = mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(labels.MetricName, "series_1"))
+		series2 = mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(labels.MetricName, "series_2"))
+	)
+
+	exemplar := func(ts int64) mimirpb.Exemplar {
+		return mimirpb.Exemplar{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: float64(ts), TimestampMs: ts}
+	}
+
+	tests := map[string]struct {
+		storeSetResponses []interface{}
+		expected          []mimirpb.TimeSeries
+		expectedErr       string
+	}{
+		"a single store-gateway instance holds the required blocks": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{{Labels: series1, Exemplars: []mimirpb.Exemplar{exemplar(11), exemplar(15)}}},
+						Hints:      mockExemplarsHints(block1, block2),
+					}}: {block1, block2},
+				},
+			},
+			expected: []mimirpb.TimeSeries{{Labels: series1, Exemplars: []mimirpb.Exemplar{exemplar(11), exemplar(15)}}},
+		},
+		"multiple store-gateway instances holds the required blocks with overlapping series": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{{Labels: series1, Exemplars: []mimirpb.Exemplar{exemplar(11)}}, {Labels: series2, Exemplars: []mimirpb.Exemplar{exemplar(12)}}},
+						Hints:      mockExemplarsHints(block1),
+					}}: {block1},
+					&storeGatewayClientMock{remoteAddr: "2.2.2.2", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Timeseries: []mimirpb.TimeSeries{{Labels: series2, Exemplars: []mimirpb.Exemplar{exemplar(18)}}},
+						Hints:      mockExemplarsHints(block2),
+					}}: {block2},
+				},
+			},
+			expected: []mimirpb.TimeSeries{
+				{Labels: series1, Exemplars: []mimirpb.Exemplar{exemplar(11)}},
+				{Labels: series2, Exemplars: []mimirpb.Exemplar{exemplar(12), exemplar(18)}},
+			},
+		},
+		"a block is not queried by any store-gateway": {
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{remoteAddr: "1.1.1.1", mockedExemplarsResponse: &storepb.ExemplarsResponse{
+						Hints: mockExemplarsHints(block1),
+					}}: {block1, block2},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedErr: newStoreConsistencyCheckFailedError([]ulid.ULID{block2}).Error(),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			stores := &blocksStoreSetMock{mockedResponses: testData.storeSetResponses}
+			finder := &blocksFinderMock{}
+			finder.On("GetBlocks", mock.Anything, "user-1", minT, maxT).Return(bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			}, map[ulid.ULID]*bucketindex.BlockDeletionMark(nil), nil)
+
+			q := &blocksStoreQuerier{
+				ctx:         context.Background(),
+				userID:      "user-1",
+				finder:      finder,
+				stores:      stores,
+				consistency: NewBlocksConsistencyChecker(0, 0, log.NewNopLogger(), nil),
+				logger:      log.NewNopLogger(),
+				metrics:     newBlocksStoreQueryableMetrics(nil),
+				limits:      &blocksStoreLimitsMock{},
+			}
+
+			actual, _, err := q.selectExemplars(minT, maxT, []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, "series_.*")})
+			if testData.expectedErr != "" {
+				require.EqualError(t, err, testData.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, actual)
+		})
+	}
+}
+
 func TestBlocksStoreQuerier_SelectSortedShouldHonorQueryStoreAfter(t *testing.T) {
 	now := time.Now()
 
@@ -2015,6 +2307,8 @@ type storeGatewayClientMock struct {
 	mockedLabelNamesErr       error
 	mockedLabelValuesResponse *storepb.LabelValuesResponse
 	mockedLabelValuesErr      error
+	mockedExemplarsResponse   *storepb.ExemplarsResponse
+	mockedExemplarsErr        error
 }
 
 func (m *storeGatewayClientMock) Series(ctx context.Context, _ *storepb.SeriesRequest, _ ...grpc.CallOption) (storegatewaypb.StoreGateway_SeriesClient, error) {
@@ -2034,6 +2328,10 @@ func (m *storeGatewayClientMock) LabelValues(context.Context, *storepb.LabelValu
 	return m.mockedLabelValuesResponse, m.mockedLabelValuesErr
 }
 
+func (m *storeGatewayClientMock) Exemplars(context.Context, *storepb.ExemplarsRequest, ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	return m.mockedExemplarsResponse, m.mockedExemplarsErr
+}
+
 func (m *storeGatewayClientMock) RemoteAddress() string {
 	return m.remoteAddr
 }
@@ -2110,6 +2408,11 @@ func (m *cancelerStoreGatewayClientMock) LabelValues(ctx context.Context, _ *sto
 	return nil, ctx.Err()
 }
 
+func (m *cancelerStoreGatewayClientMock) Exemplars(ctx context.Context, _ *storepb.ExemplarsRequest, _ ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	m.cancel()
+	return nil, ctx.Err()
+}
+
 func (m *cancelerStoreGatewayClientMock) RemoteAddress() string {
 	return m.remoteAddr
 }
@@ -2231,6 +2534,20 @@ func mockNamesHints(ids ...ulid.ULID) *types.Any {
 	return marshalled
 }
 
+func mockExemplarsHints(ids ...ulid.ULID) *types.Any {
+	hints := &hintspb.ExemplarsResponseHints{}
+	for _, id := range ids {
+		hints.AddQueriedBlock(id)
+	}
+
+	marshalled, err := types.MarshalAny(hints)
+	if err != nil {
+		panic(err)
+	}
+
+	return marshalled
+}
+
 func mockValuesHints(ids ...ulid.ULID) *types.Any {
 	hints := &hintspb.LabelValuesResponseHints{}
 	for _, id := range ids {
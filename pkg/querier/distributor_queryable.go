@@ -198,6 +198,15 @@ func (q *distributorQuerier) streamingSelect(ctx context.Context, minT, maxT int
 		sets = append(sets, series.NewConcreteSeriesSetFromSortedSeries(streamingSeries))
 	}
 
+	set := mergeIngesterSeriesSets(sets)
+	if len(results.Warnings) > 0 {
+		// Some ingesters couldn't be queried and a partial response is returned.
+		set = series.NewSeriesSetWithWarnings(set, results.Warnings)
+	}
+	return set
+}
+
+func mergeIngesterSeriesSets(sets []storage.SeriesSet) storage.SeriesSet {
 	if len(sets) == 0 {
 		return storage.EmptySeriesSet()
 	}
@@ -4,6 +4,7 @@ package engine
 
 import (
 	"flag"
+	"fmt"
 	"strings"
 	"time"
 
@@ -28,8 +29,22 @@ type Config struct {
 	// LookbackDelta determines the time since the last sample after which a time
 	// series is considered stale.
 	LookbackDelta time.Duration `yaml:"lookback_delta" category:"advanced"`
+
+	// PromQLEngine selects the engine used to evaluate PromQL queries.
+	PromQLEngine string `yaml:"promql_engine" category:"experimental"`
+
+	// EnablePromQLEngineFallback makes queries not supported by the selected engine
+	// be evaluated by the Prometheus engine instead of failing.
+	EnablePromQLEngineFallback bool `yaml:"enable_promql_engine_fallback" category:"experimental"`
 }
 
+const (
+	PrometheusEngine = "prometheus"
+	StreamingEngine  = "streaming"
+)
+
+var supportedPromQLEngines = []string{PrometheusEngine, StreamingEngine}
+
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	sharedWithQueryFrontend := func(help string) string {
 		if !strings.HasSuffix(help, " ") {
@@ -44,6 +59,18 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.MaxSamples, "querier.max-samples", 50e6, sharedWithQueryFrontend("Maximum number of samples a single query can load into memory."))
 	f.DurationVar(&cfg.DefaultEvaluationInterval, "querier.default-evaluation-interval", time.Minute, sharedWithQueryFrontend("The default evaluation interval or step size for subqueries."))
 	f.DurationVar(&cfg.LookbackDelta, "querier.lookback-delta", 5*time.Minute, sharedWithQueryFrontend("Time since the last sample after which a time series is considered stale and ignored by expression evaluations."))
+	f.StringVar(&cfg.PromQLEngine, "querier.promql-engine", PrometheusEngine, fmt.Sprintf("PromQL engine to use, either %s.", strings.Join(supportedPromQLEngines, " or ")))
+	f.BoolVar(&cfg.EnablePromQLEngineFallback, "querier.enable-promql-engine-fallback", true, "If set to true and the streaming engine is in use, fall back to the Prometheus engine for any queries not supported by the streaming engine.")
+}
+
+func (cfg *Config) Validate() error {
+	for _, name := range supportedPromQLEngines {
+		if cfg.PromQLEngine == name {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown PromQL engine %q, supported values are: %s", cfg.PromQLEngine, strings.Join(supportedPromQLEngines, ", "))
 }
 
 // NewPromQLEngineOptions returns the PromQL engine options based on the provided config.
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+// exemplarStore is a store supporting exemplar queries, with the filter used to decide whether
+// the store should be queried for a given time range.
+type exemplarStore struct {
+	filter    QueryableWithFilter
+	queryable storage.ExemplarQueryable
+}
+
+// newMergeExemplarQueryable returns a storage.ExemplarQueryable querying the exemplars from the ingesters,
+// through the input distributor queryable, and from the stores supporting exemplar queries, merging the results.
+func newMergeExemplarQueryable(distributor storage.ExemplarQueryable, stores []QueryableWithFilter, logger log.Logger) storage.ExemplarQueryable {
+	var exemplarStores []exemplarStore
+	for _, s := range stores {
+		if q, ok := exemplarQueryableFrom(s); ok {
+			exemplarStores = append(exemplarStores, exemplarStore{filter: s, queryable: q})
+		}
+	}
+
+	if len(exemplarStores) == 0 {
+		return distributor
+	}
+
+	return &mergeExemplarQueryable{
+		distributor: distributor,
+		stores:      exemplarStores,
+		logger:      logger,
+	}
+}
+
+// exemplarQueryableFrom returns the storage.ExemplarQueryable implemented by the input queryable,
+// or by the queryable it wraps.
+func exemplarQueryableFrom(q storage.Queryable) (storage.ExemplarQueryable, bool) {
+	switch t := q.(type) {
+	case storage.ExemplarQueryable:
+		return t, true
+	case storeQueryable:
+		return exemplarQueryableFrom(t.QueryableWithFilter)
+	case alwaysTrueFilterQueryable:
+		return exemplarQueryableFrom(t.Queryable)
+	case useBeforeTimestampQueryable:
+		return exemplarQueryableFrom(t.Queryable)
+	default:
+		return nil, false
+	}
+}
+
+type mergeExemplarQueryable struct {
+	distributor storage.ExemplarQueryable
+	stores      []exemplarStore
+	logger      log.Logger
+}
+
+func (m *mergeExemplarQueryable) ExemplarQuerier(ctx context.Context) (storage.ExemplarQuerier, error) {
+	return &mergeExemplarQuerier{
+		ctx:         ctx,
+		distributor: m.distributor,
+		stores:      m.stores,
+		logger:      m.logger,
+	}, nil
+}
+
+type mergeExemplarQuerier struct {
+	ctx         context.Context
+	distributor storage.ExemplarQueryable
+	stores      []exemplarStore
+	logger      log.Logger
+}
+
+// Select implements storage.ExemplarQuerier. The ingesters are always queried, while the stores
+// are queried only if the time range is eligible to be queried from the stores.
+func (q *mergeExemplarQuerier) Select(start, end int64, matchers ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	spanlog, ctx := spanlogger.NewWithLogger(q.ctx, q.logger, "mergeExemplarQuerier.Select")
+	defer spanlog.Finish()
+
+	now := time.Now()
+	queryables := []storage.ExemplarQueryable{q.distributor}
+	for _, s := range q.stores {
+		if s.filter.UseQueryable(now, start, end) {
+			queryables = append(queryables, s.queryable)
+		}
+	}
+
+	var (
+		g, gCtx = errgroup.WithContext(ctx)
+		mtx     sync.Mutex
+		sets    [][]mimirpb.TimeSeries
+	)
+
+	for _, queryable := range queryables {
+		queryable := queryable
+
+		g.Go(func() error {
+			querier, err := queryable.ExemplarQuerier(gCtx)
+			if err != nil {
+				return err
+			}
+
+			res, err := querier.Select(start, end, matchers...)
+			if err != nil {
+				return err
+			}
+
+			set := make([]mimirpb.TimeSeries, 0, len(res))
+			for _, r := range res {
+				set = append(set, mimirpb.TimeSeries{
+					Labels:    mimirpb.FromLabelsToLabelAdapters(r.SeriesLabels),
+					Exemplars: mimirpb.FromExemplarsToExemplarProtos(r.Exemplars),
+				})
+			}
+			// Results are not guaranteed to be sorted by labels, which is required to merge them.
+			sort.Slice(set, func(i, j int) bool {
+				return labels.Compare(mimirpb.FromLabelAdaptersToLabels(set[i].Labels), mimirpb.FromLabelAdaptersToLabels(set[j].Labels)) < 0
+			})
+
+			mtx.Lock()
+			sets = append(sets, set)
+			mtx.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := block.MergeExemplars(sets...)
+
+	var numExemplars int
+	ret := make([]exemplar.QueryResult, 0, len(merged))
+	for _, ts := range merged {
+		ret = append(ret, exemplar.QueryResult{
+			SeriesLabels: mimirpb.FromLabelAdaptersToLabels(ts.Labels),
+			Exemplars:    mimirpb.FromExemplarProtosToExemplars(ts.Exemplars),
+		})
+		numExemplars += len(ts.Exemplars)
+	}
+
+	level.Debug(spanlog).Log("numQueryables", len(queryables), "numSeries", len(ret), "numExemplars", numExemplars)
+	return ret, nil
+}
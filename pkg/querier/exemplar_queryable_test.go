@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+func TestMergeExemplarQueryable(t *testing.T) {
+	var (
+		now     = time.Now()
+		series1 = labels.FromStrings(labels.MetricName, "series_1")
+		series2 = labels.FromStrings(labels.MetricName, "series_2")
+	)
+
+	exemplarAt := func(ts int64) exemplar.Exemplar {
+		return exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "1"), Value: float64(ts), Ts: ts}
+	}
+
+	ingestersResult := []exemplar.QueryResult{
+		{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{exemplarAt(30), exemplarAt(40)}},
+		{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{exemplarAt(35)}},
+	}
+	storeResult := []exemplar.QueryResult{
+		{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{exemplarAt(10)}},
+		{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{exemplarAt(20), exemplarAt(30)}},
+	}
+
+	distributor := &exemplarQueryableMock{result: ingestersResult}
+	store := &exemplarQueryableMock{result: storeResult}
+	queryable := newMergeExemplarQueryable(distributor, []QueryableWithFilter{
+		storeQueryable{QueryableWithFilter: UseAlwaysQueryable(store), QueryStoreAfter: time.Hour},
+	}, log.NewNopLogger())
+
+	t.Run("should merge ingesters and store results when the time range is eligible to be queried from the store", func(t *testing.T) {
+		querier, err := queryable.ExemplarQuerier(context.Background())
+		require.NoError(t, err)
+
+		actual, err := querier.Select(util.TimeToMillis(now.Add(-2*time.Hour)), util.TimeToMillis(now), []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, "series_.*")})
+		require.NoError(t, err)
+		assert.Equal(t, []exemplar.QueryResult{
+			{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{exemplarAt(10), exemplarAt(35)}},
+			{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{exemplarAt(20), exemplarAt(30), exemplarAt(40)}},
+		}, actual)
+	})
+
+	t.Run("should query only ingesters when the time range is not eligible to be queried from the store", func(t *testing.T) {
+		querier, err := queryable.ExemplarQuerier(context.Background())
+		require.NoError(t, err)
+
+		actual, err := querier.Select(util.TimeToMillis(now.Add(-30*time.Minute)), util.TimeToMillis(now), []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, "series_.*")})
+		require.NoError(t, err)
+		assert.Equal(t, []exemplar.QueryResult{
+			{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{exemplarAt(35)}},
+			{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{exemplarAt(30), exemplarAt(40)}},
+		}, actual)
+	})
+
+	t.Run("should return the distributor queryable when no store supports exemplars", func(t *testing.T) {
+		actual := newMergeExemplarQueryable(distributor, []QueryableWithFilter{UseAlwaysQueryable(storage.QueryableFunc(nil))}, log.NewNopLogger())
+		assert.Same(t, distributor, actual)
+	})
+}
+
+type exemplarQueryableMock struct {
+	storage.Queryable
+	result []exemplar.QueryResult
+}
+
+func (m *exemplarQueryableMock) ExemplarQuerier(context.Context) (storage.ExemplarQuerier, error) {
+	return m, nil
+}
+
+func (m *exemplarQueryableMock) Select(_, _ int64, _ ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	return m.result, nil
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	v1 "github.com/prometheus/prometheus/web/api/v1"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// memoryConsumptionTrackingEngine wraps a PromQL engine, and injects a MemoryConsumptionTracker in the
+// context of each executed query, so that the memory consumed by the query is tracked and limited
+// by the queryables and, for the streaming engine, by the engine. The memory allocated by the Prometheus
+// engine isn't tracked. The peak estimated memory consumption is reported in the query stats.
+type memoryConsumptionTrackingEngine struct {
+	v1.QueryEngine
+
+	limits       *validation.Overrides
+	queryMetrics *stats.QueryMetrics
+}
+
+func newMemoryConsumptionTrackingEngine(engine v1.QueryEngine, limits *validation.Overrides, queryMetrics *stats.QueryMetrics) v1.QueryEngine {
+	return &memoryConsumptionTrackingEngine{
+		QueryEngine:  engine,
+		limits:       limits,
+		queryMetrics: queryMetrics,
+	}
+}
+
+func (e *memoryConsumptionTrackingEngine) NewInstantQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, ts time.Time) (promql.Query, error) {
+	query, err := e.QueryEngine.NewInstantQuery(ctx, q, opts, qs, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryConsumptionTrackingQuery{Query: query, engine: e}, nil
+}
+
+func (e *memoryConsumptionTrackingEngine) NewRangeQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (promql.Query, error) {
+	query, err := e.QueryEngine.NewRangeQuery(ctx, q, opts, qs, start, end, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryConsumptionTrackingQuery{Query: query, engine: e}, nil
+}
+
+type memoryConsumptionTrackingQuery struct {
+	promql.Query
+
+	engine *memoryConsumptionTrackingEngine
+}
+
+func (q *memoryConsumptionTrackingQuery) Exec(ctx context.Context) *promql.Result {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		// Let the engine fail the query in the same way it would do without memory tracking.
+		return q.Query.Exec(ctx)
+	}
+
+	tracker := limiter.NewMemoryConsumptionTracker(q.maxEstimatedMemory(tenantIDs), q.engine.queryMetrics)
+
+	res := q.Query.Exec(limiter.AddMemoryConsumptionTrackerToContext(ctx, tracker))
+	stats.FromContext(ctx).UpdateEstimatedPeakMemoryConsumption(tracker.PeakEstimatedMemoryConsumptionBytes())
+
+	return res
+}
+
+// maxEstimatedMemory returns the limit of the memory consumed by the whole query. For tenant federated queries,
+// each tenant can consume up to its own limit, which is enforced on the memory tracked by its queriers, so the
+// whole query is limited to the sum of the tenants' limits, or not limited if any tenant has no limit.
+func (q *memoryConsumptionTrackingQuery) maxEstimatedMemory(tenantIDs []string) uint64 {
+	var total uint64
+	for _, tenantID := range tenantIDs {
+		limit := q.engine.limits.MaxEstimatedChunksAndSamplesMemoryPerQuery(tenantID)
+		if limit <= 0 {
+			return 0
+		}
+		total += uint64(limit)
+	}
+	return total
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/storage/series"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestMemoryConsumptionTrackingEngine(t *testing.T) {
+	// The queryable simulates the series iterators holding some memory for each selected series set.
+	queryable := storage.QueryableFunc(func(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+		return &memoryConsumingQuerier{ctx: ctx}, nil
+	})
+
+	testCases := map[string]struct {
+		limit              int
+		expectedErr        bool
+		expectedPeakMemory uint64
+	}{
+		"no limit": {
+			limit:              0,
+			expectedPeakMemory: memoryConsumingQuerierBytesPerSelect,
+		},
+		"limit not exceeded": {
+			limit:              memoryConsumingQuerierBytesPerSelect,
+			expectedPeakMemory: memoryConsumingQuerierBytesPerSelect,
+		},
+		"limit exceeded": {
+			limit:              memoryConsumingQuerierBytesPerSelect - 1,
+			expectedErr:        true,
+			expectedPeakMemory: 0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			limits := defaultLimitsConfig()
+			limits.MaxEstimatedChunksAndSamplesMemoryPerQuery = testCase.limit
+			overrides, err := validation.NewOverrides(limits, nil)
+			require.NoError(t, err)
+
+			engine := newMemoryConsumptionTrackingEngine(promql.NewEngine(promql.EngineOpts{MaxSamples: 100, Timeout: time.Minute}), overrides, stats.NewQueryMetrics(prometheus.NewPedanticRegistry()))
+
+			queryStats, ctx := stats.ContextWithEmptyStats(user.InjectOrgID(context.Background(), "user-1"))
+			q, err := engine.NewInstantQuery(ctx, queryable, nil, `foo`, time.Now())
+			require.NoError(t, err)
+			defer q.Close()
+
+			res := q.Exec(ctx)
+			if testCase.expectedErr {
+				require.Error(t, res.Err)
+				assert.ErrorContains(t, res.Err, "the query exceeded the maximum allowed estimated amount of memory held by the chunks and samples of a single query")
+			} else {
+				require.NoError(t, res.Err)
+			}
+
+			assert.Equal(t, testCase.expectedPeakMemory, queryStats.LoadEstimatedPeakMemoryConsumption())
+		})
+	}
+}
+
+const memoryConsumingQuerierBytesPerSelect = 1000
+
+// memoryConsumingQuerier is a storage.Querier returning a single empty series set, and recording the
+// consumption of some memory in the tracker of the querier context.
+type memoryConsumingQuerier struct {
+	storage.Querier
+
+	ctx context.Context
+}
+
+func (q *memoryConsumingQuerier) Select(bool, *storage.SelectHints, ...*labels.Matcher) storage.SeriesSet {
+	if err := limiter.MemoryConsumptionTrackerFromContextWithFallback(q.ctx).IncreaseMemoryConsumption(memoryConsumingQuerierBytesPerSelect); err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+
+	return series.NewConcreteSeriesSetFromUnsortedSeries(nil)
+}
+
+func (q *memoryConsumingQuerier) Close() error {
+	return nil
+}
+
+func TestQuerier_ShouldReleaseMemoryConsumptionOnClose(t *testing.T) {
+	var cfg Config
+	flagext.DefaultValues(&cfg)
+
+	limits := defaultLimitsConfig()
+	limits.QueryIngestersWithin = 0 // Always query ingesters in this test.
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	// The distributor simulates the memory held by the chunks fetched from ingesters.
+	distributor := &mockDistributor{}
+	distributor.On("QueryStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tracker := limiter.MemoryConsumptionTrackerFromContextWithFallback(args.Get(0).(context.Context))
+		require.NoError(t, tracker.IncreaseMemoryConsumption(memoryConsumingQuerierBytesPerSelect))
+	}).Return(client.CombinedQueryStreamResponse{}, nil)
+
+	tracker := limiter.NewMemoryConsumptionTracker(0, nil)
+	ctx := limiter.AddMemoryConsumptionTrackerToContext(user.InjectOrgID(context.Background(), "user-1"), tracker)
+
+	queryable, _, _ := New(cfg, overrides, distributor, nil, stats.NewQueryMetrics(nil), nil, log.NewNopLogger(), nil)
+	q, err := queryable.Querier(ctx, 0, time.Now().UnixMilli())
+	require.NoError(t, err)
+
+	set := q.Select(true, nil, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "foo"))
+	require.False(t, set.Next())
+	require.NoError(t, set.Err())
+	assert.Equal(t, uint64(memoryConsumingQuerierBytesPerSelect), tracker.CurrentEstimatedMemoryConsumptionBytes())
+
+	require.NoError(t, q.Close())
+	assert.Equal(t, uint64(0), tracker.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(memoryConsumingQuerierBytesPerSelect), tracker.PeakEstimatedMemoryConsumptionBytes())
+}
+
+func TestQuerier_ShouldApplyTheTenantMemoryConsumptionLimit(t *testing.T) {
+	var cfg Config
+	flagext.DefaultValues(&cfg)
+
+	limits := defaultLimitsConfig()
+	limits.QueryIngestersWithin = 0 // Always query ingesters in this test.
+
+	user1Limits := limits
+	user1Limits.MaxEstimatedChunksAndSamplesMemoryPerQuery = memoryConsumingQuerierBytesPerSelect - 1
+	user2Limits := limits
+	user2Limits.MaxEstimatedChunksAndSamplesMemoryPerQuery = memoryConsumingQuerierBytesPerSelect
+
+	overrides, err := validation.NewOverrides(limits, validation.NewMockTenantLimits(map[string]*validation.Limits{
+		"user-1": &user1Limits,
+		"user-2": &user2Limits,
+	}))
+	require.NoError(t, err)
+
+	// The distributor simulates the memory held by the chunks fetched from ingesters.
+	var trackerErr error
+	distributor := &mockDistributor{}
+	distributor.On("QueryStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		trackerErr = limiter.MemoryConsumptionTrackerFromContextWithFallback(args.Get(0).(context.Context)).IncreaseMemoryConsumption(memoryConsumingQuerierBytesPerSelect)
+	}).Return(client.CombinedQueryStreamResponse{}, nil)
+
+	queryable, _, _ := New(cfg, overrides, distributor, nil, stats.NewQueryMetrics(nil), nil, log.NewNopLogger(), nil)
+
+	// The tracker of the whole query is not limited, like for a tenant federated query
+	// where some tenants have no limit, but the limit of each tenant still applies.
+	for tenantID, expectErr := range map[string]bool{"user-1": true, "user-2": false} {
+		t.Run(tenantID, func(t *testing.T) {
+			tracker := limiter.NewMemoryConsumptionTracker(0, nil)
+			ctx := limiter.AddMemoryConsumptionTrackerToContext(user.InjectOrgID(context.Background(), tenantID), tracker)
+
+			q, err := queryable.Querier(ctx, 0, time.Now().UnixMilli())
+			require.NoError(t, err)
+			defer q.Close()
+
+			set := q.Select(true, nil, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "foo"))
+			require.False(t, set.Next())
+			if expectErr {
+				require.Error(t, trackerErr)
+				assert.Equal(t, uint64(0), tracker.CurrentEstimatedMemoryConsumptionBytes())
+			} else {
+				require.NoError(t, trackerErr)
+				assert.Equal(t, uint64(memoryConsumingQuerierBytesPerSelect), tracker.CurrentEstimatedMemoryConsumptionBytes())
+			}
+		})
+	}
+}
+
+func TestMemoryConsumptionTrackingEngine_TenantFederatedQuery(t *testing.T) {
+	queryable := storage.QueryableFunc(func(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+		return &memoryConsumingQuerier{ctx: ctx}, nil
+	})
+
+	testCases := map[string]struct {
+		user2Limit  int
+		expectedErr bool
+	}{
+		"the query is limited to the sum of the tenants' limits": {
+			user2Limit: memoryConsumingQuerierBytesPerSelect / 2,
+		},
+		"the query is not limited if any tenant has no limit": {
+			user2Limit: 0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			overrides := validation.MockOverrides(func(defaults *validation.Limits, tenantLimits map[string]*validation.Limits) {
+				user1Limits := *defaults
+				user1Limits.MaxEstimatedChunksAndSamplesMemoryPerQuery = memoryConsumingQuerierBytesPerSelect / 2
+				user2Limits := *defaults
+				user2Limits.MaxEstimatedChunksAndSamplesMemoryPerQuery = testCase.user2Limit
+				tenantLimits["user-1"] = &user1Limits
+				tenantLimits["user-2"] = &user2Limits
+			})
+
+			engine := newMemoryConsumptionTrackingEngine(promql.NewEngine(promql.EngineOpts{MaxSamples: 100, Timeout: time.Minute}), overrides, stats.NewQueryMetrics(prometheus.NewPedanticRegistry()))
+
+			ctx := user.InjectOrgID(context.Background(), "user-1|user-2")
+			q, err := engine.NewInstantQuery(ctx, queryable, nil, `foo`, time.Now())
+			require.NoError(t, err)
+			defer q.Close()
+
+			require.NoError(t, q.Exec(ctx).Err)
+		})
+	}
+}
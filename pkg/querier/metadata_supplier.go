@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+	lru "github.com/hashicorp/golang-lru/simplelru"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+// storedMetadataTTL is how long the metric metadata read from the storage is cached for each tenant.
+// The stored metadata is updated by the compactor at every blocks cleanup, so there's no need to read
+// it at every request.
+const storedMetadataTTL = time.Minute
+
+// storedMetadataCacheSize is the max number of tenants whose stored metric metadata is cached.
+// The least recently used tenants are evicted once the cache is full.
+const storedMetadataCacheSize = 1000
+
+// NewStoreMetadataSupplier returns a MetadataSupplier returning the metric metadata held by the ingesters,
+// through the input next supplier, merged with the metric metadata persisted to the storage.
+func NewStoreMetadataSupplier(next MetadataSupplier, bkt objstore.Bucket, cfgProvider bucket.TenantConfigProvider, logger log.Logger) MetadataSupplier {
+	return &storeMetadataSupplier{
+		next:        next,
+		bkt:         bkt,
+		cfgProvider: cfgProvider,
+		logger:      logger,
+		cache:       newStoredMetadataCache(storedMetadataCacheSize),
+	}
+}
+
+type cachedStoredMetadata struct {
+	metadata  []scrape.MetricMetadata
+	fetchedAt time.Time
+}
+
+type storeMetadataSupplier struct {
+	next        MetadataSupplier
+	bkt         objstore.Bucket
+	cfgProvider bucket.TenantConfigProvider
+	logger      log.Logger
+
+	cacheMx sync.Mutex
+	cache   *lru.LRU
+}
+
+func newStoredMetadataCache(size int) *lru.LRU {
+	// The only error returned is for a non-positive size.
+	c, _ := lru.NewLRU(size, nil)
+	return c
+}
+
+func (s *storeMetadataSupplier) MetricsMetadata(ctx context.Context) ([]scrape.MetricMetadata, error) {
+	spanlog, ctx := spanlogger.NewWithLogger(ctx, s.logger, "storeMetadataSupplier.MetricsMetadata")
+	defer spanlog.Finish()
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.next.MetricsMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.storedMetadata(ctx, userID)
+	if err != nil {
+		// The stored metadata is a best effort, so we return the ingesters metadata only.
+		level.Warn(spanlog).Log("msg", "failed to read metric metadata from the storage", "user", userID, "err", err)
+		return result, nil
+	}
+
+	// The contract for the metadata endpoint requires that each returned metric metadata is unique.
+	unique := make(map[scrape.MetricMetadata]struct{}, len(result))
+	for _, m := range result {
+		unique[m] = struct{}{}
+	}
+	for _, m := range stored {
+		if _, exists := unique[m]; !exists {
+			result = append(result, m)
+			unique[m] = struct{}{}
+		}
+	}
+
+	level.Debug(spanlog).Log("msg", "merged metric metadata from the storage", "user", userID, "stored", len(stored), "results", len(result))
+	return result, nil
+}
+
+// storedMetadata returns the merged metric metadata of the tenant read from the storage, caching it for storedMetadataTTL.
+func (s *storeMetadataSupplier) storedMetadata(ctx context.Context, userID string) ([]scrape.MetricMetadata, error) {
+	s.cacheMx.Lock()
+	cached, ok := s.cache.Get(userID)
+	s.cacheMx.Unlock()
+
+	if ok && time.Since(cached.(cachedStoredMetadata).fetchedAt) < storedMetadataTTL {
+		return cached.(cachedStoredMetadata).metadata, nil
+	}
+
+	m, err := metricsmetadata.ReadMergedMetadata(ctx, s.bkt, userID, s.cfgProvider, s.logger)
+	if err != nil && !errors.Is(err, metricsmetadata.ErrMetadataNotFound) {
+		return nil, err
+	}
+
+	var metadata []scrape.MetricMetadata
+	if m != nil {
+		metadata = make([]scrape.MetricMetadata, 0, len(m.Metrics))
+		for _, e := range m.Metrics {
+			metadata = append(metadata, e.ToScrapeMetadata())
+		}
+	}
+
+	s.cacheMx.Lock()
+	s.cache.Add(userID, cachedStoredMetadata{metadata: metadata, fetchedAt: time.Now()})
+	s.evictExpiredLocked()
+	s.cacheMx.Unlock()
+
+	return metadata, nil
+}
+
+// evictExpiredLocked removes the least recently used tenants from the cache, as far as their
+// stored metadata is expired. Must be called with cacheMx held.
+func (s *storeMetadataSupplier) evictExpiredLocked() {
+	for {
+		userID, cached, ok := s.cache.GetOldest()
+		if !ok || time.Since(cached.(cachedStoredMetadata).fetchedAt) < storedMetadataTTL {
+			return
+		}
+		s.cache.Remove(userID)
+	}
+}
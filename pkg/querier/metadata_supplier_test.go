@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/metricsmetadata"
+)
+
+func TestStoreMetadataSupplier(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	now := time.Now().Unix()
+
+	ingestersMetadata := []scrape.MetricMetadata{
+		{Metric: "live", Type: textparse.MetricTypeCounter, Help: "help"},
+		{Metric: "shared", Type: textparse.MetricTypeGauge, Help: "help"},
+	}
+	next := &metadataSupplierMock{result: ingestersMetadata}
+
+	t.Run("should return the ingesters metadata if there's no stored metadata", func(t *testing.T) {
+		s := NewStoreMetadataSupplier(next, objstore.NewInMemBucket(), nil, log.NewNopLogger())
+
+		actual, err := s.MetricsMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, ingestersMetadata, actual)
+	})
+
+	t.Run("should merge the ingesters metadata with the stored metadata", func(t *testing.T) {
+		bkt := objstore.NewInMemBucket()
+		require.NoError(t, metricsmetadata.WriteMergedMetadata(context.Background(), bkt, userID, nil, &metricsmetadata.Metadata{
+			Version: metricsmetadata.MetadataVersion1,
+			Metrics: []metricsmetadata.Entry{
+				{Metric: "shared", Type: textparse.MetricTypeGauge, Help: "help", LastSeen: now},
+				{Metric: "stored", Type: textparse.MetricTypeHistogram, Help: "help", Unit: "seconds", LastSeen: now},
+			},
+			UpdatedAt: now,
+		}))
+
+		s := NewStoreMetadataSupplier(next, bkt, nil, log.NewNopLogger())
+
+		actual, err := s.MetricsMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []scrape.MetricMetadata{
+			{Metric: "live", Type: textparse.MetricTypeCounter, Help: "help"},
+			{Metric: "shared", Type: textparse.MetricTypeGauge, Help: "help"},
+			{Metric: "stored", Type: textparse.MetricTypeHistogram, Help: "help", Unit: "seconds"},
+		}, actual)
+
+		// The stored metadata should be cached.
+		require.NoError(t, bkt.Delete(context.Background(), path.Join(userID, metricsmetadata.MergedMetadataPath)))
+
+		actual, err = s.MetricsMetadata(ctx)
+		require.NoError(t, err)
+		assert.Len(t, actual, 3)
+	})
+
+	t.Run("should return the ingesters metadata if the stored metadata is corrupted", func(t *testing.T) {
+		bkt := objstore.NewInMemBucket()
+		require.NoError(t, bkt.Upload(context.Background(), path.Join(userID, metricsmetadata.MergedMetadataPath), strings.NewReader("invalid!}")))
+
+		s := NewStoreMetadataSupplier(next, bkt, nil, log.NewNopLogger())
+
+		actual, err := s.MetricsMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, ingestersMetadata, actual)
+	})
+}
+
+func TestStoreMetadataSupplier_ShouldBoundTheCache(t *testing.T) {
+	next := &metadataSupplierMock{}
+	s := NewStoreMetadataSupplier(next, objstore.NewInMemBucket(), nil, log.NewNopLogger()).(*storeMetadataSupplier)
+	s.cache = newStoredMetadataCache(2)
+
+	for _, userID := range []string{"user-1", "user-2", "user-3"} {
+		_, err := s.MetricsMetadata(user.InjectOrgID(context.Background(), userID))
+		require.NoError(t, err)
+	}
+
+	// The least recently used tenant has been evicted.
+	assert.Equal(t, []interface{}{"user-2", "user-3"}, s.cache.Keys())
+
+	// Expired tenants are evicted once the cache is updated.
+	s.cache.Add("user-2", cachedStoredMetadata{fetchedAt: time.Now().Add(-2 * storedMetadataTTL)})
+	s.cache.Add("user-3", cachedStoredMetadata{fetchedAt: time.Now().Add(-2 * storedMetadataTTL)})
+
+	_, err := s.MetricsMetadata(user.InjectOrgID(context.Background(), "user-1"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"user-1"}, s.cache.Keys())
+}
+
+type metadataSupplierMock struct {
+	result []scrape.MetricMetadata
+}
+
+func (m *metadataSupplierMock) MetricsMetadata(context.Context) ([]scrape.MetricMetadata, error) {
+	// Return a copy, given the result may be appended by the caller.
+	return append([]scrape.MetricMetadata(nil), m.result...), nil
+}
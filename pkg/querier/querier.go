@@ -21,14 +21,17 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/storage"
+	v1 "github.com/prometheus/prometheus/web/api/v1"
 	"golang.org/x/sync/errgroup"
 
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/querier/batch"
 	"github.com/grafana/mimir/pkg/querier/engine"
 	"github.com/grafana/mimir/pkg/querier/iterators"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/chunk"
 	"github.com/grafana/mimir/pkg/storage/lazyquery"
+	"github.com/grafana/mimir/pkg/streamingpromql"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
 	"github.com/grafana/mimir/pkg/util/limiter"
@@ -56,6 +59,8 @@ type Config struct {
 	StreamingChunksPerStoreGatewaySeriesBufferSize uint64        `yaml:"streaming_chunks_per_store_gateway_series_buffer_size" category:"experimental"`
 	MinimizeIngesterRequests                       bool          `yaml:"minimize_ingester_requests" category:"experimental"`
 	MinimiseIngesterRequestsHedgingDelay           time.Duration `yaml:"minimize_ingester_requests_hedging_delay" category:"experimental"`
+	QueryStoreForExemplarsEnabled                  bool          `yaml:"query_store_for_exemplars_enabled" category:"experimental"`
+	QueryStoreForMetadataEnabled                   bool          `yaml:"query_store_for_metadata_enabled" category:"experimental"`
 
 	// PromQL engine config.
 	EngineConfig engine.Config `yaml:",inline"`
@@ -91,6 +96,9 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.MinimizeIngesterRequests, minimiseIngesterRequestsFlagName, false, "If true, when querying ingesters, only the minimum required ingesters required to reach quorum will be queried initially, with other ingesters queried only if needed due to failures from the initial set of ingesters. Enabling this option reduces resource consumption for the happy path at the cost of increased latency for the unhappy path.")
 	f.DurationVar(&cfg.MinimiseIngesterRequestsHedgingDelay, minimiseIngesterRequestsFlagName+"-hedging-delay", 3*time.Second, "Delay before initiating requests to further ingesters when request minimization is enabled and the initially selected set of ingesters have not all responded. Ignored if -"+minimiseIngesterRequestsFlagName+" is not enabled.")
 
+	f.BoolVar(&cfg.QueryStoreForExemplarsEnabled, "querier.query-store-for-exemplars-enabled", false, "True to query exemplars from the store-gateways, in addition to the ingesters, when the query time range is eligible to be queried from the store-gateways. Exemplars are available in the storage only if they are shipped by ingesters, through -blocks-storage.tsdb.exemplars-shipping-enabled.")
+	f.BoolVar(&cfg.QueryStoreForMetadataEnabled, "querier.query-store-for-metadata-enabled", false, "True to merge the metric metadata held by the ingesters with the metric metadata persisted to the long-term storage. Metric metadata is persisted to the storage only if it's shipped by ingesters, through -ingester.metadata-shipping-interval, and merged by the compactor, through -compactor.metadata-merging-enabled.")
+
 	// Why 256 series / ingester/store-gateway?
 	// Based on our testing, 256 series / ingester was a good balance between memory consumption and the CPU overhead of managing a batch of series.
 	f.Uint64Var(&cfg.StreamingChunksPerIngesterSeriesBufferSize, "querier.streaming-chunks-per-ingester-buffer-size", 256, "Number of series to buffer per ingester when streaming chunks from ingesters.")
@@ -105,6 +113,10 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 }
 
 func (cfg *Config) Validate() error {
+	if err := cfg.EngineConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid PromQL engine config: %w", err)
+	}
+
 	return nil
 }
 
@@ -129,9 +141,8 @@ func getChunksIteratorFunction(cfg Config) chunkIteratorFunc {
 }
 
 // New builds a queryable and promql engine.
-func New(cfg Config, limits *validation.Overrides, distributor Distributor, stores []QueryableWithFilter, reg prometheus.Registerer, logger log.Logger, tracker *activitytracker.ActivityTracker) (storage.SampleAndChunkQueryable, storage.ExemplarQueryable, *promql.Engine) {
+func New(cfg Config, limits *validation.Overrides, distributor Distributor, stores []QueryableWithFilter, queryMetrics *stats.QueryMetrics, reg prometheus.Registerer, logger log.Logger, tracker *activitytracker.ActivityTracker) (storage.SampleAndChunkQueryable, storage.ExemplarQueryable, v1.QueryEngine) {
 	iteratorFunc := getChunksIteratorFunction(cfg)
-	queryMetrics := stats.NewQueryMetrics(reg)
 
 	distributorQueryable := newDistributorQueryable(distributor, iteratorFunc, limits, queryMetrics, logger)
 
@@ -144,6 +155,9 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 	}
 	queryable := NewQueryable(distributorQueryable, ns, iteratorFunc, cfg, limits, queryMetrics, logger)
 	exemplarQueryable := newDistributorExemplarQueryable(distributor, logger)
+	if cfg.QueryStoreForExemplarsEnabled {
+		exemplarQueryable = newMergeExemplarQueryable(exemplarQueryable, ns, logger)
+	}
 
 	lazyQueryable := storage.QueryableFunc(func(ctx context.Context, mint int64, maxt int64) (storage.Querier, error) {
 		querier, err := queryable.Querier(ctx, mint, maxt)
@@ -153,8 +167,23 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 		return lazyquery.NewLazyQuerier(querier), nil
 	})
 
-	engine := promql.NewEngine(engine.NewPromQLEngineOptions(cfg.EngineConfig, tracker, logger, reg))
-	return NewSampleAndChunkQueryable(lazyQueryable), exemplarQueryable, engine
+	opts := engine.NewPromQLEngineOptions(cfg.EngineConfig, tracker, logger, reg)
+
+	// Each engine tracks the memory consumed by its own queries, so that the memory consumed by a query
+	// falling back to the Prometheus engine isn't counted for both engines.
+	var eng v1.QueryEngine
+	switch cfg.EngineConfig.PromQLEngine {
+	case engine.StreamingEngine:
+		eng = newMemoryConsumptionTrackingEngine(streamingpromql.NewEngine(opts), limits, queryMetrics)
+		if cfg.EngineConfig.EnablePromQLEngineFallback {
+			fallback := newMemoryConsumptionTrackingEngine(promql.NewEngine(opts), limits, queryMetrics)
+			eng = streamingpromql.NewEngineWithFallback(eng, fallback, reg, logger)
+		}
+	default:
+		eng = newMemoryConsumptionTrackingEngine(promql.NewEngine(opts), limits, queryMetrics)
+	}
+
+	return NewSampleAndChunkQueryable(lazyQueryable), exemplarQueryable, eng
 }
 
 // NewSampleAndChunkQueryable creates a SampleAndChunkQueryable from a Queryable.
@@ -191,6 +220,11 @@ type QueryableWithFilter interface {
 	UseQueryable(now time.Time, queryMinT, queryMaxT int64) bool
 }
 
+// NewQueryLimiter returns a QueryLimiter enforcing the tenant's limits on the series and chunks fetched by a query.
+func NewQueryLimiter(userID string, limits *validation.Overrides, queryMetrics *stats.QueryMetrics) *limiter.QueryLimiter {
+	return limiter.NewQueryLimiter(limits.MaxFetchedSeriesPerQuery(userID), limits.MaxFetchedChunkBytesPerQuery(userID), limits.MaxChunksPerQuery(userID), queryMetrics)
+}
+
 // NewQueryable creates a new Queryable for Mimir.
 func NewQueryable(distributor QueryableWithFilter, stores []QueryableWithFilter, chunkIterFn chunkIteratorFunc, cfg Config, limits *validation.Overrides, queryMetrics *stats.QueryMetrics, logger log.Logger) storage.Queryable {
 	return storage.QueryableFunc(func(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
@@ -201,7 +235,15 @@ func NewQueryable(distributor QueryableWithFilter, stores []QueryableWithFilter,
 			return nil, err
 		}
 
-		ctx = limiter.AddQueryLimiterToContext(ctx, limiter.NewQueryLimiter(limits.MaxFetchedSeriesPerQuery(userID), limits.MaxFetchedChunkBytesPerQuery(userID), limits.MaxChunksPerQuery(userID), queryMetrics))
+		// The QueryLimiter is already in the context when it's shared with the remote clusters of a federated query.
+		if _, ok := limiter.QueryLimiterFromContext(ctx); !ok {
+			ctx = limiter.AddQueryLimiterToContext(ctx, NewQueryLimiter(userID, limits, queryMetrics))
+		}
+
+		// The chunks fetched by the querier are held until the querier is closed. They're limited by the
+		// tenant's limit, which for tenant federated queries applies to each tenant on its own.
+		memoryTracker := limiter.MemoryConsumptionTrackerFromContextWithFallback(ctx).NewChild(uint64(limits.MaxEstimatedChunksAndSamplesMemoryPerQuery(userID)), queryMetrics)
+		ctx = limiter.AddMemoryConsumptionTrackerToContext(ctx, memoryTracker)
 
 		mint, maxt, err = validateQueryTimeRange(ctx, userID, mint, maxt, limits, cfg.MaxQueryIntoFuture, logger)
 		if errors.Is(err, errEmptyTimeRange) {
@@ -218,6 +260,7 @@ func NewQueryable(distributor QueryableWithFilter, stores []QueryableWithFilter,
 			limits:             limits,
 			maxQueryIntoFuture: cfg.MaxQueryIntoFuture,
 			logger:             logger,
+			memoryTracker:      memoryTracker,
 		}
 
 		if distributor.UseQueryable(now, mint, maxt) {
@@ -256,6 +299,7 @@ type querier struct {
 	limits             *validation.Overrides
 	maxQueryIntoFuture time.Duration
 	logger             log.Logger
+	memoryTracker      *limiter.MemoryConsumptionTracker
 }
 
 // Select implements storage.Querier interface.
@@ -288,7 +332,9 @@ func (q querier) Select(_ bool, sp *storage.SelectHints, matchers ...*labels.Mat
 	} else if err != nil {
 		return storage.ErrSeriesSet(err)
 	}
-	if sp.Func == "series" { // Clamp max time range for series-only queries, before we check max length.
+	// Clamp max time range for series-only queries, before we check max length. The streaming PromQL engine selects
+	// the labels of the series of a query in the same way, but they must be selected over the whole query time range.
+	if sp.Func == "series" && !querierapi.IsQueryEngineQuerierContext(q.ctx) {
 		maxQueryLength := q.limits.MaxLabelsQueryLength(userID)
 		startMs = int64(clampTime(ctx, model.Time(startMs), maxQueryLength, model.Time(endMs).Add(-maxQueryLength), true, "start", "max label query length", log))
 	}
@@ -414,7 +460,9 @@ func (q querier) LabelNames(matchers ...*labels.Matcher) ([]string, storage.Warn
 	return util.MergeSlices(sets...), warnings, nil
 }
 
-func (querier) Close() error {
+// Close implements storage.Querier, and releases the memory consumption of the chunks fetched by the querier.
+func (q querier) Close() error {
+	q.memoryTracker.DecreaseAllMemoryConsumption()
 	return nil
 }
 
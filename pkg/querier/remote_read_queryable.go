@@ -0,0 +1,492 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	prom_remote "github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/weaveworks/common/middleware"
+	"github.com/weaveworks/common/user"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/storage/series"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	util_log "github.com/grafana/mimir/pkg/util/log"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	// Estimated size of a sample received in a sampled remote read response, counted against the
+	// chunk bytes limit of the query.
+	remoteClusterReadSampleBytes = 16
+
+	streamedChunksContentType = "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse"
+
+	// The remote read URL of a remote cluster must end with this path, which is replaced
+	// with the path of the label names and values endpoints to run those queries.
+	remoteReadURLPathSuffix = "/read"
+)
+
+var errRemoteClusterNameEmpty = errors.New("the name of a remote cluster must not be empty")
+
+// RemoteReadClusterConfig configures a remote Mimir or Prometheus cluster queried through the remote read API.
+type RemoteReadClusterConfig struct {
+	Name              string         `yaml:"name" doc:"description=The name of the remote cluster, used as the value of the cluster label of the series it returns."`
+	URL               string         `yaml:"url" doc:"description=The URL of the remote read endpoint of the remote cluster, for example http://mimir.eu-west.example.com/prometheus/api/v1/read. The label names and values are queried from the labels and label values endpoints sharing the same path prefix."`
+	Timeout           time.Duration  `yaml:"timeout" doc:"description=The timeout of each remote read request to the remote cluster. 0 to only apply the timeout of the query."`
+	BasicAuthUsername string         `yaml:"basic_auth_username" doc:"description=The username used to authenticate to the remote cluster with HTTP basic authentication."`
+	BasicAuthPassword flagext.Secret `yaml:"basic_auth_password" doc:"description=The password used to authenticate to the remote cluster with HTTP basic authentication."`
+}
+
+func (cfg *RemoteReadClusterConfig) Validate() error {
+	if cfg.Name == "" {
+		return errRemoteClusterNameEmpty
+	}
+	if u, err := url.Parse(cfg.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid remote read URL %q of the remote cluster %s", cfg.URL, cfg.Name)
+	} else if !strings.HasSuffix(u.Path, remoteReadURLPathSuffix) {
+		return fmt.Errorf("the remote read URL %q of the remote cluster %s must end with %s", cfg.URL, cfg.Name, remoteReadURLPathSuffix)
+	}
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("the timeout of the remote cluster %s must not be negative", cfg.Name)
+	}
+	return nil
+}
+
+type remoteClusterReadCtxKey struct{}
+
+// ContextWithRemoteClusterRead returns a context marking the request as a remote read sent by a remote cluster.
+func ContextWithRemoteClusterRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, remoteClusterReadCtxKey{}, true)
+}
+
+// IsRemoteClusterRead returns whether the request is a remote read sent by a remote cluster,
+// which should be served only from the local cluster data.
+func IsRemoteClusterRead(ctx context.Context) bool {
+	v, ok := ctx.Value(remoteClusterReadCtxKey{}).(bool)
+	return ok && v
+}
+
+// NewRemoteClusterReadMiddleware returns a middleware marking the requests having the
+// querierapi.RemoteClusterReadHeader header as sent by a remote cluster.
+func NewRemoteClusterReadMiddleware() middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(querierapi.RemoteClusterReadHeader) != "" {
+				r = r.WithContext(ContextWithRemoteClusterRead(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// NewRemoteReadQueryable returns a queryable running the selects against a remote cluster through the
+// remote read API, and the label names and values queries through the corresponding Prometheus HTTP API
+// endpoints. The tenant ID of the query is forwarded to the remote cluster, and the series received
+// are counted against the QueryLimiter in the query context, which tenantfederation.NewClustersQueryable
+// shares with the local cluster. The size of each response is limited by the tenant's
+// -tenant-federation.max-remote-cluster-response-size-bytes limit. If the remote cluster can't be queried and partial
+// responses are enabled for the query, the remote cluster is skipped and a warning is returned
+// instead of an error.
+func NewRemoteReadQueryable(cfg RemoteReadClusterConfig, limits *validation.Overrides, httpClient *http.Client, logger log.Logger) storage.Queryable {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return storage.QueryableFunc(func(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		maxResponseSize := limits.TenantFederationMaxRemoteClusterResponseSizeBytes(userID)
+		if maxResponseSize <= 0 {
+			maxResponseSize = math.MaxInt32
+		}
+
+		return &remoteReadQuerier{
+			ctx:             ctx,
+			mint:            mint,
+			maxt:            maxt,
+			cfg:             cfg,
+			client:          httpClient,
+			queryLimiter:    limiter.QueryLimiterFromContextWithFallback(ctx),
+			maxResponseSize: maxResponseSize,
+			logger:          logger,
+		}, nil
+	})
+}
+
+type remoteReadQuerier struct {
+	ctx          context.Context
+	mint, maxt   int64
+	cfg          RemoteReadClusterConfig
+	client       *http.Client
+	queryLimiter *limiter.QueryLimiter
+	logger       log.Logger
+
+	// Maximum size of a response received from the remote cluster. For a streamed remote read
+	// response, the limit applies both to the whole response and to each frame.
+	maxResponseSize int
+}
+
+// Select implements storage.Querier.
+func (q *remoteReadQuerier) Select(_ bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	mint, maxt := q.mint, q.maxt
+	if hints != nil {
+		mint, maxt = hints.Start, hints.End
+	}
+
+	set, err := q.read(mint, maxt, matchers)
+	if err != nil {
+		if warnings, ok := q.partialResponse(err); ok {
+			return series.NewSeriesSetWithWarnings(storage.EmptySeriesSet(), warnings)
+		}
+		return storage.ErrSeriesSet(err)
+	}
+
+	// The series are always sorted: a streamed response is sorted by the remote cluster,
+	// while a sampled response is sorted when it's decoded.
+	return set
+}
+
+// LabelNames implements storage.Querier.
+func (q *remoteReadQuerier) LabelNames(matchers ...*labels.Matcher) ([]string, storage.Warnings, error) {
+	return q.queryLabels("labels", matchers)
+}
+
+// LabelValues implements storage.Querier.
+func (q *remoteReadQuerier) LabelValues(name string, matchers ...*labels.Matcher) ([]string, storage.Warnings, error) {
+	return q.queryLabels("label/"+url.PathEscape(name)+"/values", matchers)
+}
+
+// queryLabels runs a label names or values query against the input endpoint of the Prometheus HTTP API
+// of the remote cluster, which is relative to the path of the remote read endpoint.
+func (q *remoteReadQuerier) queryLabels(endpoint string, matchers []*labels.Matcher) ([]string, storage.Warnings, error) {
+	values, warnings, err := q.doQueryLabels(endpoint, matchers)
+	if err != nil {
+		if partialWarnings, ok := q.partialResponse(err); ok {
+			return nil, partialWarnings, nil
+		}
+		return nil, nil, err
+	}
+
+	return values, warnings, nil
+}
+
+func (q *remoteReadQuerier) doQueryLabels(endpoint string, matchers []*labels.Matcher) (_ []string, _ storage.Warnings, err error) {
+	spanlog, ctx := spanlogger.NewWithLogger(q.ctx, q.logger, "remoteReadQuerier.queryLabels")
+	spanlog.SetTag("cluster", q.cfg.Name)
+	defer spanlog.Finish()
+
+	defer func() {
+		if err != nil {
+			err = errors.Wrapf(err, "label query to cluster %s", q.cfg.Name)
+		}
+	}()
+
+	if q.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.cfg.Timeout)
+		defer cancel()
+	}
+
+	params := url.Values{
+		"start": []string{model.Time(q.mint).String()},
+		"end":   []string{model.Time(q.maxt).String()},
+	}
+	if len(matchers) > 0 {
+		params.Set("match[]", matchersToSelector(matchers))
+	}
+
+	u, err := url.Parse(q.cfg.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	u.Path = strings.TrimSuffix(u.Path, remoteReadURLPathSuffix) + "/" + endpoint
+	u.RawPath = ""
+	u.RawQuery = params.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := q.setRequestHeaders(ctx, httpReq); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := q.client.Do(httpReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, nil, fmt.Errorf("unexpected response status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	body, err := io.ReadAll(newRemoteClusterResponseReader(resp.Body, q.maxResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var decoded struct {
+		Status   string   `json:"status"`
+		Data     []string `json:"data"`
+		Error    string   `json:"error"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, nil, errors.Wrap(err, "decode response")
+	}
+	if decoded.Status != "success" {
+		return nil, nil, fmt.Errorf("unexpected response status %q: %s", decoded.Status, decoded.Error)
+	}
+
+	var warnings storage.Warnings
+	for _, w := range decoded.Warnings {
+		warnings = append(warnings, errors.New(w))
+	}
+
+	slices.Sort(decoded.Data)
+	return decoded.Data, warnings, nil
+}
+
+// matchersToSelector returns the series selector matching the series selected by all the input matchers.
+func matchersToSelector(matchers []*labels.Matcher) string {
+	selectors := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		selectors = append(selectors, m.String())
+	}
+	return "{" + strings.Join(selectors, ",") + "}"
+}
+
+// Close implements storage.Querier.
+func (q *remoteReadQuerier) Close() error {
+	return nil
+}
+
+// partialResponse returns the warnings to return instead of the input error, if partial responses are enabled.
+// The query limits are always enforced, even if partial responses are enabled.
+func (q *remoteReadQuerier) partialResponse(err error) (storage.Warnings, bool) {
+	var limitErr validation.LimitError
+	if !querierapi.IsPartialResponseEnabled(q.ctx) || errors.As(err, &limitErr) {
+		return nil, false
+	}
+
+	level.Warn(util_log.WithContext(q.ctx, q.logger)).Log("msg", "unable to query remote cluster, returning a partial response", "cluster", q.cfg.Name, "err", err)
+	querierapi.MarkPartialResponse(q.ctx)
+	return storage.Warnings{err}, true
+}
+
+// read runs a remote read request against the remote cluster and returns the series received.
+func (q *remoteReadQuerier) read(mint, maxt int64, matchers []*labels.Matcher) (_ storage.SeriesSet, err error) {
+	spanlog, ctx := spanlogger.NewWithLogger(q.ctx, q.logger, "remoteReadQuerier.read")
+	spanlog.SetTag("cluster", q.cfg.Name)
+	defer spanlog.Finish()
+
+	defer func() {
+		if err != nil {
+			err = errors.Wrapf(err, "remote read from cluster %s", q.cfg.Name)
+		}
+	}()
+
+	if q.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.cfg.Timeout)
+		defer cancel()
+	}
+
+	queryReq, err := client.ToQueryRequest(model.Time(mint), model.Time(maxt), matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody, err := proto.Marshal(&client.ReadRequest{
+		Queries:               []*client.QueryRequest{queryReq},
+		AcceptedResponseTypes: []client.ReadRequest_ResponseType{client.STREAMED_XOR_CHUNKS, client.SAMPLES},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, q.cfg.URL, bytes.NewReader(snappy.Encode(nil, reqBody)))
+	if err != nil {
+		return nil, err
+	}
+	if err := q.setRequestHeaders(ctx, httpReq); err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Read-Version", "0.1.0")
+
+	resp, err := q.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), streamedChunksContentType) {
+		return decodeStreamedChunksResponse(newRemoteClusterResponseReader(resp.Body, q.maxResponseSize), q.maxResponseSize, q.queryLimiter)
+	}
+	return decodeSampledResponse(ctx, resp, q.maxResponseSize, q.queryLimiter)
+}
+
+// setRequestHeaders sets the headers of the requests to the remote cluster: the tenant ID, the
+// credentials, and the header marking the request as sent by a remote cluster.
+func (q *remoteReadQuerier) setRequestHeaders(ctx context.Context, httpReq *http.Request) error {
+	if err := user.InjectOrgIDIntoHTTPRequest(ctx, httpReq); err != nil {
+		return err
+	}
+	if q.cfg.BasicAuthUsername != "" {
+		httpReq.SetBasicAuth(q.cfg.BasicAuthUsername, q.cfg.BasicAuthPassword.String())
+	}
+	httpReq.Header.Set(querierapi.RemoteClusterReadHeader, "true")
+	return nil
+}
+
+// decodeStreamedChunksResponse decodes the series of a STREAMED_XOR_CHUNKS remote read response.
+// The chunks of the same series can be split across multiple consecutive frames.
+func decodeStreamedChunksResponse(body io.Reader, maxFrameSize int, queryLimiter *limiter.QueryLimiter) (storage.SeriesSet, error) {
+	var (
+		reader     = prom_remote.NewChunkedReader(body, uint64(maxFrameSize), nil)
+		result     []storage.Series
+		lastLabels labels.Labels
+		lastChunks []storage.Series
+	)
+
+	flush := func() {
+		if len(lastChunks) > 0 {
+			result = append(result, storage.ChainedSeriesMerge(lastChunks...))
+		}
+	}
+
+	for {
+		frame := client.StreamReadResponse{}
+		if err := reader.NextProto(&frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.Wrap(err, "decode streamed response")
+		}
+
+		for _, s := range frame.ChunkedSeries {
+			// The frame buffer is reused by the reader, so the labels and chunks must be copied.
+			if lastChunks == nil || !labels.Equal(lastLabels, mimirpb.FromLabelAdaptersToLabels(s.Labels)) {
+				if err := queryLimiter.AddSeries(s.Labels); err != nil {
+					return nil, err
+				}
+
+				flush()
+				lastLabels = mimirpb.FromLabelAdaptersToLabelsWithCopy(s.Labels)
+				lastChunks = nil
+			}
+
+			if err := queryLimiter.AddChunks(len(s.Chunks)); err != nil {
+				return nil, err
+			}
+			for _, chk := range s.Chunks {
+				if err := queryLimiter.AddChunkBytes(len(chk.Data)); err != nil {
+					return nil, err
+				}
+
+				c, err := chunkenc.FromData(chunkenc.Encoding(chk.Type), slices.Clone(chk.Data))
+				if err != nil {
+					return nil, errors.Wrap(err, "decode streamed chunk")
+				}
+				lastChunks = append(lastChunks, &storage.SeriesEntry{Lset: lastLabels, SampleIteratorFn: c.Iterator})
+			}
+		}
+	}
+	flush()
+
+	return series.NewConcreteSeriesSetFromSortedSeries(result), nil
+}
+
+// decodeSampledResponse decodes the series of a SAMPLES remote read response. The samples are counted
+// against the chunk bytes limit of the query, based on their estimated size.
+func decodeSampledResponse(ctx context.Context, resp *http.Response, maxSize int, queryLimiter *limiter.QueryLimiter) (storage.SeriesSet, error) {
+	var readResp client.ReadResponse
+	if _, err := util.ParseProtoReader(ctx, resp.Body, int(resp.ContentLength), maxSize, nil, &readResp, util.RawSnappy); err != nil {
+		return nil, errors.Wrap(err, "decode sampled response")
+	}
+
+	if len(readResp.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 query result, got %d", len(readResp.Results))
+	}
+
+	for _, ts := range readResp.Results[0].Timeseries {
+		if err := queryLimiter.AddSeries(ts.Labels); err != nil {
+			return nil, err
+		}
+		if err := queryLimiter.AddChunkBytes(len(ts.Samples) * remoteClusterReadSampleBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	return newTimeSeriesSeriesSet(readResp.Results[0].Timeseries), nil
+}
+
+// remoteClusterResponseReader reads a response received from a remote cluster, failing once
+// more than max bytes have been read.
+type remoteClusterResponseReader struct {
+	r         io.Reader
+	read, max int
+}
+
+func newRemoteClusterResponseReader(r io.Reader, max int) io.Reader {
+	return &remoteClusterResponseReader{r: r, max: max}
+}
+
+func (r *remoteClusterResponseReader) Read(p []byte) (int, error) {
+	// Read at most one byte more than allowed, to detect responses exceeding the limit.
+	if remaining := r.max - r.read; len(p) > remaining+1 {
+		p = p[:remaining+1]
+	}
+
+	n, err := r.r.Read(p)
+	r.read += n
+	if r.read > r.max {
+		return 0, fmt.Errorf("the response exceeded the maximum size of %d bytes, set by -tenant-federation.max-remote-cluster-response-size-bytes", r.max)
+	}
+	return n, err
+}
@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/storage/series"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestRemoteReadQueryable_Select(t *testing.T) {
+	series1 := series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "a"), []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}}, nil)
+	series2 := series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "b"), getNSamples(500), nil)
+	remote := newRemoteReadTestQueryable(series1, series2)
+
+	tests := map[string]struct {
+		handler func(*testing.T) http.Handler
+	}{
+		"streamed XOR chunks response": {
+			handler: func(*testing.T) http.Handler {
+				// Use small frames to ensure the chunks of a single series are split across multiple frames.
+				return remoteReadHandler(remote, 100, log.NewNopLogger())
+			},
+		},
+		"sampled response": {
+			handler: func(t *testing.T) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var req client.ReadRequest
+					_, err := util.ParseProtoReader(r.Context(), r.Body, int(r.ContentLength), maxRemoteReadQuerySize, nil, &req, util.RawSnappy)
+					require.NoError(t, err)
+
+					// Simulate a remote cluster not supporting streamed responses.
+					req.AcceptedResponseTypes = nil
+					remoteReadSamples(r.Context(), remote, w, &req, log.NewNopLogger())
+				})
+			},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			var receivedOrgID, receivedClusterReadHeader string
+			handler := testData.handler(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/prometheus/api/v1/read", r.URL.Path)
+				receivedOrgID = r.Header.Get(user.OrgIDHeaderName)
+				receivedClusterReadHeader = r.Header.Get(querierapi.RemoteClusterReadHeader)
+				handler.ServeHTTP(w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			cfg := RemoteReadClusterConfig{Name: "eu", URL: server.URL + "/prometheus/api/v1/read"}
+			q, err := NewRemoteReadQueryable(cfg, validation.MockDefaultOverrides(), nil, log.NewNopLogger()).Querier(user.InjectOrgID(context.Background(), "team-a"), 0, 600_000)
+			require.NoError(t, err)
+
+			set := q.Select(true, &storage.SelectHints{Start: 0, End: 600_000}, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"))
+			actual, err := seriesSetToQueryResponse(set)
+			require.NoError(t, err)
+			assert.Empty(t, set.Warnings())
+
+			expected, err := seriesSetToQueryResponse(series.NewConcreteSeriesSetFromSortedSeries([]storage.Series{series1, series2}))
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+
+			assert.Equal(t, "team-a", receivedOrgID)
+			assert.Equal(t, "true", receivedClusterReadHeader)
+		})
+	}
+}
+
+func TestRemoteReadQueryable_LabelNamesAndValues(t *testing.T) {
+	var receivedOrgID, receivedClusterReadHeader string
+	var receivedParams url.Values
+
+	mux := http.NewServeMux()
+	handle := func(path, response string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			receivedOrgID = r.Header.Get(user.OrgIDHeaderName)
+			receivedClusterReadHeader = r.Header.Get(querierapi.RemoteClusterReadHeader)
+			receivedParams = r.Form
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(response))
+		})
+	}
+	handle("/prometheus/api/v1/labels", `{"status":"success","data":["zone","__name__","job"]}`)
+	handle("/prometheus/api/v1/label/job/values", `{"status":"success","data":["b","a"],"warnings":["some values were dropped"]}`)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	cfg := RemoteReadClusterConfig{Name: "eu", URL: server.URL + "/prometheus/api/v1/read"}
+	q, err := NewRemoteReadQueryable(cfg, validation.MockDefaultOverrides(), nil, log.NewNopLogger()).Querier(user.InjectOrgID(context.Background(), "team-a"), 0, 600_000)
+	require.NoError(t, err)
+
+	names, warnings, err := q.LabelNames()
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, []string{labels.MetricName, "job", "zone"}, names)
+	assert.Equal(t, "team-a", receivedOrgID)
+	assert.Equal(t, "true", receivedClusterReadHeader)
+	assert.Equal(t, url.Values{"start": []string{"0"}, "end": []string{"600"}}, receivedParams)
+
+	values, warnings, err := q.LabelValues("job", labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"), labels.MustNewMatcher(labels.MatchRegexp, "zone", "z.*"))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.EqualError(t, warnings[0], "some values were dropped")
+	assert.Equal(t, []string{"a", "b"}, values)
+	assert.Equal(t, []string{`{__name__="up",zone=~"z.*"}`}, receivedParams["match[]"])
+}
+
+func TestRemoteReadQueryable_Limits(t *testing.T) {
+	remote := newRemoteReadTestQueryable(
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "a"), getNSamples(100), nil),
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "b"), getNSamples(100), nil),
+	)
+
+	tests := map[string]struct {
+		limits      func(*validation.Limits)
+		expectedErr string
+	}{
+		"max fetched series per query": {
+			limits:      func(l *validation.Limits) { l.MaxFetchedSeriesPerQuery = 1 },
+			expectedErr: fmt.Sprintf(limiter.MaxSeriesHitMsgFormat, 1),
+		},
+		"max fetched chunk bytes per query": {
+			limits:      func(l *validation.Limits) { l.MaxFetchedChunkBytesPerQuery = 10 },
+			expectedErr: fmt.Sprintf(limiter.MaxChunkBytesHitMsgFormat, 10),
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(RemoteReadHandler(remote, log.NewNopLogger()))
+			t.Cleanup(server.Close)
+
+			overrides := validation.MockOverrides(func(defaults *validation.Limits, _ map[string]*validation.Limits) {
+				testData.limits(defaults)
+			})
+			cfg := RemoteReadClusterConfig{Name: "eu", URL: server.URL + "/prometheus/api/v1/read"}
+
+			// The query limits are enforced even if partial responses are enabled. The QueryLimiter
+			// is taken from the context, where it's shared with the other clusters of the query.
+			ctx := querierapi.ContextWithPartialResponse(user.InjectOrgID(context.Background(), "team-a"), true)
+			ctx = limiter.AddQueryLimiterToContext(ctx, NewQueryLimiter("team-a", overrides, nil))
+			q, err := NewRemoteReadQueryable(cfg, overrides, nil, log.NewNopLogger()).Querier(ctx, 0, 600_000)
+			require.NoError(t, err)
+
+			set := q.Select(true, nil, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"))
+			require.False(t, set.Next())
+			require.ErrorContains(t, set.Err(), testData.expectedErr)
+			assert.False(t, querierapi.IsPartialResponse(ctx))
+		})
+	}
+}
+
+func TestRemoteReadQueryable_MaxResponseSize(t *testing.T) {
+	remote := newRemoteReadTestQueryable(
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "a"), getNSamples(100), nil),
+	)
+	server := httptest.NewServer(RemoteReadHandler(remote, log.NewNopLogger()))
+	t.Cleanup(server.Close)
+
+	overrides := validation.MockOverrides(func(defaults *validation.Limits, _ map[string]*validation.Limits) {
+		defaults.TenantFederationMaxRemoteClusterResponseSizeBytes = 10
+	})
+	cfg := RemoteReadClusterConfig{Name: "eu", URL: server.URL + "/prometheus/api/v1/read"}
+
+	q, err := NewRemoteReadQueryable(cfg, overrides, nil, log.NewNopLogger()).Querier(user.InjectOrgID(context.Background(), "team-a"), 0, 600_000)
+	require.NoError(t, err)
+
+	set := q.Select(true, nil, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"))
+	require.False(t, set.Next())
+	require.ErrorContains(t, set.Err(), "the response exceeded the maximum size of 10 bytes")
+}
+
+func TestRemoteReadQueryable_Failures(t *testing.T) {
+	tests := map[string]struct {
+		handler     func(done <-chan struct{}) http.HandlerFunc
+		timeout     time.Duration
+		expectedErr string
+	}{
+		"remote cluster returns an error": {
+			handler: func(<-chan struct{}) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				}
+			},
+			expectedErr: "unexpected response status 503 Service Unavailable: unavailable",
+		},
+		"remote cluster exceeds the timeout": {
+			handler: func(done <-chan struct{}) http.HandlerFunc {
+				return func(http.ResponseWriter, *http.Request) {
+					<-done
+				}
+			},
+			timeout:     100 * time.Millisecond,
+			expectedErr: context.DeadlineExceeded.Error(),
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			// The done channel unblocks the slow remote cluster once the test completes.
+			done := make(chan struct{})
+			server := httptest.NewServer(testData.handler(done))
+			t.Cleanup(server.Close)
+			t.Cleanup(func() { close(done) })
+
+			cfg := RemoteReadClusterConfig{Name: "eu", URL: server.URL + "/prometheus/api/v1/read", Timeout: testData.timeout}
+			queryable := NewRemoteReadQueryable(cfg, validation.MockDefaultOverrides(), nil, log.NewNopLogger())
+			matcher := labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up")
+
+			t.Run("partial responses disabled", func(t *testing.T) {
+				ctx := user.InjectOrgID(context.Background(), "team-a")
+				q, err := queryable.Querier(ctx, 0, 1000)
+				require.NoError(t, err)
+
+				set := q.Select(true, nil, matcher)
+				require.False(t, set.Next())
+				require.ErrorContains(t, set.Err(), testData.expectedErr)
+
+				_, _, err = q.LabelNames()
+				require.ErrorContains(t, err, "label query to cluster eu")
+				require.ErrorContains(t, err, testData.expectedErr)
+			})
+
+			t.Run("partial responses enabled", func(t *testing.T) {
+				ctx := querierapi.ContextWithPartialResponse(user.InjectOrgID(context.Background(), "team-a"), true)
+				q, err := queryable.Querier(ctx, 0, 1000)
+				require.NoError(t, err)
+
+				set := q.Select(true, nil, matcher)
+				require.False(t, set.Next())
+				require.NoError(t, set.Err())
+				require.Len(t, set.Warnings(), 1)
+				assert.ErrorContains(t, set.Warnings()[0], testData.expectedErr)
+
+				names, warnings, err := q.LabelValues("job")
+				require.NoError(t, err)
+				assert.Empty(t, names)
+				require.Len(t, warnings, 1)
+				assert.True(t, querierapi.IsPartialResponse(ctx))
+			})
+		})
+	}
+}
+
+func TestRemoteReadClusterConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg         RemoteReadClusterConfig
+		expectedErr string
+	}{
+		"valid": {
+			cfg: RemoteReadClusterConfig{Name: "eu", URL: "http://mimir-eu/prometheus/api/v1/read", Timeout: time.Minute},
+		},
+		"empty name": {
+			cfg:         RemoteReadClusterConfig{URL: "http://mimir-eu/prometheus/api/v1/read"},
+			expectedErr: errRemoteClusterNameEmpty.Error(),
+		},
+		"invalid URL": {
+			cfg:         RemoteReadClusterConfig{Name: "eu", URL: "mimir-eu"},
+			expectedErr: `invalid remote read URL "mimir-eu" of the remote cluster eu`,
+		},
+		"URL not ending with /read": {
+			cfg:         RemoteReadClusterConfig{Name: "eu", URL: "http://mimir-eu/prometheus/api/v1"},
+			expectedErr: `the remote read URL "http://mimir-eu/prometheus/api/v1" of the remote cluster eu must end with /read`,
+		},
+		"negative timeout": {
+			cfg:         RemoteReadClusterConfig{Name: "eu", URL: "http://mimir-eu/prometheus/api/v1/read", Timeout: -time.Second},
+			expectedErr: "the timeout of the remote cluster eu must not be negative",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := testData.cfg.Validate()
+			if testData.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, testData.expectedErr)
+			}
+		})
+	}
+}
+
+// newRemoteReadTestQueryable returns a queryable serving the input sorted series.
+func newRemoteReadTestQueryable(input ...storage.Series) storage.SampleAndChunkQueryable {
+	return &mockSampleAndChunkQueryable{
+		queryableFn: func(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+			return mockQuerier{seriesSet: series.NewConcreteSeriesSetFromSortedSeries(input)}, nil
+		},
+		chunkQueryableFn: func(ctx context.Context, mint, maxt int64) (storage.ChunkQuerier, error) {
+			return mockChunkQuerier{seriesSet: series.NewConcreteSeriesSetFromSortedSeries(input)}, nil
+		},
+	}
+}
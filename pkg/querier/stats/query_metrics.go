@@ -14,10 +14,11 @@ const (
 	RejectReasonMaxSeries     = "max-fetched-series-per-query"
 	RejectReasonMaxChunkBytes = "max-fetched-chunk-bytes-per-query"
 	RejectReasonMaxChunks     = "max-fetched-chunks-per-query"
+	RejectReasonMaxMemory     = "max-estimated-chunks-and-samples-memory-per-query"
 )
 
 var (
-	rejectReasons = []string{RejectReasonMaxSeries, RejectReasonMaxChunkBytes, RejectReasonMaxChunks}
+	rejectReasons = []string{RejectReasonMaxSeries, RejectReasonMaxChunkBytes, RejectReasonMaxChunks, RejectReasonMaxMemory}
 )
 
 // QueryMetrics collects metrics on the number of chunks used while serving queries.
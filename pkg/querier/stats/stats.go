@@ -7,6 +7,8 @@ package stats
 
 import (
 	"context"
+	"sort"
+	"sync"
 	"sync/atomic" //lint:ignore faillint we can't use go.uber.org/atomic with a protobuf struct without wrapping it.
 	"time"
 
@@ -17,6 +19,39 @@ type contextKey int
 
 var ctxKey = contextKey(0)
 
+// Stats holds the statistics of a query. The type is declared here, instead of being generated
+// from stats.proto, so that it can hold the mutexes protecting the per-store and per-tenant statistics.
+type Stats struct {
+	// The sum of all wall time spent in the querier to execute the query.
+	WallTime time.Duration `protobuf:"bytes,1,opt,name=wall_time,json=wallTime,proto3,stdduration" json:"wall_time"`
+	// The number of series fetched for the query
+	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
+	// The number of bytes of the chunks fetched for the query, after any deduplication
+	FetchedChunkBytes uint64 `protobuf:"varint,3,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
+	// The number of chunks fetched for the query, after any deduplication
+	FetchedChunksCount uint64 `protobuf:"varint,4,opt,name=fetched_chunks_count,json=fetchedChunksCount,proto3" json:"fetched_chunks_count,omitempty"`
+	// The number of sharded queries executed. 0 if sharding is disabled or the query can't be sharded.
+	ShardedQueries uint32 `protobuf:"varint,5,opt,name=sharded_queries,json=shardedQueries,proto3" json:"sharded_queries,omitempty"`
+	// The number of split partial queries executed. 0 if splitting is disabled or the query can't be split.
+	SplitQueries uint32 `protobuf:"varint,6,opt,name=split_queries,json=splitQueries,proto3" json:"split_queries,omitempty"`
+	// The number of index bytes fetched on the store-gateway for the query
+	FetchedIndexBytes uint64 `protobuf:"varint,7,opt,name=fetched_index_bytes,json=fetchedIndexBytes,proto3" json:"fetched_index_bytes,omitempty"`
+	// The estimated number of series to be fetched for the query
+	EstimatedSeriesCount uint64 `protobuf:"varint,8,opt,name=estimated_series_count,json=estimatedSeriesCount,proto3" json:"estimated_series_count,omitempty"`
+	// The highest estimated memory consumed by the query in a querier, in bytes. When the query is split or
+	// sharded, this is the highest estimated memory consumed by any of the partial queries.
+	EstimatedPeakMemoryConsumptionBytes uint64 `protobuf:"varint,9,opt,name=estimated_peak_memory_consumption_bytes,json=estimatedPeakMemoryConsumptionBytes,proto3" json:"estimated_peak_memory_consumption_bytes,omitempty"`
+	// Per-store statistics, tracked only when explicitly requested (eg. when the query is analyzed).
+	StoreStats []StoreStats `protobuf:"bytes,10,rep,name=store_stats,json=storeStats,proto3" json:"store_stats"`
+	// Per-tenant statistics, tracked only for tenant federated queries spanning multiple tenants.
+	TenantStats []TenantStats `protobuf:"bytes,11,rep,name=tenant_stats,json=tenantStats,proto3" json:"tenant_stats"`
+
+	// storeStatsMtx protects StoreStats.
+	storeStatsMtx sync.Mutex
+	// tenantStatsMtx protects TenantStats.
+	tenantStatsMtx sync.Mutex
+}
+
 // ContextWithEmptyStats returns a context with empty stats.
 func ContextWithEmptyStats(ctx context.Context) (*Stats, context.Context) {
 	stats := &Stats{}
@@ -171,6 +206,101 @@ func (s *Stats) LoadEstimatedSeriesCount() uint64 {
 	return atomic.LoadUint64(&s.EstimatedSeriesCount)
 }
 
+// UpdateEstimatedPeakMemoryConsumption updates the peak estimated memory consumption, if the input
+// bytes are higher than the current peak.
+func (s *Stats) UpdateEstimatedPeakMemoryConsumption(bytes uint64) {
+	if s == nil {
+		return
+	}
+
+	for {
+		current := atomic.LoadUint64(&s.EstimatedPeakMemoryConsumptionBytes)
+		if bytes <= current || atomic.CompareAndSwapUint64(&s.EstimatedPeakMemoryConsumptionBytes, current, bytes) {
+			return
+		}
+	}
+}
+
+func (s *Stats) LoadEstimatedPeakMemoryConsumption() uint64 {
+	if s == nil {
+		return 0
+	}
+
+	return atomic.LoadUint64(&s.EstimatedPeakMemoryConsumptionBytes)
+}
+
+// AddStoreStats records the statistics about the requests issued to a single store.
+func (s *Stats) AddStoreStats(storeStats StoreStats) {
+	if s == nil {
+		return
+	}
+
+	s.storeStatsMtx.Lock()
+	defer s.storeStatsMtx.Unlock()
+
+	s.StoreStats = append(s.StoreStats, storeStats)
+}
+
+// LoadStoreStats returns a copy of the per-store statistics recorded so far.
+func (s *Stats) LoadStoreStats() []StoreStats {
+	if s == nil {
+		return nil
+	}
+
+	s.storeStatsMtx.Lock()
+	defer s.storeStatsMtx.Unlock()
+
+	if len(s.StoreStats) == 0 {
+		return nil
+	}
+
+	return append([]StoreStats(nil), s.StoreStats...)
+}
+
+// AddTenantStats records the statistics about the sub-queries run against a single tenant. The statistics
+// are added to the ones previously recorded for the same tenant, if any.
+func (s *Stats) AddTenantStats(tenantStats TenantStats) {
+	if s == nil {
+		return
+	}
+
+	s.tenantStatsMtx.Lock()
+	defer s.tenantStatsMtx.Unlock()
+
+	for i := range s.TenantStats {
+		if existing := &s.TenantStats[i]; existing.TenantId == tenantStats.TenantId {
+			existing.FetchedSeriesCount += tenantStats.FetchedSeriesCount
+			existing.FetchedChunkBytes += tenantStats.FetchedChunkBytes
+			existing.FetchedChunksCount += tenantStats.FetchedChunksCount
+			existing.FetchedIndexBytes += tenantStats.FetchedIndexBytes
+			existing.QueueTime += tenantStats.QueueTime
+			return
+		}
+	}
+
+	s.TenantStats = append(s.TenantStats, tenantStats)
+}
+
+// LoadTenantStats returns a copy of the per-tenant statistics recorded so far, sorted by tenant ID.
+func (s *Stats) LoadTenantStats() []TenantStats {
+	if s == nil {
+		return nil
+	}
+
+	s.tenantStatsMtx.Lock()
+	defer s.tenantStatsMtx.Unlock()
+
+	if len(s.TenantStats) == 0 {
+		return nil
+	}
+
+	res := append([]TenantStats(nil), s.TenantStats...)
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TenantId < res[j].TenantId
+	})
+	return res
+}
+
 // Merge the provided Stats into this one.
 func (s *Stats) Merge(other *Stats) {
 	if s == nil || other == nil {
@@ -185,6 +315,65 @@ func (s *Stats) Merge(other *Stats) {
 	s.AddSplitQueries(other.LoadSplitQueries())
 	s.AddFetchedIndexBytes(other.LoadFetchedIndexBytes())
 	s.AddEstimatedSeriesCount(other.LoadEstimatedSeriesCount())
+	s.UpdateEstimatedPeakMemoryConsumption(other.LoadEstimatedPeakMemoryConsumption())
+
+	for _, storeStats := range other.LoadStoreStats() {
+		s.AddStoreStats(storeStats)
+	}
+
+	for _, tenantStats := range other.LoadTenantStats() {
+		s.AddTenantStats(tenantStats)
+	}
+}
+
+// Equal returns whether the input *Stats is equal to this one. It's implemented here, instead of being
+// generated from stats.proto, because the generated one copies Stats values, including the mutex.
+func (s *Stats) Equal(that interface{}) bool {
+	if that == nil {
+		return s == nil
+	}
+
+	other, ok := that.(*Stats)
+	if !ok {
+		return false
+	}
+	if other == nil {
+		return s == nil
+	} else if s == nil {
+		return false
+	}
+
+	if s.WallTime != other.WallTime ||
+		s.FetchedSeriesCount != other.FetchedSeriesCount ||
+		s.FetchedChunkBytes != other.FetchedChunkBytes ||
+		s.FetchedChunksCount != other.FetchedChunksCount ||
+		s.ShardedQueries != other.ShardedQueries ||
+		s.SplitQueries != other.SplitQueries ||
+		s.FetchedIndexBytes != other.FetchedIndexBytes ||
+		s.EstimatedSeriesCount != other.EstimatedSeriesCount ||
+		s.EstimatedPeakMemoryConsumptionBytes != other.EstimatedPeakMemoryConsumptionBytes {
+		return false
+	}
+
+	if len(s.StoreStats) != len(other.StoreStats) {
+		return false
+	}
+	for i := range s.StoreStats {
+		if !s.StoreStats[i].Equal(&other.StoreStats[i]) {
+			return false
+		}
+	}
+
+	if len(s.TenantStats) != len(other.TenantStats) {
+		return false
+	}
+	for i := range s.TenantStats {
+		if !s.TenantStats[i].Equal(&other.TenantStats[i]) {
+			return false
+		}
+	}
+
+	return true
 }
 
 func ShouldTrackHTTPGRPCResponse(r *httpgrpc.HTTPResponse) bool {
@@ -29,25 +29,6 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
-type Stats struct {
-	// The sum of all wall time spent in the querier to execute the query.
-	WallTime time.Duration `protobuf:"bytes,1,opt,name=wall_time,json=wallTime,proto3,stdduration" json:"wall_time"`
-	// The number of series fetched for the query
-	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
-	// The number of bytes of the chunks fetched for the query, after any deduplication
-	FetchedChunkBytes uint64 `protobuf:"varint,3,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
-	// The number of chunks fetched for the query, after any deduplication
-	FetchedChunksCount uint64 `protobuf:"varint,4,opt,name=fetched_chunks_count,json=fetchedChunksCount,proto3" json:"fetched_chunks_count,omitempty"`
-	// The number of sharded queries executed. 0 if sharding is disabled or the query can't be sharded.
-	ShardedQueries uint32 `protobuf:"varint,5,opt,name=sharded_queries,json=shardedQueries,proto3" json:"sharded_queries,omitempty"`
-	// The number of split partial queries executed. 0 if splitting is disabled or the query can't be split.
-	SplitQueries uint32 `protobuf:"varint,6,opt,name=split_queries,json=splitQueries,proto3" json:"split_queries,omitempty"`
-	// The number of index bytes fetched on the store-gateway for the query
-	FetchedIndexBytes uint64 `protobuf:"varint,7,opt,name=fetched_index_bytes,json=fetchedIndexBytes,proto3" json:"fetched_index_bytes,omitempty"`
-	// The estimated number of series to be fetched for the query
-	EstimatedSeriesCount uint64 `protobuf:"varint,8,opt,name=estimated_series_count,json=estimatedSeriesCount,proto3" json:"estimated_series_count,omitempty"`
-}
-
 func (m *Stats) Reset()      { *m = Stats{} }
 func (*Stats) ProtoMessage() {}
 func (*Stats) Descriptor() ([]byte, []int) {
@@ -136,47 +117,254 @@ func (m *Stats) GetEstimatedSeriesCount() uint64 {
 	return 0
 }
 
+func (m *Stats) GetEstimatedPeakMemoryConsumptionBytes() uint64 {
+	if m != nil {
+		return m.EstimatedPeakMemoryConsumptionBytes
+	}
+	return 0
+}
+
+func (m *Stats) GetStoreStats() []StoreStats {
+	if m != nil {
+		return m.StoreStats
+	}
+	return nil
+}
+
+func (m *Stats) GetTenantStats() []TenantStats {
+	if m != nil {
+		return m.TenantStats
+	}
+	return nil
+}
+
+// StoreStats holds the statistics about the requests issued by the querier to a single store (ingester or store-gateway).
+type StoreStats struct {
+	// The component the store belongs to (eg. ingester or store-gateway).
+	Component string `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	// The address of the store.
+	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// The number of series fetched from the store.
+	FetchedSeriesCount uint64 `protobuf:"varint,3,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
+	// The number of bytes of the chunks fetched from the store.
+	FetchedChunkBytes uint64 `protobuf:"varint,4,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
+	// The time spent waiting for the store to return the series.
+	Latency time.Duration `protobuf:"bytes,5,opt,name=latency,proto3,stdduration" json:"latency"`
+}
+
+func (m *StoreStats) Reset()      { *m = StoreStats{} }
+func (*StoreStats) ProtoMessage() {}
+func (*StoreStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4756a0aec8b9d44, []int{1}
+}
+func (m *StoreStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StoreStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StoreStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StoreStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreStats.Merge(m, src)
+}
+func (m *StoreStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *StoreStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreStats proto.InternalMessageInfo
+
+func (m *StoreStats) GetComponent() string {
+	if m != nil {
+		return m.Component
+	}
+	return ""
+}
+
+func (m *StoreStats) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *StoreStats) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
+	}
+	return 0
+}
+
+func (m *StoreStats) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
+	}
+	return 0
+}
+
+func (m *StoreStats) GetLatency() time.Duration {
+	if m != nil {
+		return m.Latency
+	}
+	return 0
+}
+
+// TenantStats holds the statistics about the sub-queries run against a single tenant of a tenant federated query.
+type TenantStats struct {
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// The number of series fetched for the tenant.
+	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
+	// The number of bytes of the chunks fetched for the tenant.
+	FetchedChunkBytes uint64 `protobuf:"varint,3,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
+	// The number of chunks fetched for the tenant.
+	FetchedChunksCount uint64 `protobuf:"varint,4,opt,name=fetched_chunks_count,json=fetchedChunksCount,proto3" json:"fetched_chunks_count,omitempty"`
+	// The number of index bytes fetched on the store-gateway for the tenant.
+	FetchedIndexBytes uint64 `protobuf:"varint,5,opt,name=fetched_index_bytes,json=fetchedIndexBytes,proto3" json:"fetched_index_bytes,omitempty"`
+	// The time the tenant sub-queries have been queued in the querier, waiting for a free worker.
+	QueueTime time.Duration `protobuf:"bytes,6,opt,name=queue_time,json=queueTime,proto3,stdduration" json:"queue_time"`
+}
+
+func (m *TenantStats) Reset()      { *m = TenantStats{} }
+func (*TenantStats) ProtoMessage() {}
+func (*TenantStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4756a0aec8b9d44, []int{2}
+}
+func (m *TenantStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TenantStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TenantStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantStats.Merge(m, src)
+}
+func (m *TenantStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantStats proto.InternalMessageInfo
+
+func (m *TenantStats) GetTenantId() string {
+	if m != nil {
+		return m.TenantId
+	}
+	return ""
+}
+
+func (m *TenantStats) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
+	}
+	return 0
+}
+
+func (m *TenantStats) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
+	}
+	return 0
+}
+
+func (m *TenantStats) GetFetchedChunksCount() uint64 {
+	if m != nil {
+		return m.FetchedChunksCount
+	}
+	return 0
+}
+
+func (m *TenantStats) GetFetchedIndexBytes() uint64 {
+	if m != nil {
+		return m.FetchedIndexBytes
+	}
+	return 0
+}
+
+func (m *TenantStats) GetQueueTime() time.Duration {
+	if m != nil {
+		return m.QueueTime
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Stats)(nil), "stats.Stats")
+	proto.RegisterType((*StoreStats)(nil), "stats.StoreStats")
+	proto.RegisterType((*TenantStats)(nil), "stats.TenantStats")
 }
 
 func init() { proto.RegisterFile("stats.proto", fileDescriptor_b4756a0aec8b9d44) }
 
 var fileDescriptor_b4756a0aec8b9d44 = []byte{
-	// 362 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0x3f, 0x4f, 0xc2, 0x40,
-	0x18, 0xc6, 0x7b, 0xf2, 0x47, 0x3c, 0x44, 0x63, 0x25, 0xa6, 0x32, 0xbc, 0x10, 0x1d, 0x64, 0x2a,
-	0x46, 0xdd, 0x5c, 0x0c, 0xb8, 0x38, 0x0a, 0x4e, 0x2e, 0x4d, 0xa1, 0x47, 0x69, 0x2c, 0x3d, 0xec,
-	0x5d, 0xa3, 0x6e, 0x7e, 0x04, 0xdd, 0xfc, 0x08, 0x7e, 0x14, 0x46, 0x46, 0x26, 0x95, 0x63, 0x71,
-	0xe4, 0x23, 0x98, 0xbb, 0xb6, 0x04, 0xdc, 0x7a, 0xef, 0xef, 0xfd, 0xe5, 0x79, 0x72, 0x3d, 0x5c,
-	0x64, 0xdc, 0xe6, 0xcc, 0x1c, 0x85, 0x94, 0x53, 0x3d, 0xa7, 0x0e, 0x95, 0xb2, 0x4b, 0x5d, 0xaa,
-	0x26, 0x0d, 0xf9, 0x15, 0xc3, 0x0a, 0xb8, 0x94, 0xba, 0x3e, 0x69, 0xa8, 0x53, 0x37, 0xea, 0x37,
-	0x9c, 0x28, 0xb4, 0xb9, 0x47, 0x83, 0x98, 0x1f, 0xbd, 0x67, 0x70, 0xae, 0x23, 0x7d, 0xfd, 0x0a,
-	0x6f, 0x3d, 0xd9, 0xbe, 0x6f, 0x71, 0x6f, 0x48, 0x0c, 0x54, 0x43, 0xf5, 0xe2, 0xd9, 0xa1, 0x19,
-	0xdb, 0x66, 0x6a, 0x9b, 0xd7, 0x89, 0xdd, 0x2c, 0x8c, 0xbf, 0xaa, 0xda, 0xc7, 0x77, 0x15, 0xb5,
-	0x0b, 0xd2, 0xba, 0xf3, 0x86, 0x44, 0x3f, 0xc5, 0xe5, 0x3e, 0xe1, 0xbd, 0x01, 0x71, 0x2c, 0x46,
-	0x42, 0x8f, 0x30, 0xab, 0x47, 0xa3, 0x80, 0x1b, 0x1b, 0x35, 0x54, 0xcf, 0xb6, 0xf5, 0x84, 0x75,
-	0x14, 0x6a, 0x49, 0xa2, 0x9b, 0x78, 0x3f, 0x35, 0x7a, 0x83, 0x28, 0x78, 0xb0, 0xba, 0x2f, 0x9c,
-	0x30, 0x23, 0xa3, 0x84, 0xbd, 0x04, 0xb5, 0x24, 0x69, 0x4a, 0xb0, 0x9a, 0xa0, 0xf6, 0xd3, 0x84,
-	0xec, 0x5a, 0x82, 0x12, 0x92, 0x84, 0x13, 0xbc, 0xcb, 0x06, 0x76, 0xe8, 0x10, 0xc7, 0x7a, 0x8c,
-	0x54, 0xb2, 0x91, 0xab, 0xa1, 0x7a, 0xa9, 0xbd, 0x93, 0x8c, 0x6f, 0xe3, 0xa9, 0x7e, 0x8c, 0x4b,
-	0x6c, 0xe4, 0x7b, 0x7c, 0xb9, 0x96, 0x57, 0x6b, 0xdb, 0x6a, 0x98, 0x2e, 0xad, 0xf4, 0xf5, 0x02,
-	0x87, 0x3c, 0x27, 0x7d, 0x37, 0xd7, 0xfa, 0xde, 0x48, 0x12, 0xf7, 0xbd, 0xc0, 0x07, 0x84, 0x71,
-	0x6f, 0x68, 0xf3, 0xff, 0x77, 0x52, 0x50, 0x4a, 0x79, 0x49, 0x57, 0x6e, 0xa5, 0x79, 0x39, 0x99,
-	0x81, 0x36, 0x9d, 0x81, 0xb6, 0x98, 0x01, 0x7a, 0x15, 0x80, 0x3e, 0x05, 0xa0, 0xb1, 0x00, 0x34,
-	0x11, 0x80, 0x7e, 0x04, 0xa0, 0x5f, 0x01, 0xda, 0x42, 0x00, 0x7a, 0x9b, 0x83, 0x36, 0x99, 0x83,
-	0x36, 0x9d, 0x83, 0x76, 0x1f, 0x3f, 0x83, 0x6e, 0x5e, 0xfd, 0xab, 0xf3, 0xbf, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xba, 0xe5, 0x86, 0x2e, 0x23, 0x02, 0x00, 0x00,
-}
-
-func (this *Stats) Equal(that interface{}) bool {
+	// 579 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x94, 0x31, 0x6f, 0xd3, 0x40,
+	0x14, 0xc7, 0x7d, 0x6d, 0xd2, 0xc4, 0xe7, 0x16, 0x54, 0x13, 0x21, 0x53, 0xd0, 0x25, 0x4a, 0x87,
+	0x66, 0x72, 0x50, 0x61, 0x40, 0x54, 0x48, 0x28, 0x61, 0xe9, 0x80, 0x04, 0x4e, 0x26, 0x16, 0xcb,
+	0xb1, 0xaf, 0x89, 0x95, 0xd8, 0x97, 0xfa, 0xce, 0x82, 0x6c, 0x7c, 0x04, 0x46, 0x46, 0xd8, 0x98,
+	0xf9, 0x14, 0x1d, 0x33, 0x76, 0x02, 0xe2, 0x08, 0x29, 0x63, 0x3e, 0x02, 0xba, 0x67, 0x3b, 0x49,
+	0x91, 0x22, 0x95, 0x8d, 0x2d, 0xf7, 0xfe, 0xef, 0x97, 0xff, 0xf3, 0xf3, 0xdf, 0x87, 0x35, 0x2e,
+	0x1c, 0xc1, 0xcd, 0x71, 0xc4, 0x04, 0xd3, 0x8b, 0x70, 0x38, 0xaa, 0xf4, 0x59, 0x9f, 0x41, 0xa5,
+	0x29, 0x7f, 0xa5, 0xe2, 0x11, 0xe9, 0x33, 0xd6, 0x1f, 0xd1, 0x26, 0x9c, 0x7a, 0xf1, 0x45, 0xd3,
+	0x8b, 0x23, 0x47, 0xf8, 0x2c, 0x4c, 0xf5, 0xfa, 0xa2, 0x80, 0x8b, 0x1d, 0xc9, 0xeb, 0x2f, 0xb1,
+	0xfa, 0xde, 0x19, 0x8d, 0x6c, 0xe1, 0x07, 0xd4, 0x40, 0x35, 0xd4, 0xd0, 0x4e, 0x1f, 0x98, 0x29,
+	0x6d, 0xe6, 0xb4, 0xf9, 0x2a, 0xa3, 0x5b, 0xe5, 0xab, 0x1f, 0x55, 0xe5, 0xf3, 0xcf, 0x2a, 0xb2,
+	0xca, 0x92, 0xea, 0xfa, 0x01, 0xd5, 0x1f, 0xe3, 0xca, 0x05, 0x15, 0xee, 0x80, 0x7a, 0x36, 0xa7,
+	0x91, 0x4f, 0xb9, 0xed, 0xb2, 0x38, 0x14, 0xc6, 0x4e, 0x0d, 0x35, 0x0a, 0x96, 0x9e, 0x69, 0x1d,
+	0x90, 0xda, 0x52, 0xd1, 0x4d, 0x7c, 0x2f, 0x27, 0xdc, 0x41, 0x1c, 0x0e, 0xed, 0xde, 0x44, 0x50,
+	0x6e, 0xec, 0x02, 0x70, 0x98, 0x49, 0x6d, 0xa9, 0xb4, 0xa4, 0xb0, 0xe9, 0x00, 0xfd, 0xb9, 0x43,
+	0xe1, 0x86, 0x03, 0x00, 0x99, 0xc3, 0x09, 0xbe, 0xcb, 0x07, 0x4e, 0xe4, 0x51, 0xcf, 0xbe, 0x8c,
+	0xc1, 0xd9, 0x28, 0xd6, 0x50, 0xe3, 0xc0, 0xba, 0x93, 0x95, 0xdf, 0xa6, 0x55, 0xfd, 0x18, 0x1f,
+	0xf0, 0xf1, 0xc8, 0x17, 0xab, 0xb6, 0x3d, 0x68, 0xdb, 0x87, 0x62, 0xde, 0xb4, 0x31, 0xaf, 0x1f,
+	0x7a, 0xf4, 0x43, 0x36, 0x6f, 0xe9, 0xc6, 0xbc, 0xe7, 0x52, 0x49, 0xe7, 0x7d, 0x8a, 0xef, 0x53,
+	0x2e, 0xfc, 0xc0, 0x11, 0x7f, 0xef, 0xa4, 0x0c, 0x48, 0x65, 0xa5, 0x6e, 0x6e, 0xa5, 0x8b, 0x4f,
+	0xd6, 0xd4, 0x98, 0x3a, 0x43, 0x3b, 0xa0, 0x01, 0x8b, 0x26, 0xb6, 0xcb, 0x42, 0x1e, 0x07, 0x63,
+	0xf9, 0x02, 0x32, 0x67, 0x15, 0xfe, 0xe6, 0x78, 0xd5, 0xfe, 0x86, 0x3a, 0xc3, 0xd7, 0xd0, 0xdc,
+	0x5e, 0xf7, 0xa6, 0xb3, 0x3c, 0x93, 0xa9, 0x61, 0x11, 0xb5, 0x21, 0x2e, 0x06, 0xae, 0xed, 0x36,
+	0xb4, 0xd3, 0x43, 0x13, 0x4e, 0x66, 0x47, 0x2a, 0x90, 0x83, 0x56, 0x41, 0xbe, 0x59, 0x0b, 0xf3,
+	0x55, 0x45, 0x3f, 0xc3, 0xfb, 0x82, 0x86, 0x4e, 0x28, 0x32, 0x54, 0x03, 0x54, 0xcf, 0xd0, 0x2e,
+	0x48, 0x9b, 0xac, 0x26, 0xd6, 0xa5, 0xe7, 0xe5, 0xc5, 0x97, 0xaa, 0xb2, 0xfc, 0x5a, 0x55, 0xea,
+	0xbf, 0x11, 0xc6, 0x6b, 0x1f, 0xfd, 0x11, 0x56, 0x5d, 0x16, 0x8c, 0x59, 0x48, 0x43, 0x01, 0x79,
+	0x53, 0xad, 0x75, 0x41, 0x37, 0x70, 0xc9, 0xf1, 0xbc, 0x88, 0x72, 0x0e, 0xf1, 0x51, 0xad, 0xfc,
+	0xb8, 0x35, 0x65, 0xbb, 0xff, 0x9a, 0xb2, 0xc2, 0xb6, 0x94, 0xbd, 0xc0, 0xa5, 0x91, 0x23, 0x68,
+	0xe8, 0x4e, 0x8c, 0xe2, 0xed, 0xbf, 0x83, 0x9c, 0xa9, 0x7f, 0xdf, 0xc1, 0xda, 0xc6, 0x52, 0xf4,
+	0x87, 0x58, 0xcd, 0xd6, 0xe7, 0x7b, 0xd9, 0x83, 0x96, 0xd3, 0xc2, 0xb9, 0xf7, 0x5f, 0x7e, 0x33,
+	0x5b, 0x52, 0x5e, 0xdc, 0x96, 0xf2, 0x16, 0xc6, 0x97, 0x31, 0x8d, 0x69, 0x7a, 0x75, 0xec, 0xdd,
+	0x7e, 0x65, 0x2a, 0x60, 0xf2, 0xee, 0x68, 0x9d, 0x4d, 0x67, 0x44, 0xb9, 0x9e, 0x11, 0x65, 0x39,
+	0x23, 0xe8, 0x63, 0x42, 0xd0, 0xb7, 0x84, 0xa0, 0xab, 0x84, 0xa0, 0x69, 0x42, 0xd0, 0xaf, 0x84,
+	0xa0, 0x45, 0x42, 0x94, 0x65, 0x42, 0xd0, 0xa7, 0x39, 0x51, 0xa6, 0x73, 0xa2, 0x5c, 0xcf, 0x89,
+	0xf2, 0x2e, 0xbd, 0xfa, 0x7a, 0x7b, 0x60, 0xf2, 0xe4, 0xcf, 0x00, 0x02, 0xf7, 0x8c, 0x18, 0x17,
+	0x05, 0x00, 0x00,
+}
+
+func (this *StoreStats) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Stats)
+	that1, ok := that.(*StoreStats)
 	if !ok {
-		that2, ok := that.(Stats)
+		that2, ok := that.(StoreStats)
 		if ok {
 			that1 = &that2
 		} else {
@@ -188,7 +376,10 @@ func (this *Stats) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.WallTime != that1.WallTime {
+	if this.Component != that1.Component {
+		return false
+	}
+	if this.Address != that1.Address {
 		return false
 	}
 	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
@@ -197,19 +388,46 @@ func (this *Stats) Equal(that interface{}) bool {
 	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
 		return false
 	}
-	if this.FetchedChunksCount != that1.FetchedChunksCount {
+	if this.Latency != that1.Latency {
 		return false
 	}
-	if this.ShardedQueries != that1.ShardedQueries {
+	return true
+}
+func (this *TenantStats) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TenantStats)
+	if !ok {
+		that2, ok := that.(TenantStats)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
 		return false
 	}
-	if this.SplitQueries != that1.SplitQueries {
+	if this.TenantId != that1.TenantId {
+		return false
+	}
+	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
+		return false
+	}
+	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
+		return false
+	}
+	if this.FetchedChunksCount != that1.FetchedChunksCount {
 		return false
 	}
 	if this.FetchedIndexBytes != that1.FetchedIndexBytes {
 		return false
 	}
-	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+	if this.QueueTime != that1.QueueTime {
 		return false
 	}
 	return true
@@ -218,7 +436,7 @@ func (this *Stats) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 12)
+	s := make([]string, 0, 15)
 	s = append(s, "&stats.Stats{")
 	s = append(s, "WallTime: "+fmt.Sprintf("%#v", this.WallTime)+",\n")
 	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
@@ -228,6 +446,50 @@ func (this *Stats) GoString() string {
 	s = append(s, "SplitQueries: "+fmt.Sprintf("%#v", this.SplitQueries)+",\n")
 	s = append(s, "FetchedIndexBytes: "+fmt.Sprintf("%#v", this.FetchedIndexBytes)+",\n")
 	s = append(s, "EstimatedSeriesCount: "+fmt.Sprintf("%#v", this.EstimatedSeriesCount)+",\n")
+	s = append(s, "EstimatedPeakMemoryConsumptionBytes: "+fmt.Sprintf("%#v", this.EstimatedPeakMemoryConsumptionBytes)+",\n")
+	if this.StoreStats != nil {
+		vs := make([]*StoreStats, len(this.StoreStats))
+		for i := range vs {
+			vs[i] = &this.StoreStats[i]
+		}
+		s = append(s, "StoreStats: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	if this.TenantStats != nil {
+		vs := make([]*TenantStats, len(this.TenantStats))
+		for i := range vs {
+			vs[i] = &this.TenantStats[i]
+		}
+		s = append(s, "TenantStats: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StoreStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&stats.StoreStats{")
+	s = append(s, "Component: "+fmt.Sprintf("%#v", this.Component)+",\n")
+	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "Latency: "+fmt.Sprintf("%#v", this.Latency)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TenantStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&stats.TenantStats{")
+	s = append(s, "TenantId: "+fmt.Sprintf("%#v", this.TenantId)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "FetchedChunksCount: "+fmt.Sprintf("%#v", this.FetchedChunksCount)+",\n")
+	s = append(s, "FetchedIndexBytes: "+fmt.Sprintf("%#v", this.FetchedIndexBytes)+",\n")
+	s = append(s, "QueueTime: "+fmt.Sprintf("%#v", this.QueueTime)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -259,6 +521,39 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.TenantStats) > 0 {
+		for iNdEx := len(m.TenantStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TenantStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStats(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.StoreStats) > 0 {
+		for iNdEx := len(m.StoreStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.StoreStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStats(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if m.EstimatedPeakMemoryConsumptionBytes != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.EstimatedPeakMemoryConsumptionBytes))
+		i--
+		dAtA[i] = 0x48
+	}
 	if m.EstimatedSeriesCount != 0 {
 		i = encodeVarintStats(dAtA, i, uint64(m.EstimatedSeriesCount))
 		i--
@@ -305,59 +600,249 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintStats(dAtA []byte, offset int, v uint64) int {
-	offset -= sovStats(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *StoreStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *Stats) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *StoreStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StoreStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.WallTime)
-	n += 1 + l + sovStats(uint64(l))
-	if m.FetchedSeriesCount != 0 {
-		n += 1 + sovStats(uint64(m.FetchedSeriesCount))
+	n2, err2 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Latency, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Latency):])
+	if err2 != nil {
+		return 0, err2
 	}
+	i -= n2
+	i = encodeVarintStats(dAtA, i, uint64(n2))
+	i--
+	dAtA[i] = 0x2a
 	if m.FetchedChunkBytes != 0 {
-		n += 1 + sovStats(uint64(m.FetchedChunkBytes))
-	}
-	if m.FetchedChunksCount != 0 {
-		n += 1 + sovStats(uint64(m.FetchedChunksCount))
+		i = encodeVarintStats(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x20
 	}
-	if m.ShardedQueries != 0 {
-		n += 1 + sovStats(uint64(m.ShardedQueries))
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.SplitQueries != 0 {
-		n += 1 + sovStats(uint64(m.SplitQueries))
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintStats(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.FetchedIndexBytes != 0 {
-		n += 1 + sovStats(uint64(m.FetchedIndexBytes))
+	if len(m.Component) > 0 {
+		i -= len(m.Component)
+		copy(dAtA[i:], m.Component)
+		i = encodeVarintStats(dAtA, i, uint64(len(m.Component)))
+		i--
+		dAtA[i] = 0xa
 	}
-	if m.EstimatedSeriesCount != 0 {
-		n += 1 + sovStats(uint64(m.EstimatedSeriesCount))
+	return len(dAtA) - i, nil
+}
+
+func (m *TenantStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func sovStats(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+func (m *TenantStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func sozStats(x uint64) (n int) {
+
+func (m *TenantStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.QueueTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.QueueTime):])
+	if err3 != nil {
+		return 0, err3
+	}
+	i -= n3
+	i = encodeVarintStats(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x32
+	if m.FetchedIndexBytes != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.FetchedIndexBytes))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FetchedChunksCount != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.FetchedChunksCount))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.FetchedChunkBytes != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TenantId) > 0 {
+		i -= len(m.TenantId)
+		copy(dAtA[i:], m.TenantId)
+		i = encodeVarintStats(dAtA, i, uint64(len(m.TenantId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintStats(dAtA []byte, offset int, v uint64) int {
+	offset -= sovStats(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Stats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.WallTime)
+	n += 1 + l + sovStats(uint64(l))
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovStats(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovStats(uint64(m.FetchedChunkBytes))
+	}
+	if m.FetchedChunksCount != 0 {
+		n += 1 + sovStats(uint64(m.FetchedChunksCount))
+	}
+	if m.ShardedQueries != 0 {
+		n += 1 + sovStats(uint64(m.ShardedQueries))
+	}
+	if m.SplitQueries != 0 {
+		n += 1 + sovStats(uint64(m.SplitQueries))
+	}
+	if m.FetchedIndexBytes != 0 {
+		n += 1 + sovStats(uint64(m.FetchedIndexBytes))
+	}
+	if m.EstimatedSeriesCount != 0 {
+		n += 1 + sovStats(uint64(m.EstimatedSeriesCount))
+	}
+	if m.EstimatedPeakMemoryConsumptionBytes != 0 {
+		n += 1 + sovStats(uint64(m.EstimatedPeakMemoryConsumptionBytes))
+	}
+	if len(m.StoreStats) > 0 {
+		for _, e := range m.StoreStats {
+			l = e.Size()
+			n += 1 + l + sovStats(uint64(l))
+		}
+	}
+	if len(m.TenantStats) > 0 {
+		for _, e := range m.TenantStats {
+			l = e.Size()
+			n += 1 + l + sovStats(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StoreStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Component)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovStats(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovStats(uint64(m.FetchedChunkBytes))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Latency)
+	n += 1 + l + sovStats(uint64(l))
+	return n
+}
+
+func (m *TenantStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TenantId)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovStats(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovStats(uint64(m.FetchedChunkBytes))
+	}
+	if m.FetchedChunksCount != 0 {
+		n += 1 + sovStats(uint64(m.FetchedChunksCount))
+	}
+	if m.FetchedIndexBytes != 0 {
+		n += 1 + sovStats(uint64(m.FetchedIndexBytes))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.QueueTime)
+	n += 1 + l + sovStats(uint64(l))
+	return n
+}
+
+func sovStats(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozStats(x uint64) (n int) {
 	return sovStats(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
 func (this *Stats) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForStoreStats := "[]StoreStats{"
+	for _, f := range this.StoreStats {
+		repeatedStringForStoreStats += strings.Replace(strings.Replace(f.String(), "StoreStats", "StoreStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForStoreStats += "}"
+	repeatedStringForTenantStats := "[]TenantStats{"
+	for _, f := range this.TenantStats {
+		repeatedStringForTenantStats += strings.Replace(strings.Replace(f.String(), "TenantStats", "TenantStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForTenantStats += "}"
 	s := strings.Join([]string{`&Stats{`,
 		`WallTime:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.WallTime), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
 		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
@@ -367,6 +852,38 @@ func (this *Stats) String() string {
 		`SplitQueries:` + fmt.Sprintf("%v", this.SplitQueries) + `,`,
 		`FetchedIndexBytes:` + fmt.Sprintf("%v", this.FetchedIndexBytes) + `,`,
 		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
+		`EstimatedPeakMemoryConsumptionBytes:` + fmt.Sprintf("%v", this.EstimatedPeakMemoryConsumptionBytes) + `,`,
+		`StoreStats:` + repeatedStringForStoreStats + `,`,
+		`TenantStats:` + repeatedStringForTenantStats + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StoreStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StoreStats{`,
+		`Component:` + fmt.Sprintf("%v", this.Component) + `,`,
+		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`Latency:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Latency), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantStats{`,
+		`TenantId:` + fmt.Sprintf("%v", this.TenantId) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`FetchedChunksCount:` + fmt.Sprintf("%v", this.FetchedChunksCount) + `,`,
+		`FetchedIndexBytes:` + fmt.Sprintf("%v", this.FetchedIndexBytes) + `,`,
+		`QueueTime:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.QueueTime), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -574,6 +1091,475 @@ func (m *Stats) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedPeakMemoryConsumptionBytes", wireType)
+			}
+			m.EstimatedPeakMemoryConsumptionBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EstimatedPeakMemoryConsumptionBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StoreStats = append(m.StoreStats, StoreStats{})
+			if err := m.StoreStats[len(m.StoreStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TenantStats = append(m.TenantStats, TenantStats{})
+			if err := m.TenantStats[len(m.TenantStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StoreStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Component", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Component = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
+			}
+			m.FetchedSeriesCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
+			}
+			m.FetchedChunkBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Latency", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Latency, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TenantId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
+			}
+			m.FetchedSeriesCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
+			}
+			m.FetchedChunkBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunksCount", wireType)
+			}
+			m.FetchedChunksCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunksCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedIndexBytes", wireType)
+			}
+			m.FetchedIndexBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedIndexBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.QueueTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -115,6 +115,65 @@ func TestStats_AddSplitQueries(t *testing.T) {
 	})
 }
 
+func TestStats_UpdateEstimatedPeakMemoryConsumption(t *testing.T) {
+	t.Run("update and load peak memory consumption", func(t *testing.T) {
+		stats, _ := ContextWithEmptyStats(context.Background())
+		stats.UpdateEstimatedPeakMemoryConsumption(100)
+		stats.UpdateEstimatedPeakMemoryConsumption(300)
+		stats.UpdateEstimatedPeakMemoryConsumption(200)
+
+		assert.Equal(t, uint64(300), stats.LoadEstimatedPeakMemoryConsumption())
+	})
+
+	t.Run("update and load peak memory consumption nil receiver", func(t *testing.T) {
+		var stats *Stats
+		stats.UpdateEstimatedPeakMemoryConsumption(100)
+
+		assert.Equal(t, uint64(0), stats.LoadEstimatedPeakMemoryConsumption())
+	})
+}
+
+func TestStats_AddStoreStats(t *testing.T) {
+	t.Run("add and load store stats", func(t *testing.T) {
+		stats, _ := ContextWithEmptyStats(context.Background())
+		stats.AddStoreStats(StoreStats{Component: "ingester", Address: "ingester-1", FetchedSeriesCount: 10})
+		stats.AddStoreStats(StoreStats{Component: "store-gateway", Address: "store-gateway-1", FetchedChunkBytes: 100, Latency: time.Second})
+
+		assert.Equal(t, []StoreStats{
+			{Component: "ingester", Address: "ingester-1", FetchedSeriesCount: 10},
+			{Component: "store-gateway", Address: "store-gateway-1", FetchedChunkBytes: 100, Latency: time.Second},
+		}, stats.LoadStoreStats())
+	})
+
+	t.Run("add and load store stats nil receiver", func(t *testing.T) {
+		var stats *Stats
+		stats.AddStoreStats(StoreStats{Component: "ingester", Address: "ingester-1"})
+
+		assert.Nil(t, stats.LoadStoreStats())
+	})
+}
+
+func TestStats_AddTenantStats(t *testing.T) {
+	t.Run("add and load tenant stats", func(t *testing.T) {
+		stats, _ := ContextWithEmptyStats(context.Background())
+		stats.AddTenantStats(TenantStats{TenantId: "team-b", FetchedSeriesCount: 10, QueueTime: time.Second})
+		stats.AddTenantStats(TenantStats{TenantId: "team-a", FetchedChunkBytes: 100})
+		stats.AddTenantStats(TenantStats{TenantId: "team-b", FetchedSeriesCount: 5, FetchedChunksCount: 2, QueueTime: time.Second})
+
+		assert.Equal(t, []TenantStats{
+			{TenantId: "team-a", FetchedChunkBytes: 100},
+			{TenantId: "team-b", FetchedSeriesCount: 15, FetchedChunksCount: 2, QueueTime: 2 * time.Second},
+		}, stats.LoadTenantStats())
+	})
+
+	t.Run("add and load tenant stats nil receiver", func(t *testing.T) {
+		var stats *Stats
+		stats.AddTenantStats(TenantStats{TenantId: "team-a"})
+
+		assert.Nil(t, stats.LoadTenantStats())
+	})
+}
+
 func TestStats_Merge(t *testing.T) {
 	t.Run("merge two stats objects", func(t *testing.T) {
 		stats1 := &Stats{}
@@ -124,6 +183,9 @@ func TestStats_Merge(t *testing.T) {
 		stats1.AddFetchedChunks(10)
 		stats1.AddShardedQueries(20)
 		stats1.AddSplitQueries(10)
+		stats1.UpdateEstimatedPeakMemoryConsumption(1024)
+		stats1.AddStoreStats(StoreStats{Component: "ingester", Address: "ingester-1"})
+		stats1.AddTenantStats(TenantStats{TenantId: "team-a", FetchedSeriesCount: 10})
 
 		stats2 := &Stats{}
 		stats2.AddWallTime(time.Second)
@@ -132,6 +194,10 @@ func TestStats_Merge(t *testing.T) {
 		stats2.AddFetchedChunks(11)
 		stats2.AddShardedQueries(21)
 		stats2.AddSplitQueries(11)
+		stats2.UpdateEstimatedPeakMemoryConsumption(512)
+		stats2.AddStoreStats(StoreStats{Component: "store-gateway", Address: "store-gateway-1"})
+		stats2.AddTenantStats(TenantStats{TenantId: "team-a", FetchedSeriesCount: 5})
+		stats2.AddTenantStats(TenantStats{TenantId: "team-b", FetchedSeriesCount: 20})
 
 		stats1.Merge(stats2)
 
@@ -141,6 +207,15 @@ func TestStats_Merge(t *testing.T) {
 		assert.Equal(t, uint64(21), stats1.LoadFetchedChunks())
 		assert.Equal(t, uint32(41), stats1.LoadShardedQueries())
 		assert.Equal(t, uint32(21), stats1.LoadSplitQueries())
+		assert.Equal(t, uint64(1024), stats1.LoadEstimatedPeakMemoryConsumption())
+		assert.Equal(t, []StoreStats{
+			{Component: "ingester", Address: "ingester-1"},
+			{Component: "store-gateway", Address: "store-gateway-1"},
+		}, stats1.LoadStoreStats())
+		assert.Equal(t, []TenantStats{
+			{TenantId: "team-a", FetchedSeriesCount: 15},
+			{TenantId: "team-b", FetchedSeriesCount: 20},
+		}, stats1.LoadTenantStats())
 	})
 
 	t.Run("merge two nil stats objects", func(t *testing.T) {
@@ -157,3 +232,16 @@ func TestStats_Merge(t *testing.T) {
 		assert.Equal(t, uint32(0), stats1.LoadSplitQueries())
 	})
 }
+
+func TestStats_Equal(t *testing.T) {
+	stats1 := &Stats{FetchedSeriesCount: 1}
+	stats1.AddStoreStats(StoreStats{Component: "ingester", Address: "ingester-1"})
+
+	stats2 := &Stats{FetchedSeriesCount: 1}
+	stats2.AddStoreStats(StoreStats{Component: "ingester", Address: "ingester-1"})
+
+	assert.True(t, stats1.Equal(stats2))
+	assert.False(t, stats1.Equal(&Stats{FetchedSeriesCount: 1}))
+	assert.False(t, stats1.Equal(nil))
+	assert.True(t, (*Stats)(nil).Equal(nil))
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package stats
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// StoreStatsHeader is the HTTP request header used to ask the querier to track per-store statistics.
+	StoreStatsHeader = "X-Mimir-Store-Stats"
+
+	// Components reported in the per-store statistics.
+	StoreComponentIngester     = "ingester"
+	StoreComponentStoreGateway = "store-gateway"
+)
+
+type storeStatsContextKey int
+
+var storeStatsCtxKey = storeStatsContextKey(0)
+
+// ContextWithStoreStatsEnabled returns a context with per-store statistics tracking enabled.
+func ContextWithStoreStatsEnabled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, storeStatsCtxKey, true)
+}
+
+// IsStoreStatsEnabled returns whether per-store statistics should be tracked in the context.
+// Per-store statistics are tracked only if stats tracking is enabled too.
+func IsStoreStatsEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(storeStatsCtxKey).(bool)
+	return enabled && IsEnabled(ctx)
+}
+
+// StoreStatsMiddleware enables the tracking of per-store statistics when requested via StoreStatsHeader.
+type StoreStatsMiddleware struct{}
+
+// NewStoreStatsMiddleware makes a new StoreStatsMiddleware.
+func NewStoreStatsMiddleware() StoreStatsMiddleware {
+	return StoreStatsMiddleware{}
+}
+
+// Wrap implements middleware.Interface.
+func (m StoreStatsMiddleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if enabled, _ := strconv.ParseBool(r.Header.Get(StoreStatsHeader)); enabled {
+			r = r.WithContext(ContextWithStoreStatsEnabled(r.Context()))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
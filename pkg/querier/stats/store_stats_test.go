@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package stats
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreStatsMiddleware(t *testing.T) {
+	testCases := map[string]struct {
+		header        string
+		statsEnabled  bool
+		expectEnabled bool
+	}{
+		"header not set": {
+			statsEnabled:  true,
+			expectEnabled: false,
+		},
+		"header set": {
+			header:        "true",
+			statsEnabled:  true,
+			expectEnabled: true,
+		},
+		"header set but stats disabled": {
+			header:        "true",
+			statsEnabled:  false,
+			expectEnabled: false,
+		},
+		"header set to an invalid value": {
+			header:        "foo",
+			statsEnabled:  true,
+			expectEnabled: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var actualEnabled bool
+			handler := NewStoreStatsMiddleware().Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actualEnabled = IsStoreStatsEnabled(r.Context())
+			}))
+
+			ctx := context.Background()
+			if testCase.statsEnabled {
+				_, ctx = ContextWithEmptyStats(ctx)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			if testCase.header != "" {
+				req.Header.Set(StoreStatsHeader, testCase.header)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, testCase.expectEnabled, actualEnabled)
+		})
+	}
+}
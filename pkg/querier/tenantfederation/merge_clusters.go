@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tenantfederation
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const defaultClusterLabel = "__cluster__"
+
+// ClusterQueryable is the queryable of a single cluster of a federated query.
+type ClusterQueryable struct {
+	Name      string
+	Queryable storage.Queryable
+}
+
+// NewClustersQueryable returns a queryable that merges the results of the local cluster with the results
+// of the remote clusters, usually created with querier.NewRemoteReadQueryable. The result contains a
+// label "__cluster__" to identify the cluster that it originally resulted from. At most maxConcurrency
+// clusters are queried concurrently. The queriers of all clusters share the same QueryLimiter, so that the
+// tenant's query limits apply to the series and chunks fetched from all clusters together.
+// Remote read requests sent by a remote cluster are only served from the local cluster, without the
+// "__cluster__" label, to avoid loops between clusters querying each other.
+func NewClustersQueryable(local ClusterQueryable, remotes []ClusterQueryable, limits *validation.Overrides, queryMetrics *stats.QueryMetrics, maxConcurrency int, logger log.Logger) storage.Queryable {
+	return NewMergeQueryable(defaultClusterLabel, clusterQuerierCallback(local, remotes, limits, queryMetrics), true, maxConcurrency, logger)
+}
+
+func clusterQuerierCallback(local ClusterQueryable, remotes []ClusterQueryable, limits *validation.Overrides, queryMetrics *stats.QueryMetrics) MergeQuerierCallback {
+	return func(ctx context.Context, mint int64, maxt int64) ([]string, []storage.Querier, error) {
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx = limiter.AddQueryLimiterToContext(ctx, querier.NewQueryLimiter(userID, limits, queryMetrics))
+
+		clusters := []ClusterQueryable{local}
+		if !querier.IsRemoteClusterRead(ctx) {
+			clusters = append(clusters, remotes...)
+		}
+
+		ids := make([]string, 0, len(clusters))
+		queriers := make([]storage.Querier, 0, len(clusters))
+		for _, cluster := range clusters {
+			q, err := cluster.Queryable.Querier(ctx, mint, maxt)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			ids = append(ids, cluster.Name)
+			queriers = append(queriers, q)
+		}
+
+		return ids, queriers, nil
+	}
+}
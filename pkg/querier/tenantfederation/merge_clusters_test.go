@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tenantfederation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/mimir/pkg/querier"
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/storage/series"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// clusterQuerier is a storage.Querier returning a single series with the input labels.
+type clusterQuerier struct {
+	storage.Querier
+
+	lbls     labels.Labels
+	warnings storage.Warnings
+}
+
+func (q *clusterQuerier) Select(bool, *storage.SelectHints, ...*labels.Matcher) storage.SeriesSet {
+	set := series.NewConcreteSeriesSetFromSortedSeries([]storage.Series{series.NewConcreteSeries(q.lbls, nil, nil)})
+	return series.NewSeriesSetWithWarnings(set, q.warnings)
+}
+
+func (q *clusterQuerier) Close() error {
+	return nil
+}
+
+func clusterQueryable(name string, warnings storage.Warnings, lbls ...string) ClusterQueryable {
+	return ClusterQueryable{
+		Name: name,
+		Queryable: storage.QueryableFunc(func(context.Context, int64, int64) (storage.Querier, error) {
+			return &clusterQuerier{lbls: labels.FromStrings(append([]string{labels.MetricName, "up"}, lbls...)...), warnings: warnings}, nil
+		}),
+	}
+}
+
+func TestClustersQueryable(t *testing.T) {
+	queryable := NewClustersQueryable(
+		clusterQueryable("us", nil),
+		[]ClusterQueryable{clusterQueryable("eu", nil), clusterQueryable("ap", nil, defaultClusterLabel, "ap-1")},
+		validation.MockDefaultOverrides(),
+		nil,
+		defaultMaxConcurrency,
+		log.NewNopLogger(),
+	)
+
+	selectLabels := func(t *testing.T, ctx context.Context, matchers ...*labels.Matcher) []labels.Labels {
+		q, err := queryable.Querier(ctx, mint, maxt)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, q.Close()) })
+
+		set := q.Select(true, nil, matchers...)
+		var actual []labels.Labels
+		for set.Next() {
+			actual = append(actual, set.At().Labels())
+		}
+		require.NoError(t, set.Err())
+		return actual
+	}
+
+	t.Run("series of all clusters have the cluster label", func(t *testing.T) {
+		queryStats, ctx := stats.ContextWithEmptyStats(user.InjectOrgID(context.Background(), "team-a"))
+		actual := selectLabels(t, ctx)
+		assert.Equal(t, []labels.Labels{
+			labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "ap", retainExistingPrefix+defaultClusterLabel, "ap-1"),
+			labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "eu"),
+			labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "us"),
+		}, actual)
+
+		// The clusters are not tracked as tenants in the query stats.
+		assert.Empty(t, queryStats.LoadTenantStats())
+	})
+
+	t.Run("only the clusters matching the cluster label matchers are queried", func(t *testing.T) {
+		actual := selectLabels(t, user.InjectOrgID(context.Background(), "team-a"), labels.MustNewMatcher(labels.MatchRegexp, defaultClusterLabel, "us|eu"))
+		assert.Equal(t, []labels.Labels{
+			labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "eu"),
+			labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "us"),
+		}, actual)
+	})
+
+	t.Run("label values of the cluster label", func(t *testing.T) {
+		q, err := queryable.Querier(user.InjectOrgID(context.Background(), "team-a"), mint, maxt)
+		require.NoError(t, err)
+
+		values, _, err := q.LabelValues(defaultClusterLabel)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"us", "eu", "ap"}, values)
+	})
+
+	t.Run("remote read from a remote cluster is only served by the local cluster", func(t *testing.T) {
+		ctx := querier.ContextWithRemoteClusterRead(user.InjectOrgID(context.Background(), "team-a"))
+		actual := selectLabels(t, ctx)
+		assert.Equal(t, []labels.Labels{labels.FromStrings(labels.MetricName, "up")}, actual)
+	})
+}
+
+func TestClustersQueryable_RemoteClusterWarnings(t *testing.T) {
+	queryable := NewClustersQueryable(
+		clusterQueryable("us", nil),
+		[]ClusterQueryable{clusterQueryable("eu", storage.Warnings{errors.New("remote read from cluster eu: timeout")})},
+		validation.MockDefaultOverrides(),
+		nil,
+		defaultMaxConcurrency,
+		log.NewNopLogger(),
+	)
+
+	q, err := queryable.Querier(user.InjectOrgID(context.Background(), "team-a"), mint, maxt)
+	require.NoError(t, err)
+
+	set := q.Select(true, nil)
+	var actual []labels.Labels
+	for set.Next() {
+		actual = append(actual, set.At().Labels())
+	}
+	require.NoError(t, set.Err())
+	assert.Equal(t, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "eu"),
+		labels.FromStrings(labels.MetricName, "up", defaultClusterLabel, "us"),
+	}, actual)
+	assertEqualWarnings(t, []string{"warning querying cluster eu: remote read from cluster eu: timeout"}, set.Warnings())
+}
+
+func TestClustersQueryable_ShouldShareTheQueryLimiterAcrossClusters(t *testing.T) {
+	var queryLimiters []*limiter.QueryLimiter
+	trackingClusterQueryable := func(name string) ClusterQueryable {
+		return ClusterQueryable{
+			Name: name,
+			Queryable: storage.QueryableFunc(func(ctx context.Context, _, _ int64) (storage.Querier, error) {
+				queryLimiter, ok := limiter.QueryLimiterFromContext(ctx)
+				require.True(t, ok)
+				queryLimiters = append(queryLimiters, queryLimiter)
+				return &clusterQuerier{lbls: labels.FromStrings(labels.MetricName, "up")}, nil
+			}),
+		}
+	}
+
+	queryable := NewClustersQueryable(
+		trackingClusterQueryable("us"),
+		[]ClusterQueryable{trackingClusterQueryable("eu"), trackingClusterQueryable("ap")},
+		validation.MockDefaultOverrides(),
+		nil,
+		defaultMaxConcurrency,
+		log.NewNopLogger(),
+	)
+
+	q, err := queryable.Querier(user.InjectOrgID(context.Background(), "team-a"), mint, maxt)
+	require.NoError(t, err)
+	require.NoError(t, q.Close())
+
+	require.Len(t, queryLimiters, 3)
+	assert.Same(t, queryLimiters[0], queryLimiters[1])
+	assert.Same(t, queryLimiters[0], queryLimiters[2])
+}
@@ -21,18 +21,20 @@ import (
 // metadata for all tenant IDs that are part of the request and merges the results.
 //
 // No deduplication of metadata is done before being returned.
-func NewMetadataSupplier(next querier.MetadataSupplier, logger log.Logger) querier.MetadataSupplier {
+func NewMetadataSupplier(next querier.MetadataSupplier, maxConcurrency int, logger log.Logger) querier.MetadataSupplier {
 	return &mergeMetadataSupplier{
-		next:     next,
-		logger:   logger,
-		resolver: tenant.NewMultiResolver(),
+		next:           next,
+		logger:         logger,
+		resolver:       tenant.NewMultiResolver(),
+		maxConcurrency: maxConcurrency,
 	}
 }
 
 type mergeMetadataSupplier struct {
-	next     querier.MetadataSupplier
-	resolver tenant.Resolver
-	logger   log.Logger
+	next           querier.MetadataSupplier
+	resolver       tenant.Resolver
+	maxConcurrency int
+	logger         log.Logger
 }
 
 func (m *mergeMetadataSupplier) MetricsMetadata(ctx context.Context) ([]scrape.MetricMetadata, error) {
@@ -46,7 +48,7 @@ func (m *mergeMetadataSupplier) MetricsMetadata(ctx context.Context) ([]scrape.M
 
 	if len(tenantIDs) == 1 {
 		level.Debug(spanlog).Log("msg", "only a single tenant, bypassing federated metadata supplier")
-		return m.next.MetricsMetadata(ctx)
+		return m.next.MetricsMetadata(user.InjectOrgID(ctx, tenantIDs[0]))
 	}
 
 	results := make([][]scrape.MetricMetadata, len(tenantIDs))
@@ -62,7 +64,7 @@ func (m *mergeMetadataSupplier) MetricsMetadata(ctx context.Context) ([]scrape.M
 		return nil
 	}
 
-	err = concurrency.ForEachJob(ctx, len(tenantIDs), maxConcurrency, run)
+	err = concurrency.ForEachJob(ctx, len(tenantIDs), m.maxConcurrency, run)
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/concurrency"
@@ -21,6 +23,7 @@ import (
 	"github.com/weaveworks/common/user"
 	"golang.org/x/exp/slices"
 
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 )
 
@@ -36,8 +39,11 @@ import (
 // If the label "__tenant_id__" is already existing, its value is overwritten
 // by the tenant ID and the previous value is exposed through a new label
 // prefixed with "original_". This behaviour is not implemented recursively.
-func NewQueryable(upstream storage.Queryable, byPassWithSingleQuerier bool, logger log.Logger) storage.Queryable {
-	return NewMergeQueryable(defaultTenantLabel, tenantQuerierCallback(upstream), byPassWithSingleQuerier, logger)
+// Each tenant's querier is created with a context holding only that tenant ID, so
+// that the tenant's own query limits are applied to its sub-queries. At most
+// maxConcurrency per-tenant sub-queries are run concurrently.
+func NewQueryable(upstream storage.Queryable, byPassWithSingleQuerier bool, maxConcurrency int, logger log.Logger) storage.Queryable {
+	return NewMergeQueryable(defaultTenantLabel, tenantQuerierCallback(upstream), byPassWithSingleQuerier, maxConcurrency, logger)
 }
 
 func tenantQuerierCallback(queryable storage.Queryable) MergeQuerierCallback {
@@ -47,16 +53,31 @@ func tenantQuerierCallback(queryable storage.Queryable) MergeQuerierCallback {
 			return nil, nil, err
 		}
 
+		// Track the stats of each tenant on their own when the query spans multiple tenants.
+		queryStats := stats.FromContext(ctx)
+		trackTenantStats := queryStats != nil && len(tenantIDs) > 1
+
 		var queriers = make([]storage.Querier, len(tenantIDs))
 		for pos, tenantID := range tenantIDs {
+			tenantCtx := user.InjectOrgID(ctx, tenantID)
+
+			var tenantStats *stats.Stats
+			if trackTenantStats {
+				tenantStats, tenantCtx = stats.ContextWithEmptyStats(tenantCtx)
+			}
+
 			q, err := queryable.Querier(
-				user.InjectOrgID(ctx, tenantID),
+				tenantCtx,
 				mint,
 				maxt,
 			)
 			if err != nil {
 				return nil, nil, err
 			}
+
+			if trackTenantStats {
+				q = &tenantStatsQuerier{Querier: q, tenantID: tenantID, queryStats: queryStats, tenantStats: tenantStats}
+			}
 			queriers[pos] = q
 		}
 
@@ -64,6 +85,32 @@ func tenantQuerierCallback(queryable storage.Queryable) MergeQuerierCallback {
 	}
 }
 
+// tenantStatsQuerier is a storage.Querier tracking the stats of a single tenant of a federated query.
+// The tenant stats are merged into the stats of the whole query when the querier is closed.
+type tenantStatsQuerier struct {
+	storage.Querier
+
+	tenantID    string
+	queryStats  *stats.Stats
+	tenantStats *stats.Stats
+	closeOnce   sync.Once
+}
+
+func (q *tenantStatsQuerier) Close() error {
+	q.closeOnce.Do(func() {
+		q.queryStats.Merge(q.tenantStats)
+		q.queryStats.AddTenantStats(stats.TenantStats{
+			TenantId:           q.tenantID,
+			FetchedSeriesCount: q.tenantStats.LoadFetchedSeries(),
+			FetchedChunkBytes:  q.tenantStats.LoadFetchedChunkBytes(),
+			FetchedChunksCount: q.tenantStats.LoadFetchedChunks(),
+			FetchedIndexBytes:  q.tenantStats.LoadFetchedIndexBytes(),
+		})
+	})
+
+	return q.Querier.Close()
+}
+
 // MergeQuerierCallback returns the underlying queriers and their IDs relevant
 // for the query.
 type MergeQuerierCallback func(ctx context.Context, mint int64, maxt int64) (ids []string, queriers []storage.Querier, err error)
@@ -80,12 +127,13 @@ type MergeQuerierCallback func(ctx context.Context, mint int64, maxt int64) (ids
 // If the label `idLabelName` is already existing, its value is overwritten and
 // the previous value is exposed through a new label prefixed with "original_".
 // This behaviour is not implemented recursively.
-func NewMergeQueryable(idLabelName string, callback MergeQuerierCallback, byPassWithSingleQuerier bool, logger log.Logger) storage.Queryable {
+func NewMergeQueryable(idLabelName string, callback MergeQuerierCallback, byPassWithSingleQuerier bool, maxConcurrency int, logger log.Logger) storage.Queryable {
 	return &mergeQueryable{
 		logger:                  logger,
 		idLabelName:             idLabelName,
 		callback:                callback,
 		bypassWithSingleQuerier: byPassWithSingleQuerier,
+		maxConcurrency:          maxConcurrency,
 	}
 }
 
@@ -93,6 +141,7 @@ type mergeQueryable struct {
 	logger                  log.Logger
 	idLabelName             string
 	bypassWithSingleQuerier bool
+	maxConcurrency          int
 	callback                MergeQuerierCallback
 }
 
@@ -113,11 +162,12 @@ func (m *mergeQueryable) Querier(ctx context.Context, mint int64, maxt int64) (s
 	}
 
 	return &mergeQuerier{
-		logger:      m.logger,
-		ctx:         ctx,
-		idLabelName: m.idLabelName,
-		queriers:    queriers,
-		ids:         ids,
+		logger:         m.logger,
+		ctx:            ctx,
+		idLabelName:    m.idLabelName,
+		queriers:       queriers,
+		ids:            ids,
+		maxConcurrency: m.maxConcurrency,
 	}, nil
 }
 
@@ -128,11 +178,12 @@ func (m *mergeQueryable) Querier(ctx context.Context, mint int64, maxt int64) (s
 // the previous value is exposed through a new label prefixed with "original_".
 // This behaviour is not implemented recursively
 type mergeQuerier struct {
-	logger      log.Logger
-	ctx         context.Context
-	queriers    []storage.Querier
-	idLabelName string
-	ids         []string
+	logger         log.Logger
+	ctx            context.Context
+	queriers       []storage.Querier
+	idLabelName    string
+	ids            []string
+	maxConcurrency int
 }
 
 // LabelValues returns all potential values for a label name.  It is not safe
@@ -246,7 +297,7 @@ func (m *mergeQuerier) mergeDistinctStringSliceWithTenants(f stringSliceFunc, te
 		return nil
 	}
 
-	err := concurrency.ForEachJob(m.ctx, len(jobs), maxConcurrency, run)
+	err := m.forEachTenantJob(m.ctx, len(jobs), func(idx int) string { return jobs[idx].id }, run)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -324,7 +375,7 @@ func (m *mergeQuerier) Select(sortSeries bool, hints *storage.SelectHints, match
 		return nil
 	}
 
-	err := concurrency.ForEachJob(ctx, len(jobs), maxConcurrency, run)
+	err := m.forEachTenantJob(ctx, len(jobs), func(idx int) string { return jobs[idx].id }, run)
 	if err != nil {
 		return storage.ErrSeriesSet(err)
 	}
@@ -332,6 +383,22 @@ func (m *mergeQuerier) Select(sortSeries bool, hints *storage.SelectHints, match
 	return storage.NewMergeSeriesSet(seriesSets, storage.ChainedSeriesMerge)
 }
 
+// forEachTenantJob runs the per-tenant jobs, with at most maxConcurrency jobs running at the same time.
+// The time each job has been queued waiting for a free worker is tracked in the per-tenant query stats,
+// when the jobs are per-tenant sub-queries.
+func (m *mergeQuerier) forEachTenantJob(ctx context.Context, jobs int, jobID func(idx int) string, run func(ctx context.Context, idx int) error) error {
+	queryStats := stats.FromContext(m.ctx)
+	trackQueueTime := m.idLabelName == defaultTenantLabel
+	queuedAt := time.Now()
+
+	return concurrency.ForEachJob(ctx, jobs, m.maxConcurrency, func(ctx context.Context, idx int) error {
+		if trackQueueTime {
+			queryStats.AddTenantStats(stats.TenantStats{TenantId: jobID(idx), QueueTime: time.Since(queuedAt)})
+		}
+		return run(ctx, idx)
+	})
+}
+
 type addLabelsSeriesSet struct {
 	upstream   storage.SeriesSet
 	labels     []labels.Label
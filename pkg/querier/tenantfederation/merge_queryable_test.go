@@ -24,8 +24,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
 	"golang.org/x/exp/slices"
 
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/series"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 )
@@ -266,7 +268,7 @@ type mergeQueryableScenario struct {
 
 func (s *mergeQueryableScenario) init() (storage.Querier, error) {
 	// initialize with default tenant label
-	q := NewQueryable(&s.queryable, !s.doNotByPassSingleQuerier, log.NewNopLogger())
+	q := NewQueryable(&s.queryable, !s.doNotByPassSingleQuerier, defaultMaxConcurrency, log.NewNopLogger())
 
 	// inject tenants into context
 	ctx := context.Background()
@@ -345,7 +347,7 @@ type labelValuesScenario struct {
 func TestMergeQueryable_Querier(t *testing.T) {
 	t.Run("querying without a tenant specified should error", func(t *testing.T) {
 		queryable := &mockTenantQueryableWithFilter{logger: log.NewNopLogger()}
-		q := NewQueryable(queryable, false /* bypassWithSingleQuerier */, log.NewNopLogger())
+		q := NewQueryable(queryable, false /* bypassWithSingleQuerier */, defaultMaxConcurrency, log.NewNopLogger())
 		// Create a context with no tenant specified.
 		ctx := context.Background()
 
@@ -853,6 +855,86 @@ func assertEqualWarnings(t *testing.T, exp []string, act storage.Warnings) {
 	assert.ElementsMatch(t, exp, actStrings)
 }
 
+// perTenantQuerier is a storage.Querier tracking the tenant and the stats of its context.
+type perTenantQuerier struct {
+	storage.Querier
+
+	ctx        context.Context
+	series     uint64
+	running    *atomic.Int32
+	maxRunning *atomic.Int32
+}
+
+func (q *perTenantQuerier) Select(bool, *storage.SelectHints, ...*labels.Matcher) storage.SeriesSet {
+	running := q.running.Inc()
+	defer q.running.Dec()
+
+	for maxRunning := q.maxRunning.Load(); running > maxRunning && !q.maxRunning.CAS(maxRunning, running); {
+		maxRunning = q.maxRunning.Load()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	stats.FromContext(q.ctx).AddFetchedSeries(q.series)
+	return storage.EmptySeriesSet()
+}
+
+func (q *perTenantQuerier) Close() error {
+	return nil
+}
+
+func TestMergeQueryable_PerTenantSubQueries(t *testing.T) {
+	var (
+		seriesPerTenant = map[string]uint64{"team-a": 1, "team-b": 2, "team-c": 3}
+		running         = atomic.NewInt32(0)
+		maxRunning      = atomic.NewInt32(0)
+		querierTenants  []string
+	)
+
+	upstream := storage.QueryableFunc(func(ctx context.Context, _, _ int64) (storage.Querier, error) {
+		// Each tenant's querier gets a context with only that tenant, so that its own limits are applied.
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		querierTenants = append(querierTenants, tenantID)
+
+		return &perTenantQuerier{ctx: ctx, series: seriesPerTenant[tenantID], running: running, maxRunning: maxRunning}, nil
+	})
+
+	const maxConcurrency = 1
+	queryable := NewQueryable(upstream, false, maxConcurrency, log.NewNopLogger())
+
+	queryStats, ctx := stats.ContextWithEmptyStats(user.InjectOrgID(context.Background(), "team-a|team-b|team-c"))
+	q, err := queryable.Querier(ctx, mint, maxt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b", "team-c"}, querierTenants)
+
+	set := q.Select(true, nil)
+	require.False(t, set.Next())
+	require.NoError(t, set.Err())
+	require.NoError(t, q.Close())
+
+	// The per-tenant sub-queries have been run one at a time.
+	assert.Equal(t, int32(maxConcurrency), maxRunning.Load())
+
+	// The stats of each tenant are tracked on their own, and merged into the stats of the whole query.
+	assert.Equal(t, uint64(6), queryStats.LoadFetchedSeries())
+
+	tenantStats := queryStats.LoadTenantStats()
+	require.Len(t, tenantStats, 3)
+
+	var totalQueueTime time.Duration
+	for i, tenantID := range []string{"team-a", "team-b", "team-c"} {
+		assert.Equal(t, tenantID, tenantStats[i].TenantId)
+		assert.Equal(t, seriesPerTenant[tenantID], tenantStats[i].FetchedSeriesCount)
+		totalQueueTime += tenantStats[i].QueueTime
+	}
+
+	// Since sub-queries have been run one at a time, the second and third ones have been queued
+	// for at least the duration of the sub-queries which run before them.
+	assert.GreaterOrEqual(t, totalQueueTime, 30*time.Millisecond)
+}
+
 func TestSetLabelsRetainExisting(t *testing.T) {
 	for _, tc := range []struct {
 		labels           labels.Labels
@@ -899,7 +981,7 @@ func TestTracingMergeQueryable(t *testing.T) {
 	// set a multi tenant resolver
 	tenant.WithDefaultResolver(tenant.NewMultiResolver())
 	filter := mockTenantQueryableWithFilter{}
-	q := NewQueryable(&filter, false, log.NewNopLogger())
+	q := NewQueryable(&filter, false, defaultMaxConcurrency, log.NewNopLogger())
 	// retrieve querier if set
 	querier, err := q.Querier(ctx, mint, maxt)
 	require.NoError(t, err)
@@ -6,24 +6,84 @@
 package tenantfederation
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"time"
 
 	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/mimir/pkg/querier"
 )
 
 const (
-	defaultTenantLabel   = "__tenant_id__"
-	retainExistingPrefix = "original_"
-	maxConcurrency       = 16
+	defaultTenantLabel    = "__tenant_id__"
+	retainExistingPrefix  = "original_"
+	defaultMaxConcurrency = 16
+)
+
+var (
+	errInvalidMaxConcurrent   = errors.New("the maximum number of concurrent per-tenant sub-queries must be greater than 0")
+	errEmptyLocalClusterName  = errors.New("the local cluster name must not be empty when remote clusters are configured")
+	errInvalidRefreshInterval = errors.New("the known tenants refresh interval must be greater than 0 when tenant ID patterns are enabled")
 )
 
 type Config struct {
 	// Enabled switches on support for multi tenant query federation
 	Enabled bool `yaml:"enabled"`
+
+	TenantPatternsEnabled       bool          `yaml:"tenant_patterns_enabled" category:"experimental"`
+	KnownTenantsRefreshInterval time.Duration `yaml:"known_tenants_refresh_interval" category:"experimental"`
+	MaxConcurrent               int           `yaml:"max_concurrent" category:"experimental"`
+
+	LocalClusterName string                            `yaml:"local_cluster_name" category:"experimental"`
+	RemoteClusters   []querier.RemoteReadClusterConfig `yaml:"remote_clusters" category:"experimental" doc:"nocli|description=Remote Mimir or Prometheus clusters queried through the remote read API, in addition to the local cluster. The series returned by each cluster have the '__cluster__' label set to the cluster name. Each remote cluster is configured with its 'name', the 'url' of its remote read endpoint, an optional per-request 'timeout', and optional 'basic_auth_username' and 'basic_auth_password'. Remote clusters are only queried when tenant federation is enabled."`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.Enabled, "tenant-federation.enabled", false, "If enabled on all services, queries can be federated across multiple tenants. The tenant IDs involved need to be specified separated by a '|' character in the 'X-Scope-OrgID' header.")
+	f.BoolVar(&cfg.TenantPatternsEnabled, "tenant-federation.tenant-patterns-enabled", false, "If enabled, the tenant IDs in the 'X-Scope-OrgID' header of federated queries can be patterns containing the '*' wildcard, which matches any sequence of characters. The query-frontend, or the querier when queried directly, resolves the patterns to the matching tenants with blocks in the storage or series in the ingesters, so it needs access to the blocks storage bucket and the ingesters ring. The tenant making the query must be set in the 'X-Caller-OrgID' header, and can only use the patterns allowed by -tenant-federation.allowed-tenant-patterns. If disabled, '*' is treated as a regular tenant ID character.")
+	f.DurationVar(&cfg.KnownTenantsRefreshInterval, "tenant-federation.known-tenants-refresh-interval", time.Minute, "How frequently the list of known tenants, used to resolve tenant ID patterns, is refreshed in the background.")
+	f.IntVar(&cfg.MaxConcurrent, "tenant-federation.max-concurrent", defaultMaxConcurrency, "The maximum number of per-tenant sub-queries executed concurrently by the querier for each tenant federated query. Sub-queries exceeding the limit are queued until a running one completes.")
+	f.StringVar(&cfg.LocalClusterName, "tenant-federation.local-cluster-name", "local", "The name of the local cluster, used as the value of the '__cluster__' label of the series returned by the local cluster when remote clusters are configured.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Enabled && cfg.MaxConcurrent <= 0 {
+		return errInvalidMaxConcurrent
+	}
+
+	if cfg.PatternsEnabled() && cfg.KnownTenantsRefreshInterval <= 0 {
+		return errInvalidRefreshInterval
+	}
+
+	if cfg.RemoteClustersEnabled() {
+		if cfg.LocalClusterName == "" {
+			return errEmptyLocalClusterName
+		}
+
+		names := map[string]struct{}{cfg.LocalClusterName: {}}
+		for _, cluster := range cfg.RemoteClusters {
+			if err := cluster.Validate(); err != nil {
+				return err
+			}
+			if _, ok := names[cluster.Name]; ok {
+				return fmt.Errorf("the cluster name %s is used by more than one cluster", cluster.Name)
+			}
+			names[cluster.Name] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// RemoteClustersEnabled returns whether remote clusters are queried in addition to the local cluster.
+func (cfg *Config) RemoteClustersEnabled() bool {
+	return cfg.Enabled && len(cfg.RemoteClusters) > 0
+}
+
+// PatternsEnabled returns whether tenant ID patterns are enabled in federated queries.
+func (cfg *Config) PatternsEnabled() bool {
+	return cfg.Enabled && cfg.TenantPatternsEnabled
 }
 
 // filterValuesByMatchers applies matchers to inputed `idLabelName` and
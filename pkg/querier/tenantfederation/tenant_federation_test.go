@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tenantfederation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/querier"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	remoteCluster := func(name string) querier.RemoteReadClusterConfig {
+		return querier.RemoteReadClusterConfig{Name: name, URL: "http://mimir-" + name + "/prometheus/api/v1/read", Timeout: time.Minute}
+	}
+
+	tests := map[string]struct {
+		cfg         Config
+		expectedErr string
+	}{
+		"valid": {
+			cfg: Config{Enabled: true, MaxConcurrent: 16, LocalClusterName: "us", RemoteClusters: []querier.RemoteReadClusterConfig{remoteCluster("eu"), remoteCluster("ap")}},
+		},
+		"invalid max concurrent": {
+			cfg:         Config{Enabled: true, MaxConcurrent: 0},
+			expectedErr: errInvalidMaxConcurrent.Error(),
+		},
+		"invalid max concurrent when tenant federation is disabled": {
+			cfg: Config{Enabled: false, MaxConcurrent: 0},
+		},
+		"invalid known tenants refresh interval": {
+			cfg:         Config{Enabled: true, MaxConcurrent: 16, TenantPatternsEnabled: true, KnownTenantsRefreshInterval: 0},
+			expectedErr: errInvalidRefreshInterval.Error(),
+		},
+		"invalid known tenants refresh interval when tenant ID patterns are disabled": {
+			cfg: Config{Enabled: true, MaxConcurrent: 16, TenantPatternsEnabled: false, KnownTenantsRefreshInterval: 0},
+		},
+		"empty local cluster name": {
+			cfg:         Config{Enabled: true, MaxConcurrent: 16, RemoteClusters: []querier.RemoteReadClusterConfig{remoteCluster("eu")}},
+			expectedErr: errEmptyLocalClusterName.Error(),
+		},
+		"invalid remote cluster": {
+			cfg:         Config{Enabled: true, MaxConcurrent: 16, LocalClusterName: "us", RemoteClusters: []querier.RemoteReadClusterConfig{{Name: "eu"}}},
+			expectedErr: `invalid remote read URL "" of the remote cluster eu`,
+		},
+		"remote cluster with the same name of the local cluster": {
+			cfg:         Config{Enabled: true, MaxConcurrent: 16, LocalClusterName: "eu", RemoteClusters: []querier.RemoteReadClusterConfig{remoteCluster("eu")}},
+			expectedErr: "the cluster name eu is used by more than one cluster",
+		},
+		"remote clusters with the same name": {
+			cfg:         Config{Enabled: true, MaxConcurrent: 16, LocalClusterName: "us", RemoteClusters: []querier.RemoteReadClusterConfig{remoteCluster("eu"), remoteCluster("eu")}},
+			expectedErr: "the cluster name eu is used by more than one cluster",
+		},
+		"remote clusters are not validated when tenant federation is disabled": {
+			cfg: Config{Enabled: false, MaxConcurrent: 16, RemoteClusters: []querier.RemoteReadClusterConfig{{Name: "eu"}}},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := testData.cfg.Validate()
+			if testData.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, testData.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tenantfederation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/util/globalerror"
+)
+
+const (
+	tenantPatternWildcard = "*"
+
+	// CallerTenantIDHeaderName is the HTTP header identifying the tenant making a federated query
+	// using tenant ID patterns. The tenant ID patterns are authorized and limited using the caller's tenant.
+	CallerTenantIDHeaderName = "X-Caller-OrgID"
+)
+
+var maxTenantsPerPatternMsgFormat = globalerror.TenantFederationMaxTenantsPerPattern.MessageWithPerTenantLimitConfig(
+	"the tenant ID pattern %q matches %d tenants, which exceeds the limit of %d tenants",
+	"tenant-federation.max-tenants-per-pattern",
+)
+
+// TenantsLister lists the tenants known to the cluster.
+type TenantsLister interface {
+	ListTenants(ctx context.Context) ([]string, error)
+}
+
+// TenantsListerFunc is an adapter to use a function as TenantsLister.
+type TenantsListerFunc func(ctx context.Context) ([]string, error)
+
+// ListTenants implements TenantsLister.
+func (f TenantsListerFunc) ListTenants(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// TenantPatternAuthorizer authorizes the tenant ID patterns used in federated queries.
+type TenantPatternAuthorizer interface {
+	// AuthorizeTenantPattern returns an error if the caller tenant is not allowed to use the tenant ID pattern.
+	AuthorizeTenantPattern(ctx context.Context, callerID, pattern string) error
+}
+
+// TenantPatternLimits is the interface of the limits used by the tenant pattern resolver.
+type TenantPatternLimits interface {
+	TenantFederationAllowedTenantPatterns(userID string) []string
+	TenantFederationMaxTenantsPerPattern(userID string) int
+}
+
+// limitsTenantPatternAuthorizer only allows the tenant ID patterns in the caller's limits.
+type limitsTenantPatternAuthorizer struct {
+	limits TenantPatternLimits
+}
+
+// NewLimitsTenantPatternAuthorizer returns a TenantPatternAuthorizer which only allows the tenant ID
+// patterns configured in the caller tenant's limits.
+func NewLimitsTenantPatternAuthorizer(limits TenantPatternLimits) TenantPatternAuthorizer {
+	return &limitsTenantPatternAuthorizer{limits: limits}
+}
+
+func (a *limitsTenantPatternAuthorizer) AuthorizeTenantPattern(_ context.Context, callerID, pattern string) error {
+	if !slices.Contains(a.limits.TenantFederationAllowedTenantPatterns(callerID), pattern) {
+		return fmt.Errorf("the tenant ID pattern %q is not allowed for the tenant %s", pattern, callerID)
+	}
+	return nil
+}
+
+// TenantPatternResolver resolves the tenant ID patterns in the tenant IDs of federated queries to the
+// matching known tenants. It's a service refreshing the known tenants in the background, so that
+// queries never wait for the tenants to be listed.
+type TenantPatternResolver struct {
+	services.Service
+
+	authorizer      TenantPatternAuthorizer
+	listers         []TenantsLister
+	limits          TenantPatternLimits
+	refreshInterval time.Duration
+	logger          log.Logger
+
+	// Listers which are services, started and stopped with the resolver.
+	subservices        *services.Manager
+	subservicesWatcher *services.FailureWatcher
+
+	knownTenantsMx     sync.RWMutex
+	knownTenants       []string
+	knownTenantsLoaded bool
+}
+
+// NewTenantPatternResolver returns a TenantPatternResolver resolving the tenant ID patterns to the
+// matching tenants returned by the listers, after they've been authorized by the authorizer. The
+// list of known tenants is refreshed every refreshInterval.
+func NewTenantPatternResolver(authorizer TenantPatternAuthorizer, listers []TenantsLister, limits TenantPatternLimits, refreshInterval time.Duration, logger log.Logger) (*TenantPatternResolver, error) {
+	r := &TenantPatternResolver{
+		authorizer:         authorizer,
+		listers:            listers,
+		limits:             limits,
+		refreshInterval:    refreshInterval,
+		logger:             logger,
+		subservicesWatcher: services.NewFailureWatcher(),
+	}
+
+	var servs []services.Service
+	for _, lister := range listers {
+		if s, ok := lister.(services.Service); ok {
+			servs = append(servs, s)
+		}
+	}
+
+	if len(servs) > 0 {
+		var err error
+		if r.subservices, err = services.NewManager(servs...); err != nil {
+			return nil, errors.Wrap(err, "register tenant pattern resolver subservices")
+		}
+	}
+
+	r.Service = services.NewBasicService(r.starting, r.running, r.stopping)
+	return r, nil
+}
+
+func (r *TenantPatternResolver) starting(ctx context.Context) error {
+	if r.subservices != nil {
+		r.subservicesWatcher.WatchManager(r.subservices)
+
+		if err := services.StartManagerAndAwaitHealthy(ctx, r.subservices); err != nil {
+			return errors.Wrap(err, "unable to start tenant pattern resolver subservices")
+		}
+	}
+
+	// Failing to list the tenants doesn't prevent the startup: only the queries
+	// using tenant ID patterns fail until the known tenants are loaded.
+	r.refreshKnownTenants(ctx)
+	return nil
+}
+
+func (r *TenantPatternResolver) running(ctx context.Context) error {
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.refreshKnownTenants(ctx)
+		case err := <-r.subservicesWatcher.Chan():
+			return errors.Wrap(err, "tenant pattern resolver subservice failed")
+		}
+	}
+}
+
+func (r *TenantPatternResolver) stopping(_ error) error {
+	if r.subservices != nil {
+		return services.StopManagerAndAwaitStopped(context.Background(), r.subservices)
+	}
+	return nil
+}
+
+// refreshKnownTenants lists the known tenants from all the listers. If any lister fails,
+// the previously known tenants are kept.
+func (r *TenantPatternResolver) refreshKnownTenants(ctx context.Context) {
+	var knownTenants []string
+	for _, lister := range r.listers {
+		tenants, err := lister.ListTenants(ctx)
+		if err != nil {
+			level.Warn(r.logger).Log("msg", "unable to refresh the known tenants used to resolve tenant ID patterns", "err", err)
+			return
+		}
+		knownTenants = append(knownTenants, tenants...)
+	}
+
+	r.knownTenantsMx.Lock()
+	r.knownTenants = tenant.NormalizeTenantIDs(knownTenants)
+	r.knownTenantsLoaded = true
+	r.knownTenantsMx.Unlock()
+}
+
+// getKnownTenants returns the known tenants, and whether they've been loaded at least once.
+func (r *TenantPatternResolver) getKnownTenants() ([]string, bool) {
+	r.knownTenantsMx.RLock()
+	defer r.knownTenantsMx.RUnlock()
+
+	return r.knownTenants, r.knownTenantsLoaded
+}
+
+// ResolveTenantIDs returns the tenant IDs with the tenant ID patterns replaced by the matching known
+// tenants. The patterns are authorized and limited using the caller tenant.
+func (r *TenantPatternResolver) ResolveTenantIDs(ctx context.Context, callerID string, tenantIDs []string) ([]string, error) {
+	if !slices.ContainsFunc(tenantIDs, isTenantPattern) {
+		return tenantIDs, nil
+	}
+
+	if callerID == "" {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "the tenant making a query with tenant ID patterns must be set in the %s header", CallerTenantIDHeaderName)
+	}
+	if err := tenant.ValidTenantID(callerID); err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "invalid %s header: %s", CallerTenantIDHeaderName, err.Error())
+	}
+
+	resolved := make([]string, 0, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		if !isTenantPattern(tenantID) {
+			resolved = append(resolved, tenantID)
+			continue
+		}
+
+		matched, err := r.expandTenantPattern(ctx, callerID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, matched...)
+	}
+
+	return tenant.NormalizeTenantIDs(resolved), nil
+}
+
+func (r *TenantPatternResolver) expandTenantPattern(ctx context.Context, callerID, pattern string) ([]string, error) {
+	if err := r.authorizer.AuthorizeTenantPattern(ctx, callerID, pattern); err != nil {
+		return nil, httpgrpc.Errorf(http.StatusForbidden, "%s", err.Error())
+	}
+
+	re, err := tenantPatternToRegexp(pattern)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+	}
+
+	knownTenants, loaded := r.getKnownTenants()
+	if !loaded {
+		return nil, httpgrpc.Errorf(http.StatusServiceUnavailable, "the known tenants used to resolve the tenant ID pattern %q haven't been loaded yet", pattern)
+	}
+
+	var matched []string
+	for _, tenantID := range knownTenants {
+		if re.MatchString(tenantID) {
+			matched = append(matched, tenantID)
+		}
+	}
+
+	if limit := r.limits.TenantFederationMaxTenantsPerPattern(callerID); limit > 0 && len(matched) > limit {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, maxTenantsPerPatternMsgFormat, pattern, len(matched), limit)
+	}
+
+	return matched, nil
+}
+
+// Wrap implements middleware.Interface. It replaces the tenant ID patterns in the request with the
+// matching tenants, both in the request context and in the X-Scope-OrgID header, so that the
+// following handlers and the downstream components only see the resolved tenant IDs.
+func (r *TenantPatternResolver) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tenantIDs, err := tenant.TenantIDs(req.Context())
+		if err != nil || !slices.ContainsFunc(tenantIDs, isTenantPattern) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		resolved, err := r.ResolveTenantIDs(req.Context(), req.Header.Get(CallerTenantIDHeaderName), tenantIDs)
+		if err == nil && len(resolved) == 0 {
+			err = httpgrpc.Errorf(http.StatusBadRequest, "the tenant ID patterns in the %s header don't match any tenant", user.OrgIDHeaderName)
+		}
+		if err != nil {
+			if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+				http.Error(w, string(resp.Body), int(resp.Code))
+			} else {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		orgID := tenant.JoinTenantIDs(resolved)
+		req = req.WithContext(user.InjectOrgID(req.Context(), orgID))
+		req.Header.Set(user.OrgIDHeaderName, orgID)
+		next.ServeHTTP(w, req)
+	})
+}
+
+func isTenantPattern(tenantID string) bool {
+	return strings.Contains(tenantID, tenantPatternWildcard)
+}
+
+// tenantPatternToRegexp returns an anchored regexp matching the tenant IDs matched by the pattern.
+func tenantPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, tenantPatternWildcard)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tenantfederation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+)
+
+type mockTenantPatternLimits struct {
+	allowedTenantPatterns map[string][]string
+	maxTenantsPerPattern  map[string]int
+}
+
+func (m mockTenantPatternLimits) TenantFederationAllowedTenantPatterns(userID string) []string {
+	return m.allowedTenantPatterns[userID]
+}
+
+func (m mockTenantPatternLimits) TenantFederationMaxTenantsPerPattern(userID string) int {
+	return m.maxTenantsPerPattern[userID]
+}
+
+func newTestTenantPatternResolver(t *testing.T, listers []TenantsLister, limits TenantPatternLimits) *TenantPatternResolver {
+	resolver, err := NewTenantPatternResolver(NewLimitsTenantPatternAuthorizer(limits), listers, limits, time.Hour, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), resolver))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), resolver))
+	})
+
+	return resolver
+}
+
+func TestTenantPatternResolver_ResolveTenantIDs(t *testing.T) {
+	knownTenants := TenantsListerFunc(func(context.Context) ([]string, error) {
+		return []string{"team-a-prod", "team-a-dev", "team-b-prod"}, nil
+	})
+	ingesterTenants := TenantsListerFunc(func(context.Context) ([]string, error) {
+		return []string{"team-a-staging", "team-a-prod"}, nil
+	})
+
+	limits := mockTenantPatternLimits{
+		allowedTenantPatterns: map[string][]string{
+			"team-a":   {"team-a-*"},
+			"team-c":   {"team-c-*"},
+			"ops":      {"*-prod", "team-a-*"},
+			"limited":  {"team-a-*"},
+			"at-limit": {"team-a-*"},
+		},
+		maxTenantsPerPattern: map[string]int{
+			"limited":  2,
+			"at-limit": 3,
+		},
+	}
+
+	resolver := newTestTenantPatternResolver(t, []TenantsLister{knownTenants, ingesterTenants}, limits)
+
+	tests := map[string]struct {
+		callerID           string
+		tenantIDs          []string
+		expectedTenantIDs  []string
+		expectedErr        string
+		expectedStatusCode int
+	}{
+		"literal tenant IDs are passed through without a caller": {
+			tenantIDs:         []string{"team-a-prod", "team-b-prod"},
+			expectedTenantIDs: []string{"team-a-prod", "team-b-prod"},
+		},
+		"pattern is expanded to the known tenants from all listers": {
+			callerID:          "team-a",
+			tenantIDs:         []string{"team-a-*"},
+			expectedTenantIDs: []string{"team-a-dev", "team-a-prod", "team-a-staging"},
+		},
+		"pattern matching in the middle of the tenant ID": {
+			callerID:          "ops",
+			tenantIDs:         []string{"*-prod"},
+			expectedTenantIDs: []string{"team-a-prod", "team-b-prod"},
+		},
+		"pattern is combined with literal tenant IDs and deduplicated": {
+			callerID:          "team-a",
+			tenantIDs:         []string{"team-a-*", "team-a-dev", "team-b-prod"},
+			expectedTenantIDs: []string{"team-a-dev", "team-a-prod", "team-a-staging", "team-b-prod"},
+		},
+		"pattern matching no tenants": {
+			callerID:          "team-c",
+			tenantIDs:         []string{"team-c-*"},
+			expectedTenantIDs: []string{},
+		},
+		"pattern without a caller": {
+			tenantIDs:          []string{"team-a-*"},
+			expectedErr:        "the tenant making a query with tenant ID patterns must be set in the X-Caller-OrgID header",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"pattern with an invalid caller": {
+			callerID:           "team/a",
+			tenantIDs:          []string{"team-a-*"},
+			expectedErr:        "invalid X-Caller-OrgID header",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"pattern not allowed for the caller": {
+			callerID:           "team-a",
+			tenantIDs:          []string{"*-prod"},
+			expectedErr:        `the tenant ID pattern "*-prod" is not allowed for the tenant team-a`,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		"pattern matching more tenants than the caller's limit": {
+			callerID:           "limited",
+			tenantIDs:          []string{"team-a-*"},
+			expectedErr:        `the tenant ID pattern "team-a-*" matches 3 tenants, which exceeds the limit of 2 tenants`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"pattern matching as many tenants as the caller's limit": {
+			callerID:          "at-limit",
+			tenantIDs:         []string{"team-a-*"},
+			expectedTenantIDs: []string{"team-a-dev", "team-a-prod", "team-a-staging"},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := resolver.ResolveTenantIDs(context.Background(), testData.callerID, testData.tenantIDs)
+			if testData.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testData.expectedErr)
+
+				resp, ok := httpgrpc.HTTPResponseFromError(err)
+				require.True(t, ok)
+				assert.Equal(t, testData.expectedStatusCode, int(resp.Code))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.ElementsMatch(t, testData.expectedTenantIDs, actual)
+		})
+	}
+}
+
+func TestTenantPatternResolver_KnownTenantsRefresh(t *testing.T) {
+	var (
+		tenants   = []string{"team-a-prod"}
+		listerErr error
+		lister    = TenantsListerFunc(func(context.Context) ([]string, error) {
+			return tenants, listerErr
+		})
+		limits = mockTenantPatternLimits{allowedTenantPatterns: map[string][]string{"team-a": {"team-a-*"}}}
+		ctx    = context.Background()
+	)
+
+	resolver := newTestTenantPatternResolver(t, []TenantsLister{lister}, limits)
+
+	actual, err := resolver.ResolveTenantIDs(ctx, "team-a", []string{"team-a-*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a-prod"}, actual)
+
+	// The known tenants are only refreshed in the background.
+	tenants = []string{"team-a-prod", "team-a-dev"}
+	actual, err = resolver.ResolveTenantIDs(ctx, "team-a", []string{"team-a-*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a-prod"}, actual)
+
+	resolver.refreshKnownTenants(ctx)
+	actual, err = resolver.ResolveTenantIDs(ctx, "team-a", []string{"team-a-*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a-dev", "team-a-prod"}, actual)
+
+	// The previously known tenants are kept if the refresh fails.
+	listerErr = errors.New("bucket unavailable")
+	resolver.refreshKnownTenants(ctx)
+	actual, err = resolver.ResolveTenantIDs(ctx, "team-a", []string{"team-a-*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a-dev", "team-a-prod"}, actual)
+}
+
+func TestTenantPatternResolver_KnownTenantsListingFailure(t *testing.T) {
+	limits := mockTenantPatternLimits{allowedTenantPatterns: map[string][]string{"team-a": {"team-a-*"}}}
+	resolver := newTestTenantPatternResolver(t, []TenantsLister{TenantsListerFunc(func(context.Context) ([]string, error) {
+		return nil, errors.New("bucket unavailable")
+	})}, limits)
+
+	// The resolver starts even if the known tenants can't be listed.
+	_, err := resolver.ResolveTenantIDs(context.Background(), "team-a", []string{"team-a-*"})
+	require.ErrorContains(t, err, `the known tenants used to resolve the tenant ID pattern "team-a-*" haven't been loaded yet`)
+
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, int(resp.Code))
+
+	// Tenant IDs without patterns don't require the known tenants.
+	actual, err := resolver.ResolveTenantIDs(context.Background(), "team-a", []string{"team-a-prod", "team-b-prod"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a-prod", "team-b-prod"}, actual)
+}
+
+type serviceTenantsLister struct {
+	services.Service
+	TenantsListerFunc
+}
+
+func TestTenantPatternResolver_ShouldRunTheListersWhichAreServices(t *testing.T) {
+	running := atomic.NewBool(false)
+	lister := &serviceTenantsLister{
+		Service: services.NewIdleService(func(context.Context) error {
+			running.Store(true)
+			return nil
+		}, func(error) error {
+			running.Store(false)
+			return nil
+		}),
+		TenantsListerFunc: func(context.Context) ([]string, error) {
+			if !running.Load() {
+				return nil, errors.New("lister not running")
+			}
+			return []string{"team-a-prod"}, nil
+		},
+	}
+
+	limits := mockTenantPatternLimits{allowedTenantPatterns: map[string][]string{"team-a": {"team-a-*"}}}
+	resolver, err := NewTenantPatternResolver(NewLimitsTenantPatternAuthorizer(limits), []TenantsLister{lister}, limits, time.Hour, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), resolver))
+
+	actual, err := resolver.ResolveTenantIDs(context.Background(), "team-a", []string{"team-a-*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a-prod"}, actual)
+
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), resolver))
+	assert.False(t, running.Load())
+}
+
+func TestTenantPatternResolver_Wrap(t *testing.T) {
+	tenant.WithDefaultResolver(tenant.NewMultiResolver())
+	t.Cleanup(func() {
+		tenant.WithDefaultResolver(tenant.NewSingleResolver())
+	})
+
+	lister := TenantsListerFunc(func(context.Context) ([]string, error) {
+		return []string{"team-a-prod", "team-a-dev", "team-b-prod"}, nil
+	})
+	limits := mockTenantPatternLimits{allowedTenantPatterns: map[string][]string{"team-a": {"team-a-*"}, "team-c": {"team-c-*"}}}
+	resolver := newTestTenantPatternResolver(t, []TenantsLister{lister}, limits)
+
+	tests := map[string]struct {
+		orgID              string
+		callerID           string
+		expectedOrgID      string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		"tenant IDs without patterns are passed through": {
+			orgID:              "team-a-prod|team-b-prod",
+			expectedOrgID:      "team-a-prod|team-b-prod",
+			expectedStatusCode: http.StatusOK,
+		},
+		"patterns are replaced by the matching tenants": {
+			orgID:              "team-a-*|team-b-prod",
+			callerID:           "team-a",
+			expectedOrgID:      "team-a-dev|team-a-prod|team-b-prod",
+			expectedStatusCode: http.StatusOK,
+		},
+		"patterns not allowed for the caller": {
+			orgID:              "team-b-*",
+			callerID:           "team-a",
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       `the tenant ID pattern "team-b-*" is not allowed for the tenant team-a`,
+		},
+		"patterns matching no tenants": {
+			orgID:              "team-c-*",
+			callerID:           "team-c",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "the tenant ID patterns in the X-Scope-OrgID header don't match any tenant",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			var actualCtxOrgID, actualHeaderOrgID string
+			handler := resolver.Wrap(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				actualCtxOrgID, _ = user.ExtractOrgID(req.Context())
+				actualHeaderOrgID = req.Header.Get(user.OrgIDHeaderName)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/query", nil)
+			req.Header.Set(user.OrgIDHeaderName, testData.orgID)
+			if testData.callerID != "" {
+				req.Header.Set(CallerTenantIDHeaderName, testData.callerID)
+			}
+			req = req.WithContext(user.InjectOrgID(req.Context(), testData.orgID))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, testData.expectedStatusCode, rec.Code)
+			if testData.expectedStatusCode != http.StatusOK {
+				assert.Contains(t, rec.Body.String(), testData.expectedBody)
+				return
+			}
+
+			assert.Equal(t, testData.expectedOrgID, actualCtxOrgID)
+			assert.Equal(t, testData.expectedOrgID, actualHeaderOrgID)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/rules"
 	"github.com/prometheus/prometheus/storage"
+	v1 "github.com/prometheus/prometheus/web/api/v1"
 	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/user"
 
@@ -147,6 +148,33 @@ type RulesLimits interface {
 	RulerSyncRulesOnChangesEnabled(userID string) bool
 }
 
+// EngineQueryFunc returns a new query function that executes instant queries against the given engine,
+// like rules.EngineQueryFunc does, but accepting any engine implementing v1.QueryEngine.
+func EngineQueryFunc(engine v1.QueryEngine, q storage.Queryable) rules.QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		q, err := engine.NewInstantQuery(ctx, q, nil, qs, t)
+		if err != nil {
+			return nil, err
+		}
+		res := q.Exec(ctx)
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		switch v := res.Value.(type) {
+		case promql.Vector:
+			return v, nil
+		case promql.Scalar:
+			return promql.Vector{promql.Sample{
+				T:      v.T,
+				F:      v.V,
+				Metric: labels.Labels{},
+			}}, nil
+		default:
+			return nil, errors.New("rule result is not a vector or scalar")
+		}
+	}
+}
+
 func MetricsQueryFunc(qf rules.QueryFunc, queries, failedQueries prometheus.Counter) rules.QueryFunc {
 	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
 		queries.Inc()
@@ -205,6 +233,7 @@ func RecordAndReportRuleQueryMetrics(qf rules.QueryFunc, queryTime prometheus.Co
 			numBytes := stats.LoadFetchedChunkBytes()
 			numChunks := stats.LoadFetchedChunks()
 			shardedQueries := stats.LoadShardedQueries()
+			peakMemory := stats.LoadEstimatedPeakMemoryConsumption()
 
 			queryTime.Add(wallTime.Seconds())
 
@@ -217,6 +246,7 @@ func RecordAndReportRuleQueryMetrics(qf rules.QueryFunc, queryTime prometheus.Co
 				"fetched_chunk_bytes", numBytes,
 				"fetched_chunks_count", numChunks,
 				"sharded_queries", shardedQueries,
+				"estimated_peak_memory_consumption_bytes", peakMemory,
 				"query", qs,
 			}
 			level.Info(util_log.WithContext(ctx, logger)).Log(logMessage...)
@@ -30,6 +30,7 @@ import (
 	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 	"github.com/grafana/mimir/pkg/util/version"
 )
@@ -160,13 +161,14 @@ func (q *RemoteQuerier) Read(ctx context.Context, query *prompb.Query) (*prompb.
 
 	req := httpgrpc.HTTPRequest{
 		Method: http.MethodPost,
-		Url:    q.promHTTPPrefix + readEndpointPath,
+		Url:    q.promHTTPPrefix + readEndpointPath + "?" + url.Values{querierapi.PartialResponseParam: []string{"false"}}.Encode(),
 		Body:   snappy.Encode(nil, data),
 		Headers: []*httpgrpc.Header{
 			{Key: textproto.CanonicalMIMEHeaderKey("Content-Encoding"), Values: []string{"snappy"}},
 			{Key: textproto.CanonicalMIMEHeaderKey("Accept-Encoding"), Values: []string{"snappy"}},
 			{Key: textproto.CanonicalMIMEHeaderKey("Content-Type"), Values: []string{"application/x-protobuf"}},
 			{Key: textproto.CanonicalMIMEHeaderKey("User-Agent"), Values: []string{userAgent}},
+			{Key: textproto.CanonicalMIMEHeaderKey(querierapi.QueryPriorityHeader), Values: []string{querierapi.QueryPriorityRule.String()}},
 			{Key: textproto.CanonicalMIMEHeaderKey("X-Prometheus-Remote-Read-Version"), Values: []string{"0.1.0"}},
 		},
 	}
@@ -249,6 +251,9 @@ func (q *RemoteQuerier) createRequest(ctx context.Context, query string, ts time
 	if !ts.IsZero() {
 		args.Set("time", ts.Format(time.RFC3339Nano))
 	}
+	// Rules must never be evaluated on partial data, regardless of the tenant's configuration,
+	// otherwise alerts could be wrongly resolved and recording rules could store wrong results.
+	args.Set(querierapi.PartialResponseParam, "false")
 	body := []byte(args.Encode())
 	acceptHeader := ""
 
@@ -267,6 +272,7 @@ func (q *RemoteQuerier) createRequest(ctx context.Context, query string, ts time
 		Body:   body,
 		Headers: []*httpgrpc.Header{
 			{Key: textproto.CanonicalMIMEHeaderKey("User-Agent"), Values: []string{userAgent}},
+			{Key: textproto.CanonicalMIMEHeaderKey(querierapi.QueryPriorityHeader), Values: []string{querierapi.QueryPriorityRule.String()}},
 			{Key: textproto.CanonicalMIMEHeaderKey("Content-Type"), Values: []string{mimeTypeFormPost}},
 			{Key: textproto.CanonicalMIMEHeaderKey("Content-Length"), Values: []string{strconv.Itoa(len(body))}},
 			{Key: textproto.CanonicalMIMEHeaderKey("Accept"), Values: []string{acceptHeader}},
@@ -25,6 +25,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 )
 
 type mockHTTPGRPCClient func(ctx context.Context, req *httpgrpc.HTTPRequest, _ ...grpc.CallOption) (*httpgrpc.HTTPResponse, error)
@@ -61,7 +62,8 @@ func TestRemoteQuerier_ReadReq(t *testing.T) {
 	require.NotNil(t, inReq)
 	require.Equal(t, http.MethodPost, inReq.Method)
 	require.Equal(t, body, inReq.Body)
-	require.Equal(t, "/prometheus/api/v1/read", inReq.Url)
+	require.Equal(t, "/prometheus/api/v1/read?partial_response=false", inReq.Url)
+	require.Equal(t, "rule", getHeader(inReq.Headers, querierapi.QueryPriorityHeader))
 }
 
 func TestRemoteQuerier_ReadReqTimeout(t *testing.T) {
@@ -99,8 +101,9 @@ func TestRemoteQuerier_QueryReq(t *testing.T) {
 
 			require.NotNil(t, inReq)
 			require.Equal(t, http.MethodPost, inReq.Method)
-			require.Equal(t, "query=qs&time="+url.QueryEscape(tm.Format(time.RFC3339Nano)), string(inReq.Body))
+			require.Equal(t, "partial_response=false&query=qs&time="+url.QueryEscape(tm.Format(time.RFC3339Nano)), string(inReq.Body))
 			require.Equal(t, "/prometheus/api/v1/query", inReq.Url)
+			require.Equal(t, "rule", getHeader(inReq.Headers, querierapi.QueryPriorityHeader))
 
 			acceptHeader := getHeader(inReq.Headers, "Accept")
 
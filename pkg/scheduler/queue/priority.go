@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package queue
+
+import (
+	"flag"
+	"time"
+
+	"github.com/pkg/errors"
+
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+)
+
+var (
+	errInvalidPriorityWeight      = errors.New("the weight of each query priority class must be greater than 0")
+	errInvalidStarvationTimeout   = errors.New("the query priority starvation timeout must not be negative")
+	disabledPrioritizationWeights = []int{1}
+)
+
+// PrioritizationConfig configures how the requests of a tenant are dequeued based on their priority class.
+type PrioritizationConfig struct {
+	Enabled           bool          `yaml:"enabled" category:"experimental"`
+	RuleWeight        int           `yaml:"rule_weight" category:"experimental"`
+	DashboardWeight   int           `yaml:"dashboard_weight" category:"experimental"`
+	AdHocWeight       int           `yaml:"adhoc_weight" category:"experimental"`
+	StarvationTimeout time.Duration `yaml:"starvation_timeout" category:"experimental"`
+}
+
+func (cfg *PrioritizationConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+".enabled", false, "True to dequeue the requests of each tenant based on their priority class, instead of in FIFO order. The priority class of a query is set with the "+querierapi.QueryPriorityHeader+" HTTP header to rule, dashboard or adhoc. If not set, queries run by the ruler are in the rule class, queries run by Grafana dashboards are in the dashboard class, and any other query is in the adhoc class.")
+	f.IntVar(&cfg.RuleWeight, prefix+".rule-weight", 4, "Weight of the rule priority class. Each priority class is dequeued proportionally to its weight, among the classes with requests in the queue.")
+	f.IntVar(&cfg.DashboardWeight, prefix+".dashboard-weight", 2, "Weight of the dashboard priority class.")
+	f.IntVar(&cfg.AdHocWeight, prefix+".adhoc-weight", 1, "Weight of the adhoc priority class.")
+	f.DurationVar(&cfg.StarvationTimeout, prefix+".starvation-timeout", 30*time.Second, "Requests waiting in the queue for longer than this timeout are dequeued before any other request of the tenant, regardless of their priority class. 0 to disable.")
+}
+
+func (cfg *PrioritizationConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.RuleWeight <= 0 || cfg.DashboardWeight <= 0 || cfg.AdHocWeight <= 0 {
+		return errInvalidPriorityWeight
+	}
+	if cfg.StarvationTimeout < 0 {
+		return errInvalidStarvationTimeout
+	}
+	return nil
+}
+
+// weights returns the weights indexed by priority class. If prioritization is disabled, all requests
+// belong to a single class.
+func (cfg PrioritizationConfig) weights() []int {
+	if !cfg.Enabled {
+		return disabledPrioritizationWeights
+	}
+
+	weights := make([]int, len(querierapi.QueryPriorities))
+	for _, p := range querierapi.QueryPriorities {
+		switch p {
+		case querierapi.QueryPriorityRule:
+			weights[p] = cfg.RuleWeight
+		case querierapi.QueryPriorityDashboard:
+			weights[p] = cfg.DashboardWeight
+		default:
+			weights[p] = cfg.AdHocWeight
+		}
+	}
+	return weights
+}
+
+// queuedRequest is a request waiting in a user queue.
+type queuedRequest struct {
+	req        Request
+	enqueuedAt time.Time
+}
+
+// enqueue adds the request to the tail of the input priority class.
+func (q *userQueue) enqueue(req Request, class int, now time.Time) {
+	q.requests[class] = append(q.requests[class], queuedRequest{req: req, enqueuedAt: now})
+	q.length++
+}
+
+// dequeue removes and returns the request at the head of the next priority class to serve. The user queue
+// must not be empty.
+func (q *userQueue) dequeue(weights []int, starvationTimeout time.Duration, now time.Time) Request {
+	class := q.nextClass(weights, starvationTimeout, now)
+
+	req := q.requests[class][0].req
+	q.requests[class][0] = queuedRequest{}
+	q.requests[class] = q.requests[class][1:]
+	q.length--
+
+	return req
+}
+
+// nextClass picks the priority class to dequeue from. The classes with requests are picked with a smooth
+// weighted round-robin, so that each class is served proportionally to its weight, unless the oldest
+// request in the queue has been waiting for longer than the starvation timeout.
+func (q *userQueue) nextClass(weights []int, starvationTimeout time.Duration, now time.Time) int {
+	if starvationTimeout > 0 {
+		oldest := -1
+		for class, reqs := range q.requests {
+			if len(reqs) > 0 && (oldest < 0 || reqs[0].enqueuedAt.Before(q.requests[oldest][0].enqueuedAt)) {
+				oldest = class
+			}
+		}
+		if oldest >= 0 && now.Sub(q.requests[oldest][0].enqueuedAt) >= starvationTimeout {
+			return oldest
+		}
+	}
+
+	selected, total := -1, 0
+	for class, reqs := range q.requests {
+		if len(reqs) == 0 {
+			// Don't let an empty class accumulate credit to spend once it gets new requests.
+			q.currentWeights[class] = 0
+			continue
+		}
+
+		q.currentWeights[class] += weights[class]
+		total += weights[class]
+		// On ties, prefer the class with the higher priority.
+		if selected < 0 || q.currentWeights[class] >= q.currentWeights[selected] {
+			selected = class
+		}
+	}
+	q.currentWeights[selected] -= total
+
+	return selected
+}
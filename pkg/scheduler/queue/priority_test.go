@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+)
+
+func TestPrioritizationConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg         PrioritizationConfig
+		expectedErr error
+	}{
+		"valid": {
+			cfg: PrioritizationConfig{Enabled: true, RuleWeight: 4, DashboardWeight: 2, AdHocWeight: 1, StarvationTimeout: time.Minute},
+		},
+		"invalid weight": {
+			cfg:         PrioritizationConfig{Enabled: true, RuleWeight: 4, DashboardWeight: 2, AdHocWeight: 0},
+			expectedErr: errInvalidPriorityWeight,
+		},
+		"invalid weight when prioritization is disabled": {
+			cfg: PrioritizationConfig{Enabled: false, RuleWeight: 4, DashboardWeight: 2, AdHocWeight: 0},
+		},
+		"negative starvation timeout": {
+			cfg:         PrioritizationConfig{Enabled: true, RuleWeight: 4, DashboardWeight: 2, AdHocWeight: 1, StarvationTimeout: -time.Second},
+			expectedErr: errInvalidStarvationTimeout,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testData.expectedErr, testData.cfg.Validate())
+		})
+	}
+}
+
+func TestUserQueue_Dequeue(t *testing.T) {
+	prioritization := PrioritizationConfig{Enabled: true, RuleWeight: 4, DashboardWeight: 2, AdHocWeight: 1}
+	now := time.Now()
+
+	t.Run("should dequeue each priority class proportionally to its weight", func(t *testing.T) {
+		uq := newUserQueues(100, 0, prioritization)
+		q := uq.getOrAddQueue("user-1", 0)
+
+		for i := 0; i < 35; i++ {
+			q.enqueue(querierapi.QueryPriorityRule, uq.priorityClass(querierapi.QueryPriorityRule), now)
+			q.enqueue(querierapi.QueryPriorityDashboard, uq.priorityClass(querierapi.QueryPriorityDashboard), now)
+			q.enqueue(querierapi.QueryPriorityAdHoc, uq.priorityClass(querierapi.QueryPriorityAdHoc), now)
+		}
+
+		// Dequeue as many requests as the sum of the weights, multiple times.
+		dequeued := map[querierapi.QueryPriority]int{}
+		for i := 0; i < 35; i++ {
+			dequeued[q.dequeue(uq.priorityWeights, uq.starvationTimeout, now).(querierapi.QueryPriority)]++
+		}
+
+		assert.Equal(t, map[querierapi.QueryPriority]int{
+			querierapi.QueryPriorityRule:      20,
+			querierapi.QueryPriorityDashboard: 10,
+			querierapi.QueryPriorityAdHoc:     5,
+		}, dequeued)
+	})
+
+	t.Run("should dequeue the requests of a class in FIFO order", func(t *testing.T) {
+		uq := newUserQueues(100, 0, prioritization)
+		q := uq.getOrAddQueue("user-1", 0)
+
+		for i := 0; i < 3; i++ {
+			q.enqueue(i, uq.priorityClass(querierapi.QueryPriorityDashboard), now)
+		}
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, i, q.dequeue(uq.priorityWeights, uq.starvationTimeout, now))
+		}
+		assert.Equal(t, 0, q.length)
+	})
+
+	t.Run("should dequeue the oldest request once it exceeds the starvation timeout", func(t *testing.T) {
+		prioritization := prioritization
+		prioritization.StarvationTimeout = time.Minute
+
+		uq := newUserQueues(100, 0, prioritization)
+		q := uq.getOrAddQueue("user-1", 0)
+
+		q.enqueue("adhoc", uq.priorityClass(querierapi.QueryPriorityAdHoc), now)
+		for i := 0; i < 10; i++ {
+			q.enqueue("rule", uq.priorityClass(querierapi.QueryPriorityRule), now.Add(time.Second))
+		}
+
+		// The ad-hoc request is not dequeued until it has waited for longer than the starvation timeout.
+		assert.Equal(t, "rule", q.dequeue(uq.priorityWeights, uq.starvationTimeout, now.Add(30*time.Second)))
+		assert.Equal(t, "rule", q.dequeue(uq.priorityWeights, uq.starvationTimeout, now.Add(30*time.Second)))
+		assert.Equal(t, "adhoc", q.dequeue(uq.priorityWeights, uq.starvationTimeout, now.Add(time.Minute)))
+		assert.Equal(t, "rule", q.dequeue(uq.priorityWeights, uq.starvationTimeout, now.Add(time.Minute)))
+	})
+
+	t.Run("should dequeue in FIFO order when prioritization is disabled", func(t *testing.T) {
+		uq := newUserQueues(100, 0, PrioritizationConfig{Enabled: false})
+		q := uq.getOrAddQueue("user-1", 0)
+		require.Len(t, q.requests, 1)
+
+		q.enqueue("adhoc", uq.priorityClass(querierapi.QueryPriorityAdHoc), now)
+		q.enqueue("rule", uq.priorityClass(querierapi.QueryPriorityRule), now)
+		q.enqueue("dashboard", uq.priorityClass(querierapi.QueryPriorityDashboard), now)
+
+		for _, expected := range []string{"adhoc", "rule", "dashboard"} {
+			assert.Equal(t, expected, q.dequeue(uq.priorityWeights, uq.starvationTimeout, now))
+		}
+	})
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 )
 
 func BenchmarkGetNextRequest(b *testing.B) {
@@ -28,7 +30,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 	queues := make([]*RequestQueue, 0, b.N)
 
 	for n := 0; n < b.N; n++ {
-		queue := NewRequestQueue(maxOutstandingPerTenant, 0,
+		queue := NewRequestQueue(maxOutstandingPerTenant, 0, PrioritizationConfig{},
 			promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
 			promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
 		)
@@ -42,7 +44,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 			for j := 0; j < numTenants; j++ {
 				userID := strconv.Itoa(j)
 
-				err := queue.EnqueueRequest(userID, "request", 0, nil)
+				err := queue.EnqueueRequest(userID, "request", querierapi.QueryPriorityAdHoc, 0, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -85,7 +87,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	requests := make([]string, 0, numTenants)
 
 	for n := 0; n < b.N; n++ {
-		q := NewRequestQueue(maxOutstandingPerTenant, 0,
+		q := NewRequestQueue(maxOutstandingPerTenant, 0, PrioritizationConfig{},
 			promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
 			promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}),
 		)
@@ -106,7 +108,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		for i := 0; i < maxOutstandingPerTenant; i++ {
 			for j := 0; j < numTenants; j++ {
-				err := queues[n].EnqueueRequest(users[j], requests[j], 0, nil)
+				err := queues[n].EnqueueRequest(users[j], requests[j], querierapi.QueryPriorityAdHoc, 0, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -118,7 +120,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBecauseQuerierHasBeenForgotten(t *testing.T) {
 	const forgetDelay = 3 * time.Second
 
-	queue := NewRequestQueue(1, forgetDelay,
+	queue := NewRequestQueue(1, forgetDelay, PrioritizationConfig{},
 		promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
 		promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
 
@@ -147,7 +149,7 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	// Enqueue a request from an user which would be assigned to querier-1.
 	// NOTE: "user-1" hash falls in the querier-1 shard.
-	require.NoError(t, queue.EnqueueRequest("user-1", "request", 1, nil))
+	require.NoError(t, queue.EnqueueRequest("user-1", "request", querierapi.QueryPriorityAdHoc, 1, nil))
 
 	startTime := time.Now()
 	querier2wg.Wait()
@@ -157,6 +159,66 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 	assert.GreaterOrEqual(t, waitTime.Milliseconds(), forgetDelay.Milliseconds())
 }
 
+func TestRequestQueue_GetNextRequestForQuerier_ShouldDequeueRequestsByPriority(t *testing.T) {
+	tests := map[string]struct {
+		prioritization PrioritizationConfig
+		expected       []string
+	}{
+		"prioritization disabled": {
+			prioritization: PrioritizationConfig{Enabled: false},
+			expected:       []string{"adhoc-1", "adhoc-2", "dashboard-1", "rule-1", "rule-2", "dashboard-2"},
+		},
+		"prioritization enabled": {
+			prioritization: PrioritizationConfig{Enabled: true, RuleWeight: 4, DashboardWeight: 2, AdHocWeight: 1},
+			expected:       []string{"rule-1", "dashboard-1", "rule-2", "adhoc-1", "dashboard-2", "adhoc-2"},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			queue := NewRequestQueue(10, 0, testData.prioritization,
+				promauto.With(nil).NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+				promauto.With(nil).NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
+
+			ctx := context.Background()
+			require.NoError(t, services.StartAndAwaitRunning(ctx, queue))
+			t.Cleanup(func() {
+				require.NoError(t, services.StopAndAwaitTerminated(ctx, queue))
+			})
+
+			queue.RegisterQuerierConnection("querier-1")
+			t.Cleanup(func() {
+				queue.UnregisterQuerierConnection("querier-1")
+			})
+
+			for _, req := range []struct {
+				name     string
+				priority querierapi.QueryPriority
+			}{
+				{"adhoc-1", querierapi.QueryPriorityAdHoc},
+				{"adhoc-2", querierapi.QueryPriorityAdHoc},
+				{"dashboard-1", querierapi.QueryPriorityDashboard},
+				{"rule-1", querierapi.QueryPriorityRule},
+				{"rule-2", querierapi.QueryPriorityRule},
+				{"dashboard-2", querierapi.QueryPriorityDashboard},
+			} {
+				require.NoError(t, queue.EnqueueRequest("user-1", req.name, req.priority, 0, nil))
+			}
+
+			var actual []string
+			last := FirstUser()
+			for range testData.expected {
+				req, idx, err := queue.GetNextRequestForQuerier(ctx, last, "querier-1")
+				require.NoError(t, err)
+				actual = append(actual, req.(string))
+				last = idx
+			}
+
+			assert.Equal(t, testData.expected, actual)
+		})
+	}
+}
+
 func TestContextCond(t *testing.T) {
 	t.Run("wait until broadcast", func(t *testing.T) {
 		t.Parallel()
@@ -12,6 +12,7 @@ import (
 
 	"golang.org/x/exp/slices"
 
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/util"
 )
 
@@ -39,6 +40,11 @@ type queues struct {
 
 	maxUserQueueSize int
 
+	// Weights of the priority classes, indexed by class, and timeout after which a request is dequeued
+	// regardless of its priority class.
+	priorityWeights   []int
+	starvationTimeout time.Duration
+
 	// How long to wait before removing a querier which has got disconnected
 	// but hasn't notified about a graceful shutdown.
 	forgetDelay time.Duration
@@ -51,7 +57,12 @@ type queues struct {
 }
 
 type userQueue struct {
-	ch chan Request
+	// Requests waiting in the queue, by priority class. Requests of the same class are dequeued in FIFO order.
+	requests [][]queuedRequest
+	length   int
+
+	// Current weights of the smooth weighted round-robin used to pick the priority class to dequeue from.
+	currentWeights []int
 
 	// If not nil, only these queriers can handle user requests. If nil, all queriers can.
 	// We set this to nil if number of available queriers <= maxQueriers.
@@ -66,14 +77,16 @@ type userQueue struct {
 	index int
 }
 
-func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration) *queues {
+func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration, prioritization PrioritizationConfig) *queues {
 	return &queues{
-		userQueues:       map[string]*userQueue{},
-		users:            nil,
-		maxUserQueueSize: maxUserQueueSize,
-		forgetDelay:      forgetDelay,
-		queriers:         map[string]*querier{},
-		sortedQueriers:   nil,
+		userQueues:        map[string]*userQueue{},
+		users:             nil,
+		maxUserQueueSize:  maxUserQueueSize,
+		priorityWeights:   prioritization.weights(),
+		starvationTimeout: prioritization.StarvationTimeout,
+		forgetDelay:       forgetDelay,
+		queriers:          map[string]*querier{},
+		sortedQueriers:    nil,
 	}
 }
 
@@ -100,7 +113,7 @@ func (q *queues) deleteQueue(userID string) {
 // MaxQueriers is used to compute which queriers should handle requests for this user.
 // If maxQueriers is <= 0, all queriers can handle this user's requests.
 // If maxQueriers has changed since the last call, queriers for this are recomputed.
-func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
+func (q *queues) getOrAddQueue(userID string, maxQueriers int) *userQueue {
 	// Empty user is not allowed, as that would break our users list ("" is used for free spot).
 	if userID == "" {
 		return nil
@@ -114,9 +127,10 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 
 	if uq == nil {
 		uq = &userQueue{
-			ch:    make(chan Request, q.maxUserQueueSize),
-			seed:  util.ShuffleShardSeed(userID, ""),
-			index: -1,
+			requests:       make([][]queuedRequest, len(q.priorityWeights)),
+			currentWeights: make([]int, len(q.priorityWeights)),
+			seed:           util.ShuffleShardSeed(userID, ""),
+			index:          -1,
 		}
 		q.userQueues[userID] = uq
 
@@ -141,13 +155,22 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 		uq.queriers = shuffleQueriersForUser(uq.seed, maxQueriers, q.sortedQueriers, nil)
 	}
 
-	return uq.ch
+	return uq
+}
+
+// priorityClass returns the class of the user queues where requests with the input priority are enqueued.
+func (q *queues) priorityClass(priority querierapi.QueryPriority) int {
+	if len(q.priorityWeights) == 1 {
+		// Prioritization is disabled.
+		return 0
+	}
+	return int(priority)
 }
 
 // Finds next queue for the querier. To support fair scheduling between users, client is expected
 // to pass last user index returned by this function as argument. Is there was no previous
 // last user index, use -1.
-func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (chan Request, string, int) {
+func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (*userQueue, string, int) {
 	uid := lastUserIndex
 
 	// Ensure the querier is not shutting down. If the querier is shutting down, we shouldn't forward
@@ -179,7 +202,7 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 			}
 		}
 
-		return q.ch, u, uid
+		return q, u, uid
 	}
 	return nil, "", uid
 }
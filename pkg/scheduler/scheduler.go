@@ -30,6 +30,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/grafana/mimir/pkg/frontend/v2/frontendv2pb"
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
 	"github.com/grafana/mimir/pkg/scheduler/queue"
 	"github.com/grafana/mimir/pkg/scheduler/schedulerdiscovery"
 	"github.com/grafana/mimir/pkg/scheduler/schedulerpb"
@@ -71,6 +72,8 @@ type Scheduler struct {
 	connectedQuerierClients  prometheus.GaugeFunc
 	connectedFrontendClients prometheus.GaugeFunc
 	queueDuration            prometheus.Histogram
+	priorityQueueLength      *prometheus.GaugeVec
+	priorityQueueDuration    *prometheus.HistogramVec
 	inflightRequests         prometheus.Summary
 }
 
@@ -89,20 +92,25 @@ type connectedFrontend struct {
 }
 
 type Config struct {
-	MaxOutstandingPerTenant int                       `yaml:"max_outstanding_requests_per_tenant"`
-	QuerierForgetDelay      time.Duration             `yaml:"querier_forget_delay" category:"experimental"`
-	GRPCClientConfig        grpcclient.Config         `yaml:"grpc_client_config" doc:"description=This configures the gRPC client used to report errors back to the query-frontend."`
-	ServiceDiscovery        schedulerdiscovery.Config `yaml:",inline"`
+	MaxOutstandingPerTenant int                        `yaml:"max_outstanding_requests_per_tenant"`
+	QuerierForgetDelay      time.Duration              `yaml:"querier_forget_delay" category:"experimental"`
+	Prioritization          queue.PrioritizationConfig `yaml:"prioritization"`
+	GRPCClientConfig        grpcclient.Config          `yaml:"grpc_client_config" doc:"description=This configures the gRPC client used to report errors back to the query-frontend."`
+	ServiceDiscovery        schedulerdiscovery.Config  `yaml:",inline"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.IntVar(&cfg.MaxOutstandingPerTenant, "query-scheduler.max-outstanding-requests-per-tenant", 100, "Maximum number of outstanding requests per tenant per query-scheduler. In-flight requests above this limit will fail with HTTP response status code 429.")
 	f.DurationVar(&cfg.QuerierForgetDelay, "query-scheduler.querier-forget-delay", 0, "If a querier disconnects without sending notification about graceful shutdown, the query-scheduler will keep the querier in the tenant's shard until the forget delay has passed. This feature is useful to reduce the blast radius when shuffle-sharding is enabled.")
+	cfg.Prioritization.RegisterFlagsWithPrefix("query-scheduler.prioritization", f)
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-scheduler.grpc-client-config", f)
 	cfg.ServiceDiscovery.RegisterFlags(f, logger)
 }
 
 func (cfg *Config) Validate() error {
+	if err := cfg.Prioritization.Validate(); err != nil {
+		return err
+	}
 	return cfg.ServiceDiscovery.Validate()
 }
 
@@ -133,13 +141,26 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 		Name: "cortex_query_scheduler_discarded_requests_total",
 		Help: "Total number of query requests discarded.",
 	}, []string{"user"})
-	s.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, s.queueLength, s.discardedRequests)
+	s.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, cfg.Prioritization, s.queueLength, s.discardedRequests)
 
 	s.queueDuration = promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
 		Name:    "cortex_query_scheduler_queue_duration_seconds",
 		Help:    "Time spend by requests in queue before getting picked up by a querier.",
 		Buckets: prometheus.DefBuckets,
 	})
+	s.priorityQueueLength = promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cortex_query_scheduler_priority_queue_length",
+		Help: "Number of queries in the queue, by priority class.",
+	}, []string{"priority"})
+	s.priorityQueueDuration = promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cortex_query_scheduler_priority_queue_duration_seconds",
+		Help:    "Time spend by requests in queue before getting picked up by a querier, by priority class.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"priority"})
+	for _, p := range querierapi.QueryPriorities {
+		s.priorityQueueLength.WithLabelValues(p.String())
+		s.priorityQueueDuration.WithLabelValues(p.String())
+	}
 	s.connectedQuerierClients = promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
 		Name: "cortex_query_scheduler_connected_querier_clients",
 		Help: "Number of querier worker clients currently connected to the query-scheduler.",
@@ -191,6 +212,7 @@ type schedulerRequest struct {
 	queryID         uint64
 	request         *httpgrpc.HTTPRequest
 	statsEnabled    bool
+	priority        querierapi.QueryPriority
 
 	enqueueTime time.Time
 
@@ -330,6 +352,7 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		queryID:         msg.QueryID,
 		request:         msg.HttpRequest,
 		statsEnabled:    msg.StatsEnabled,
+		priority:        requestPriority(msg.HttpRequest),
 	}
 
 	now := time.Now()
@@ -347,8 +370,9 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxQueriersPerUser)
 
 	s.activeUsers.UpdateUserTimestamp(userID, now)
-	return s.requestQueue.EnqueueRequest(userID, req, maxQueriers, func() {
+	return s.requestQueue.EnqueueRequest(userID, req, req.priority, maxQueriers, func() {
 		shouldCancel = false
+		s.priorityQueueLength.WithLabelValues(req.priority.String()).Inc()
 
 		s.pendingRequestsMu.Lock()
 		s.pendingRequests[requestKey{frontendAddr: frontendAddr, queryID: msg.QueryID}] = req
@@ -356,6 +380,17 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 	})
 }
 
+// requestPriority returns the priority class of the request, based on its HTTP headers.
+func requestPriority(req *httpgrpc.HTTPRequest) querierapi.QueryPriority {
+	header := http.Header{}
+	for _, h := range req.GetHeaders() {
+		for _, v := range h.Values {
+			header.Add(h.Key, v)
+		}
+	}
+	return querierapi.QueryPriorityFromHeader(header)
+}
+
 // This method doesn't do removal from the queue.
 func (s *Scheduler) cancelRequestAndRemoveFromPending(frontendAddr string, queryID uint64) {
 	s.pendingRequestsMu.Lock()
@@ -399,7 +434,10 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 
 		r := req.(*schedulerRequest)
 
-		s.queueDuration.Observe(time.Since(r.enqueueTime).Seconds())
+		queueDuration := time.Since(r.enqueueTime).Seconds()
+		s.queueDuration.Observe(queueDuration)
+		s.priorityQueueLength.WithLabelValues(r.priority.String()).Dec()
+		s.priorityQueueDuration.WithLabelValues(r.priority.String()).Observe(queueDuration)
 		r.queueSpan.Finish()
 
 		/*
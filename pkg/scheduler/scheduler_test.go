@@ -446,6 +446,81 @@ func TestSchedulerMetrics(t *testing.T) {
 	`), "cortex_query_scheduler_queue_length"))
 }
 
+func TestSchedulerPriorityMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+
+	_, frontendClient, querierClient := setupScheduler(t, reg)
+
+	frontendLoop := initFrontendLoop(t, frontendClient, "frontend-12345")
+	for queryID, headers := range [][]*httpgrpc.Header{
+		{{Key: "X-Mimir-Query-Priority", Values: []string{"rule"}}},
+		{{Key: "X-Dashboard-Uid", Values: []string{"abc"}}},
+		nil,
+		{{Key: "X-Mimir-Query-Priority", Values: []string{"unknown"}}},
+	} {
+		frontendToScheduler(t, frontendLoop, &schedulerpb.FrontendToScheduler{
+			Type:        schedulerpb.ENQUEUE,
+			QueryID:     uint64(queryID),
+			UserID:      "test",
+			HttpRequest: &httpgrpc.HTTPRequest{Method: "GET", Url: "/hello", Headers: headers},
+		})
+	}
+
+	require.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_query_scheduler_priority_queue_length Number of queries in the queue, by priority class.
+		# TYPE cortex_query_scheduler_priority_queue_length gauge
+		cortex_query_scheduler_priority_queue_length{priority="adhoc"} 2
+		cortex_query_scheduler_priority_queue_length{priority="dashboard"} 1
+		cortex_query_scheduler_priority_queue_length{priority="rule"} 1
+	`), "cortex_query_scheduler_priority_queue_length"))
+
+	querierLoop, err := querierClient.QuerierLoop(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, querierLoop.Send(&schedulerpb.QuerierToScheduler{QuerierID: "querier-1"}))
+
+	_, err = querierLoop.Recv()
+	require.NoError(t, err)
+
+	// Prioritization is disabled by default, so requests are dequeued in FIFO order.
+	require.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_query_scheduler_priority_queue_length Number of queries in the queue, by priority class.
+		# TYPE cortex_query_scheduler_priority_queue_length gauge
+		cortex_query_scheduler_priority_queue_length{priority="adhoc"} 2
+		cortex_query_scheduler_priority_queue_length{priority="dashboard"} 1
+		cortex_query_scheduler_priority_queue_length{priority="rule"} 0
+	`), "cortex_query_scheduler_priority_queue_length"))
+
+	// Dequeue the remaining requests.
+	require.NoError(t, querierLoop.Send(&schedulerpb.QuerierToScheduler{}))
+	for i := 0; i < 3; i++ {
+		_, err = querierLoop.Recv()
+		require.NoError(t, err)
+		require.NoError(t, querierLoop.Send(&schedulerpb.QuerierToScheduler{}))
+	}
+
+	require.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_query_scheduler_priority_queue_length Number of queries in the queue, by priority class.
+		# TYPE cortex_query_scheduler_priority_queue_length gauge
+		cortex_query_scheduler_priority_queue_length{priority="adhoc"} 0
+		cortex_query_scheduler_priority_queue_length{priority="dashboard"} 0
+		cortex_query_scheduler_priority_queue_length{priority="rule"} 0
+	`), "cortex_query_scheduler_priority_queue_length"))
+
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+
+	queueDurationCounts := map[string]uint64{}
+	for _, family := range metrics {
+		if family.GetName() != "cortex_query_scheduler_priority_queue_duration_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			queueDurationCounts[m.GetLabel()[0].GetValue()] = m.GetHistogram().GetSampleCount()
+		}
+	}
+	require.Equal(t, map[string]uint64{"adhoc": 2, "dashboard": 1, "rule": 1}, queueDurationCounts)
+}
+
 func initFrontendLoop(t *testing.T, client schedulerpb.SchedulerForFrontendClient, frontendAddr string) schedulerpb.SchedulerForFrontend_FrontendLoopClient {
 	loop, err := client.FrontendLoop(context.Background())
 	require.NoError(t, err)
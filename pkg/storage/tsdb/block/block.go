@@ -33,6 +33,8 @@ const (
 	IndexFilename = "index"
 	// IndexHeaderFilename is the canonical name for binary index header file that stores essential information.
 	IndexHeaderFilename = "index-header"
+	// SparseIndexHeaderFilename is the canonical name for the file storing the sparse in-memory representation of the index-header.
+	SparseIndexHeaderFilename = "sparse-index-header"
 	// ChunksDirname is the known dir name for chunks with compressed samples.
 	ChunksDirname = "chunks"
 
@@ -40,6 +42,9 @@ const (
 	DebugMetas = "debug/metas"
 )
 
+// optionalFilenames are the files which may be stored alongside a block, in addition to the index and chunks.
+var optionalFilenames = []string{ExemplarsFilename, LabelBloomFilterFilename, SparseIndexHeaderFilename}
+
 // Download downloads directory that is meant to be block directory. If any of the files
 // have a hash calculated in the meta file and it matches with what is in the destination path then
 // we do not download it. We always re-download the meta file.
@@ -119,6 +124,17 @@ func Upload(ctx context.Context, logger log.Logger, bkt objstore.Bucket, blockDi
 		return cleanUp(logger, bkt, id, errors.Wrap(err, "upload index"))
 	}
 
+	// The exemplars and label bloom filter files are optional.
+	for _, name := range optionalFilenames {
+		if _, err := os.Stat(filepath.Join(blockDir, name)); err == nil {
+			if err := objstore.UploadFile(ctx, logger, bkt, filepath.Join(blockDir, name), path.Join(id.String(), name)); err != nil {
+				return cleanUp(logger, bkt, id, errors.Wrapf(err, "upload %s", name))
+			}
+		} else if !os.IsNotExist(err) {
+			return cleanUp(logger, bkt, id, errors.Wrapf(err, "stat %s", name))
+		}
+	}
+
 	// Meta.json always need to be uploaded as a last item. This will allow to assume block directories without meta file to be pending uploads.
 	if err := bkt.Upload(ctx, path.Join(id.String(), MetaFilename), strings.NewReader(metaEncoded.String())); err != nil {
 		// Don't call cleanUp here. Despite getting error, meta.json may have been uploaded in certain cases,
@@ -311,6 +327,18 @@ func GatherFileStats(blockDir string) (res []File, _ error) {
 	}
 	res = append(res, mf)
 
+	for _, name := range optionalFilenames {
+		optionalFile, err := os.Stat(filepath.Join(blockDir, name))
+		if err == nil {
+			res = append(res, File{
+				RelPath:   optionalFile.Name(),
+				SizeBytes: optionalFile.Size(),
+			})
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "stat %v", filepath.Join(blockDir, name))
+		}
+	}
+
 	metaFile, err := os.Stat(filepath.Join(blockDir, MetaFilename))
 	if err != nil {
 		return nil, errors.Wrapf(err, "stat %v", filepath.Join(blockDir, MetaFilename))
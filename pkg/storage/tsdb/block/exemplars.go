@@ -0,0 +1,625 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+const (
+	// ExemplarsFilename is the known file name of the exemplars stored alongside a block.
+	ExemplarsFilename = "exemplars"
+
+	exemplarsFileMagic    = uint32(0x4D455846)
+	exemplarsFileVersion2 = byte(2)
+	exemplarsHeaderSize   = 5
+
+	// ExemplarsFooterSize is the size of the footer at the end of the exemplars file, storing the
+	// offset of the index.
+	ExemplarsFooterSize = 8
+)
+
+var errInvalidExemplarsFile = errors.New("invalid exemplars file")
+
+// WriteExemplarsFile writes the exemplars of the input series to the exemplars file in the block directory.
+// The file is made of:
+//
+//   - A header (magic number and version).
+//   - The series grouped by metric name, each group being an independent snappy stream of the series of the
+//     metric, each one encoded as a length-prefixed protobuf TimeSeries. Groups are sorted by metric name,
+//     and series are sorted by labels within each group.
+//   - A snappy stream made of a zero length, marking the end of the series.
+//   - The index: the number of groups, followed by the metric name, offset and length of each group, all
+//     encoded as uvarints (the metric name is length-prefixed).
+//   - The footer: the offset of the index, as a big endian uint64.
+//
+// The index allows to read only the groups of the metrics matching a query. Series without exemplars are skipped.
+func WriteExemplarsFile(blockDir string, series []mimirpb.TimeSeries) (err error) {
+	series = append([]mimirpb.TimeSeries(nil), series...)
+	sort.Slice(series, func(i, j int) bool {
+		return compareExemplarsSeries(series[i].Labels, series[j].Labels) < 0
+	})
+
+	w, err := NewExemplarsWriter(blockDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			w.Abort()
+		}
+	}()
+
+	for _, s := range series {
+		if err := w.Write(s); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// compareExemplarsSeries compares the labels of two series in the order they're stored in the exemplars
+// file: by metric name first, and then by labels.
+func compareExemplarsSeries(a, b []mimirpb.LabelAdapter) int {
+	al, bl := mimirpb.FromLabelAdaptersToLabels(a), mimirpb.FromLabelAdaptersToLabels(b)
+	if c := strings.Compare(al.Get(labels.MetricName), bl.Get(labels.MetricName)); c != 0 {
+		return c
+	}
+	return labels.Compare(al, bl)
+}
+
+// ExemplarsIndexEntry locates the series of a metric in the exemplars file.
+type ExemplarsIndexEntry struct {
+	MetricName string
+	// Offset and Length of the snappy stream of the metric series in the file.
+	Offset, Length int64
+}
+
+// ExemplarsWriter writes the exemplars file of a block one series at a time, without buffering
+// the series in memory. Series must be written sorted by metric name and then by labels.
+type ExemplarsWriter struct {
+	blockDir string
+	f        *os.File
+	cw       *countingWriter
+	w        *snappy.Writer
+	sizeBuf  []byte
+	data     []byte
+
+	// The index of the groups written so far, the last one being the group being written.
+	index []ExemplarsIndexEntry
+}
+
+// NewExemplarsWriter creates a temporary exemplars file in the block directory, renamed to the
+// exemplars file once the writer is closed.
+func NewExemplarsWriter(blockDir string) (*ExemplarsWriter, error) {
+	f, err := os.Create(filepath.Join(blockDir, ExemplarsFilename+".tmp"))
+	if err != nil {
+		return nil, errors.Wrap(err, "create exemplars file")
+	}
+
+	w := &ExemplarsWriter{
+		blockDir: blockDir,
+		f:        f,
+		cw:       &countingWriter{w: bufio.NewWriter(f)},
+		sizeBuf:  make([]byte, binary.MaxVarintLen64),
+	}
+
+	header := make([]byte, exemplarsHeaderSize)
+	binary.BigEndian.PutUint32(header, exemplarsFileMagic)
+	header[4] = exemplarsFileVersion2
+	if _, err := w.cw.Write(header); err != nil {
+		w.Abort()
+		return nil, errors.Wrap(err, "write exemplars file header")
+	}
+
+	return w, nil
+}
+
+// Write writes the exemplars of the input series. Series without exemplars are skipped.
+func (w *ExemplarsWriter) Write(s mimirpb.TimeSeries) error {
+	if len(s.Exemplars) == 0 {
+		return nil
+	}
+
+	metricName := mimirpb.FromLabelAdaptersToLabels(s.Labels).Get(labels.MetricName)
+	if n := len(w.index); n == 0 || w.index[n-1].MetricName != metricName {
+		if n > 0 && w.index[n-1].MetricName > metricName {
+			return errors.Errorf("exemplars series are not sorted by metric name: %q written after %q", metricName, w.index[n-1].MetricName)
+		}
+		if err := w.startGroup(metricName); err != nil {
+			return err
+		}
+	}
+
+	ts := mimirpb.TimeSeries{Labels: s.Labels, Exemplars: s.Exemplars}
+	size := ts.Size()
+	if cap(w.data) < size {
+		w.data = make([]byte, size)
+	}
+	w.data = w.data[:size]
+	if _, err := ts.MarshalToSizedBuffer(w.data); err != nil {
+		return errors.Wrap(err, "encode exemplars")
+	}
+
+	return w.writeRecord(w.data)
+}
+
+// startGroup ends the snappy stream of the current group, if any, and starts the one of a new group.
+func (w *ExemplarsWriter) startGroup(metricName string) error {
+	if err := w.endGroup(); err != nil {
+		return err
+	}
+
+	w.index = append(w.index, ExemplarsIndexEntry{MetricName: metricName, Offset: w.cw.n})
+	w.w = snappy.NewBufferedWriter(w.cw)
+	return nil
+}
+
+// endGroup flushes the snappy stream of the current group, if any, and records its length in the index.
+func (w *ExemplarsWriter) endGroup() error {
+	if w.w == nil {
+		return nil
+	}
+
+	// Closing the snappy writer doesn't close the underlying writer.
+	if err := w.w.Close(); err != nil {
+		return errors.Wrap(err, "write exemplars")
+	}
+	w.w = nil
+
+	last := &w.index[len(w.index)-1]
+	last.Length = w.cw.n - last.Offset
+	return nil
+}
+
+// writeRecord writes a length-prefixed record to the current snappy stream.
+func (w *ExemplarsWriter) writeRecord(data []byte) error {
+	n := binary.PutUvarint(w.sizeBuf, uint64(len(data)))
+	if _, err := w.w.Write(w.sizeBuf[:n]); err != nil {
+		return errors.Wrap(err, "write exemplars")
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return errors.Wrap(err, "write exemplars")
+	}
+	return nil
+}
+
+// writeIndex writes the end of the series, the index and the footer.
+func (w *ExemplarsWriter) writeIndex() error {
+	if err := w.endGroup(); err != nil {
+		return err
+	}
+
+	// The end of the series is marked with a zero length, so that the file can be read sequentially
+	// without reading the index.
+	w.w = snappy.NewBufferedWriter(w.cw)
+	if err := w.writeRecord(nil); err != nil {
+		return err
+	}
+	if err := w.w.Close(); err != nil {
+		return errors.Wrap(err, "write exemplars")
+	}
+	w.w = nil
+
+	indexOffset := w.cw.n
+	buf := binary.AppendUvarint(nil, uint64(len(w.index)))
+	for _, e := range w.index {
+		buf = binary.AppendUvarint(buf, uint64(len(e.MetricName)))
+		buf = append(buf, e.MetricName...)
+		buf = binary.AppendUvarint(buf, uint64(e.Offset))
+		buf = binary.AppendUvarint(buf, uint64(e.Length))
+	}
+	buf = binary.BigEndian.AppendUint64(buf, uint64(indexOffset))
+	if _, err := w.cw.Write(buf); err != nil {
+		return errors.Wrap(err, "write exemplars index")
+	}
+	return w.cw.w.Flush()
+}
+
+// Close writes the index, and renames the temporary file to the exemplars file.
+// The temporary file is removed on error.
+func (w *ExemplarsWriter) Close() error {
+	if err := w.writeIndex(); err != nil {
+		w.Abort()
+		return errors.Wrap(err, "flush exemplars file")
+	}
+	if err := w.f.Sync(); err != nil {
+		w.Abort()
+		return errors.Wrap(err, "sync exemplars file")
+	}
+	if err := w.f.Close(); err != nil {
+		_ = os.Remove(w.f.Name())
+		return errors.Wrap(err, "close exemplars file")
+	}
+
+	return os.Rename(w.f.Name(), filepath.Join(w.blockDir, ExemplarsFilename))
+}
+
+// Abort closes and removes the temporary file, without writing the exemplars file.
+func (w *ExemplarsWriter) Abort() {
+	_ = w.f.Close()
+	_ = os.Remove(w.f.Name())
+}
+
+// countingWriter counts the bytes written to the underlying buffered writer.
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ReadExemplarsIndex reads the index of an exemplars file of the given size through r. The returned
+// entries are sorted by metric name.
+func ReadExemplarsIndex(r io.ReaderAt, size int64) ([]ExemplarsIndexEntry, error) {
+	if size < exemplarsHeaderSize+ExemplarsFooterSize {
+		return nil, errors.Wrap(errInvalidExemplarsFile, "file too small")
+	}
+
+	header := make([]byte, exemplarsHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, errors.Wrap(err, "read exemplars file header")
+	}
+	if err := checkExemplarsHeader(header); err != nil {
+		return nil, err
+	}
+
+	footer := make([]byte, ExemplarsFooterSize)
+	if _, err := r.ReadAt(footer, size-ExemplarsFooterSize); err != nil {
+		return nil, errors.Wrap(err, "read exemplars file footer")
+	}
+	indexOffset := int64(binary.BigEndian.Uint64(footer))
+	if indexOffset < exemplarsHeaderSize || indexOffset > size-ExemplarsFooterSize {
+		return nil, errors.Wrapf(errInvalidExemplarsFile, "invalid index offset %d", indexOffset)
+	}
+
+	buf := make([]byte, size-ExemplarsFooterSize-indexOffset)
+	if _, err := r.ReadAt(buf, indexOffset); err != nil {
+		return nil, errors.Wrap(err, "read exemplars index")
+	}
+	return decodeExemplarsIndex(buf, indexOffset)
+}
+
+func decodeExemplarsIndex(buf []byte, indexOffset int64) ([]ExemplarsIndexEntry, error) {
+	d := exemplarsIndexDecoder{buf: buf}
+	count := d.uvarint()
+	if d.err == nil && count > uint64(len(buf)) {
+		return nil, errors.Wrapf(errInvalidExemplarsFile, "invalid index entries count %d", count)
+	}
+
+	entries := make([]ExemplarsIndexEntry, 0, count)
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		e := ExemplarsIndexEntry{
+			MetricName: d.string(),
+			Offset:     int64(d.uvarint()),
+			Length:     int64(d.uvarint()),
+		}
+		if d.err == nil && (e.Offset < exemplarsHeaderSize || e.Length < 0 || e.Offset+e.Length > indexOffset) {
+			return nil, errors.Wrapf(errInvalidExemplarsFile, "invalid index entry for metric %q", e.MetricName)
+		}
+		entries = append(entries, e)
+	}
+	if d.err != nil {
+		return nil, errors.Wrap(errInvalidExemplarsFile, "decode index")
+	}
+	return entries, nil
+}
+
+type exemplarsIndexDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *exemplarsIndexDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errInvalidExemplarsFile
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *exemplarsIndexDecoder) string() string {
+	l := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if l > uint64(len(d.buf)) {
+		d.err = errInvalidExemplarsFile
+		return ""
+	}
+	s := string(d.buf[:l])
+	d.buf = d.buf[l:]
+	return s
+}
+
+// ExemplarsIterator iterates over the series of an exemplars file, sorted by metric name and then by labels.
+type ExemplarsIterator interface {
+	// Next advances the iterator to the next series, and returns false once there are no more
+	// series or an error occurred.
+	Next() bool
+	// At returns the current series. The returned series can be retained.
+	At() mimirpb.TimeSeries
+	// Err returns the error occurred while iterating, if any.
+	Err() error
+}
+
+// ExemplarsReader reads the series of an exemplars file one at a time.
+type ExemplarsReader struct {
+	br  *bufio.Reader
+	cur mimirpb.TimeSeries
+	err error
+}
+
+// NewExemplarsReader reads the header of the exemplars file content from r, and returns a reader
+// over its series.
+func NewExemplarsReader(r io.Reader) (*ExemplarsReader, error) {
+	header := make([]byte, exemplarsHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.Wrap(errInvalidExemplarsFile, "read header")
+	}
+	if err := checkExemplarsHeader(header); err != nil {
+		return nil, err
+	}
+
+	return NewExemplarsGroupReader(r), nil
+}
+
+// NewExemplarsGroupReader returns a reader over the series of a group of the exemplars file, whose
+// content is read from r, starting at the group offset, as located by the exemplars index.
+func NewExemplarsGroupReader(r io.Reader) *ExemplarsReader {
+	return &ExemplarsReader{br: bufio.NewReader(snappy.NewReader(r))}
+}
+
+func checkExemplarsHeader(header []byte) error {
+	if binary.BigEndian.Uint32(header) != exemplarsFileMagic {
+		return errors.Wrap(errInvalidExemplarsFile, "invalid magic number")
+	}
+	if header[4] != exemplarsFileVersion2 {
+		return errors.Wrapf(errInvalidExemplarsFile, "unsupported version %d", header[4])
+	}
+	return nil
+}
+
+func (r *ExemplarsReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	size, err := binary.ReadUvarint(r.br)
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+	if err != nil {
+		r.err = errors.Wrap(err, "read exemplars")
+		return false
+	}
+	if size == 0 {
+		// End of the series.
+		return false
+	}
+
+	// Allocate a new buffer for each series, because the unmarshalled labels reference it.
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.br, data); err != nil {
+		r.err = errors.Wrap(err, "read exemplars")
+		return false
+	}
+
+	r.cur = mimirpb.TimeSeries{}
+	if err := r.cur.Unmarshal(data); err != nil {
+		r.err = errors.Wrap(err, "decode exemplars")
+		return false
+	}
+	return true
+}
+
+func (r *ExemplarsReader) At() mimirpb.TimeSeries {
+	return r.cur
+}
+
+func (r *ExemplarsReader) Err() error {
+	return r.err
+}
+
+// ReadExemplars reads the exemplars file content from r, and calls fn for each series in the file,
+// in the order they have been written, without reading the index. The series passed to fn can be retained.
+func ReadExemplars(r io.Reader, fn func(series mimirpb.TimeSeries) error) error {
+	er, err := NewExemplarsReader(r)
+	if err != nil {
+		return err
+	}
+
+	for er.Next() {
+		if err := fn(er.At()); err != nil {
+			return err
+		}
+	}
+	return er.Err()
+}
+
+// ReadExemplarsFile reads all the series from the exemplars file in the block directory.
+// Returns os.ErrNotExist if the block has no exemplars file.
+func ReadExemplarsFile(blockDir string) ([]mimirpb.TimeSeries, error) {
+	f, err := os.Open(filepath.Join(blockDir, ExemplarsFilename))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var series []mimirpb.TimeSeries
+	err = ReadExemplars(f, func(s mimirpb.TimeSeries) error {
+		series = append(series, s)
+		return nil
+	})
+	return series, err
+}
+
+// MergeExemplars merges the input sets of series, each one sorted by metric name and then by labels, into
+// a single set sorted the same way. The exemplars of the same series are merged and deduplicated by timestamp, assuming the
+// exemplars of each series are sorted by timestamp.
+func MergeExemplars(sets ...[]mimirpb.TimeSeries) []mimirpb.TimeSeries {
+	var result []mimirpb.TimeSeries
+	for _, set := range sets {
+		result = mergeExemplarsSets(result, set)
+	}
+	return result
+}
+
+// mergedExemplarsIterator merges the series of multiple iterators, each one sorted by metric name and then by labels.
+type mergedExemplarsIterator struct {
+	its []ExemplarsIterator
+	// The current series of each iterator, valid if the iterator is not exhausted.
+	heads     []mimirpb.TimeSeries
+	exhausted []bool
+	// Whether the current series of each iterator has been merged and should be advanced.
+	consumed []bool
+	cur      mimirpb.TimeSeries
+	err      error
+}
+
+// NewMergedExemplarsIterator returns an iterator merging the series of the input iterators, each one
+// sorted by metric name and then by labels, the same way as MergeExemplars does, without buffering all the series in memory.
+func NewMergedExemplarsIterator(its ...ExemplarsIterator) ExemplarsIterator {
+	consumed := make([]bool, len(its))
+	for i := range consumed {
+		consumed[i] = true
+	}
+
+	return &mergedExemplarsIterator{
+		its:       its,
+		heads:     make([]mimirpb.TimeSeries, len(its)),
+		exhausted: make([]bool, len(its)),
+		consumed:  consumed,
+	}
+}
+
+func (m *mergedExemplarsIterator) Next() bool {
+	if m.err != nil {
+		return false
+	}
+
+	// Advance the iterators whose current series has been merged.
+	for i, it := range m.its {
+		if !m.consumed[i] || m.exhausted[i] {
+			continue
+		}
+		m.consumed[i] = false
+
+		if it.Next() {
+			m.heads[i] = it.At()
+			continue
+		}
+		if err := it.Err(); err != nil {
+			m.err = err
+			return false
+		}
+		m.exhausted[i] = true
+	}
+
+	// Find the first series in the exemplars file order, and merge the exemplars of all the iterators at that series.
+	found := false
+	for i := range m.its {
+		if m.exhausted[i] {
+			continue
+		}
+		if !found {
+			m.cur = m.heads[i]
+			m.consumed[i] = true
+			found = true
+			continue
+		}
+
+		switch c := compareExemplarsSeries(m.heads[i].Labels, m.cur.Labels); {
+		case c < 0:
+			for j := 0; j < i; j++ {
+				m.consumed[j] = false
+			}
+			m.cur = m.heads[i]
+			m.consumed[i] = true
+		case c == 0:
+			m.cur = mimirpb.TimeSeries{Labels: m.cur.Labels, Exemplars: mergeExemplars(m.cur.Exemplars, m.heads[i].Exemplars)}
+			m.consumed[i] = true
+		}
+	}
+	return found
+}
+
+func (m *mergedExemplarsIterator) At() mimirpb.TimeSeries {
+	return m.cur
+}
+
+func (m *mergedExemplarsIterator) Err() error {
+	return m.err
+}
+
+func mergeExemplarsSets(a, b []mimirpb.TimeSeries) []mimirpb.TimeSeries {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+
+	result := make([]mimirpb.TimeSeries, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := compareExemplarsSeries(a[i].Labels, b[j].Labels); {
+		case c < 0:
+			result = append(result, a[i])
+			i++
+		case c > 0:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, mimirpb.TimeSeries{Labels: a[i].Labels, Exemplars: mergeExemplars(a[i].Exemplars, b[j].Exemplars)})
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	result = append(result, b[j:]...)
+	return result
+}
+
+// mergeExemplars merges and dedupes two lists of exemplars of the same series, sorted by timestamp.
+func mergeExemplars(a, b []mimirpb.Exemplar) []mimirpb.Exemplar {
+	result := make([]mimirpb.Exemplar, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].TimestampMs < b[j].TimestampMs {
+			result = append(result, a[i])
+			i++
+		} else if a[i].TimestampMs > b[j].TimestampMs {
+			result = append(result, b[j])
+			j++
+		} else {
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	result = append(result, b[j:]...)
+	return result
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestWriteAndReadExemplarsFile(t *testing.T) {
+	dir := t.TempDir()
+
+	input := []mimirpb.TimeSeries{
+		{
+			Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "series_b")),
+			Exemplars: []mimirpb.Exemplar{
+				{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: 1, TimestampMs: 10},
+				{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "2")), Value: 2, TimestampMs: 20},
+			},
+		},
+		{
+			Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "series_c")),
+		},
+		{
+			Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "series_a")),
+			Exemplars: []mimirpb.Exemplar{
+				{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "3")), Value: 3, TimestampMs: 30},
+			},
+		},
+	}
+
+	require.NoError(t, WriteExemplarsFile(dir, input))
+
+	// The temporary file has been renamed.
+	_, err := os.Stat(filepath.Join(dir, ExemplarsFilename+".tmp"))
+	require.True(t, os.IsNotExist(err))
+
+	actual, err := ReadExemplarsFile(dir)
+	require.NoError(t, err)
+
+	// Series are sorted by labels, and series without exemplars are skipped.
+	assert.Equal(t, []mimirpb.TimeSeries{input[2], input[0]}, actual)
+}
+
+func TestReadExemplarsIndex(t *testing.T) {
+	dir := t.TempDir()
+
+	series := func(lbls ...string) mimirpb.TimeSeries {
+		return mimirpb.TimeSeries{
+			Labels:    mimirpb.FromLabelsToLabelAdapters(labels.FromStrings(lbls...)),
+			Exemplars: []mimirpb.Exemplar{{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: 1, TimestampMs: 10}},
+		}
+	}
+
+	// The "A" label sorts before the metric name, but series are grouped by metric name first.
+	input := []mimirpb.TimeSeries{
+		series("__name__", "series_b", "A", "1"),
+		series("__name__", "series_a", "A", "2"),
+		series("__name__", "series_b", "A", "0"),
+		series("A", "3"),
+	}
+	require.NoError(t, WriteExemplarsFile(dir, input))
+
+	content, err := os.ReadFile(filepath.Join(dir, ExemplarsFilename))
+	require.NoError(t, err)
+
+	index, err := ReadExemplarsIndex(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	require.Len(t, index, 3)
+
+	readGroup := func(e ExemplarsIndexEntry) []mimirpb.TimeSeries {
+		var actual []mimirpb.TimeSeries
+		r := NewExemplarsGroupReader(bytes.NewReader(content[e.Offset : e.Offset+e.Length]))
+		for r.Next() {
+			actual = append(actual, r.At())
+		}
+		require.NoError(t, r.Err())
+		return actual
+	}
+
+	assert.Equal(t, "", index[0].MetricName)
+	assert.Equal(t, []mimirpb.TimeSeries{input[3]}, readGroup(index[0]))
+	assert.Equal(t, "series_a", index[1].MetricName)
+	assert.Equal(t, []mimirpb.TimeSeries{input[1]}, readGroup(index[1]))
+	assert.Equal(t, "series_b", index[2].MetricName)
+	assert.Equal(t, []mimirpb.TimeSeries{input[2], input[0]}, readGroup(index[2]))
+
+	// The sequential reader reads all the groups, without reading the index.
+	actual, err := ReadExemplarsFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []mimirpb.TimeSeries{input[3], input[1], input[2], input[0]}, actual)
+
+	// The index of a truncated file is invalid.
+	_, err = ReadExemplarsIndex(bytes.NewReader(content[:len(content)-1]), int64(len(content)-1))
+	require.ErrorIs(t, err, errInvalidExemplarsFile)
+}
+
+func TestExemplarsWriter_ShouldFailIfSeriesAreNotSortedByMetricName(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := NewExemplarsWriter(dir)
+	require.NoError(t, err)
+	defer w.Abort()
+
+	exemplars := []mimirpb.Exemplar{{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: 1, TimestampMs: 10}}
+	require.NoError(t, w.Write(mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "series_b")), Exemplars: exemplars}))
+	require.Error(t, w.Write(mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "series_a")), Exemplars: exemplars}))
+}
+
+func TestReadExemplars_ShouldFailOnInvalidFile(t *testing.T) {
+	for name, content := range map[string][]byte{
+		"empty":                nil,
+		"invalid magic number": {0, 1, 2, 3, exemplarsFileVersion2},
+		"unsupported version":  {0x4D, 0x45, 0x58, 0x46, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := ReadExemplars(bytes.NewReader(content), func(mimirpb.TimeSeries) error { return nil })
+			require.ErrorIs(t, err, errInvalidExemplarsFile)
+		})
+	}
+}
+
+func TestReadExemplarsFile_ShouldReturnNotExistIfMissing(t *testing.T) {
+	_, err := ReadExemplarsFile(t.TempDir())
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestUpload_ShouldUploadExemplarsFile(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	blockID, err := CreateBlock(ctx, tmpDir, []labels.Labels{
+		labels.FromStrings("a", "1"),
+		labels.FromStrings("a", "2"),
+		labels.FromStrings("a", "3"),
+	}, 100, 0, 1000, labels.FromStrings("ext1", "val1"))
+	require.NoError(t, err)
+
+	blockDir := filepath.Join(tmpDir, blockID.String())
+	require.NoError(t, WriteExemplarsFile(blockDir, []mimirpb.TimeSeries{{
+		Labels:    mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("a", "1")),
+		Exemplars: []mimirpb.Exemplar{{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: 1, TimestampMs: 10}},
+	}}))
+
+	require.NoError(t, Upload(ctx, log.NewNopLogger(), bkt, blockDir, nil))
+
+	ok, err := bkt.Exists(ctx, path.Join(blockID.String(), ExemplarsFilename))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	meta, err := DownloadMeta(ctx, log.NewNopLogger(), bkt, blockID)
+	require.NoError(t, err)
+
+	found := false
+	for _, f := range meta.Thanos.Files {
+		if f.RelPath == ExemplarsFilename {
+			found = true
+			assert.Greater(t, f.SizeBytes, int64(0))
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestMergeExemplars(t *testing.T) {
+	exemplar := func(ts int64) mimirpb.Exemplar {
+		return mimirpb.Exemplar{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: float64(ts), TimestampMs: ts}
+	}
+	series := func(name string, exemplars ...mimirpb.Exemplar) mimirpb.TimeSeries {
+		return mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", name)), Exemplars: exemplars}
+	}
+
+	actual := MergeExemplars(
+		[]mimirpb.TimeSeries{series("a", exemplar(1), exemplar(3)), series("c", exemplar(1))},
+		nil,
+		[]mimirpb.TimeSeries{series("a", exemplar(2), exemplar(3)), series("b", exemplar(5))},
+	)
+
+	assert.Equal(t, []mimirpb.TimeSeries{
+		series("a", exemplar(1), exemplar(2), exemplar(3)),
+		series("b", exemplar(5)),
+		series("c", exemplar(1)),
+	}, actual)
+}
+
+func TestMergedExemplarsIterator(t *testing.T) {
+	exemplar := func(ts int64) mimirpb.Exemplar {
+		return mimirpb.Exemplar{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: float64(ts), TimestampMs: ts}
+	}
+	series := func(name string, exemplars ...mimirpb.Exemplar) mimirpb.TimeSeries {
+		return mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", name)), Exemplars: exemplars}
+	}
+
+	sets := [][]mimirpb.TimeSeries{
+		{series("a", exemplar(1), exemplar(3)), series("c", exemplar(1))},
+		nil,
+		{series("a", exemplar(2), exemplar(3)), series("b", exemplar(5))},
+		{series("b", exemplar(4)), series("d", exemplar(1))},
+	}
+
+	// Read each set from an exemplars file, to also test the reader.
+	var its []ExemplarsIterator
+	for _, set := range sets {
+		dir := t.TempDir()
+		require.NoError(t, WriteExemplarsFile(dir, set))
+
+		f, err := os.Open(filepath.Join(dir, ExemplarsFilename))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+
+		r, err := NewExemplarsReader(f)
+		require.NoError(t, err)
+		its = append(its, r)
+	}
+
+	var actual []mimirpb.TimeSeries
+	it := NewMergedExemplarsIterator(its...)
+	for it.Next() {
+		actual = append(actual, it.At())
+	}
+	require.NoError(t, it.Err())
+
+	assert.Equal(t, MergeExemplars(sets...), actual)
+	assert.Equal(t, []mimirpb.TimeSeries{
+		series("a", exemplar(1), exemplar(2), exemplar(3)),
+		series("b", exemplar(4), exemplar(5)),
+		series("c", exemplar(1)),
+		series("d", exemplar(1)),
+	}, actual)
+}
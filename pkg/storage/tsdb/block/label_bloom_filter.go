@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb/index"
+)
+
+const (
+	// LabelBloomFilterFilename is the known file name of the label bloom filter stored alongside a block.
+	LabelBloomFilterFilename = "label-bloom-filter"
+
+	labelBloomFilterMagic    = uint32(0x4D4C4246)
+	labelBloomFilterVersion1 = byte(1)
+
+	// The header is made of: magic number (4 bytes), version (1 byte), number of hash functions (4 bytes)
+	// and number of 64-bit words in the bitset (4 bytes). The bitset is followed by a CRC32 Castagnoli
+	// checksum (4 bytes) of the header and bitset.
+	labelBloomFilterHeaderSize   = 4 + 1 + 4 + 4
+	labelBloomFilterChecksumSize = 4
+
+	labelBloomFilterMaxHashes = 32
+)
+
+var (
+	errInvalidLabelBloomFilter = errors.New("invalid label bloom filter")
+
+	labelBloomFilterCastagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// LabelBloomFilter is a probabilistic set of the label name/value pairs of a block. MayContain()
+// never returns false for a pair added to the filter, while it may return true for a pair which
+// hasn't been added, with a probability close to the false positive rate the filter was sized for.
+type LabelBloomFilter struct {
+	bits      []uint64
+	numHashes uint32
+}
+
+// NewLabelBloomFilter returns an empty LabelBloomFilter sized to hold numItems label name/value pairs
+// with the given false positive rate.
+func NewLabelBloomFilter(numItems int, falsePositiveRate float64) *LabelBloomFilter {
+	if numItems < 1 {
+		numItems = 1
+	}
+
+	// Compute the optimal number of bits and hash functions.
+	numBits := math.Ceil(-float64(numItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	numWords := int(math.Ceil(numBits / 64))
+	numHashes := int(math.Round(float64(numWords*64) / float64(numItems) * math.Ln2))
+
+	if numHashes < 1 {
+		numHashes = 1
+	} else if numHashes > labelBloomFilterMaxHashes {
+		numHashes = labelBloomFilterMaxHashes
+	}
+
+	return &LabelBloomFilter{
+		bits:      make([]uint64, numWords),
+		numHashes: uint32(numHashes),
+	}
+}
+
+// Add adds the label name/value pair to the filter.
+func (f *LabelBloomFilter) Add(name, value string) {
+	h1, h2 := labelBloomFilterHashes(name, value)
+	numBits := uint32(len(f.bits) * 64)
+
+	for i := uint32(0); i < f.numHashes; i++ {
+		bit := (h1 + i*h2) % numBits
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MayContain returns false if the label name/value pair has definitely not been added to the filter,
+// or true if it may have been added.
+func (f *LabelBloomFilter) MayContain(name, value string) bool {
+	h1, h2 := labelBloomFilterHashes(name, value)
+	numBits := uint32(len(f.bits) * 64)
+
+	for i := uint32(0); i < f.numHashes; i++ {
+		bit := (h1 + i*h2) % numBits
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SizeBytes returns the size of the filter bitset in bytes.
+func (f *LabelBloomFilter) SizeBytes() int {
+	return len(f.bits) * 8
+}
+
+// EstimatedFalsePositiveRate returns the false positive rate of the filter, estimated from the ratio of bits set.
+func (f *LabelBloomFilter) EstimatedFalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.bits {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(len(f.bits)*64), float64(f.numHashes))
+}
+
+// labelBloomFilterHashes returns the two hashes of the label name/value pair used to compute the
+// filter bits, according to the Kirsch-Mitzenmacher double hashing technique. The hash is FNV-1a,
+// computed without allocations.
+func labelBloomFilterHashes(name, value string) (uint32, uint32) {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for i := 0; i < len(name); i++ {
+		h ^= uint64(name[i])
+		h *= prime64
+	}
+	// Separate name and value with a byte which can't be part of a valid UTF-8 string.
+	h ^= 0xff
+	h *= prime64
+	for i := 0; i < len(value); i++ {
+		h ^= uint64(value[i])
+		h *= prime64
+	}
+
+	// The second hash must be odd, so that it's coprime with the number of bits (a multiple of 64).
+	return uint32(h), uint32(h>>32) | 1
+}
+
+// MarshalBinary encodes the filter, including a checksum of its content.
+func (f *LabelBloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, labelBloomFilterHeaderSize, labelBloomFilterHeaderSize+len(f.bits)*8+labelBloomFilterChecksumSize)
+	binary.BigEndian.PutUint32(data[0:], labelBloomFilterMagic)
+	data[4] = labelBloomFilterVersion1
+	binary.BigEndian.PutUint32(data[5:], f.numHashes)
+	binary.BigEndian.PutUint32(data[9:], uint32(len(f.bits)))
+
+	for _, w := range f.bits {
+		data = binary.BigEndian.AppendUint64(data, w)
+	}
+	return binary.BigEndian.AppendUint32(data, crc32.Checksum(data, labelBloomFilterCastagnoliTable)), nil
+}
+
+// UnmarshalBinary decodes a filter encoded with MarshalBinary, verifying its checksum.
+func (f *LabelBloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < labelBloomFilterHeaderSize+labelBloomFilterChecksumSize {
+		return errors.Wrap(errInvalidLabelBloomFilter, "file too short")
+	}
+	if binary.BigEndian.Uint32(data[0:]) != labelBloomFilterMagic {
+		return errors.Wrap(errInvalidLabelBloomFilter, "invalid magic number")
+	}
+	if data[4] != labelBloomFilterVersion1 {
+		return errors.Wrapf(errInvalidLabelBloomFilter, "unsupported version %d", data[4])
+	}
+
+	numHashes := binary.BigEndian.Uint32(data[5:])
+	numWords := int(binary.BigEndian.Uint32(data[9:]))
+	if numHashes < 1 || numHashes > labelBloomFilterMaxHashes || numWords < 1 || len(data) != labelBloomFilterHeaderSize+numWords*8+labelBloomFilterChecksumSize {
+		return errors.Wrap(errInvalidLabelBloomFilter, "invalid header")
+	}
+
+	checksumOffset := len(data) - labelBloomFilterChecksumSize
+	if crc32.Checksum(data[:checksumOffset], labelBloomFilterCastagnoliTable) != binary.BigEndian.Uint32(data[checksumOffset:]) {
+		return errors.Wrap(errInvalidLabelBloomFilter, "checksum mismatch")
+	}
+
+	f.numHashes = numHashes
+	f.bits = make([]uint64, numWords)
+	for i := range f.bits {
+		f.bits[i] = binary.BigEndian.Uint64(data[labelBloomFilterHeaderSize+i*8:])
+	}
+	return nil
+}
+
+// ReadLabelBloomFilter reads and decodes the label bloom filter file content from r.
+func ReadLabelBloomFilter(r io.Reader) (*LabelBloomFilter, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read label bloom filter")
+	}
+
+	f := &LabelBloomFilter{}
+	if err := f.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// WriteLabelBloomFilterFile builds the label bloom filter of all the label name/value pairs in the block
+// index, sized for the given false positive rate, and writes it to the label bloom filter file in the
+// block directory.
+func WriteLabelBloomFilterFile(blockDir string, falsePositiveRate float64) (err error) {
+	ir, err := index.NewFileReader(filepath.Join(blockDir, IndexFilename))
+	if err != nil {
+		return errors.Wrap(err, "open index")
+	}
+	defer func() {
+		if closeErr := ir.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	names, err := ir.LabelNames()
+	if err != nil {
+		return errors.Wrap(err, "read label names")
+	}
+
+	valuesByName := make([][]string, len(names))
+	numItems := 0
+	for i, name := range names {
+		valuesByName[i], err = ir.LabelValues(name)
+		if err != nil {
+			return errors.Wrapf(err, "read label values of %s", name)
+		}
+		numItems += len(valuesByName[i])
+	}
+
+	f := NewLabelBloomFilter(numItems, falsePositiveRate)
+	for i, name := range names {
+		for _, value := range valuesByName[i] {
+			f.Add(name, value)
+		}
+	}
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "encode label bloom filter")
+	}
+
+	tmpPath := filepath.Join(blockDir, LabelBloomFilterFilename+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0o666); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "write label bloom filter file")
+	}
+	return os.Rename(tmpPath, filepath.Join(blockDir, LabelBloomFilterFilename))
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelBloomFilter(t *testing.T) {
+	const (
+		numItems          = 10000
+		falsePositiveRate = 0.01
+	)
+
+	f := NewLabelBloomFilter(numItems, falsePositiveRate)
+	for i := 0; i < numItems; i++ {
+		f.Add("pod", fmt.Sprintf("pod-%d", i))
+	}
+
+	// There should be no false negatives.
+	for i := 0; i < numItems; i++ {
+		require.True(t, f.MayContain("pod", fmt.Sprintf("pod-%d", i)))
+	}
+
+	// The label name should be part of the hashed item.
+	assert.False(t, f.MayContain("pod-0", ""))
+
+	falsePositives := 0
+	for i := 0; i < numItems; i++ {
+		if f.MayContain("pod", fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+
+	// Allow some margin, given the false positive rate is probabilistic.
+	assert.Less(t, float64(falsePositives)/numItems, 2*falsePositiveRate)
+	assert.InDelta(t, falsePositiveRate, f.EstimatedFalsePositiveRate(), falsePositiveRate)
+	assert.Equal(t, 11984, f.SizeBytes())
+}
+
+func TestLabelBloomFilter_MarshalAndUnmarshal(t *testing.T) {
+	f := NewLabelBloomFilter(100, 0.01)
+	f.Add("foo", "bar")
+
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+
+	actual, err := ReadLabelBloomFilter(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, f, actual)
+	assert.True(t, actual.MayContain("foo", "bar"))
+
+	t.Run("should fail on corrupted data", func(t *testing.T) {
+		corrupted := append([]byte(nil), data...)
+		corrupted[labelBloomFilterHeaderSize] ^= 0xff
+
+		_, err := ReadLabelBloomFilter(bytes.NewReader(corrupted))
+		assert.ErrorIs(t, err, errInvalidLabelBloomFilter)
+	})
+
+	t.Run("should fail on truncated data", func(t *testing.T) {
+		_, err := ReadLabelBloomFilter(bytes.NewReader(data[:len(data)-1]))
+		assert.ErrorIs(t, err, errInvalidLabelBloomFilter)
+	})
+}
+
+func TestWriteLabelBloomFilterFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	blockID, err := CreateBlock(context.Background(), tmpDir, []labels.Labels{
+		labels.FromStrings("__name__", "metric", "pod", "a"),
+		labels.FromStrings("__name__", "metric", "pod", "b"),
+		labels.FromStrings("__name__", "metric", "pod", "c"),
+	}, 100, 0, 1000, labels.EmptyLabels())
+	require.NoError(t, err)
+
+	blockDir := filepath.Join(tmpDir, blockID.String())
+	require.NoError(t, WriteLabelBloomFilterFile(blockDir, 0.01))
+
+	r, err := os.Open(filepath.Join(blockDir, LabelBloomFilterFilename))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	f, err := ReadLabelBloomFilter(r)
+	require.NoError(t, err)
+
+	for _, value := range []string{"a", "b", "c"} {
+		assert.True(t, f.MayContain("pod", value))
+	}
+	assert.True(t, f.MayContain("__name__", "metric"))
+	assert.False(t, f.MayContain("pod", "d"))
+
+	// The file should be listed in the block files.
+	files, err := GatherFileStats(blockDir)
+	require.NoError(t, err)
+
+	found := false
+	for _, file := range files {
+		found = found || file.RelPath == LabelBloomFilterFilename
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"golang.org/x/exp/slices"
+)
+
+// DefaultTopLabelNamesLimit is the default number of label names with the highest cardinality
+// recorded in the meta.json of blocks.
+const DefaultTopLabelNamesLimit = 10
+
+// LabelNameCardinality holds the number of values of a label name in a block.
+type LabelNameCardinality struct {
+	Name   string `json:"name"`
+	Values int    `json:"values"`
+}
+
+// GatherTopLabelNamesCardinality returns up to limit label names with the highest number of values in
+// the index of the block in blockDir, sorted by number of values in descending order and then by name.
+// The number of values of each label name is counted from the entries of the postings offset table,
+// which has an entry for each label name and value pair, so that no label value is decoded.
+func GatherTopLabelNamesCardinality(blockDir string, limit int) (_ []LabelNameCardinality, err error) {
+	f, err := fileutil.OpenMmapFile(filepath.Join(blockDir, IndexFilename))
+	if err != nil {
+		return nil, errors.Wrap(err, "open index")
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	b := realByteSlice(f.Bytes())
+	toc, err := index.NewTOCFromByteSlice(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "read index TOC")
+	}
+
+	var result []LabelNameCardinality
+	err = index.ReadPostingsOffsetTable(b, toc.PostingsTable, func(name, _ []byte, _ uint64, _ int) error {
+		// Skip the all postings key, which has an empty label name.
+		if len(name) == 0 {
+			return nil
+		}
+
+		// Entries are sorted by label name, so all the values of a label name are adjacent.
+		if l := len(result); l > 0 && result[l-1].Name == string(name) {
+			result[l-1].Values++
+			return nil
+		}
+		result = append(result, LabelNameCardinality{Name: string(name), Values: 1})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "read postings offset table")
+	}
+
+	slices.SortFunc(result, func(a, b LabelNameCardinality) bool {
+		if a.Values != b.Values {
+			return a.Values > b.Values
+		}
+		return a.Name < b.Name
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// realByteSlice implements index.ByteSlice over an in-memory byte slice.
+type realByteSlice []byte
+
+func (b realByteSlice) Len() int {
+	return len(b)
+}
+
+func (b realByteSlice) Range(start, end int) []byte {
+	return b[start:end]
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherTopLabelNamesCardinality(t *testing.T) {
+	dir := t.TempDir()
+
+	blockID, err := CreateBlock(context.Background(), dir, []labels.Labels{
+		labels.FromStrings("__name__", "up", "job", "a", "pod", "1"),
+		labels.FromStrings("__name__", "up", "job", "a", "pod", "2"),
+		labels.FromStrings("__name__", "up", "job", "b", "pod", "3"),
+		labels.FromStrings("__name__", "metric", "job", "b", "pod", "4", "zone", "z"),
+	}, 10, 0, 1000, labels.EmptyLabels())
+	require.NoError(t, err)
+
+	blockDir := filepath.Join(dir, blockID.String())
+
+	t.Run("should return all label names sorted by cardinality", func(t *testing.T) {
+		actual, err := GatherTopLabelNamesCardinality(blockDir, DefaultTopLabelNamesLimit)
+		require.NoError(t, err)
+		assert.Equal(t, []LabelNameCardinality{
+			{Name: "pod", Values: 4},
+			{Name: "__name__", Values: 2},
+			{Name: "job", Values: 2},
+			{Name: "zone", Values: 1},
+		}, actual)
+	})
+
+	t.Run("should honor the limit", func(t *testing.T) {
+		actual, err := GatherTopLabelNamesCardinality(blockDir, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []LabelNameCardinality{
+			{Name: "pod", Values: 4},
+			{Name: "__name__", Values: 2},
+		}, actual)
+	})
+}
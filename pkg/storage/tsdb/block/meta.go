@@ -73,6 +73,10 @@ type ThanosMeta struct {
 	// Useful to avoid API call to get size of each file, as well as for debugging purposes.
 	// Optional, added in v0.17.0.
 	Files []File `json:"files,omitempty"`
+
+	// TopLabelNames is a list of the label names with the highest number of values in the block index,
+	// sorted by number of values in descending order. Optional.
+	TopLabelNames []LabelNameCardinality `json:"top_label_names,omitempty"`
 }
 
 type Matchers []*labels.Matcher
@@ -24,6 +24,7 @@ const (
 	IndexCompressedFilename = IndexFilename + ".gz"
 	IndexVersion1           = 1
 	IndexVersion2           = 2 // Added CompactorShardID field.
+	IndexVersion3           = 3 // Added blocks stats fields.
 	SegmentsFormatUnknown   = ""
 
 	// SegmentsFormat1Based6Digits defined segments numbered with 6 digits numbers in a sequence starting from number 1
@@ -90,6 +91,19 @@ type Block struct {
 
 	// Block's compactor shard ID, copied from tsdb.CompactorShardIDExternalLabel label.
 	CompactorShardID string `json:"compactor_shard_id,omitempty"`
+
+	// Block's stats, copied from the meta.json. They're zero if unknown.
+	NumSeries  uint64 `json:"num_series,omitempty"`
+	NumChunks  uint64 `json:"num_chunks,omitempty"`
+	NumSamples uint64 `json:"num_samples,omitempty"`
+
+	// IndexSizeBytes is the size of the block index, copied from the files list in the meta.json.
+	// It's zero if unknown.
+	IndexSizeBytes int64 `json:"index_size_bytes,omitempty"`
+
+	// TopLabelNames is the list of label names with the highest number of values in the block,
+	// copied from the meta.json. It's empty if unknown.
+	TopLabelNames []block.LabelNameCardinality `json:"top_label_names,omitempty"`
 }
 
 // Within returns whether the block contains samples within the provided range.
@@ -153,7 +167,22 @@ func BlockFromThanosMeta(meta block.Meta) *Block {
 		SegmentsFormat:   segmentsFormat,
 		SegmentsNum:      segmentsNum,
 		CompactorShardID: meta.Thanos.Labels[mimir_tsdb.CompactorShardIDExternalLabel],
+		NumSeries:        meta.Stats.NumSeries,
+		NumChunks:        meta.Stats.NumChunks,
+		NumSamples:       meta.Stats.NumSamples,
+		IndexSizeBytes:   detectBlockIndexSize(meta),
+		TopLabelNames:    meta.Thanos.TopLabelNames,
+	}
+}
+
+func detectBlockIndexSize(meta block.Meta) int64 {
+	for _, file := range meta.Thanos.Files {
+		if file.RelPath == block.IndexFilename {
+			return file.SizeBytes
+		}
 	}
+
+	return 0
 }
 
 func detectBlockSegmentsFormat(meta block.Meta) (string, int) {
@@ -272,6 +272,43 @@ func TestBlockFromThanosMeta(t *testing.T) {
 				CompactorShardID: "some weird value",
 			},
 		},
+		"meta.json with stats": {
+			meta: block.Meta{
+				BlockMeta: tsdb.BlockMeta{
+					ULID:    blockID,
+					MinTime: 10,
+					MaxTime: 20,
+					Stats: tsdb.BlockStats{
+						NumSeries:  100,
+						NumChunks:  200,
+						NumSamples: 300,
+					},
+				},
+				Thanos: block.ThanosMeta{
+					Files: []block.File{
+						{RelPath: "index", SizeBytes: 1024},
+						{RelPath: "meta.json"},
+					},
+					TopLabelNames: []block.LabelNameCardinality{
+						{Name: "pod", Values: 50},
+						{Name: "__name__", Values: 10},
+					},
+				},
+			},
+			expected: Block{
+				ID:             blockID,
+				MinTime:        10,
+				MaxTime:        20,
+				NumSeries:      100,
+				NumChunks:      200,
+				NumSamples:     300,
+				IndexSizeBytes: 1024,
+				TopLabelNames: []block.LabelNameCardinality{
+					{Name: "pod", Values: 50},
+					{Name: "__name__", Values: 10},
+				},
+			},
+		},
 	}
 
 	for testName, testData := range tests {
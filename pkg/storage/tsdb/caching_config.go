@@ -14,6 +14,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/cache"
+	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/regexp"
 	"github.com/oklog/ulid"
@@ -23,15 +24,25 @@ import (
 
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketcache"
+	"github.com/grafana/mimir/pkg/storegateway/diskcache"
 )
 
 // subrangeSize is the size of each subrange that bucket objects are split into for better caching
 const subrangeSize int64 = 16000
 
-var supportedCacheBackends = []string{cache.BackendMemcached, cache.BackendRedis}
+// ChunksCacheBackendDisk is the value for the disk chunks cache backend.
+const ChunksCacheBackendDisk = "disk"
+
+var (
+	supportedCacheBackends       = []string{cache.BackendMemcached, cache.BackendRedis}
+	supportedChunksCacheBackends = []string{cache.BackendMemcached, cache.BackendRedis, ChunksCacheBackendDisk}
+
+	errInvalidChunksCacheInMemoryL1MaxItems = errors.New("the chunks cache in-memory L1 max items must be greater than or equal to 0")
+)
 
 type ChunksCacheConfig struct {
 	cache.BackendConfig `yaml:",inline"`
+	Disk                DiskChunksCacheConfig `yaml:"disk"`
 
 	MaxGetRangeRequests             int           `yaml:"max_get_range_requests" category:"advanced"`
 	AttributesTTL                   time.Duration `yaml:"attributes_ttl" category:"advanced"`
@@ -41,10 +52,11 @@ type ChunksCacheConfig struct {
 }
 
 func (cfg *ChunksCacheConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
-	f.StringVar(&cfg.Backend, prefix+"backend", "", fmt.Sprintf("Backend for chunks cache, if not empty. Supported values: %s.", strings.Join(supportedCacheBackends, ", ")))
+	f.StringVar(&cfg.Backend, prefix+"backend", "", fmt.Sprintf("Backend for chunks cache, if not empty. Supported values: %s.", strings.Join(supportedChunksCacheBackends, ", ")))
 
 	cfg.Memcached.RegisterFlagsWithPrefix(prefix+"memcached.", f)
 	cfg.Redis.RegisterFlagsWithPrefix(prefix+"redis.", f)
+	cfg.Disk.RegisterFlagsWithPrefix(f, prefix+"disk.")
 
 	f.IntVar(&cfg.MaxGetRangeRequests, prefix+"max-get-range-requests", 3, "Maximum number of sub-GetRange requests that a single GetRange request can be split into when fetching chunks. Zero or negative value = unlimited number of sub-requests.")
 	f.DurationVar(&cfg.AttributesTTL, prefix+"attributes-ttl", 168*time.Hour, "TTL for caching object attributes for chunks. If the metadata cache is configured, attributes will be stored under this cache backend, otherwise attributes are stored in the chunks cache backend.")
@@ -54,9 +66,84 @@ func (cfg *ChunksCacheConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix st
 }
 
 func (cfg *ChunksCacheConfig) Validate() error {
+	if cfg.Backend == ChunksCacheBackendDisk {
+		return cfg.Disk.Validate()
+	}
 	return cfg.BackendConfig.Validate()
 }
 
+type DiskChunksCacheConfig struct {
+	diskcache.Config `yaml:",inline"`
+
+	InMemoryL1MaxItems int `yaml:"in_memory_l1_max_items" category:"experimental"`
+}
+
+func (cfg *DiskChunksCacheConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
+	cfg.Config.RegisterFlagsWithPrefix(f, prefix)
+
+	f.IntVar(&cfg.InMemoryL1MaxItems, prefix+"in-memory-l1-max-items", 0, "Maximum number of items to keep in a first level in-memory LRU cache, looked up before the disk cache. 0 to disable the in-memory cache.")
+}
+
+func (cfg *DiskChunksCacheConfig) Validate() error {
+	if err := cfg.Config.Validate(); err != nil {
+		return err
+	}
+	if cfg.InMemoryL1MaxItems < 0 {
+		return errInvalidChunksCacheInMemoryL1MaxItems
+	}
+	return nil
+}
+
+// NewChunksCacheClient creates the cache client used by the chunks cache, or returns nil if the chunks cache is disabled.
+// The returned service, if not nil, releases the resources of the cache client once stopped, and must be started and
+// stopped by the caller.
+func NewChunksCacheClient(cfg ChunksCacheConfig, logger log.Logger, reg prometheus.Registerer) (cache.Cache, services.Service, error) {
+	if cfg.Backend != ChunksCacheBackendDisk {
+		client, err := cache.CreateClient("chunks-cache", cfg.BackendConfig, logger, prometheus.WrapRegistererWithPrefix("thanos_", reg))
+		return client, nil, err
+	}
+
+	client, err := diskcache.NewCache("chunks-cache", cfg.Disk.Config, chunksCacheItemType, logger, reg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "create chunks cache disk client")
+	}
+
+	// Stop the disk cache asynchronous writes once the chunks cache is no longer used.
+	stopper := services.NewIdleService(nil, func(error) error {
+		client.Stop()
+		return nil
+	})
+
+	if cfg.Disk.InMemoryL1MaxItems == 0 {
+		return client, stopper, nil
+	}
+
+	// The disk cache always allocates the fetched items on the heap, so it's safe for the in-memory cache to retain them.
+	l1, err := cache.WrapWithLRUCache(client, "chunks-cache", prometheus.WrapRegistererWithPrefix("cortex_", reg), cfg.Disk.InMemoryL1MaxItems, cfg.SubrangeTTL)
+	if err != nil {
+		client.Stop()
+		return nil, nil, errors.Wrap(err, "wrap chunks cache with in-memory cache")
+	}
+	return l1, stopper, nil
+}
+
+// chunksCacheItemType returns the item type of a chunks cache key, used to label the disk cache metrics.
+func chunksCacheItemType(key string) string {
+	// Keys of the fine-grained chunks cache start with "C:", while the keys of the caching bucket
+	// are made of an optional bucket ID followed by the operation.
+	parts := strings.SplitN(key, ":", 3)
+	if parts[0] == "C" {
+		return "chunks"
+	}
+	for _, part := range parts[:len(parts)-1] {
+		switch part {
+		case "subrange", "attrs", "iter", "exists", "content":
+			return part
+		}
+	}
+	return "other"
+}
+
 type MetadataCacheConfig struct {
 	cache.BackendConfig `yaml:",inline"`
 
@@ -141,6 +228,7 @@ func CreateCachingBucket(chunksCache cache.Cache, chunksConfig ChunksCacheConfig
 		if !chunksConfig.FineGrainedChunksCachingEnabled {
 			cfg.CacheGetRange("chunks", chunksCache, isTSDBChunkFile, subrangeSize, attributesCache, chunksConfig.AttributesTTL, chunksConfig.SubrangeTTL, chunksConfig.MaxGetRangeRequests)
 		}
+		cfg.CacheGetRange("exemplars", chunksCache, isBlockExemplarsFile, subrangeSize, attributesCache, chunksConfig.AttributesTTL, chunksConfig.SubrangeTTL, chunksConfig.MaxGetRangeRequests)
 	}
 
 	if !cachingConfigured {
@@ -173,6 +261,16 @@ func isBlockIndexFile(name string) bool {
 	return err == nil
 }
 
+func isBlockExemplarsFile(name string) bool {
+	// Ensure the path ends with "<block id>/<exemplars filename>".
+	if !strings.HasSuffix(name, "/"+block.ExemplarsFilename) {
+		return false
+	}
+
+	_, err := ulid.Parse(filepath.Base(filepath.Dir(name)))
+	return err == nil
+}
+
 func isBucketIndexFile(name string) bool {
 	// TODO can't reference bucketindex because of a circular dependency. To be fixed.
 	return strings.HasSuffix(name, "/bucket-index.json.gz")
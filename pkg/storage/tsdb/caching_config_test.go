@@ -6,11 +6,22 @@
 package tsdb
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/cache"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
 	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storegateway/diskcache"
 )
 
 func TestIsTenantDir(t *testing.T) {
@@ -39,3 +50,88 @@ func TestIsBlockIndexFile(t *testing.T) {
 	assert.True(t, isBlockIndexFile(fmt.Sprintf("%s/index", blockID.String())))
 	assert.True(t, isBlockIndexFile(fmt.Sprintf("/%s/index", blockID.String())))
 }
+
+func TestIsBlockExemplarsFile(t *testing.T) {
+	blockID := ulid.MustNew(1, nil)
+
+	assert.False(t, isBlockExemplarsFile(""))
+	assert.False(t, isBlockExemplarsFile("/exemplars"))
+	assert.False(t, isBlockExemplarsFile("test/exemplars"))
+	assert.False(t, isBlockExemplarsFile(fmt.Sprintf("%s/index", blockID.String())))
+	assert.True(t, isBlockExemplarsFile(fmt.Sprintf("%s/exemplars", blockID.String())))
+	assert.True(t, isBlockExemplarsFile(fmt.Sprintf("/%s/exemplars", blockID.String())))
+}
+
+func TestChunksCacheConfig_Validate(t *testing.T) {
+	validDisk := DiskChunksCacheConfig{
+		Config: diskcache.Config{Dir: "/tmp/chunks-cache", MaxSizeBytes: 1, MaxAsyncConcurrency: 1, MaxAsyncBufferSize: 1},
+	}
+
+	tests := map[string]struct {
+		cfg      ChunksCacheConfig
+		expected error
+	}{
+		"no backend should pass": {
+			cfg: ChunksCacheConfig{},
+		},
+		"unsupported backend should fail": {
+			cfg:      ChunksCacheConfig{BackendConfig: cache.BackendConfig{Backend: "xxx"}},
+			expected: fmt.Errorf("unsupported cache backend: xxx"),
+		},
+		"disk without directory should fail": {
+			cfg:      ChunksCacheConfig{BackendConfig: cache.BackendConfig{Backend: ChunksCacheBackendDisk}},
+			expected: diskcache.ErrMissingDir,
+		},
+		"disk with negative in-memory L1 max items should fail": {
+			cfg: func() ChunksCacheConfig {
+				cfg := ChunksCacheConfig{BackendConfig: cache.BackendConfig{Backend: ChunksCacheBackendDisk}, Disk: validDisk}
+				cfg.Disk.InMemoryL1MaxItems = -1
+				return cfg
+			}(),
+			expected: errInvalidChunksCacheInMemoryL1MaxItems,
+		},
+		"disk with directory should pass": {
+			cfg: ChunksCacheConfig{BackendConfig: cache.BackendConfig{Backend: ChunksCacheBackendDisk}, Disk: validDisk},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testData.expected, testData.cfg.Validate())
+		})
+	}
+}
+
+func TestNewChunksCacheClient_Disk(t *testing.T) {
+	for _, l1MaxItems := range []int{0, 10} {
+		t.Run(fmt.Sprintf("in-memory L1 max items: %d", l1MaxItems), func(t *testing.T) {
+			cfg := ChunksCacheConfig{}
+			cfg.RegisterFlagsWithPrefix(flag.NewFlagSet("", flag.PanicOnError), "")
+			cfg.Backend = ChunksCacheBackendDisk
+			cfg.Disk.Dir = t.TempDir()
+			cfg.Disk.InMemoryL1MaxItems = l1MaxItems
+			require.NoError(t, cfg.Validate())
+
+			c, s, err := NewChunksCacheClient(cfg, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+			require.NoError(t, err)
+			require.NotNil(t, s)
+			require.NoError(t, services.StartAndAwaitRunning(context.Background(), s))
+			t.Cleanup(func() {
+				require.NoError(t, services.StopAndAwaitTerminated(context.Background(), s))
+			})
+
+			c.StoreAsync(map[string][]byte{"C:user:block:0:1": {1}}, time.Hour)
+			test.Poll(t, time.Second, 1, func() interface{} {
+				return len(c.Fetch(context.Background(), []string{"C:user:block:0:1"}))
+			})
+		})
+	}
+}
+
+func TestChunksCacheItemType(t *testing.T) {
+	assert.Equal(t, "chunks", chunksCacheItemType("C:user:block:0:10"))
+	assert.Equal(t, "subrange", chunksCacheItemType("subrange:user/block/chunks/000001:0:16000"))
+	assert.Equal(t, "subrange", chunksCacheItemType("bucket:subrange:user/block/chunks/000001:0:16000"))
+	assert.Equal(t, "attrs", chunksCacheItemType("attrs:user/block/chunks/000001"))
+	assert.Equal(t, "other", chunksCacheItemType("unknown"))
+}
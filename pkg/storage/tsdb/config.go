@@ -120,6 +120,9 @@ var (
 	errEarlyCompactionRequiresActiveSeries          = fmt.Errorf("early compaction requires -%s to be enabled", activeseries.EnabledFlag)
 	errEmptyBlockranges                             = errors.New("empty block ranges for TSDB")
 	errInvalidIndexHeaderLazyLoadingConcurrency     = errors.New("invalid index-header lazy loading max concurrency; must be non-negative")
+	errInvalidIndexHeaderLazyLoadingMaxLoaded       = errors.New("invalid index-header lazy loading max loaded index-headers; must be non-negative")
+	errInvalidIndexHeaderLazyLoadingMaxLoadedBytes  = errors.New("invalid index-header lazy loading max loaded bytes; must be non-negative")
+	errInvalidIndexHeaderLazyLoadingWaitTimeout     = errors.New("invalid index-header lazy loading budget wait timeout; must be non-negative")
 )
 
 // BlocksStorageConfig holds the config information for the blocks storage.
@@ -252,6 +255,9 @@ type TSDBConfig struct {
 	EarlyHeadCompactionMinInMemorySeries                     int64 `yaml:"early_head_compaction_min_in_memory_series" category:"experimental"`
 	EarlyHeadCompactionMinEstimatedSeriesReductionPercentage int   `yaml:"early_head_compaction_min_estimated_series_reduction_percentage" category:"experimental"`
 
+	// ExemplarsShippingEnabled enables the upload of the exemplars of each block to the storage.
+	ExemplarsShippingEnabled bool `yaml:"exemplars_shipping_enabled" category:"experimental"`
+
 	// HeadCompactionIntervalJitterEnabled is enabled by default, but allows to disable it in tests.
 	HeadCompactionIntervalJitterEnabled bool `yaml:"-"`
 }
@@ -291,6 +297,7 @@ func (cfg *TSDBConfig) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.BlockPostingsForMatchersCacheForce, "blocks-storage.tsdb.block-postings-for-matchers-cache-force", false, "Force the cache to be used for postings for matchers in compacted blocks, even if it's not a concurrent (query-sharding) call.")
 	f.Int64Var(&cfg.EarlyHeadCompactionMinInMemorySeries, "blocks-storage.tsdb.early-head-compaction-min-in-memory-series", 0, fmt.Sprintf("When the number of in-memory series in the ingester is equal to or greater than this setting, the ingester tries to compact the TSDB Head. The early compaction removes from the memory all samples and inactive series up until -%s time ago. After an early compaction, the ingester will not accept any sample with a timestamp older than -%s time ago (unless out of order ingestion is enabled). The ingester checks every -%s whether an early compaction is required. Use 0 to disable it.", activeseries.IdleTimeoutFlag, activeseries.IdleTimeoutFlag, headCompactionIntervalFlag))
 	f.IntVar(&cfg.EarlyHeadCompactionMinEstimatedSeriesReductionPercentage, "blocks-storage.tsdb.early-head-compaction-min-estimated-series-reduction-percentage", 10, "When the early compaction is enabled, the early compaction is triggered only if the estimated series reduction is at least the configured percentage (0-100).")
+	f.BoolVar(&cfg.ExemplarsShippingEnabled, "blocks-storage.tsdb.exemplars-shipping-enabled", false, "True to upload to the storage, alongside each block, the exemplars stored in the TSDB head for the block time range. Exemplars are then queried from the store-gateways. Requires exemplars storage to be enabled.")
 
 	cfg.HeadCompactionIntervalJitterEnabled = true
 
@@ -394,6 +401,11 @@ type BucketStoreConfig struct {
 	// Maximum index-headers loaded into store-gateway concurrently
 	IndexHeaderLazyLoadingConcurrency int `yaml:"index_header_lazy_loading_concurrency" category:"experimental"`
 
+	// Maximum number and size of index-headers loaded into store-gateway at the same time.
+	IndexHeaderLazyLoadingMaxLoaded         int           `yaml:"index_header_lazy_loading_max_loaded" category:"experimental"`
+	IndexHeaderLazyLoadingMaxLoadedBytes    int64         `yaml:"index_header_lazy_loading_max_loaded_bytes" category:"experimental"`
+	IndexHeaderLazyLoadingBudgetWaitTimeout time.Duration `yaml:"index_header_lazy_loading_budget_wait_timeout" category:"experimental"`
+
 	// Controls the partitioner, used to aggregate multiple GET object API requests.
 	PartitionerMaxGapBytes uint64 `yaml:"partitioner_max_gap_bytes" category:"advanced"`
 
@@ -410,6 +422,7 @@ type BucketStoreConfig struct {
 	StreamingBatchSize          int    `yaml:"streaming_series_batch_size" category:"advanced"`
 	ChunkRangesPerSeries        int    `yaml:"fine_grained_chunks_caching_ranges_per_series" category:"experimental"`
 	SeriesSelectionStrategyName string `yaml:"series_selection_strategy" category:"experimental"`
+	LabelBloomFilterEnabled     bool   `yaml:"label_bloom_filter_enabled" category:"experimental"`
 	SelectionStrategies         struct {
 		WorstCaseSeriesPreference float64 `yaml:"worst_case_series_preference" category:"experimental"`
 	} `yaml:"series_selection_strategies"`
@@ -454,10 +467,14 @@ func (cfg *BucketStoreConfig) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.IndexHeaderLazyLoadingEnabled, "blocks-storage.bucket-store.index-header-lazy-loading-enabled", true, "If enabled, store-gateway will lazy load an index-header only once required by a query.")
 	f.DurationVar(&cfg.IndexHeaderLazyLoadingIdleTimeout, "blocks-storage.bucket-store.index-header-lazy-loading-idle-timeout", 60*time.Minute, "If index-header lazy loading is enabled and this setting is > 0, the store-gateway will offload unused index-headers after 'idle timeout' inactivity.")
 	f.IntVar(&cfg.IndexHeaderLazyLoadingConcurrency, "blocks-storage.bucket-store.index-header-lazy-loading-concurrency", 0, "Maximum number of concurrent index header loads across all tenants. If set to 0, concurrency is unlimited.")
+	f.IntVar(&cfg.IndexHeaderLazyLoadingMaxLoaded, "blocks-storage.bucket-store.index-header-lazy-loading-max-loaded", 0, "If index-header lazy loading is enabled and this setting is > 0, maximum number of index-headers loaded at the same time across all tenants. Once the limit is reached, the least recently used index-headers are unloaded to make room for new ones.")
+	f.Int64Var(&cfg.IndexHeaderLazyLoadingMaxLoadedBytes, "blocks-storage.bucket-store.index-header-lazy-loading-max-loaded-bytes", 0, "If index-header lazy loading is enabled and this setting is > 0, maximum size in bytes of the index-headers loaded at the same time across all tenants. The size of an index-header is estimated from its size on disk. Once the limit is reached, the least recently used index-headers are unloaded to make room for new ones.")
+	f.DurationVar(&cfg.IndexHeaderLazyLoadingBudgetWaitTimeout, "blocks-storage.bucket-store.index-header-lazy-loading-budget-wait-timeout", 0, "How long an index-header load waits for room when the max loaded index-headers or bytes limit is reached and all loaded index-headers are in use. If set to 0, the load fails immediately.")
 	f.Uint64Var(&cfg.PartitionerMaxGapBytes, "blocks-storage.bucket-store.partitioner-max-gap-bytes", DefaultPartitionerMaxGapSize, "Max size - in bytes - of a gap for which the partitioner aggregates together two bucket GET object requests.")
 	f.IntVar(&cfg.StreamingBatchSize, "blocks-storage.bucket-store.batch-series-size", 5000, "This option controls how many series to fetch per batch. The batch size must be greater than 0.")
 	f.IntVar(&cfg.ChunkRangesPerSeries, "blocks-storage.bucket-store.fine-grained-chunks-caching-ranges-per-series", 1, "This option controls into how many ranges the chunks of each series from each block are split. This value is effectively the number of chunks cache items per series per block when -blocks-storage.bucket-store.chunks-cache.fine-grained-chunks-caching-enabled is enabled.")
 	f.StringVar(&cfg.SeriesSelectionStrategyName, seriesSelectionStrategyFlag, WorstCasePostingsStrategy, "This option controls the strategy to selection of series and deferring application of matchers. A more aggressive strategy will fetch less posting lists at the cost of more series. This is useful when querying large blocks in which many series share the same label name and value. Supported values (most aggressive to least aggressive): "+strings.Join(validSeriesSelectionStrategies, ", ")+".")
+	f.BoolVar(&cfg.LabelBloomFilterEnabled, "blocks-storage.bucket-store.label-bloom-filter-enabled", false, "If enabled, the store-gateway loads the label bloom filter of each block, when available, the first time a request can be checked against it, and uses it to skip blocks that don't contain the label values requested by equality matchers. When index-header lazy loading is enabled, the filters idle for longer than the index-header lazy loading idle timeout are released from memory. The label bloom filters are built by the compactor when -compactor.label-bloom-filter-enabled is enabled.")
 	f.Float64Var(&cfg.SelectionStrategies.WorstCaseSeriesPreference, "blocks-storage.bucket-store.series-selection-strategies.worst-case-series-preference", 0.75, "This option is only used when "+seriesSelectionStrategyFlag+"="+WorstCasePostingsStrategy+". Increasing the series preference results in fetching more series than postings. Must be a positive floating point number.")
 }
 
@@ -496,6 +513,15 @@ func (cfg *BucketStoreConfig) Validate(logger log.Logger) error {
 	if cfg.IndexHeaderLazyLoadingConcurrency < 0 {
 		return errInvalidIndexHeaderLazyLoadingConcurrency
 	}
+	if cfg.IndexHeaderLazyLoadingMaxLoaded < 0 {
+		return errInvalidIndexHeaderLazyLoadingMaxLoaded
+	}
+	if cfg.IndexHeaderLazyLoadingMaxLoadedBytes < 0 {
+		return errInvalidIndexHeaderLazyLoadingMaxLoadedBytes
+	}
+	if cfg.IndexHeaderLazyLoadingBudgetWaitTimeout < 0 {
+		return errInvalidIndexHeaderLazyLoadingWaitTimeout
+	}
 	return nil
 }
 
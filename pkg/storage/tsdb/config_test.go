@@ -134,6 +134,18 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: errInvalidIndexHeaderLazyLoadingConcurrency,
 		},
+		"should fail on negative index-header lazy loading max loaded": {
+			setup: func(cfg *BlocksStorageConfig, activeSeriesCfg *activeseries.Config) {
+				cfg.BucketStore.IndexHeaderLazyLoadingMaxLoaded = -1
+			},
+			expectedErr: errInvalidIndexHeaderLazyLoadingMaxLoaded,
+		},
+		"should fail on negative index-header lazy loading max loaded bytes": {
+			setup: func(cfg *BlocksStorageConfig, activeSeriesCfg *activeseries.Config) {
+				cfg.BucketStore.IndexHeaderLazyLoadingMaxLoadedBytes = -1
+			},
+			expectedErr: errInvalidIndexHeaderLazyLoadingMaxLoadedBytes,
+		},
 		"should fail if forced compaction is enabled but active series tracker is not": {
 			setup: func(cfg *BlocksStorageConfig, activeSeriesCfg *activeseries.Config) {
 				cfg.TSDB.EarlyHeadCompactionMinInMemorySeries = 1_000_000
@@ -14,9 +14,11 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/cache"
 	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/grafana/mimir/pkg/storegateway/diskcache"
 	"github.com/grafana/mimir/pkg/storegateway/indexcache"
 	"github.com/grafana/mimir/pkg/util"
 )
@@ -31,6 +33,9 @@ const (
 	// IndexCacheBackendRedis is the value for the Redis index cache backend.
 	IndexCacheBackendRedis = cache.BackendRedis
 
+	// IndexCacheBackendDisk is the value for the disk index cache backend.
+	IndexCacheBackendDisk = "disk"
+
 	// IndexCacheBackendDefault is the value for the default index cache backend.
 	IndexCacheBackendDefault = IndexCacheBackendInMemory
 
@@ -38,7 +43,7 @@ const (
 )
 
 var (
-	supportedIndexCacheBackends = []string{IndexCacheBackendInMemory, IndexCacheBackendMemcached, IndexCacheBackendRedis}
+	supportedIndexCacheBackends = []string{IndexCacheBackendInMemory, IndexCacheBackendMemcached, IndexCacheBackendRedis, IndexCacheBackendDisk}
 
 	errUnsupportedIndexCacheBackend = errors.New("unsupported index cache backend")
 )
@@ -46,6 +51,7 @@ var (
 type IndexCacheConfig struct {
 	cache.BackendConfig `yaml:",inline"`
 	InMemory            InMemoryIndexCacheConfig `yaml:"inmemory"`
+	Disk                DiskIndexCacheConfig     `yaml:"disk"`
 }
 
 func (cfg *IndexCacheConfig) RegisterFlags(f *flag.FlagSet) {
@@ -58,6 +64,7 @@ func (cfg *IndexCacheConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix str
 	cfg.InMemory.RegisterFlagsWithPrefix(prefix+"inmemory.", f)
 	cfg.Memcached.RegisterFlagsWithPrefix(prefix+"memcached.", f)
 	cfg.Redis.RegisterFlagsWithPrefix(prefix+"redis.", f)
+	cfg.Disk.RegisterFlagsWithPrefix(prefix+"disk.", f)
 }
 
 // Validate the config.
@@ -73,6 +80,12 @@ func (cfg *IndexCacheConfig) Validate() error {
 		}
 	}
 
+	if cfg.Backend == IndexCacheBackendDisk {
+		if err := cfg.Disk.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -84,18 +97,39 @@ func (cfg *InMemoryIndexCacheConfig) RegisterFlagsWithPrefix(prefix string, f *f
 	f.Uint64Var(&cfg.MaxSizeBytes, prefix+"max-size-bytes", uint64(1*units.Gibibyte), "Maximum size in bytes of in-memory index cache used to speed up blocks index lookups (shared between all tenants).")
 }
 
-// NewIndexCache creates a new index cache based on the input configuration.
-func NewIndexCache(cfg IndexCacheConfig, logger log.Logger, registerer prometheus.Registerer) (indexcache.IndexCache, error) {
+type DiskIndexCacheConfig struct {
+	diskcache.Config `yaml:",inline"`
+
+	InMemoryL1Enabled bool `yaml:"in_memory_l1_enabled" category:"experimental"`
+}
+
+func (cfg *DiskIndexCacheConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.Config.RegisterFlagsWithPrefix(f, prefix)
+
+	f.BoolVar(&cfg.InMemoryL1Enabled, prefix+"in-memory-l1-enabled", false, "If enabled, items are looked up in an in-memory cache, configured through the in-memory index cache flags, before looking them up on disk.")
+}
+
+// NewIndexCache creates a new index cache based on the input configuration. The returned service, if not nil,
+// releases the resources of the index cache once stopped, and must be started and stopped by the caller.
+func NewIndexCache(cfg IndexCacheConfig, logger log.Logger, registerer prometheus.Registerer) (indexcache.IndexCache, services.Service, error) {
+	var (
+		c   indexcache.IndexCache
+		err error
+	)
+
 	switch cfg.Backend {
 	case IndexCacheBackendInMemory:
-		return newInMemoryIndexCache(cfg.InMemory, logger, registerer)
+		c, err = newInMemoryIndexCache(cfg.InMemory, logger, registerer)
 	case IndexCacheBackendMemcached:
-		return newMemcachedIndexCache(cfg.Memcached, logger, registerer)
+		c, err = newMemcachedIndexCache(cfg.Memcached, logger, registerer)
 	case IndexCacheBackendRedis:
-		return newRedisIndexCache(cfg.Redis, logger, registerer)
+		c, err = newRedisIndexCache(cfg.Redis, logger, registerer)
+	case IndexCacheBackendDisk:
+		return newDiskIndexCache(cfg.Disk, cfg.InMemory, logger, registerer)
 	default:
-		return nil, errUnsupportedIndexCacheBackend
+		err = errUnsupportedIndexCacheBackend
 	}
+	return c, nil, err
 }
 
 func newInMemoryIndexCache(cfg InMemoryIndexCacheConfig, logger log.Logger, registerer prometheus.Registerer) (indexcache.IndexCache, error) {
@@ -140,3 +174,42 @@ func newRedisIndexCache(cfg cache.RedisClientConfig, logger log.Logger, register
 
 	return indexcache.NewTracingIndexCache(c, logger), nil
 }
+
+func newDiskIndexCache(cfg DiskIndexCacheConfig, inMemoryCfg InMemoryIndexCacheConfig, logger log.Logger, registerer prometheus.Registerer) (indexcache.IndexCache, services.Service, error) {
+	client, err := diskcache.NewCache("index-cache", cfg.Config, indexcache.CacheTypeFromKey, logger, registerer)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "create index cache disk client")
+	}
+
+	// Stop the disk cache asynchronous writes once the index cache is no longer used.
+	stopper := services.NewIdleService(nil, func(error) error {
+		client.Stop()
+		return nil
+	})
+
+	if !cfg.InMemoryL1Enabled {
+		c, err := indexcache.NewRemoteIndexCache(logger, client, registerer)
+		if err != nil {
+			client.Stop()
+			return nil, nil, errors.Wrap(err, "create disk-based index cache")
+		}
+
+		return indexcache.NewTracingIndexCache(c, logger), stopper, nil
+	}
+
+	// The in-memory cache is looked up first, so the index cache metrics track the in-memory cache,
+	// while the disk cache is tracked by its own metrics.
+	l1, err := newInMemoryIndexCache(inMemoryCfg, logger, registerer)
+	if err != nil {
+		client.Stop()
+		return nil, nil, errors.Wrap(err, "create in-memory index cache")
+	}
+
+	l2, err := indexcache.NewRemoteIndexCache(logger, client, nil)
+	if err != nil {
+		client.Stop()
+		return nil, nil, errors.Wrap(err, "create disk-based index cache")
+	}
+
+	return indexcache.NewTracingIndexCache(indexcache.NewMultiLevelIndexCache(l1, l2), logger), stopper, nil
+}
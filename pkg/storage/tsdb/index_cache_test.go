@@ -6,11 +6,23 @@
 package tsdb
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/go-kit/log"
 	"github.com/grafana/dskit/cache"
 	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storegateway/diskcache"
 )
 
 func TestIndexCacheConfig_Validate(t *testing.T) {
@@ -71,6 +83,24 @@ func TestIndexCacheConfig_Validate(t *testing.T) {
 				},
 			},
 		},
+		"disk without directory should fail": {
+			cfg: IndexCacheConfig{
+				BackendConfig: cache.BackendConfig{
+					Backend: IndexCacheBackendDisk,
+				},
+			},
+			expected: diskcache.ErrMissingDir,
+		},
+		"disk with directory should pass": {
+			cfg: IndexCacheConfig{
+				BackendConfig: cache.BackendConfig{
+					Backend: IndexCacheBackendDisk,
+				},
+				Disk: DiskIndexCacheConfig{
+					Config: diskcache.Config{Dir: "/tmp/index-cache", MaxSizeBytes: 1, MaxAsyncConcurrency: 1, MaxAsyncBufferSize: 1},
+				},
+			},
+		},
 		"inmemory should pass": {
 			cfg: IndexCacheConfig{
 				BackendConfig: cache.BackendConfig{
@@ -86,3 +116,38 @@ func TestIndexCacheConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewIndexCache_Disk(t *testing.T) {
+	for _, l1Enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("in-memory L1 enabled: %t", l1Enabled), func(t *testing.T) {
+			cfg := IndexCacheConfig{}
+			flagext.DefaultValues(&cfg)
+			cfg.Backend = IndexCacheBackendDisk
+			cfg.Disk.Dir = t.TempDir()
+			cfg.Disk.InMemoryL1Enabled = l1Enabled
+			require.NoError(t, cfg.Validate())
+
+			reg := prometheus.NewPedanticRegistry()
+			c, s, err := NewIndexCache(cfg, log.NewNopLogger(), reg)
+			require.NoError(t, err)
+			require.NotNil(t, s)
+			require.NoError(t, services.StartAndAwaitRunning(context.Background(), s))
+			t.Cleanup(func() {
+				require.NoError(t, services.StopAndAwaitTerminated(context.Background(), s))
+			})
+
+			ctx := context.Background()
+			blockID := ulid.MustNew(1, nil)
+			c.StoreSeriesForRef("user", blockID, 1, []byte{1})
+
+			// Items are asynchronously written to disk.
+			test.Poll(t, time.Second, 1, func() interface{} {
+				hits, _ := c.FetchMultiSeriesForRefs(ctx, "user", blockID, []storage.SeriesRef{1})
+				return len(hits)
+			})
+
+			_, err = reg.Gather()
+			require.NoError(t, err)
+		})
+	}
+}
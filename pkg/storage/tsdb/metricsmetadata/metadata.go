@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package metricsmetadata
+
+import (
+	"path"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+)
+
+const (
+	// MetadataDir is the directory, within the tenant bucket, where metric metadata is stored.
+	MetadataDir = "metadata"
+
+	// IngestersDir is the directory, within MetadataDir, where each ingester uploads its own snapshot.
+	IngestersDir = "ingesters"
+
+	MetadataFilename           = "metadata.json"
+	MetadataCompressedFilename = MetadataFilename + ".gz"
+	MetadataVersion1           = 1
+)
+
+// MergedMetadataPath is the path, within the tenant bucket, of the metric metadata merged by the compactor.
+var MergedMetadataPath = path.Join(MetadataDir, MetadataCompressedFilename)
+
+// IngesterMetadataPath returns the path, within the tenant bucket, of the metric metadata snapshot
+// uploaded by the input ingester.
+func IngesterMetadataPath(instanceID string) string {
+	return path.Join(MetadataDir, IngestersDir, instanceID+".json.gz")
+}
+
+// Metadata holds the metric metadata of a tenant.
+type Metadata struct {
+	// Version of the metadata format.
+	Version int `json:"version"`
+
+	// Metrics is the list of metric metadata entries, sorted by metric name.
+	Metrics []Entry `json:"metrics"`
+
+	// UpdatedAt is a unix timestamp (seconds precision) of when the metadata has been updated
+	// (written in the storage) the last time.
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+func (m *Metadata) GetUpdatedAt() time.Time {
+	return time.Unix(m.UpdatedAt, 0)
+}
+
+// Entry holds the metadata of a metric. A metric can have multiple entries, one for each
+// distinct metadata it has been seen with.
+type Entry struct {
+	Metric string               `json:"metric"`
+	Type   textparse.MetricType `json:"type"`
+	Help   string               `json:"help,omitempty"`
+	Unit   string               `json:"unit,omitempty"`
+
+	// LastSeen is a unix timestamp (seconds precision) of when the metadata has been seen the last time.
+	LastSeen int64 `json:"last_seen"`
+}
+
+func (e Entry) GetLastSeen() time.Time {
+	return time.Unix(e.LastSeen, 0)
+}
+
+// ToScrapeMetadata returns the entry as scrape.MetricMetadata.
+func (e Entry) ToScrapeMetadata() scrape.MetricMetadata {
+	return scrape.MetricMetadata{
+		Metric: e.Metric,
+		Type:   e.Type,
+		Help:   e.Help,
+		Unit:   e.Unit,
+	}
+}
+
+// Merge merges the entries of the input metadata, keeping the most recent last seen timestamp
+// for entries with the same metadata. The returned entries are sorted.
+func Merge(metadata ...*Metadata) []Entry {
+	lastSeen := map[scrape.MetricMetadata]int64{}
+	for _, m := range metadata {
+		if m == nil {
+			continue
+		}
+
+		for _, e := range m.Metrics {
+			key := e.ToScrapeMetadata()
+			if ts, ok := lastSeen[key]; !ok || e.LastSeen > ts {
+				lastSeen[key] = e.LastSeen
+			}
+		}
+	}
+
+	entries := make([]Entry, 0, len(lastSeen))
+	for m, ts := range lastSeen {
+		entries = append(entries, Entry{Metric: m.Metric, Type: m.Type, Help: m.Help, Unit: m.Unit, LastSeen: ts})
+	}
+	SortEntries(entries)
+	return entries
+}
+
+// RemoveEntriesLastSeenBefore returns the input entries without the ones last seen before the cutoff.
+// The input slice is modified in place.
+func RemoveEntriesLastSeenBefore(entries []Entry, cutoff time.Time) []Entry {
+	kept := entries[:0]
+	for _, e := range entries {
+		if !e.GetLastSeen().Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// SortEntries sorts the input entries by metric name, type, help and unit.
+func SortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Help != b.Help {
+			return a.Help < b.Help
+		}
+		return a.Unit < b.Unit
+	})
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package metricsmetadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	tests := map[string]struct {
+		input    []*Metadata
+		expected []Entry
+	}{
+		"no metadata": {
+			input:    nil,
+			expected: []Entry{},
+		},
+		"nil metadata": {
+			input:    []*Metadata{nil},
+			expected: []Entry{},
+		},
+		"should keep the most recent last seen of the same metadata": {
+			input: []*Metadata{
+				{Metrics: []Entry{
+					{Metric: "b", Type: textparse.MetricTypeGauge, Help: "help", LastSeen: 20},
+					{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help", LastSeen: 10},
+				}},
+				{Metrics: []Entry{
+					{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help", LastSeen: 30},
+				}},
+			},
+			expected: []Entry{
+				{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help", LastSeen: 30},
+				{Metric: "b", Type: textparse.MetricTypeGauge, Help: "help", LastSeen: 20},
+			},
+		},
+		"should keep distinct metadata of the same metric": {
+			input: []*Metadata{
+				{Metrics: []Entry{
+					{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help 2", LastSeen: 10},
+				}},
+				{Metrics: []Entry{
+					{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help 1", Unit: "seconds", LastSeen: 20},
+				}},
+			},
+			expected: []Entry{
+				{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help 1", Unit: "seconds", LastSeen: 20},
+				{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help 2", LastSeen: 10},
+			},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testData.expected, Merge(testData.input...))
+		})
+	}
+}
+
+func TestRemoveEntriesLastSeenBefore(t *testing.T) {
+	entries := []Entry{
+		{Metric: "a", LastSeen: 10},
+		{Metric: "b", LastSeen: 20},
+		{Metric: "c", LastSeen: 30},
+	}
+
+	assert.Equal(t, []Entry{
+		{Metric: "b", LastSeen: 20},
+		{Metric: "c", LastSeen: 30},
+	}, RemoveEntriesLastSeenBefore(entries, time.Unix(20, 0)))
+}
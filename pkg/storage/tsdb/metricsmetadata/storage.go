@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package metricsmetadata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/runutil"
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+)
+
+var (
+	ErrMetadataNotFound  = errors.New("metric metadata not found")
+	ErrMetadataCorrupted = errors.New("metric metadata corrupted")
+)
+
+// ReadMergedMetadata reads, parses and returns the metric metadata merged by the compactor.
+func ReadMergedMetadata(ctx context.Context, bkt objstore.Bucket, userID string, cfgProvider bucket.TenantConfigProvider, logger log.Logger) (*Metadata, error) {
+	return readMetadata(ctx, bucket.NewUserBucketClient(userID, bkt, cfgProvider), MergedMetadataPath, logger)
+}
+
+// WriteMergedMetadata uploads the provided merged metric metadata to the storage.
+func WriteMergedMetadata(ctx context.Context, bkt objstore.Bucket, userID string, cfgProvider bucket.TenantConfigProvider, m *Metadata) error {
+	return writeMetadata(ctx, bucket.NewUserBucketClient(userID, bkt, cfgProvider), MergedMetadataPath, m)
+}
+
+// WriteIngesterMetadata uploads the provided metric metadata snapshot of an ingester to the storage.
+func WriteIngesterMetadata(ctx context.Context, bkt objstore.Bucket, userID string, cfgProvider bucket.TenantConfigProvider, instanceID string, m *Metadata) error {
+	return writeMetadata(ctx, bucket.NewUserBucketClient(userID, bkt, cfgProvider), IngesterMetadataPath(instanceID), m)
+}
+
+// readMetadata reads the metric metadata at the input path. It has a one-minute timeout for completing
+// the read against the bucket, like the bucket index.
+func readMetadata(ctx context.Context, bkt objstore.InstrumentedBucket, name string, logger log.Logger) (*Metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	reader, err := bkt.WithExpectedErrs(bkt.IsObjNotFoundErr).Get(ctx, name)
+	if err != nil {
+		if bkt.IsObjNotFoundErr(err) {
+			return nil, ErrMetadataNotFound
+		}
+		return nil, errors.Wrap(err, "read metric metadata")
+	}
+	defer runutil.CloseWithLogOnErr(logger, reader, "close metric metadata reader")
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, ErrMetadataCorrupted
+	}
+	defer runutil.CloseWithLogOnErr(logger, gzipReader, "close metric metadata gzip reader")
+
+	m := &Metadata{}
+	if err := json.NewDecoder(gzipReader).Decode(m); err != nil {
+		return nil, ErrMetadataCorrupted
+	}
+
+	return m, nil
+}
+
+func writeMetadata(ctx context.Context, bkt objstore.Bucket, name string, m *Metadata) error {
+	content, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "marshal metric metadata")
+	}
+
+	var gzipContent bytes.Buffer
+	gzip := gzip.NewWriter(&gzipContent)
+	gzip.Name = MetadataFilename
+
+	if _, err := gzip.Write(content); err != nil {
+		return errors.Wrap(err, "gzip metric metadata")
+	}
+	if err := gzip.Close(); err != nil {
+		return errors.Wrap(err, "close gzip metric metadata")
+	}
+
+	if err := bkt.Upload(ctx, name, &gzipContent); err != nil {
+		return errors.Wrap(err, "upload metric metadata")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package metricsmetadata
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
+)
+
+func TestReadMergedMetadata(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	bkt, _ := mimir_testutil.PrepareFilesystemBucket(t)
+
+	t.Run("should return error if metadata does not exist", func(t *testing.T) {
+		m, err := ReadMergedMetadata(ctx, bkt, userID, nil, logger)
+		require.Equal(t, ErrMetadataNotFound, err)
+		require.Nil(t, m)
+	})
+
+	t.Run("should return error if metadata is corrupted", func(t *testing.T) {
+		require.NoError(t, bkt.Upload(ctx, path.Join(userID, MergedMetadataPath), strings.NewReader("invalid!}")))
+
+		m, err := ReadMergedMetadata(ctx, bkt, userID, nil, logger)
+		require.Equal(t, ErrMetadataCorrupted, err)
+		require.Nil(t, m)
+	})
+
+	t.Run("should return the parsed metadata on success", func(t *testing.T) {
+		expected := &Metadata{
+			Version:   MetadataVersion1,
+			Metrics:   []Entry{{Metric: "a", Type: textparse.MetricTypeCounter, Help: "help", Unit: "seconds", LastSeen: 10}},
+			UpdatedAt: 20,
+		}
+		require.NoError(t, WriteMergedMetadata(ctx, bkt, userID, nil, expected))
+
+		m, err := ReadMergedMetadata(ctx, bkt, userID, nil, logger)
+		require.NoError(t, err)
+		assert.Equal(t, expected, m)
+	})
+}
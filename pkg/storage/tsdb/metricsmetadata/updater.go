@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package metricsmetadata
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+)
+
+// Updater merges the metric metadata snapshots uploaded by the ingesters into the tenant merged metadata.
+type Updater struct {
+	bkt    objstore.InstrumentedBucket
+	logger log.Logger
+}
+
+func NewUpdater(bkt objstore.Bucket, userID string, cfgProvider bucket.TenantConfigProvider, logger log.Logger) *Updater {
+	return &Updater{
+		bkt:    bucket.NewUserBucketClient(userID, bkt, cfgProvider),
+		logger: logger,
+	}
+}
+
+// UpdateMetadata merges the existing merged metadata with the snapshots uploaded by the ingesters, and
+// removes the entries not seen within the retention period. Ingester snapshots not updated within the
+// retention period are deleted from the storage. The returned metadata is not written to the storage,
+// and is nil if there's no metadata at all for the tenant.
+func (w *Updater) UpdateMetadata(ctx context.Context, retention time.Duration) (*Metadata, error) {
+	cutoff := time.Now().Add(-retention)
+
+	merged, err := readMetadata(ctx, w.bkt, MergedMetadataPath, w.logger)
+	found := err == nil
+	if errors.Is(err, ErrMetadataCorrupted) {
+		level.Warn(w.logger).Log("msg", "found corrupted merged metric metadata, recreating it")
+		found = true
+	} else if err != nil && !errors.Is(err, ErrMetadataNotFound) {
+		return nil, err
+	}
+
+	all := []*Metadata{merged}
+	err = w.bkt.Iter(ctx, path.Join(MetadataDir, IngestersDir), func(name string) error {
+		if !strings.HasSuffix(name, ".json.gz") {
+			return nil
+		}
+
+		m, err := readMetadata(ctx, w.bkt, name, w.logger)
+		if errors.Is(err, ErrMetadataNotFound) {
+			// The snapshot has been deleted in the meanwhile.
+			return nil
+		}
+		if errors.Is(err, ErrMetadataCorrupted) {
+			level.Warn(w.logger).Log("msg", "skipped corrupted ingester metric metadata", "file", name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if m.GetUpdatedAt().Before(cutoff) {
+			// The ingester hasn't uploaded its snapshot within the retention period, so all its
+			// entries would be removed anyway.
+			if err := w.bkt.Delete(ctx, name); err != nil && !w.bkt.IsObjNotFoundErr(err) {
+				level.Warn(w.logger).Log("msg", "failed to delete stale ingester metric metadata", "file", name, "err", err)
+			} else {
+				level.Info(w.logger).Log("msg", "deleted stale ingester metric metadata", "file", name)
+			}
+			return nil
+		}
+
+		all = append(all, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !found && len(all) == 1 {
+		return nil, nil
+	}
+
+	return &Metadata{
+		Version:   MetadataVersion1,
+		Metrics:   RemoveEntriesLastSeenBefore(Merge(all...), cutoff),
+		UpdatedAt: time.Now().Unix(),
+	}, nil
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package metricsmetadata
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
+)
+
+func TestUpdater_UpdateMetadata(t *testing.T) {
+	const (
+		userID    = "user-1"
+		retention = time.Hour
+	)
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	now := time.Now()
+
+	t.Run("should return nil if there's no metadata", func(t *testing.T) {
+		bkt, _ := mimir_testutil.PrepareFilesystemBucket(t)
+
+		m, err := NewUpdater(bkt, userID, nil, logger).UpdateMetadata(ctx, retention)
+		require.NoError(t, err)
+		assert.Nil(t, m)
+	})
+
+	t.Run("should merge ingesters metadata into the merged metadata and apply the retention", func(t *testing.T) {
+		bkt, _ := mimir_testutil.PrepareFilesystemBucket(t)
+
+		recent := now.Add(-time.Minute).Unix()
+		expired := now.Add(-2 * retention).Unix()
+
+		require.NoError(t, WriteMergedMetadata(ctx, bkt, userID, nil, &Metadata{
+			Version: MetadataVersion1,
+			Metrics: []Entry{
+				{Metric: "expired", Type: textparse.MetricTypeGauge, LastSeen: expired},
+				{Metric: "merged", Type: textparse.MetricTypeGauge, LastSeen: recent},
+				{Metric: "shared", Type: textparse.MetricTypeCounter, LastSeen: expired},
+			},
+			UpdatedAt: recent,
+		}))
+		require.NoError(t, WriteIngesterMetadata(ctx, bkt, userID, nil, "ingester-1", &Metadata{
+			Version: MetadataVersion1,
+			Metrics: []Entry{
+				{Metric: "ingester_1", Type: textparse.MetricTypeCounter, LastSeen: recent},
+				{Metric: "shared", Type: textparse.MetricTypeCounter, LastSeen: recent},
+			},
+			UpdatedAt: recent,
+		}))
+		// The snapshot of an ingester which hasn't uploaded it within the retention period.
+		require.NoError(t, WriteIngesterMetadata(ctx, bkt, userID, nil, "ingester-2", &Metadata{
+			Version:   MetadataVersion1,
+			Metrics:   []Entry{{Metric: "ingester_2", Type: textparse.MetricTypeCounter, LastSeen: expired}},
+			UpdatedAt: expired,
+		}))
+
+		m, err := NewUpdater(bkt, userID, nil, logger).UpdateMetadata(ctx, retention)
+		require.NoError(t, err)
+		require.NotNil(t, m)
+		assert.Equal(t, MetadataVersion1, m.Version)
+		assert.Equal(t, []Entry{
+			{Metric: "ingester_1", Type: textparse.MetricTypeCounter, LastSeen: recent},
+			{Metric: "merged", Type: textparse.MetricTypeGauge, LastSeen: recent},
+			{Metric: "shared", Type: textparse.MetricTypeCounter, LastSeen: recent},
+		}, m.Metrics)
+
+		// Only the stale ingester snapshot should have been deleted.
+		exists, err := bkt.Exists(ctx, path.Join(userID, IngesterMetadataPath("ingester-1")))
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = bkt.Exists(ctx, path.Join(userID, IngesterMetadataPath("ingester-2")))
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("should recreate corrupted merged metadata", func(t *testing.T) {
+		bkt, _ := mimir_testutil.PrepareFilesystemBucket(t)
+
+		recent := now.Add(-time.Minute).Unix()
+		require.NoError(t, bkt.Upload(ctx, path.Join(userID, MergedMetadataPath), strings.NewReader("invalid!}")))
+		require.NoError(t, WriteIngesterMetadata(ctx, bkt, userID, nil, "ingester-1", &Metadata{
+			Version:   MetadataVersion1,
+			Metrics:   []Entry{{Metric: "ingester_1", Type: textparse.MetricTypeCounter, LastSeen: recent}},
+			UpdatedAt: recent,
+		}))
+
+		m, err := NewUpdater(bkt, userID, nil, logger).UpdateMetadata(ctx, retention)
+		require.NoError(t, err)
+		require.NotNil(t, m)
+		assert.Equal(t, []Entry{{Metric: "ingester_1", Type: textparse.MetricTypeCounter, LastSeen: recent}}, m.Metrics)
+	})
+}
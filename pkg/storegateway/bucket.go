@@ -116,12 +116,22 @@ type BucketStore struct {
 	// or rely on the transparent caching bucket.
 	fineGrainedChunksCachingEnabled bool
 
+	// labelBloomFilterEnabled controls whether to load the blocks label bloom filter,
+	// and use it to skip blocks which don't contain the requested label values.
+	labelBloomFilterEnabled bool
+
+	// closing is closed once the store is closed, to stop the background tasks.
+	closing chan struct{}
+
 	// Query gate which limits the maximum amount of concurrent queries.
 	queryGate gate.Gate
 
 	// Gate used to limit concurrency on loading index-headers across all tenants.
 	lazyLoadingGate gate.Gate
 
+	// Budget used to limit the number and size of loaded index-headers. Optional.
+	indexHeaderLoadedBudget *indexheader.LoadedBudget
+
 	// chunksLimiterFactory creates a new limiter used to limit the number of chunks fetched by each Series() call.
 	chunksLimiterFactory ChunksLimiterFactory
 	// seriesLimiterFactory creates a new limiter used to limit the number of touched series by each Series() call,
@@ -214,12 +224,25 @@ func WithLazyLoadingGate(lazyLoadingGate gate.Gate) BucketStoreOption {
 	}
 }
 
+// WithIndexHeaderLoadedBudget sets a budget to limit the number and size of loaded index-headers.
+func WithIndexHeaderLoadedBudget(budget *indexheader.LoadedBudget) BucketStoreOption {
+	return func(s *BucketStore) {
+		s.indexHeaderLoadedBudget = budget
+	}
+}
+
 func WithFineGrainedChunksCaching(enabled bool) BucketStoreOption {
 	return func(s *BucketStore) {
 		s.fineGrainedChunksCachingEnabled = enabled
 	}
 }
 
+func WithLabelBloomFilter(enabled bool) BucketStoreOption {
+	return func(s *BucketStore) {
+		s.labelBloomFilterEnabled = enabled
+	}
+}
+
 // NewBucketStore creates a new bucket backed store that implements the store API against
 // an object store bucket. It is optimized to work against high latency backends.
 func NewBucketStore(
@@ -265,6 +288,7 @@ func NewBucketStore(
 		maxSeriesPerBatch:           maxSeriesPerBatch,
 		numChunksRangesPerSeries:    numChunksRangesPerSeries,
 		postingsStrategy:            postingsStrategy,
+		closing:                     make(chan struct{}),
 	}
 
 	for _, option := range options {
@@ -272,7 +296,12 @@ func NewBucketStore(
 	}
 
 	// Depend on the options
-	s.indexReaderPool = indexheader.NewReaderPool(s.logger, lazyIndexReaderEnabled, lazyIndexReaderIdleTimeout, s.lazyLoadingGate, metrics.indexHeaderReaderMetrics)
+	s.indexReaderPool = indexheader.NewReaderPool(s.logger, lazyIndexReaderEnabled, lazyIndexReaderIdleTimeout, s.lazyLoadingGate, s.indexHeaderLoadedBudget, metrics.indexHeaderReaderMetrics)
+
+	// The label bloom filters are released when idle the same way the lazy loaded index-headers are.
+	if s.labelBloomFilterEnabled && lazyIndexReaderEnabled && lazyIndexReaderIdleTimeout > 0 {
+		go s.releaseIdleLabelBloomFiltersLoop(lazyIndexReaderIdleTimeout)
+	}
 
 	if err := os.MkdirAll(dir, 0750); err != nil {
 		return nil, errors.Wrap(err, "create dir")
@@ -287,6 +316,7 @@ func (s *BucketStore) RemoveBlocksAndClose() error {
 
 	// Release other resources even if it failed to close some blocks.
 	s.indexReaderPool.Close()
+	close(s.closing)
 
 	return err
 }
@@ -448,6 +478,10 @@ func (s *BucketStore) addBlock(ctx context.Context, meta *block.Meta) (err error
 	if err != nil {
 		return errors.Wrap(err, "new bucket block")
 	}
+
+	if s.labelBloomFilterEnabled {
+		b.labelBloomFilter = newLazyLabelBloomFilter(b)
+	}
 	defer func() {
 		if err != nil {
 			runutil.CloseWithErrCapture(&err, b, "index-header")
@@ -595,7 +629,7 @@ func (s *BucketStore) Series(req *storepb.SeriesRequest, srv storepb.Store_Serie
 
 	logSeriesRequestToSpan(srv.Context(), s.logger, req.MinTime, req.MaxTime, matchers, reqBlockMatchers, shardSelector, req.StreamingChunksBatchSize)
 
-	blocks, indexReaders, chunkReaders := s.openBlocksForReading(ctx, req.SkipChunks, req.MinTime, req.MaxTime, reqBlockMatchers, stats)
+	blocks, skippedBlocks, indexReaders, chunkReaders := s.openBlocksForReading(ctx, req.SkipChunks, req.MinTime, req.MaxTime, reqBlockMatchers, matchers, stats)
 	// We must keep the readers open until all their data has been sent.
 	for _, r := range indexReaders {
 		defer runutil.CloseWithLogOnErr(s.logger, r, "close block index reader")
@@ -628,6 +662,11 @@ func (s *BucketStore) Series(req *storepb.SeriesRequest, srv storepb.Store_Serie
 	for _, b := range blocks {
 		resHints.AddQueriedBlock(b.meta.ULID)
 	}
+	// Blocks skipped because of their label bloom filter have been queried too, given they
+	// are guaranteed to not contain any matching series.
+	for _, b := range skippedBlocks {
+		resHints.AddQueriedBlock(b.meta.ULID)
+	}
 	if err := s.sendHints(srv, resHints); err != nil {
 		return err
 	}
@@ -1250,7 +1289,10 @@ func (s *BucketStore) recordSeriesHashCacheStats(stats *queryStats) {
 	s.metrics.seriesHashCacheHits.Add(float64(stats.seriesHashCacheHits))
 }
 
-func (s *BucketStore) openBlocksForReading(ctx context.Context, skipChunks bool, minT, maxT int64, blockMatchers []*labels.Matcher, stats *safeQueryStats) ([]*bucketBlock, map[ulid.ULID]*bucketIndexReader, map[ulid.ULID]chunkReader) {
+// openBlocksForReading opens the blocks matching the request for reading. Blocks whose label bloom filter
+// guarantees they don't contain any series matching the request matchers are not opened, but returned
+// as skipped blocks.
+func (s *BucketStore) openBlocksForReading(ctx context.Context, skipChunks bool, minT, maxT int64, blockMatchers, matchers []*labels.Matcher, stats *safeQueryStats) ([]*bucketBlock, []*bucketBlock, map[ulid.ULID]*bucketIndexReader, map[ulid.ULID]chunkReader) {
 	// ignore the span context so that we can use the context for cancellation
 	span, _ := tracing.StartSpan(ctx, "bucket_store_open_blocks_for_reading")
 	defer span.Finish()
@@ -1259,14 +1301,14 @@ func (s *BucketStore) openBlocksForReading(ctx context.Context, skipChunks bool,
 	defer s.blocksMx.RUnlock()
 
 	// Find all blocks owned by this store-gateway instance and matching the request.
-	blocks := s.blockSet.getFor(minT, maxT, blockMatchers)
+	blocks, skipped := s.filterBlocksByLabelBloomFilter(ctx, s.blockSet.getFor(minT, maxT, blockMatchers), matchers)
 
 	indexReaders := make(map[ulid.ULID]*bucketIndexReader, len(blocks))
 	for _, b := range blocks {
 		indexReaders[b.meta.ULID] = b.loadedIndexReader(s.postingsStrategy, stats)
 	}
 	if skipChunks {
-		return blocks, indexReaders, nil
+		return blocks, skipped, indexReaders, nil
 	}
 
 	chunkReaders := make(map[ulid.ULID]chunkReader, len(blocks))
@@ -1274,7 +1316,7 @@ func (s *BucketStore) openBlocksForReading(ctx context.Context, skipChunks bool,
 		chunkReaders[b.meta.ULID] = b.chunkReader(ctx)
 	}
 
-	return blocks, indexReaders, chunkReaders
+	return blocks, skipped, indexReaders, chunkReaders
 }
 
 // LabelNames implements the storepb.StoreServer interface.
@@ -1329,6 +1371,12 @@ func (s *BucketStore) LabelNames(ctx context.Context, req *storepb.LabelNamesReq
 		g.Go(func() error {
 			defer runutil.CloseWithLogOnErr(s.logger, indexr, "label names")
 
+			// The label bloom filter is checked (and loaded, if not loaded yet) concurrently for each block.
+			if !b.mayContainSeriesMatching(gctx, reqSeriesMatchers) {
+				s.metrics.labelBloomFilterSkippedBlocks.Inc()
+				return nil
+			}
+
 			result, err := blockLabelNames(gctx, indexr, reqSeriesMatchers, seriesLimiter, s.maxSeriesPerBatch, s.logger, stats)
 			if err != nil {
 				return errors.Wrapf(err, "block %s", b.meta.ULID)
@@ -1514,6 +1562,12 @@ func (s *BucketStore) LabelValues(ctx context.Context, req *storepb.LabelValuesR
 		resHints.AddQueriedBlock(b.meta.ULID)
 
 		g.Go(func() error {
+			// The label bloom filter is checked (and loaded, if not loaded yet) concurrently for each block.
+			if !b.mayContainSeriesMatching(gctx, reqSeriesMatchers) {
+				s.metrics.labelBloomFilterSkippedBlocks.Inc()
+				return nil
+			}
+
 			result, err := blockLabelValues(gctx, b, s.postingsStrategy, s.maxSeriesPerBatch, req.Label, reqSeriesMatchers, s.logger, stats)
 			if err != nil {
 				return errors.Wrapf(err, "block %s", b.meta.ULID)
@@ -1821,6 +1875,10 @@ type bucketBlock struct {
 	// request hints' BlockMatchers.
 	blockLabels labels.Labels
 
+	// labelBloomFilter is the probabilistic set of the label name/value pairs in the block, loaded
+	// on first use, or nil if the label bloom filters are disabled.
+	labelBloomFilter *lazyLabelBloomFilter
+
 	expandedPostingsPromises sync.Map
 }
 
@@ -1870,6 +1928,36 @@ func newBucketBlock(
 	return b, nil
 }
 
+// mayHaveFile returns whether the block may have the optional file with the given name. Blocks
+// whose meta.json lists the block files have the optional file only if it's listed.
+func (b *bucketBlock) mayHaveFile(name string) bool {
+	if len(b.meta.Thanos.Files) == 0 {
+		return true
+	}
+	for _, f := range b.meta.Thanos.Files {
+		if f.RelPath == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fileSize returns the size of the block file with the input name, relative to the block directory.
+// The size is looked up in the block meta, falling back to the object attributes if missing.
+func (b *bucketBlock) fileSize(ctx context.Context, name string) (int64, error) {
+	for _, f := range b.meta.Thanos.Files {
+		if f.RelPath == name && f.SizeBytes > 0 {
+			return f.SizeBytes, nil
+		}
+	}
+
+	attrs, err := b.bkt.Attributes(ctx, path.Join(b.meta.ULID.String(), name))
+	if err != nil {
+		return 0, err
+	}
+	return attrs.Size, nil
+}
+
 func (b *bucketBlock) indexFilename() string {
 	return path.Join(b.meta.ULID.String(), block.IndexFilename)
 }
@@ -1950,6 +2038,9 @@ func (b *bucketBlock) overlapsClosedInterval(mint, maxt int64) bool {
 // Close waits for all pending readers to finish and then closes all underlying resources.
 func (b *bucketBlock) Close() error {
 	b.pendingReaders.Wait()
+	if b.labelBloomFilter != nil {
+		b.labelBloomFilter.releaseIfIdleSince(0)
+	}
 	return b.indexHeaderReader.Close()
 }
 
@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package storegateway
+
+import (
+	"context"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/grafana/dskit/runutil"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/thanos-io/objstore"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storegateway/hintspb"
+	"github.com/grafana/mimir/pkg/storegateway/storepb"
+)
+
+// Exemplars returns the exemplars stored alongside the blocks, for the series matching any of the
+// request matchers sets and within the request time range.
+func (s *BucketStore) Exemplars(ctx context.Context, req *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	matchersSets := make([][]*labels.Matcher, 0, len(req.Matchers))
+	for _, m := range req.Matchers {
+		matchers, err := storepb.MatchersToPromMatchers(m.Matchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request labels matchers").Error())
+		}
+		matchersSets = append(matchersSets, matchers)
+	}
+
+	resHints := &hintspb.ExemplarsResponseHints{}
+
+	var reqBlockMatchers []*labels.Matcher
+	if req.Hints != nil {
+		reqHints := &hintspb.ExemplarsRequestHints{}
+		err := types.UnmarshalAny(req.Hints, reqHints)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "unmarshal exemplars request hints").Error())
+		}
+
+		reqBlockMatchers, err = storepb.MatchersToPromMatchers(reqHints.BlockMatchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request hints labels matchers").Error())
+		}
+	}
+
+	seriesLimiter := s.seriesLimiterFactory(s.metrics.queriesDropped.WithLabelValues("series"))
+	g, gctx := errgroup.WithContext(ctx)
+
+	s.blocksMx.RLock()
+
+	var mtx sync.Mutex
+	var sets [][]mimirpb.TimeSeries
+
+	for _, b := range s.blocks {
+		b := b
+		if !b.overlapsClosedInterval(req.Start, req.End) {
+			continue
+		}
+		if len(reqBlockMatchers) > 0 && !b.matchLabels(reqBlockMatchers) {
+			continue
+		}
+
+		resHints.AddQueriedBlock(b.meta.ULID)
+
+		if !b.mayHaveFile(block.ExemplarsFilename) {
+			continue
+		}
+
+		g.Go(func() error {
+			result, err := blockExemplars(gctx, b, req.Start, req.End, matchersSets, seriesLimiter)
+			if err != nil {
+				return errors.Wrapf(err, "block %s", b.meta.ULID)
+			}
+
+			if len(result) > 0 {
+				mtx.Lock()
+				sets = append(sets, result)
+				mtx.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	s.blocksMx.RUnlock()
+
+	if err := g.Wait(); err != nil {
+		code := codes.Internal
+		if st, ok := status.FromError(errors.Cause(err)); ok {
+			code = st.Code()
+		} else if errors.Is(err, context.Canceled) {
+			code = codes.Canceled
+		}
+		return nil, status.Error(code, err.Error())
+	}
+
+	anyHints, err := types.MarshalAny(resHints)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, errors.Wrap(err, "marshal exemplars response hints").Error())
+	}
+
+	return &storepb.ExemplarsResponse{
+		Timeseries: block.MergeExemplars(sets...),
+		Hints:      anyHints,
+	}, nil
+}
+
+// blockExemplars reads the index of the exemplars file of the block from the bucket, then reads only
+// the groups of series whose metric name may match any of the matchers sets, and returns the exemplars
+// within the closed interval [mint, maxt] of the series matching any of the matchers sets. Each
+// returned series is reserved from the series limiter.
+func blockExemplars(ctx context.Context, b *bucketBlock, mint, maxt int64, matchersSets [][]*labels.Matcher, seriesLimiter SeriesLimiter) ([]mimirpb.TimeSeries, error) {
+	name := path.Join(b.meta.ULID.String(), block.ExemplarsFilename)
+	size, err := b.fileSize(ctx, block.ExemplarsFilename)
+	if b.bkt.IsObjNotFoundErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get exemplars file size")
+	}
+
+	index, err := block.ReadExemplarsIndex(&bucketReaderAt{ctx: ctx, bkt: b.bkt, name: name}, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "read exemplars file index")
+	}
+
+	var result []mimirpb.TimeSeries
+	for _, e := range index {
+		if !metricNameMatchesAnyMatchersSet(e.MetricName, matchersSets) {
+			continue
+		}
+
+		series, err := groupExemplars(ctx, b, name, e, mint, maxt, matchersSets, seriesLimiter)
+		if err != nil {
+			return nil, errors.Wrap(err, "read exemplars file")
+		}
+		result = append(result, series...)
+	}
+	return result, nil
+}
+
+// groupExemplars reads the group of series of the exemplars file located by the index entry e, and returns
+// the exemplars within the closed interval [mint, maxt] of the series matching any of the matchers sets.
+func groupExemplars(ctx context.Context, b *bucketBlock, name string, e block.ExemplarsIndexEntry, mint, maxt int64, matchersSets [][]*labels.Matcher, seriesLimiter SeriesLimiter) ([]mimirpb.TimeSeries, error) {
+	r := &bucketRangesReader{ctx: ctx, bkt: b.bkt, name: name, offset: e.Offset, end: e.Offset + e.Length, rangeSize: exemplarsFileRangeSize}
+	defer runutil.CloseWithLogOnErr(b.logger, r, "exemplars file reader")
+
+	var result []mimirpb.TimeSeries
+	it := block.NewExemplarsGroupReader(r)
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		s := it.At()
+		if !matchesAnyMatchersSet(s.Labels, matchersSets) {
+			continue
+		}
+
+		var exemplars []mimirpb.Exemplar
+		for _, e := range s.Exemplars {
+			if e.TimestampMs >= mint && e.TimestampMs <= maxt {
+				exemplars = append(exemplars, e)
+			}
+		}
+		if len(exemplars) == 0 {
+			continue
+		}
+		if err := seriesLimiter.Reserve(1); err != nil {
+			return nil, errors.Wrap(err, "exceeded series limit")
+		}
+		result = append(result, mimirpb.TimeSeries{Labels: s.Labels, Exemplars: exemplars})
+	}
+	return result, it.Err()
+}
+
+// exemplarsFileRangeSize is the max size of the ranges the groups of series of the exemplars files are read in.
+// Groups are read in ranges, rather than with a single request, so that they're cached in the chunks cache the
+// same way as the chunks files are, without being fully buffered in memory.
+const exemplarsFileRangeSize = 1024 * 1024
+
+// bucketReaderAt reads ranges of an object from the bucket.
+type bucketReaderAt struct {
+	ctx  context.Context
+	bkt  objstore.BucketReader
+	name string
+}
+
+func (r *bucketReaderAt) ReadAt(p []byte, off int64) (_ int, err error) {
+	rc, err := r.bkt.GetRange(r.ctx, r.name, off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer runutil.CloseWithErrCapture(&err, rc, "close object range reader")
+
+	return io.ReadFull(rc, p)
+}
+
+// bucketRangesReader reads the [offset, end) range of an object from the bucket sequentially, one range
+// of at most rangeSize at a time.
+type bucketRangesReader struct {
+	ctx       context.Context
+	bkt       objstore.BucketReader
+	name      string
+	offset    int64
+	end       int64
+	rangeSize int64
+
+	cur io.ReadCloser
+}
+
+func (r *bucketRangesReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if r.offset >= r.end {
+				return 0, io.EOF
+			}
+
+			length := r.rangeSize
+			if r.offset+length > r.end {
+				length = r.end - r.offset
+			}
+
+			rc, err := r.bkt.GetRange(r.ctx, r.name, r.offset, length)
+			if err != nil {
+				return 0, err
+			}
+			r.cur = rc
+			r.offset += length
+		}
+
+		n, err := r.cur.Read(p)
+		if errors.Is(err, io.EOF) {
+			err = r.cur.Close()
+			r.cur = nil
+			if n > 0 || err != nil {
+				return n, err
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *bucketRangesReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	return r.cur.Close()
+}
+
+func matchesAnyMatchersSet(series []mimirpb.LabelAdapter, matchersSets [][]*labels.Matcher) bool {
+	lbls := mimirpb.FromLabelAdaptersToLabels(series)
+
+outer:
+	for _, matchers := range matchersSets {
+		for _, m := range matchers {
+			if !m.Matches(lbls.Get(m.Name)) {
+				continue outer
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// metricNameMatchesAnyMatchersSet returns whether the series with the input metric name may match any of the
+// matchers sets, looking only at the metric name matchers.
+func metricNameMatchesAnyMatchersSet(metricName string, matchersSets [][]*labels.Matcher) bool {
+outer:
+	for _, matchers := range matchersSets {
+		for _, m := range matchers {
+			if m.Name == labels.MetricName && !m.Matches(metricName) {
+				continue outer
+			}
+		}
+		return true
+	}
+	return false
+}
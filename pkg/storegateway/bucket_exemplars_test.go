@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package storegateway
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/types"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storegateway/hintspb"
+	"github.com/grafana/mimir/pkg/storegateway/storepb"
+)
+
+func TestBucketStore_Exemplars(t *testing.T) {
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+
+	exemplar := func(ts int64) mimirpb.Exemplar {
+		return mimirpb.Exemplar{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: float64(ts), TimestampMs: ts}
+	}
+	series := func(name string, exemplars ...mimirpb.Exemplar) mimirpb.TimeSeries {
+		return mimirpb.TimeSeries{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", name)), Exemplars: exemplars}
+	}
+
+	// Block 1 and 2 have exemplars, block 3 has no exemplars file.
+	block1 := newExemplarsTestBlock(t, bkt, 0, 100, []mimirpb.TimeSeries{series("a", exemplar(10), exemplar(50)), series("b", exemplar(20))})
+	block2 := newExemplarsTestBlock(t, bkt, 100, 200, []mimirpb.TimeSeries{series("a", exemplar(150)), series("c", exemplar(160))})
+	block3 := newExemplarsTestBlock(t, bkt, 200, 300, nil)
+
+	newStore := func(maxSeries uint64) *BucketStore {
+		return &BucketStore{
+			logger:               log.NewNopLogger(),
+			metrics:              NewBucketStoreMetrics(nil),
+			seriesLimiterFactory: newStaticSeriesLimiterFactory(maxSeries),
+			blocks: map[ulid.ULID]*bucketBlock{
+				block1.meta.ULID: block1,
+				block2.meta.ULID: block2,
+				block3.meta.ULID: block3,
+			},
+		}
+	}
+	store := newStore(0)
+
+	matchers := func(sets ...[]storepb.LabelMatcher) []storepb.ExemplarMatchers {
+		var res []storepb.ExemplarMatchers
+		for _, s := range sets {
+			res = append(res, storepb.ExemplarMatchers{Matchers: s})
+		}
+		return res
+	}
+
+	tests := map[string]struct {
+		req             *storepb.ExemplarsRequest
+		blockMatchers   []storepb.LabelMatcher
+		expected        []mimirpb.TimeSeries
+		expectedQueried []ulid.ULID
+	}{
+		"should return exemplars of matching series across blocks": {
+			req: &storepb.ExemplarsRequest{Start: 0, End: 300, Matchers: matchers(
+				[]storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "__name__", Value: "a"}},
+			)},
+			expected:        []mimirpb.TimeSeries{series("a", exemplar(10), exemplar(50), exemplar(150))},
+			expectedQueried: []ulid.ULID{block1.meta.ULID, block2.meta.ULID, block3.meta.ULID},
+		},
+		"should return exemplars of series matching any matchers set": {
+			req: &storepb.ExemplarsRequest{Start: 0, End: 300, Matchers: matchers(
+				[]storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "__name__", Value: "b"}},
+				[]storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "__name__", Value: "c"}},
+			)},
+			expected:        []mimirpb.TimeSeries{series("b", exemplar(20)), series("c", exemplar(160))},
+			expectedQueried: []ulid.ULID{block1.meta.ULID, block2.meta.ULID, block3.meta.ULID},
+		},
+		"should filter exemplars by time range": {
+			req: &storepb.ExemplarsRequest{Start: 50, End: 150, Matchers: matchers(
+				[]storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: "__name__", Value: ".+"}},
+			)},
+			expected:        []mimirpb.TimeSeries{series("a", exemplar(50), exemplar(150))},
+			expectedQueried: []ulid.ULID{block1.meta.ULID, block2.meta.ULID},
+		},
+		"should query only the blocks matching the hints": {
+			req: &storepb.ExemplarsRequest{Start: 0, End: 300, Matchers: matchers(
+				[]storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: "__name__", Value: ".+"}},
+			)},
+			blockMatchers:   []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: block.BlockIDLabel, Value: block2.meta.ULID.String()}},
+			expected:        []mimirpb.TimeSeries{series("a", exemplar(150)), series("c", exemplar(160))},
+			expectedQueried: []ulid.ULID{block2.meta.ULID},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			if testData.blockMatchers != nil {
+				anyHints, err := types.MarshalAny(&hintspb.ExemplarsRequestHints{BlockMatchers: testData.blockMatchers})
+				require.NoError(t, err)
+				testData.req.Hints = anyHints
+			}
+
+			res, err := store.Exemplars(ctx, testData.req)
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, res.Timeseries)
+
+			resHints := hintspb.ExemplarsResponseHints{}
+			require.NoError(t, types.UnmarshalAny(res.Hints, &resHints))
+
+			expectedHints := hintspb.ExemplarsResponseHints{}
+			for _, id := range testData.expectedQueried {
+				expectedHints.AddQueriedBlock(id)
+			}
+			assert.ElementsMatch(t, expectedHints.QueriedBlocks, resHints.QueriedBlocks)
+		})
+	}
+}
+
+func TestBucketStore_Exemplars_ShouldApplySeriesLimit(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	exemplar := mimirpb.Exemplar{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: 1, TimestampMs: 10}
+
+	b := newExemplarsTestBlock(t, bkt, 0, 100, []mimirpb.TimeSeries{
+		{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "a")), Exemplars: []mimirpb.Exemplar{exemplar}},
+		{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "b")), Exemplars: []mimirpb.Exemplar{exemplar}},
+		{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", "c")), Exemplars: []mimirpb.Exemplar{exemplar}},
+	})
+
+	store := &BucketStore{
+		logger:               log.NewNopLogger(),
+		metrics:              NewBucketStoreMetrics(nil),
+		seriesLimiterFactory: newStaticSeriesLimiterFactory(2),
+		blocks:               map[ulid.ULID]*bucketBlock{b.meta.ULID: b},
+	}
+
+	req := func(value string) *storepb.ExemplarsRequest {
+		return &storepb.ExemplarsRequest{Start: 0, End: 100, Matchers: []storepb.ExemplarMatchers{{
+			Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: "__name__", Value: value}},
+		}}}
+	}
+
+	// Only the series matching the request count towards the limit.
+	res, err := store.Exemplars(context.Background(), req("a|b"))
+	require.NoError(t, err)
+	assert.Len(t, res.Timeseries, 2)
+
+	_, err = store.Exemplars(context.Background(), req(".+"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeded series limit")
+	assert.Equal(t, codes.Code(http.StatusUnprocessableEntity), status.Code(err))
+}
+
+func TestBucketStore_Exemplars_ShouldReadOnlyTheMatchingMetrics(t *testing.T) {
+	ctx := context.Background()
+	bkt := &rangesRecordingBucket{Bucket: objstore.NewInMemBucket()}
+
+	series := func(name string) mimirpb.TimeSeries {
+		return mimirpb.TimeSeries{
+			Labels:    mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("__name__", name)),
+			Exemplars: []mimirpb.Exemplar{{Labels: mimirpb.FromLabelsToLabelAdapters(labels.FromStrings("trace_id", "1")), Value: 1, TimestampMs: 10}},
+		}
+	}
+	b := newExemplarsTestBlock(t, bkt, 0, 100, []mimirpb.TimeSeries{series("a"), series("b"), series("c")})
+
+	name := path.Join(b.meta.ULID.String(), block.ExemplarsFilename)
+	attrs, err := bkt.Attributes(ctx, name)
+	require.NoError(t, err)
+	index, err := block.ReadExemplarsIndex(&bucketReaderAt{ctx: ctx, bkt: bkt, name: name}, attrs.Size)
+	require.NoError(t, err)
+	require.Len(t, index, 3)
+	bkt.ranges = nil
+
+	store := &BucketStore{
+		logger:               log.NewNopLogger(),
+		metrics:              NewBucketStoreMetrics(nil),
+		seriesLimiterFactory: newStaticSeriesLimiterFactory(0),
+		blocks:               map[ulid.ULID]*bucketBlock{b.meta.ULID: b},
+	}
+	res, err := store.Exemplars(ctx, &storepb.ExemplarsRequest{Start: 0, End: 100, Matchers: []storepb.ExemplarMatchers{{
+		Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_RE, Name: "__name__", Value: "a|c"}},
+	}}})
+	require.NoError(t, err)
+	assert.Equal(t, []mimirpb.TimeSeries{series("a"), series("c")}, res.Timeseries)
+
+	// The group of the "b" metric has not been read.
+	require.NotEmpty(t, bkt.ranges)
+	for _, r := range bkt.ranges {
+		assert.False(t, r[0] < index[1].Offset+index[1].Length && index[1].Offset < r[0]+r[1], "range %v overlaps the group of the metric b", r)
+	}
+}
+
+// rangesRecordingBucket records the offset and length of the object ranges read.
+type rangesRecordingBucket struct {
+	objstore.Bucket
+	ranges [][2]int64
+}
+
+func (b *rangesRecordingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	b.ranges = append(b.ranges, [2]int64{off, length})
+	return b.Bucket.GetRange(ctx, name, off, length)
+}
+
+func TestBucketRangesReader(t *testing.T) {
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	content := []byte("0123456789abcdefghij")
+	require.NoError(t, bkt.Upload(ctx, "object", bytes.NewReader(content)))
+
+	for _, rangeSize := range []int64{1, 3, 7, 20, 100} {
+		r := &bucketRangesReader{ctx: ctx, bkt: bkt, name: "object", end: int64(len(content)), rangeSize: rangeSize}
+		actual, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Equal(t, content, actual, "range size: %d", rangeSize)
+	}
+}
+
+// newExemplarsTestBlock uploads the exemplars file of a block to the bucket, and returns the bucketBlock.
+// No exemplars file is uploaded if there are no exemplars.
+func newExemplarsTestBlock(t *testing.T, bkt objstore.Bucket, minT, maxT int64, series []mimirpb.TimeSeries) *bucketBlock {
+	meta := &block.Meta{BlockMeta: tsdb.BlockMeta{ULID: ulid.MustNew(uint64(maxT), nil), MinTime: minT, MaxTime: maxT}}
+	meta.Thanos.Files = []block.File{{RelPath: block.IndexFilename}}
+
+	if len(series) > 0 {
+		dir := t.TempDir()
+		require.NoError(t, block.WriteExemplarsFile(dir, series))
+
+		f, err := os.Open(filepath.Join(dir, block.ExemplarsFilename))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+		require.NoError(t, bkt.Upload(context.Background(), path.Join(meta.ULID.String(), block.ExemplarsFilename), f))
+
+		meta.Thanos.Files = append(meta.Thanos.Files, block.File{RelPath: block.ExemplarsFilename})
+	}
+
+	return &bucketBlock{
+		logger:      log.NewNopLogger(),
+		bkt:         bkt,
+		meta:        meta,
+		blockLabels: labels.FromStrings(block.BlockIDLabel, meta.ULID.String()),
+	}
+}
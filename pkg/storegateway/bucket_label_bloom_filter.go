@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package storegateway
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/runutil"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+// labelBloomFilterLoadConcurrency is the max number of label bloom filters loaded concurrently by a request.
+const labelBloomFilterLoadConcurrency = 16
+
+// lazyLabelBloomFilter loads the label bloom filter of a block on first use, rather than when the block
+// is loaded, and keeps it until it's released because idle or the block is closed.
+type lazyLabelBloomFilter struct {
+	block *bucketBlock
+
+	mx     sync.Mutex
+	loaded bool
+	// filter is nil if the block has no filter or it failed to load.
+	filter *block.LabelBloomFilter
+
+	// usedAt is the unix nano time of the last usage of the filter.
+	usedAt atomic.Int64
+}
+
+func newLazyLabelBloomFilter(b *bucketBlock) *lazyLabelBloomFilter {
+	return &lazyLabelBloomFilter{block: b}
+}
+
+// get returns the label bloom filter of the block, loading it if not loaded yet, or nil if not available.
+func (f *lazyLabelBloomFilter) get(ctx context.Context) *block.LabelBloomFilter {
+	f.usedAt.Store(time.Now().UnixNano())
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.loaded {
+		return f.filter
+	}
+
+	filter, err := f.block.loadLabelBloomFilter(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Try again on the next request.
+			return nil
+		}
+		// The block is queried without filter until the filter is released.
+		f.block.metrics.labelBloomFilterLoadFailures.Inc()
+		level.Warn(f.block.logger).Log("msg", "failed to load label bloom filter", "err", err)
+	}
+
+	f.loaded = true
+	f.filter = filter
+	if filter != nil {
+		f.block.metrics.labelBloomFilterSizeBytes.Observe(float64(filter.SizeBytes()))
+		f.block.metrics.labelBloomFilterFalsePositiveRate.Observe(filter.EstimatedFalsePositiveRate())
+		f.block.metrics.labelBloomFilterLoadedBytes.Add(float64(filter.SizeBytes()))
+	}
+	return filter
+}
+
+// releaseIfIdleSince releases the filter if it hasn't been used since the input time (as unix nano).
+// If idleSince is 0, the filter is released regardless of its last usage.
+func (f *lazyLabelBloomFilter) releaseIfIdleSince(idleSince int64) {
+	if idleSince > 0 && f.usedAt.Load() > idleSince {
+		return
+	}
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.filter != nil {
+		f.block.metrics.labelBloomFilterLoadedBytes.Sub(float64(f.filter.SizeBytes()))
+	}
+	f.loaded = false
+	f.filter = nil
+}
+
+// loadLabelBloomFilter reads the label bloom filter of the block from the bucket. Returns nil if the block has no filter.
+func (b *bucketBlock) loadLabelBloomFilter(ctx context.Context) (*block.LabelBloomFilter, error) {
+	if !b.mayHaveFile(block.LabelBloomFilterFilename) {
+		return nil, nil
+	}
+
+	r, err := b.bkt.Get(ctx, path.Join(b.meta.ULID.String(), block.LabelBloomFilterFilename))
+	if b.bkt.IsObjNotFoundErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get label bloom filter")
+	}
+	defer runutil.CloseWithLogOnErr(b.logger, r, "label bloom filter reader")
+
+	f, err := block.ReadLabelBloomFilter(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read label bloom filter")
+	}
+	return f, nil
+}
+
+// mayContainSeriesMatching returns false if the label bloom filter of the block guarantees that
+// no series in the block matches all the input matchers, otherwise it returns true. The filter
+// is loaded on first use.
+func (b *bucketBlock) mayContainSeriesMatching(ctx context.Context, matchers []*labels.Matcher) bool {
+	if b.labelBloomFilter == nil || !anyLabelBloomFilterCheckableMatcher(matchers) {
+		return true
+	}
+
+	f := b.labelBloomFilter.get(ctx)
+	if f == nil {
+		return true
+	}
+
+	for _, m := range matchers {
+		if !labelBloomFilterMayMatch(f, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyLabelBloomFilterCheckableMatcher returns whether any of the input matchers can be checked against a
+// label bloom filter, so that the filter isn't loaded for requests which can't be filtered.
+func anyLabelBloomFilterCheckableMatcher(matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if m.Type == labels.MatchEqual || (m.Type == labels.MatchRegexp && len(m.SetMatches()) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseIdleLabelBloomFiltersLoop periodically releases the label bloom filters idle for longer than
+// the idle timeout, until the store is closed.
+func (s *BucketStore) releaseIdleLabelBloomFiltersLoop(idleTimeout time.Duration) {
+	ticker := time.NewTicker(idleTimeout / 10)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			s.releaseIdleLabelBloomFilters(time.Now().Add(-idleTimeout).UnixNano())
+		}
+	}
+}
+
+// releaseIdleLabelBloomFilters releases the label bloom filters of the blocks which haven't been used
+// since the input time (as unix nano).
+func (s *BucketStore) releaseIdleLabelBloomFilters(idleSince int64) {
+	s.blocksMx.RLock()
+	defer s.blocksMx.RUnlock()
+
+	for _, b := range s.blocks {
+		if b.labelBloomFilter != nil {
+			b.labelBloomFilter.releaseIfIdleSince(idleSince)
+		}
+	}
+}
+
+// labelBloomFilterMayMatch returns false if the filter guarantees that no series matches the matcher.
+// Only equality matchers and regexp matchers matching a set of values can be checked against the filter.
+func labelBloomFilterMayMatch(f *block.LabelBloomFilter, m *labels.Matcher) bool {
+	var values []string
+	switch m.Type {
+	case labels.MatchEqual:
+		values = []string{m.Value}
+	case labels.MatchRegexp:
+		values = m.SetMatches()
+	}
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		// An empty value also matches series without the label, which can't be checked against the filter.
+		if v == "" || f.MayContain(m.Name, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterBlocksByLabelBloomFilter splits the input blocks between the ones which may contain series
+// matching all the input matchers, and the ones skipped according to their label bloom filter. The
+// filters not loaded yet are loaded concurrently.
+func (s *BucketStore) filterBlocksByLabelBloomFilter(ctx context.Context, blocks []*bucketBlock, matchers []*labels.Matcher) (queried, skipped []*bucketBlock) {
+	mayContain := make([]bool, len(blocks))
+	for i := range mayContain {
+		mayContain[i] = true
+	}
+	_ = concurrency.ForEachJob(ctx, len(blocks), labelBloomFilterLoadConcurrency, func(ctx context.Context, idx int) error {
+		mayContain[idx] = blocks[idx].mayContainSeriesMatching(ctx, matchers)
+		return nil
+	})
+
+	queried = make([]*bucketBlock, 0, len(blocks))
+	for i, b := range blocks {
+		if mayContain[i] {
+			queried = append(queried, b)
+		} else {
+			skipped = append(skipped, b)
+		}
+	}
+
+	s.metrics.labelBloomFilterSkippedBlocks.Add(float64(len(skipped)))
+	return queried, skipped
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package storegateway
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+func TestLabelBloomFilterMayMatch(t *testing.T) {
+	f := block.NewLabelBloomFilter(10, 0.0001)
+	f.Add("__name__", "up")
+	f.Add("job", "api")
+
+	tests := map[string]struct {
+		matcher  *labels.Matcher
+		expected bool
+	}{
+		"equal matcher on existing pair": {
+			matcher:  labels.MustNewMatcher(labels.MatchEqual, "job", "api"),
+			expected: true,
+		},
+		"equal matcher on missing pair": {
+			matcher:  labels.MustNewMatcher(labels.MatchEqual, "job", "db"),
+			expected: false,
+		},
+		"equal matcher on empty value": {
+			matcher:  labels.MustNewMatcher(labels.MatchEqual, "cluster", ""),
+			expected: true,
+		},
+		"regexp set matcher with an existing value": {
+			matcher:  labels.MustNewMatcher(labels.MatchRegexp, "job", "db|api"),
+			expected: true,
+		},
+		"regexp set matcher without existing values": {
+			matcher:  labels.MustNewMatcher(labels.MatchRegexp, "job", "db|cache"),
+			expected: false,
+		},
+		"regexp matcher which can't be checked against the filter": {
+			matcher:  labels.MustNewMatcher(labels.MatchRegexp, "job", "d.+"),
+			expected: true,
+		},
+		"not equal matcher": {
+			matcher:  labels.MustNewMatcher(labels.MatchNotEqual, "job", "api"),
+			expected: true,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testData.expected, labelBloomFilterMayMatch(f, testData.matcher))
+		})
+	}
+}
+
+func TestBucketStore_FilterBlocksByLabelBloomFilter(t *testing.T) {
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	metrics := NewBucketStoreMetrics(nil)
+
+	// Block 1 and 2 have a label bloom filter, block 3 has no filter, block 4 has a corrupted filter.
+	block1 := newLabelBloomFilterTestBlock(t, bkt, metrics, 100, labels.FromStrings("__name__", "a", "job", "api"))
+	block2 := newLabelBloomFilterTestBlock(t, bkt, metrics, 200, labels.FromStrings("__name__", "b", "job", "db"))
+	block3 := newLabelBloomFilterTestBlock(t, bkt, metrics, 300, labels.EmptyLabels())
+	block4 := newLabelBloomFilterTestBlock(t, bkt, metrics, 400, labels.EmptyLabels())
+
+	require.NoError(t, bkt.Upload(ctx, path.Join(block4.meta.ULID.String(), block.LabelBloomFilterFilename), bytes.NewReader([]byte("invalid"))))
+	block4.meta.Thanos.Files = append(block4.meta.Thanos.Files, block.File{RelPath: block.LabelBloomFilterFilename})
+
+	for _, b := range []*bucketBlock{block1, block2, block3, block4} {
+		b.labelBloomFilter = newLazyLabelBloomFilter(b)
+	}
+
+	store := &BucketStore{logger: log.NewNopLogger(), metrics: metrics}
+	blocks := []*bucketBlock{block1, block2, block3, block4}
+
+	// The filters are not loaded until a request can be checked against them.
+	queried, skipped := store.filterBlocksByLabelBloomFilter(ctx, blocks, []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchNotEqual, "job", "api"),
+	})
+	assert.Equal(t, blocks, queried)
+	assert.Empty(t, skipped)
+	for _, b := range blocks {
+		assert.False(t, b.labelBloomFilter.loaded)
+	}
+
+	queried, skipped = store.filterBlocksByLabelBloomFilter(ctx, blocks, []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, "__name__", "a"),
+		labels.MustNewMatcher(labels.MatchRegexp, "job", "api|cache"),
+	})
+	assert.Equal(t, []*bucketBlock{block1, block3, block4}, queried)
+	assert.Equal(t, []*bucketBlock{block2}, skipped)
+
+	require.NotNil(t, block1.labelBloomFilter.filter)
+	require.NotNil(t, block2.labelBloomFilter.filter)
+	require.Nil(t, block3.labelBloomFilter.filter)
+	require.Nil(t, block4.labelBloomFilter.filter)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.labelBloomFilterLoadFailures))
+	loadedBytes := float64(block1.labelBloomFilter.filter.SizeBytes() + block2.labelBloomFilter.filter.SizeBytes())
+	assert.Equal(t, loadedBytes, testutil.ToFloat64(metrics.labelBloomFilterLoadedBytes))
+
+	queried, skipped = store.filterBlocksByLabelBloomFilter(ctx, blocks, []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, "job", "cache"),
+	})
+	assert.Equal(t, []*bucketBlock{block3, block4}, queried)
+	assert.Equal(t, []*bucketBlock{block1, block2}, skipped)
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.labelBloomFilterSkippedBlocks))
+
+	// The filters (and load failures) are kept until released.
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.labelBloomFilterLoadFailures))
+}
+
+func TestBucketStore_ReleaseIdleLabelBloomFilters(t *testing.T) {
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	metrics := NewBucketStoreMetrics(nil)
+
+	block1 := newLabelBloomFilterTestBlock(t, bkt, metrics, 100, labels.FromStrings("__name__", "a"))
+	block2 := newLabelBloomFilterTestBlock(t, bkt, metrics, 200, labels.FromStrings("__name__", "b"))
+	store := &BucketStore{logger: log.NewNopLogger(), metrics: metrics, blocks: map[ulid.ULID]*bucketBlock{}}
+	for _, b := range []*bucketBlock{block1, block2} {
+		b.labelBloomFilter = newLazyLabelBloomFilter(b)
+		store.blocks[b.meta.ULID] = b
+	}
+
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "__name__", "a")}
+	require.True(t, block1.mayContainSeriesMatching(ctx, matchers))
+	require.False(t, block2.mayContainSeriesMatching(ctx, matchers))
+	require.Equal(t, float64(block1.labelBloomFilter.filter.SizeBytes()+block2.labelBloomFilter.filter.SizeBytes()), testutil.ToFloat64(metrics.labelBloomFilterLoadedBytes))
+
+	// Only the filters not used since the idle time are released.
+	block1.labelBloomFilter.usedAt.Store(time.Now().Add(-time.Hour).UnixNano())
+	store.releaseIdleLabelBloomFilters(time.Now().Add(-time.Minute).UnixNano())
+	assert.False(t, block1.labelBloomFilter.loaded)
+	assert.True(t, block2.labelBloomFilter.loaded)
+	assert.Equal(t, float64(block2.labelBloomFilter.filter.SizeBytes()), testutil.ToFloat64(metrics.labelBloomFilterLoadedBytes))
+
+	// A released filter is loaded again on the next use.
+	require.False(t, block1.mayContainSeriesMatching(ctx, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "__name__", "b")}))
+	assert.True(t, block1.labelBloomFilter.loaded)
+
+	// Filters are released regardless of their usage when the idle time is 0.
+	store.releaseIdleLabelBloomFilters(0)
+	assert.False(t, block1.labelBloomFilter.loaded)
+	assert.False(t, block2.labelBloomFilter.loaded)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.labelBloomFilterLoadedBytes))
+}
+
+// newLabelBloomFilterTestBlock uploads the label bloom filter of a block with the input label pairs
+// to the bucket, and returns the bucketBlock. No filter is uploaded if there are no labels.
+func newLabelBloomFilterTestBlock(t *testing.T, bkt objstore.Bucket, metrics *BucketStoreMetrics, maxT int64, lbls labels.Labels) *bucketBlock {
+	meta := &block.Meta{BlockMeta: tsdb.BlockMeta{ULID: ulid.MustNew(uint64(maxT), nil), MinTime: maxT - 100, MaxTime: maxT}}
+	meta.Thanos.Files = []block.File{{RelPath: block.IndexFilename}}
+
+	if !lbls.IsEmpty() {
+		f := block.NewLabelBloomFilter(lbls.Len(), 0.0001)
+		lbls.Range(func(l labels.Label) {
+			f.Add(l.Name, l.Value)
+		})
+
+		data, err := f.MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, bkt.Upload(context.Background(), path.Join(meta.ULID.String(), block.LabelBloomFilterFilename), bytes.NewReader(data)))
+
+		meta.Thanos.Files = append(meta.Thanos.Files, block.File{RelPath: block.LabelBloomFilterFilename})
+	}
+
+	return &bucketBlock{
+		logger:  log.NewNopLogger(),
+		bkt:     bkt,
+		metrics: metrics,
+		meta:    meta,
+	}
+}
@@ -52,6 +52,12 @@ type BucketStoreMetrics struct {
 	seriesFetchDuration   prometheus.Histogram
 	postingsFetchDuration prometheus.Histogram
 
+	labelBloomFilterSizeBytes         prometheus.Histogram
+	labelBloomFilterFalsePositiveRate prometheus.Histogram
+	labelBloomFilterLoadFailures      prometheus.Counter
+	labelBloomFilterLoadedBytes       prometheus.Gauge
+	labelBloomFilterSkippedBlocks     prometheus.Counter
+
 	indexHeaderReaderMetrics *indexheader.ReaderPoolMetrics
 }
 
@@ -168,6 +174,29 @@ func NewBucketStoreMetrics(reg prometheus.Registerer) *BucketStoreMetrics {
 		},
 	})
 
+	m.labelBloomFilterSizeBytes = promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+		Name:    "cortex_bucket_store_label_bloom_filter_size_bytes",
+		Help:    "Size in bytes of the label bloom filters of the loaded blocks.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 10), // 1KiB to 256MiB
+	})
+	m.labelBloomFilterFalsePositiveRate = promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+		Name:    "cortex_bucket_store_label_bloom_filter_estimated_false_positive_rate",
+		Help:    "Estimated false positive rate of the label bloom filters of the loaded blocks.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1},
+	})
+	m.labelBloomFilterLoadFailures = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name: "cortex_bucket_store_label_bloom_filter_load_failures_total",
+		Help: "Total number of failures loading the label bloom filter of a block. Blocks whose filter failed to load are queried without filter.",
+	})
+	m.labelBloomFilterLoadedBytes = promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+		Name: "cortex_bucket_store_label_bloom_filter_loaded_bytes",
+		Help: "Size in bytes of the label bloom filters currently loaded in memory.",
+	})
+	m.labelBloomFilterSkippedBlocks = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name: "cortex_bucket_store_label_bloom_filter_skipped_blocks_total",
+		Help: "Total number of blocks not queried because their label bloom filter doesn't contain the label values requested by the query.",
+	})
+
 	m.indexHeaderReaderMetrics = indexheader.NewReaderPoolMetrics(prometheus.WrapRegistererWithPrefix("cortex_bucket_store_", reg))
 
 	m.streamingSeriesRequestDurationByStage = promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
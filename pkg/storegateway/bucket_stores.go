@@ -16,8 +16,8 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/backoff"
-	"github.com/grafana/dskit/cache"
 	"github.com/grafana/dskit/gate"
+	"github.com/grafana/dskit/services"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -31,6 +31,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/storegateway/chunkscache"
 	"github.com/grafana/mimir/pkg/storegateway/indexcache"
+	"github.com/grafana/mimir/pkg/storegateway/indexheader"
 	"github.com/grafana/mimir/pkg/storegateway/storepb"
 	util_log "github.com/grafana/mimir/pkg/util/log"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
@@ -69,6 +70,12 @@ type BucketStores struct {
 	// Gate used to limit concurrency on loading index-headers across all tenants.
 	lazyLoadingGate gate.Gate
 
+	// Budget used to limit the number and size of loaded index-headers across all tenants.
+	indexHeaderLoadedBudget *indexheader.LoadedBudget
+
+	// Services releasing the resources of the caches shared across all tenants, once stopped.
+	cachesServices []services.Service
+
 	// Keeps a bucket store for each tenant.
 	storesMu sync.RWMutex
 	stores   map[string]*BucketStore
@@ -83,7 +90,7 @@ type BucketStores struct {
 
 // NewBucketStores makes a new BucketStores.
 func NewBucketStores(cfg tsdb.BlocksStorageConfig, shardingStrategy ShardingStrategy, bucketClient objstore.Bucket, limits *validation.Overrides, logger log.Logger, reg prometheus.Registerer) (*BucketStores, error) {
-	chunksCacheClient, err := cache.CreateClient("chunks-cache", cfg.BucketStore.ChunksCache.BackendConfig, logger, prometheus.WrapRegistererWithPrefix("thanos_", reg))
+	chunksCacheClient, chunksCacheService, err := tsdb.NewChunksCacheClient(cfg.BucketStore.ChunksCache, logger, reg)
 	if err != nil {
 		return nil, errors.Wrapf(err, "chunks-cache")
 	}
@@ -109,19 +116,31 @@ func NewBucketStores(cfg tsdb.BlocksStorageConfig, shardingStrategy ShardingStra
 		lazyLoadingGate = gate.NewInstrumented(lazyLoadingGateReg, cfg.BucketStore.IndexHeaderLazyLoadingConcurrency, blockingGate)
 	}
 
+	// The number and size of loaded index-headers are limited across all tenants.
+	var indexHeaderLoadedBudget *indexheader.LoadedBudget
+	if cfg.BucketStore.IndexHeaderLazyLoadingMaxLoaded > 0 || cfg.BucketStore.IndexHeaderLazyLoadingMaxLoadedBytes > 0 {
+		indexHeaderLoadedBudget = indexheader.NewLoadedBudget(
+			cfg.BucketStore.IndexHeaderLazyLoadingMaxLoadedBytes,
+			cfg.BucketStore.IndexHeaderLazyLoadingMaxLoaded,
+			cfg.BucketStore.IndexHeaderLazyLoadingBudgetWaitTimeout,
+			indexheader.NewLoadedBudgetMetrics(prometheus.WrapRegistererWithPrefix("cortex_bucket_store_", reg)),
+		)
+	}
+
 	u := &BucketStores{
-		logger:             logger,
-		cfg:                cfg,
-		limits:             limits,
-		bucket:             cachingBucket,
-		shardingStrategy:   shardingStrategy,
-		stores:             map[string]*BucketStore{},
-		bucketStoreMetrics: NewBucketStoreMetrics(reg),
-		metaFetcherMetrics: NewMetadataFetcherMetrics(),
-		queryGate:          queryGate,
-		lazyLoadingGate:    lazyLoadingGate,
-		partitioners:       newGapBasedPartitioners(cfg.BucketStore.PartitionerMaxGapBytes, reg),
-		seriesHashCache:    hashcache.NewSeriesHashCache(cfg.BucketStore.SeriesHashCacheMaxBytes),
+		logger:                  logger,
+		cfg:                     cfg,
+		limits:                  limits,
+		bucket:                  cachingBucket,
+		shardingStrategy:        shardingStrategy,
+		stores:                  map[string]*BucketStore{},
+		bucketStoreMetrics:      NewBucketStoreMetrics(reg),
+		metaFetcherMetrics:      NewMetadataFetcherMetrics(),
+		queryGate:               queryGate,
+		lazyLoadingGate:         lazyLoadingGate,
+		indexHeaderLoadedBudget: indexHeaderLoadedBudget,
+		partitioners:            newGapBasedPartitioners(cfg.BucketStore.PartitionerMaxGapBytes, reg),
+		seriesHashCache:         hashcache.NewSeriesHashCache(cfg.BucketStore.SeriesHashCacheMaxBytes),
 		syncBackoffConfig: backoff.Config{
 			MinBackoff: 1 * time.Second,
 			MaxBackoff: 10 * time.Second,
@@ -153,10 +172,17 @@ func NewBucketStores(cfg tsdb.BlocksStorageConfig, shardingStrategy ShardingStra
 	}, u.getBlocksLoadedMetric)
 
 	// Init the index cache.
-	if u.indexCache, err = tsdb.NewIndexCache(cfg.BucketStore.IndexCache, logger, reg); err != nil {
+	var indexCacheService services.Service
+	if u.indexCache, indexCacheService, err = tsdb.NewIndexCache(cfg.BucketStore.IndexCache, logger, reg); err != nil {
 		return nil, errors.Wrap(err, "create index cache")
 	}
 
+	for _, s := range []services.Service{chunksCacheService, indexCacheService} {
+		if s != nil {
+			u.cachesServices = append(u.cachesServices, s)
+		}
+	}
+
 	chunksCache, err := chunkscache.NewChunksCache(logger, chunksCacheClient, reg)
 	if err != nil {
 		return nil, errors.Wrap(err, "create chunks cache")
@@ -361,6 +387,24 @@ func (u *BucketStores) LabelValues(ctx context.Context, req *storepb.LabelValues
 	return store.LabelValues(ctx, req)
 }
 
+// Exemplars implements the storegatewaypb.StoreGatewayServer interface.
+func (u *BucketStores) Exemplars(ctx context.Context, req *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	spanLog, spanCtx := spanlogger.NewWithLogger(ctx, u.logger, "BucketStores.Exemplars")
+	defer spanLog.Span.Finish()
+
+	userID := getUserIDFromGRPCContext(spanCtx)
+	if userID == "" {
+		return nil, fmt.Errorf("no userID")
+	}
+
+	store := u.getStore(userID)
+	if store == nil {
+		return &storepb.ExemplarsResponse{}, nil
+	}
+
+	return store.Exemplars(ctx, req)
+}
+
 // scanUsers in the bucket and return the list of found users. If an error occurs while
 // iterating the bucket, it may return both an error and a subset of the users in the bucket.
 func (u *BucketStores) scanUsers(ctx context.Context) ([]string, error) {
@@ -474,7 +518,9 @@ func (u *BucketStores) getOrCreateStore(userID string) (*BucketStore, error) {
 		WithChunksCache(u.chunksCache),
 		WithQueryGate(u.queryGate),
 		WithLazyLoadingGate(u.lazyLoadingGate),
+		WithIndexHeaderLoadedBudget(u.indexHeaderLoadedBudget),
 		WithFineGrainedChunksCaching(u.cfg.BucketStore.ChunksCache.FineGrainedChunksCachingEnabled),
+		WithLabelBloomFilter(u.cfg.BucketStore.LabelBloomFilterEnabled),
 	}
 
 	bs, err := NewBucketStore(
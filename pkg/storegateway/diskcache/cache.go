@@ -0,0 +1,567 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package diskcache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/units"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/cache"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// Each item is stored in its own file, made of a fixed size header followed by the item key and value.
+	// The header contains: magic (4 bytes), CRC32 Castagnoli checksum of the rest of the file (4 bytes),
+	// expiration unix timestamp in milliseconds (8 bytes, 0 means no expiration) and key length (4 bytes).
+	fileMagic  = "MDC1"
+	headerSize = 4 + 4 + 8 + 4
+
+	// Items are first written to a temporary file, which is atomically renamed once fully written.
+	tmpFileSuffix = ".tmp"
+)
+
+var (
+	castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+	ErrMissingDir         = errors.New("the disk cache directory is required")
+	ErrInvalidMaxSize     = errors.New("the disk cache max size must be greater than 0")
+	ErrInvalidConcurrency = errors.New("the disk cache max async concurrency must be greater than 0")
+	ErrInvalidBufferSize  = errors.New("the disk cache max async buffer size must be greater than 0")
+	errAsyncBufferFull    = errors.New("the disk cache async buffer is full")
+	errCorruptedItem      = errors.New("corrupted disk cache item")
+
+	_ cache.Cache             = (*Cache)(nil)
+	_ cache.RemoteCacheClient = (*Cache)(nil)
+)
+
+// Config holds the disk cache configuration.
+type Config struct {
+	Dir                 string `yaml:"dir" category:"experimental"`
+	MaxSizeBytes        uint64 `yaml:"max_size_bytes" category:"experimental"`
+	MaxAsyncConcurrency int    `yaml:"max_async_concurrency" category:"experimental"`
+	MaxAsyncBufferSize  int    `yaml:"max_async_buffer_size" category:"experimental"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
+	f.StringVar(&cfg.Dir, prefix+"dir", "", "Directory where the disk cache stores the cached items. The directory must be used exclusively by this cache. Items found in the directory at startup are re-used.")
+	f.Uint64Var(&cfg.MaxSizeBytes, prefix+"max-size-bytes", uint64(10*units.Gibibyte), "Maximum size in bytes of the items stored in the disk cache. Once the limit is reached, the least recently used items are evicted.")
+	f.IntVar(&cfg.MaxAsyncConcurrency, prefix+"max-async-concurrency", 10, "The maximum number of concurrent asynchronous writes to the disk cache.")
+	f.IntVar(&cfg.MaxAsyncBufferSize, prefix+"max-async-buffer-size", 25000, "The maximum number of enqueued asynchronous writes to the disk cache. Writes are skipped when the queue is full.")
+}
+
+// Validate the config.
+func (cfg *Config) Validate() error {
+	if cfg.Dir == "" {
+		return ErrMissingDir
+	}
+	if cfg.MaxSizeBytes == 0 {
+		return ErrInvalidMaxSize
+	}
+	if cfg.MaxAsyncConcurrency <= 0 {
+		return ErrInvalidConcurrency
+	}
+	if cfg.MaxAsyncBufferSize <= 0 {
+		return ErrInvalidBufferSize
+	}
+	return nil
+}
+
+type entry struct {
+	key       string
+	size      uint64
+	expiresAt int64 // Unix timestamp in milliseconds, 0 means no expiration.
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return e.expiresAt > 0 && e.expiresAt <= now.UnixMilli()
+}
+
+type writeOp struct {
+	key       string
+	value     []byte
+	expiresAt int64
+}
+
+// Cache is a disk-backed cache with byte-based LRU eviction. Each item is stored in its own file,
+// checksummed and atomically renamed once fully written, so that partially written or corrupted
+// items are never returned. The in-memory index of the items is rebuilt from the directory at startup.
+//
+// Cache implements both cache.Cache and cache.RemoteCacheClient, so that it can be used as a backend
+// wherever a remote cache can. Memory allocators passed to Fetch() are not used: fetched values are
+// always allocated on the heap, so it's safe to retain them.
+type Cache struct {
+	name     string
+	dir      string
+	maxSize  uint64
+	itemType func(key string) string
+	logger   log.Logger
+
+	mtx     sync.Mutex
+	lru     *list.List // The front is the most recently used entry.
+	entries map[string]*list.Element
+	size    uint64
+
+	queue   chan writeOp
+	stop    chan struct{}
+	workers sync.WaitGroup
+
+	// Metrics.
+	hits          *prometheus.CounterVec
+	misses        *prometheus.CounterVec
+	evictions     *prometheus.CounterVec
+	corrupted     prometheus.Counter
+	droppedWrites prometheus.Counter
+	failedWrites  prometheus.Counter
+}
+
+// NewCache makes a new Cache, loading the items already stored in the configured directory.
+// The itemType function is used to classify the items in the metrics, and can be nil.
+func NewCache(name string, cfg Config, itemType func(key string) string, logger log.Logger, reg prometheus.Registerer) (*Cache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if itemType == nil {
+		itemType = func(string) string { return "" }
+	}
+
+	c := &Cache{
+		name:     name,
+		dir:      cfg.Dir,
+		maxSize:  cfg.MaxSizeBytes,
+		itemType: itemType,
+		logger:   log.With(logger, "cache", name),
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+		queue:    make(chan writeOp, cfg.MaxAsyncBufferSize),
+		stop:     make(chan struct{}),
+	}
+
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return nil, errors.Wrap(err, "create disk cache directory")
+	}
+	if err := c.load(); err != nil {
+		return nil, errors.Wrap(err, "load disk cache items")
+	}
+
+	constLabels := prometheus.Labels{"name": name}
+	c.hits = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Name:        "cortex_bucket_store_disk_cache_hits_total",
+		Help:        "Total number of items requested to the disk cache that were a hit.",
+		ConstLabels: constLabels,
+	}, []string{"item_type"})
+	c.misses = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Name:        "cortex_bucket_store_disk_cache_misses_total",
+		Help:        "Total number of items requested to the disk cache that were a miss.",
+		ConstLabels: constLabels,
+	}, []string{"item_type"})
+	c.evictions = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Name:        "cortex_bucket_store_disk_cache_evictions_total",
+		Help:        "Total number of items evicted from the disk cache.",
+		ConstLabels: constLabels,
+	}, []string{"item_type"})
+	c.corrupted = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name:        "cortex_bucket_store_disk_cache_corrupted_items_total",
+		Help:        "Total number of items read from the disk cache that failed the checksum verification, and have been removed.",
+		ConstLabels: constLabels,
+	})
+	c.droppedWrites = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name:        "cortex_bucket_store_disk_cache_dropped_writes_total",
+		Help:        "Total number of items not written to the disk cache because the async buffer was full.",
+		ConstLabels: constLabels,
+	})
+	c.failedWrites = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name:        "cortex_bucket_store_disk_cache_failed_writes_total",
+		Help:        "Total number of items that failed to be written to the disk cache.",
+		ConstLabels: constLabels,
+	})
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "cortex_bucket_store_disk_cache_items",
+		Help:        "Current number of items in the disk cache.",
+		ConstLabels: constLabels,
+	}, func() float64 {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		return float64(c.lru.Len())
+	})
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "cortex_bucket_store_disk_cache_size_bytes",
+		Help:        "Current size in bytes of the items in the disk cache.",
+		ConstLabels: constLabels,
+	}, func() float64 {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		return float64(c.size)
+	})
+
+	for i := 0; i < cfg.MaxAsyncConcurrency; i++ {
+		c.workers.Add(1)
+		go c.asyncWriteLoop()
+	}
+
+	level.Info(c.logger).Log("msg", "created disk cache", "dir", c.dir, "items", c.lru.Len(), "size", c.size)
+	return c, nil
+}
+
+// Name implements cache.Cache.
+func (c *Cache) Name() string {
+	return c.name
+}
+
+// Stop stops the asynchronous writes. Enqueued writes not processed yet are discarded.
+func (c *Cache) Stop() {
+	close(c.stop)
+	c.workers.Wait()
+}
+
+// StoreAsync implements cache.Cache.
+func (c *Cache) StoreAsync(data map[string][]byte, ttl time.Duration) {
+	for key, value := range data {
+		_ = c.SetAsync(key, value, ttl)
+	}
+}
+
+// SetAsync implements cache.RemoteCacheClient.
+func (c *Cache) SetAsync(key string, value []byte, ttl time.Duration) error {
+	op := writeOp{key: key, value: value}
+	if ttl > 0 {
+		op.expiresAt = time.Now().Add(ttl).UnixMilli()
+	}
+
+	select {
+	case c.queue <- op:
+		return nil
+	default:
+		c.droppedWrites.Inc()
+		return errAsyncBufferFull
+	}
+}
+
+// Fetch implements cache.Cache.
+func (c *Cache) Fetch(ctx context.Context, keys []string, _ ...cache.Option) map[string][]byte {
+	hits := map[string][]byte{}
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if value, ok := c.get(key); ok {
+			hits[key] = value
+			c.hits.WithLabelValues(c.itemType(key)).Inc()
+		} else {
+			c.misses.WithLabelValues(c.itemType(key)).Inc()
+		}
+	}
+	return hits
+}
+
+// GetMulti implements cache.RemoteCacheClient.
+func (c *Cache) GetMulti(ctx context.Context, keys []string, opts ...cache.Option) map[string][]byte {
+	return c.Fetch(ctx, keys, opts...)
+}
+
+// Delete implements cache.Cache and cache.RemoteCacheClient.
+func (c *Cache) Delete(_ context.Context, key string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		return c.removeLocked(elem)
+	}
+	return nil
+}
+
+func (c *Cache) get(key string) ([]byte, bool) {
+	c.mtx.Lock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.mtx.Unlock()
+		return nil, false
+	}
+	if elem.Value.(*entry).expired(time.Now()) {
+		_ = c.removeLocked(elem)
+		c.mtx.Unlock()
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	c.mtx.Unlock()
+
+	// The file is read without holding the lock. If the item is concurrently evicted or
+	// replaced, the file is either not found or we read a previous, consistent, version of it.
+	data, err := os.ReadFile(c.path(key))
+	if err == nil {
+		var value []byte
+		if value, err = decodeItem(data, key); err == nil {
+			return value, true
+		}
+		c.corrupted.Inc()
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		level.Warn(c.logger).Log("msg", "removing unreadable item from the disk cache", "key", key, "err", err)
+	}
+
+	c.mtx.Lock()
+	if c.entries[key] == elem {
+		_ = c.removeLocked(elem)
+	}
+	c.mtx.Unlock()
+	return nil, false
+}
+
+func (c *Cache) asyncWriteLoop() {
+	defer c.workers.Done()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case op := <-c.queue:
+			if err := c.set(op.key, op.value, op.expiresAt); err != nil {
+				c.failedWrites.Inc()
+				level.Warn(c.logger).Log("msg", "failed to write item to the disk cache", "key", op.key, "err", err)
+			}
+		}
+	}
+}
+
+func (c *Cache) set(key string, value []byte, expiresAt int64) error {
+	size := uint64(headerSize + len(key) + len(value))
+	if size > c.maxSize {
+		// The item would evict the whole cache.
+		return nil
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*"+tmpFileSuffix)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	header := encodeHeader(key, value, expiresAt)
+	_, err = tmp.Write(header)
+	if err == nil {
+		_, err = tmp.Write(value)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		c.size -= e.size
+		e.size = size
+		e.expiresAt = expiresAt
+		c.lru.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.lru.PushFront(&entry{key: key, size: size, expiresAt: expiresAt})
+	}
+	c.size += size
+
+	c.evictLocked()
+	return nil
+}
+
+// evictLocked removes the least recently used items until the cache size is within the limit.
+// Must be called with the lock held.
+func (c *Cache) evictLocked() {
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+
+		key := elem.Value.(*entry).key
+		if err := c.removeLocked(elem); err != nil {
+			level.Warn(c.logger).Log("msg", "failed to remove evicted item from the disk cache", "key", key, "err", err)
+		}
+		c.evictions.WithLabelValues(c.itemType(key)).Inc()
+	}
+}
+
+// removeLocked removes the item from the index and its file. Must be called with the lock held.
+func (c *Cache) removeLocked(elem *list.Element) error {
+	e := elem.Value.(*entry)
+	c.lru.Remove(elem)
+	delete(c.entries, e.key)
+	c.size -= e.size
+
+	if err := os.Remove(c.path(e.key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// load rebuilds the index from the items stored in the directory. Leftover temporary files,
+// unreadable and expired items are removed. The items checksum is verified when they're read.
+func (c *Cache) load() error {
+	type loadedEntry struct {
+		entry
+		modTime time.Time
+	}
+
+	var (
+		loaded []loadedEntry
+		now    = time.Now()
+	)
+
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		if strings.HasSuffix(path, tmpFileSuffix) {
+			return os.Remove(path)
+		}
+
+		key, expiresAt, err := readItemHeader(path)
+		if err != nil || c.path(key) != path || (expiresAt > 0 && expiresAt <= now.UnixMilli()) {
+			return os.Remove(path)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		loaded = append(loaded, loadedEntry{
+			entry:   entry{key: key, size: uint64(info.Size()), expiresAt: expiresAt},
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Consider the most recently written items as the most recently used.
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].modTime.Before(loaded[j].modTime)
+	})
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, l := range loaded {
+		e := l.entry
+		c.entries[e.key] = c.lru.PushFront(&e)
+		c.size += e.size
+	}
+
+	// The max size may have been lowered since the items were written.
+	for c.size > c.maxSize {
+		if err := c.removeLocked(c.lru.Back()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// path returns the path of the file storing the item with the given key. Files are spread
+// across 256 sub-directories, to keep the number of files per directory reasonable.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+// encodeHeader returns the header of the item file, followed by the key.
+func encodeHeader(key string, value []byte, expiresAt int64) []byte {
+	buf := make([]byte, headerSize+len(key))
+	copy(buf, fileMagic)
+	binary.BigEndian.PutUint64(buf[8:], uint64(expiresAt))
+	binary.BigEndian.PutUint32(buf[16:], uint32(len(key)))
+	copy(buf[headerSize:], key)
+
+	crc := crc32.Update(0, castagnoliTable, buf[8:])
+	crc = crc32.Update(crc, castagnoliTable, value)
+	binary.BigEndian.PutUint32(buf[4:], crc)
+	return buf
+}
+
+// decodeItem verifies the item file content and returns the item value.
+func decodeItem(data []byte, key string) ([]byte, error) {
+	if len(data) < headerSize || string(data[:4]) != fileMagic {
+		return nil, errCorruptedItem
+	}
+	if crc32.Checksum(data[8:], castagnoliTable) != binary.BigEndian.Uint32(data[4:]) {
+		return nil, errors.Wrap(errCorruptedItem, "checksum mismatch")
+	}
+
+	keyLen := int(binary.BigEndian.Uint32(data[16:]))
+	if len(data) < headerSize+keyLen || string(data[headerSize:headerSize+keyLen]) != key {
+		return nil, errors.Wrap(errCorruptedItem, "key mismatch")
+	}
+	return data[headerSize+keyLen:], nil
+}
+
+// readItemHeader reads the key and expiration of the item stored in the input file.
+func readItemHeader(path string) (key string, expiresAt int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return "", 0, err
+	}
+	if string(header[:4]) != fileMagic {
+		return "", 0, errCorruptedItem
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	keyLen := int64(binary.BigEndian.Uint32(header[16:]))
+	if headerSize+keyLen > info.Size() {
+		return "", 0, errCorruptedItem
+	}
+
+	keyBytes := make([]byte, keyLen)
+	if _, err := io.ReadFull(f, keyBytes); err != nil {
+		return "", 0, err
+	}
+	return string(keyBytes), int64(binary.BigEndian.Uint64(header[8:])), nil
+}
@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package diskcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/test"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_StoreAndFetch(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	c := newTestCache(t, t.TempDir(), 1024, reg)
+
+	c.StoreAsync(map[string][]byte{
+		"a:1": []byte("value-1"),
+		"b:2": []byte("value-2"),
+		"b:3": {},
+	}, time.Hour)
+	waitItems(t, c, 3)
+
+	ctx := context.Background()
+	assert.Equal(t, map[string][]byte{
+		"a:1": []byte("value-1"),
+		"b:2": []byte("value-2"),
+		"b:3": {},
+	}, c.Fetch(ctx, []string{"a:1", "b:2", "b:3", "b:4"}))
+
+	require.NoError(t, c.Delete(ctx, "a:1"))
+	assert.Equal(t, map[string][]byte{"b:2": []byte("value-2")}, c.GetMulti(ctx, []string{"a:1", "b:2"}))
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_bucket_store_disk_cache_hits_total Total number of items requested to the disk cache that were a hit.
+		# TYPE cortex_bucket_store_disk_cache_hits_total counter
+		cortex_bucket_store_disk_cache_hits_total{item_type="a",name="test"} 1
+		cortex_bucket_store_disk_cache_hits_total{item_type="b",name="test"} 3
+
+		# HELP cortex_bucket_store_disk_cache_misses_total Total number of items requested to the disk cache that were a miss.
+		# TYPE cortex_bucket_store_disk_cache_misses_total counter
+		cortex_bucket_store_disk_cache_misses_total{item_type="a",name="test"} 1
+		cortex_bucket_store_disk_cache_misses_total{item_type="b",name="test"} 1
+
+		# HELP cortex_bucket_store_disk_cache_items Current number of items in the disk cache.
+		# TYPE cortex_bucket_store_disk_cache_items gauge
+		cortex_bucket_store_disk_cache_items{name="test"} 2
+	`), "cortex_bucket_store_disk_cache_hits_total", "cortex_bucket_store_disk_cache_misses_total", "cortex_bucket_store_disk_cache_items"))
+}
+
+func TestCache_ShouldEvictLeastRecentlyUsedItems(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	value := make([]byte, 100)
+	itemSize := uint64(headerSize + len("a:1") + len(value))
+
+	// The cache fits 3 items.
+	c := newTestCache(t, t.TempDir(), 3*itemSize, reg)
+	ctx := context.Background()
+
+	for _, key := range []string{"a:1", "a:2", "a:3"} {
+		require.NoError(t, c.set(key, value, 0))
+	}
+
+	// Use the first item, so that the second one is the least recently used.
+	require.Len(t, c.Fetch(ctx, []string{"a:1"}), 1)
+	require.NoError(t, c.set("a:4", value, 0))
+
+	assert.Equal(t, []string{"a:1", "a:3", "a:4"}, sortedKeys(c.Fetch(ctx, []string{"a:1", "a:2", "a:3", "a:4"})))
+	assert.Equal(t, 3*itemSize, c.size)
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.evictions.WithLabelValues("a")))
+
+	// The file of the evicted item should have been removed.
+	_, err := os.Stat(c.path("a:2"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Items bigger than the whole cache are not stored.
+	require.NoError(t, c.set("a:5", make([]byte, 3*itemSize), 0))
+	assert.Empty(t, c.Fetch(ctx, []string{"a:5"}))
+	assert.Len(t, c.Fetch(ctx, []string{"a:1", "a:3", "a:4"}), 3)
+}
+
+func TestCache_ShouldNotReturnExpiredItems(t *testing.T) {
+	c := newTestCache(t, t.TempDir(), 1024, nil)
+	ctx := context.Background()
+
+	require.NoError(t, c.set("a:1", []byte("expired"), time.Now().Add(-time.Second).UnixMilli()))
+	require.NoError(t, c.set("a:2", []byte("valid"), time.Now().Add(time.Hour).UnixMilli()))
+
+	assert.Equal(t, map[string][]byte{"a:2": []byte("valid")}, c.Fetch(ctx, []string{"a:1", "a:2"}))
+
+	_, err := os.Stat(c.path("a:1"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCache_ShouldRemoveCorruptedItems(t *testing.T) {
+	c := newTestCache(t, t.TempDir(), 1024, nil)
+	ctx := context.Background()
+
+	require.NoError(t, c.set("a:1", []byte("value-1"), 0))
+	require.NoError(t, c.set("a:2", []byte("value-2"), 0))
+
+	// Flip a byte of the first item value.
+	data, err := os.ReadFile(c.path("a:1"))
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(c.path("a:1"), data, 0o644))
+
+	assert.Equal(t, map[string][]byte{"a:2": []byte("value-2")}, c.Fetch(ctx, []string{"a:1", "a:2"}))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.corrupted))
+
+	_, err = os.Stat(c.path("a:1"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, c.Fetch(ctx, []string{"a:1"}))
+}
+
+func TestCache_ShouldReloadItemsAtStartup(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	value := make([]byte, 100)
+	itemSize := uint64(headerSize + len("a:1") + len(value))
+
+	c := newTestCache(t, dir, 1024, nil)
+	for _, key := range []string{"a:1", "a:2", "a:3"} {
+		require.NoError(t, c.set(key, value, 0))
+
+		// Ensure items have different modification times, used to rebuild the LRU order.
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, c.set("a:4", value, time.Now().Add(-time.Second).UnixMilli()))
+	c.Stop()
+
+	// Write a leftover temporary file and a file which is not a cache item.
+	tmpFile := filepath.Join(dir, "00", "leftover"+tmpFileSuffix)
+	require.NoError(t, os.MkdirAll(filepath.Dir(tmpFile), os.ModePerm))
+	require.NoError(t, os.WriteFile(tmpFile, []byte("partial"), 0o644))
+	invalidFile := filepath.Join(dir, "00", "invalid")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("invalid"), 0o644))
+
+	// Reopen the cache with a lower max size, so that the oldest item is evicted.
+	c = newTestCache(t, dir, 2*itemSize, nil)
+	assert.Equal(t, []string{"a:2", "a:3"}, sortedKeys(c.Fetch(ctx, []string{"a:1", "a:2", "a:3", "a:4"})))
+
+	for _, path := range []string{tmpFile, invalidFile, c.path("a:1"), c.path("a:4")} {
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err), path)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{Dir: "/tmp", MaxSizeBytes: 1, MaxAsyncConcurrency: 1, MaxAsyncBufferSize: 1}
+	assert.NoError(t, valid.Validate())
+
+	tests := map[string]struct {
+		setup    func(cfg *Config)
+		expected error
+	}{
+		"missing dir":                {setup: func(cfg *Config) { cfg.Dir = "" }, expected: ErrMissingDir},
+		"zero max size":              {setup: func(cfg *Config) { cfg.MaxSizeBytes = 0 }, expected: ErrInvalidMaxSize},
+		"zero max async concurrency": {setup: func(cfg *Config) { cfg.MaxAsyncConcurrency = 0 }, expected: ErrInvalidConcurrency},
+		"zero max async buffer size": {setup: func(cfg *Config) { cfg.MaxAsyncBufferSize = 0 }, expected: ErrInvalidBufferSize},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := valid
+			testData.setup(&cfg)
+			assert.Equal(t, testData.expected, cfg.Validate())
+		})
+	}
+}
+
+func newTestCache(t *testing.T, dir string, maxSize uint64, reg prometheus.Registerer) *Cache {
+	itemType := func(key string) string {
+		return key[:strings.Index(key, ":")]
+	}
+
+	c, err := NewCache("test", Config{Dir: dir, MaxSizeBytes: maxSize, MaxAsyncConcurrency: 2, MaxAsyncBufferSize: 10}, itemType, log.NewNopLogger(), reg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		select {
+		case <-c.stop:
+		default:
+			c.Stop()
+		}
+	})
+	return c
+}
+
+func waitItems(t *testing.T, c *Cache, expected int) {
+	test.Poll(t, time.Second, expected, func() interface{} {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		return c.lru.Len()
+	})
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/go-kit/log"
@@ -20,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/thanos-io/objstore"
+	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/tracing"
 
 	"github.com/grafana/mimir/pkg/storage/bucket"
@@ -28,6 +30,8 @@ import (
 	"github.com/grafana/mimir/pkg/storegateway/storepb"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
+	"github.com/grafana/mimir/pkg/util/globalerror"
+	"github.com/grafana/mimir/pkg/util/limiter"
 	"github.com/grafana/mimir/pkg/util/validation"
 )
 
@@ -44,16 +48,28 @@ const (
 var (
 	// Validation errors.
 	errInvalidTenantShardSize = errors.New("invalid tenant shard size, the value must be greater or equal to 0")
+
+	// This is the closest fitting Prometheus API error code for requests rejected due to limiting.
+	tooBusyError = httpgrpc.Errorf(http.StatusServiceUnavailable,
+		globalerror.StoreGatewayTooBusy.Message("the store-gateway is currently too busy to process queries, try again later"))
 )
 
 // Config holds the store gateway config.
 type Config struct {
 	ShardingRing RingConfig `yaml:"sharding_ring" doc:"description=The hash ring configuration."`
+
+	ReadPathCPUUtilizationLimit          float64 `yaml:"read_path_cpu_utilization_limit" category:"experimental"`
+	ReadPathMemoryUtilizationLimit       uint64  `yaml:"read_path_memory_utilization_limit" category:"experimental"`
+	LogUtilizationBasedLimiterCPUSamples bool    `yaml:"log_utilization_based_limiter_cpu_samples" category:"experimental"`
 }
 
 // RegisterFlags registers the Config flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	cfg.ShardingRing.RegisterFlags(f, logger)
+
+	f.Float64Var(&cfg.ReadPathCPUUtilizationLimit, "store-gateway.read-path-cpu-utilization-limit", 0, "CPU utilization limit, as CPU cores, for CPU/memory utilization based read request limiting. Rejected requests are retried by queriers on another store-gateway replica. Use 0 to disable it.")
+	f.Uint64Var(&cfg.ReadPathMemoryUtilizationLimit, "store-gateway.read-path-memory-utilization-limit", 0, "Memory limit, in bytes, for CPU/memory utilization based read request limiting. Rejected requests are retried by queriers on another store-gateway replica. Use 0 to disable it.")
+	f.BoolVar(&cfg.LogUtilizationBasedLimiterCPUSamples, "store-gateway.log-utilization-based-limiter-cpu-samples", false, "Enable logging of utilization based limiter CPU samples.")
 }
 
 // Validate the Config.
@@ -88,6 +104,15 @@ type StoreGateway struct {
 	bucketSync *prometheus.CounterVec
 	// Shutdown marker for store-gateway scale down
 	shutdownMarker prometheus.Gauge
+
+	utilizationBasedLimiter    utilizationBasedLimiter
+	utilizationLimitedRequests *prometheus.CounterVec
+}
+
+type utilizationBasedLimiter interface {
+	services.Service
+
+	LimitingReason() string
 }
 
 func NewStoreGateway(gatewayCfg Config, storageCfg mimir_tsdb.BlocksStorageConfig, limits *validation.Overrides, logger log.Logger, reg prometheus.Registerer, tracker *activitytracker.ActivityTracker) (*StoreGateway, error) {
@@ -127,6 +152,10 @@ func newStoreGateway(gatewayCfg Config, storageCfg mimir_tsdb.BlocksStorageConfi
 			Name: "cortex_storegateway_prepare_shutdown_requested",
 			Help: "If the store-gateway has been requested to prepare for shutdown via endpoint or marker file.",
 		}),
+		utilizationLimitedRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_storegateway_utilization_limited_read_requests_total",
+			Help: "Total number of times read requests have been rejected due to utilization based limiting.",
+		}, []string{"reason"}),
 	}
 
 	// Init metrics.
@@ -167,6 +196,13 @@ func newStoreGateway(gatewayCfg Config, storageCfg mimir_tsdb.BlocksStorageConfi
 		return nil, errors.Wrap(err, "create bucket stores")
 	}
 
+	if gatewayCfg.ReadPathCPUUtilizationLimit > 0 || gatewayCfg.ReadPathMemoryUtilizationLimit > 0 {
+		g.utilizationBasedLimiter = limiter.NewUtilizationBasedLimiter(gatewayCfg.ReadPathCPUUtilizationLimit,
+			gatewayCfg.ReadPathMemoryUtilizationLimit, 0, gatewayCfg.LogUtilizationBasedLimiterCPUSamples,
+			log.WithPrefix(logger, "context", "utilization based limiter"),
+			prometheus.WrapRegistererWithPrefix("cortex_storegateway_", reg))
+	}
+
 	g.Service = services.NewBasicService(g.starting, g.running, g.stopping)
 
 	return g, nil
@@ -193,7 +229,13 @@ func (g *StoreGateway) starting(ctx context.Context) (err error) {
 
 	// First of all we register the instance in the ring and wait
 	// until the lifecycler successfully started.
-	if g.subservices, err = services.NewManager(g.ringLifecycler, g.ring); err != nil {
+	servs := []services.Service{g.ringLifecycler, g.ring}
+	if g.utilizationBasedLimiter != nil {
+		servs = append(servs, g.utilizationBasedLimiter)
+	}
+	servs = append(servs, g.stores.cachesServices...)
+
+	if g.subservices, err = services.NewManager(servs...); err != nil {
 		return errors.Wrap(err, "unable to start store-gateway dependencies")
 	}
 
@@ -315,6 +357,10 @@ func (g *StoreGateway) Series(req *storepb.SeriesRequest, srv storegatewaypb.Sto
 	})
 	defer g.tracker.Delete(ix)
 
+	if err := g.checkReadOverloaded(); err != nil {
+		return err
+	}
+
 	return g.stores.Series(req, srv)
 }
 
@@ -325,6 +371,10 @@ func (g *StoreGateway) LabelNames(ctx context.Context, req *storepb.LabelNamesRe
 	})
 	defer g.tracker.Delete(ix)
 
+	if err := g.checkReadOverloaded(); err != nil {
+		return nil, err
+	}
+
 	return g.stores.LabelNames(ctx, req)
 }
 
@@ -335,9 +385,42 @@ func (g *StoreGateway) LabelValues(ctx context.Context, req *storepb.LabelValues
 	})
 	defer g.tracker.Delete(ix)
 
+	if err := g.checkReadOverloaded(); err != nil {
+		return nil, err
+	}
+
 	return g.stores.LabelValues(ctx, req)
 }
 
+// Exemplars implements the storegatewaypb.StoreGatewayServer interface.
+func (g *StoreGateway) Exemplars(ctx context.Context, req *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	ix := g.tracker.Insert(func() string {
+		return requestActivity(ctx, "StoreGateway/Exemplars", req)
+	})
+	defer g.tracker.Delete(ix)
+
+	if err := g.checkReadOverloaded(); err != nil {
+		return nil, err
+	}
+
+	return g.stores.Exemplars(ctx, req)
+}
+
+// checkReadOverloaded checks whether the store-gateway read path is overloaded wrt. CPU and/or memory.
+func (g *StoreGateway) checkReadOverloaded() error {
+	if g.utilizationBasedLimiter == nil {
+		return nil
+	}
+
+	reason := g.utilizationBasedLimiter.LimitingReason()
+	if reason == "" {
+		return nil
+	}
+
+	g.utilizationLimitedRequests.WithLabelValues(reason).Inc()
+	return tooBusyError
+}
+
 func requestActivity(ctx context.Context, name string, req interface{}) string {
 	user := getUserIDFromGRPCContext(ctx)
 	traceID, _ := tracing.ExtractSampledTraceID(ctx)
@@ -41,6 +41,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
+	"github.com/weaveworks/common/httpgrpc"
 	"google.golang.org/grpc/status"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
@@ -53,6 +54,7 @@ import (
 	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
 	"github.com/grafana/mimir/pkg/storegateway/storepb"
 	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/globalerror"
 	"github.com/grafana/mimir/pkg/util/test"
 	"github.com/grafana/mimir/pkg/util/validation"
 )
@@ -1487,6 +1489,50 @@ func TestStoreGateway_SeriesQueryingShouldEnforceMaxChunksPerQueryLimit(t *testi
 	}
 }
 
+func TestStoreGateway_ShouldRejectReadRequestsWhenOverloaded(t *testing.T) {
+	ctx := setUserIDToGRPCContext(context.Background(), "user-1")
+
+	bucketClient, _ := mimir_testutil.PrepareFilesystemBucket(t)
+	ringStore, closer := consul.NewInMemoryClient(ring.GetCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { assert.NoError(t, closer.Close()) })
+
+	reg := prometheus.NewPedanticRegistry()
+	g, err := newStoreGateway(mockGatewayConfig(), mockStorageConfig(t), bucketClient, ringStore, defaultLimitsOverrides(t), log.NewNopLogger(), reg, nil)
+	require.NoError(t, err)
+	g.utilizationBasedLimiter = &fakeUtilizationBasedLimiter{limitingReason: "memory"}
+
+	srv := newStoreGatewayTestServer(t, g)
+
+	_, _, _, err = srv.Series(ctx, &storepb.SeriesRequest{MinTime: math.MinInt64, MaxTime: math.MaxInt64})
+	require.Error(t, err)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, int(resp.Code))
+	assert.True(t, globalerror.StoreGatewayTooBusy.IsInMessage(string(resp.Body)))
+
+	_, err = g.LabelNames(ctx, &storepb.LabelNamesRequest{})
+	require.Equal(t, tooBusyError, err)
+
+	_, err = g.LabelValues(ctx, &storepb.LabelValuesRequest{Label: labels.MetricName})
+	require.Equal(t, tooBusyError, err)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_storegateway_utilization_limited_read_requests_total Total number of times read requests have been rejected due to utilization based limiting.
+		# TYPE cortex_storegateway_utilization_limited_read_requests_total counter
+		cortex_storegateway_utilization_limited_read_requests_total{reason="memory"} 3
+	`), "cortex_storegateway_utilization_limited_read_requests_total"))
+}
+
+type fakeUtilizationBasedLimiter struct {
+	services.BasicService
+
+	limitingReason string
+}
+
+func (l *fakeUtilizationBasedLimiter) LimitingReason() string {
+	return l.limitingReason
+}
+
 func mockGatewayConfig() Config {
 	cfg := Config{}
 	flagext.DefaultValues(&cfg)
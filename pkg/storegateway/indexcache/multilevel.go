@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package indexcache
+
+import (
+	"context"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/mimir/pkg/storage/sharding"
+)
+
+// MultiLevelIndexCache is an IndexCache looking up items in a faster L1 cache first
+// and then in a slower L2 cache. Items found in the L2 cache are backfilled to the L1.
+type MultiLevelIndexCache struct {
+	l1 IndexCache
+	l2 IndexCache
+}
+
+// NewMultiLevelIndexCache makes a new MultiLevelIndexCache.
+func NewMultiLevelIndexCache(l1, l2 IndexCache) *MultiLevelIndexCache {
+	return &MultiLevelIndexCache{l1: l1, l2: l2}
+}
+
+func (c *MultiLevelIndexCache) StorePostings(userID string, blockID ulid.ULID, l labels.Label, v []byte) {
+	c.l1.StorePostings(userID, blockID, l, v)
+	c.l2.StorePostings(userID, blockID, l, v)
+}
+
+func (c *MultiLevelIndexCache) FetchMultiPostings(ctx context.Context, userID string, blockID ulid.ULID, keys []labels.Label) BytesResult {
+	hits := make(map[labels.Label][]byte, len(keys))
+	var misses []labels.Label
+
+	l1Result := c.l1.FetchMultiPostings(ctx, userID, blockID, keys)
+	for i := 0; ; i++ {
+		b, ok := l1Result.Next()
+		if !ok {
+			break
+		}
+		if b != nil {
+			hits[keys[i]] = b
+		} else {
+			misses = append(misses, keys[i])
+		}
+	}
+
+	if len(misses) > 0 {
+		l2Result := c.l2.FetchMultiPostings(ctx, userID, blockID, misses)
+		for i := 0; ; i++ {
+			b, ok := l2Result.Next()
+			if !ok {
+				break
+			}
+			if b != nil {
+				hits[misses[i]] = b
+				c.l1.StorePostings(userID, blockID, misses[i], b)
+			}
+		}
+	}
+
+	return &MapIterator[labels.Label]{
+		Keys: keys,
+		M:    hits,
+	}
+}
+
+func (c *MultiLevelIndexCache) StoreSeriesForRef(userID string, blockID ulid.ULID, id storage.SeriesRef, v []byte) {
+	c.l1.StoreSeriesForRef(userID, blockID, id, v)
+	c.l2.StoreSeriesForRef(userID, blockID, id, v)
+}
+
+func (c *MultiLevelIndexCache) FetchMultiSeriesForRefs(ctx context.Context, userID string, blockID ulid.ULID, ids []storage.SeriesRef) (hits map[storage.SeriesRef][]byte, misses []storage.SeriesRef) {
+	hits, misses = c.l1.FetchMultiSeriesForRefs(ctx, userID, blockID, ids)
+	if len(misses) == 0 {
+		return hits, misses
+	}
+
+	l2Hits, l2Misses := c.l2.FetchMultiSeriesForRefs(ctx, userID, blockID, misses)
+	if hits == nil {
+		hits = make(map[storage.SeriesRef][]byte, len(l2Hits))
+	}
+	for id, b := range l2Hits {
+		hits[id] = b
+		c.l1.StoreSeriesForRef(userID, blockID, id, b)
+	}
+
+	return hits, l2Misses
+}
+
+func (c *MultiLevelIndexCache) StoreExpandedPostings(userID string, blockID ulid.ULID, key LabelMatchersKey, postingsSelectionStrategy string, v []byte) {
+	c.l1.StoreExpandedPostings(userID, blockID, key, postingsSelectionStrategy, v)
+	c.l2.StoreExpandedPostings(userID, blockID, key, postingsSelectionStrategy, v)
+}
+
+func (c *MultiLevelIndexCache) FetchExpandedPostings(ctx context.Context, userID string, blockID ulid.ULID, key LabelMatchersKey, postingsSelectionStrategy string) ([]byte, bool) {
+	if b, ok := c.l1.FetchExpandedPostings(ctx, userID, blockID, key, postingsSelectionStrategy); ok {
+		return b, true
+	}
+	b, ok := c.l2.FetchExpandedPostings(ctx, userID, blockID, key, postingsSelectionStrategy)
+	if ok {
+		c.l1.StoreExpandedPostings(userID, blockID, key, postingsSelectionStrategy, b)
+	}
+	return b, ok
+}
+
+func (c *MultiLevelIndexCache) StoreSeriesForPostings(userID string, blockID ulid.ULID, shard *sharding.ShardSelector, postingsKey PostingsKey, v []byte) {
+	c.l1.StoreSeriesForPostings(userID, blockID, shard, postingsKey, v)
+	c.l2.StoreSeriesForPostings(userID, blockID, shard, postingsKey, v)
+}
+
+func (c *MultiLevelIndexCache) FetchSeriesForPostings(ctx context.Context, userID string, blockID ulid.ULID, shard *sharding.ShardSelector, postingsKey PostingsKey) ([]byte, bool) {
+	if b, ok := c.l1.FetchSeriesForPostings(ctx, userID, blockID, shard, postingsKey); ok {
+		return b, true
+	}
+	b, ok := c.l2.FetchSeriesForPostings(ctx, userID, blockID, shard, postingsKey)
+	if ok {
+		c.l1.StoreSeriesForPostings(userID, blockID, shard, postingsKey, b)
+	}
+	return b, ok
+}
+
+func (c *MultiLevelIndexCache) StoreLabelNames(userID string, blockID ulid.ULID, matchersKey LabelMatchersKey, v []byte) {
+	c.l1.StoreLabelNames(userID, blockID, matchersKey, v)
+	c.l2.StoreLabelNames(userID, blockID, matchersKey, v)
+}
+
+func (c *MultiLevelIndexCache) FetchLabelNames(ctx context.Context, userID string, blockID ulid.ULID, matchersKey LabelMatchersKey) ([]byte, bool) {
+	if b, ok := c.l1.FetchLabelNames(ctx, userID, blockID, matchersKey); ok {
+		return b, true
+	}
+	b, ok := c.l2.FetchLabelNames(ctx, userID, blockID, matchersKey)
+	if ok {
+		c.l1.StoreLabelNames(userID, blockID, matchersKey, b)
+	}
+	return b, ok
+}
+
+func (c *MultiLevelIndexCache) StoreLabelValues(userID string, blockID ulid.ULID, labelName string, matchersKey LabelMatchersKey, v []byte) {
+	c.l1.StoreLabelValues(userID, blockID, labelName, matchersKey, v)
+	c.l2.StoreLabelValues(userID, blockID, labelName, matchersKey, v)
+}
+
+func (c *MultiLevelIndexCache) FetchLabelValues(ctx context.Context, userID string, blockID ulid.ULID, labelName string, matchersKey LabelMatchersKey) ([]byte, bool) {
+	if b, ok := c.l1.FetchLabelValues(ctx, userID, blockID, labelName, matchersKey); ok {
+		return b, true
+	}
+	b, ok := c.l2.FetchLabelValues(ctx, userID, blockID, labelName, matchersKey)
+	if ok {
+		c.l1.StoreLabelValues(userID, blockID, labelName, matchersKey, b)
+	}
+	return b, ok
+}
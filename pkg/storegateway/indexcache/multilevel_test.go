@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package indexcache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiLevelIndexCache_FetchMultiPostings(t *testing.T) {
+	ctx := context.Background()
+	userID := "tenant"
+	blockID := ulid.MustNew(1, nil)
+	label1 := labels.Label{Name: "instance", Value: "a"}
+	label2 := labels.Label{Name: "instance", Value: "b"}
+	label3 := labels.Label{Name: "instance", Value: "c"}
+
+	l1, l1Client := newTestRemoteIndexCache(t)
+	l2, l2Client := newTestRemoteIndexCache(t)
+	c := NewMultiLevelIndexCache(l1, l2)
+
+	c.StorePostings(userID, blockID, label1, []byte{1})
+	l2.StorePostings(userID, blockID, label2, []byte{2})
+
+	result := c.FetchMultiPostings(ctx, userID, blockID, []labels.Label{label1, label2, label3})
+	assert.Equal(t, 3, result.Remaining())
+
+	var actual [][]byte
+	for {
+		b, ok := result.Next()
+		if !ok {
+			break
+		}
+		actual = append(actual, b)
+	}
+	assert.Equal(t, [][]byte{{1}, {2}, nil}, actual)
+
+	// The postings found in the L2 should have been backfilled to the L1.
+	assert.Len(t, l1Client.cache, 2)
+	assert.Len(t, l2Client.cache, 2)
+}
+
+func TestMultiLevelIndexCache_FetchMultiSeriesForRefs(t *testing.T) {
+	ctx := context.Background()
+	userID := "tenant"
+	blockID := ulid.MustNew(1, nil)
+
+	l1, l1Client := newTestRemoteIndexCache(t)
+	l2, _ := newTestRemoteIndexCache(t)
+	c := NewMultiLevelIndexCache(l1, l2)
+
+	c.StoreSeriesForRef(userID, blockID, 1, []byte{1})
+	l2.StoreSeriesForRef(userID, blockID, 2, []byte{2})
+
+	hits, misses := c.FetchMultiSeriesForRefs(ctx, userID, blockID, []storage.SeriesRef{1, 2, 3, 4})
+	assert.Equal(t, map[storage.SeriesRef][]byte{1: {1}, 2: {2}}, hits)
+	assert.Equal(t, []storage.SeriesRef{3, 4}, misses)
+
+	// The series found in the L2 should have been backfilled to the L1.
+	hits, misses = l1.FetchMultiSeriesForRefs(ctx, userID, blockID, []storage.SeriesRef{1, 2})
+	assert.Len(t, hits, 2)
+	assert.Empty(t, misses)
+	assert.Len(t, l1Client.cache, 2)
+}
+
+func TestMultiLevelIndexCache_FetchExpandedPostings(t *testing.T) {
+	ctx := context.Background()
+	userID := "tenant"
+	blockID := ulid.MustNew(1, nil)
+	key1 := CanonicalLabelMatchersKey([]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "foo", "1")})
+	key2 := CanonicalLabelMatchersKey([]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "foo", "2")})
+
+	l1, _ := newTestRemoteIndexCache(t)
+	l2, _ := newTestRemoteIndexCache(t)
+	c := NewMultiLevelIndexCache(l1, l2)
+
+	l2.StoreExpandedPostings(userID, blockID, key1, "all", []byte{1})
+
+	_, ok := l1.FetchExpandedPostings(ctx, userID, blockID, key1, "all")
+	require.False(t, ok)
+
+	b, ok := c.FetchExpandedPostings(ctx, userID, blockID, key1, "all")
+	require.True(t, ok)
+	assert.Equal(t, []byte{1}, b)
+
+	// The item found in the L2 should have been backfilled to the L1.
+	b, ok = l1.FetchExpandedPostings(ctx, userID, blockID, key1, "all")
+	require.True(t, ok)
+	assert.Equal(t, []byte{1}, b)
+
+	_, ok = c.FetchExpandedPostings(ctx, userID, blockID, key2, "all")
+	assert.False(t, ok)
+}
+
+func newTestRemoteIndexCache(t *testing.T) (*RemoteIndexCache, *mockedRemoteCacheClient) {
+	client := newMockedRemoteCacheClient(nil)
+	c, err := NewRemoteIndexCache(log.NewNopLogger(), client, nil)
+	require.NoError(t, err)
+	return c, client
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
@@ -119,6 +120,29 @@ func (c *RemoteIndexCache) FetchMultiPostings(ctx context.Context, userID string
 	}
 }
 
+// CacheTypeFromKey returns the index cache item type of the input key, as built by the
+// RemoteIndexCache, or an empty string if the key doesn't belong to any known item type.
+func CacheTypeFromKey(key string) string {
+	prefix, _, _ := strings.Cut(key, ":")
+
+	switch prefix {
+	case "P2":
+		return cacheTypePostings
+	case "S":
+		return cacheTypeSeriesForRef
+	case "E2":
+		return cacheTypeExpandedPostings
+	case "SP2":
+		return cacheTypeSeriesForPostings
+	case "LN":
+		return cacheTypeLabelNames
+	case "LV2":
+		return cacheTypeLabelValues
+	default:
+		return ""
+	}
+}
+
 // postingsCacheKey returns the cache key used to store postings matching the input
 // label name/value pair in the given block.
 func postingsCacheKey(userID, blockID string, l labels.Label) string {
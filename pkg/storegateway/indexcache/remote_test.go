@@ -960,3 +960,23 @@ func remove(slice []string, needle string) []string {
 	}
 	return res
 }
+
+func TestCacheTypeFromKey(t *testing.T) {
+	userID := "tenant"
+	blockID := ulid.MustNew(1, nil)
+	matchersKey := CanonicalLabelMatchersKey([]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "foo", "bar")})
+
+	tests := map[string]string{
+		postingsCacheKey(userID, blockID.String(), labels.Label{Name: "foo", Value: "bar"}):           cacheTypePostings,
+		seriesForRefCacheKey(userID, blockID, 1):                                                      cacheTypeSeriesForRef,
+		expandedPostingsCacheKey(userID, blockID, matchersKey, "all"):                                 cacheTypeExpandedPostings,
+		seriesForPostingsCacheKey(userID, blockID, nil, CanonicalPostingsKey([]storage.SeriesRef{1})): cacheTypeSeriesForPostings,
+		labelNamesCacheKey(userID, blockID, matchersKey):                                              cacheTypeLabelNames,
+		labelValuesCacheKey(userID, blockID, "foo", matchersKey):                                      cacheTypeLabelValues,
+		"unknown": "",
+	}
+
+	for key, expected := range tests {
+		assert.Equal(t, expected, CacheTypeFromKey(key), key)
+	}
+}
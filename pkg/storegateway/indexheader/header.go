@@ -54,9 +54,12 @@ type Reader interface {
 type Config struct {
 	MaxIdleFileHandles uint `yaml:"max_idle_file_handles" category:"advanced"`
 	VerifyOnLoad       bool `yaml:"verify_on_load" category:"advanced"`
+
+	SparsePersistenceEnabled bool `yaml:"sparse_persistence_enabled" category:"experimental"`
 }
 
 func (cfg *Config) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
 	f.UintVar(&cfg.MaxIdleFileHandles, prefix+"max-idle-file-handles", 1, "Maximum number of idle file handles the store-gateway keeps open for each index header file.")
 	f.BoolVar(&cfg.VerifyOnLoad, prefix+"verify-on-load", false, "If true, verify the checksum of index headers upon loading them (either on startup or lazily when lazy loading is enabled). Setting to true helps detect disk corruption at the cost of slowing down index header loading.")
+	f.BoolVar(&cfg.SparsePersistenceEnabled, prefix+"sparse-persistence-enabled", false, "If true, the store-gateway persists the sparse in-memory representation of each index-header to disk next to the index-header, and loads it instead of rebuilding it when the index-header is loaded again. When the index-header is built, the sparse index-header is downloaded from the bucket too, if uploaded by the compactor.")
 }
@@ -41,6 +41,22 @@ var implementations = []struct {
 			return br
 		},
 	},
+	{
+		name: "stream binary reader with sparse index-header",
+		factory: func(t *testing.T, ctx context.Context, dir string, id ulid.ULID) Reader {
+			cfg := Config{SparsePersistenceEnabled: true}
+
+			// The first reader persists the sparse index-header, which is loaded by the second one.
+			br, err := NewStreamBinaryReader(ctx, log.NewNopLogger(), nil, dir, id, 32, NewStreamBinaryReaderMetrics(nil), cfg)
+			require.NoError(t, err)
+			require.NoError(t, br.Close())
+
+			br, err = NewStreamBinaryReader(ctx, log.NewNopLogger(), nil, dir, id, 32, NewStreamBinaryReaderMetrics(nil), cfg)
+			require.NoError(t, err)
+			requireCleanup(t, br.Close)
+			return br
+		},
+	},
 	{
 		name: "lazy stream binary reader",
 		factory: func(t *testing.T, ctx context.Context, dir string, id ulid.ULID) Reader {
@@ -48,7 +64,7 @@ var implementations = []struct {
 				return NewStreamBinaryReader(ctx, log.NewNopLogger(), nil, dir, id, 32, NewStreamBinaryReaderMetrics(nil), Config{})
 			}
 
-			br, err := NewLazyBinaryReader(ctx, readerFactory, log.NewNopLogger(), nil, dir, id, NewLazyBinaryReaderMetrics(nil), nil, gate.NewNoop())
+			br, err := NewLazyBinaryReader(ctx, readerFactory, log.NewNopLogger(), nil, dir, id, NewLazyBinaryReaderMetrics(nil), nil, gate.NewNoop(), nil)
 			require.NoError(t, err)
 			requireCleanup(t, br.Close)
 			return br
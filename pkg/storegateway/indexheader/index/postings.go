@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/dskit/runutil"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb/encoding"
 	"github.com/prometheus/prometheus/tsdb/index"
 	"golang.org/x/exp/slices"
 
@@ -218,6 +219,68 @@ func newV2PostingOffsetTable(factory *streamencoding.DecbufFactory, tableOffset
 	return &t, nil
 }
 
+// NewPostingOffsetTableFromSparse returns a PostingOffsetTable, restoring the sampled posting offsets
+// from their sparse representation encoded with PostingOffsetTableV2.EncodeSparse, instead of reading
+// the whole postings offset table. Only index version 2 is supported.
+func NewPostingOffsetTableFromSparse(factory *streamencoding.DecbufFactory, tableOffset int, indexVersion int, postingOffsetsInMemSampling int, d *encoding.Decbuf) (PostingOffsetTable, error) {
+	if indexVersion != index.FormatV2 {
+		return nil, fmt.Errorf("sparse postings offset table is not supported for index version %v", indexVersion)
+	}
+
+	t := PostingOffsetTableV2{
+		factory:                     factory,
+		tableOffset:                 tableOffset,
+		postings:                    map[string]*postingValueOffsets{},
+		postingOffsetsInMemSampling: postingOffsetsInMemSampling,
+	}
+
+	numNames := d.Uvarint()
+	for i := 0; d.Err() == nil && i < numNames; i++ {
+		name := d.UvarintStr()
+		e := &postingValueOffsets{lastValOffset: d.Varint64()}
+
+		numOffsets := d.Uvarint()
+		if d.Err() != nil {
+			break
+		}
+		if numOffsets == 0 {
+			return nil, fmt.Errorf("decode sparse postings offset table: no offsets for label name %q", name)
+		}
+
+		e.offsets = make([]postingOffset, 0, numOffsets)
+		for j := 0; d.Err() == nil && j < numOffsets; j++ {
+			e.offsets = append(e.offsets, postingOffset{value: d.UvarintStr(), tableOff: d.Uvarint()})
+		}
+		t.postings[name] = e
+	}
+
+	if d.Err() != nil {
+		return nil, errors.Wrap(d.Err(), "decode sparse postings offset table")
+	}
+	return &t, nil
+}
+
+// EncodeSparse encodes the sampled posting offsets, so that they can be restored with NewPostingOffsetTableFromSparse.
+func (t *PostingOffsetTableV2) EncodeSparse(e *encoding.Encbuf) {
+	names := make([]string, 0, len(t.postings))
+	for name := range t.postings {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	e.PutUvarint(len(names))
+	for _, name := range names {
+		v := t.postings[name]
+		e.PutUvarintStr(name)
+		e.PutVarint64(v.lastValOffset)
+		e.PutUvarint(len(v.offsets))
+		for _, o := range v.offsets {
+			e.PutUvarintStr(o.value)
+			e.PutUvarint(o.tableOff)
+		}
+	}
+}
+
 // readOffsetTable reads an offset table and at the given position calls f for each
 // found entry. If f returns an error it stops decoding and returns the received error.
 func readOffsetTable(factory *streamencoding.DecbufFactory, tableOffset int, f func(string, string, uint64) error) (err error) {
@@ -14,6 +14,7 @@ import (
 	"unsafe"
 
 	"github.com/grafana/dskit/runutil"
+	"github.com/prometheus/prometheus/tsdb/encoding"
 	"github.com/prometheus/prometheus/tsdb/index"
 
 	streamencoding "github.com/grafana/mimir/pkg/storegateway/indexheader/encoding"
@@ -77,6 +78,43 @@ func NewSymbols(factory *streamencoding.DecbufFactory, version, offset int, doCh
 	return s, nil
 }
 
+// NewSymbolsFromSparse returns a Symbols object for symbol lookups, restoring the sampled symbol
+// offsets from their sparse representation encoded with EncodeSparse, instead of reading
+// the whole symbols table.
+func NewSymbolsFromSparse(factory *streamencoding.DecbufFactory, version, offset int, d *encoding.Decbuf) (*Symbols, error) {
+	s := &Symbols{
+		factory:     factory,
+		version:     version,
+		tableOffset: offset,
+		seen:        d.Uvarint(),
+	}
+
+	// One offset is sampled every symbolFactor symbols, starting with the first one.
+	cnt := d.Uvarint()
+	if d.Err() == nil && cnt != (s.seen+symbolFactor-1)/symbolFactor {
+		return nil, fmt.Errorf("decode sparse symbols: unexpected number of offsets %d for %d symbols", cnt, s.seen)
+	}
+
+	s.offsets = make([]int, 0, cnt)
+	for i := 0; d.Err() == nil && i < cnt; i++ {
+		s.offsets = append(s.offsets, d.Uvarint())
+	}
+
+	if d.Err() != nil {
+		return nil, fmt.Errorf("decode sparse symbols: %w", d.Err())
+	}
+	return s, nil
+}
+
+// EncodeSparse encodes the sampled symbol offsets, so that they can be restored with NewSymbolsFromSparse.
+func (s *Symbols) EncodeSparse(e *encoding.Encbuf) {
+	e.PutUvarint(s.seen)
+	e.PutUvarint(len(s.offsets))
+	for _, off := range s.offsets {
+		e.PutUvarint(off)
+	}
+}
+
 var ErrSymbolNotFound = errors.New("symbol not found")
 
 // Lookup takes a symbol reference and returns the symbol string.
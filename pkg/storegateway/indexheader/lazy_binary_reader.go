@@ -76,6 +76,7 @@ type LazyBinaryReader struct {
 	metrics         *LazyBinaryReaderMetrics
 	onClosed        func(*LazyBinaryReader)
 	lazyLoadingGate gate.Gate
+	loadedBudget    *LoadedBudget
 	ctx             context.Context
 
 	readerMx      sync.RWMutex
@@ -91,6 +92,7 @@ type LazyBinaryReader struct {
 // on the local disk at dir location, this function will build it downloading required
 // sections from the full index stored in the bucket. However, this function doesn't load
 // (mmap or streaming read) the index-header; it will be loaded at first Reader function call.
+// If loadedBudget is not nil, the loaded index-header is accounted in the budget.
 func NewLazyBinaryReader(
 	ctx context.Context,
 	readerFactory func() (Reader, error),
@@ -101,6 +103,7 @@ func NewLazyBinaryReader(
 	metrics *LazyBinaryReaderMetrics,
 	onClosed func(*LazyBinaryReader),
 	lazyLoadingGate gate.Gate,
+	loadedBudget *LoadedBudget,
 ) (*LazyBinaryReader, error) {
 	path := filepath.Join(dir, id.String(), block.IndexHeaderFilename)
 
@@ -128,6 +131,7 @@ func NewLazyBinaryReader(
 		onClosed:        onClosed,
 		readerFactory:   readerFactory,
 		lazyLoadingGate: lazyLoadingGate,
+		loadedBudget:    loadedBudget,
 		ctx:             ctx,
 	}, nil
 }
@@ -152,7 +156,7 @@ func (r *LazyBinaryReader) IndexVersion() (int, error) {
 		return 0, err
 	}
 
-	r.usedAt.Store(time.Now().UnixNano())
+	r.markUsed()
 	return r.reader.IndexVersion()
 }
 
@@ -165,7 +169,7 @@ func (r *LazyBinaryReader) PostingsOffset(name, value string) (index.Range, erro
 		return index.Range{}, err
 	}
 
-	r.usedAt.Store(time.Now().UnixNano())
+	r.markUsed()
 	return r.reader.PostingsOffset(name, value)
 }
 
@@ -178,7 +182,7 @@ func (r *LazyBinaryReader) LookupSymbol(o uint32) (string, error) {
 		return "", err
 	}
 
-	r.usedAt.Store(time.Now().UnixNano())
+	r.markUsed()
 	return r.reader.LookupSymbol(o)
 }
 
@@ -191,7 +195,7 @@ func (r *LazyBinaryReader) SymbolsReader() (streamindex.SymbolsReader, error) {
 		return nil, err
 	}
 
-	r.usedAt.Store(time.Now().UnixNano())
+	r.markUsed()
 	return r.reader.SymbolsReader()
 }
 
@@ -204,7 +208,7 @@ func (r *LazyBinaryReader) LabelValuesOffsets(name string, prefix string, filter
 		return nil, err
 	}
 
-	r.usedAt.Store(time.Now().UnixNano())
+	r.markUsed()
 	return r.reader.LabelValuesOffsets(name, prefix, filter)
 }
 
@@ -217,7 +221,7 @@ func (r *LazyBinaryReader) LabelNames() ([]string, error) {
 		return nil, err
 	}
 
-	r.usedAt.Store(time.Now().UnixNano())
+	r.markUsed()
 	return r.reader.LabelNames()
 }
 
@@ -232,19 +236,10 @@ func (r *LazyBinaryReader) load() (returnErr error) {
 		return r.readerErr
 	}
 
-	// lazyLoadingGate implementation: blocks load if too many are happening at once.
-	err := r.lazyLoadingGate.Start(r.ctx)
-	if err != nil {
-		return errors.Wrapf(err, "failed to wait for turn")
-	}
-	defer r.lazyLoadingGate.Done()
-
-	// Take the write lock to ensure we'll try to load it only once. Take again
-	// the read lock once done.
+	// Release the read lock while waiting for room in the budget and for the loading gate, so that
+	// the reader isn't locked while waiting. Take again the read lock once done.
 	r.readerMx.RUnlock()
-	r.readerMx.Lock()
 	defer func() {
-		r.readerMx.Unlock()
 		r.readerMx.RLock()
 
 		// Between the write unlock and the subsequent read lock, the unload() may have run,
@@ -254,7 +249,29 @@ func (r *LazyBinaryReader) load() (returnErr error) {
 		}
 	}()
 
-	// Ensure none else tried to load it in the meanwhile.
+	// Reserve room in the budget before waiting for turn, so that loads waiting for room
+	// don't take the loading gate slots of the loads which could run.
+	if r.loadedBudget != nil {
+		// Failing to reserve room in the budget is a transient error, so we don't store it in readerErr.
+		if err := r.reserveLoadedBudget(); err != nil {
+			return errors.Wrapf(err, "lazy load index-header file at %s", r.filepath)
+		}
+	}
+
+	// lazyLoadingGate implementation: blocks load if too many are happening at once.
+	err := r.lazyLoadingGate.Start(r.ctx)
+	if err != nil {
+		r.releaseLoadedBudgetIfNotLoaded()
+		return errors.Wrapf(err, "failed to wait for turn")
+	}
+	defer r.lazyLoadingGate.Done()
+
+	// Take the write lock to ensure we'll try to load it only once.
+	r.readerMx.Lock()
+	defer r.readerMx.Unlock()
+
+	// Ensure none else tried to load it in the meanwhile. The index-header accounted in the budget
+	// is the one loaded by the other load, or it has been released if the other load failed.
 	if r.reader != nil {
 		return nil
 	}
@@ -270,6 +287,9 @@ func (r *LazyBinaryReader) load() (returnErr error) {
 	if err != nil {
 		r.metrics.loadFailedCount.Inc()
 		r.readerErr = err
+		if r.loadedBudget != nil {
+			r.loadedBudget.release(r)
+		}
 		return errors.Wrapf(err, "lazy load index-header file at %s", r.filepath)
 	}
 
@@ -282,6 +302,32 @@ func (r *LazyBinaryReader) load() (returnErr error) {
 	return nil
 }
 
+// releaseLoadedBudgetIfNotLoaded releases the index-header from the loaded budget, unless it has been
+// loaded in the meanwhile. This function MUST be called without holding any lock.
+func (r *LazyBinaryReader) releaseLoadedBudgetIfNotLoaded() {
+	if r.loadedBudget == nil {
+		return
+	}
+
+	r.readerMx.Lock()
+	defer r.readerMx.Unlock()
+
+	if r.reader == nil {
+		r.loadedBudget.release(r)
+	}
+}
+
+// markUsed updates the last usage time of the reader and, at most once per loadedBudgetTouchInterval,
+// its position in the least recently used list of the loaded budget.
+func (r *LazyBinaryReader) markUsed() {
+	now := time.Now().UnixNano()
+	prev := r.usedAt.Swap(now)
+
+	if r.loadedBudget != nil && now-prev >= loadedBudgetTouchInterval {
+		r.loadedBudget.touch(r)
+	}
+}
+
 // unloadIfIdleSince closes underlying BinaryReader if the reader is idle since given time (as unix nano). If idleSince is 0,
 // the check on the last usage is skipped. Calling this function on a already unloaded reader is a no-op.
 func (r *LazyBinaryReader) unloadIfIdleSince(ts int64) error {
@@ -298,6 +344,34 @@ func (r *LazyBinaryReader) unloadIfIdleSince(ts int64) error {
 		return errNotIdle
 	}
 
+	if err := r.unload(); err != nil {
+		return err
+	}
+
+	if r.loadedBudget != nil {
+		r.loadedBudget.release(r)
+	}
+	return nil
+}
+
+// tryUnload closes the underlying BinaryReader, unless the reader is currently in use or being
+// loaded. Returns whether the reader is unloaded. This function is used by the LoadedBudget, which
+// takes care of removing the reader from the budget.
+func (r *LazyBinaryReader) tryUnload() bool {
+	if !r.readerMx.TryLock() {
+		return false
+	}
+	defer r.readerMx.Unlock()
+
+	// A reader accounted in the budget but not loaded is being loaded.
+	if r.reader == nil {
+		return false
+	}
+	return r.unload() == nil
+}
+
+// unload closes the underlying BinaryReader. This function MUST be called with the write lock already acquired.
+func (r *LazyBinaryReader) unload() error {
 	r.metrics.unloadCount.Inc()
 	if err := r.reader.Close(); err != nil {
 		r.metrics.unloadFailedCount.Inc()
@@ -308,6 +382,18 @@ func (r *LazyBinaryReader) unloadIfIdleSince(ts int64) error {
 	return nil
 }
 
+// reserveLoadedBudget accounts the index-header in the loaded budget, using its size on disk
+// as an estimate of its memory and mmap footprint. This function MUST be called without holding
+// any lock, because it may wait for room in the budget.
+func (r *LazyBinaryReader) reserveLoadedBudget() error {
+	info, err := os.Stat(r.filepath)
+	if err != nil {
+		return errors.Wrap(err, "read index-header size")
+	}
+
+	return r.loadedBudget.reserve(r.ctx, r, info.Size())
+}
+
 // isIdleSince returns true if the reader is idle since given time (as unix nano).
 func (r *LazyBinaryReader) isIdleSince(ts int64) bool {
 	if r.usedAt.Load() > ts {
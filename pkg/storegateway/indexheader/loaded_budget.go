@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package indexheader
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// loadedBudgetRetryInterval is how frequently a load waiting for room in the budget retries to
+// unload the least recently used index-headers.
+const loadedBudgetRetryInterval = 100 * time.Millisecond
+
+// loadedBudgetTouchInterval is the min interval between two updates of the position of a reader in the
+// least recently used list, so that the budget lock isn't taken each time an index-header is used.
+const loadedBudgetTouchInterval = int64(time.Second)
+
+var errLoadedBudgetExhausted = errors.New("the index-header loading budget is exhausted")
+
+// LoadedBudgetMetrics holds metrics tracked by LoadedBudget.
+type LoadedBudgetMetrics struct {
+	loadedBytes   prometheus.Gauge
+	loadedReaders prometheus.Gauge
+	evictions     prometheus.Counter
+	rejected      prometheus.Counter
+	waitDuration  prometheus.Histogram
+}
+
+// NewLoadedBudgetMetrics makes new LoadedBudgetMetrics.
+func NewLoadedBudgetMetrics(reg prometheus.Registerer) *LoadedBudgetMetrics {
+	return &LoadedBudgetMetrics{
+		loadedBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "indexheader_lazy_loaded_bytes",
+			Help: "Size in bytes of the index-headers currently loaded by lazy readers and accounted in the loading budget.",
+		}),
+		loadedReaders: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "indexheader_lazy_loaded",
+			Help: "Number of index-headers currently loaded by lazy readers and accounted in the loading budget.",
+		}),
+		evictions: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "indexheader_lazy_load_budget_evictions_total",
+			Help: "Total number of index-headers unloaded to make room in the loading budget.",
+		}),
+		rejected: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "indexheader_lazy_load_budget_rejected_total",
+			Help: "Total number of index-header lazy loads rejected because the loading budget was exhausted.",
+		}),
+		waitDuration: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "indexheader_lazy_load_budget_wait_duration_seconds",
+			Help:    "Time spent waiting for room in the loading budget before lazy loading an index-header.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60},
+		}),
+	}
+}
+
+// LoadedBudget bounds the number and total size of the index-headers loaded by lazy readers. The
+// budget is meant to be shared by all the ReaderPools of a store-gateway, so that it's enforced across
+// all tenants. When a lazy reader needs to load its index-header and the budget is exhausted, the
+// least recently used index-headers not in use are unloaded, according to a least recently used list
+// updated (at most once per loadedBudgetTouchInterval) every time an index-header is used. If no index-header can be unloaded,
+// the load waits up to the configured timeout for room to be released, and then fails.
+type LoadedBudget struct {
+	maxBytes    int64
+	maxReaders  int
+	waitTimeout time.Duration
+	metrics     *LoadedBudgetMetrics
+
+	mx sync.Mutex
+	// The element in the least recently used list of each accounted reader.
+	loaded map[*LazyBinaryReader]*list.Element
+	// The accounted readers, from the most to the least recently used.
+	lru         *list.List
+	loadedBytes int64
+	// Closed and replaced every time some room is released, to wake up waiting loads.
+	released chan struct{}
+}
+
+// NewLoadedBudget makes a new LoadedBudget. A zero maxBytes or maxReaders means no limit on
+// the total size or number of loaded index-headers, respectively. A zero waitTimeout means
+// loads fail immediately if the budget is exhausted and no index-header can be unloaded.
+func NewLoadedBudget(maxBytes int64, maxReaders int, waitTimeout time.Duration, metrics *LoadedBudgetMetrics) *LoadedBudget {
+	return &LoadedBudget{
+		maxBytes:    maxBytes,
+		maxReaders:  maxReaders,
+		waitTimeout: waitTimeout,
+		metrics:     metrics,
+		loaded:      map[*LazyBinaryReader]*list.Element{},
+		lru:         list.New(),
+		released:    make(chan struct{}),
+	}
+}
+
+// loadedBudgetEntry is an element of the least recently used list.
+type loadedBudgetEntry struct {
+	reader *LazyBinaryReader
+	size   int64
+}
+
+// reserve accounts the index-header of r, which is about to be loaded, in the budget, unloading
+// the least recently used index-headers if required. Reserving an index-header already accounted
+// is a no-op. This function MUST be called without holding any lock of r, because it may wait for
+// room to be released.
+func (b *LoadedBudget) reserve(ctx context.Context, r *LazyBinaryReader, size int64) error {
+	b.mx.Lock()
+	ok := b.tryReserve(r, size)
+	b.mx.Unlock()
+
+	if ok {
+		return nil
+	}
+	if b.waitTimeout <= 0 {
+		b.metrics.rejected.Inc()
+		return errLoadedBudgetExhausted
+	}
+
+	start := time.Now()
+	defer func() {
+		b.metrics.waitDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	timeout := time.NewTimer(b.waitTimeout)
+	defer timeout.Stop()
+
+	// Index-headers in use can't be unloaded, so we periodically retry even if no room has been released.
+	retry := time.NewTicker(loadedBudgetRetryInterval)
+	defer retry.Stop()
+
+	for {
+		b.mx.Lock()
+		released := b.released
+		b.mx.Unlock()
+
+		select {
+		case <-released:
+		case <-retry.C:
+		case <-timeout.C:
+			b.metrics.rejected.Inc()
+			return errLoadedBudgetExhausted
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		b.mx.Lock()
+		ok := b.tryReserve(r, size)
+		b.mx.Unlock()
+
+		if ok {
+			return nil
+		}
+	}
+}
+
+// tryReserve accounts the index-header of r in the budget if there's room for it, after unloading
+// the least recently used index-headers not in use. Returns whether it has been accounted. This
+// function MUST be called with the budget lock already acquired.
+func (b *LoadedBudget) tryReserve(r *LazyBinaryReader, size int64) bool {
+	if _, ok := b.loaded[r]; ok {
+		return true
+	}
+
+	if !b.fits(size) {
+		// Unload the least recently used readers until there's enough room.
+		for e := b.lru.Back(); e != nil && !b.fits(size); {
+			entry := e.Value.(*loadedBudgetEntry)
+			prev := e.Prev()
+
+			if entry.reader != r && entry.reader.tryUnload() {
+				b.remove(e)
+				b.metrics.evictions.Inc()
+			}
+			e = prev
+		}
+		b.updateMetrics()
+
+		// An index-header bigger than the whole budget is loaded only if there's nothing
+		// else loaded, otherwise it could never be loaded.
+		if !b.fits(size) && len(b.loaded) > 0 {
+			return false
+		}
+	}
+
+	b.loaded[r] = b.lru.PushFront(&loadedBudgetEntry{reader: r, size: size})
+	b.loadedBytes += size
+	b.updateMetrics()
+	return true
+}
+
+// touch moves the index-header of r, if accounted, to the front of the least recently used list.
+func (b *LoadedBudget) touch(r *LazyBinaryReader) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if e, ok := b.loaded[r]; ok {
+		b.lru.MoveToFront(e)
+	}
+}
+
+// remove removes the input element from the budget. This function MUST be called with the budget lock already acquired.
+func (b *LoadedBudget) remove(e *list.Element) {
+	entry := e.Value.(*loadedBudgetEntry)
+	b.lru.Remove(e)
+	delete(b.loaded, entry.reader)
+	b.loadedBytes -= entry.size
+}
+
+// fits returns whether the budget has room for one more reader with the given size.
+// This function MUST be called with the budget lock already acquired.
+func (b *LoadedBudget) fits(size int64) bool {
+	if b.maxBytes > 0 && b.loadedBytes+size > b.maxBytes {
+		return false
+	}
+	if b.maxReaders > 0 && len(b.loaded)+1 > b.maxReaders {
+		return false
+	}
+	return true
+}
+
+// release removes the index-header of r from the budget, if accounted, and wakes up the waiting loads.
+func (b *LoadedBudget) release(r *LazyBinaryReader) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	e, ok := b.loaded[r]
+	if !ok {
+		return
+	}
+
+	b.remove(e)
+	b.updateMetrics()
+
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// updateMetrics MUST be called with the budget lock already acquired.
+func (b *LoadedBudget) updateMetrics() {
+	b.metrics.loadedBytes.Set(float64(b.loadedBytes))
+	b.metrics.loadedReaders.Set(float64(len(b.loaded)))
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package indexheader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/gate"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore/providers/filesystem"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+func TestLoadedBudget_ShouldUnloadLeastRecentlyUsedReaders(t *testing.T) {
+	budget := NewLoadedBudget(0, 2, 0, NewLoadedBudgetMetrics(nil))
+	readers, _ := prepareLoadedBudgetTestReaders(t, budget, 3)
+
+	loadLoadedBudgetTestReader(t, readers[0], 1)
+	loadLoadedBudgetTestReader(t, readers[1], 2)
+	assertLoadedBudget(t, budget, 2, 0)
+
+	// Use the first reader again, so that the second one is the least recently used.
+	loadLoadedBudgetTestReader(t, readers[0], 3)
+	loadLoadedBudgetTestReader(t, readers[2], 4)
+
+	assert.NotNil(t, readers[0].reader)
+	assert.Nil(t, readers[1].reader)
+	assert.NotNil(t, readers[2].reader)
+	assertLoadedBudget(t, budget, 2, 1)
+
+	// An evicted reader is transparently reloaded upon next usage.
+	loadLoadedBudgetTestReader(t, readers[1], 5)
+	assert.Nil(t, readers[0].reader)
+	assert.NotNil(t, readers[1].reader)
+	assertLoadedBudget(t, budget, 2, 2)
+
+	// Closing a reader releases its room in the budget.
+	require.NoError(t, readers[1].Close())
+	assertLoadedBudget(t, budget, 1, 2)
+}
+
+func TestLoadedBudget_ShouldLimitLoadedBytes(t *testing.T) {
+	budget := NewLoadedBudget(0, 0, 0, NewLoadedBudgetMetrics(nil))
+	readers, size := prepareLoadedBudgetTestReaders(t, budget, 3)
+
+	// Only two index-headers fit in the budget.
+	budget.maxBytes = 2*size + size/2
+
+	loadLoadedBudgetTestReader(t, readers[0], 1)
+	loadLoadedBudgetTestReader(t, readers[1], 2)
+	assert.Equal(t, float64(2*size), promtestutil.ToFloat64(budget.metrics.loadedBytes))
+
+	loadLoadedBudgetTestReader(t, readers[2], 3)
+	assert.Nil(t, readers[0].reader)
+	assert.Equal(t, float64(2*size), promtestutil.ToFloat64(budget.metrics.loadedBytes))
+	assertLoadedBudget(t, budget, 2, 1)
+
+	// An index-header bigger than the whole budget is loaded only if nothing else is loaded.
+	budget.maxBytes = size / 2
+
+	loadLoadedBudgetTestReader(t, readers[0], 4)
+	assertLoadedBudget(t, budget, 1, 3)
+}
+
+func TestLoadedBudget_ShouldFailFastIfReadersInUse(t *testing.T) {
+	budget := NewLoadedBudget(0, 1, 0, NewLoadedBudgetMetrics(nil))
+	readers, _ := prepareLoadedBudgetTestReaders(t, budget, 2)
+
+	loadLoadedBudgetTestReader(t, readers[0], 1)
+
+	// Simulate the first reader in use.
+	readers[0].readerMx.RLock()
+	_, err := readers[1].IndexVersion()
+	readers[0].readerMx.RUnlock()
+
+	require.ErrorIs(t, err, errLoadedBudgetExhausted)
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(budget.metrics.rejected))
+	assertLoadedBudget(t, budget, 1, 0)
+
+	// The load is retried upon next usage.
+	loadLoadedBudgetTestReader(t, readers[1], 2)
+	assert.Nil(t, readers[0].reader)
+	assertLoadedBudget(t, budget, 1, 1)
+}
+
+func TestLoadedBudget_ShouldWaitForReadersInUse(t *testing.T) {
+	budget := NewLoadedBudget(0, 1, 10*time.Second, NewLoadedBudgetMetrics(nil))
+	readers, _ := prepareLoadedBudgetTestReaders(t, budget, 2)
+
+	loadLoadedBudgetTestReader(t, readers[0], 1)
+
+	// Simulate the first reader in use, until a while after the second reader load has started.
+	readers[0].readerMx.RLock()
+	go func() {
+		time.Sleep(2 * loadedBudgetRetryInterval)
+		readers[0].readerMx.RUnlock()
+	}()
+
+	_, err := readers[1].IndexVersion()
+	require.NoError(t, err)
+
+	assert.Nil(t, readers[0].reader)
+	assert.NotNil(t, readers[1].reader)
+	assert.Equal(t, float64(0), promtestutil.ToFloat64(budget.metrics.rejected))
+	assertLoadedBudget(t, budget, 1, 1)
+}
+
+func TestLoadedBudget_ShouldStopWaitingOnTimeout(t *testing.T) {
+	budget := NewLoadedBudget(0, 1, 2*loadedBudgetRetryInterval, NewLoadedBudgetMetrics(nil))
+	readers, _ := prepareLoadedBudgetTestReaders(t, budget, 2)
+
+	loadLoadedBudgetTestReader(t, readers[0], 1)
+
+	readers[0].readerMx.RLock()
+	_, err := readers[1].IndexVersion()
+	readers[0].readerMx.RUnlock()
+
+	require.ErrorIs(t, err, errLoadedBudgetExhausted)
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(budget.metrics.rejected))
+	assertLoadedBudget(t, budget, 1, 0)
+}
+
+func TestLoadedBudget_ShouldNotHoldTheLoadingGateWhileWaiting(t *testing.T) {
+	budget := NewLoadedBudget(0, 1, 10*time.Second, NewLoadedBudgetMetrics(nil))
+	lazyLoadingGate := gate.NewBlocking(1)
+	readers, _ := prepareLoadedBudgetTestReadersWithGate(t, budget, lazyLoadingGate, 2)
+
+	loadLoadedBudgetTestReader(t, readers[0], 1)
+
+	// Simulate the first reader in use, so that the second reader load waits for room in the budget.
+	readers[0].readerMx.RLock()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := readers[1].IndexVersion()
+		done <- err
+	}()
+
+	// Wait until the second reader load is waiting for room in the budget.
+	time.Sleep(2 * loadedBudgetRetryInterval)
+
+	// The waiting load holds neither the loading gate slot nor the lock of the reader.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, lazyLoadingGate.Start(ctx))
+	lazyLoadingGate.Done()
+
+	require.True(t, readers[1].readerMx.TryLock())
+	readers[1].readerMx.Unlock()
+
+	readers[0].readerMx.RUnlock()
+	require.NoError(t, <-done)
+
+	assert.Nil(t, readers[0].reader)
+	assert.NotNil(t, readers[1].reader)
+	assertLoadedBudget(t, budget, 1, 1)
+}
+
+func TestLoadedBudget_ShouldReserveOnce(t *testing.T) {
+	budget := NewLoadedBudget(0, 0, 0, NewLoadedBudgetMetrics(nil))
+	readers, _ := prepareLoadedBudgetTestReaders(t, budget, 1)
+
+	budget.mx.Lock()
+	require.True(t, budget.tryReserve(readers[0], 10))
+	require.True(t, budget.tryReserve(readers[0], 10))
+	budget.mx.Unlock()
+
+	assert.Equal(t, float64(10), promtestutil.ToFloat64(budget.metrics.loadedBytes))
+	assertLoadedBudget(t, budget, 1, 0)
+
+	budget.release(readers[0])
+	assert.Equal(t, float64(0), promtestutil.ToFloat64(budget.metrics.loadedBytes))
+	assertLoadedBudget(t, budget, 0, 0)
+}
+
+func TestLoadedBudget_ShouldNotEvictReadersBeingLoaded(t *testing.T) {
+	budget := NewLoadedBudget(0, 1, 0, NewLoadedBudgetMetrics(nil))
+	readers, _ := prepareLoadedBudgetTestReaders(t, budget, 2)
+
+	// The first reader has room reserved in the budget, but its index-header isn't loaded yet.
+	budget.mx.Lock()
+	require.True(t, budget.tryReserve(readers[0], 1))
+	assert.False(t, budget.tryReserve(readers[1], 1))
+	budget.mx.Unlock()
+
+	assertLoadedBudget(t, budget, 1, 0)
+	budget.release(readers[0])
+}
+
+// prepareLoadedBudgetTestReaders creates a block and returns the requested number of lazy readers
+// sharing the input budget, along with the size of the index-header.
+func prepareLoadedBudgetTestReaders(t *testing.T, budget *LoadedBudget, numReaders int) ([]*LazyBinaryReader, int64) {
+	return prepareLoadedBudgetTestReadersWithGate(t, budget, gate.NewNoop(), numReaders)
+}
+
+// prepareLoadedBudgetTestReadersWithGate is like prepareLoadedBudgetTestReaders, but the readers share the input lazy loading gate.
+func prepareLoadedBudgetTestReadersWithGate(t *testing.T, budget *LoadedBudget, lazyLoadingGate gate.Gate, numReaders int) ([]*LazyBinaryReader, int64) {
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+
+	tmpDir := filepath.Join(t.TempDir(), "test-indexheader")
+	bkt, err := filesystem.NewBucket(filepath.Join(tmpDir, "bkt"))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, bkt.Close()) })
+
+	blockID, err := block.CreateBlock(ctx, tmpDir, []labels.Labels{
+		labels.FromStrings("a", "1"),
+		labels.FromStrings("a", "2"),
+		labels.FromStrings("a", "3"),
+	}, 100, 0, 1000, labels.FromStrings("ext1", "1"))
+	require.NoError(t, err)
+	require.NoError(t, block.Upload(ctx, logger, bkt, filepath.Join(tmpDir, blockID.String()), nil))
+
+	readers := make([]*LazyBinaryReader, numReaders)
+	for i := range readers {
+		factory := func() (Reader, error) {
+			return NewStreamBinaryReader(ctx, logger, bkt, tmpDir, blockID, 3, NewStreamBinaryReaderMetrics(nil), Config{})
+		}
+
+		readers[i], err = NewLazyBinaryReader(ctx, factory, logger, bkt, tmpDir, blockID, NewLazyBinaryReaderMetrics(nil), nil, lazyLoadingGate, budget)
+		require.NoError(t, err)
+
+		r := readers[i]
+		t.Cleanup(func() { require.NoError(t, r.Close()) })
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, blockID.String(), block.IndexHeaderFilename))
+	require.NoError(t, err)
+
+	return readers, info.Size()
+}
+
+// loadLoadedBudgetTestReader uses the reader, which loads it, and then overrides its last usage
+// time with the input one, old enough that the next usage moves the reader to the front of the
+// least recently used list regardless of loadedBudgetTouchInterval.
+func loadLoadedBudgetTestReader(t *testing.T, r *LazyBinaryReader, usedAt int64) {
+	_, err := r.IndexVersion()
+	require.NoError(t, err)
+	require.NotNil(t, r.reader)
+
+	r.usedAt.Store(usedAt)
+}
+
+func assertLoadedBudget(t *testing.T, budget *LoadedBudget, expectedLoaded int, expectedEvictions int) {
+	t.Helper()
+
+	assert.Equal(t, float64(expectedLoaded), promtestutil.ToFloat64(budget.metrics.loadedReaders))
+	assert.Equal(t, float64(expectedEvictions), promtestutil.ToFloat64(budget.metrics.evictions))
+
+	budget.mx.Lock()
+	assert.Len(t, budget.loaded, expectedLoaded)
+	budget.mx.Unlock()
+}
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package indexheader
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/runutil"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb/encoding"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"github.com/thanos-io/objstore"
+	"github.com/thanos-io/objstore/providers/filesystem"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	streamindex "github.com/grafana/mimir/pkg/storegateway/indexheader/index"
+)
+
+const (
+	// SparseHeaderFormatV1 represents the first version of the sparse index-header file.
+	SparseHeaderFormatV1 = 1
+
+	// MagicSparseHeader are 4 bytes at the head of a sparse index-header file.
+	MagicSparseHeader = 0x5350524D
+)
+
+var errSparseHeaderMismatch = errors.New("sparse index-header doesn't match the index-header")
+
+// The sparse index-header stores the in-memory representation of the symbols and postings offset table
+// of an index-header, which is otherwise rebuilt reading the whole index-header every time it's loaded.
+// It's only supported for TSDB index v2. The format is:
+//
+//	┌────────────────────────────────────────────────────────────────────────┐
+//	│ magic(4) │ version(1) │ index-header size(8) │ TOC symbols offset(8)     │
+//	├────────────────────────────────────────────────────────────────────────┤
+//	│ TOC postings offset table offset(8) │ postings offsets sampling <uvarint>│
+//	├────────────────────────────────────────────────────────────────────────┤
+//	│ sparse symbols                                                         │
+//	├────────────────────────────────────────────────────────────────────────┤
+//	│ sparse postings offset table                                           │
+//	├────────────────────────────────────────────────────────────────────────┤
+//	│ CRC32 <4b>                                                             │
+//	└────────────────────────────────────────────────────────────────────────┘
+//
+// The index-header size, table of contents and sampling are used to check the sparse index-header
+// matches the index-header and configuration it's loaded with.
+
+// encodeSparseHeader returns the sparse index-header of r, which has been loaded from an
+// index-header file of the given size and with the given postings offsets sampling.
+func encodeSparseHeader(r *StreamBinaryReader, indexHeaderSize, postingOffsetsInMemSampling int) ([]byte, error) {
+	postings, ok := r.postingsOffsetTable.(*streamindex.PostingOffsetTableV2)
+	if !ok {
+		return nil, fmt.Errorf("sparse index-header is not supported for index version %d", r.indexVersion)
+	}
+
+	e := encoding.Encbuf{}
+	e.PutBE32(MagicSparseHeader)
+	e.PutByte(SparseHeaderFormatV1)
+	e.PutBE64(uint64(indexHeaderSize))
+	e.PutBE64(r.toc.Symbols)
+	e.PutBE64(r.toc.PostingsOffsetTable)
+	e.PutUvarint(postingOffsetsInMemSampling)
+	r.symbols.EncodeSparse(&e)
+	postings.EncodeSparse(&e)
+	e.PutHash(newCRC32())
+
+	return e.Get(), nil
+}
+
+// decodeSparseHeader restores the symbols and postings offset table of r from the sparse index-header,
+// after checking it matches the index-header file of the given size and the postings offsets sampling.
+func decodeSparseHeader(r *StreamBinaryReader, data []byte, indexHeaderSize, postingOffsetsInMemSampling int) error {
+	if len(data) < crc32.Size {
+		return errors.New("sparse index-header too short")
+	}
+	if crc := crc32.Checksum(data[:len(data)-crc32.Size], castagnoliTable); crc != binary.BigEndian.Uint32(data[len(data)-crc32.Size:]) {
+		return errors.New("sparse index-header checksum mismatch")
+	}
+
+	d := encoding.Decbuf{B: data[:len(data)-crc32.Size]}
+	if magic := d.Be32(); d.Err() == nil && magic != MagicSparseHeader {
+		return fmt.Errorf("invalid sparse index-header magic number %x", magic)
+	}
+	if version := d.Byte(); d.Err() == nil && version != SparseHeaderFormatV1 {
+		return fmt.Errorf("unknown sparse index-header version %d", version)
+	}
+
+	size, symbolsOffset, postingsOffset, sampling := d.Be64(), d.Be64(), d.Be64(), d.Uvarint()
+	if d.Err() != nil {
+		return errors.Wrap(d.Err(), "decode sparse index-header")
+	}
+	if int(size) != indexHeaderSize || symbolsOffset != r.toc.Symbols || postingsOffset != r.toc.PostingsOffsetTable || sampling != postingOffsetsInMemSampling {
+		return errSparseHeaderMismatch
+	}
+
+	symbols, err := streamindex.NewSymbolsFromSparse(r.factory, r.indexVersion, int(r.toc.Symbols), &d)
+	if err != nil {
+		return err
+	}
+
+	postings, err := streamindex.NewPostingOffsetTableFromSparse(r.factory, int(r.toc.PostingsOffsetTable), r.indexVersion, postingOffsetsInMemSampling, &d)
+	if err != nil {
+		return err
+	}
+
+	if d.Len() > 0 {
+		return fmt.Errorf("unexpected %d bytes at the end of the sparse index-header", d.Len())
+	}
+
+	r.symbols = symbols
+	r.postingsOffsetTable = postings
+	return nil
+}
+
+// loadSparseHeader restores the symbols and postings offset table of r from the sparse index-header file
+// at path. Returns an error if the file doesn't exist, is corrupted, or doesn't match the index-header.
+func loadSparseHeader(r *StreamBinaryReader, path string, indexHeaderSize, postingOffsetsInMemSampling int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return decodeSparseHeader(r, data, indexHeaderSize, postingOffsetsInMemSampling)
+}
+
+// writeSparseHeader writes the sparse index-header of r to the file at path.
+func writeSparseHeader(r *StreamBinaryReader, path string, indexHeaderSize, postingOffsetsInMemSampling int) error {
+	data, err := encodeSparseHeader(r, indexHeaderSize, postingOffsetsInMemSampling)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "write sparse index-header")
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// downloadSparseHeader downloads the sparse index-header of the block from the bucket to the file at dst,
+// unless the file already exists. It's not an error if the sparse index-header doesn't exist in the bucket.
+func downloadSparseHeader(ctx context.Context, logger log.Logger, bkt objstore.BucketReader, id ulid.ULID, dst string) (err error) {
+	if _, err := os.Stat(dst); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	rc, err := bkt.Get(ctx, path.Join(id.String(), block.SparseIndexHeaderFilename))
+	if bkt.IsObjNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "get sparse index-header")
+	}
+	defer runutil.CloseWithLogOnErr(logger, rc, "sparse index-header reader")
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return errors.Wrap(err, "read sparse index-header")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return errors.Wrap(err, "create index-header dir")
+	}
+
+	tmpPath := dst + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "write sparse index-header")
+	}
+	return os.Rename(tmpPath, dst)
+}
+
+// WriteSparseHeaderFile builds the index-header of the block stored in the local blockDir, and writes its
+// sparse index-header to the block directory, so that it can be uploaded to the bucket alongside the block.
+// The sparse index-header is only written for TSDB index v2.
+func WriteSparseHeaderFile(ctx context.Context, logger log.Logger, blockDir string, postingOffsetsInMemSampling int) (err error) {
+	id, err := ulid.Parse(filepath.Base(blockDir))
+	if err != nil {
+		return errors.Wrap(err, "parse block ID")
+	}
+
+	bkt, err := filesystem.NewBucket(filepath.Dir(blockDir))
+	if err != nil {
+		return errors.Wrap(err, "create filesystem bucket")
+	}
+	defer runutil.CloseWithErrCapture(&err, bkt, "filesystem bucket")
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(blockDir), id.String()+"-index-header-")
+	if err != nil {
+		return errors.Wrap(err, "create index-header dir")
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(tmpDir); err == nil {
+			err = removeErr
+		}
+	}()
+
+	indexHeaderPath := filepath.Join(tmpDir, block.IndexHeaderFilename)
+	if err := WriteBinary(ctx, bkt, id, indexHeaderPath); err != nil {
+		return errors.Wrap(err, "write index-header")
+	}
+
+	info, err := os.Stat(indexHeaderPath)
+	if err != nil {
+		return errors.Wrap(err, "read index-header size")
+	}
+
+	r, err := newFileStreamBinaryReader(indexHeaderPath, postingOffsetsInMemSampling, logger, NewStreamBinaryReaderMetrics(nil), Config{MaxIdleFileHandles: 1})
+	if err != nil {
+		return errors.Wrap(err, "read index-header")
+	}
+	defer runutil.CloseWithErrCapture(&err, r, "index-header reader")
+
+	if r.indexVersion != index.FormatV2 {
+		level.Debug(logger).Log("msg", "skipped writing sparse index-header because not supported for the index version", "block", id, "index_version", r.indexVersion)
+		return nil
+	}
+
+	return writeSparseHeader(r, filepath.Join(blockDir, block.SparseIndexHeaderFilename), int(info.Size()), postingOffsetsInMemSampling)
+}
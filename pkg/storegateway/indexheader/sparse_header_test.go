@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package indexheader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore/providers/filesystem"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+func TestStreamBinaryReader_SparseHeader(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+
+	tmpDir := t.TempDir()
+	bkt, err := filesystem.NewBucket(filepath.Join(tmpDir, "bkt"))
+	require.NoError(t, err)
+	requireCleanup(t, bkt.Close)
+
+	// Create a block with enough label values to have the postings offset table sampled.
+	var series []labels.Labels
+	for i := 0; i < 100; i++ {
+		series = append(series, labels.FromStrings("a", strconv.Itoa(i), "b", strconv.Itoa(i%7), "c", fmt.Sprintf("value-%03d", i)))
+	}
+
+	id, err := block.CreateBlock(ctx, tmpDir, series, 10, 0, 1000, labels.FromStrings("ext1", "1"))
+	require.NoError(t, err)
+	require.NoError(t, block.Upload(ctx, logger, bkt, filepath.Join(tmpDir, id.String()), nil))
+	sparseCfg := Config{SparsePersistenceEnabled: true}
+
+	newReader := func(t *testing.T, dir string, sampling int, cfg Config) *StreamBinaryReader {
+		r, err := NewStreamBinaryReader(ctx, logger, bkt, dir, id, sampling, NewStreamBinaryReaderMetrics(nil), cfg)
+		require.NoError(t, err)
+		requireCleanup(t, r.Close)
+		return r
+	}
+
+	// encodeReader returns the sparse index-header of the reader, used to compare readers in-memory representation.
+	encodeReader := func(t *testing.T, r *StreamBinaryReader, dir string, sampling int) []byte {
+		info, err := os.Stat(filepath.Join(dir, id.String(), block.IndexHeaderFilename))
+		require.NoError(t, err)
+
+		data, err := encodeSparseHeader(r, int(info.Size()), sampling)
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("should not persist the sparse index-header if disabled", func(t *testing.T) {
+		dir := t.TempDir()
+		newReader(t, dir, 32, Config{})
+
+		_, err := os.Stat(filepath.Join(dir, id.String(), block.SparseIndexHeaderFilename))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("should persist the sparse index-header and load it", func(t *testing.T) {
+		dir := t.TempDir()
+		sparsePath := filepath.Join(dir, id.String(), block.SparseIndexHeaderFilename)
+
+		expected := encodeReader(t, newReader(t, dir, 32, Config{}), dir, 32)
+
+		newReader(t, dir, 32, sparseCfg)
+		persisted, err := os.ReadFile(sparsePath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, persisted)
+
+		r := newReader(t, dir, 32, sparseCfg)
+		assert.Equal(t, expected, encodeReader(t, r, dir, 32))
+
+		names, err := r.LabelNames()
+		require.NoError(t, err)
+		assert.NotEmpty(t, names)
+	})
+
+	t.Run("should rebuild the sparse index-header if the sampling doesn't match", func(t *testing.T) {
+		dir := t.TempDir()
+		sparsePath := filepath.Join(dir, id.String(), block.SparseIndexHeaderFilename)
+
+		newReader(t, dir, 32, sparseCfg)
+		r := newReader(t, dir, 16, sparseCfg)
+		assert.Equal(t, encodeReader(t, newReader(t, dir, 16, Config{}), dir, 16), encodeReader(t, r, dir, 16))
+
+		data, err := os.ReadFile(sparsePath)
+		require.NoError(t, err)
+		assert.ErrorIs(t, decodeSparseHeader(&StreamBinaryReader{toc: r.toc, factory: r.factory, indexVersion: r.indexVersion}, data, len(data), 32), errSparseHeaderMismatch)
+		assert.Equal(t, encodeReader(t, r, dir, 16), data)
+	})
+
+	t.Run("should rebuild the sparse index-header if corrupted", func(t *testing.T) {
+		dir := t.TempDir()
+		sparsePath := filepath.Join(dir, id.String(), block.SparseIndexHeaderFilename)
+
+		expected := encodeReader(t, newReader(t, dir, 32, sparseCfg), dir, 32)
+
+		corrupted := bytes.Clone(expected)
+		corrupted[len(corrupted)/2] ^= 0xff
+		require.NoError(t, os.WriteFile(sparsePath, corrupted, 0o600))
+
+		r := newReader(t, dir, 32, sparseCfg)
+		assert.Equal(t, expected, encodeReader(t, r, dir, 32))
+
+		data, err := os.ReadFile(sparsePath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, data)
+	})
+
+	t.Run("should download the sparse index-header written by the compactor from the bucket", func(t *testing.T) {
+		require.NoError(t, WriteSparseHeaderFile(ctx, logger, filepath.Join(tmpDir, id.String()), 32))
+
+		uploaded, err := os.ReadFile(filepath.Join(tmpDir, id.String(), block.SparseIndexHeaderFilename))
+		require.NoError(t, err)
+		require.NoError(t, bkt.Upload(ctx, path.Join(id.String(), block.SparseIndexHeaderFilename), bytes.NewReader(uploaded)))
+
+		// The temporary index-header written to build the sparse index-header should have been removed.
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.Contains(t, []string{"bkt", id.String()}, e.Name())
+		}
+
+		dir := t.TempDir()
+		r := newReader(t, dir, 32, sparseCfg)
+
+		downloaded, err := os.ReadFile(filepath.Join(dir, id.String(), block.SparseIndexHeaderFilename))
+		require.NoError(t, err)
+		assert.Equal(t, uploaded, downloaded)
+		assert.Equal(t, uploaded, encodeReader(t, r, dir, 32))
+	})
+}
@@ -8,6 +8,7 @@ package indexheader
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
@@ -65,6 +66,7 @@ type StreamBinaryReader struct {
 // NewStreamBinaryReader loads or builds new index-header if not present on disk.
 func NewStreamBinaryReader(ctx context.Context, logger log.Logger, bkt objstore.BucketReader, dir string, id ulid.ULID, postingOffsetsInMemSampling int, metrics *StreamBinaryReaderMetrics, cfg Config) (*StreamBinaryReader, error) {
 	binfn := filepath.Join(dir, id.String(), block.IndexHeaderFilename)
+
 	br, err := newFileStreamBinaryReader(binfn, postingOffsetsInMemSampling, logger, metrics, cfg)
 	if err == nil {
 		return br, nil
@@ -78,6 +80,15 @@ func NewStreamBinaryReader(ctx context.Context, logger log.Logger, bkt objstore.
 	}
 
 	level.Debug(logger).Log("msg", "built index-header file", "path", binfn, "elapsed", time.Since(start))
+
+	if cfg.SparsePersistenceEnabled {
+		// The sparse index-header may have been uploaded to the bucket by the compactor.
+		sparsefn := filepath.Join(dir, id.String(), block.SparseIndexHeaderFilename)
+		if err := downloadSparseHeader(ctx, logger, bkt, id, sparsefn); err != nil {
+			level.Warn(logger).Log("msg", "failed to download sparse index-header", "path", sparsefn, "err", err)
+		}
+	}
+
 	return newFileStreamBinaryReader(binfn, postingOffsetsInMemSampling, logger, metrics, cfg)
 }
 
@@ -125,14 +136,35 @@ func newFileStreamBinaryReader(path string, postingOffsetsInMemSampling int, log
 		return nil, fmt.Errorf("cannot read table-of-contents: %w", err)
 	}
 
-	r.symbols, err = streamindex.NewSymbols(r.factory, r.indexVersion, int(r.toc.Symbols), cfg.VerifyOnLoad)
-	if err != nil {
-		return nil, fmt.Errorf("cannot load symbols: %w", err)
+	// The sparse index-header is only supported for TSDB index v2.
+	sparseEnabled := cfg.SparsePersistenceEnabled && r.indexVersion == index.FormatV2
+	sparsefn := filepath.Join(filepath.Dir(path), block.SparseIndexHeaderFilename)
+
+	if sparseEnabled {
+		if err = loadSparseHeader(r, sparsefn, indexHeaderSize, postingOffsetsInMemSampling); err == nil {
+			level.Debug(logger).Log("msg", "loaded sparse index-header from disk", "path", sparsefn)
+		} else if !os.IsNotExist(err) {
+			level.Warn(logger).Log("msg", "failed to load sparse index-header from disk; rebuilding", "path", sparsefn, "err", err)
+		}
 	}
 
-	r.postingsOffsetTable, err = streamindex.NewPostingOffsetTable(r.factory, int(r.toc.PostingsOffsetTable), r.indexVersion, indexLastPostingListEndBound, postingOffsetsInMemSampling, cfg.VerifyOnLoad)
-	if err != nil {
-		return nil, err
+	if r.symbols == nil {
+		r.symbols, err = streamindex.NewSymbols(r.factory, r.indexVersion, int(r.toc.Symbols), cfg.VerifyOnLoad)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load symbols: %w", err)
+		}
+
+		r.postingsOffsetTable, err = streamindex.NewPostingOffsetTable(r.factory, int(r.toc.PostingsOffsetTable), r.indexVersion, indexLastPostingListEndBound, postingOffsetsInMemSampling, cfg.VerifyOnLoad)
+		if err != nil {
+			return nil, err
+		}
+
+		if sparseEnabled {
+			// Failing to persist the sparse index-header doesn't prevent using the index-header.
+			if err := writeSparseHeader(r, sparsefn, indexHeaderSize, postingOffsetsInMemSampling); err != nil {
+				level.Warn(logger).Log("msg", "failed to write sparse index-header to disk", "path", sparsefn, "err", err)
+			}
+		}
 	}
 
 	labelNames, err := r.postingsOffsetTable.LabelNames()
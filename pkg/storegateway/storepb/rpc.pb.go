@@ -9,6 +9,7 @@ import (
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
 	types "github.com/gogo/protobuf/types"
+	mimirpb "github.com/grafana/mimir/pkg/mimirpb"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -427,6 +428,127 @@ func (m *LabelValuesResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LabelValuesResponse proto.InternalMessageInfo
 
+type ExemplarsRequest struct {
+	Start int64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   int64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	// matchers is a list of label matchers sets. Exemplars of series matching any of the sets are returned.
+	Matchers []ExemplarMatchers `protobuf:"bytes,3,rep,name=matchers,proto3" json:"matchers"`
+	// hints is an opaque data structure that can be used to carry additional information.
+	// The content of this field and whether it's supported depends on the
+	// implementation of a specific store.
+	Hints *types.Any `protobuf:"bytes,4,opt,name=hints,proto3" json:"hints,omitempty"`
+}
+
+func (m *ExemplarsRequest) Reset()      { *m = ExemplarsRequest{} }
+func (*ExemplarsRequest) ProtoMessage() {}
+func (*ExemplarsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{7}
+}
+func (m *ExemplarsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExemplarsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExemplarsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExemplarsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExemplarsRequest.Merge(m, src)
+}
+func (m *ExemplarsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExemplarsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExemplarsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExemplarsRequest proto.InternalMessageInfo
+
+type ExemplarMatchers struct {
+	Matchers []LabelMatcher `protobuf:"bytes,1,rep,name=matchers,proto3" json:"matchers"`
+}
+
+func (m *ExemplarMatchers) Reset()      { *m = ExemplarMatchers{} }
+func (*ExemplarMatchers) ProtoMessage() {}
+func (*ExemplarMatchers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{8}
+}
+func (m *ExemplarMatchers) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExemplarMatchers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExemplarMatchers.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExemplarMatchers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExemplarMatchers.Merge(m, src)
+}
+func (m *ExemplarMatchers) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExemplarMatchers) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExemplarMatchers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExemplarMatchers proto.InternalMessageInfo
+
+type ExemplarsResponse struct {
+	/// timeseries contains the exemplars of each series, sorted by series labels.
+	Timeseries []mimirpb.TimeSeries `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries"`
+	Warnings   []string             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	/// hints is an opaque data structure that can be used to carry additional information from
+	/// the store. The content of this field and whether it's supported depends on the
+	/// implementation of a specific store.
+	Hints *types.Any `protobuf:"bytes,3,opt,name=hints,proto3" json:"hints,omitempty"`
+}
+
+func (m *ExemplarsResponse) Reset()      { *m = ExemplarsResponse{} }
+func (*ExemplarsResponse) ProtoMessage() {}
+func (*ExemplarsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{9}
+}
+func (m *ExemplarsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExemplarsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExemplarsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExemplarsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExemplarsResponse.Merge(m, src)
+}
+func (m *ExemplarsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExemplarsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExemplarsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExemplarsResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*SeriesRequest)(nil), "thanos.SeriesRequest")
 	proto.RegisterType((*Stats)(nil), "thanos.Stats")
@@ -435,61 +557,71 @@ func init() {
 	proto.RegisterType((*LabelNamesResponse)(nil), "thanos.LabelNamesResponse")
 	proto.RegisterType((*LabelValuesRequest)(nil), "thanos.LabelValuesRequest")
 	proto.RegisterType((*LabelValuesResponse)(nil), "thanos.LabelValuesResponse")
+	proto.RegisterType((*ExemplarsRequest)(nil), "thanos.ExemplarsRequest")
+	proto.RegisterType((*ExemplarMatchers)(nil), "thanos.ExemplarMatchers")
+	proto.RegisterType((*ExemplarsResponse)(nil), "thanos.ExemplarsResponse")
 }
 
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor_77a6da22d6a3feb1) }
 
 var fileDescriptor_77a6da22d6a3feb1 = []byte{
-	// 772 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0x41, 0x6f, 0xd3, 0x48,
-	0x14, 0xc7, 0x3d, 0xf1, 0xd8, 0x99, 0x4c, 0x9a, 0xae, 0x3b, 0xed, 0x56, 0xae, 0xbb, 0x72, 0xa3,
-	0x48, 0x2b, 0x45, 0xab, 0xdd, 0x74, 0x55, 0x24, 0x10, 0x07, 0x0e, 0x4d, 0x25, 0x94, 0x5a, 0xc0,
-	0xc1, 0x45, 0x1c, 0xb8, 0x44, 0x4e, 0x32, 0x4d, 0xac, 0x26, 0x76, 0xf0, 0x38, 0xd0, 0xf4, 0xc4,
-	0x47, 0xe0, 0x63, 0x20, 0xf1, 0x09, 0xb8, 0x72, 0xaa, 0xc4, 0x81, 0x1e, 0x7b, 0x42, 0x24, 0xbd,
-	0x70, 0xec, 0x47, 0x40, 0x9e, 0x99, 0xc4, 0x31, 0x4a, 0x55, 0x2a, 0x71, 0xf3, 0xfb, 0xff, 0xdf,
-	0xcc, 0xbc, 0xf7, 0x7b, 0xcf, 0xb8, 0x10, 0x0d, 0xdb, 0xb5, 0x61, 0x14, 0xc6, 0x21, 0xd1, 0xe3,
-	0x9e, 0x17, 0x84, 0xcc, 0x2a, 0xc6, 0xe3, 0x21, 0x65, 0x42, 0xb4, 0xfe, 0xeb, 0xfa, 0x71, 0x6f,
-	0xd4, 0xaa, 0xb5, 0xc3, 0xc1, 0x6e, 0x37, 0xec, 0x86, 0xbb, 0x5c, 0x6e, 0x8d, 0x8e, 0x79, 0xc4,
-	0x03, 0xfe, 0x25, 0xd3, 0xb7, 0xba, 0x61, 0xd8, 0xed, 0xd3, 0x34, 0xcb, 0x0b, 0xc6, 0xc2, 0xaa,
-	0x7c, 0xcc, 0xe1, 0xd2, 0x11, 0x8d, 0x7c, 0xca, 0x5c, 0xfa, 0x6a, 0x44, 0x59, 0x4c, 0xb6, 0x30,
-	0x1a, 0xf8, 0x41, 0x33, 0xf6, 0x07, 0xd4, 0x04, 0x65, 0x50, 0x55, 0xdd, 0xfc, 0xc0, 0x0f, 0x9e,
-	0xfb, 0x03, 0xca, 0x2d, 0xef, 0x54, 0x58, 0x39, 0x69, 0x79, 0xa7, 0xdc, 0xba, 0x9f, 0x58, 0x71,
-	0xbb, 0x47, 0x23, 0x66, 0xaa, 0x65, 0xb5, 0x5a, 0xdc, 0xdb, 0xa8, 0x89, 0xca, 0x6b, 0x4f, 0xbc,
-	0x16, 0xed, 0x3f, 0x15, 0x66, 0x1d, 0x9e, 0x7f, 0xdd, 0x51, 0xdc, 0x79, 0x2e, 0xd9, 0xc1, 0x45,
-	0x76, 0xe2, 0x0f, 0x9b, 0xed, 0xde, 0x28, 0x38, 0x61, 0x26, 0x2a, 0x83, 0x2a, 0x72, 0x71, 0x22,
-	0x1d, 0x70, 0x85, 0xfc, 0x83, 0xb5, 0x9e, 0x1f, 0xc4, 0xcc, 0x2c, 0x94, 0x01, 0xbf, 0x55, 0xf4,
-	0x52, 0x9b, 0xf5, 0x52, 0xdb, 0x0f, 0xc6, 0xae, 0x48, 0x21, 0x8f, 0xf0, 0x36, 0x8b, 0x23, 0xea,
-	0x0d, 0xfc, 0xa0, 0x2b, 0x6f, 0x6c, 0xb6, 0x92, 0x97, 0x9a, 0xcc, 0x3f, 0xa3, 0x66, 0xa7, 0x0c,
-	0xaa, 0xd0, 0x35, 0xe7, 0x29, 0xe2, 0x85, 0x7a, 0x92, 0x70, 0xe4, 0x9f, 0x51, 0x07, 0x22, 0x68,
-	0x68, 0x0e, 0x44, 0x9a, 0xa1, 0x3b, 0x10, 0xe9, 0x46, 0xde, 0x81, 0x28, 0x6f, 0x20, 0x07, 0x22,
-	0x6c, 0x14, 0x1d, 0x88, 0x8a, 0xc6, 0x8a, 0x03, 0xd1, 0x8a, 0x51, 0x72, 0x20, 0x2a, 0x19, 0xab,
-	0x95, 0x07, 0x58, 0x3b, 0x8a, 0xbd, 0x98, 0x91, 0x1a, 0x5e, 0x3f, 0xa6, 0x49, 0x43, 0x9d, 0xa6,
-	0x1f, 0x74, 0xe8, 0x69, 0xb3, 0x35, 0x8e, 0x29, 0xe3, 0xf4, 0xa0, 0xbb, 0x26, 0xad, 0xc3, 0xc4,
-	0xa9, 0x27, 0x46, 0xe5, 0x73, 0x0e, 0xaf, 0xce, 0xa0, 0xb3, 0x61, 0x18, 0x30, 0x4a, 0xaa, 0x58,
-	0x67, 0x5c, 0xe1, 0xa7, 0x8a, 0x7b, 0xab, 0x33, 0x7a, 0x22, 0xaf, 0xa1, 0xb8, 0xd2, 0x27, 0x16,
-	0xce, 0xbf, 0xf1, 0xa2, 0xc0, 0x0f, 0xba, 0x7c, 0x06, 0x85, 0x86, 0xe2, 0xce, 0x04, 0xf2, 0xef,
-	0x0c, 0x96, 0x7a, 0x33, 0xac, 0x86, 0x32, 0xc3, 0xf5, 0x37, 0xd6, 0x58, 0x52, 0xbf, 0x09, 0x79,
-	0x76, 0x69, 0xfe, 0x64, 0x22, 0x26, 0x69, 0xdc, 0x25, 0x87, 0xd8, 0x48, 0xa9, 0xca, 0x22, 0x35,
-	0x7e, 0xe2, 0xaf, 0xf4, 0x84, 0xf4, 0x45, 0xb5, 0x1c, 0x69, 0x43, 0x71, 0xff, 0x60, 0x59, 0x3d,
-	0x7b, 0x95, 0x1c, 0xb9, 0x7e, 0xc3, 0x55, 0x0b, 0xd3, 0xc9, 0x5c, 0x25, 0x75, 0x84, 0xf5, 0x88,
-	0xb2, 0x51, 0x3f, 0xae, 0x7c, 0x00, 0x78, 0x8d, 0xef, 0xd8, 0x33, 0x6f, 0x90, 0xae, 0xf1, 0x06,
-	0x6f, 0x2e, 0x8a, 0x39, 0x0a, 0xd5, 0x15, 0x01, 0x31, 0xb0, 0x4a, 0x83, 0x0e, 0x6f, 0x58, 0x75,
-	0x93, 0xcf, 0x74, 0xbf, 0xb4, 0xdb, 0xf7, 0x6b, 0x71, 0xc9, 0xf5, 0x5f, 0x5f, 0x72, 0x07, 0x22,
-	0x60, 0xe4, 0x1c, 0x88, 0x72, 0x86, 0x5a, 0x89, 0x30, 0x59, 0x2c, 0x56, 0x8e, 0x7f, 0x03, 0x6b,
-	0x41, 0x22, 0x98, 0xa0, 0xac, 0x56, 0x0b, 0xae, 0x08, 0x88, 0x85, 0x91, 0x9c, 0x2c, 0x33, 0x73,
-	0xdc, 0x98, 0xc7, 0x69, 0xdd, 0xea, 0xad, 0x75, 0x57, 0x3e, 0x01, 0xf9, 0xe8, 0x0b, 0xaf, 0x3f,
-	0xca, 0x20, 0xea, 0x27, 0x2a, 0x5f, 0xb9, 0x82, 0x2b, 0x82, 0x14, 0x1c, 0x5c, 0x02, 0x4e, 0x5b,
-	0x02, 0x4e, 0xbf, 0x1b, 0xb8, 0xfc, 0x9d, 0xc0, 0xe5, 0x0c, 0xd5, 0x81, 0x48, 0x35, 0x60, 0x65,
-	0x84, 0xd7, 0x33, 0x3d, 0x48, 0x72, 0x9b, 0x58, 0x7f, 0xcd, 0x15, 0x89, 0x4e, 0x46, 0xbf, 0x8b,
-	0xdd, 0xde, 0x17, 0x90, 0xfc, 0xe5, 0x61, 0x44, 0xc9, 0x43, 0xac, 0xcb, 0x35, 0xfe, 0x33, 0xfb,
-	0x73, 0x4a, 0x9e, 0xd6, 0xe6, 0xcf, 0xb2, 0x28, 0xf1, 0x7f, 0x40, 0x0e, 0x30, 0x4e, 0x87, 0x4e,
-	0xb6, 0x32, 0xbd, 0x2f, 0x6e, 0xad, 0x65, 0x2d, 0xb3, 0x64, 0xa7, 0x8f, 0x71, 0x71, 0x01, 0x00,
-	0xc9, 0xa6, 0x66, 0x26, 0x6b, 0x6d, 0x2f, 0xf5, 0xc4, 0x3d, 0xf5, 0xfd, 0xf3, 0x89, 0xad, 0x5c,
-	0x4c, 0x6c, 0xe5, 0x72, 0x62, 0x2b, 0xd7, 0x13, 0x1b, 0xbc, 0x9d, 0xda, 0xe0, 0xfd, 0xd4, 0x06,
-	0xe7, 0x53, 0x1b, 0x5c, 0x4c, 0x6d, 0xf0, 0x6d, 0x6a, 0x83, 0xef, 0x53, 0x5b, 0xb9, 0x9e, 0xda,
-	0xe0, 0xdd, 0x95, 0xad, 0x5c, 0x5c, 0xd9, 0xca, 0xe5, 0x95, 0xad, 0xbc, 0xcc, 0xb3, 0x04, 0xc4,
-	0xb0, 0xd5, 0xd2, 0x39, 0xa9, 0x7b, 0x3f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x9b, 0x71, 0x84, 0x02,
-	0xa8, 0x06, 0x00, 0x00,
+	// 884 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0xcf, 0x6f, 0xe3, 0x44,
+	0x14, 0xc7, 0x3d, 0xf1, 0xd8, 0x99, 0x4c, 0xb6, 0x65, 0x3a, 0x5b, 0x56, 0x6e, 0x16, 0x79, 0xa3,
+	0x48, 0x48, 0x11, 0x82, 0x74, 0x55, 0x24, 0x10, 0x2b, 0x71, 0xd8, 0xae, 0x40, 0x5d, 0x0b, 0x38,
+	0xb8, 0x88, 0x03, 0x97, 0xc8, 0x4e, 0xa7, 0x89, 0xd5, 0xf8, 0x07, 0x1e, 0x07, 0xd2, 0x3d, 0xf1,
+	0x27, 0x70, 0xe3, 0xce, 0x09, 0x89, 0xbf, 0x80, 0x2b, 0xa7, 0x4a, 0x1c, 0xe8, 0x71, 0x4f, 0x88,
+	0xa4, 0x17, 0x8e, 0xfb, 0x27, 0xac, 0xe6, 0x47, 0x62, 0x7b, 0x9b, 0x55, 0x77, 0xa5, 0x9e, 0xe2,
+	0x79, 0xdf, 0x37, 0xcf, 0xef, 0x7d, 0xde, 0xf3, 0x0b, 0x6e, 0xe5, 0xd9, 0x68, 0x90, 0xe5, 0x69,
+	0x91, 0x52, 0xbb, 0x98, 0x04, 0x49, 0xca, 0x3b, 0xed, 0xe2, 0x3c, 0x63, 0x5c, 0x19, 0x3b, 0x0f,
+	0xc7, 0x51, 0x31, 0x99, 0x85, 0x83, 0x51, 0x1a, 0xef, 0x8f, 0xf3, 0xe0, 0x34, 0x48, 0x82, 0xfd,
+	0x38, 0x8a, 0xa3, 0x7c, 0x3f, 0x3b, 0x1b, 0xab, 0xa7, 0x2c, 0x54, 0xbf, 0xfa, 0xc6, 0x47, 0xd5,
+	0x1b, 0xe9, 0x38, 0xdd, 0x97, 0xe6, 0x70, 0x76, 0x2a, 0x4f, 0xf2, 0x20, 0x9f, 0xb4, 0xfb, 0xde,
+	0x38, 0x4d, 0xc7, 0x53, 0x56, 0x7a, 0x05, 0xc9, 0xb9, 0x92, 0x7a, 0x7f, 0x36, 0xf0, 0xd6, 0x31,
+	0xcb, 0x23, 0xc6, 0x7d, 0xf6, 0xc3, 0x8c, 0xf1, 0x82, 0xee, 0x61, 0x14, 0x47, 0xc9, 0xb0, 0x88,
+	0x62, 0xe6, 0x80, 0x2e, 0xe8, 0x9b, 0x7e, 0x33, 0x8e, 0x92, 0x6f, 0xa3, 0x98, 0x49, 0x29, 0x98,
+	0x2b, 0xa9, 0xa1, 0xa5, 0x60, 0x2e, 0xa5, 0x4f, 0x84, 0x54, 0x8c, 0x26, 0x2c, 0xe7, 0x8e, 0xd9,
+	0x35, 0xfb, 0xed, 0x83, 0xdd, 0x81, 0xaa, 0x75, 0xf0, 0x55, 0x10, 0xb2, 0xe9, 0xd7, 0x4a, 0x3c,
+	0x84, 0x17, 0xff, 0x3e, 0x30, 0xfc, 0xb5, 0x2f, 0x7d, 0x80, 0xdb, 0xfc, 0x2c, 0xca, 0x86, 0xa3,
+	0xc9, 0x2c, 0x39, 0xe3, 0x0e, 0xea, 0x82, 0x3e, 0xf2, 0xb1, 0x30, 0x3d, 0x91, 0x16, 0xfa, 0x01,
+	0xb6, 0x26, 0x51, 0x52, 0x70, 0xa7, 0xd5, 0x05, 0x32, 0xaa, 0xaa, 0x65, 0xb0, 0xaa, 0x65, 0xf0,
+	0x38, 0x39, 0xf7, 0x95, 0x0b, 0xfd, 0x1c, 0xdf, 0xe7, 0x45, 0xce, 0x82, 0x38, 0x4a, 0xc6, 0x3a,
+	0xe2, 0x30, 0x14, 0x6f, 0x1a, 0xf2, 0xe8, 0x19, 0x73, 0x4e, 0xba, 0xa0, 0x0f, 0x7d, 0x67, 0xed,
+	0xa2, 0xde, 0x70, 0x28, 0x1c, 0x8e, 0xa3, 0x67, 0xcc, 0x83, 0x08, 0x12, 0xcb, 0x83, 0xc8, 0x22,
+	0xb6, 0x07, 0x91, 0x4d, 0x9a, 0x1e, 0x44, 0x4d, 0x82, 0x3c, 0x88, 0x30, 0x69, 0x7b, 0x10, 0xb5,
+	0xc9, 0x1d, 0x0f, 0xa2, 0x3b, 0x64, 0xcb, 0x83, 0x68, 0x8b, 0x6c, 0xf7, 0x3e, 0xc5, 0xd6, 0x71,
+	0x11, 0x14, 0x9c, 0x0e, 0xf0, 0xdd, 0x53, 0x26, 0x0a, 0x3a, 0x19, 0x46, 0xc9, 0x09, 0x9b, 0x0f,
+	0xc3, 0xf3, 0x82, 0x71, 0x49, 0x0f, 0xfa, 0x3b, 0x5a, 0x7a, 0x2a, 0x94, 0x43, 0x21, 0xf4, 0xfe,
+	0x6e, 0xe0, 0xed, 0x15, 0x74, 0x9e, 0xa5, 0x09, 0x67, 0xb4, 0x8f, 0x6d, 0x2e, 0x2d, 0xf2, 0x56,
+	0xfb, 0x60, 0x7b, 0x45, 0x4f, 0xf9, 0x1d, 0x19, 0xbe, 0xd6, 0x69, 0x07, 0x37, 0x7f, 0x0a, 0xf2,
+	0x24, 0x4a, 0xc6, 0xb2, 0x07, 0xad, 0x23, 0xc3, 0x5f, 0x19, 0xe8, 0x87, 0x2b, 0x58, 0xe6, 0xeb,
+	0x61, 0x1d, 0x19, 0x2b, 0x5c, 0xef, 0x63, 0x8b, 0x8b, 0xfc, 0x1d, 0x28, 0xbd, 0xb7, 0xd6, 0xaf,
+	0x14, 0x46, 0xe1, 0x26, 0x55, 0xfa, 0x14, 0x93, 0x92, 0xaa, 0x4e, 0xd2, 0x92, 0x37, 0xde, 0x2b,
+	0x6f, 0x68, 0x5d, 0x65, 0x2b, 0x91, 0x1e, 0x19, 0xfe, 0x3b, 0xbc, 0x6e, 0xaf, 0x87, 0xd2, 0x2d,
+	0xb7, 0x5f, 0x13, 0xaa, 0xd2, 0x9d, 0x5a, 0x28, 0x6d, 0x47, 0xd8, 0xce, 0x19, 0x9f, 0x4d, 0x8b,
+	0xde, 0x1f, 0x00, 0xef, 0xc8, 0x19, 0xfb, 0x26, 0x88, 0xcb, 0x31, 0xde, 0x95, 0xc5, 0xe5, 0x85,
+	0x44, 0x61, 0xfa, 0xea, 0x40, 0x09, 0x36, 0x59, 0x72, 0x22, 0x0b, 0x36, 0x7d, 0xf1, 0x58, 0xce,
+	0x97, 0x75, 0xf3, 0x7c, 0x55, 0x87, 0xdc, 0x7e, 0xf3, 0x21, 0xf7, 0x20, 0x02, 0xa4, 0xe1, 0x41,
+	0xd4, 0x20, 0x66, 0x2f, 0xc7, 0xb4, 0x9a, 0xac, 0x6e, 0xff, 0x2e, 0xb6, 0x12, 0x61, 0x70, 0x40,
+	0xd7, 0xec, 0xb7, 0x7c, 0x75, 0xa0, 0x1d, 0x8c, 0x74, 0x67, 0xb9, 0xd3, 0x90, 0xc2, 0xfa, 0x5c,
+	0xe6, 0x6d, 0xde, 0x98, 0x77, 0xef, 0x2f, 0xa0, 0x5f, 0xfa, 0x5d, 0x30, 0x9d, 0xd5, 0x10, 0x4d,
+	0x85, 0x55, 0x8e, 0x5c, 0xcb, 0x57, 0x87, 0x12, 0x1c, 0xdc, 0x00, 0xce, 0xda, 0x00, 0xce, 0x7e,
+	0x3b, 0x70, 0xcd, 0xb7, 0x02, 0xd7, 0x20, 0xa6, 0x07, 0x91, 0x49, 0x60, 0x6f, 0x86, 0xef, 0xd6,
+	0x6a, 0xd0, 0xe4, 0xee, 0x61, 0xfb, 0x47, 0x69, 0xd1, 0xe8, 0xf4, 0xe9, 0xd6, 0xd8, 0xfd, 0x06,
+	0x30, 0xf9, 0x62, 0xce, 0xe2, 0x6c, 0x1a, 0xe4, 0xd7, 0x87, 0x0b, 0x6c, 0x60, 0xd4, 0x28, 0x19,
+	0x3d, 0xba, 0xb6, 0x15, 0x9d, 0x55, 0xdd, 0xab, 0x98, 0xba, 0x74, 0x7e, 0x6d, 0x33, 0xae, 0x93,
+	0x84, 0x37, 0x27, 0xe9, 0x61, 0xf2, 0x6a, 0xbc, 0x1a, 0x73, 0xf0, 0xe6, 0xcc, 0x7b, 0xbf, 0x02,
+	0xbc, 0x53, 0x29, 0x58, 0x63, 0x7e, 0x84, 0xb1, 0x58, 0xfb, 0xeb, 0x1d, 0xa5, 0xe2, 0x8d, 0xd2,
+	0xbc, 0x60, 0xf3, 0x2c, 0x1c, 0x88, 0xff, 0x00, 0xfd, 0xed, 0xab, 0x78, 0x15, 0xef, 0xdb, 0x6a,
+	0xc5, 0xc1, 0x3f, 0x40, 0x2c, 0xdc, 0x34, 0x67, 0xf4, 0x33, 0x6c, 0xeb, 0x8d, 0xf2, 0x6e, 0x7d,
+	0x4f, 0xea, 0x06, 0x75, 0xee, 0xbd, 0x6a, 0x56, 0x65, 0x3c, 0x04, 0xf4, 0x09, 0xc6, 0xe5, 0xf7,
+	0x47, 0xf7, 0x6a, 0x48, 0xaa, 0x0b, 0xa4, 0xd3, 0xd9, 0x24, 0x69, 0x1a, 0x5f, 0xe2, 0x76, 0x65,
+	0x16, 0x69, 0xdd, 0xb5, 0xf6, 0x91, 0x75, 0xee, 0x6f, 0xd4, 0x54, 0x9c, 0xc3, 0xc7, 0x17, 0x0b,
+	0xd7, 0xb8, 0x5c, 0xb8, 0xc6, 0xf3, 0x85, 0x6b, 0xbc, 0x58, 0xb8, 0xe0, 0xe7, 0xa5, 0x0b, 0x7e,
+	0x5f, 0xba, 0xe0, 0x62, 0xe9, 0x82, 0xcb, 0xa5, 0x0b, 0xfe, 0x5b, 0xba, 0xe0, 0xff, 0xa5, 0x6b,
+	0xbc, 0x58, 0xba, 0xe0, 0x97, 0x2b, 0xd7, 0xb8, 0xbc, 0x72, 0x8d, 0xe7, 0x57, 0xae, 0xf1, 0x7d,
+	0x93, 0x0b, 0x10, 0x59, 0x18, 0xda, 0x92, 0xd4, 0xc7, 0x2f, 0x07, 0x00, 0x6a, 0x82, 0xe7, 0x46,
+	0x65, 0x08, 0x00, 0x00,
 }
 
 func (this *SeriesRequest) Equal(that interface{}) bool {
@@ -893,6 +1025,113 @@ func (this *LabelValuesResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *ExemplarsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ExemplarsRequest)
+	if !ok {
+		that2, ok := that.(ExemplarsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Start != that1.Start {
+		return false
+	}
+	if this.End != that1.End {
+		return false
+	}
+	if len(this.Matchers) != len(that1.Matchers) {
+		return false
+	}
+	for i := range this.Matchers {
+		if !this.Matchers[i].Equal(&that1.Matchers[i]) {
+			return false
+		}
+	}
+	if !this.Hints.Equal(that1.Hints) {
+		return false
+	}
+	return true
+}
+func (this *ExemplarMatchers) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ExemplarMatchers)
+	if !ok {
+		that2, ok := that.(ExemplarMatchers)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Matchers) != len(that1.Matchers) {
+		return false
+	}
+	for i := range this.Matchers {
+		if !this.Matchers[i].Equal(&that1.Matchers[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *ExemplarsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ExemplarsResponse)
+	if !ok {
+		that2, ok := that.(ExemplarsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Timeseries) != len(that1.Timeseries) {
+		return false
+	}
+	for i := range this.Timeseries {
+		if !this.Timeseries[i].Equal(&that1.Timeseries[i]) {
+			return false
+		}
+	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
+	if !this.Hints.Equal(that1.Hints) {
+		return false
+	}
+	return true
+}
 func (this *SeriesRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -1057,6 +1296,63 @@ func (this *LabelValuesResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *ExemplarsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&storepb.ExemplarsRequest{")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	if this.Matchers != nil {
+		vs := make([]*ExemplarMatchers, len(this.Matchers))
+		for i := range vs {
+			vs[i] = &this.Matchers[i]
+		}
+		s = append(s, "Matchers: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	if this.Hints != nil {
+		s = append(s, "Hints: "+fmt.Sprintf("%#v", this.Hints)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ExemplarMatchers) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&storepb.ExemplarMatchers{")
+	if this.Matchers != nil {
+		vs := make([]*LabelMatcher, len(this.Matchers))
+		for i := range vs {
+			vs[i] = &this.Matchers[i]
+		}
+		s = append(s, "Matchers: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ExemplarsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&storepb.ExemplarsResponse{")
+	if this.Timeseries != nil {
+		vs := make([]*mimirpb.TimeSeries, len(this.Timeseries))
+		for i := range vs {
+			vs[i] = &this.Timeseries[i]
+		}
+		s = append(s, "Timeseries: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	if this.Hints != nil {
+		s = append(s, "Hints: "+fmt.Sprintf("%#v", this.Hints)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringRpc(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1748,33 +2044,187 @@ func (m *LabelValuesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintRpc(dAtA []byte, offset int, v uint64) int {
-	offset -= sovRpc(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ExemplarsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *SeriesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ExemplarsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExemplarsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.MinTime != 0 {
-		n += 1 + sovRpc(uint64(m.MinTime))
-	}
-	if m.MaxTime != 0 {
-		n += 1 + sovRpc(uint64(m.MaxTime))
+	if m.Hints != nil {
+		{
+			size, err := m.Hints.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
 	if len(m.Matchers) > 0 {
-		for _, e := range m.Matchers {
-			l = e.Size()
-			n += 1 + l + sovRpc(uint64(l))
+		for iNdEx := len(m.Matchers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Matchers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.End != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Start != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExemplarMatchers) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExemplarMatchers) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExemplarMatchers) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Matchers) > 0 {
+		for iNdEx := len(m.Matchers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Matchers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExemplarsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExemplarsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExemplarsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Hints != nil {
+		{
+			size, err := m.Hints.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Timeseries) > 0 {
+		for iNdEx := len(m.Timeseries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Timeseries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintRpc(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRpc(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *SeriesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MinTime != 0 {
+		n += 1 + sovRpc(uint64(m.MinTime))
+	}
+	if m.MaxTime != 0 {
+		n += 1 + sovRpc(uint64(m.MaxTime))
+	}
+	if len(m.Matchers) > 0 {
+		for _, e := range m.Matchers {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
 		}
 	}
 	if m.SkipChunks {
@@ -1988,6 +2438,71 @@ func (m *LabelValuesResponse) Size() (n int) {
 	return n
 }
 
+func (m *ExemplarsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Start != 0 {
+		n += 1 + sovRpc(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovRpc(uint64(m.End))
+	}
+	if len(m.Matchers) > 0 {
+		for _, e := range m.Matchers {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Hints != nil {
+		l = m.Hints.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *ExemplarMatchers) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Matchers) > 0 {
+		for _, e := range m.Matchers {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExemplarsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Timeseries) > 0 {
+		for _, e := range m.Timeseries {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Hints != nil {
+		l = m.Hints.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
 func sovRpc(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -2155,6 +2670,56 @@ func (this *LabelValuesResponse) String() string {
 	}, "")
 	return s
 }
+func (this *ExemplarsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMatchers := "[]ExemplarMatchers{"
+	for _, f := range this.Matchers {
+		repeatedStringForMatchers += strings.Replace(strings.Replace(f.String(), "ExemplarMatchers", "ExemplarMatchers", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForMatchers += "}"
+	s := strings.Join([]string{`&ExemplarsRequest{`,
+		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`Matchers:` + repeatedStringForMatchers + `,`,
+		`Hints:` + strings.Replace(fmt.Sprintf("%v", this.Hints), "Any", "types.Any", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExemplarMatchers) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMatchers := "[]LabelMatcher{"
+	for _, f := range this.Matchers {
+		repeatedStringForMatchers += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForMatchers += "}"
+	s := strings.Join([]string{`&ExemplarMatchers{`,
+		`Matchers:` + repeatedStringForMatchers + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExemplarsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForTimeseries := "[]TimeSeries{"
+	for _, f := range this.Timeseries {
+		repeatedStringForTimeseries += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForTimeseries += "}"
+	s := strings.Join([]string{`&ExemplarsResponse{`,
+		`Timeseries:` + repeatedStringForTimeseries + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`Hints:` + strings.Replace(fmt.Sprintf("%v", this.Hints), "Any", "types.Any", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringRpc(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -3355,6 +3920,409 @@ func (m *LabelValuesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ExemplarsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemplarsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemplarsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Matchers = append(m.Matchers, ExemplarMatchers{})
+			if err := m.Matchers[len(m.Matchers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hints == nil {
+				m.Hints = &types.Any{}
+			}
+			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExemplarMatchers) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemplarMatchers: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemplarMatchers: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Matchers = append(m.Matchers, LabelMatcher{})
+			if err := m.Matchers[len(m.Matchers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExemplarsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemplarsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemplarsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeseries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Timeseries = append(m.Timeseries, mimirpb.TimeSeries{})
+			if err := m.Timeseries[len(m.Timeseries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hints == nil {
+				m.Hints = &types.Any{}
+			}
+			if err := m.Hints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipRpc(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package streamingpromql implements a PromQL engine that evaluates queries in a streaming fashion,
+// one series at a time, instead of loading all the selected series into memory before evaluating
+// the query like the Prometheus engine does.
+//
+// The engine supports a subset of PromQL: any query using an unsupported expression is rejected with
+// a NotSupportedError, and can be evaluated by the Prometheus engine instead, through EngineWithFallback.
+package streamingpromql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	v1 "github.com/prometheus/prometheus/web/api/v1"
+)
+
+// defaultLookbackDelta is the lookback delta used when none is configured, as in the Prometheus engine.
+const defaultLookbackDelta = 5 * time.Minute
+
+// Engine is a streaming PromQL engine.
+type Engine struct {
+	lookbackDelta        time.Duration
+	timeout              time.Duration
+	activeQueryTracker   promql.QueryTracker
+	enableNegativeOffset bool
+}
+
+var _ v1.QueryEngine = &Engine{}
+
+// NewEngine returns a streaming PromQL engine configured with the given options. The max samples
+// limit and the per-step stats are not supported, and the @ modifier is always left to the fallback engine.
+func NewEngine(opts promql.EngineOpts) *Engine {
+	lookbackDelta := opts.LookbackDelta
+	if lookbackDelta == 0 {
+		lookbackDelta = defaultLookbackDelta
+	}
+
+	return &Engine{
+		lookbackDelta:        lookbackDelta,
+		timeout:              opts.Timeout,
+		activeQueryTracker:   opts.ActiveQueryTracker,
+		enableNegativeOffset: opts.EnableNegativeOffset,
+	}
+}
+
+// SetQueryLogger implements v1.QueryEngine. Logging queries to a query log isn't supported by this engine.
+func (e *Engine) SetQueryLogger(promql.QueryLogger) {}
+
+func (e *Engine) NewInstantQuery(_ context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, ts time.Time) (promql.Query, error) {
+	return newQuery(e, q, opts, qs, ts, ts, 0)
+}
+
+func (e *Engine) NewRangeQuery(_ context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (promql.Query, error) {
+	if interval <= 0 {
+		return nil, errors.New("zero or negative query resolution step widths are not accepted. Try a positive integer")
+	}
+
+	return newQuery(e, q, opts, qs, start, end, interval)
+}
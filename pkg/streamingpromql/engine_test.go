@@ -0,0 +1,522 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/storage/series"
+	"github.com/grafana/mimir/pkg/util/limiter"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// patEvalInstant matches the eval commands of PromQL test scripts, in the same way as the Prometheus test framework.
+var patEvalInstant = regexp.MustCompile(`^eval(?:_(fail|ordered))?\s+instant\s+(?:at\s+(.+?))?\s+(.+)$`)
+
+func newTestEngineOpts() promql.EngineOpts {
+	return promql.EngineOpts{
+		MaxSamples:           math.MaxInt,
+		Timeout:              100 * time.Second,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+		NoStepSubqueryIntervalFn: func(int64) int64 {
+			return time.Minute.Milliseconds()
+		},
+	}
+}
+
+// TestEngine_UpstreamTestCases runs the PromQL test files of the Prometheus engine. Each evaluation is run
+// by the Prometheus test framework, which checks that the Prometheus engine returns the expected result, and
+// then by the streaming engine over the same data, which must return the same result as the Prometheus engine.
+// Evaluations not supported by the streaming engine are skipped.
+func TestEngine_UpstreamTestCases(t *testing.T) {
+	files, err := filepath.Glob("testdata/upstream/*.test")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	totalSupported, totalUnsupported := 0, 0
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			script, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			supported, unsupported := runTestScript(t, string(script))
+			totalSupported += supported
+			totalUnsupported += unsupported
+		})
+	}
+
+	t.Logf("evaluations supported by the streaming engine: %d, not supported: %d", totalSupported, totalUnsupported)
+	require.GreaterOrEqual(t, totalSupported, 190, "expected a significant number of upstream test cases to be supported by the streaming engine")
+}
+
+// runTestScript runs each eval command of a PromQL test script with promql.Test, together with the load
+// commands preceding it since the last clear command, and then compares the result of the Prometheus and
+// the streaming engines over the data loaded by the test. It returns the number of evaluations supported
+// and not supported by the streaming engine.
+func runTestScript(t *testing.T, script string) (supported, unsupported int) {
+	var loads []string
+
+	for _, cmd := range splitTestScript(script) {
+		first, _, _ := strings.Cut(cmd, "\n")
+
+		switch {
+		case first == "clear":
+			loads = nil
+
+		case strings.HasPrefix(first, "load"):
+			loads = append(loads, cmd)
+
+		case patEvalInstant.MatchString(first):
+			if runTestEval(t, loads, cmd) {
+				supported++
+			} else {
+				unsupported++
+			}
+
+		default:
+			require.Failf(t, "unexpected command in test script", "%s", first)
+		}
+	}
+
+	return supported, unsupported
+}
+
+// splitTestScript splits a PromQL test script into its commands. As in the Prometheus test framework,
+// a clear command is a single line, and other commands end at the first empty line or comment.
+func splitTestScript(script string) []string {
+	var cmds []string
+	var cmd []string
+
+	flush := func() {
+		if len(cmd) > 0 {
+			cmds = append(cmds, strings.Join(cmd, "\n"))
+			cmd = nil
+		}
+	}
+
+	for _, l := range strings.Split(script, "\n") {
+		l = strings.TrimSpace(l)
+		switch {
+		case l == "" || strings.HasPrefix(l, "#"):
+			flush()
+		case l == "clear":
+			flush()
+			cmds = append(cmds, l)
+		default:
+			cmd = append(cmd, l)
+		}
+	}
+	flush()
+
+	return cmds
+}
+
+// runTestEval runs the eval command with promql.Test, which checks the result of the Prometheus engine against
+// the expected result of the command, and then requires the streaming engine to return the same result, both
+// as an instant query and as a range query. It returns false if the expression isn't supported by the streaming engine.
+func runTestEval(t *testing.T, loads []string, eval string) bool {
+	first, _, _ := strings.Cut(eval, "\n")
+	desc := fmt.Sprintf("%q", first)
+
+	test, err := promql.NewTest(t, strings.Join(append(slices.Clone(loads), eval), "\n\n"))
+	require.NoError(t, err, desc)
+	defer test.Close()
+	require.NoError(t, test.Run(), desc)
+
+	parts := patEvalInstant.FindStringSubmatch(first)
+	at, err := model.ParseDuration(parts[2])
+	require.NoError(t, err, desc)
+	ts := time.Unix(0, 0).UTC().Add(time.Duration(at))
+
+	prometheusEngine := test.QueryEngine()
+	streamingEngine := NewEngine(newTestEngineOpts())
+
+	return compareEngines(t, prometheusEngine, streamingEngine, test.Queryable(), parts[3], ts, ts, 0, desc) &&
+		compareEngines(t, prometheusEngine, streamingEngine, test.Queryable(), parts[3], ts.Add(-time.Minute), ts.Add(time.Minute), time.Minute, desc)
+}
+
+// compareEngines evaluates the query with both engines and requires them to return the same result.
+// An instant query is run if interval is zero. It returns false if the query isn't supported by the
+// streaming engine.
+func compareEngines(t *testing.T, prometheusEngine *promql.Engine, streamingEngine *Engine, queryable storage.Queryable, expr string, start, end time.Time, interval time.Duration, desc string) bool {
+	ctx := context.Background()
+
+	newQuery := func(engine interface {
+		NewInstantQuery(context.Context, storage.Queryable, promql.QueryOpts, string, time.Time) (promql.Query, error)
+		NewRangeQuery(context.Context, storage.Queryable, promql.QueryOpts, string, time.Time, time.Time, time.Duration) (promql.Query, error)
+	}) (promql.Query, error) {
+		if interval == 0 {
+			return engine.NewInstantQuery(ctx, queryable, nil, expr, start)
+		}
+		return engine.NewRangeQuery(ctx, queryable, nil, expr, start, end, interval)
+	}
+
+	streamingQuery, err := newQuery(streamingEngine)
+	if IsNotSupportedError(err) {
+		return false
+	}
+
+	prometheusQuery, prometheusErr := newQuery(prometheusEngine)
+	if prometheusErr != nil {
+		require.Error(t, err, desc)
+		return true
+	}
+	require.NoError(t, err, desc)
+
+	expected := prometheusQuery.Exec(ctx)
+	defer prometheusQuery.Close()
+	actual := streamingQuery.Exec(ctx)
+	defer streamingQuery.Close()
+
+	if IsNotSupportedError(actual.Err) {
+		return false
+	}
+
+	if expected.Err != nil {
+		require.Error(t, actual.Err, "%s: the Prometheus engine failed with: %v", desc, expected.Err)
+		return true
+	}
+	require.NoError(t, actual.Err, desc)
+
+	switch expectedValue := expected.Value.(type) {
+	case promql.Vector:
+		actualValue, ok := actual.Value.(promql.Vector)
+		require.True(t, ok, "%s: expected a vector, got %T", desc, actual.Value)
+		requireVectorsEqual(t, expectedValue, actualValue, desc)
+
+	case promql.Matrix:
+		actualValue, ok := actual.Value.(promql.Matrix)
+		require.True(t, ok, "%s: expected a matrix, got %T", desc, actual.Value)
+		requireMatricesEqual(t, expectedValue, actualValue, desc)
+
+	default:
+		require.Failf(t, "unexpected result type", "%s: %T", desc, expected.Value)
+	}
+
+	return true
+}
+
+func requireVectorsEqual(t *testing.T, expected, actual promql.Vector, desc string) {
+	sortVector := func(v promql.Vector) {
+		sort.Slice(v, func(i, j int) bool { return labels.Compare(v[i].Metric, v[j].Metric) < 0 })
+	}
+	sortVector(expected)
+	sortVector(actual)
+
+	require.Len(t, actual, len(expected), "%s: expected %v, got %v", desc, expected, actual)
+	for i := range expected {
+		require.Nil(t, expected[i].H, "%s: native histograms should not be supported", desc)
+		require.Equal(t, expected[i].Metric, actual[i].Metric, desc)
+		require.Equal(t, expected[i].T, actual[i].T, desc)
+		require.True(t, almostEqual(expected[i].F, actual[i].F), "%s: expected %v for %s, got %v", desc, expected[i].F, expected[i].Metric, actual[i].F)
+	}
+}
+
+func requireMatricesEqual(t *testing.T, expected, actual promql.Matrix, desc string) {
+	require.Len(t, actual, len(expected), "%s: expected %v, got %v", desc, expected, actual)
+	for i := range expected {
+		require.Empty(t, expected[i].Histograms, "%s: native histograms should not be supported", desc)
+		require.Equal(t, expected[i].Metric, actual[i].Metric, desc)
+		require.Len(t, actual[i].Floats, len(expected[i].Floats), "%s: expected %v, got %v", desc, expected[i], actual[i])
+
+		for j := range expected[i].Floats {
+			e, a := expected[i].Floats[j], actual[i].Floats[j]
+			require.Equal(t, e.T, a.T, desc)
+			require.True(t, almostEqual(e.F, a.F), "%s: expected %v for %s at %d, got %v", desc, e.F, expected[i].Metric, e.T, a.F)
+		}
+	}
+}
+
+// almostEqual returns true if a and b are within a small relative error of each other, considering NaNs equal.
+func almostEqual(a, b float64) bool {
+	const epsilon = 0.000001
+	minNormal := math.Float64frombits(0x0010000000000000) // The smallest positive normal value of type float64.
+
+	if math.IsNaN(a) && math.IsNaN(b) {
+		return true
+	}
+	if a == b {
+		return true
+	}
+
+	diff := math.Abs(a - b)
+	if a == 0 || b == 0 || diff < minNormal {
+		return diff < epsilon*minNormal
+	}
+	return diff/(math.Abs(a)+math.Abs(b)) < epsilon
+}
+
+func TestEngine_CompareWithPrometheusEngine(t *testing.T) {
+	const script = `
+load 1m
+	http_requests_total{job="api", instance="1", status="200"} 0+10x30 0+5x30
+	http_requests_total{job="api", instance="2", status="200"} 0+20x20 _x5 100+20x34
+	http_requests_total{job="api", instance="1", status="500"} 0+1x60
+	http_requests_total{job="db", instance="1", status="200"} 0+3x15 stale 0+3x30
+	http_requests_total{job="db", instance="2", status="200"} _x20 0+7x40
+	errors_total{job="api", instance="1"} 0+1x60
+	errors_total{job="api", instance="2"} 0+2x30
+	other_errors_total{job="api", instance="2"} _x40 0+3x20
+	errors_total{job="db", instance="1"} 10 NaN 12 Inf -Inf 20+1x55
+	gauge{job="api", instance="1"} 1 -5 10 0.5 -0.25 3+1x55
+	gauge{job="db", instance="1"} -Inf 2 Inf 4 NaN 7-1x55
+`
+
+	expressions := []string{
+		`http_requests_total`,
+		`http_requests_total offset 5m`,
+		`http_requests_total offset -5m`,
+		`rate(http_requests_total[5m])`,
+		`rate(http_requests_total[90s] offset 2m)`,
+		`increase(http_requests_total[10m])`,
+		`delta(gauge[5m])`,
+		`sum_over_time(gauge[3m])`,
+		`avg_over_time(errors_total[5m])`,
+		`min_over_time(gauge[5m])`,
+		`max_over_time(gauge[5m])`,
+		`count_over_time(http_requests_total[4m])`,
+		`last_over_time(http_requests_total[2m])`,
+		`sum(rate(http_requests_total[5m]))`,
+		`sum by (job) (rate(http_requests_total[5m]))`,
+		`sum without (instance) (http_requests_total)`,
+		`avg by (status) (http_requests_total)`,
+		`avg(errors_total)`,
+		`min by (job) (gauge)`,
+		`max(gauge)`,
+		`count by (job, status) (http_requests_total)`,
+		`group by (instance) (http_requests_total)`,
+		`sum by (__name__) ({job="api"})`,
+		`abs(gauge)`,
+		`ceil(gauge / 3)`,
+		`floor(-gauge)`,
+		`-{job="api"}`,
+		`sqrt(abs({job="api"}))`,
+		`ln(http_requests_total + 1)`,
+		`log2(errors_total) + log10(errors_total)`,
+		`exp(gauge)`,
+		`http_requests_total * 2`,
+		`2 - http_requests_total`,
+		`-(2 ^ 3) * http_requests_total`,
+		`http_requests_total % 7`,
+		`http_requests_total > 100`,
+		`100 < http_requests_total`,
+		`http_requests_total >= bool 100`,
+		`gauge != 3`,
+		`gauge == bool 2 * 2`,
+		`{__name__=~"http_requests_total|errors_total"} * 2`,
+		`{job="db", instance="1"} == bool 20`,
+		`errors_total / on(job, instance) sum by (job, instance) (http_requests_total)`,
+		`sum by (job) (rate(errors_total[5m])) / sum by (job) (rate(http_requests_total[5m]))`,
+		`http_requests_total{status="200"} - ignoring(status) http_requests_total{status="500"}`,
+		`errors_total > on(job, instance) gauge`,
+		`errors_total < bool ignoring() gauge`,
+		`atan2(errors_total, gauge)`,
+		`rate({__name__=~"http_requests_total|errors_total", instance="1", job="api", status=""}[5m])`,
+
+		// Series with the same labels once the metric name is dropped.
+		`{__name__=~"errors_total|other_errors_total", instance="2"} * 2`,
+		`abs({__name__=~"errors_total|other_errors_total", instance="2"})`,
+		`rate({__name__=~"errors_total|other_errors_total", instance="2"}[5m])`,
+		`-{__name__=~"errors_total|other_errors_total", instance="2"}`,
+		`{__name__=~"errors_total|gauge", job="api"} + 1`,
+		`{__name__=~"errors_total|gauge", job="api"} > bool 1`,
+	}
+
+	test, err := promql.NewTest(t, script)
+	require.NoError(t, err)
+	t.Cleanup(test.Close)
+	require.NoError(t, test.Run())
+	db := test.Queryable()
+
+	prometheusEngine := promql.NewEngine(newTestEngineOpts())
+	streamingEngine := NewEngine(newTestEngineOpts())
+	epoch := time.Unix(0, 0).UTC()
+
+	for _, expr := range expressions {
+		t.Run(expr, func(t *testing.T) {
+			require.True(t, compareEngines(t, prometheusEngine, streamingEngine, db, expr, epoch, epoch.Add(70*time.Minute), 30*time.Second, "range query"), "expected the range query to be supported")
+			require.True(t, compareEngines(t, prometheusEngine, streamingEngine, db, expr, epoch.Add(3*time.Minute), epoch.Add(63*time.Minute), 7*time.Minute, "range query with a large step"), "expected the range query to be supported")
+
+			for _, ts := range []time.Duration{0, 90 * time.Second, 17 * time.Minute, 40 * time.Minute, 64 * time.Minute} {
+				require.True(t, compareEngines(t, prometheusEngine, streamingEngine, db, expr, epoch.Add(ts), epoch.Add(ts), 0, fmt.Sprintf("instant query at %s", ts)), "expected the instant query to be supported")
+			}
+		})
+	}
+}
+
+func TestEngine_NotSupportedExpressions(t *testing.T) {
+	engine := NewEngine(newTestEngineOpts())
+	ts := time.Unix(0, 0)
+
+	for _, expr := range []string{
+		`1 + 2`,
+		`foo[5m]`,
+		`topk(5, foo)`,
+		`quantile(0.9, foo)`,
+		`stddev(foo)`,
+		`foo @ 100`,
+		`rate(foo[5m] @ end())`,
+		`rate(foo[5m:1m])`,
+		`histogram_quantile(0.9, foo)`,
+		`label_replace(foo, "a", "b", "c", "d")`,
+		`foo and bar`,
+		`foo or bar`,
+		`foo unless bar`,
+		`foo * on(job) group_left bar`,
+		`foo * scalar(bar)`,
+		`foo * time()`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := engine.NewInstantQuery(context.Background(), nil, nil, expr, ts)
+			require.Error(t, err)
+			require.True(t, IsNotSupportedError(err), "expected a NotSupportedError, got: %v", err)
+		})
+	}
+}
+
+func TestEngine_ParseError(t *testing.T) {
+	engine := NewEngine(newTestEngineOpts())
+
+	_, err := engine.NewInstantQuery(context.Background(), nil, nil, `sum(`, time.Unix(0, 0))
+	require.Error(t, err)
+	require.False(t, IsNotSupportedError(err))
+}
+
+func TestEngine_MemoryConsumptionLimit(t *testing.T) {
+	const script = `
+load 1m
+	http_requests_total{job="api", instance="1"} 0+10x60
+	http_requests_total{job="api", instance="2"} 0+20x60
+	http_requests_total{job="db", instance="1"} 0+3x60
+	errors_total{job="api", instance="2"} _x30 0+1x30
+	other_errors_total{job="api", instance="2"} 0+1x20
+`
+
+	test, err := promql.NewTest(t, script)
+	require.NoError(t, err)
+	t.Cleanup(test.Close)
+	require.NoError(t, test.Run())
+	db := test.Queryable()
+
+	engine := NewEngine(newTestEngineOpts())
+	start, end := time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour)
+
+	for _, expr := range []string{
+		`http_requests_total`,
+		`rate(http_requests_total[5m])`,
+		`sum by (job) (http_requests_total)`,
+		`http_requests_total / on(job, instance) http_requests_total`,
+		`{__name__=~"errors_total|other_errors_total"} * 2`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			t.Run("unlimited", func(t *testing.T) {
+				tracker := limiter.NewMemoryConsumptionTracker(0, nil)
+				ctx := limiter.AddMemoryConsumptionTrackerToContext(context.Background(), tracker)
+
+				q, err := engine.NewRangeQuery(ctx, db, nil, expr, start, end, time.Minute)
+				require.NoError(t, err)
+
+				res := q.Exec(ctx)
+				require.NoError(t, res.Err)
+				require.NotZero(t, tracker.PeakEstimatedMemoryConsumptionBytes())
+				require.NotZero(t, tracker.CurrentEstimatedMemoryConsumptionBytes(), "the result should be tracked until the query is closed")
+
+				q.Close()
+				require.Zero(t, tracker.CurrentEstimatedMemoryConsumptionBytes(), "all the memory should be released once the query is closed")
+			})
+
+			t.Run("limited", func(t *testing.T) {
+				// Not enough to hold the points of a single series.
+				tracker := limiter.NewMemoryConsumptionTracker(60*uint64(unsafe.Sizeof(promql.FPoint{})), nil)
+				ctx := limiter.AddMemoryConsumptionTrackerToContext(context.Background(), tracker)
+
+				q, err := engine.NewRangeQuery(ctx, db, nil, expr, start, end, time.Minute)
+				require.NoError(t, err)
+
+				res := q.Exec(ctx)
+				require.Error(t, res.Err)
+				require.IsType(t, validation.LimitError(""), res.Err)
+
+				q.Close()
+				require.Zero(t, tracker.CurrentEstimatedMemoryConsumptionBytes(), "all the memory should be released once the query is closed")
+			})
+		})
+	}
+
+	t.Run("limit exceeded while accumulating a group", func(t *testing.T) {
+		// The 61 points of each step are held in slices of 100 elements taken from the pools. The limit
+		// allows holding the points of a series and the values of its group, but not the counts of the group. The first input series belongs to the first output group.
+		tracker := limiter.NewMemoryConsumptionTracker(100*fPointSize+100*float64Size, nil)
+		ctx := limiter.AddMemoryConsumptionTrackerToContext(context.Background(), tracker)
+
+		q, err := engine.NewRangeQuery(ctx, db, nil, `sum by (instance) (http_requests_total)`, start, end, time.Minute)
+		require.NoError(t, err)
+
+		res := q.Exec(ctx)
+		require.Error(t, res.Err)
+		require.IsType(t, validation.LimitError(""), res.Err)
+
+		q.Close()
+		require.Zero(t, tracker.CurrentEstimatedMemoryConsumptionBytes(), "all the memory should be released once the query is closed")
+	})
+}
+
+func TestEngine_SeriesChangedBetweenSelects(t *testing.T) {
+	newSeries := func(instance string) storage.Series {
+		return series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "instance", instance), []model.SamplePair{{Timestamp: 0, Value: 1}}, nil)
+	}
+
+	// The series "b" isn't selected anymore with its samples, and the series "c" is only selected with its samples.
+	queryable := storage.QueryableFunc(func(context.Context, int64, int64) (storage.Querier, error) {
+		return &selectQuerier{selectFn: func(hints *storage.SelectHints) []storage.Series {
+			if hints.Func == "series" {
+				return []storage.Series{newSeries("a"), newSeries("b"), newSeries("d")}
+			}
+			return []storage.Series{newSeries("a"), newSeries("c"), newSeries("d")}
+		}}, nil
+	})
+
+	engine := NewEngine(newTestEngineOpts())
+	q, err := engine.NewInstantQuery(context.Background(), queryable, nil, `up`, time.Unix(0, 0))
+	require.NoError(t, err)
+	defer q.Close()
+
+	res := q.Exec(context.Background())
+	require.NoError(t, res.Err)
+	require.Equal(t, promql.Vector{
+		{Metric: labels.FromStrings(labels.MetricName, "up", "instance", "a"), T: 0, F: 1},
+		{Metric: labels.FromStrings(labels.MetricName, "up", "instance", "d"), T: 0, F: 1},
+	}, res.Value)
+}
+
+type selectQuerier struct {
+	storage.Querier
+	selectFn func(hints *storage.SelectHints) []storage.Series
+}
+
+func (q *selectQuerier) Select(_ bool, hints *storage.SelectHints, _ ...*labels.Matcher) storage.SeriesSet {
+	return series.NewConcreteSeriesSetFromSortedSeries(q.selectFn(hints))
+}
+
+func (q *selectQuerier) Close() error {
+	return nil
+}
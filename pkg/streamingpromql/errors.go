@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotSupportedError is returned when the streaming engine can't evaluate a query, either because the
+// query contains an expression the engine doesn't support, or because the queried data can't be
+// handled by the engine (for example, native histograms).
+type NotSupportedError struct {
+	reason string
+}
+
+func newNotSupportedError(reason string) NotSupportedError {
+	return NotSupportedError{reason: reason}
+}
+
+func (e NotSupportedError) Error() string {
+	return fmt.Sprintf("not supported by the streaming engine: %s", e.reason)
+}
+
+// IsNotSupportedError returns true if err is, or wraps, a NotSupportedError.
+func IsNotSupportedError(err error) bool {
+	return errors.As(err, &NotSupportedError{})
+}
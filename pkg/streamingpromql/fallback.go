@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/stats"
+	v1 "github.com/prometheus/prometheus/web/api/v1"
+
+	querierstats "github.com/grafana/mimir/pkg/querier/stats"
+)
+
+// EngineWithFallback evaluates queries with a preferred engine, and falls back to another engine when
+// the preferred engine returns a NotSupportedError, either when the query is created or when it's executed.
+type EngineWithFallback struct {
+	preferred v1.QueryEngine
+	fallback  v1.QueryEngine
+	logger    log.Logger
+
+	unsupportedQueries prometheus.Counter
+}
+
+var _ v1.QueryEngine = &EngineWithFallback{}
+
+func NewEngineWithFallback(preferred, fallback v1.QueryEngine, reg prometheus.Registerer, logger log.Logger) *EngineWithFallback {
+	return &EngineWithFallback{
+		preferred: preferred,
+		fallback:  fallback,
+		logger:    logger,
+		unsupportedQueries: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_streaming_promql_engine_unsupported_queries_total",
+			Help: "Total number of queries not supported by the streaming PromQL engine, and evaluated by the Prometheus engine instead.",
+		}),
+	}
+}
+
+func (e *EngineWithFallback) SetQueryLogger(l promql.QueryLogger) {
+	e.preferred.SetQueryLogger(l)
+	e.fallback.SetQueryLogger(l)
+}
+
+func (e *EngineWithFallback) NewInstantQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, ts time.Time) (promql.Query, error) {
+	newFallback := func(ctx context.Context) (promql.Query, error) {
+		return e.fallback.NewInstantQuery(ctx, q, opts, qs, ts)
+	}
+
+	query, err := e.preferred.NewInstantQuery(ctx, q, opts, qs, ts)
+	return e.wrap(ctx, qs, query, err, newFallback)
+}
+
+func (e *EngineWithFallback) NewRangeQuery(ctx context.Context, q storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (promql.Query, error) {
+	newFallback := func(ctx context.Context) (promql.Query, error) {
+		return e.fallback.NewRangeQuery(ctx, q, opts, qs, start, end, interval)
+	}
+
+	query, err := e.preferred.NewRangeQuery(ctx, q, opts, qs, start, end, interval)
+	return e.wrap(ctx, qs, query, err, newFallback)
+}
+
+func (e *EngineWithFallback) wrap(ctx context.Context, qs string, query promql.Query, err error, newFallback func(context.Context) (promql.Query, error)) (promql.Query, error) {
+	if err == nil {
+		return &queryWithFallback{engine: e, qs: qs, query: query, newFallback: newFallback}, nil
+	}
+
+	if !IsNotSupportedError(err) {
+		return nil, err
+	}
+
+	e.recordFallback(qs, err)
+	return newFallback(ctx)
+}
+
+func (e *EngineWithFallback) recordFallback(qs string, err error) {
+	e.unsupportedQueries.Inc()
+	level.Debug(e.logger).Log("msg", "query not supported by the streaming engine, falling back to the Prometheus engine", "query", qs, "reason", err)
+}
+
+// queryWithFallback is a query created by the preferred engine, which is replaced by the query created by the
+// fallback engine if the preferred engine fails to execute it with a NotSupportedError.
+type queryWithFallback struct {
+	engine      *EngineWithFallback
+	qs          string
+	newFallback func(context.Context) (promql.Query, error)
+
+	mtx   sync.Mutex
+	query promql.Query
+}
+
+func (q *queryWithFallback) current() promql.Query {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	return q.query
+}
+
+// Exec executes the preferred query, and the fallback query if the preferred one isn't supported. The preferred query
+// is executed with its own query stats, which are discarded if the fallback query is executed, so that the series
+// and chunks fetched by both queries aren't counted twice. Queriers are created for each query, so the per-query
+// limits of the queryable, like the memory consumption limit of the engines, are enforced on each query separately.
+func (q *queryWithFallback) Exec(ctx context.Context) *promql.Result {
+	preferredCtx := ctx
+	var preferredStats *querierstats.Stats
+	if querierstats.IsEnabled(ctx) {
+		preferredStats, preferredCtx = querierstats.ContextWithEmptyStats(ctx)
+	}
+
+	preferred := q.current()
+	res := preferred.Exec(preferredCtx)
+	if res.Err == nil || !IsNotSupportedError(res.Err) {
+		querierstats.FromContext(ctx).Merge(preferredStats)
+		return res
+	}
+
+	q.engine.recordFallback(q.qs, res.Err)
+
+	fallback, err := q.newFallback(ctx)
+	if err != nil {
+		return &promql.Result{Err: err}
+	}
+
+	q.mtx.Lock()
+	q.query = fallback
+	q.mtx.Unlock()
+	preferred.Close()
+
+	return fallback.Exec(ctx)
+}
+
+func (q *queryWithFallback) Close() {
+	q.current().Close()
+}
+
+func (q *queryWithFallback) Statement() parser.Statement {
+	return q.current().Statement()
+}
+
+func (q *queryWithFallback) Stats() *stats.Statistics {
+	return q.current().Stats()
+}
+
+func (q *queryWithFallback) Cancel() {
+	q.current().Cancel()
+}
+
+func (q *queryWithFallback) String() string {
+	return q.qs
+}
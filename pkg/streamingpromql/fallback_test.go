@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	prometheusstorage "github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/prometheus/prometheus/util/teststorage"
+	"github.com/stretchr/testify/require"
+
+	querierstats "github.com/grafana/mimir/pkg/querier/stats"
+)
+
+func TestEngineWithFallback(t *testing.T) {
+	storage := teststorage.New(t)
+	t.Cleanup(func() { require.NoError(t, storage.Close()) })
+
+	app := storage.Appender(context.Background())
+	for i := int64(0); i < 10; i++ {
+		ts := i * time.Minute.Milliseconds()
+		_, err := app.Append(0, labels.FromStrings(labels.MetricName, "float_metric", "idx", "1"), ts, float64(i))
+		require.NoError(t, err)
+		_, err = app.Append(0, labels.FromStrings(labels.MetricName, "float_metric", "idx", "2"), ts, float64(2*i))
+		require.NoError(t, err)
+		_, err = app.AppendHistogram(0, labels.FromStrings(labels.MetricName, "histogram_metric"), ts, tsdbutil.GenerateTestHistogram(int(i)), nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	ctx := context.Background()
+	start := time.Unix(0, 0)
+	end := start.Add(9 * time.Minute)
+
+	testCases := map[string]struct {
+		expr              string
+		expectedFallbacks int
+	}{
+		"supported expression": {
+			expr:              `sum(rate(float_metric[5m]))`,
+			expectedFallbacks: 0,
+		},
+		"expression not supported when the query is created": {
+			expr:              `topk(1, float_metric)`,
+			expectedFallbacks: 1,
+		},
+		"expression not supported when the query is executed": {
+			expr:              `histogram_metric`,
+			expectedFallbacks: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, instant := range []bool{true, false} {
+				reg := prometheus.NewPedanticRegistry()
+				prometheusEngine := promql.NewEngine(newTestEngineOpts())
+				engine := NewEngineWithFallback(NewEngine(newTestEngineOpts()), prometheusEngine, reg, log.NewNopLogger())
+
+				var expected, actual promql.Query
+				var err error
+				if instant {
+					expected, err = prometheusEngine.NewInstantQuery(ctx, storage, nil, testCase.expr, end)
+					require.NoError(t, err)
+					actual, err = engine.NewInstantQuery(ctx, storage, nil, testCase.expr, end)
+					require.NoError(t, err)
+				} else {
+					expected, err = prometheusEngine.NewRangeQuery(ctx, storage, nil, testCase.expr, start, end, time.Minute)
+					require.NoError(t, err)
+					actual, err = engine.NewRangeQuery(ctx, storage, nil, testCase.expr, start, end, time.Minute)
+					require.NoError(t, err)
+				}
+
+				expectedResult := expected.Exec(ctx)
+				actualResult := actual.Exec(ctx)
+				require.NoError(t, expectedResult.Err)
+				require.NoError(t, actualResult.Err)
+				require.Equal(t, expectedResult.Value.String(), actualResult.Value.String())
+				require.Equal(t, testCase.expr, actual.String())
+
+				expected.Close()
+				actual.Close()
+
+				require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+					# HELP cortex_streaming_promql_engine_unsupported_queries_total Total number of queries not supported by the streaming PromQL engine, and evaluated by the Prometheus engine instead.
+					# TYPE cortex_streaming_promql_engine_unsupported_queries_total counter
+					cortex_streaming_promql_engine_unsupported_queries_total `+strconv.Itoa(testCase.expectedFallbacks)+`
+				`), "cortex_streaming_promql_engine_unsupported_queries_total"))
+			}
+		})
+	}
+}
+
+func TestEngineWithFallback_ShouldNotFallBackOnOtherErrors(t *testing.T) {
+	storage := teststorage.New(t)
+	t.Cleanup(func() { require.NoError(t, storage.Close()) })
+
+	reg := prometheus.NewPedanticRegistry()
+	engine := NewEngineWithFallback(NewEngine(newTestEngineOpts()), promql.NewEngine(newTestEngineOpts()), reg, log.NewNopLogger())
+
+	_, err := engine.NewInstantQuery(context.Background(), storage, nil, `sum(`, time.Now())
+	require.Error(t, err)
+	require.False(t, IsNotSupportedError(err))
+	require.Equal(t, float64(0), testutil.ToFloat64(engine.unsupportedQueries))
+}
+
+func TestEngineWithFallback_ShouldNotCountStatsOfUnsupportedQueries(t *testing.T) {
+	storage := teststorage.New(t)
+	t.Cleanup(func() { require.NoError(t, storage.Close()) })
+
+	app := storage.Appender(context.Background())
+	_, err := app.AppendHistogram(0, labels.FromStrings(labels.MetricName, "histogram_metric"), 0, tsdbutil.GenerateTestHistogram(0), nil)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// Count each select as a fetched series.
+	queryable := prometheusstorage.QueryableFunc(func(ctx context.Context, mint, maxt int64) (prometheusstorage.Querier, error) {
+		q, err := storage.Querier(ctx, mint, maxt)
+		if err != nil {
+			return nil, err
+		}
+		return &statsQuerier{Querier: q, stats: querierstats.FromContext(ctx)}, nil
+	})
+
+	engine := NewEngineWithFallback(NewEngine(newTestEngineOpts()), promql.NewEngine(newTestEngineOpts()), nil, log.NewNopLogger())
+	stats, ctx := querierstats.ContextWithEmptyStats(context.Background())
+
+	q, err := engine.NewInstantQuery(ctx, queryable, nil, `histogram_metric`, time.Unix(0, 0))
+	require.NoError(t, err)
+	defer q.Close()
+
+	res := q.Exec(ctx)
+	require.NoError(t, res.Err)
+	require.Equal(t, float64(1), testutil.ToFloat64(engine.unsupportedQueries))
+	require.Equal(t, uint64(1), stats.LoadFetchedSeries(), "only the select of the fallback engine should be counted")
+}
+
+type statsQuerier struct {
+	prometheusstorage.Querier
+	stats *querierstats.Stats
+}
+
+func (q *statsQuerier) Select(sortSeries bool, hints *prometheusstorage.SelectHints, matchers ...*labels.Matcher) prometheusstorage.SeriesSet {
+	q.stats.AddFetchedSeries(1)
+	return q.Querier.Select(sortSeries, hints, matchers...)
+}
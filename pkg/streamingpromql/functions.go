@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/functions.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors.
+
+package streamingpromql
+
+import (
+	"context"
+	"math"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+)
+
+// rangeVectorFunctions holds the range vector functions supported by the streaming engine.
+var rangeVectorFunctions = map[string]rangeVectorFunction{
+	"rate": func(floats []promql.FPoint, step int64, rangeMillis, offset int64) (float64, bool) {
+		return extrapolatedRate(floats, step, rangeMillis, offset, true, true)
+	},
+	"increase": func(floats []promql.FPoint, step int64, rangeMillis, offset int64) (float64, bool) {
+		return extrapolatedRate(floats, step, rangeMillis, offset, true, false)
+	},
+	"delta": func(floats []promql.FPoint, step int64, rangeMillis, offset int64) (float64, bool) {
+		return extrapolatedRate(floats, step, rangeMillis, offset, false, false)
+	},
+	"sum_over_time": func(floats []promql.FPoint, _, _, _ int64) (float64, bool) {
+		var sum, c float64
+		for _, p := range floats {
+			sum, c = kahanSumInc(p.F, sum, c)
+		}
+		if math.IsInf(sum, 0) {
+			return sum, true
+		}
+		return sum + c, true
+	},
+	"avg_over_time": func(floats []promql.FPoint, _, _, _ int64) (float64, bool) {
+		var mean, count, c float64
+		for _, p := range floats {
+			count++
+			if math.IsInf(mean, 0) {
+				if math.IsInf(p.F, 0) && (mean > 0) == (p.F > 0) {
+					// The mean and the value are Inf of the same sign: the mean is already correct.
+					continue
+				}
+				if !math.IsInf(p.F, 0) && !math.IsNaN(p.F) {
+					// The mean is Inf and the value is finite: keep the mean, otherwise the
+					// computation below would turn it into NaN.
+					continue
+				}
+			}
+			mean, c = kahanSumInc(p.F/count-mean/count, mean, c)
+		}
+		if math.IsInf(mean, 0) {
+			return mean, true
+		}
+		return mean + c, true
+	},
+	"min_over_time": func(floats []promql.FPoint, _, _, _ int64) (float64, bool) {
+		min := floats[0].F
+		for _, p := range floats {
+			if p.F < min || math.IsNaN(min) {
+				min = p.F
+			}
+		}
+		return min, true
+	},
+	"max_over_time": func(floats []promql.FPoint, _, _, _ int64) (float64, bool) {
+		max := floats[0].F
+		for _, p := range floats {
+			if p.F > max || math.IsNaN(max) {
+				max = p.F
+			}
+		}
+		return max, true
+	},
+	"count_over_time": func(floats []promql.FPoint, _, _, _ int64) (float64, bool) {
+		return float64(len(floats)), true
+	},
+	"last_over_time": func(floats []promql.FPoint, _, _, _ int64) (float64, bool) {
+		return floats[len(floats)-1].F, true
+	},
+}
+
+// extrapolatedRate is the float-only implementation of rate(), increase() and delta(), in the same way
+// as the Prometheus engine.
+func extrapolatedRate(floats []promql.FPoint, step int64, rangeMillis, offset int64, isCounter, isRate bool) (float64, bool) {
+	if len(floats) < 2 {
+		return 0, false
+	}
+
+	rangeStart := step - (rangeMillis + offset)
+	rangeEnd := step - offset
+
+	numSamplesMinusOne := len(floats) - 1
+	firstT := floats[0].T
+	lastT := floats[numSamplesMinusOne].T
+	result := floats[numSamplesMinusOne].F - floats[0].F
+
+	if isCounter {
+		// Handle counter resets.
+		prevValue := floats[0].F
+		for _, p := range floats[1:] {
+			if p.F < prevValue {
+				result += prevValue
+			}
+			prevValue = p.F
+		}
+	}
+
+	// Duration between first/last samples and boundary of range.
+	durationToStart := float64(firstT-rangeStart) / 1000
+	durationToEnd := float64(rangeEnd-lastT) / 1000
+
+	sampledInterval := float64(lastT-firstT) / 1000
+	averageDurationBetweenSamples := sampledInterval / float64(numSamplesMinusOne)
+
+	if isCounter && result > 0 && floats[0].F >= 0 {
+		// Counters cannot be negative: don't extrapolate beyond the point the counter would be zero.
+		durationToZero := sampledInterval * (floats[0].F / result)
+		if durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	// If the first/last samples are close to the boundaries of the range, extrapolate the result.
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+	extrapolateToInterval := sampledInterval
+
+	if durationToStart < extrapolationThreshold {
+		extrapolateToInterval += durationToStart
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+	if durationToEnd < extrapolationThreshold {
+		extrapolateToInterval += durationToEnd
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+	factor := extrapolateToInterval / sampledInterval
+	if isRate {
+		factor /= float64(rangeMillis) / 1000
+	}
+
+	return result * factor, true
+}
+
+func kahanSumInc(inc, sum, c float64) (newSum, newC float64) {
+	t := sum + inc
+	// Using Neumaier improvement, swap if next term larger than sum.
+	if math.Abs(sum) >= math.Abs(inc) {
+		c += (sum - t) + inc
+	} else {
+		c += (inc - t) + sum
+	}
+	return t, c
+}
+
+// instantVectorFunctions holds the functions over instant vectors supported by the streaming engine.
+// All of them apply a transformation to each point and drop the metric name.
+var instantVectorFunctions = map[string]func(float64) float64{
+	"abs":   math.Abs,
+	"ceil":  math.Ceil,
+	"floor": math.Floor,
+	"exp":   math.Exp,
+	"sqrt":  math.Sqrt,
+	"ln":    math.Log,
+	"log2":  math.Log2,
+	"log10": math.Log10,
+}
+
+// InstantVectorFunction applies a function to each point of the series produced by its inner operator.
+type InstantVectorFunction struct {
+	Inner    InstantVectorOperator
+	Function func(float64) float64
+}
+
+var _ InstantVectorOperator = &InstantVectorFunction{}
+
+func (f *InstantVectorFunction) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	lbls, err := f.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range lbls {
+		lbls[i] = dropMetricName(lbls[i])
+	}
+
+	return lbls, nil
+}
+
+func (f *InstantVectorFunction) Next(ctx context.Context) (InstantVectorSeriesData, error) {
+	data, err := f.Inner.Next(ctx)
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+
+	for i := range data.Floats {
+		data.Floats[i].F = f.Function(data.Floats[i].F)
+	}
+
+	return data, nil
+}
+
+func (f *InstantVectorFunction) Close() {
+	f.Inner.Close()
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+)
+
+// EOS is the error returned by InstantVectorOperator.Next when there are no more series to return.
+var EOS = errors.New("operator stream exhausted") //nolint:revive
+
+// errUnexpectedEndOfStream is returned when an operator returns fewer series than it announced.
+var errUnexpectedEndOfStream = errors.New("operator returned fewer series than expected")
+
+// InstantVectorOperator is an operator that produces an instant vector for each step of the query,
+// one series at a time.
+type InstantVectorOperator interface {
+	// SeriesMetadata returns the labels of the series that this operator will produce, in the same
+	// order Next returns them. It must be called once, before Next is called for the first time.
+	SeriesMetadata(ctx context.Context) ([]labels.Labels, error)
+
+	// Next returns the points of the next series produced by this operator, or EOS if there are no
+	// more series. The returned points are ordered by timestamp and there is at most one point for
+	// each step of the query. The caller owns the returned slice and should return it to the query
+	// pool with putFPointSlice once done with it.
+	Next(ctx context.Context) (InstantVectorSeriesData, error)
+
+	// Close releases all the resources held by this operator. It can be called more than once.
+	Close()
+}
+
+// InstantVectorSeriesData holds the points of a series produced by an InstantVectorOperator.
+type InstantVectorSeriesData struct {
+	Floats []promql.FPoint
+}
+
+// queryTimeRange holds the time range and step of the query being evaluated. Instant queries
+// are evaluated as range queries with a single step.
+type queryTimeRange struct {
+	start    int64 // Milliseconds since epoch.
+	end      int64 // Milliseconds since epoch.
+	interval int64 // Milliseconds.
+	steps    int
+}
+
+func newQueryTimeRange(start, end, interval int64) queryTimeRange {
+	if start == end {
+		// An instant query: use an arbitrary non-zero interval, so that loops over steps terminate.
+		interval = 1
+	}
+
+	return queryTimeRange{
+		start:    start,
+		end:      end,
+		interval: interval,
+		steps:    int((end-start)/interval) + 1,
+	}
+}
+
+// stepIndex returns the index of the step of the query at timestamp t.
+func (r queryTimeRange) stepIndex(t int64) int {
+	return int((t - r.start) / r.interval)
+}
+
+func dropMetricName(l labels.Labels) labels.Labels {
+	return labels.NewBuilder(l).Del(labels.MetricName).Labels()
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"math"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"golang.org/x/exp/slices"
+)
+
+// supportedAggregations holds the aggregation operations supported by the streaming engine.
+var supportedAggregations = map[parser.ItemType]struct{}{
+	parser.SUM:   {},
+	parser.AVG:   {},
+	parser.MIN:   {},
+	parser.MAX:   {},
+	parser.COUNT: {},
+	parser.GROUP: {},
+}
+
+// Aggregation evaluates an aggregation operation, like "sum by (job) (...)". Each output group is
+// computed as soon as all its input series have been read, so only the groups with some input series
+// not read yet are held in memory.
+type Aggregation struct {
+	Inner     InstantVectorOperator
+	Op        parser.ItemType
+	Grouping  []string // Sorted.
+	Without   bool
+	timeRange queryTimeRange
+	pool      *limitingPool
+
+	// remainingInnerSeriesToGroup holds the group of each input series not read yet.
+	remainingInnerSeriesToGroup []*aggregationGroup
+
+	// remainingGroups holds the groups not returned yet, ordered by the index of their last input series.
+	remainingGroups []*aggregationGroup
+}
+
+type aggregationGroup struct {
+	labels               labels.Labels
+	lastSeriesIndex      int
+	remainingSeriesCount int
+
+	// Accumulated values and number of input points for each step. They are allocated when
+	// the first input series of the group is read.
+	values []float64
+	counts []float64
+}
+
+var _ InstantVectorOperator = &Aggregation{}
+
+func (a *Aggregation) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	innerSeries, err := a.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]*aggregationGroup{}
+	a.remainingInnerSeriesToGroup = make([]*aggregationGroup, 0, len(innerSeries))
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	buf := make([]byte, 0, 1024)
+
+	keyLabels := a.Grouping
+	if a.Without {
+		keyLabels = append([]string{labels.MetricName}, a.Grouping...)
+		slices.Sort(keyLabels)
+	}
+
+	for i, series := range innerSeries {
+		var key []byte
+		if a.Without {
+			key = series.BytesWithoutLabels(buf, keyLabels...)
+		} else {
+			key = series.BytesWithLabels(buf, keyLabels...)
+		}
+
+		g, ok := groups[string(key)]
+		if !ok {
+			g = &aggregationGroup{labels: a.groupLabels(lb, series)}
+			groups[string(key)] = g
+		}
+
+		g.lastSeriesIndex = i
+		g.remainingSeriesCount++
+		a.remainingInnerSeriesToGroup = append(a.remainingInnerSeriesToGroup, g)
+	}
+
+	a.remainingGroups = make([]*aggregationGroup, 0, len(groups))
+	for _, g := range groups {
+		a.remainingGroups = append(a.remainingGroups, g)
+	}
+
+	slices.SortFunc(a.remainingGroups, func(a, b *aggregationGroup) bool {
+		return a.lastSeriesIndex < b.lastSeriesIndex
+	})
+
+	lbls := make([]labels.Labels, 0, len(a.remainingGroups))
+	for _, g := range a.remainingGroups {
+		lbls = append(lbls, g.labels)
+	}
+
+	return lbls, nil
+}
+
+func (a *Aggregation) groupLabels(lb *labels.Builder, series labels.Labels) labels.Labels {
+	switch {
+	case a.Without:
+		lb.Reset(series)
+		lb.Del(a.Grouping...)
+		lb.Del(labels.MetricName)
+		return lb.Labels()
+	case len(a.Grouping) > 0:
+		lb.Reset(series)
+		lb.Keep(a.Grouping...)
+		return lb.Labels()
+	default:
+		return labels.EmptyLabels()
+	}
+}
+
+func (a *Aggregation) Next(ctx context.Context) (InstantVectorSeriesData, error) {
+	if len(a.remainingGroups) == 0 {
+		return InstantVectorSeriesData{}, EOS
+	}
+
+	// Read input series until all the input series of the next group have been accumulated. The group is
+	// only removed from the remaining groups once computed, so that Close returns its slices on error.
+	thisGroup := a.remainingGroups[0]
+
+	for thisGroup.remainingSeriesCount > 0 {
+		data, err := a.Inner.Next(ctx)
+		if err != nil {
+			if err == EOS {
+				// The inner operator returned fewer series than it announced.
+				return InstantVectorSeriesData{}, errUnexpectedEndOfStream
+			}
+			return InstantVectorSeriesData{}, err
+		}
+
+		g := a.remainingInnerSeriesToGroup[0]
+		a.remainingInnerSeriesToGroup = a.remainingInnerSeriesToGroup[1:]
+		err = a.accumulate(g, data)
+		a.pool.putFPointSlice(data.Floats)
+		if err != nil {
+			return InstantVectorSeriesData{}, err
+		}
+	}
+
+	points, err := a.pool.getFPointSlice(a.timeRange.steps)
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+
+	a.remainingGroups = a.remainingGroups[1:]
+	for i, count := range thisGroup.counts {
+		if count == 0 {
+			continue
+		}
+
+		t := a.timeRange.start + int64(i)*a.timeRange.interval
+		f := thisGroup.values[i]
+		switch a.Op {
+		case parser.COUNT:
+			f = count
+		case parser.GROUP:
+			f = 1
+		}
+
+		points = append(points, promql.FPoint{T: t, F: f})
+	}
+
+	a.pool.putFloat64Slice(thisGroup.values)
+	a.pool.putFloat64Slice(thisGroup.counts)
+	thisGroup.values, thisGroup.counts = nil, nil
+
+	return InstantVectorSeriesData{Floats: points}, nil
+}
+
+// accumulate adds the points of an input series to the group, in the same way as the Prometheus engine.
+func (a *Aggregation) accumulate(g *aggregationGroup, data InstantVectorSeriesData) error {
+	if g.values == nil {
+		var err error
+		if g.values, err = a.pool.getFloat64Slice(a.timeRange.steps); err != nil {
+			return err
+		}
+		if g.counts, err = a.pool.getFloat64Slice(a.timeRange.steps); err != nil {
+			return err
+		}
+	}
+
+	g.remainingSeriesCount--
+
+	for _, p := range data.Floats {
+		idx := a.timeRange.stepIndex(p.T)
+		g.counts[idx]++
+
+		if g.counts[idx] == 1 {
+			// First point for this step.
+			g.values[idx] = p.F
+			continue
+		}
+
+		switch a.Op {
+		case parser.SUM:
+			g.values[idx] += p.F
+		case parser.AVG:
+			mean, count := g.values[idx], g.counts[idx]
+			if math.IsInf(mean, 0) {
+				if math.IsInf(p.F, 0) && (mean > 0) == (p.F > 0) {
+					// The mean and the value are Inf of the same sign: the mean is already correct.
+					break
+				}
+				if !math.IsInf(p.F, 0) && !math.IsNaN(p.F) {
+					// The mean is Inf and the value is finite: keep the mean, otherwise the
+					// computation below would turn it into NaN.
+					break
+				}
+			}
+			g.values[idx] += p.F/count - mean/count
+		case parser.MAX:
+			if g.values[idx] < p.F || math.IsNaN(g.values[idx]) {
+				g.values[idx] = p.F
+			}
+		case parser.MIN:
+			if g.values[idx] > p.F || math.IsNaN(g.values[idx]) {
+				g.values[idx] = p.F
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Aggregation) Close() {
+	a.Inner.Close()
+
+	for _, g := range a.remainingGroups {
+		a.pool.putFloat64Slice(g.values)
+		a.pool.putFloat64Slice(g.counts)
+		g.values, g.counts = nil, nil
+	}
+}
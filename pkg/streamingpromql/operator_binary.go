@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"golang.org/x/exp/slices"
+)
+
+// supportedBinaryOperations holds the binary operations supported by the streaming engine.
+var supportedBinaryOperations = map[parser.ItemType]struct{}{
+	parser.ADD:   {},
+	parser.SUB:   {},
+	parser.MUL:   {},
+	parser.DIV:   {},
+	parser.POW:   {},
+	parser.MOD:   {},
+	parser.ATAN2: {},
+	parser.EQLC:  {},
+	parser.NEQ:   {},
+	parser.GTR:   {},
+	parser.LSS:   {},
+	parser.GTE:   {},
+	parser.LTE:   {},
+}
+
+// binaryOperation returns the result of the binary operation between two floats, and whether the
+// result should be kept, in the same way as the Prometheus engine. For comparison operations, the
+// returned value is lhs.
+func binaryOperation(op parser.ItemType, lhs, rhs float64) (float64, bool) {
+	switch op {
+	case parser.ADD:
+		return lhs + rhs, true
+	case parser.SUB:
+		return lhs - rhs, true
+	case parser.MUL:
+		return lhs * rhs, true
+	case parser.DIV:
+		return lhs / rhs, true
+	case parser.POW:
+		return math.Pow(lhs, rhs), true
+	case parser.MOD:
+		return math.Mod(lhs, rhs), true
+	case parser.ATAN2:
+		return math.Atan2(lhs, rhs), true
+	case parser.EQLC:
+		return lhs, lhs == rhs
+	case parser.NEQ:
+		return lhs, lhs != rhs
+	case parser.GTR:
+		return lhs, lhs > rhs
+	case parser.LSS:
+		return lhs, lhs < rhs
+	case parser.GTE:
+		return lhs, lhs >= rhs
+	case parser.LTE:
+		return lhs, lhs <= rhs
+	}
+	panic(fmt.Errorf("operator %q not supported", op))
+}
+
+// shouldDropMetricName returns true if the result of the binary operation doesn't keep the metric name.
+func shouldDropMetricName(op parser.ItemType) bool {
+	switch op {
+	case parser.ADD, parser.SUB, parser.DIV, parser.MUL, parser.POW, parser.MOD:
+		return true
+	default:
+		return false
+	}
+}
+
+// VectorScalarBinaryOperation evaluates a binary operation between an instant vector and a scalar
+// constant, like "foo * 2" or "2 > foo".
+type VectorScalarBinaryOperation struct {
+	Vector     InstantVectorOperator
+	Scalar     float64
+	Op         parser.ItemType
+	ScalarSide bool // True if the scalar is the left-hand side of the operation.
+	ReturnBool bool
+}
+
+var _ InstantVectorOperator = &VectorScalarBinaryOperation{}
+
+func (b *VectorScalarBinaryOperation) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	lbls, err := b.Vector.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if shouldDropMetricName(b.Op) || b.ReturnBool {
+		for i := range lbls {
+			lbls[i] = dropMetricName(lbls[i])
+		}
+	}
+
+	return lbls, nil
+}
+
+func (b *VectorScalarBinaryOperation) Next(ctx context.Context) (InstantVectorSeriesData, error) {
+	data, err := b.Vector.Next(ctx)
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+
+	// Apply the operation in place, dropping the points that are filtered out.
+	points := data.Floats[:0]
+	for _, p := range data.Floats {
+		lhs, rhs := p.F, b.Scalar
+		if b.ScalarSide {
+			lhs, rhs = rhs, lhs
+		}
+
+		f, keep := binaryOperation(b.Op, lhs, rhs)
+		if b.Op.IsComparisonOperator() {
+			// Comparisons always keep the value of the vector, even if it's on the right-hand side.
+			f = p.F
+		}
+		if b.ReturnBool {
+			f = boolToFloat(keep)
+			keep = true
+		}
+		if keep {
+			p.F = f
+			points = append(points, p)
+		}
+	}
+
+	return InstantVectorSeriesData{Floats: points}, nil
+}
+
+func (b *VectorScalarBinaryOperation) Close() {
+	b.Vector.Close()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// VectorVectorBinaryOperation evaluates a one-to-one binary operation between two instant vectors,
+// like "foo / on(job) bar". Each left-hand side series is matched with at most one right-hand side series.
+// The left-hand side is streamed, while right-hand side series are held in memory only until they are
+// consumed, or if they're read before they're needed.
+type VectorVectorBinaryOperation struct {
+	Left       InstantVectorOperator
+	Right      InstantVectorOperator
+	Op         parser.ItemType
+	Matching   *parser.VectorMatching
+	ReturnBool bool
+	pool       *limitingPool
+
+	// leftSeries holds, for each left-hand side series, the index of the matching right-hand side series,
+	// or -1 if there's none.
+	leftSeries []int
+	// outputCount is the number of output series not returned yet.
+	outputCount int
+
+	// nextRightIndex is the index of the next series to read from the right-hand side.
+	nextRightIndex int
+	// rightNeeded tracks whether each right-hand side series is matched by a left-hand side series.
+	rightNeeded []bool
+	// rightBuffer holds the right-hand side series read but not consumed yet.
+	rightBuffer map[int]InstantVectorSeriesData
+}
+
+var _ InstantVectorOperator = &VectorVectorBinaryOperation{}
+
+func (b *VectorVectorBinaryOperation) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	leftLabels, err := b.Left.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rightLabels, err := b.Right.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := b.signatureFunc()
+
+	rightSigs := make(map[string][]int, len(rightLabels))
+	for i, l := range rightLabels {
+		sig := signature(l)
+		rightSigs[sig] = append(rightSigs[sig], i)
+	}
+
+	b.leftSeries = make([]int, len(leftLabels))
+	b.rightNeeded = make([]bool, len(rightLabels))
+	b.rightBuffer = map[int]InstantVectorSeriesData{}
+
+	matchedSigs := make(map[string]struct{}, len(leftLabels))
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	var outputLabels []labels.Labels
+
+	for i, l := range leftLabels {
+		sig := signature(l)
+		rightIdxs := rightSigs[sig]
+		if len(rightIdxs) == 0 {
+			b.leftSeries[i] = -1
+			continue
+		}
+
+		// Whether multiple series matching the same labels conflict depends on the steps at which they
+		// have points, so leave these cases to the Prometheus engine.
+		if len(rightIdxs) > 1 {
+			return nil, newNotSupportedError("binary operation with multiple right-hand side series matching the same labels")
+		}
+		if _, ok := matchedSigs[sig]; ok {
+			return nil, newNotSupportedError("binary operation with multiple left-hand side series matching the same labels")
+		}
+		matchedSigs[sig] = struct{}{}
+
+		b.leftSeries[i] = rightIdxs[0]
+		b.rightNeeded[rightIdxs[0]] = true
+		outputLabels = append(outputLabels, b.resultLabels(lb, l))
+	}
+
+	b.outputCount = len(outputLabels)
+	return outputLabels, nil
+}
+
+func (b *VectorVectorBinaryOperation) signatureFunc() func(labels.Labels) string {
+	buf := make([]byte, 0, 1024)
+	names := slices.Clone(b.Matching.MatchingLabels)
+
+	if b.Matching.On {
+		slices.Sort(names)
+		return func(l labels.Labels) string {
+			return string(l.BytesWithLabels(buf, names...))
+		}
+	}
+
+	names = append(names, labels.MetricName)
+	slices.Sort(names)
+	return func(l labels.Labels) string {
+		return string(l.BytesWithoutLabels(buf, names...))
+	}
+}
+
+func (b *VectorVectorBinaryOperation) resultLabels(lb *labels.Builder, l labels.Labels) labels.Labels {
+	lb.Reset(l)
+
+	if shouldDropMetricName(b.Op) || b.ReturnBool {
+		lb.Del(labels.MetricName)
+	}
+
+	if b.Matching.On {
+		lb.Keep(b.Matching.MatchingLabels...)
+	} else {
+		lb.Del(b.Matching.MatchingLabels...)
+	}
+
+	return lb.Labels()
+}
+
+func (b *VectorVectorBinaryOperation) Next(ctx context.Context) (InstantVectorSeriesData, error) {
+	if b.outputCount == 0 {
+		return InstantVectorSeriesData{}, EOS
+	}
+
+	for {
+		left, err := b.Left.Next(ctx)
+		if err == EOS {
+			return InstantVectorSeriesData{}, errUnexpectedEndOfStream
+		}
+		if err != nil {
+			return InstantVectorSeriesData{}, err
+		}
+
+		rightIdx := b.leftSeries[0]
+		b.leftSeries = b.leftSeries[1:]
+		if rightIdx < 0 {
+			// This series has no match on the right-hand side.
+			b.pool.putFPointSlice(left.Floats)
+			continue
+		}
+
+		right, err := b.rightSeries(ctx, rightIdx)
+		if err != nil {
+			b.pool.putFPointSlice(left.Floats)
+			return InstantVectorSeriesData{}, err
+		}
+
+		b.outputCount--
+		result := b.compute(left, right)
+		b.pool.putFPointSlice(right.Floats)
+		return result, nil
+	}
+}
+
+// rightSeries returns the right-hand side series at index idx, reading and buffering the series before it if needed.
+func (b *VectorVectorBinaryOperation) rightSeries(ctx context.Context, idx int) (InstantVectorSeriesData, error) {
+	if data, ok := b.rightBuffer[idx]; ok {
+		delete(b.rightBuffer, idx)
+		return data, nil
+	}
+
+	for b.nextRightIndex <= idx {
+		data, err := b.Right.Next(ctx)
+		if err == EOS {
+			return InstantVectorSeriesData{}, errUnexpectedEndOfStream
+		}
+		if err != nil {
+			return InstantVectorSeriesData{}, err
+		}
+
+		current := b.nextRightIndex
+		b.nextRightIndex++
+
+		switch {
+		case current == idx:
+			return data, nil
+		case b.rightNeeded[current]:
+			b.rightBuffer[current] = data
+		default:
+			b.pool.putFPointSlice(data.Floats)
+		}
+	}
+
+	return InstantVectorSeriesData{}, fmt.Errorf("right-hand side series %d has already been consumed", idx)
+}
+
+// compute returns the result of the operation between the points of the two series, reusing the left-hand side slice.
+func (b *VectorVectorBinaryOperation) compute(left, right InstantVectorSeriesData) InstantVectorSeriesData {
+	points := left.Floats[:0]
+	rightPoints := right.Floats
+
+	for _, p := range left.Floats {
+		// Find the right-hand side point at the same step, if any.
+		for len(rightPoints) > 0 && rightPoints[0].T < p.T {
+			rightPoints = rightPoints[1:]
+		}
+		if len(rightPoints) == 0 {
+			break
+		}
+		if rightPoints[0].T != p.T {
+			continue
+		}
+
+		f, keep := binaryOperation(b.Op, p.F, rightPoints[0].F)
+		if b.ReturnBool {
+			f = boolToFloat(keep)
+			keep = true
+		}
+		if keep {
+			p.F = f
+			points = append(points, p)
+		}
+	}
+
+	return InstantVectorSeriesData{Floats: points}
+}
+
+func (b *VectorVectorBinaryOperation) Close() {
+	b.Left.Close()
+	b.Right.Close()
+
+	for idx, data := range b.rightBuffer {
+		b.pool.putFPointSlice(data.Floats)
+		delete(b.rightBuffer, idx)
+	}
+}
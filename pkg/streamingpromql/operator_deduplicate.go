@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"golang.org/x/exp/slices"
+)
+
+var errSameLabelset = errors.New("vector cannot contain metrics with the same labelset")
+
+// DeduplicateAndMerge merges the series produced by its inner operator that have the same labels, like
+// the series of different metrics once the metric name is dropped. Series are merged if they don't have
+// points at the same step, otherwise the query fails, in the same way as the Prometheus engine.
+//
+// Series with unique labels are passed through. Only the series with duplicated labels and some other
+// series with the same labels not read yet are held in memory.
+type DeduplicateAndMerge struct {
+	Inner InstantVectorOperator
+
+	// RequireNoOverlapAtAll is true if series with the same labels conflict even if they don't have points
+	// at the same step, as it happens for the output of range vector functions in the Prometheus engine.
+	RequireNoOverlapAtAll bool
+
+	pool *limitingPool
+
+	// remainingInnerSeriesToGroup holds the group of each input series not read yet.
+	remainingInnerSeriesToGroup []*deduplicationGroup
+
+	// remainingGroups holds the groups not returned yet, ordered by the index of their last input series.
+	remainingGroups []*deduplicationGroup
+}
+
+type deduplicationGroup struct {
+	lastSeriesIndex      int
+	remainingSeriesCount int
+	series               []InstantVectorSeriesData
+}
+
+var _ InstantVectorOperator = &DeduplicateAndMerge{}
+
+func (d *DeduplicateAndMerge) SeriesMetadata(ctx context.Context) ([]labels.Labels, error) {
+	innerSeries, err := d.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*deduplicationGroup, len(innerSeries))
+	groupLabels := make(map[*deduplicationGroup]labels.Labels, len(innerSeries))
+	d.remainingInnerSeriesToGroup = make([]*deduplicationGroup, 0, len(innerSeries))
+	buf := make([]byte, 0, 1024)
+
+	for i, l := range innerSeries {
+		key := string(l.Bytes(buf))
+		g, ok := groups[key]
+		if !ok {
+			g = &deduplicationGroup{}
+			groups[key] = g
+			groupLabels[g] = l
+		}
+
+		g.lastSeriesIndex = i
+		g.remainingSeriesCount++
+		d.remainingInnerSeriesToGroup = append(d.remainingInnerSeriesToGroup, g)
+	}
+
+	d.remainingGroups = make([]*deduplicationGroup, 0, len(groups))
+	for _, g := range groups {
+		d.remainingGroups = append(d.remainingGroups, g)
+	}
+
+	slices.SortFunc(d.remainingGroups, func(a, b *deduplicationGroup) bool {
+		return a.lastSeriesIndex < b.lastSeriesIndex
+	})
+
+	lbls := make([]labels.Labels, 0, len(d.remainingGroups))
+	for _, g := range d.remainingGroups {
+		lbls = append(lbls, groupLabels[g])
+	}
+
+	return lbls, nil
+}
+
+func (d *DeduplicateAndMerge) Next(ctx context.Context) (InstantVectorSeriesData, error) {
+	if len(d.remainingGroups) == 0 {
+		return InstantVectorSeriesData{}, EOS
+	}
+
+	thisGroup := d.remainingGroups[0]
+	d.remainingGroups = d.remainingGroups[1:]
+
+	for thisGroup.remainingSeriesCount > 0 {
+		data, err := d.Inner.Next(ctx)
+		if err == EOS {
+			return InstantVectorSeriesData{}, errUnexpectedEndOfStream
+		}
+		if err != nil {
+			return InstantVectorSeriesData{}, err
+		}
+
+		g := d.remainingInnerSeriesToGroup[0]
+		d.remainingInnerSeriesToGroup = d.remainingInnerSeriesToGroup[1:]
+		g.remainingSeriesCount--
+
+		if len(data.Floats) == 0 {
+			d.pool.putFPointSlice(data.Floats)
+			continue
+		}
+		g.series = append(g.series, data)
+	}
+
+	series := thisGroup.series
+	thisGroup.series = nil
+
+	switch len(series) {
+	case 0:
+		return InstantVectorSeriesData{}, nil
+	case 1:
+		return series[0], nil
+	}
+
+	if d.RequireNoOverlapAtAll {
+		d.releaseSeries(series)
+		return InstantVectorSeriesData{}, errSameLabelset
+	}
+
+	return d.mergeSeries(series)
+}
+
+// mergeSeries merges the points of the series into the first one, and releases the others. It returns
+// errSameLabelset if more than one series has a point at the same step.
+func (d *DeduplicateAndMerge) mergeSeries(series []InstantVectorSeriesData) (InstantVectorSeriesData, error) {
+	size := 0
+	for _, s := range series {
+		size += len(s.Floats)
+	}
+
+	merged, err := d.pool.getFPointSlice(size)
+	if err != nil {
+		d.releaseSeries(series)
+		return InstantVectorSeriesData{}, err
+	}
+
+	for _, s := range series {
+		merged = append(merged, s.Floats...)
+		d.pool.putFPointSlice(s.Floats)
+	}
+
+	slices.SortFunc(merged, func(a, b promql.FPoint) bool {
+		return a.T < b.T
+	})
+
+	for i := 1; i < len(merged); i++ {
+		if merged[i].T == merged[i-1].T {
+			d.pool.putFPointSlice(merged)
+			return InstantVectorSeriesData{}, errSameLabelset
+		}
+	}
+
+	return InstantVectorSeriesData{Floats: merged}, nil
+}
+
+func (d *DeduplicateAndMerge) releaseSeries(series []InstantVectorSeriesData) {
+	for _, s := range series {
+		d.pool.putFPointSlice(s.Floats)
+	}
+}
+
+func (d *DeduplicateAndMerge) Close() {
+	d.Inner.Close()
+
+	for _, g := range d.remainingGroups {
+		d.releaseSeries(g.series)
+		g.series = nil
+	}
+}
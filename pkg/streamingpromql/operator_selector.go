@@ -0,0 +1,345 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"golang.org/x/exp/slices"
+)
+
+// selector selects the series matching a vector or matrix selector. The labels of the series are
+// selected first, and the series with their samples are then read one at a time, as they're consumed
+// by the operator owning the selector.
+type selector struct {
+	timeRange queryTimeRange
+	matchers  []*labels.Matcher
+
+	offset int64 // Milliseconds.
+
+	// lookbackDelta is the lookback delta of vector selectors, or zero for matrix selectors.
+	lookbackDelta int64 // Milliseconds.
+
+	// rangeMillis is the range of matrix selectors, or zero for vector selectors.
+	rangeMillis int64
+
+	// function is the name of the function the selected series are passed to, if any.
+	function string
+
+	pool *limitingPool
+
+	// querier is shared by all the selectors of the query, and set when the query is executed.
+	querier storage.Querier
+
+	// remainingSeries holds the labels of the series not returned by next yet.
+	remainingSeries []labels.Labels
+	seriesSet       storage.SeriesSet
+	current         storage.Series
+}
+
+// selectHints returns the hints to select the series with, mirroring the time range the Prometheus
+// engine selects series over.
+func (s *selector) selectHints() *storage.SelectHints {
+	start := s.timeRange.start - s.offset
+	end := s.timeRange.end - s.offset
+	if s.rangeMillis == 0 {
+		start -= s.lookbackDelta
+	} else {
+		start -= s.rangeMillis
+	}
+
+	return &storage.SelectHints{
+		Start: start,
+		End:   end,
+		Step:  s.timeRange.interval,
+		Range: s.rangeMillis,
+		Func:  s.function,
+	}
+}
+
+func (s *selector) seriesMetadata() ([]labels.Labels, error) {
+	if s.querier == nil {
+		return nil, errors.New("series metadata requested before the querier was set")
+	}
+	if s.seriesSet != nil || s.remainingSeries != nil {
+		return nil, errors.New("series metadata already requested")
+	}
+
+	// Only the labels are needed here: the "series" function hint allows queriers to not fetch any chunks.
+	hints := s.selectHints()
+	hints.Func = "series"
+
+	ss := s.querier.Select(true, hints, s.matchers...)
+	s.remainingSeries = []labels.Labels{}
+	for ss.Next() {
+		s.remainingSeries = append(s.remainingSeries, ss.At().Labels())
+	}
+	if err := ss.Err(); err != nil {
+		return nil, err
+	}
+
+	// Operators may modify the returned slice.
+	return slices.Clone(s.remainingSeries), nil
+}
+
+// next returns the next selected series, or EOS if all series have been returned. The series are selected
+// again with their samples on the first call, and read from the series set as they're returned.
+func (s *selector) next() (storage.Series, error) {
+	if len(s.remainingSeries) == 0 {
+		return nil, EOS
+	}
+
+	lbls := s.remainingSeries[0]
+	s.remainingSeries = s.remainingSeries[1:]
+
+	if s.seriesSet == nil {
+		s.seriesSet = s.querier.Select(true, s.selectHints(), s.matchers...)
+	}
+
+	// Both selects return the series sorted by labels. A series not returned by the first select
+	// is skipped, and a series not returned by the second one doesn't have any samples.
+	for {
+		if s.current == nil {
+			if !s.seriesSet.Next() {
+				if err := s.seriesSet.Err(); err != nil {
+					return nil, err
+				}
+				break
+			}
+			s.current = s.seriesSet.At()
+		}
+
+		cmp := labels.Compare(s.current.Labels(), lbls)
+		if cmp > 0 {
+			break
+		}
+
+		series := s.current
+		s.current = nil
+		if cmp == 0 {
+			return series, nil
+		}
+	}
+
+	return storage.NewListSeries(lbls, nil), nil
+}
+
+func (s *selector) close() {
+	s.remainingSeries = nil
+	s.seriesSet = nil
+	s.current = nil
+}
+
+// InstantVectorSelector evaluates a vector selector, like "foo{bar="baz"}", at each step of the query.
+type InstantVectorSelector struct {
+	selector *selector
+
+	chunkIterator    chunkenc.Iterator
+	memoizedIterator *storage.MemoizedSeriesIterator
+}
+
+var _ InstantVectorOperator = &InstantVectorSelector{}
+
+func (v *InstantVectorSelector) SeriesMetadata(_ context.Context) ([]labels.Labels, error) {
+	return v.selector.seriesMetadata()
+}
+
+func (v *InstantVectorSelector) Next(_ context.Context) (InstantVectorSeriesData, error) {
+	series, err := v.selector.next()
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+
+	if v.memoizedIterator == nil {
+		v.memoizedIterator = storage.NewMemoizedEmptyIterator(v.selector.lookbackDelta)
+	}
+
+	v.chunkIterator = series.Iterator(v.chunkIterator)
+	v.memoizedIterator.Reset(v.chunkIterator)
+
+	timeRange := v.selector.timeRange
+	floats, err := v.selector.pool.getFPointSlice(timeRange.steps)
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+	data := InstantVectorSeriesData{Floats: floats}
+
+	for ts := timeRange.start; ts <= timeRange.end; ts += timeRange.interval {
+		refT := ts - v.selector.offset
+
+		var t int64
+		var f float64
+
+		valueType := v.memoizedIterator.Seek(refT)
+		switch valueType {
+		case chunkenc.ValNone:
+			if err := v.memoizedIterator.Err(); err != nil {
+				v.selector.pool.putFPointSlice(data.Floats)
+				return InstantVectorSeriesData{}, err
+			}
+		case chunkenc.ValFloat:
+			t, f = v.memoizedIterator.At()
+		default:
+			v.selector.pool.putFPointSlice(data.Floats)
+			return InstantVectorSeriesData{}, newNotSupportedError("native histogram samples")
+		}
+
+		if valueType == chunkenc.ValNone || t > refT {
+			var h *histogram.FloatHistogram
+			var ok bool
+			t, f, h, ok = v.memoizedIterator.PeekPrev()
+			if h != nil {
+				v.selector.pool.putFPointSlice(data.Floats)
+				return InstantVectorSeriesData{}, newNotSupportedError("native histogram samples")
+			}
+			if !ok || t < refT-v.selector.lookbackDelta {
+				continue
+			}
+		}
+
+		if value.IsStaleNaN(f) {
+			continue
+		}
+
+		data.Floats = append(data.Floats, promql.FPoint{T: ts, F: f})
+	}
+
+	return data, nil
+}
+
+func (v *InstantVectorSelector) Close() {
+	v.selector.close()
+}
+
+// rangeVectorFunction computes the output value of a range vector function from the float samples
+// in the range, at timestamp step. It returns false if there's no output value for the step.
+type rangeVectorFunction func(floats []promql.FPoint, step int64, rangeMillis, offset int64) (float64, bool)
+
+// RangeVectorFunction evaluates a range vector function, like "rate(foo[5m])", over a matrix selector
+// at each step of the query. Only the samples in the range of the current step are held in memory.
+type RangeVectorFunction struct {
+	selector *selector
+	function rangeVectorFunction
+
+	// keepMetricName is true if the function keeps the metric name of the input series.
+	keepMetricName bool
+
+	chunkIterator chunkenc.Iterator
+}
+
+var _ InstantVectorOperator = &RangeVectorFunction{}
+
+func (r *RangeVectorFunction) SeriesMetadata(_ context.Context) ([]labels.Labels, error) {
+	lbls, err := r.selector.seriesMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.keepMetricName {
+		for i := range lbls {
+			lbls[i] = dropMetricName(lbls[i])
+		}
+	}
+
+	return lbls, nil
+}
+
+func (r *RangeVectorFunction) Next(_ context.Context) (InstantVectorSeriesData, error) {
+	series, err := r.selector.next()
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+
+	r.chunkIterator = series.Iterator(r.chunkIterator)
+	it := r.chunkIterator
+
+	timeRange := r.selector.timeRange
+	floats, err := r.selector.pool.getFPointSlice(timeRange.steps)
+	if err != nil {
+		return InstantVectorSeriesData{}, err
+	}
+	data := InstantVectorSeriesData{Floats: floats}
+
+	// window holds the samples in the range of the current step. pending holds the first sample
+	// read from the iterator that is after the range of the current step, if any.
+	window, err := r.selector.pool.getFPointSlice(16)
+	if err != nil {
+		r.selector.pool.putFPointSlice(data.Floats)
+		return InstantVectorSeriesData{}, err
+	}
+	defer func() { r.selector.pool.putFPointSlice(window) }()
+
+	var pending promql.FPoint
+	hasPending := false
+	exhausted := false
+
+	for ts := timeRange.start; ts <= timeRange.end; ts += timeRange.interval {
+		maxt := ts - r.selector.offset
+		mint := maxt - r.selector.rangeMillis
+
+		// Drop samples that aren't in the range anymore.
+		drop := 0
+		for drop < len(window) && window[drop].T < mint {
+			drop++
+		}
+		if drop > 0 {
+			window = window[:copy(window, window[drop:])]
+		}
+
+		// Add the samples entering the range.
+		for !exhausted {
+			if !hasPending {
+				switch it.Next() {
+				case chunkenc.ValNone:
+					if err := it.Err(); err != nil {
+						r.selector.pool.putFPointSlice(data.Floats)
+						return InstantVectorSeriesData{}, err
+					}
+					exhausted = true
+					continue
+				case chunkenc.ValFloat:
+					pending.T, pending.F = it.At()
+					hasPending = true
+				default:
+					r.selector.pool.putFPointSlice(data.Floats)
+					return InstantVectorSeriesData{}, newNotSupportedError("native histogram samples")
+				}
+			}
+
+			if pending.T > maxt {
+				break
+			}
+
+			hasPending = false
+			if pending.T < mint || value.IsStaleNaN(pending.F) {
+				continue
+			}
+			window, err = r.selector.pool.appendFPoint(window, pending)
+			if err != nil {
+				r.selector.pool.putFPointSlice(data.Floats)
+				return InstantVectorSeriesData{}, err
+			}
+		}
+
+		if len(window) == 0 {
+			continue
+		}
+
+		if f, ok := r.function(window, ts, r.selector.rangeMillis, r.selector.offset); ok {
+			data.Floats = append(data.Floats, promql.FPoint{T: ts, F: f})
+		}
+	}
+
+	return data, nil
+}
+
+func (r *RangeVectorFunction) Close() {
+	r.selector.close()
+}
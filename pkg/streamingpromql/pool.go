@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"unsafe"
+
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/util/pool"
+
+	"github.com/grafana/mimir/pkg/util/limiter"
+)
+
+// maxExpectedPointsPerSeries is the largest slice size the pools below keep track of. Bigger
+// slices are allocated and released as usual.
+const maxExpectedPointsPerSeries = 100_000
+
+var (
+	fPointSlicePool = pool.New(1, maxExpectedPointsPerSeries, 10, func(size int) interface{} {
+		return make([]promql.FPoint, 0, size)
+	})
+
+	float64SlicePool = pool.New(1, maxExpectedPointsPerSeries, 10, func(size int) interface{} {
+		return make([]float64, 0, size)
+	})
+)
+
+// Size of the elements of the pooled slices, used to estimate the memory consumed by the slices.
+const (
+	fPointSize  = uint64(unsafe.Sizeof(promql.FPoint{}))
+	float64Size = uint64(unsafe.Sizeof(float64(0)))
+)
+
+// limitingPool takes slices from the pools above, and tracks the estimated memory consumed by the slices
+// in use by a query, returning an error if taking a slice would exceed the query memory consumption limit.
+type limitingPool struct {
+	tracker *limiter.MemoryConsumptionTracker
+}
+
+func newLimitingPool() *limitingPool {
+	return &limitingPool{tracker: limiter.NewMemoryConsumptionTracker(0, nil)}
+}
+
+// getFPointSlice returns an empty slice of points with a capacity of at least size.
+func (p *limitingPool) getFPointSlice(size int) ([]promql.FPoint, error) {
+	if size <= 0 {
+		return nil, nil
+	}
+
+	s := fPointSlicePool.Get(size).([]promql.FPoint)[:0]
+	if err := p.tracker.IncreaseMemoryConsumption(uint64(cap(s)) * fPointSize); err != nil {
+		fPointSlicePool.Put(s)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (p *limitingPool) putFPointSlice(s []promql.FPoint) {
+	if s != nil {
+		p.tracker.DecreaseMemoryConsumption(uint64(cap(s)) * fPointSize)
+		fPointSlicePool.Put(s[:0])
+	}
+}
+
+// appendFPoint appends the point to the slice of points, replacing the slice with a bigger one
+// taken from the pool if it's full. If an error is returned, the input slice is returned unchanged.
+func (p *limitingPool) appendFPoint(s []promql.FPoint, point promql.FPoint) ([]promql.FPoint, error) {
+	if len(s) == cap(s) {
+		bigger, err := p.getFPointSlice(2*cap(s) + 1)
+		if err != nil {
+			return s, err
+		}
+
+		bigger = append(bigger, s...)
+		p.putFPointSlice(s)
+		s = bigger
+	}
+
+	return append(s, point), nil
+}
+
+// getFloat64Slice returns a zeroed slice of floats with a length of size.
+func (p *limitingPool) getFloat64Slice(size int) ([]float64, error) {
+	s := float64SlicePool.Get(size).([]float64)[:size]
+	if err := p.tracker.IncreaseMemoryConsumption(uint64(cap(s)) * float64Size); err != nil {
+		float64SlicePool.Put(s[:0])
+		return nil, err
+	}
+
+	for i := range s {
+		s[i] = 0
+	}
+	return s, nil
+}
+
+func (p *limitingPool) putFloat64Slice(s []float64) {
+	if s != nil {
+		p.tracker.DecreaseMemoryConsumption(uint64(cap(s)) * float64Size)
+		float64SlicePool.Put(s[:0])
+	}
+}
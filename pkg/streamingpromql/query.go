@@ -0,0 +1,508 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package streamingpromql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/stats"
+	"golang.org/x/exp/slices"
+
+	querierapi "github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/util/limiter"
+)
+
+// Query is a query evaluated by the streaming engine. It implements promql.Query.
+type Query struct {
+	engine    *Engine
+	queryable storage.Queryable
+	qs        string
+	statement *parser.EvalStmt
+	timeRange queryTimeRange
+	root      InstantVectorOperator
+	selectors []*selector
+	timers    *stats.QueryTimers
+	cancel    context.CancelFunc
+	result    *promql.Result
+
+	// pool is shared by all the operators of the query. Its memory consumption tracker is replaced
+	// with the one of the query context when the query is executed.
+	pool *limitingPool
+}
+
+var _ promql.Query = &Query{}
+
+func newQuery(engine *Engine, queryable storage.Queryable, opts promql.QueryOpts, qs string, start, end time.Time, interval time.Duration) (*Query, error) {
+	lookbackDelta := engine.lookbackDelta
+	if opts != nil && opts.LookbackDelta() > 0 {
+		lookbackDelta = opts.LookbackDelta()
+	}
+
+	expr, err := parser.ParseExpr(qs)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{
+		engine:    engine,
+		queryable: queryable,
+		qs:        qs,
+		statement: &parser.EvalStmt{
+			Expr:          expr,
+			Start:         start,
+			End:           end,
+			Interval:      interval,
+			LookbackDelta: lookbackDelta,
+		},
+		timeRange: newQueryTimeRange(timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()),
+		timers:    stats.NewQueryTimers(),
+		pool:      newLimitingPool(),
+	}
+
+	if expr.Type() != parser.ValueTypeVector {
+		return nil, newNotSupportedError(fmt.Sprintf("%s expression", parser.DocumentedType(expr.Type())))
+	}
+
+	q.root, err = q.convertToOperator(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// convertToOperator returns the operator evaluating the instant vector expression expr.
+func (q *Query) convertToOperator(expr parser.Expr) (InstantVectorOperator, error) {
+	switch e := expr.(type) {
+	case *parser.VectorSelector:
+		s, err := q.newSelector(e, 0, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return &InstantVectorSelector{selector: s}, nil
+
+	case *parser.AggregateExpr:
+		if _, ok := supportedAggregations[e.Op]; !ok || e.Param != nil {
+			return nil, newNotSupportedError(fmt.Sprintf("'%s' aggregation", e.Op))
+		}
+
+		inner, err := q.convertToOperator(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		grouping := slices.Clone(e.Grouping)
+		slices.Sort(grouping)
+
+		return &Aggregation{
+			Inner:     inner,
+			Op:        e.Op,
+			Grouping:  grouping,
+			Without:   e.Without,
+			timeRange: q.timeRange,
+			pool:      q.pool,
+		}, nil
+
+	case *parser.Call:
+		return q.convertFunctionCallToOperator(e)
+
+	case *parser.BinaryExpr:
+		return q.convertBinaryExpressionToOperator(e)
+
+	case *parser.UnaryExpr:
+		inner, err := q.convertToOperator(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.Op != parser.SUB {
+			return inner, nil
+		}
+
+		negation := &InstantVectorFunction{Inner: inner, Function: func(f float64) float64 { return -f }}
+		return &DeduplicateAndMerge{Inner: negation, RequireNoOverlapAtAll: true, pool: q.pool}, nil
+
+	case *parser.ParenExpr:
+		return q.convertToOperator(e.Expr)
+
+	default:
+		return nil, newNotSupportedError(fmt.Sprintf("PromQL expression type %T", e))
+	}
+}
+
+func (q *Query) convertFunctionCallToOperator(e *parser.Call) (InstantVectorOperator, error) {
+	if f, ok := instantVectorFunctions[e.Func.Name]; ok {
+		inner, err := q.convertToOperator(e.Args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return &DeduplicateAndMerge{Inner: &InstantVectorFunction{Inner: inner, Function: f}, pool: q.pool}, nil
+	}
+
+	f, ok := rangeVectorFunctions[e.Func.Name]
+	if !ok {
+		return nil, newNotSupportedError(fmt.Sprintf("'%s' function", e.Func.Name))
+	}
+
+	arg := e.Args[0]
+	for {
+		paren, ok := arg.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		arg = paren.Expr
+	}
+
+	ms, ok := arg.(*parser.MatrixSelector)
+	if !ok {
+		return nil, newNotSupportedError(fmt.Sprintf("'%s' function over %T", e.Func.Name, arg))
+	}
+
+	s, err := q.newSelector(ms.VectorSelector.(*parser.VectorSelector), ms.Range, e.Func.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last_over_time function keeps the metric name, so the output series can't have the same labels.
+	keepMetricName := e.Func.Name == "last_over_time"
+	op := &RangeVectorFunction{selector: s, function: f, keepMetricName: keepMetricName}
+	if keepMetricName {
+		return op, nil
+	}
+
+	return &DeduplicateAndMerge{Inner: op, RequireNoOverlapAtAll: true, pool: q.pool}, nil
+}
+
+func (q *Query) convertBinaryExpressionToOperator(e *parser.BinaryExpr) (InstantVectorOperator, error) {
+	if _, ok := supportedBinaryOperations[e.Op]; !ok {
+		return nil, newNotSupportedError(fmt.Sprintf("'%s' binary operation", e.Op))
+	}
+
+	lhsType, rhsType := e.LHS.Type(), e.RHS.Type()
+
+	if lhsType == parser.ValueTypeVector && rhsType == parser.ValueTypeVector {
+		if e.VectorMatching == nil || e.VectorMatching.Card != parser.CardOneToOne {
+			return nil, newNotSupportedError("binary operation with many-to-one or one-to-many matching")
+		}
+
+		left, err := q.convertToOperator(e.LHS)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := q.convertToOperator(e.RHS)
+		if err != nil {
+			return nil, err
+		}
+
+		return &VectorVectorBinaryOperation{
+			Left:       left,
+			Right:      right,
+			Op:         e.Op,
+			Matching:   e.VectorMatching,
+			ReturnBool: e.ReturnBool,
+			pool:       q.pool,
+		}, nil
+	}
+
+	vectorExpr, scalarExpr, scalarSide := e.LHS, e.RHS, false
+	if lhsType == parser.ValueTypeScalar {
+		vectorExpr, scalarExpr, scalarSide = e.RHS, e.LHS, true
+	}
+
+	scalar, ok := constantScalar(scalarExpr)
+	if !ok {
+		return nil, newNotSupportedError("binary operation with a non-constant scalar")
+	}
+
+	vector, err := q.convertToOperator(vectorExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &VectorScalarBinaryOperation{
+		Vector:     vector,
+		Scalar:     scalar,
+		Op:         e.Op,
+		ScalarSide: scalarSide,
+		ReturnBool: e.ReturnBool,
+	}
+
+	if !shouldDropMetricName(e.Op) && !e.ReturnBool {
+		// The metric name is kept, so the output series can't have the same labels.
+		return op, nil
+	}
+
+	return &DeduplicateAndMerge{Inner: op, pool: q.pool}, nil
+}
+
+// constantScalar returns the value of the scalar expression expr, if it only contains number literals.
+func constantScalar(expr parser.Expr) (float64, bool) {
+	switch e := expr.(type) {
+	case *parser.NumberLiteral:
+		return e.Val, true
+
+	case *parser.ParenExpr:
+		return constantScalar(e.Expr)
+
+	case *parser.UnaryExpr:
+		v, ok := constantScalar(e.Expr)
+		if e.Op == parser.SUB {
+			v = -v
+		}
+		return v, ok
+
+	case *parser.BinaryExpr:
+		if _, ok := supportedBinaryOperations[e.Op]; !ok {
+			return 0, false
+		}
+
+		lhs, ok := constantScalar(e.LHS)
+		if !ok {
+			return 0, false
+		}
+
+		rhs, ok := constantScalar(e.RHS)
+		if !ok {
+			return 0, false
+		}
+
+		v, keep := binaryOperation(e.Op, lhs, rhs)
+		if e.Op.IsComparisonOperator() {
+			// Comparisons between scalars always require the bool modifier.
+			v = boolToFloat(keep)
+		}
+		return v, true
+
+	default:
+		return 0, false
+	}
+}
+
+func (q *Query) newSelector(vs *parser.VectorSelector, rng time.Duration, function string) (*selector, error) {
+	if vs.Timestamp != nil || vs.StartOrEnd != 0 {
+		return nil, newNotSupportedError("@ modifier")
+	}
+
+	if vs.OriginalOffset < 0 && !q.engine.enableNegativeOffset {
+		return nil, newNotSupportedError("negative offset")
+	}
+
+	s := &selector{
+		timeRange:   q.timeRange,
+		matchers:    vs.LabelMatchers,
+		offset:      vs.OriginalOffset.Milliseconds(),
+		rangeMillis: rng.Milliseconds(),
+		function:    function,
+		pool:        q.pool,
+	}
+
+	if rng == 0 {
+		s.lookbackDelta = q.statement.LookbackDelta.Milliseconds()
+	}
+
+	q.selectors = append(q.selectors, s)
+	return s, nil
+}
+
+// newQuerier returns a querier over the time range of all the selectors of the query, like the Prometheus
+// engine does, and sets it as the querier of the selectors. Sharing a querier makes the per-query limits
+// of the queryable apply to the whole query.
+func (q *Query) newQuerier(ctx context.Context) (storage.Querier, error) {
+	mint, maxt := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, s := range q.selectors {
+		hints := s.selectHints()
+		if hints.Start < mint {
+			mint = hints.Start
+		}
+		if hints.End > maxt {
+			maxt = hints.End
+		}
+	}
+
+	querier, err := q.queryable.Querier(querierapi.ContextForQueryEngineQuerier(ctx), mint, maxt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range q.selectors {
+		s.querier = querier
+	}
+
+	return querier, nil
+}
+
+func (q *Query) Exec(ctx context.Context) *promql.Result {
+	defer q.root.Close()
+
+	q.pool.tracker = limiter.MemoryConsumptionTrackerFromContextWithFallback(ctx)
+
+	ctx, cancel := context.WithCancel(ctx)
+	q.cancel = cancel
+	defer cancel()
+
+	if q.engine.timeout != 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, q.engine.timeout)
+		defer cancelTimeout()
+	}
+
+	execSpanTimer, ctx := q.timers.GetSpanTimer(ctx, stats.ExecTotalTime)
+	defer execSpanTimer.Finish()
+
+	if tracker := q.engine.activeQueryTracker; tracker != nil {
+		queueSpanTimer, _ := q.timers.GetSpanTimer(ctx, stats.ExecQueueTime)
+		queryID, err := tracker.Insert(ctx, q.qs)
+		queueSpanTimer.Finish()
+		if err != nil {
+			q.result = &promql.Result{Err: convertContextError(err)}
+			return q.result
+		}
+		defer tracker.Delete(queryID)
+	}
+
+	evalSpanTimer, ctx := q.timers.GetSpanTimer(ctx, stats.EvalTotalTime)
+	defer evalSpanTimer.Finish()
+
+	querier, err := q.newQuerier(ctx)
+	if err != nil {
+		q.result = &promql.Result{Err: convertContextError(err)}
+		return q.result
+	}
+	defer querier.Close()
+
+	series, err := q.root.SeriesMetadata(ctx)
+	if err != nil {
+		q.result = &promql.Result{Err: convertContextError(err)}
+		return q.result
+	}
+
+	var value parser.Value
+	if q.IsInstant() {
+		value, err = q.populateVector(ctx, series)
+	} else {
+		value, err = q.populateMatrix(ctx, series)
+	}
+
+	if err != nil {
+		q.result = &promql.Result{Err: convertContextError(err)}
+		return q.result
+	}
+
+	q.result = &promql.Result{Value: value}
+	return q.result
+}
+
+// IsInstant returns true if the query is an instant query.
+func (q *Query) IsInstant() bool {
+	return q.statement.Start == q.statement.End && q.statement.Interval == 0
+}
+
+func (q *Query) populateVector(ctx context.Context, series []labels.Labels) (promql.Vector, error) {
+	v := make(promql.Vector, 0, len(series))
+
+	for _, s := range series {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		d, err := q.root.Next(ctx)
+		if err != nil {
+			if errors.Is(err, EOS) {
+				return nil, errUnexpectedEndOfStream
+			}
+			return nil, err
+		}
+
+		for _, p := range d.Floats {
+			v = append(v, promql.Sample{Metric: s, T: p.T, F: p.F})
+		}
+
+		q.pool.putFPointSlice(d.Floats)
+	}
+
+	return v, nil
+}
+
+func (q *Query) populateMatrix(ctx context.Context, series []labels.Labels) (promql.Matrix, error) {
+	m := make(promql.Matrix, 0, len(series))
+
+	for _, s := range series {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		d, err := q.root.Next(ctx)
+		if err != nil {
+			if errors.Is(err, EOS) {
+				return nil, errUnexpectedEndOfStream
+			}
+			return nil, err
+		}
+
+		if len(d.Floats) == 0 {
+			q.pool.putFPointSlice(d.Floats)
+			continue
+		}
+
+		m = append(m, promql.Series{Metric: s, Floats: d.Floats})
+	}
+
+	sort.Sort(m)
+
+	return m, nil
+}
+
+// convertContextError converts context errors to the errors returned by the Prometheus engine.
+func convertContextError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return promql.ErrQueryCanceled("query execution")
+	case errors.Is(err, context.DeadlineExceeded):
+		return promql.ErrQueryTimeout("query execution")
+	default:
+		return err
+	}
+}
+
+func (q *Query) Close() {
+	if q.result == nil {
+		return
+	}
+
+	if m, ok := q.result.Value.(promql.Matrix); ok {
+		for _, s := range m {
+			q.pool.putFPointSlice(s.Floats)
+		}
+	}
+}
+
+func (q *Query) Statement() parser.Statement {
+	return q.statement
+}
+
+func (q *Query) Stats() *stats.Statistics {
+	return &stats.Statistics{Timers: q.timers}
+}
+
+func (q *Query) Cancel() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+}
+
+func (q *Query) String() string {
+	return q.qs
+}
@@ -14,23 +14,24 @@ type ID string
 const (
 	errPrefix = "err-mimir-"
 
-	MissingMetricName             ID = "missing-metric-name"
-	InvalidMetricName             ID = "metric-name-invalid"
-	MaxLabelNamesPerSeries        ID = "max-label-names-per-series"
-	MaxNativeHistogramBuckets     ID = "max-native-histogram-buckets"
-	SeriesInvalidLabel            ID = "label-invalid"
-	SeriesLabelNameTooLong        ID = "label-name-too-long"
-	SeriesLabelValueTooLong       ID = "label-value-too-long"
-	SeriesWithDuplicateLabelNames ID = "duplicate-label-names"
-	SeriesLabelsNotSorted         ID = "labels-not-sorted"
-	SampleTooFarInFuture          ID = "too-far-in-future"
-	MaxSeriesPerMetric            ID = "max-series-per-metric"
-	MaxMetadataPerMetric          ID = "max-metadata-per-metric"
-	MaxSeriesPerUser              ID = "max-series-per-user"
-	MaxMetadataPerUser            ID = "max-metadata-per-user"
-	MaxChunksPerQuery             ID = "max-chunks-per-query"
-	MaxSeriesPerQuery             ID = "max-series-per-query"
-	MaxChunkBytesPerQuery         ID = "max-chunks-bytes-per-query"
+	MissingMetricName                          ID = "missing-metric-name"
+	InvalidMetricName                          ID = "metric-name-invalid"
+	MaxLabelNamesPerSeries                     ID = "max-label-names-per-series"
+	MaxNativeHistogramBuckets                  ID = "max-native-histogram-buckets"
+	SeriesInvalidLabel                         ID = "label-invalid"
+	SeriesLabelNameTooLong                     ID = "label-name-too-long"
+	SeriesLabelValueTooLong                    ID = "label-value-too-long"
+	SeriesWithDuplicateLabelNames              ID = "duplicate-label-names"
+	SeriesLabelsNotSorted                      ID = "labels-not-sorted"
+	SampleTooFarInFuture                       ID = "too-far-in-future"
+	MaxSeriesPerMetric                         ID = "max-series-per-metric"
+	MaxMetadataPerMetric                       ID = "max-metadata-per-metric"
+	MaxSeriesPerUser                           ID = "max-series-per-user"
+	MaxMetadataPerUser                         ID = "max-metadata-per-user"
+	MaxChunksPerQuery                          ID = "max-chunks-per-query"
+	MaxSeriesPerQuery                          ID = "max-series-per-query"
+	MaxChunkBytesPerQuery                      ID = "max-chunks-bytes-per-query"
+	MaxEstimatedChunksAndSamplesMemoryPerQuery ID = "max-estimated-chunks-and-samples-memory-per-query"
 
 	DistributorMaxIngestionRate             ID = "distributor-max-ingestion-rate"
 	DistributorMaxInflightPushRequests      ID = "distributor-max-inflight-push-requests"
@@ -40,6 +41,9 @@ const (
 	IngesterMaxTenants              ID = "ingester-max-tenants"
 	IngesterMaxInMemorySeries       ID = "ingester-max-series"
 	IngesterMaxInflightPushRequests ID = "ingester-max-inflight-push-requests"
+	IngesterTooBusy                 ID = "ingester-too-busy"
+
+	StoreGatewayTooBusy ID = "store-gateway-too-busy"
 
 	ExemplarLabelsMissing    ID = "exemplar-labels-missing"
 	ExemplarLabelsTooLong    ID = "exemplar-labels-too-long"
@@ -53,12 +57,16 @@ const (
 	MaxQueryLength              ID = "max-query-length"
 	MaxTotalQueryLength         ID = "max-total-query-length"
 	MaxQueryExpressionSizeBytes ID = "max-query-expression-size-bytes"
+	MaxQueryCost                ID = "max-query-cost"
+	QueryCostBudgetExhausted    ID = "query-cost-budget-exhausted"
+	MaxRemoteReadResponseSize   ID = "max-remote-read-response-size"
 	RequestRateLimited          ID = "tenant-max-request-rate"
 	IngestionRateLimited        ID = "tenant-max-ingestion-rate"
 	TooManyHAClusters           ID = "tenant-too-many-ha-clusters"
 
 	SampleTimestampTooOld    ID = "sample-timestamp-too-old"
 	SampleOutOfOrder         ID = "sample-out-of-order"
+	HistogramOutOfOrder      ID = "histogram-out-of-order"
 	SampleDuplicateTimestamp ID = "sample-duplicate-timestamp"
 	ExemplarSeriesMissing    ID = "exemplar-series-missing"
 
@@ -66,6 +74,8 @@ const (
 	BucketIndexTooOld           ID = "bucket-index-too-old"
 
 	DistributorMaxWriteMessageSize ID = "distributor-max-write-message-size"
+
+	TenantFederationMaxTenantsPerPattern ID = "tenant-federation-max-tenants-per-pattern"
 )
 
 // Message returns the provided msg, appending the error id.
@@ -101,6 +111,11 @@ func (id ID) LabelValue() string {
 	return strings.ReplaceAll(string(id), "-", "_")
 }
 
+// IsInMessage returns whether the provided error message contains the error ID.
+func (id ID) IsInMessage(msg string) bool {
+	return strings.Contains(msg, errPrefix+string(id))
+}
+
 func buildFlagsList(flag string, addFlags ...string) (string, string) {
 	var sb strings.Builder
 	sb.WriteString("-")
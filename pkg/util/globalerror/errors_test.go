@@ -15,6 +15,13 @@ func TestID_Message(t *testing.T) {
 		MissingMetricName.Message("an error"))
 }
 
+func TestID_IsInMessage(t *testing.T) {
+	assert.True(t, MissingMetricName.IsInMessage(MissingMetricName.Message("an error")))
+	assert.True(t, MissingMetricName.IsInMessage("rpc error: "+MissingMetricName.Message("an error")))
+	assert.False(t, MissingMetricName.IsInMessage(InvalidMetricName.Message("an error")))
+	assert.False(t, MissingMetricName.IsInMessage("an error"))
+}
+
 func TestID_MessageWithPerInstanceLimitConfig(t *testing.T) {
 	for _, tc := range []struct {
 		expected string
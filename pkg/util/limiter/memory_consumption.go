@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/util/globalerror"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+type memoryConsumptionTrackerCtxKey struct{}
+
+var (
+	memoryConsumptionTrackerKey = &memoryConsumptionTrackerCtxKey{}
+
+	MaxEstimatedChunksAndSamplesMemoryPerQueryMsgFormat = globalerror.MaxEstimatedChunksAndSamplesMemoryPerQuery.MessageWithStrategyAndPerTenantLimitConfig(
+		"the query exceeded the maximum allowed estimated amount of memory held by the chunks and samples of a single query (limit: %d bytes)",
+		cardinalityStrategy,
+		validation.MaxEstimatedChunksAndSamplesMemoryPerQueryFlag,
+	)
+)
+
+// MemoryConsumptionTracker tracks the estimated memory consumed by a single query, and rejects
+// any allocation which would make it exceed the configured limit.
+type MemoryConsumptionTracker struct {
+	mtx     sync.Mutex
+	current uint64
+	peak    uint64
+
+	maxEstimatedMemoryConsumptionBytes uint64
+	limitExceeded                      bool
+
+	queryMetrics *stats.QueryMetrics
+
+	// parent is the tracker of the whole query, if this tracker has been created with NewChild.
+	parent *MemoryConsumptionTracker
+}
+
+// NewMemoryConsumptionTracker makes a new per-query memory consumption tracker. A limit of 0 disables the limit,
+// but the memory consumption is still tracked.
+func NewMemoryConsumptionTracker(maxEstimatedMemoryConsumptionBytes uint64, queryMetrics *stats.QueryMetrics) *MemoryConsumptionTracker {
+	return &MemoryConsumptionTracker{
+		maxEstimatedMemoryConsumptionBytes: maxEstimatedMemoryConsumptionBytes,
+		queryMetrics:                       queryMetrics,
+	}
+}
+
+// NewChild returns a tracker recording the memory consumed by a part of the query, like the chunks fetched by
+// a querier, in both the returned tracker and t. Both the input limit, enforced on the memory recorded by the
+// child only, and the limit of t apply to the memory recorded by the child. A limit of 0 disables the limit
+// of the child.
+func (t *MemoryConsumptionTracker) NewChild(maxEstimatedMemoryConsumptionBytes uint64, queryMetrics *stats.QueryMetrics) *MemoryConsumptionTracker {
+	child := NewMemoryConsumptionTracker(maxEstimatedMemoryConsumptionBytes, queryMetrics)
+	child.parent = t
+	return child
+}
+
+func AddMemoryConsumptionTrackerToContext(ctx context.Context, tracker *MemoryConsumptionTracker) context.Context {
+	return context.WithValue(ctx, memoryConsumptionTrackerKey, tracker)
+}
+
+// MemoryConsumptionTrackerFromContextWithFallback returns a MemoryConsumptionTracker from the current context.
+// If there is not a MemoryConsumptionTracker on the context it will return a new unlimited tracker.
+func MemoryConsumptionTrackerFromContextWithFallback(ctx context.Context) *MemoryConsumptionTracker {
+	t, ok := ctx.Value(memoryConsumptionTrackerKey).(*MemoryConsumptionTracker)
+	if !ok {
+		t = NewMemoryConsumptionTracker(0, nil)
+	}
+	return t
+}
+
+// IncreaseMemoryConsumption records the allocation of the input bytes and returns an error if the limit is
+// exceeded. When an error is returned, the allocation isn't recorded, and the caller must not perform it.
+func (t *MemoryConsumptionTracker) IncreaseMemoryConsumption(bytes uint64) error {
+	t.mtx.Lock()
+	if t.maxEstimatedMemoryConsumptionBytes > 0 && t.current+bytes > t.maxEstimatedMemoryConsumptionBytes {
+		defer t.mtx.Unlock()
+
+		if !t.limitExceeded && t.queryMetrics != nil {
+			// If we've just exceeded the limit for the first time for this query, increment the failed query metric.
+			t.queryMetrics.QueriesRejectedTotal.WithLabelValues(stats.RejectReasonMaxMemory).Inc()
+		}
+		t.limitExceeded = true
+
+		return validation.LimitError(fmt.Sprintf(MaxEstimatedChunksAndSamplesMemoryPerQueryMsgFormat, t.maxEstimatedMemoryConsumptionBytes))
+	}
+
+	// Reserve the memory before checking the limit of the parent, so that concurrent allocations
+	// can't exceed the limit of this tracker.
+	t.current += bytes
+	t.mtx.Unlock()
+
+	if t.parent != nil {
+		if err := t.parent.IncreaseMemoryConsumption(bytes); err != nil {
+			t.mtx.Lock()
+			t.current -= bytes
+			t.mtx.Unlock()
+
+			return err
+		}
+	}
+
+	t.mtx.Lock()
+	if t.current > t.peak {
+		t.peak = t.current
+	}
+	t.mtx.Unlock()
+
+	return nil
+}
+
+// DecreaseMemoryConsumption records the release of the input bytes, previously recorded with IncreaseMemoryConsumption.
+func (t *MemoryConsumptionTracker) DecreaseMemoryConsumption(bytes uint64) {
+	t.mtx.Lock()
+	if bytes > t.current {
+		// This should never happen, but we don't want to underflow in case of bugs.
+		bytes = t.current
+	}
+	t.current -= bytes
+	t.mtx.Unlock()
+
+	if t.parent != nil {
+		t.parent.DecreaseMemoryConsumption(bytes)
+	}
+}
+
+// DecreaseAllMemoryConsumption records the release of all the memory recorded by the tracker and not released yet.
+func (t *MemoryConsumptionTracker) DecreaseAllMemoryConsumption() {
+	t.DecreaseMemoryConsumption(t.CurrentEstimatedMemoryConsumptionBytes())
+}
+
+// CurrentEstimatedMemoryConsumptionBytes returns the estimated memory currently consumed by the query.
+func (t *MemoryConsumptionTracker) CurrentEstimatedMemoryConsumptionBytes() uint64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.current
+}
+
+// PeakEstimatedMemoryConsumptionBytes returns the highest estimated memory consumed by the query so far.
+func (t *MemoryConsumptionTracker) PeakEstimatedMemoryConsumptionBytes() uint64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.peak
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestMemoryConsumptionTracker_Unlimited(t *testing.T) {
+	tracker := NewMemoryConsumptionTracker(0, nil)
+
+	require.NoError(t, tracker.IncreaseMemoryConsumption(128))
+	assert.Equal(t, uint64(128), tracker.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(128), tracker.PeakEstimatedMemoryConsumptionBytes())
+
+	require.NoError(t, tracker.IncreaseMemoryConsumption(1<<40))
+	tracker.DecreaseMemoryConsumption(1 << 40)
+	assert.Equal(t, uint64(128), tracker.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(128+1<<40), tracker.PeakEstimatedMemoryConsumptionBytes())
+}
+
+func TestMemoryConsumptionTracker_Limited(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	queryMetrics := stats.NewQueryMetrics(reg)
+	tracker := NewMemoryConsumptionTracker(100, queryMetrics)
+
+	require.NoError(t, tracker.IncreaseMemoryConsumption(60))
+	require.NoError(t, tracker.IncreaseMemoryConsumption(40))
+	assert.Equal(t, uint64(100), tracker.CurrentEstimatedMemoryConsumptionBytes())
+
+	// Exceeding the limit should fail without recording the allocation.
+	err := tracker.IncreaseMemoryConsumption(1)
+	require.Error(t, err)
+	assert.Equal(t, validation.LimitError(fmt.Sprintf(MaxEstimatedChunksAndSamplesMemoryPerQueryMsgFormat, 100)), err)
+	assert.Equal(t, uint64(100), tracker.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(100), tracker.PeakEstimatedMemoryConsumptionBytes())
+
+	// Once some memory is released, allocations within the limit should succeed again.
+	tracker.DecreaseMemoryConsumption(50)
+	require.NoError(t, tracker.IncreaseMemoryConsumption(50))
+	require.Error(t, tracker.IncreaseMemoryConsumption(50))
+
+	// The rejected query should be counted only once.
+	assert.Equal(t, float64(1), testutil.ToFloat64(queryMetrics.QueriesRejectedTotal.WithLabelValues(stats.RejectReasonMaxMemory)))
+}
+
+func TestMemoryConsumptionTracker_DecreaseShouldNotUnderflow(t *testing.T) {
+	tracker := NewMemoryConsumptionTracker(0, nil)
+
+	require.NoError(t, tracker.IncreaseMemoryConsumption(10))
+	tracker.DecreaseMemoryConsumption(20)
+	assert.Equal(t, uint64(0), tracker.CurrentEstimatedMemoryConsumptionBytes())
+}
+
+func TestMemoryConsumptionTrackerFromContextWithFallback(t *testing.T) {
+	tracker := NewMemoryConsumptionTracker(100, nil)
+	ctx := AddMemoryConsumptionTrackerToContext(context.Background(), tracker)
+	assert.Same(t, tracker, MemoryConsumptionTrackerFromContextWithFallback(ctx))
+
+	fallback := MemoryConsumptionTrackerFromContextWithFallback(context.Background())
+	require.NotNil(t, fallback)
+	require.NoError(t, fallback.IncreaseMemoryConsumption(1<<40))
+}
+
+func TestMemoryConsumptionTracker_Child(t *testing.T) {
+	tracker := NewMemoryConsumptionTracker(100, nil)
+	require.NoError(t, tracker.IncreaseMemoryConsumption(10))
+
+	child := tracker.NewChild(0, nil)
+	require.NoError(t, child.IncreaseMemoryConsumption(60))
+	assert.Equal(t, uint64(60), child.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(70), tracker.CurrentEstimatedMemoryConsumptionBytes())
+
+	// The limit of the parent applies to the child, and rejected allocations are recorded in neither of them.
+	require.Error(t, child.IncreaseMemoryConsumption(31))
+	assert.Equal(t, uint64(60), child.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(70), tracker.CurrentEstimatedMemoryConsumptionBytes())
+
+	child.DecreaseMemoryConsumption(20)
+	assert.Equal(t, uint64(40), child.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(50), tracker.CurrentEstimatedMemoryConsumptionBytes())
+
+	// Releasing all the memory of the child only releases the memory recorded by the child.
+	child.DecreaseAllMemoryConsumption()
+	assert.Equal(t, uint64(0), child.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(10), tracker.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(70), tracker.PeakEstimatedMemoryConsumptionBytes())
+}
+
+func TestMemoryConsumptionTracker_ChildWithLimit(t *testing.T) {
+	tracker := NewMemoryConsumptionTracker(100, nil)
+
+	child1 := tracker.NewChild(50, nil)
+	child2 := tracker.NewChild(50, nil)
+	require.NoError(t, child1.IncreaseMemoryConsumption(40))
+	require.NoError(t, child2.IncreaseMemoryConsumption(40))
+
+	// The limit of the child applies to the memory recorded by the child only, and rejected allocations are
+	// recorded in neither the child nor the parent.
+	require.Error(t, child1.IncreaseMemoryConsumption(11))
+	assert.Equal(t, uint64(40), child1.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(80), tracker.CurrentEstimatedMemoryConsumptionBytes())
+
+	// The limit of the parent still applies, and the rejected allocation isn't recorded by the child.
+	require.NoError(t, tracker.IncreaseMemoryConsumption(15))
+	require.Error(t, child2.IncreaseMemoryConsumption(10))
+	assert.Equal(t, uint64(40), child2.CurrentEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(40), child2.PeakEstimatedMemoryConsumptionBytes())
+	assert.Equal(t, uint64(95), tracker.CurrentEstimatedMemoryConsumptionBytes())
+}
@@ -58,7 +58,8 @@ type QueryLimiter struct {
 }
 
 // NewQueryLimiter makes a new per-query limiter. Each query limiter is configured using the
-// `maxSeriesPerQuery`, `maxChunkBytesPerQuery`, and `maxChunksPerQuery` limits.
+// `maxSeriesPerQuery`, `maxChunkBytesPerQuery`, and `maxChunksPerQuery` limits. The rejected queries
+// are not tracked if queryMetrics is nil.
 func NewQueryLimiter(maxSeriesPerQuery, maxChunkBytesPerQuery, maxChunksPerQuery int, queryMetrics *stats.QueryMetrics) *QueryLimiter {
 	return &QueryLimiter{
 		uniqueSeriesMx: sync.Mutex{},
@@ -76,6 +77,12 @@ func AddQueryLimiterToContext(ctx context.Context, limiter *QueryLimiter) contex
 	return context.WithValue(ctx, ctxKey, limiter)
 }
 
+// QueryLimiterFromContext returns the QueryLimiter from the current context, and whether there's one.
+func QueryLimiterFromContext(ctx context.Context) (*QueryLimiter, bool) {
+	ql, ok := ctx.Value(ctxKey).(*QueryLimiter)
+	return ql, ok
+}
+
 // QueryLimiterFromContextWithFallback returns a QueryLimiter from the current context.
 // If there is not a QueryLimiter on the context it will return a new no-op limiter.
 func QueryLimiterFromContextWithFallback(ctx context.Context) *QueryLimiter {
@@ -103,7 +110,7 @@ func (ql *QueryLimiter) AddSeries(seriesLabels []mimirpb.LabelAdapter) error {
 	uniqueSeriesAfter := len(ql.uniqueSeries)
 
 	if uniqueSeriesAfter > ql.maxSeriesPerQuery {
-		if uniqueSeriesBefore <= ql.maxSeriesPerQuery {
+		if uniqueSeriesBefore <= ql.maxSeriesPerQuery && ql.queryMetrics != nil {
 			// If we've just exceeded the limit for the first time for this query, increment the failed query metric.
 			ql.queryMetrics.QueriesRejectedTotal.WithLabelValues(stats.RejectReasonMaxSeries).Inc()
 		}
@@ -129,7 +136,7 @@ func (ql *QueryLimiter) AddChunkBytes(chunkSizeInBytes int) error {
 	totalBytes := ql.chunkBytesCount.Add(int64(chunkSizeInBytes))
 
 	if totalBytes > int64(ql.maxChunkBytesPerQuery) {
-		if totalBytes-int64(chunkSizeInBytes) <= int64(ql.maxChunkBytesPerQuery) {
+		if totalBytes-int64(chunkSizeInBytes) <= int64(ql.maxChunkBytesPerQuery) && ql.queryMetrics != nil {
 			// If we've just exceeded the limit for the first time for this query, increment the failed query metric.
 			ql.queryMetrics.QueriesRejectedTotal.WithLabelValues(stats.RejectReasonMaxChunkBytes).Inc()
 		}
@@ -147,7 +154,7 @@ func (ql *QueryLimiter) AddChunks(count int) error {
 	totalChunks := ql.chunkCount.Add(int64(count))
 
 	if totalChunks > int64(ql.maxChunksPerQuery) {
-		if totalChunks-int64(count) <= int64(ql.maxChunksPerQuery) {
+		if totalChunks-int64(count) <= int64(ql.maxChunksPerQuery) && ql.queryMetrics != nil {
 			// If we've just exceeded the limit for the first time for this query, increment the failed query metric.
 			ql.queryMetrics.QueriesRejectedTotal.WithLabelValues(stats.RejectReasonMaxChunks).Inc()
 		}
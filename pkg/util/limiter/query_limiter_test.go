@@ -156,6 +156,7 @@ func assertRejectedQueriesMetricValue(t *testing.T, c prometheus.Collector, expe
 		cortex_querier_queries_rejected_total{reason="max-fetched-series-per-query"} %v
 		cortex_querier_queries_rejected_total{reason="max-fetched-chunk-bytes-per-query"} %v
 		cortex_querier_queries_rejected_total{reason="max-fetched-chunks-per-query"} %v
+		cortex_querier_queries_rejected_total{reason="max-estimated-chunks-and-samples-memory-per-query"} 0
 		`,
 		expectedMaxSeries,
 		expectedMaxChunkBytes,
@@ -26,6 +26,9 @@ const (
 
 	// How long is the sliding window used to compute the moving average.
 	resourceUtilizationSlidingWindow = 60 * time.Second
+
+	// How long the memory utilization must stay above the write path limit before write requests get limited.
+	writePathMemoryPressurePeriod = 10 * time.Second
 )
 
 type utilizationScanner interface {
@@ -48,7 +51,9 @@ func (s procfsScanner) Scan() (float64, uint64, error) {
 
 // UtilizationBasedLimiter is a Service offering limiting based on CPU and memory utilization.
 //
-// The respective CPU and memory utilization limits are configurable.
+// The respective CPU and memory utilization limits are configurable. An additional, typically higher,
+// memory limit can be configured to limit write requests too, once the memory utilization has been
+// above it for a sustained period of time.
 type UtilizationBasedLimiter struct {
 	services.Service
 
@@ -59,6 +64,10 @@ type UtilizationBasedLimiter struct {
 	memoryLimit uint64
 	// CPU limit in cores. The limit is enabled if the value is > 0.
 	cpuLimit float64
+	// Memory limit in bytes for write requests. The limit is enabled if the value is > 0.
+	writeMemoryLimit uint64
+	// The time since when the memory utilization is above the write memory limit, zero if it's not.
+	writeMemoryPressureSince time.Time
 	// Last CPU time counter
 	lastCPUTime float64
 	// The time of the first update
@@ -69,12 +78,15 @@ type UtilizationBasedLimiter struct {
 	limitingReason atomic.String
 	currCPUUtil    atomic.Float64
 	currMemoryUtil atomic.Uint64
+	// Reason for limiting write requests, if any.
+	writeLimitingReason atomic.String
 	// For logging of input to CPU load EWMA calculation, keep window of source samples
 	cpuSamples *cpuSampleBuffer
 }
 
-// NewUtilizationBasedLimiter returns a UtilizationBasedLimiter configured with cpuLimit and memoryLimit.
-func NewUtilizationBasedLimiter(cpuLimit float64, memoryLimit uint64, logCPUSamples bool, logger log.Logger,
+// NewUtilizationBasedLimiter returns a UtilizationBasedLimiter configured with cpuLimit and memoryLimit,
+// and with writeMemoryLimit for write requests.
+func NewUtilizationBasedLimiter(cpuLimit float64, memoryLimit, writeMemoryLimit uint64, logCPUSamples bool, logger log.Logger,
 	reg prometheus.Registerer) *UtilizationBasedLimiter {
 	// Calculate alpha for a minute long window
 	// https://github.com/VividCortex/ewma#choosing-alpha
@@ -84,9 +96,10 @@ func NewUtilizationBasedLimiter(cpuLimit float64, memoryLimit uint64, logCPUSamp
 		cpuSamples = newCPUSampleBuffer(int(resourceUtilizationSlidingWindow.Seconds()))
 	}
 	l := &UtilizationBasedLimiter{
-		logger:      logger,
-		cpuLimit:    cpuLimit,
-		memoryLimit: memoryLimit,
+		logger:           logger,
+		cpuLimit:         cpuLimit,
+		memoryLimit:      memoryLimit,
+		writeMemoryLimit: writeMemoryLimit,
 		// Use a minute long window, each sample being a second apart
 		cpuMovingAvg: math.NewEWMARate(alpha, resourceUtilizationUpdateInterval),
 		cpuSamples:   cpuSamples,
@@ -117,6 +130,12 @@ func (l *UtilizationBasedLimiter) LimitingReason() string {
 	return l.limitingReason.Load()
 }
 
+// WritePathLimitingReason returns the current reason for limiting write requests, if any.
+// If an empty string is returned, limiting of write requests is disabled.
+func (l *UtilizationBasedLimiter) WritePathLimitingReason() string {
+	return l.writeLimitingReason.Load()
+}
+
 func (l *UtilizationBasedLimiter) starting(_ context.Context) error {
 	p, err := procfs.Self()
 	if err != nil {
@@ -142,6 +161,8 @@ func (l *UtilizationBasedLimiter) compute(nowFn func() time.Time) (currCPUUtil f
 		level.Warn(l.logger).Log("msg", "failed to get CPU and memory stats", "err", err.Error())
 		// Disable any limiting, since we can't tell resource utilization
 		l.limitingReason.Store("")
+		l.writeLimitingReason.Store("")
+		l.writeMemoryPressureSince = time.Time{}
 		return
 	}
 
@@ -177,6 +198,8 @@ func (l *UtilizationBasedLimiter) compute(nowFn func() time.Time) (currCPUUtil f
 		l.currCPUUtil.Store(currCPUUtil)
 	}
 
+	l.computeWritePathLimiting(now, currMemoryUtil)
+
 	var reason string
 	if l.memoryLimit > 0 && currMemoryUtil >= l.memoryLimit {
 		reason = "memory"
@@ -210,6 +233,38 @@ func (l *UtilizationBasedLimiter) compute(nowFn func() time.Time) (currCPUUtil f
 	return
 }
 
+// computeWritePathLimiting enables the limiting of write requests when the memory utilization has been
+// above the write memory limit for at least writePathMemoryPressurePeriod, and disables it as soon as
+// the memory utilization drops below the limit.
+func (l *UtilizationBasedLimiter) computeWritePathLimiting(now time.Time, currMemoryUtil uint64) {
+	var reason string
+	if l.writeMemoryLimit > 0 && currMemoryUtil >= l.writeMemoryLimit {
+		if l.writeMemoryPressureSince.IsZero() {
+			l.writeMemoryPressureSince = now
+		}
+		if now.Sub(l.writeMemoryPressureSince) >= writePathMemoryPressurePeriod {
+			reason = "memory"
+		}
+	} else {
+		l.writeMemoryPressureSince = time.Time{}
+	}
+
+	prevReason := l.writeLimitingReason.Load()
+	if reason == prevReason {
+		return
+	}
+
+	if reason != "" {
+		level.Info(l.logger).Log("msg", "enabling resource utilization based limiting of write requests",
+			"reason", reason, "memory_limit", formatMemoryLimit(l.writeMemoryLimit), "memory_utilization", formatMemory(currMemoryUtil))
+	} else {
+		level.Info(l.logger).Log("msg", "disabling resource utilization based limiting of write requests",
+			"memory_limit", formatMemoryLimit(l.writeMemoryLimit), "memory_utilization", formatMemory(currMemoryUtil))
+	}
+
+	l.writeLimitingReason.Store(reason)
+}
+
 func formatCPU(value float64) string {
 	return fmt.Sprintf("%.2f", value)
 }
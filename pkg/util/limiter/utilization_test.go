@@ -24,7 +24,7 @@ func TestUtilizationBasedLimiter(t *testing.T) {
 		*fakeUtilizationScanner, prometheus.Gatherer) {
 		fakeScanner := &fakeUtilizationScanner{}
 		reg := prometheus.NewPedanticRegistry()
-		lim := NewUtilizationBasedLimiter(cpuLimit, memoryLimit, enableLogging, log.NewNopLogger(), reg)
+		lim := NewUtilizationBasedLimiter(cpuLimit, memoryLimit, 0, enableLogging, log.NewNopLogger(), reg)
 		lim.utilizationScanner = fakeScanner
 		require.Empty(t, lim.LimitingReason(), "Limiting should initially be disabled")
 
@@ -171,7 +171,7 @@ func TestUtilizationBasedLimiter(t *testing.T) {
 			instValues = append(instValues, float64(i))
 		}
 		scanner := &preRecordedUtilizationScanner{instantCPUValues: instValues}
-		lim := NewUtilizationBasedLimiter(1, 0, true, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+		lim := NewUtilizationBasedLimiter(1, 0, 0, true, log.NewNopLogger(), prometheus.NewPedanticRegistry())
 		lim.utilizationScanner = scanner
 
 		for i, ts := 0, time.Now(); i < len(instValues); i++ {
@@ -190,6 +190,78 @@ func TestUtilizationBasedLimiter(t *testing.T) {
 	})
 }
 
+func TestUtilizationBasedLimiter_WritePath(t *testing.T) {
+	const gigabyte = 1024 * 1024 * 1024
+
+	tim := time.Now()
+	nowFn := func() time.Time {
+		return tim
+	}
+
+	setup := func(memoryLimit, writeMemoryLimit uint64) (*UtilizationBasedLimiter, *fakeUtilizationScanner) {
+		fakeScanner := &fakeUtilizationScanner{}
+		lim := NewUtilizationBasedLimiter(0, memoryLimit, writeMemoryLimit, false, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+		lim.utilizationScanner = fakeScanner
+		require.Empty(t, lim.WritePathLimitingReason(), "Write path limiting should initially be disabled")
+
+		// Compute the utilization a first time to warm up the limiter.
+		lim.compute(nowFn)
+		return lim, fakeScanner
+	}
+
+	t.Run("write path limiting should be enabled on sustained memory pressure only", func(t *testing.T) {
+		lim, fakeScanner := setup(gigabyte, 2*gigabyte)
+
+		// Memory above the read limit but below the write limit limits reads only.
+		fakeScanner.memoryUtilization = gigabyte
+		for i := 0; i <= int(writePathMemoryPressurePeriod.Seconds()); i++ {
+			lim.compute(nowFn)
+			tim = tim.Add(time.Second)
+		}
+		require.Equal(t, "memory", lim.LimitingReason())
+		require.Empty(t, lim.WritePathLimitingReason())
+
+		// A short spike above the write limit doesn't limit writes.
+		fakeScanner.memoryUtilization = 2 * gigabyte
+		for i := 0; i < int(writePathMemoryPressurePeriod.Seconds()); i++ {
+			lim.compute(nowFn)
+			tim = tim.Add(time.Second)
+			require.Empty(t, lim.WritePathLimitingReason(), "Write path limiting should be disabled")
+		}
+		fakeScanner.memoryUtilization = gigabyte
+		lim.compute(nowFn)
+		tim = tim.Add(time.Second)
+		require.Empty(t, lim.WritePathLimitingReason())
+
+		// Sustained memory pressure above the write limit limits writes.
+		fakeScanner.memoryUtilization = 2 * gigabyte
+		for i := 0; i < int(writePathMemoryPressurePeriod.Seconds()); i++ {
+			lim.compute(nowFn)
+			tim = tim.Add(time.Second)
+		}
+		lim.compute(nowFn)
+		require.Equal(t, "memory", lim.WritePathLimitingReason(), "Write path limiting should be enabled due to memory")
+
+		// Write limiting is disabled as soon as the memory drops below the write limit.
+		fakeScanner.memoryUtilization = 2*gigabyte - 1
+		lim.compute(nowFn)
+		require.Empty(t, lim.WritePathLimitingReason(), "Write path limiting should be disabled again")
+		require.Equal(t, "memory", lim.LimitingReason())
+	})
+
+	t.Run("write path limiting should be disabled if set to 0", func(t *testing.T) {
+		lim, fakeScanner := setup(gigabyte, 0)
+
+		fakeScanner.memoryUtilization = 10 * gigabyte
+		for i := 0; i <= int(writePathMemoryPressurePeriod.Seconds()); i++ {
+			lim.compute(nowFn)
+			tim = tim.Add(time.Second)
+		}
+		require.Equal(t, "memory", lim.LimitingReason())
+		require.Empty(t, lim.WritePathLimitingReason())
+	})
+}
+
 func TestFormatCPU(t *testing.T) {
 	assert.Equal(t, "0.00", formatCPU(0))
 	assert.Equal(t, "0.11", formatCPU(0.11))
@@ -287,7 +359,7 @@ func TestUtilizationBasedLimiter_CPUUtilizationSensitivity(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			scanner := &preRecordedUtilizationScanner{instantCPUValues: testData.instantCPUValues}
 
-			lim := NewUtilizationBasedLimiter(1, 0, true, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+			lim := NewUtilizationBasedLimiter(1, 0, 0, true, log.NewNopLogger(), prometheus.NewPedanticRegistry())
 			lim.utilizationScanner = scanner
 
 			minCPUUtilization := float64(math.MaxInt64)
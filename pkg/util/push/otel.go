@@ -39,10 +39,16 @@ const (
 	maxErrMsgLen   = 1024
 )
 
+// OTLPHandlerLimits are the per-tenant limits used by the OTLP handler.
+type OTLPHandlerLimits interface {
+	CreatedTimestampZeroIngestionEnabled(userID string) bool
+}
+
 func OTLPHandler(
 	maxRecvMsgSize int,
 	sourceIPs *middleware.SourceIPExtractor,
 	allowSkipLabelNameValidation bool,
+	limits OTLPHandlerLimits,
 	reg prometheus.Registerer,
 	push Func,
 ) http.Handler {
@@ -125,7 +131,12 @@ func OTLPHandler(
 
 		level.Debug(log).Log("msg", "decoding complete, starting conversion")
 
-		metrics, err := otelMetricsToTimeseries(ctx, discardedDueToOtelParseError, logger, otlpReq.Metrics())
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return body, err
+		}
+
+		metrics, err := otelMetricsToTimeseries(ctx, discardedDueToOtelParseError, logger, otlpReq.Metrics(), limits.CreatedTimestampZeroIngestionEnabled(tenantID))
 		if err != nil {
 			return body, err
 		}
@@ -154,7 +165,7 @@ func OTLPHandler(
 	})
 }
 
-func otelMetricsToTimeseries(ctx context.Context, discardedDueToOtelParseError *prometheus.CounterVec, logger kitlog.Logger, md pmetric.Metrics) ([]mimirpb.PreallocTimeseries, error) {
+func otelMetricsToTimeseries(ctx context.Context, discardedDueToOtelParseError *prometheus.CounterVec, logger kitlog.Logger, md pmetric.Metrics, createdTimestampsEnabled bool) ([]mimirpb.PreallocTimeseries, error) {
 	tsMap, errs := prometheusremotewrite.FromMetrics(md, prometheusremotewrite.Settings{})
 
 	if errs != nil {
@@ -178,14 +189,119 @@ func otelMetricsToTimeseries(ctx context.Context, discardedDueToOtelParseError *
 		level.Warn(logger).Log("msg", "OTLP parse error", "err", parseErrs)
 	}
 
+	var createdTimestamps map[string]int64
+	if createdTimestampsEnabled {
+		createdTimestamps = otelCreatedTimestamps(md)
+	}
+
 	mimirTs := mimirpb.PreallocTimeseriesSliceFromPool()
-	for _, promTs := range tsMap {
-		mimirTs = append(mimirTs, promToMimirTimeseries(promTs))
+	for sig, promTs := range tsMap {
+		ts := promToMimirTimeseries(promTs)
+		ts.CreatedTimestamp = createdTimestamps[sig]
+		mimirTs = append(mimirTs, ts)
 	}
 
 	return mimirTs, nil
 }
 
+// otelCreatedTimestamps returns the created timestamp of the counter, classic histogram, summary and native histogram
+// series translated from md, keyed by the series signature used by the OTLP translator. The created timestamp of
+// a series is the start timestamp of its first data point. To let the OTLP translator build the series labels and
+// signatures, a copy of the cumulative metrics in md is translated, with the timestamp of each data point replaced
+// by its start timestamp.
+func otelCreatedTimestamps(md pmetric.Metrics) map[string]int64 {
+	starts := pmetric.NewMetrics()
+	found := false
+
+	resourceMetricsSlice := md.ResourceMetrics()
+	for i := 0; i < resourceMetricsSlice.Len(); i++ {
+		resourceMetrics := resourceMetricsSlice.At(i)
+		startsResourceMetrics := starts.ResourceMetrics().AppendEmpty()
+		resourceMetrics.Resource().CopyTo(startsResourceMetrics.Resource())
+
+		scopeMetricsSlice := resourceMetrics.ScopeMetrics()
+		for j := 0; j < scopeMetricsSlice.Len(); j++ {
+			metricSlice := scopeMetricsSlice.At(j).Metrics()
+			startsMetricSlice := startsResourceMetrics.ScopeMetrics().AppendEmpty().Metrics()
+
+			for k := 0; k < metricSlice.Len(); k++ {
+				metric := metricSlice.At(k)
+				if !hasCreatedTimestamp(metric) {
+					continue
+				}
+
+				startsMetric := startsMetricSlice.AppendEmpty()
+				metric.CopyTo(startsMetric)
+				setTimestampsToStartTimestamps(startsMetric)
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	// Translation errors have already been tracked when translating md.
+	tsMap, _ := prometheusremotewrite.FromMetrics(starts, prometheusremotewrite.Settings{DisableTargetInfo: true})
+
+	result := make(map[string]int64, len(tsMap))
+	for sig, ts := range tsMap {
+		var ct int64
+		if len(ts.Samples) > 0 {
+			ct = ts.Samples[0].Timestamp
+		} else if len(ts.Histograms) > 0 {
+			ct = ts.Histograms[0].Timestamp
+		}
+		if ct > 0 {
+			result[sig] = ct
+		}
+	}
+	return result
+}
+
+// hasCreatedTimestamp returns whether the series translated from metric have a created timestamp, which is the case
+// for cumulative monotonic sums, cumulative histograms and exponential histograms, and summaries.
+func hasCreatedTimestamp(metric pmetric.Metric) bool {
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		return metric.Sum().IsMonotonic() && metric.Sum().AggregationTemporality() == pmetric.AggregationTemporalityCumulative
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().AggregationTemporality() == pmetric.AggregationTemporalityCumulative
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityCumulative
+	case pmetric.MetricTypeSummary:
+		return true
+	}
+	return false
+}
+
+// setTimestampsToStartTimestamps sets the timestamp of each data point of metric to its start timestamp.
+func setTimestampsToStartTimestamps(metric pmetric.Metric) {
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		dataPoints := metric.Sum().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoints.At(i).SetTimestamp(dataPoints.At(i).StartTimestamp())
+		}
+	case pmetric.MetricTypeHistogram:
+		dataPoints := metric.Histogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoints.At(i).SetTimestamp(dataPoints.At(i).StartTimestamp())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dataPoints := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoints.At(i).SetTimestamp(dataPoints.At(i).StartTimestamp())
+		}
+	case pmetric.MetricTypeSummary:
+		dataPoints := metric.Summary().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoints.At(i).SetTimestamp(dataPoints.At(i).StartTimestamp())
+		}
+	}
+}
+
 func promToMimirTimeseries(promTs *prompb.TimeSeries) mimirpb.PreallocTimeseries {
 	labels := make([]mimirpb.LabelAdapter, 0, len(promTs.Labels))
 	for _, label := range promTs.Labels {
@@ -31,6 +31,7 @@ import (
 
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/util/test"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 func TestHandler_remoteWrite(t *testing.T) {
@@ -159,7 +160,7 @@ func TestHandlerOTLPPush(t *testing.T) {
 				req.Header.Set("Content-Encoding", tt.encoding)
 			}
 
-			handler := OTLPHandler(tt.maxMsgSize, nil, false, nil, tt.verifyFunc)
+			handler := OTLPHandler(tt.maxMsgSize, nil, false, validation.MockDefaultOverrides(), nil, tt.verifyFunc)
 
 			resp := httptest.NewRecorder()
 			handler.ServeHTTP(resp, req)
@@ -214,7 +215,7 @@ func TestHandler_otlpDroppedMetricsPanic(t *testing.T) {
 
 	req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
 	resp := httptest.NewRecorder()
-	handler := OTLPHandler(100000, nil, false, nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
+	handler := OTLPHandler(100000, nil, false, validation.MockDefaultOverrides(), nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
 		request, err := pushReq.WriteRequest()
 		assert.NoError(t, err)
 		assert.Len(t, request.Timeseries, 3)
@@ -254,7 +255,7 @@ func TestHandler_otlpDroppedMetricsPanic2(t *testing.T) {
 
 	req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
 	resp := httptest.NewRecorder()
-	handler := OTLPHandler(100000, nil, false, nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
+	handler := OTLPHandler(100000, nil, false, validation.MockDefaultOverrides(), nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
 		request, err := pushReq.WriteRequest()
 		assert.NoError(t, err)
 		assert.Len(t, request.Timeseries, 2)
@@ -280,7 +281,7 @@ func TestHandler_otlpDroppedMetricsPanic2(t *testing.T) {
 
 	req = createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
 	resp = httptest.NewRecorder()
-	handler = OTLPHandler(100000, nil, false, nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
+	handler = OTLPHandler(100000, nil, false, validation.MockDefaultOverrides(), nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
 		request, err := pushReq.WriteRequest()
 		assert.NoError(t, err)
 		assert.Len(t, request.Timeseries, 10) // 6 buckets (including +Inf) + 2 sum/count + 2 from the first case
@@ -292,6 +293,108 @@ func TestHandler_otlpDroppedMetricsPanic2(t *testing.T) {
 	assert.Equal(t, 200, resp.Code)
 }
 
+func TestHandler_otlpCreatedTimestamps(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-time.Minute)
+
+	md := pmetric.NewMetrics()
+	resource := md.ResourceMetrics().AppendEmpty()
+	metrics := resource.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("requests_total")
+	counter.SetEmptySum()
+	counter.Sum().SetIsMonotonic(true)
+	counter.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	counterPoint := counter.Sum().DataPoints().AppendEmpty()
+	counterPoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	counterPoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	counterPoint.SetDoubleValue(10)
+	counterPoint.Attributes().PutStr("code", "200")
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	gauge.SetEmptyGauge()
+	gaugePoint := gauge.Gauge().DataPoints().AppendEmpty()
+	gaugePoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	gaugePoint.SetDoubleValue(20)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("request_duration_seconds")
+	histogram.SetEmptyHistogram()
+	histogram.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	histogramPoint := histogram.Histogram().DataPoints().AppendEmpty()
+	histogramPoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	histogramPoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	histogramPoint.SetCount(3)
+	histogramPoint.SetSum(1.5)
+	histogramPoint.ExplicitBounds().FromRaw([]float64{0.5})
+	histogramPoint.BucketCounts().FromRaw([]uint64{1, 2})
+
+	exponentialHistogram := metrics.AppendEmpty()
+	exponentialHistogram.SetName("response_size_bytes")
+	exponentialHistogram.SetEmptyExponentialHistogram()
+	exponentialHistogram.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	exponentialHistogramPoint := exponentialHistogram.ExponentialHistogram().DataPoints().AppendEmpty()
+	exponentialHistogramPoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	exponentialHistogramPoint.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	exponentialHistogramPoint.SetCount(2)
+	exponentialHistogramPoint.SetSum(30)
+	exponentialHistogramPoint.Positive().BucketCounts().FromRaw([]uint64{1, 1})
+	exponentialHistogramPoint.Attributes().PutStr("http.method", "GET")
+
+	pushCreatedTimestamps := func(t *testing.T, limits OTLPHandlerLimits) map[string]int64 {
+		var createdTimestamps map[string]int64
+
+		req := createOTLPRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+		resp := httptest.NewRecorder()
+		handler := OTLPHandler(100000, nil, false, limits, nil, func(ctx context.Context, pushReq *Request) (response *mimirpb.WriteResponse, err error) {
+			request, err := pushReq.WriteRequest()
+			require.NoError(t, err)
+
+			createdTimestamps = map[string]int64{}
+			for _, ts := range request.Timeseries {
+				createdTimestamps[mimirpb.FromLabelAdaptersToLabels(ts.Labels).String()] = ts.CreatedTimestamp
+			}
+
+			pushReq.CleanUp()
+			return &mimirpb.WriteResponse{}, nil
+		})
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, 200, resp.Code)
+
+		return createdTimestamps
+	}
+
+	t.Run("created timestamp zero ingestion enabled", func(t *testing.T) {
+		limits := validation.MockOverrides(func(defaults *validation.Limits, _ map[string]*validation.Limits) {
+			defaults.CreatedTimestampZeroIngestionEnabled = true
+		})
+
+		assert.Equal(t, map[string]int64{
+			`{__name__="requests_total", code="200"}`:                 start.UnixMilli(),
+			`{__name__="temperature"}`:                                0,
+			`{__name__="request_duration_seconds_bucket", le="0.5"}`:  start.UnixMilli(),
+			`{__name__="request_duration_seconds_bucket", le="+Inf"}`: start.UnixMilli(),
+			`{__name__="request_duration_seconds_count"}`:             start.UnixMilli(),
+			`{__name__="request_duration_seconds_sum"}`:               start.UnixMilli(),
+			`{__name__="response_size_bytes", http_method="GET"}`:     start.UnixMilli(),
+		}, pushCreatedTimestamps(t, limits))
+	})
+
+	t.Run("created timestamp zero ingestion disabled", func(t *testing.T) {
+		assert.Equal(t, map[string]int64{
+			`{__name__="requests_total", code="200"}`:                 0,
+			`{__name__="temperature"}`:                                0,
+			`{__name__="request_duration_seconds_bucket", le="0.5"}`:  0,
+			`{__name__="request_duration_seconds_bucket", le="+Inf"}`: 0,
+			`{__name__="request_duration_seconds_count"}`:             0,
+			`{__name__="request_duration_seconds_sum"}`:               0,
+			`{__name__="response_size_bytes", http_method="GET"}`:     0,
+		}, pushCreatedTimestamps(t, validation.MockDefaultOverrides()))
+	})
+}
+
 func TestHandler_otlpWriteRequestTooBigWithCompression(t *testing.T) {
 
 	// createOTLPRequest will create a request which is BIGGER with compression (37 vs 58 bytes).
@@ -309,7 +412,7 @@ func TestHandler_otlpWriteRequestTooBigWithCompression(t *testing.T) {
 
 	resp := httptest.NewRecorder()
 
-	handler := OTLPHandler(140, nil, false, nil, readBodyPushFunc(t))
+	handler := OTLPHandler(140, nil, false, validation.MockDefaultOverrides(), nil, readBodyPushFunc(t))
 	handler.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
 	body, err := io.ReadAll(resp.Body)
@@ -4,6 +4,7 @@ package push
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
 )
@@ -22,6 +23,11 @@ type Request struct {
 
 	request *mimirpb.WriteRequest
 	err     error
+
+	// Protects retained and cleanUpRequested.
+	retainMtx        sync.Mutex
+	retained         int
+	cleanUpRequested bool
 }
 
 func newRequest(p supplierFunc) *Request {
@@ -62,7 +68,42 @@ func (r *Request) AddCleanup(f func()) {
 
 // CleanUp calls all added cleanups in reverse order - the last added is the first invoked. CleanUp removes
 // each called cleanup function from the list of cleanups. So subsequent calls to CleanUp will not invoke the same cleanup functions.
+// If the request is retained, the cleanups are invoked once the request is released.
 func (r *Request) CleanUp() {
+	r.retainMtx.Lock()
+	if r.retained > 0 {
+		r.cleanUpRequested = true
+		r.retainMtx.Unlock()
+		return
+	}
+	r.retainMtx.Unlock()
+
+	r.cleanUp()
+}
+
+// Retain delays the cleanups of the request until the returned release function is called, so that the
+// request can be read asynchronously after it has been handled. The release function must be called once.
+func (r *Request) Retain() (release func()) {
+	r.retainMtx.Lock()
+	r.retained++
+	r.retainMtx.Unlock()
+
+	return func() {
+		r.retainMtx.Lock()
+		r.retained--
+		cleanUp := r.retained == 0 && r.cleanUpRequested
+		if cleanUp {
+			r.cleanUpRequested = false
+		}
+		r.retainMtx.Unlock()
+
+		if cleanUp {
+			r.cleanUp()
+		}
+	}
+}
+
+func (r *Request) cleanUp() {
 	for i := len(r.cleanups) - 1; i >= 0; i-- {
 		r.cleanups[i]()
 	}
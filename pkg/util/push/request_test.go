@@ -47,6 +47,35 @@ func TestRequest_CleanUpDoubleCalling(t *testing.T) {
 	assert.Equal(t, 1, invocations)
 }
 
+// TestRequest_CleanUpRetained tests that the cleanups of a retained request are invoked once it's released.
+func TestRequest_CleanUpRetained(t *testing.T) {
+	invocations := 0
+
+	r := newRequest(noopParser)
+	r.AddCleanup(func() { invocations++ })
+
+	releaseOne := r.Retain()
+	releaseTwo := r.Retain()
+
+	r.CleanUp()
+	assert.Equal(t, 0, invocations)
+
+	releaseOne()
+	assert.Equal(t, 0, invocations)
+
+	releaseTwo()
+	assert.Equal(t, 1, invocations)
+
+	// Releasing a request before it's cleaned up doesn't invoke the cleanups.
+	r = newRequest(noopParser)
+	r.AddCleanup(func() { invocations++ })
+	r.Retain()()
+	assert.Equal(t, 1, invocations)
+
+	r.CleanUp()
+	assert.Equal(t, 2, invocations)
+}
+
 func TestRequest_WriteRequestIsParsedOnlyOnce(t *testing.T) {
 	parseCount := 0
 	p := supplierFunc(func() (*mimirpb.WriteRequest, func(), error) {
@@ -285,6 +285,24 @@ func NewMaxQueryExpressionSizeBytesError(actualSizeBytes, maxQuerySizeBytes int)
 		maxQueryExpressionSizeBytesFlag))
 }
 
+func NewMaxQueryCostError(actualCost, estimatedSeries uint64, steps int64, maxCost int) LimitError {
+	return LimitError(globalerror.MaxQueryCost.MessageWithPerTenantLimitConfig(
+		fmt.Sprintf("the estimated cost of the query exceeds the limit (query cost: %d, estimated series: %d, steps: %d, limit: %d)", actualCost, estimatedSeries, steps, maxCost),
+		maxQueryCostFlag))
+}
+
+func NewQueryCostBudgetExhaustedError(budget int) LimitError {
+	return LimitError(globalerror.QueryCostBudgetExhausted.MessageWithPerTenantLimitConfig(
+		fmt.Sprintf("the query has been rejected because the tenant exhausted the query cost budget, set to %d per minute, and the query can't wait for the budget to be replenished before its deadline", budget),
+		queryCostBudgetPerMinuteFlag))
+}
+
+func NewMaxRemoteReadResponseSizeBytesError(maxSizeBytes int) LimitError {
+	return LimitError(globalerror.MaxRemoteReadResponseSize.MessageWithPerTenantLimitConfig(
+		fmt.Sprintf("the size of the remote read response exceeds the limit (limit: %d bytes)", maxSizeBytes),
+		maxRemoteReadResponseSizeBytesFlag))
+}
+
 func NewRequestRateLimitedError(limit float64, burst int) LimitError {
 	return LimitError(globalerror.RequestRateLimited.MessageWithPerTenantLimitConfig(
 		fmt.Sprintf("the request has been rejected because the tenant exceeded the request rate limit, set to %v requests/s across all distributors with a maximum allowed burst of %d", limit, burst),
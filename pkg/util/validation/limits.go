@@ -26,30 +26,34 @@ import (
 )
 
 const (
-	MaxSeriesPerMetricFlag                 = "ingester.max-global-series-per-metric"
-	MaxMetadataPerMetricFlag               = "ingester.max-global-metadata-per-metric"
-	MaxSeriesPerUserFlag                   = "ingester.max-global-series-per-user"
-	MaxMetadataPerUserFlag                 = "ingester.max-global-metadata-per-user"
-	MaxChunksPerQueryFlag                  = "querier.max-fetched-chunks-per-query"
-	MaxChunkBytesPerQueryFlag              = "querier.max-fetched-chunk-bytes-per-query"
-	MaxSeriesPerQueryFlag                  = "querier.max-fetched-series-per-query"
-	maxLabelNamesPerSeriesFlag             = "validation.max-label-names-per-series"
-	maxLabelNameLengthFlag                 = "validation.max-length-label-name"
-	maxLabelValueLengthFlag                = "validation.max-length-label-value"
-	maxMetadataLengthFlag                  = "validation.max-metadata-length"
-	maxNativeHistogramBucketsFlag          = "validation.max-native-histogram-buckets"
-	creationGracePeriodFlag                = "validation.create-grace-period"
-	maxPartialQueryLengthFlag              = "querier.max-partial-query-length"
-	maxTotalQueryLengthFlag                = "query-frontend.max-total-query-length"
-	maxQueryExpressionSizeBytesFlag        = "query-frontend.max-query-expression-size-bytes"
-	requestRateFlag                        = "distributor.request-rate-limit"
-	requestBurstSizeFlag                   = "distributor.request-burst-size"
-	ingestionRateFlag                      = "distributor.ingestion-rate-limit"
-	ingestionBurstSizeFlag                 = "distributor.ingestion-burst-size"
-	HATrackerMaxClustersFlag               = "distributor.ha-tracker.max-clusters"
-	resultsCacheTTLFlag                    = "query-frontend.results-cache-ttl"
-	resultsCacheTTLForOutOfOrderWindowFlag = "query-frontend.results-cache-ttl-for-out-of-order-time-window"
-	QueryIngestersWithinFlag               = "querier.query-ingesters-within"
+	MaxSeriesPerMetricFlag                         = "ingester.max-global-series-per-metric"
+	MaxMetadataPerMetricFlag                       = "ingester.max-global-metadata-per-metric"
+	MaxSeriesPerUserFlag                           = "ingester.max-global-series-per-user"
+	MaxMetadataPerUserFlag                         = "ingester.max-global-metadata-per-user"
+	MaxChunksPerQueryFlag                          = "querier.max-fetched-chunks-per-query"
+	MaxChunkBytesPerQueryFlag                      = "querier.max-fetched-chunk-bytes-per-query"
+	MaxSeriesPerQueryFlag                          = "querier.max-fetched-series-per-query"
+	MaxEstimatedChunksAndSamplesMemoryPerQueryFlag = "querier.max-estimated-chunks-and-samples-memory-per-query"
+	maxLabelNamesPerSeriesFlag                     = "validation.max-label-names-per-series"
+	maxLabelNameLengthFlag                         = "validation.max-length-label-name"
+	maxLabelValueLengthFlag                        = "validation.max-length-label-value"
+	maxMetadataLengthFlag                          = "validation.max-metadata-length"
+	maxNativeHistogramBucketsFlag                  = "validation.max-native-histogram-buckets"
+	creationGracePeriodFlag                        = "validation.create-grace-period"
+	maxPartialQueryLengthFlag                      = "querier.max-partial-query-length"
+	maxTotalQueryLengthFlag                        = "query-frontend.max-total-query-length"
+	maxQueryExpressionSizeBytesFlag                = "query-frontend.max-query-expression-size-bytes"
+	maxQueryCostFlag                               = "query-frontend.max-query-cost"
+	queryCostBudgetPerMinuteFlag                   = "query-frontend.query-cost-budget-per-minute"
+	maxRemoteReadResponseSizeBytesFlag             = "query-frontend.max-remote-read-response-size-bytes"
+	requestRateFlag                                = "distributor.request-rate-limit"
+	requestBurstSizeFlag                           = "distributor.request-burst-size"
+	ingestionRateFlag                              = "distributor.ingestion-rate-limit"
+	ingestionBurstSizeFlag                         = "distributor.ingestion-burst-size"
+	HATrackerMaxClustersFlag                       = "distributor.ha-tracker.max-clusters"
+	resultsCacheTTLFlag                            = "query-frontend.results-cache-ttl"
+	resultsCacheTTLForOutOfOrderWindowFlag         = "query-frontend.results-cache-ttl-for-out-of-order-time-window"
+	QueryIngestersWithinFlag                       = "querier.query-ingesters-within"
 
 	// MinCompactorPartialBlockDeletionDelay is the minimum partial blocks deletion delay that can be configured in Mimir.
 	MinCompactorPartialBlockDeletionDelay = 4 * time.Hour
@@ -85,6 +89,11 @@ type Limits struct {
 	IngestionTenantShardSize  int                 `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
 	MetricRelabelConfigs      []*relabel.Config   `yaml:"metric_relabel_configs,omitempty" json:"metric_relabel_configs,omitempty" doc:"nocli|description=List of metric relabel configurations. Note that in most situations, it is more effective to use metrics relabeling directly in the Prometheus server, e.g. remote_write.write_relabel_configs. Labels available during the relabeling phase and cleaned afterwards: __meta_tenant_id" category:"experimental"`
 
+	CreatedTimestampZeroIngestionEnabled bool `yaml:"created_timestamp_zero_ingestion_enabled" json:"created_timestamp_zero_ingestion_enabled" category:"experimental"`
+
+	WriteMirrorEnabled     bool    `yaml:"write_mirror_enabled" json:"write_mirror_enabled" category:"experimental"`
+	WriteMirrorSampleRatio float64 `yaml:"write_mirror_sample_ratio" json:"write_mirror_sample_ratio" category:"experimental"`
+
 	// Ingester enforced limits.
 	// Series
 	MaxGlobalSeriesPerUser   int `yaml:"max_global_series_per_user" json:"max_global_series_per_user"`
@@ -106,20 +115,27 @@ type Limits struct {
 	SeparateMetricsGroupLabel string `yaml:"separate_metrics_group_label" json:"separate_metrics_group_label" category:"experimental"`
 
 	// Querier enforced limits.
-	MaxChunksPerQuery               int            `yaml:"max_fetched_chunks_per_query" json:"max_fetched_chunks_per_query"`
-	MaxFetchedSeriesPerQuery        int            `yaml:"max_fetched_series_per_query" json:"max_fetched_series_per_query"`
-	MaxFetchedChunkBytesPerQuery    int            `yaml:"max_fetched_chunk_bytes_per_query" json:"max_fetched_chunk_bytes_per_query"`
-	MaxQueryLookback                model.Duration `yaml:"max_query_lookback" json:"max_query_lookback"`
-	MaxPartialQueryLength           model.Duration `yaml:"max_partial_query_length" json:"max_partial_query_length"`
-	MaxQueryParallelism             int            `yaml:"max_query_parallelism" json:"max_query_parallelism"`
-	MaxLabelsQueryLength            model.Duration `yaml:"max_labels_query_length" json:"max_labels_query_length"`
-	MaxCacheFreshness               model.Duration `yaml:"max_cache_freshness" json:"max_cache_freshness" category:"advanced"`
-	MaxQueriersPerTenant            int            `yaml:"max_queriers_per_tenant" json:"max_queriers_per_tenant"`
-	QueryShardingTotalShards        int            `yaml:"query_sharding_total_shards" json:"query_sharding_total_shards"`
-	QueryShardingMaxShardedQueries  int            `yaml:"query_sharding_max_sharded_queries" json:"query_sharding_max_sharded_queries"`
-	QueryShardingMaxRegexpSizeBytes int            `yaml:"query_sharding_max_regexp_size_bytes" json:"query_sharding_max_regexp_size_bytes"`
-	SplitInstantQueriesByInterval   model.Duration `yaml:"split_instant_queries_by_interval" json:"split_instant_queries_by_interval" category:"experimental"`
-	QueryIngestersWithin            model.Duration `yaml:"query_ingesters_within" json:"query_ingesters_within" category:"advanced"`
+	MaxChunksPerQuery                          int            `yaml:"max_fetched_chunks_per_query" json:"max_fetched_chunks_per_query"`
+	MaxFetchedSeriesPerQuery                   int            `yaml:"max_fetched_series_per_query" json:"max_fetched_series_per_query"`
+	MaxFetchedChunkBytesPerQuery               int            `yaml:"max_fetched_chunk_bytes_per_query" json:"max_fetched_chunk_bytes_per_query"`
+	MaxEstimatedChunksAndSamplesMemoryPerQuery int            `yaml:"max_estimated_chunks_and_samples_memory_per_query" json:"max_estimated_chunks_and_samples_memory_per_query" category:"experimental"`
+	MaxQueryLookback                           model.Duration `yaml:"max_query_lookback" json:"max_query_lookback"`
+	MaxPartialQueryLength                      model.Duration `yaml:"max_partial_query_length" json:"max_partial_query_length"`
+	MaxQueryParallelism                        int            `yaml:"max_query_parallelism" json:"max_query_parallelism"`
+	MaxLabelsQueryLength                       model.Duration `yaml:"max_labels_query_length" json:"max_labels_query_length"`
+	MaxCacheFreshness                          model.Duration `yaml:"max_cache_freshness" json:"max_cache_freshness" category:"advanced"`
+	MaxQueriersPerTenant                       int            `yaml:"max_queriers_per_tenant" json:"max_queriers_per_tenant"`
+	QueryShardingTotalShards                   int            `yaml:"query_sharding_total_shards" json:"query_sharding_total_shards"`
+	QueryShardingMaxShardedQueries             int            `yaml:"query_sharding_max_sharded_queries" json:"query_sharding_max_sharded_queries"`
+	QueryShardingMaxRegexpSizeBytes            int            `yaml:"query_sharding_max_regexp_size_bytes" json:"query_sharding_max_regexp_size_bytes"`
+	SplitInstantQueriesByInterval              model.Duration `yaml:"split_instant_queries_by_interval" json:"split_instant_queries_by_interval" category:"experimental"`
+	QueryIngestersWithin                       model.Duration `yaml:"query_ingesters_within" json:"query_ingesters_within" category:"advanced"`
+	QueryPartialResponseEnabled                bool           `yaml:"query_partial_response_enabled" json:"query_partial_response_enabled" category:"experimental"`
+
+	// Tenant federation limits.
+	TenantFederationAllowedTenantPatterns             flagext.StringSliceCSV `yaml:"tenant_federation_allowed_tenant_patterns" json:"tenant_federation_allowed_tenant_patterns" category:"experimental"`
+	TenantFederationMaxTenantsPerPattern              int                    `yaml:"tenant_federation_max_tenants_per_pattern" json:"tenant_federation_max_tenants_per_pattern" category:"experimental"`
+	TenantFederationMaxRemoteClusterResponseSizeBytes int                    `yaml:"tenant_federation_max_remote_cluster_response_size_bytes" json:"tenant_federation_max_remote_cluster_response_size_bytes" category:"experimental"`
 
 	// Query-frontend limits.
 	MaxTotalQueryLength                    model.Duration `yaml:"max_total_query_length" json:"max_total_query_length"`
@@ -129,6 +145,9 @@ type Limits struct {
 	ResultsCacheTTLForLabelsQuery          model.Duration `yaml:"results_cache_ttl_for_labels_query" json:"results_cache_ttl_for_labels_query" category:"experimental"`
 	ResultsCacheForUnalignedQueryEnabled   bool           `yaml:"cache_unaligned_requests" json:"cache_unaligned_requests" category:"advanced"`
 	MaxQueryExpressionSizeBytes            int            `yaml:"max_query_expression_size_bytes" json:"max_query_expression_size_bytes" category:"experimental"`
+	MaxQueryCost                           int            `yaml:"max_query_cost" json:"max_query_cost" category:"experimental"`
+	QueryCostBudgetPerMinute               int            `yaml:"query_cost_budget_per_minute" json:"query_cost_budget_per_minute" category:"experimental"`
+	MaxRemoteReadResponseSizeBytes         int            `yaml:"max_remote_read_response_size_bytes" json:"max_remote_read_response_size_bytes" category:"experimental"`
 
 	// Cardinality
 	CardinalityAnalysisEnabled                    bool `yaml:"cardinality_analysis_enabled" json:"cardinality_analysis_enabled"`
@@ -201,6 +220,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	_ = l.CreationGracePeriod.Set("10m")
 	f.Var(&l.CreationGracePeriod, creationGracePeriodFlag, "Controls how far into the future incoming samples are accepted compared to the wall clock. Any sample with timestamp `t` will be rejected if `t > (now + validation.create-grace-period)`. Also used by query-frontend to avoid querying too far into the future. 0 to disable.")
 	f.BoolVar(&l.EnforceMetadataMetricName, "validation.enforce-metadata-metric-name", true, "Enforce every metadata has a metric name.")
+	f.BoolVar(&l.CreatedTimestampZeroIngestionEnabled, "distributor.created-timestamp-zero-ingestion-enabled", false, "Synthesize a zero sample at the created timestamp of counters, classic histograms and native histograms, when the created timestamp is sent by the client. This lets rate() and increase() account for the first increment of a new or restarted counter.")
+	f.BoolVar(&l.WriteMirrorEnabled, "distributor.write-mirror-enabled", false, "Mirror the accepted write requests of the tenant to the remote-write endpoint configured via -distributor.write-mirror.url.")
+	f.Float64Var(&l.WriteMirrorSampleRatio, "distributor.write-mirror-sample-ratio", 1, "Ratio of the accepted write requests of the tenant to mirror, between 0 and 1. 1 mirrors all write requests.")
 
 	f.IntVar(&l.MaxGlobalSeriesPerUser, MaxSeriesPerUserFlag, 150000, "The maximum number of in-memory series per tenant, across the cluster before replication. 0 to disable.")
 	f.IntVar(&l.MaxGlobalSeriesPerMetric, MaxSeriesPerMetricFlag, 0, "The maximum number of in-memory series per metric name, across the cluster before replication. 0 to disable.")
@@ -209,7 +231,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxGlobalMetadataPerMetric, MaxMetadataPerMetricFlag, 0, "The maximum number of metadata per metric, across the cluster. 0 to disable.")
 	f.IntVar(&l.MaxGlobalExemplarsPerUser, "ingester.max-global-exemplars-per-user", 0, "The maximum number of exemplars in memory, across the cluster. 0 to disable exemplars ingestion.")
 	f.Var(&l.ActiveSeriesCustomTrackersConfig, "ingester.active-series-custom-trackers", "Additional active series metrics, matching the provided matchers. Matchers should be in form <name>:<matcher>, like 'foobar:{foo=\"bar\"}'. Multiple matchers can be provided either providing the flag multiple times or providing multiple semicolon-separated values to a single flag.")
-	f.Var(&l.OutOfOrderTimeWindow, "ingester.out-of-order-time-window", fmt.Sprintf("Non-zero value enables out-of-order support for most recent samples that are within the time window in relation to the TSDB's maximum time, i.e., within [db.maxTime-timeWindow, db.maxTime]). The ingester will need more memory as a factor of rate of out-of-order samples being ingested and the number of series that are getting out-of-order samples. If query falls into this window, cached results will use value from -%s option to specify TTL for resulting cache entry.", resultsCacheTTLForOutOfOrderWindowFlag))
+	f.Var(&l.OutOfOrderTimeWindow, "ingester.out-of-order-time-window", fmt.Sprintf("Non-zero value enables out-of-order support for most recent samples that are within the time window in relation to the TSDB's maximum time, i.e., within [db.maxTime-timeWindow, db.maxTime]). Out-of-order native histogram samples are not supported and are always rejected. The ingester will need more memory as a factor of rate of out-of-order samples being ingested and the number of series that are getting out-of-order samples. If query falls into this window, cached results will use value from -%s option to specify TTL for resulting cache entry.", resultsCacheTTLForOutOfOrderWindowFlag))
 	f.BoolVar(&l.NativeHistogramsIngestionEnabled, "ingester.native-histograms-ingestion-enabled", false, "Enable ingestion of native histogram samples. If false, native histogram samples are ignored without an error. To query native histograms with query-sharding enabled make sure to set -query-frontend.query-result-response-format to 'protobuf'.")
 	f.BoolVar(&l.OutOfOrderBlocksExternalLabelEnabled, "ingester.out-of-order-blocks-external-label-enabled", false, "Whether the shipper should label out-of-order blocks with an external label before uploading them. Setting this label will compact out-of-order blocks separately from non-out-of-order blocks")
 
@@ -218,6 +240,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxChunksPerQuery, MaxChunksPerQueryFlag, 2e6, "Maximum number of chunks that can be fetched in a single query from ingesters and long-term storage. This limit is enforced in the querier, ruler and store-gateway. 0 to disable.")
 	f.IntVar(&l.MaxFetchedSeriesPerQuery, MaxSeriesPerQueryFlag, 0, "The maximum number of unique series for which a query can fetch samples from each ingesters and storage. This limit is enforced in the querier, ruler and store-gateway. 0 to disable")
 	f.IntVar(&l.MaxFetchedChunkBytesPerQuery, MaxChunkBytesPerQueryFlag, 0, "The maximum size of all chunks in bytes that a query can fetch from each ingester and storage. This limit is enforced in the querier and ruler. 0 to disable.")
+	f.IntVar(&l.MaxEstimatedChunksAndSamplesMemoryPerQuery, MaxEstimatedChunksAndSamplesMemoryPerQueryFlag, 0, "The maximum estimated memory in bytes of the chunks and samples a single query can hold in the querier. The estimate includes the chunks fetched from ingesters and store-gateways, unless they are streamed, and, when the streaming PromQL engine is used, the samples and intermediate results held by the engine. The samples decoded and the intermediate results computed by the Prometheus PromQL engine aren't included: they're bounded by -querier.max-samples instead. This limit is enforced in the querier and ruler. For tenant federated queries, the limit of each tenant applies to the chunks fetched for that tenant, and the whole query is limited to the sum of the tenants' limits. 0 to disable.")
 	f.Var(&l.MaxPartialQueryLength, maxPartialQueryLengthFlag, "Limit the time range for partial queries at the querier level.")
 	f.Var(&l.MaxQueryLookback, "querier.max-query-lookback", "Limit how long back data (series and metadata) can be queried, up until <lookback> duration ago. This limit is enforced in the query-frontend, querier and ruler. If the requested time range is outside the allowed range, the request will not fail but will be manipulated to only query data within the allowed time range. 0 to disable.")
 	f.IntVar(&l.MaxQueryParallelism, "querier.max-query-parallelism", 14, "Maximum number of split (by time) or partial (by shard) queries that will be scheduled in parallel by the query-frontend for a single input query. This limit is introduced to have a fairer query scheduling and avoid a single query over a large time range saturating all available queriers.")
@@ -235,6 +258,11 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.SplitInstantQueriesByInterval, "query-frontend.split-instant-queries-by-interval", "Split instant queries by an interval and execute in parallel. 0 to disable it.")
 	_ = l.QueryIngestersWithin.Set("13h")
 	f.Var(&l.QueryIngestersWithin, QueryIngestersWithinFlag, "Maximum lookback beyond which queries are not sent to ingester. 0 means all queries are sent to ingester.")
+	f.BoolVar(&l.QueryPartialResponseEnabled, "querier.partial-response-enabled", false, "True to return partial results with warnings, instead of failing the query, when some blocks can't be queried from any store-gateway, the ingesters quorum can't be reached, or a remote cluster of a federated query can't be queried. Can be overridden per request with the partial_response request parameter. Partial results are never stored in the query results cache, and are never used by the ruler.")
+
+	f.Var(&l.TenantFederationAllowedTenantPatterns, "tenant-federation.allowed-tenant-patterns", "Comma-separated list of tenant ID patterns the tenant can use in the 'X-Scope-OrgID' header of federated queries, when tenant ID patterns are enabled. The tenant making the query is identified by the 'X-Caller-OrgID' header. Only the listed patterns are allowed.")
+	f.IntVar(&l.TenantFederationMaxTenantsPerPattern, "tenant-federation.max-tenants-per-pattern", 100, "Maximum number of tenants a tenant ID pattern used by the tenant in a federated query can match. The tenant making the query is identified by the 'X-Caller-OrgID' header. 0 to disable.")
+	f.IntVar(&l.TenantFederationMaxRemoteClusterResponseSizeBytes, "tenant-federation.max-remote-cluster-response-size-bytes", 100*1024*1024, "Maximum size, in bytes, of each response received from a remote cluster queried by the tenant. The querier buffers each response in memory while decoding it. For a streamed remote read response, the limit applies both to the whole response and to each frame. 0 to not apply a limit.")
 
 	_ = l.RulerEvaluationDelay.Set("1m")
 	f.Var(&l.RulerEvaluationDelay, "ruler.evaluation-delay-duration", "Duration to delay the evaluation of rules to ensure the underlying metrics have been pushed.")
@@ -266,6 +294,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.ResultsCacheTTLForLabelsQuery, "query-frontend.results-cache-ttl-for-labels-query", "Time to live duration for cached label names and label values query results. The value 0 disables the cache.")
 	f.BoolVar(&l.ResultsCacheForUnalignedQueryEnabled, "query-frontend.cache-unaligned-requests", false, "Cache requests that are not step-aligned.")
 	f.IntVar(&l.MaxQueryExpressionSizeBytes, maxQueryExpressionSizeBytesFlag, 0, "Max size of the raw query, in bytes. 0 to not apply a limit to the size of the query.")
+	f.IntVar(&l.MaxQueryCost, maxQueryCostFlag, 0, "Maximum estimated cost of a query. The cost of a query is the estimated number of series it fetches multiplied by the number of steps it's evaluated at, and is computed for the whole query before the query-frontend splits and shards it. The number of series is estimated from the series fetched by previous executions of similar queries, which are stored in the query results cache, so the cost is only estimated when either -query-frontend.cache-results or cardinality-based query sharding is enabled. Queries without an estimate, such as the first execution of a query, are not rejected. 0 to disable.")
+	f.IntVar(&l.QueryCostBudgetPerMinute, queryCostBudgetPerMinuteFlag, 0, "Maximum total estimated cost of the queries of a tenant per minute. Queries exceeding the budget are delayed in the query-frontend until the budget is replenished, and are rejected if they can't run before their deadline. The cost of a query is computed as for -"+maxQueryCostFlag+", except that queries without an estimate are assumed to fetch 1000 series per selector. The budget is tracked by each query-frontend replica on its own, so the total cost of the queries of a tenant across the cluster can be up to the budget multiplied by the number of query-frontend replicas. 0 to disable.")
+	f.IntVar(&l.MaxRemoteReadResponseSizeBytes, maxRemoteReadResponseSizeBytesFlag, 100*1024*1024, "Maximum size, in bytes, of the response to a remote read request. The query-frontend buffers the whole response in memory to merge the responses of the split and sharded queries, so streamed remote read responses are only sent to the client once they're complete, and this limit bounds the memory buffered for each remote read request. Requests whose response exceeds the limit are rejected. 0 to not apply a limit.")
 
 	// Store-gateway.
 	f.IntVar(&l.StoreGatewayTenantShardSize, "store-gateway.tenant-shard-size", 0, "The tenant's shard size, used when store-gateway sharding is enabled. Value of 0 disables shuffle sharding for the tenant, that is all tenant blocks are sharded across all store-gateway replicas.")
@@ -349,6 +380,10 @@ func (l *Limits) validate() error {
 		}
 	}
 
+	if l.WriteMirrorSampleRatio < 0 || l.WriteMirrorSampleRatio > 1 {
+		return fmt.Errorf("invalid write_mirror_sample_ratio %v: must be between 0 and 1", l.WriteMirrorSampleRatio)
+	}
+
 	return nil
 }
 
@@ -510,6 +545,34 @@ func (o *Overrides) MaxFetchedChunkBytesPerQuery(userID string) int {
 	return o.getOverridesForUser(userID).MaxFetchedChunkBytesPerQuery
 }
 
+// MaxEstimatedChunksAndSamplesMemoryPerQuery returns the maximum estimated memory in bytes of the chunks and samples a single query can hold in the querier.
+func (o *Overrides) MaxEstimatedChunksAndSamplesMemoryPerQuery(userID string) int {
+	return o.getOverridesForUser(userID).MaxEstimatedChunksAndSamplesMemoryPerQuery
+}
+
+// QueryPartialResponseEnabled returns whether queries should return partial results, instead of failing,
+// when part of the read path is unavailable.
+func (o *Overrides) QueryPartialResponseEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).QueryPartialResponseEnabled
+}
+
+// TenantFederationAllowedTenantPatterns returns the tenant ID patterns the tenant can use in federated queries.
+func (o *Overrides) TenantFederationAllowedTenantPatterns(userID string) []string {
+	return o.getOverridesForUser(userID).TenantFederationAllowedTenantPatterns
+}
+
+// TenantFederationMaxTenantsPerPattern returns the maximum number of tenants a tenant ID pattern
+// used by the tenant in a federated query can match.
+func (o *Overrides) TenantFederationMaxTenantsPerPattern(userID string) int {
+	return o.getOverridesForUser(userID).TenantFederationMaxTenantsPerPattern
+}
+
+// TenantFederationMaxRemoteClusterResponseSizeBytes returns the limit of the size of a response received
+// from a remote cluster, in bytes.
+func (o *Overrides) TenantFederationMaxRemoteClusterResponseSizeBytes(userID string) int {
+	return o.getOverridesForUser(userID).TenantFederationMaxRemoteClusterResponseSizeBytes
+}
+
 // MaxQueryLookback returns the max lookback period of queries.
 func (o *Overrides) MaxQueryLookback(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxQueryLookback)
@@ -530,6 +593,21 @@ func (o *Overrides) MaxQueryExpressionSizeBytes(userID string) int {
 	return o.getOverridesForUser(userID).MaxQueryExpressionSizeBytes
 }
 
+// MaxQueryCost returns the limit of the estimated cost of a query.
+func (o *Overrides) MaxQueryCost(userID string) int {
+	return o.getOverridesForUser(userID).MaxQueryCost
+}
+
+// QueryCostBudgetPerMinute returns the limit of the total estimated cost of the queries run per minute.
+func (o *Overrides) QueryCostBudgetPerMinute(userID string) int {
+	return o.getOverridesForUser(userID).QueryCostBudgetPerMinute
+}
+
+// MaxRemoteReadResponseSizeBytes returns the limit of the size of a remote read response, in bytes.
+func (o *Overrides) MaxRemoteReadResponseSizeBytes(userID string) int {
+	return o.getOverridesForUser(userID).MaxRemoteReadResponseSizeBytes
+}
+
 // MaxLabelsQueryLength returns the limit of the length (in time) of a label names or values request.
 func (o *Overrides) MaxLabelsQueryLength(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxLabelsQueryLength)
@@ -691,6 +769,22 @@ func (o *Overrides) MetricRelabelConfigs(userID string) []*relabel.Config {
 	return o.getOverridesForUser(userID).MetricRelabelConfigs
 }
 
+// CreatedTimestampZeroIngestionEnabled returns whether the distributor should synthesize a zero sample at the
+// created timestamp of incoming series.
+func (o *Overrides) CreatedTimestampZeroIngestionEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).CreatedTimestampZeroIngestionEnabled
+}
+
+// WriteMirrorEnabled returns whether the accepted write requests of the tenant should be mirrored.
+func (o *Overrides) WriteMirrorEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).WriteMirrorEnabled
+}
+
+// WriteMirrorSampleRatio returns the ratio of the accepted write requests of the tenant to mirror.
+func (o *Overrides) WriteMirrorSampleRatio(userID string) float64 {
+	return o.getOverridesForUser(userID).WriteMirrorSampleRatio
+}
+
 // NativeHistogramsIngestionEnabled returns whether to ingest native histograms in the ingester
 func (o *Overrides) NativeHistogramsIngestionEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).NativeHistogramsIngestionEnabled
@@ -945,6 +1039,23 @@ func LargestPositiveNonZeroDurationPerTenant(tenantIDs []string, f func(string)
 	return *result
 }
 
+// LeastRestrictiveDurationPerTenant is returning the largest value of the supplied
+// limit function for all given tenants, where a value of 0 means unlimited. The method
+// will return 0 if any of the tenants has a limit of 0 or an empty tenant list is given.
+func LeastRestrictiveDurationPerTenant(tenantIDs []string, f func(string) time.Duration) time.Duration {
+	result := time.Duration(0)
+	for _, tenantID := range tenantIDs {
+		v := f(tenantID)
+		if v <= 0 {
+			return 0
+		}
+		if v > result {
+			result = v
+		}
+	}
+	return result
+}
+
 // MinDurationPerTenant is returning the minimum duration per tenant. Without
 // tenants given it will return a time.Duration(0).
 func MinDurationPerTenant(tenantIDs []string, f func(string) time.Duration) time.Duration {
@@ -294,6 +294,37 @@ func TestMinDurationPerTenant(t *testing.T) {
 	}
 }
 
+func TestLeastRestrictiveDurationPerTenant(t *testing.T) {
+	tenantLimits := map[string]*Limits{
+		"tenant-a": {
+			MaxQueryLookback: model.Duration(time.Hour),
+		},
+		"tenant-b": {
+			MaxQueryLookback: model.Duration(4 * time.Hour),
+		},
+	}
+
+	defaults := Limits{
+		MaxQueryLookback: 0,
+	}
+	ov, err := NewOverrides(defaults, NewMockTenantLimits(tenantLimits))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		tenantIDs []string
+		expLimit  time.Duration
+	}{
+		{tenantIDs: []string{}, expLimit: time.Duration(0)},
+		{tenantIDs: []string{"tenant-a"}, expLimit: time.Hour},
+		{tenantIDs: []string{"tenant-b"}, expLimit: 4 * time.Hour},
+		{tenantIDs: []string{"tenant-c"}, expLimit: time.Duration(0)},
+		{tenantIDs: []string{"tenant-a", "tenant-b"}, expLimit: 4 * time.Hour},
+		{tenantIDs: []string{"tenant-a", "tenant-b", "tenant-c"}, expLimit: time.Duration(0)},
+	} {
+		assert.Equal(t, tc.expLimit, LeastRestrictiveDurationPerTenant(tc.tenantIDs, ov.MaxQueryLookback))
+	}
+}
+
 func TestLargestPositiveNonZeroDurationPerTenant(t *testing.T) {
 	tenantLimits := map[string]*Limits{
 		"tenant-a": {
@@ -527,10 +527,22 @@ func getCustomFieldEntry(cfg interface{}, field reflect.StructField, fieldValue
 	}
 	if field.Type == reflect.TypeOf(flagext.Secret{}) {
 		fieldFlag, err := getFieldFlag(field, fieldValue, flags)
-		if err != nil || fieldFlag == nil {
+		if err != nil {
 			return nil, err
 		}
 
+		// Secrets without a CLI flag, like the ones in the elements of a list, are documented as plain strings.
+		if fieldFlag == nil {
+			return &ConfigEntry{
+				Kind:          KindField,
+				Name:          getFieldName(field),
+				Required:      isFieldRequired(field),
+				FieldDesc:     getFieldDescription(cfg, field, ""),
+				FieldType:     "string",
+				FieldCategory: getFieldCategory(field, ""),
+			}, nil
+		}
+
 		return &ConfigEntry{
 			Kind:          KindField,
 			Name:          getFieldName(field),